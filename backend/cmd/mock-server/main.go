@@ -1,57 +1,328 @@
 // Package main provides a mock backend server for development and testing.
 // This is NOT for production use - it uses in-memory storage and mock data.
 // For production, use cmd/server/main_production.go instead.
+//
+// Auth follows the production contract: the same request validation, error
+// messages and response fields, bcrypt-hashed passwords, and separate access
+// and refresh tokens with refresh rotation and reuse detection.
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/gin-gonic/gin/binding"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
 )
 
 var jwtSecret = []byte("dev-secret-key-change-in-production")
 
-// In-memory user store for development
-var users = make(map[string]User)
+// Token lifetimes match auth.DefaultAccessTokenTTL and auth.DefaultRefreshTokenTTL
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 7 * 24 * time.Hour
+)
+
+// Token types, as in the auth package's claims
+const (
+	tokenTypeAccess  = "access"
+	tokenTypeRefresh = "refresh"
+)
+
+// Errors as production reports them
+var (
+	errInvalidCredentials     = errors.New("invalid credentials")
+	errUserNotFound           = errors.New("user not found")
+	errEmailExists            = errors.New("email already registered")
+	errInvalidToken           = errors.New("invalid or expired token")
+	errTokenFamilyCompromised = errors.New("token family compromised - all sessions revoked")
+)
+
+// In-memory user and refresh token store for development
+var store = newMemoryStore()
 
+// User has the JSON shape of models.User
 type User struct {
-	ID       string `json:"id"`
-	Email    string `json:"email"`
-	Name     string `json:"name"`
-	Password string `json:"-"`
+	ID           uuid.UUID  `json:"id"`
+	Email        string     `json:"email"`
+	PasswordHash string     `json:"-"`
+	Name         string     `json:"name"`
+	Settings     string     `json:"settings"`
+	IsActive     bool       `json:"is_active"`
+	LastLoginAt  *time.Time `json:"last_login_at,omitempty"`
+	TOTPEnabled  bool       `json:"totp_enabled"`
+	Role         string     `json:"role"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
 }
 
+// Requests carry the same validation tags as services.RegisterRequest and
+// services.LoginRequest, so they fail with the same messages
 type RegisterRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	Name     string `json:"name"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8"`
+	Name     string `json:"name" binding:"required"`
 }
 
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
 }
 
-type AuthResponse struct {
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TokenResponse has the fields of services.AuthResponse without the user, as
+// the refresh endpoint returns it
+type TokenResponse struct {
 	AccessToken  string    `json:"access_token"`
 	RefreshToken string    `json:"refresh_token"`
 	ExpiresAt    time.Time `json:"expires_at"`
-	User         User      `json:"user"`
+	ExpiresIn    int64     `json:"expires_in"` // Seconds until the access token expires
+}
+
+type AuthResponse struct {
+	User *User `json:"user"`
+	TokenResponse
 }
 
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
+// refreshToken is a stored refresh token, keyed by its hash like auth.RefreshToken
+type refreshToken struct {
+	UserID    uuid.UUID
+	FamilyID  uuid.UUID
+	ExpiresAt time.Time
+	Revoked   bool
+}
+
+type memoryStore struct {
+	mu     sync.Mutex
+	users  map[string]*User // By email
+	tokens map[string]*refreshToken
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		users:  make(map[string]*User),
+		tokens: make(map[string]*refreshToken),
+	}
+}
+
+func (s *memoryStore) createUser(email, password, name string) (*User, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.users[email]; exists {
+		return nil, errEmailExists
+	}
+	now := time.Now()
+	user := &User{
+		ID:           uuid.New(),
+		Email:        email,
+		PasswordHash: string(hashedPassword),
+		Name:         name,
+		Settings:     "{}",
+		IsActive:     true,
+		Role:         "user",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	s.users[email] = user
+	copied := *user
+	return &copied, nil
+}
+
+// authenticate checks a user's password and records the login
+func (s *memoryStore) authenticate(email, password string) (*User, error) {
+	s.mu.Lock()
+	user, exists := s.users[email]
+	s.mu.Unlock()
+	if !exists || !user.IsActive {
+		return nil, errInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, errInvalidCredentials
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	user.LastLoginAt = &now
+	copied := *user
+	return &copied, nil
+}
+
+func (s *memoryStore) userByID(id uuid.UUID) (*User, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.users {
+		if user.ID == id {
+			copied := *user
+			return &copied, true
+		}
+	}
+	return nil, false
+}
+
+// issueTokens creates an access/refresh token pair in the given token family
+func (s *memoryStore) issueTokens(user *User, familyID uuid.UUID) (*TokenResponse, error) {
+	now := time.Now()
+	accessExpiry := now.Add(accessTokenTTL)
+	refreshExpiry := now.Add(refreshTokenTTL)
+	sessionID := uuid.New()
+
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id":    user.ID,
+		"email":      user.Email,
+		"token_type": tokenTypeAccess,
+		"session_id": sessionID,
+		"sub":        user.ID.String(),
+		"exp":        accessExpiry.Unix(),
+		"iat":        now.Unix(),
+		"nbf":        now.Unix(),
+	}).SignedString(jwtSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshTokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id":    user.ID,
+		"token_type": tokenTypeRefresh,
+		"family_id":  familyID,
+		"sub":        user.ID.String(),
+		"exp":        refreshExpiry.Unix(),
+		"iat":        now.Unix(),
+		"nbf":        now.Unix(),
+		"jti":        sessionID.String(),
+	}).SignedString(jwtSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.tokens[hashToken(refreshTokenString)] = &refreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		ExpiresAt: refreshExpiry,
+	}
+	s.mu.Unlock()
+
+	return &TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshTokenString,
+		ExpiresAt:    accessExpiry,
+		ExpiresIn:    int64(accessTokenTTL.Seconds()),
+	}, nil
+}
+
+// rotate exchanges a refresh token for a new pair in the same family. Reusing a
+// token that was already exchanged revokes the whole family, as in production.
+func (s *memoryStore) rotate(tokenString string) (*TokenResponse, error) {
+	claims, err := parseToken(tokenString, tokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	stored, exists := s.tokens[hashToken(tokenString)]
+	if !exists {
+		s.mu.Unlock()
+		return nil, errInvalidToken
+	}
+	if stored.Revoked {
+		for _, token := range s.tokens {
+			if token.FamilyID == stored.FamilyID {
+				token.Revoked = true
+			}
+		}
+		s.mu.Unlock()
+		return nil, errTokenFamilyCompromised
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		s.mu.Unlock()
+		return nil, errInvalidToken
+	}
+	stored.Revoked = true
+	s.mu.Unlock()
+
+	userID, _ := uuid.Parse(claims["user_id"].(string))
+	user, exists := s.userByID(userID)
+	if !exists {
+		return nil, errUserNotFound
+	}
+	if !user.IsActive {
+		return nil, errInvalidCredentials
+	}
+	return s.issueTokens(user, stored.FamilyID)
+}
+
+// parseToken validates a token's signature, expiry and type
+func parseToken(tokenString, tokenType string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return jwtSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return nil, errInvalidToken
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+	if claims["token_type"] != tokenType {
+		return nil, errInvalidToken
+	}
+	if _, ok := claims["user_id"].(string); !ok {
+		return nil, errInvalidToken
+	}
+	return claims, nil
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// Origins allowed to call the mock server, from MOCK_CORS_ORIGINS (comma-separated)
+var allowedOrigins = loadAllowedOrigins()
+
+func loadAllowedOrigins() map[string]bool {
+	raw := os.Getenv("MOCK_CORS_ORIGINS")
+	if raw == "" {
+		raw = "http://localhost:3000"
+	}
+	origins := make(map[string]bool)
+	for _, origin := range strings.Split(raw, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins[origin] = true
+		}
+	}
+	return origins
+}
+
 func enableCORS(w http.ResponseWriter, r *http.Request) bool {
-	w.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
+	if origin := r.Header.Get("Origin"); allowedOrigins[origin] {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+	w.Header().Add("Vary", "Origin")
 	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-	w.Header().Set("Access-Control-Allow-Credentials", "true")
 
 	if r.Method == "OPTIONS" {
 		w.WriteHeader(http.StatusOK)
@@ -60,12 +331,22 @@ func enableCORS(w http.ResponseWriter, r *http.Request) bool {
 	return false
 }
 
-func generateToken(userID string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
-	})
-	return token.SignedString(jwtSecret)
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, ErrorResponse{Error: message})
+}
+
+// decodeRequest decodes and validates a JSON body the way gin's ShouldBindJSON does
+func decodeRequest(r *http.Request, req interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return err
+	}
+	return binding.Validator.ValidateStruct(req)
 }
 
 func registerHandler(w http.ResponseWriter, r *http.Request) {
@@ -74,113 +355,73 @@ func registerHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req RegisterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid request body"})
+	if err := decodeRequest(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Validate
-	if req.Email == "" || req.Password == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(ErrorResponse{Error: "Email and password are required"})
+	// Production answers every registration failure, duplicates included, with a 400
+	user, err := store.createUser(req.Email, req.Password, req.Name)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if len(req.Password) < 6 {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(ErrorResponse{Error: "Password must be at least 6 characters"})
+	tokens, err := store.issueTokens(user, uuid.New())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Check if user exists
-	if _, exists := users[strings.ToLower(req.Email)]; exists {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusConflict)
-		json.NewEncoder(w).Encode(ErrorResponse{Error: "User already exists"})
+	writeJSON(w, http.StatusCreated, AuthResponse{User: user, TokenResponse: *tokens})
+	log.Printf("✅ User registered: %s", req.Email)
+}
+
+func loginHandler(w http.ResponseWriter, r *http.Request) {
+	if enableCORS(w, r) {
 		return
 	}
 
-	// Create user
-	userID := strings.ReplaceAll(time.Now().Format("20060102150405.000"), ".", "")
-	user := User{
-		ID:       userID,
-		Email:    req.Email,
-		Name:     req.Name,
-		Password: req.Password, // In production, hash this!
+	var req LoginRequest
+	if err := decodeRequest(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
 	}
-	users[strings.ToLower(req.Email)] = user
 
-	// Generate token
-	accessToken, err := generateToken(user.ID)
+	user, err := store.authenticate(req.Email, req.Password)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(ErrorResponse{Error: "Failed to generate token"})
+		writeError(w, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	refreshToken, _ := generateToken(user.ID)
-	expiresAt := time.Now().Add(24 * time.Hour)
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresAt:    expiresAt,
-		User:         user,
-	})
+	tokens, err := store.issueTokens(user, uuid.New())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
 
-	log.Printf("✅ User registered: %s", req.Email)
+	writeJSON(w, http.StatusOK, AuthResponse{User: user, TokenResponse: *tokens})
+	log.Printf("✅ User logged in: %s", req.Email)
 }
 
-func loginHandler(w http.ResponseWriter, r *http.Request) {
+func refreshHandler(w http.ResponseWriter, r *http.Request) {
 	if enableCORS(w, r) {
 		return
 	}
 
-	var req LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid request body"})
-		return
-	}
-
-	// Find user
-	user, exists := users[strings.ToLower(req.Email)]
-	if !exists || user.Password != req.Password {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid email or password"})
+	var req RefreshRequest
+	if err := decodeRequest(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Generate token
-	accessToken, err := generateToken(user.ID)
+	tokens, err := store.rotate(req.RefreshToken)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(ErrorResponse{Error: "Failed to generate token"})
+		writeError(w, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	refreshToken, _ := generateToken(user.ID)
-	expiresAt := time.Now().Add(24 * time.Hour)
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresAt:    expiresAt,
-		User:         user,
-	})
-
-	log.Printf("✅ User logged in: %s", req.Email)
+	writeJSON(w, http.StatusOK, tokens)
 }
 
 func meHandler(w http.ResponseWriter, r *http.Request) {
@@ -191,66 +432,48 @@ func meHandler(w http.ResponseWriter, r *http.Request) {
 	// Get token from header
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(ErrorResponse{Error: "No token provided"})
+		writeError(w, http.StatusUnauthorized, "No token provided")
 		return
 	}
 
-	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-
-	// Parse token
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
-
-	if err != nil || !token.Valid {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid token"})
+	// Refresh tokens are not accepted in place of access tokens
+	claims, err := parseToken(strings.TrimPrefix(authHeader, "Bearer "), tokenTypeAccess)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	claims := token.Claims.(jwt.MapClaims)
-	userID := claims["user_id"].(string)
-
-	// Find user by ID
-	for _, user := range users {
-		if user.ID == userID {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(user)
-			return
-		}
+	userID, _ := uuid.Parse(claims["user_id"].(string))
+	user, exists := store.userByID(userID)
+	if !exists {
+		writeError(w, http.StatusNotFound, errUserNotFound.Error())
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotFound)
-	json.NewEncoder(w).Encode(ErrorResponse{Error: "User not found"})
+	writeJSON(w, http.StatusOK, user)
 }
 
 func healthHandler(w http.ResponseWriter, r *http.Request) {
 	if enableCORS(w, r) {
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
 
 func main() {
 	// Add a test user
-	users["test@example.com"] = User{
-		ID:       "1",
-		Email:    "test@example.com",
-		Name:     "Test User",
-		Password: "password123",
+	if _, err := store.createUser("test@example.com", "password123", "Test User"); err != nil {
+		log.Fatal(err)
 	}
 
 	// Routes
 	http.HandleFunc("/api/auth/register", registerHandler)
 	http.HandleFunc("/api/auth/login", loginHandler)
+	http.HandleFunc("/api/auth/refresh", refreshHandler)
 	http.HandleFunc("/api/auth/me", meHandler)
 	http.HandleFunc("/api/v1/auth/register", registerHandler)
 	http.HandleFunc("/api/v1/auth/login", loginHandler)
+	http.HandleFunc("/api/v1/auth/refresh", refreshHandler)
 	http.HandleFunc("/api/v1/auth/me", meHandler)
 	http.HandleFunc("/health", healthHandler)
 
@@ -265,6 +488,7 @@ func main() {
 	log.Println("Available endpoints:")
 	log.Println("  POST /api/auth/register - Register new user")
 	log.Println("  POST /api/auth/login    - Login")
+	log.Println("  POST /api/auth/refresh  - Exchange a refresh token for a new pair")
 	log.Println("  GET  /api/auth/me       - Get current user")
 
 	if err := http.ListenAndServe(":8080", nil); err != nil {
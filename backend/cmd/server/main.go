@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/web3airdropos/backend/internal/jobs"
 	"github.com/web3airdropos/backend/internal/logger"
 	"github.com/web3airdropos/backend/internal/migrations"
+	"github.com/web3airdropos/backend/internal/tracing"
 	"github.com/web3airdropos/backend/internal/websocket"
 )
 
@@ -34,11 +36,11 @@ func main() {
 		env = "development"
 	}
 
+	// Load configuration
+	cfg := config.Load()
+
 	// Initialize structured logger
-	logger.Init(logger.Config{
-		Level:  getEnvOrDefault("LOG_LEVEL", "info"),
-		Pretty: env == "development",
-	})
+	logger.Init(loggerConfig(cfg))
 
 	log := logger.Get()
 	log.Info().
@@ -46,17 +48,36 @@ func main() {
 		Str("version", "1.0.0").
 		Msg("Starting Web3AirdropOS Backend")
 
-	// Load configuration
-	cfg := config.Load()
 	validateConfig(cfg, log)
 
+	// Tracing (export is off unless an OTLP endpoint is configured)
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Endpoint:    cfg.OTLPEndpoint,
+		ServiceName: cfg.TracingService,
+		SampleRatio: cfg.TracingSampleRate,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to set up tracing")
+	}
+
 	// Connect to PostgreSQL
 	db, err := database.Connect(cfg.DatabaseURL)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to database")
 	}
+	if err := tracing.InstrumentGORM(db); err != nil {
+		log.Fatal().Err(err).Msg("Failed to instrument database")
+	}
 	log.Info().Msg("Connected to PostgreSQL")
 
+	// Heavy reads go to the replica when one is configured
+	replica := database.ConnectReplica(cfg.DatabaseReplicaURL)
+	if replica != nil {
+		if err := tracing.InstrumentGORM(replica); err != nil {
+			log.Fatal().Err(err).Msg("Failed to instrument read replica")
+		}
+	}
+
 	// Run migrations on startup (dev mode) or check (production)
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -87,6 +108,7 @@ func main() {
 
 	// Initialize health checker
 	healthChecker := health.NewChecker(db, redisClient)
+	healthChecker.SetDependencies(healthDependencies(cfg, log))
 
 	// Initialize WebSocket hub
 	wsHub := websocket.NewHub()
@@ -100,6 +122,9 @@ func main() {
 
 	// Initialize API server
 	server := api.NewServer(cfg, db, redisClient, wsHub)
+	server.Services().SetReadReplica(replica)
+	scheduler.SetScheduledTaskRunner(server.Services().Task.RunScheduled)
+	scheduler.SetReceiptPoller(server.Services().Task.PollTransactionReceipts)
 
 	// Register health endpoints
 	healthChecker.RegisterRoutes(server.Router())
@@ -147,6 +172,14 @@ func main() {
 		log.Error().Err(err).Msg("Server shutdown error")
 	}
 
+	// Drain the scheduler while the DB and Redis are still reachable, so
+	// unfinished jobs can be released
+	scheduler.Stop()
+	log.Info().Msg("Job scheduler stopped")
+
+	// Close pooled RPC connections
+	server.Close()
+
 	// Close database
 	if err := sqlDB.Close(); err != nil {
 		log.Error().Err(err).Msg("Database close error")
@@ -159,6 +192,11 @@ func main() {
 		}
 	}
 
+	// Flush buffered spans
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error().Err(err).Msg("Tracing shutdown error")
+	}
+
 	log.Info().Msg("Shutdown complete")
 }
 
@@ -185,6 +223,14 @@ func validateConfig(cfg *config.Config, log *zerolog.Logger) {
 		}
 	}
 
+	if cfg.StorageBackend == "s3" && cfg.S3Bucket == "" {
+		errors = append(errors, "S3_BUCKET is required when STORAGE_BACKEND=s3")
+	}
+
+	if cfg.AccessTokenTTL >= cfg.RefreshTokenTTL {
+		errors = append(errors, "ACCESS_TOKEN_TTL must be shorter than REFRESH_TOKEN_TTL")
+	}
+
 	if len(errors) > 0 {
 		for _, e := range errors {
 			log.Error().Msg(e)
@@ -193,9 +239,45 @@ func validateConfig(cfg *config.Config, log *zerolog.Logger) {
 	}
 }
 
+// loggerConfig maps the logging settings onto the logger's config
+func loggerConfig(cfg *config.Config) logger.Config {
+	lc := logger.Config{
+		Level:      cfg.LogLevel,
+		Pretty:     cfg.LogFormat == "console",
+		TimeFormat: cfg.LogTimeFormat,
+		Caller:     cfg.LogCaller,
+	}
+	if cfg.LogSampleBurst > 0 {
+		lc.Sampling = &logger.Sampling{
+			Burst:      uint32(cfg.LogSampleBurst),
+			Period:     cfg.LogSamplePeriod,
+			Thereafter: uint32(max(cfg.LogSampleThereafter, 0)),
+		}
+	}
+	return lc
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+// healthDependencies selects the dependency probes enabled by HEALTH_CHECKS
+func healthDependencies(cfg *config.Config, log *zerolog.Logger) health.Dependencies {
+	var deps health.Dependencies
+	for _, check := range cfg.HealthChecks {
+		switch strings.ToLower(check) {
+		case "ai":
+			deps.AIServiceURL = cfg.AIServiceURL
+		case "docker":
+			deps.DockerHost = cfg.DockerHost
+		case "rpc":
+			deps.RPCURL = cfg.EthereumRPCURL
+		default:
+			log.Warn().Str("check", check).Msg("Unknown health check")
+		}
+	}
+	return deps
+}
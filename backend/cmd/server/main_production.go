@@ -6,6 +6,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
@@ -14,16 +15,21 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
+	"gorm.io/gorm"
 
 	"github.com/web3airdropos/backend/internal/api"
 	"github.com/web3airdropos/backend/internal/audit"
 	"github.com/web3airdropos/backend/internal/auth"
+	"github.com/web3airdropos/backend/internal/circuit"
 	"github.com/web3airdropos/backend/internal/config"
 	"github.com/web3airdropos/backend/internal/database"
 	"github.com/web3airdropos/backend/internal/jobs"
 	"github.com/web3airdropos/backend/internal/locks"
+	"github.com/web3airdropos/backend/internal/logger"
+	"github.com/web3airdropos/backend/internal/models"
 	"github.com/web3airdropos/backend/internal/queue"
 	"github.com/web3airdropos/backend/internal/tasks"
+	"github.com/web3airdropos/backend/internal/tracing"
 	"github.com/web3airdropos/backend/internal/vault"
 	"github.com/web3airdropos/backend/internal/websocket"
 )
@@ -40,13 +46,40 @@ func main() {
 	cfg := config.Load()
 	validateConfig(cfg)
 
+	// Structured logger, used by the scheduler's job logs
+	logger.Init(loggerConfig(cfg))
+
+	// Initialize tracing (export is off unless an OTLP endpoint is configured)
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Endpoint:    cfg.OTLPEndpoint,
+		ServiceName: cfg.TracingService,
+		SampleRatio: cfg.TracingSampleRate,
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to set up tracing: %v", err)
+	}
+	if cfg.OTLPEndpoint != "" {
+		log.Printf("✅ Tracing to %s", cfg.OTLPEndpoint)
+	}
+
 	// Initialize database
 	db, err := database.Connect(cfg.DatabaseURL)
 	if err != nil {
 		log.Fatalf("❌ Failed to connect to database: %v", err)
 	}
+	if err := tracing.InstrumentGORM(db); err != nil {
+		log.Fatalf("❌ Failed to instrument database: %v", err)
+	}
 	log.Println("✅ Database connected")
 
+	// Heavy reads go to the replica when one is configured
+	replica := database.ConnectReplica(cfg.DatabaseReplicaURL)
+	if replica != nil {
+		if err := tracing.InstrumentGORM(replica); err != nil {
+			log.Fatalf("❌ Failed to instrument read replica: %v", err)
+		}
+	}
+
 	// Run migrations
 	if err := database.Migrate(db); err != nil {
 		log.Fatalf("❌ Failed to run migrations: %v", err)
@@ -63,16 +96,31 @@ func main() {
 
 	// 1. Audit Logger
 	auditLogger := audit.NewLogger(db)
+	auditLogger.AddRedactedKeys(cfg.AuditRedactKeys...)
+	if replica != nil {
+		auditLogger.SetReader(replica)
+	}
 	log.Println("✅ Audit logger initialized")
 
 	// 2. Secrets Vault
 	secretsVault, err := vault.NewVault(db, vault.Config{
-		MasterKey: cfg.EncryptionKey,
+		MasterKey:          cfg.VaultMasterKey,
+		KeyProvider:        cfg.VaultKeyProvider,
+		KMSKeyID:           cfg.VaultKMSKeyID,
+		AWSRegion:          cfg.AWSRegion,
+		AWSAccessKeyID:     cfg.AWSAccessKeyID,
+		AWSSecretAccessKey: cfg.AWSSecretAccessKey,
+		AWSSessionToken:    cfg.AWSSessionToken,
+		GCPAccessToken:     cfg.GCPAccessToken,
+		VaultAddress:       cfg.VaultAddress,
+		VaultToken:         cfg.VaultToken,
+		VaultTransitMount:  cfg.VaultTransitMount,
 	})
 	if err != nil {
 		log.Fatalf("❌ Failed to initialize vault: %v", err)
 	}
-	log.Println("✅ Secrets vault initialized")
+	secretsVault.SetAuditLogger(auditLogger)
+	log.Printf("✅ Secrets vault initialized (key provider: %s)", secretsVault.KeyProviderName())
 
 	// 3. Distributed Lock Manager
 	lockManager := locks.NewLockManager(redisClient)
@@ -83,15 +131,48 @@ func main() {
 	log.Println("✅ Rate limiter initialized")
 
 	// 5. Auth Service
-	authService := auth.NewAuthService(db, cfg.JWTSecret)
+	authService := auth.NewAuthService(db, cfg.JWTSecret, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	authService.SetVault(secretsVault)
+	authService.SetAuditLogger(auditLogger)
+	authService.SetPasswordResetNotifier(func(ctx context.Context, user *models.User, token string, expiresAt time.Time) {
+		// No mail transport is configured yet; never log the token itself
+		log.Printf("🔑 Password reset requested for user %s (expires %s)", user.ID, expiresAt.Format(time.RFC3339))
+	})
+	authService.SetAccountLockout(redisClient, auth.LockoutConfig{
+		MaxAttempts:  cfg.LoginLockoutThreshold,
+		Window:       cfg.LoginLockoutWindow,
+		BaseDuration: cfg.LoginLockoutDuration,
+		MaxDuration:  cfg.LoginLockoutMaxDuration,
+	})
 	log.Println("✅ Auth service initialized")
 
+	// Users named in ADMIN_EMAILS hold the admin role from now on
+	if promoted, err := authService.BootstrapAdmins(context.Background(), cfg.AdminEmails); err != nil {
+		log.Printf("⚠️ Failed to bootstrap admins: %v", err)
+	} else if promoted > 0 {
+		log.Printf("👑 Gave the admin role to %d users from ADMIN_EMAILS", promoted)
+	}
+
 	// 6. Task Queue
 	taskQueue := queue.NewQueue(redisClient, "tasks")
 	log.Println("✅ Task queue initialized")
 
 	// 7. Task Manager
 	taskManager := tasks.NewTaskManager(db, lockManager, taskQueue)
+	taskManager.SetRetryConfig(tasks.RetryConfig{
+		MaxRetries:  cfg.TaskMaxRetries,
+		BaseBackoff: cfg.TaskRetryBaseBackoff,
+		MaxBackoff:  cfg.TaskRetryMaxBackoff,
+		Jitter:      tasks.DefaultRetryConfig().Jitter,
+	})
+
+	// Shared by the task manager and the API so both see the same circuits
+	circuits := circuit.New(circuit.Config{
+		Threshold: cfg.CircuitThreshold,
+		Window:    cfg.CircuitWindow,
+		Cooldown:  cfg.CircuitCooldown,
+	})
+	taskManager.SetCircuitBreaker(circuits)
 	log.Println("✅ Task manager initialized")
 
 	// 8. WebSocket hub
@@ -99,14 +180,40 @@ func main() {
 	go wsHub.Run()
 	log.Println("✅ WebSocket hub started")
 
+	// Let users know when their account is locked by failed logins
+	authService.SetLockoutNotifier(func(ctx context.Context, userID uuid.UUID, lockedUntil time.Time) {
+		wsHub.BroadcastToUser(userID.String(), "account_locked", map[string]interface{}{
+			"locked_until": lockedUntil,
+			"reason":       "too many failed login attempts",
+		})
+	})
+
+	// Surface task executions that ran out of retries
+	taskManager.SetDeadLetterNotifier(func(ctx context.Context, entry *models.DeadLetterJob) {
+		wsHub.BroadcastTerminal(entry.UserID.String(), websocket.TerminalMessage{
+			Level:   "error",
+			Source:  "task",
+			Message: fmt.Sprintf("%s moved to the dead-letter queue after %d attempts: %s", entry.Name, entry.Attempts, entry.LastError),
+			Details: map[string]interface{}{
+				"dead_letter_id":    entry.ID,
+				"task_execution_id": entry.TaskExecutionID,
+			},
+		})
+	})
+
 	// 9. Job Scheduler
 	scheduler := jobs.NewScheduler(db, redisClient, wsHub, cfg)
+	scheduler.AddCleanup("expired tokens", authService.CleanupExpiredTokens)
+	scheduler.AddCleanup("expired secrets", secretsVault.CleanupExpired)
+	scheduler.AddCleanup("old audit logs", func(ctx context.Context) (int64, error) {
+		return auditLogger.Cleanup(ctx, cfg.AuditLogRetentionDays)
+	})
 	go scheduler.Start()
 	log.Println("✅ Job scheduler started")
 
 	// 10. Queue Worker
 	worker := queue.NewWorker(taskQueue, "main-worker", queue.DefaultWorkerConfig())
-	registerQueueHandlers(worker, taskManager, auditLogger)
+	registerQueueHandlers(worker, db, taskManager, auditLogger, cfg)
 	go worker.Start(context.Background())
 	log.Println("✅ Queue worker started")
 
@@ -114,6 +221,7 @@ func main() {
 	prodContainer := &api.ProductionContainer{
 		Config:      cfg,
 		DB:          db,
+		ReadDB:      replica,
 		Redis:       redisClient,
 		WSHub:       wsHub,
 		AuditLogger: auditLogger,
@@ -123,6 +231,8 @@ func main() {
 		AuthService: authService,
 		TaskQueue:   taskQueue,
 		TaskManager: taskManager,
+		Scheduler:   scheduler,
+		Circuits:    circuits,
 	}
 
 	// Initialize and start API server
@@ -155,17 +265,25 @@ func main() {
 	// Stop worker
 	worker.Stop()
 
+	// Drain the scheduler before the audit logger, so jobs finishing now are still audited
+	scheduler.Stop()
+
+	// Close pooled RPC connections
+	server.Close()
+
 	// Stop audit logger
 	auditLogger.Stop()
 
-	// Stop scheduler (if it has a Stop method)
-	// scheduler.Stop()
-
 	// Cleanup expired tokens
 	if deleted, err := authService.CleanupExpiredTokens(ctx); err == nil {
 		log.Printf("🧹 Cleaned up %d expired tokens", deleted)
 	}
 
+	// Flush buffered spans
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("⚠️ Tracing shutdown error: %v", err)
+	}
+
 	log.Println("✅ Shutdown complete")
 }
 
@@ -181,9 +299,13 @@ func validateConfig(cfg *config.Config) {
 	if cfg.DatabaseURL == "" {
 		log.Fatal("❌ DATABASE_URL is required")
 	}
+
+	if cfg.AccessTokenTTL >= cfg.RefreshTokenTTL {
+		log.Fatalf("❌ ACCESS_TOKEN_TTL (%s) must be shorter than REFRESH_TOKEN_TTL (%s)", cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	}
 }
 
-func registerQueueHandlers(worker *queue.Worker, taskManager *tasks.TaskManager, auditLogger *audit.Logger) {
+func registerQueueHandlers(worker *queue.Worker, db *gorm.DB, taskManager *tasks.TaskManager, auditLogger *audit.Logger, cfg *config.Config) {
 	// Task retry handler
 	worker.RegisterHandler("task_retry", func(ctx context.Context, job *queue.Job) error {
 		var payload struct {
@@ -204,9 +326,34 @@ func registerQueueHandlers(worker *queue.Worker, taskManager *tasks.TaskManager,
 		return err
 	})
 
+	// Dead-letter replay handler
+	worker.RegisterHandler("task_replay", func(ctx context.Context, job *queue.Job) error {
+		var payload struct {
+			DeadLetterID string `json:"dead_letter_id"`
+		}
+
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return err
+		}
+
+		var entry models.DeadLetterJob
+		if err := db.WithContext(ctx).First(&entry, "id = ?", payload.DeadLetterID).Error; err != nil {
+			return err
+		}
+
+		req, err := tasks.ReplayRequest(&entry)
+		if err != nil {
+			return err
+		}
+
+		// Execution failures are retried and dead-lettered by the task manager itself
+		_, err = taskManager.Execute(ctx, req)
+		return err
+	})
+
 	// Audit log cleanup handler
 	worker.RegisterHandler("audit_cleanup", func(ctx context.Context, job *queue.Job) error {
-		deleted, err := auditLogger.Cleanup(ctx, 90) // 90 days retention
+		deleted, err := auditLogger.Cleanup(ctx, cfg.AuditLogRetentionDays)
 		if err != nil {
 			return err
 		}
@@ -214,3 +361,21 @@ func registerQueueHandlers(worker *queue.Worker, taskManager *tasks.TaskManager,
 		return nil
 	})
 }
+
+// loggerConfig maps the logging settings onto the logger's config
+func loggerConfig(cfg *config.Config) logger.Config {
+	lc := logger.Config{
+		Level:      cfg.LogLevel,
+		Pretty:     cfg.LogFormat == "console",
+		TimeFormat: cfg.LogTimeFormat,
+		Caller:     cfg.LogCaller,
+	}
+	if cfg.LogSampleBurst > 0 {
+		lc.Sampling = &logger.Sampling{
+			Burst:      uint32(cfg.LogSampleBurst),
+			Period:     cfg.LogSamplePeriod,
+			Thereafter: uint32(max(cfg.LogSampleThereafter, 0)),
+		}
+	}
+	return lc
+}
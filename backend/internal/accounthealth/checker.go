@@ -0,0 +1,273 @@
+// Package accounthealth probes platform accounts to find ones that have been
+// suspended or are otherwise unusable, so automation stops spending actions on them.
+package accounthealth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/config"
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/tracing"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+// ErrUnsupported is returned for platforms that have no health probe
+var ErrUnsupported = errors.New("health checks are not supported for this platform")
+
+// Platforms lists the platforms that can be probed
+var Platforms = []models.PlatformType{models.PlatformFarcaster, models.PlatformTwitter, models.PlatformTelegram}
+
+// Result is the outcome of one health check
+type Result struct {
+	AccountID   uuid.UUID `json:"account_id"`
+	Platform    string    `json:"platform"`
+	Health      string    `json:"health"`
+	Previous    string    `json:"previous"`
+	Reason      string    `json:"reason,omitempty"`
+	IsActive    bool      `json:"is_active"`
+	CheckedAt   time.Time `json:"checked_at"`
+	Deactivated bool      `json:"deactivated"` // The check turned the account off
+}
+
+// Checker probes accounts and records their health
+type Checker struct {
+	db     *gorm.DB
+	wsHub  *websocket.Hub
+	config *config.Config
+	http   *http.Client
+}
+
+func NewChecker(db *gorm.DB, wsHub *websocket.Hub, cfg *config.Config) *Checker {
+	return &Checker{
+		db:     db,
+		wsHub:  wsHub,
+		config: cfg,
+		http:   &http.Client{Timeout: 10 * time.Second, Transport: tracing.Transport(nil)},
+	}
+}
+
+// Check probes an account and records its health. A suspended account is
+// deactivated so automation skips it, and one deactivated that way is turned back
+// on once it checks healthy again. The owner's terminal is warned whenever the
+// account's health changes for the worse.
+func (c *Checker) Check(ctx context.Context, accountID uuid.UUID) (*Result, error) {
+	var account models.PlatformAccount
+	if err := c.db.WithContext(ctx).First(&account, "id = ?", accountID).Error; err != nil {
+		return nil, err
+	}
+
+	health, reason, err := c.probe(ctx, &account)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := account.Health
+	if previous == "" {
+		previous = models.AccountHealthActive
+	}
+	result := &Result{
+		AccountID: account.ID,
+		Platform:  string(account.Platform),
+		Health:    health,
+		Previous:  previous,
+		Reason:    reason,
+		IsActive:  account.IsActive,
+		CheckedAt: time.Now(),
+	}
+
+	updates := map[string]interface{}{
+		"health":            health,
+		"health_reason":     reason,
+		"health_checked_at": result.CheckedAt,
+	}
+	switch {
+	case health == models.AccountHealthSuspended && account.IsActive:
+		updates["is_active"] = false
+		result.IsActive = false
+		result.Deactivated = true
+	case health == models.AccountHealthActive && previous == models.AccountHealthSuspended && !account.IsActive:
+		updates["is_active"] = true
+		result.IsActive = true
+	}
+	if err := c.db.WithContext(ctx).Model(&account).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	if health != previous && health != models.AccountHealthActive {
+		message := fmt.Sprintf("⚠️ %s account %s is %s: %s", account.Platform, account.Username, strings.ReplaceAll(health, "_", " "), reason)
+		if result.Deactivated {
+			message += " (deactivated)"
+		}
+		c.wsHub.BroadcastTerminal(account.UserID.String(), websocket.TerminalMessage{
+			Level:     "warn",
+			Source:    "account",
+			Message:   message,
+			AccountID: account.ID.String(),
+			Details:   result,
+		})
+	}
+
+	return result, nil
+}
+
+// probe asks the account's platform about it. Errors are for checks that could not
+// run, e.g. missing credentials; a platform that answers with a failure is reported
+// as the error health instead.
+func (c *Checker) probe(ctx context.Context, account *models.PlatformAccount) (string, string, error) {
+	switch account.Platform {
+	case models.PlatformFarcaster:
+		return c.probeFarcaster(ctx, account)
+	case models.PlatformTwitter:
+		return c.probeTwitter(ctx, account)
+	case models.PlatformTelegram:
+		return c.probeTelegram(ctx, account)
+	default:
+		return "", "", ErrUnsupported
+	}
+}
+
+func (c *Checker) probeFarcaster(ctx context.Context, account *models.PlatformAccount) (string, string, error) {
+	if c.config.NeynarAPIKey == "" {
+		return "", "", fmt.Errorf("NEYNAR_API_KEY not configured")
+	}
+
+	url := fmt.Sprintf("https://api.neynar.com/v2/farcaster/user?fid=%s", account.PlatformUserID)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("api_key", c.config.NeynarAPIKey)
+
+	var result struct {
+		Users []struct {
+			Fid          int    `json:"fid"`
+			ActiveStatus string `json:"active_status"`
+		} `json:"users"`
+	}
+	status, err := c.do(req, &result)
+	if health, reason, done := statusHealth("neynar", status, err); done {
+		return health, reason, nil
+	}
+
+	if len(result.Users) == 0 {
+		return models.AccountHealthSuspended, "account not found", nil
+	}
+	if result.Users[0].ActiveStatus == "inactive" {
+		return models.AccountHealthSuspended, "account is inactive", nil
+	}
+	return models.AccountHealthActive, "", nil
+}
+
+func (c *Checker) probeTwitter(ctx context.Context, account *models.PlatformAccount) (string, string, error) {
+	if c.config.TwitterBearerToken == "" {
+		return "", "", fmt.Errorf("TWITTER_BEARER_TOKEN not configured")
+	}
+
+	url := fmt.Sprintf("https://api.twitter.com/2/users/%s", account.PlatformUserID)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.config.TwitterBearerToken)
+
+	// Suspended and deleted users come back as 200 with an error instead of data
+	var result struct {
+		Data *struct {
+			ID string `json:"id"`
+		} `json:"data"`
+		Errors []struct {
+			Title  string `json:"title"`
+			Detail string `json:"detail"`
+		} `json:"errors"`
+	}
+	status, err := c.do(req, &result)
+	if status == http.StatusForbidden {
+		return models.AccountHealthSuspended, "twitter API returned forbidden", nil
+	}
+	if health, reason, done := statusHealth("twitter", status, err); done {
+		return health, reason, nil
+	}
+
+	if result.Data == nil {
+		reason := "account not found"
+		if len(result.Errors) > 0 {
+			reason = result.Errors[0].Detail
+		}
+		return models.AccountHealthSuspended, reason, nil
+	}
+	return models.AccountHealthActive, "", nil
+}
+
+func (c *Checker) probeTelegram(ctx context.Context, account *models.PlatformAccount) (string, string, error) {
+	// Bot accounts carry their own token; otherwise the shared bot is checked
+	token := account.AccessToken
+	if token == "" {
+		token = c.config.TelegramBotToken
+	}
+	if token == "" {
+		return "", "", fmt.Errorf("TELEGRAM_BOT_TOKEN not configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.telegram.org/bot"+token+"/getMe", nil)
+	if err != nil {
+		return "", "", err
+	}
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	status, err := c.do(req, &result)
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return models.AccountHealthSuspended, "telegram rejected the bot token", nil
+	}
+	if health, reason, done := statusHealth("telegram", status, err); done {
+		return health, reason, nil
+	}
+
+	if !result.OK {
+		return models.AccountHealthError, result.Description, nil
+	}
+	return models.AccountHealthActive, "", nil
+}
+
+// do sends req and decodes a 200 response into out, returning the status code
+func (c *Checker) do(req *http.Request, out interface{}) (int, error) {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// statusHealth maps a failed request to a health, reporting done unless the
+// response was a 200 that still needs inspecting. A 404 means the account is gone.
+func statusHealth(api string, status int, err error) (string, string, bool) {
+	switch {
+	case err != nil:
+		return models.AccountHealthError, fmt.Sprintf("%s API error: %v", api, err), true
+	case status == http.StatusTooManyRequests:
+		return models.AccountHealthRateLimited, fmt.Sprintf("%s rate limit exceeded", api), true
+	case status == http.StatusNotFound:
+		return models.AccountHealthSuspended, "account not found", true
+	case status != http.StatusOK:
+		return models.AccountHealthError, fmt.Sprintf("%s API returned status %d", api, status), true
+	}
+	return "", "", false
+}
@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
+	"github.com/web3airdropos/backend/internal/accounthealth"
 	"github.com/web3airdropos/backend/internal/services"
+	"github.com/web3airdropos/backend/internal/services/platforms"
 )
 
 type AccountHandler struct {
@@ -42,6 +46,10 @@ func (h *AccountHandler) Create(c *gin.Context) {
 
 	account, err := h.services.Account.Create(userID, &req)
 	if err != nil {
+		if errors.Is(err, services.ErrProxyPoolNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -82,6 +90,10 @@ func (h *AccountHandler) Update(c *gin.Context) {
 
 	account, err := h.services.Account.Update(userID, accountID, &req)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidRateLimit) || errors.Is(err, services.ErrProxyPoolNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -102,7 +114,44 @@ func (h *AccountHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "account deleted"})
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "account deleted",
+		"restorable_for": h.services.Config.SoftDeleteRetention.String(),
+	})
+}
+
+// ListDeleted lists deleted accounts that can still be restored
+func (h *AccountHandler) ListDeleted(c *gin.Context) {
+	userID := getUserID(c)
+
+	accounts, err := h.services.Account.ListDeleted(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"accounts": accounts})
+}
+
+func (h *AccountHandler) Restore(c *gin.Context) {
+	userID := getUserID(c)
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account ID"})
+		return
+	}
+
+	account, err := h.services.Account.Restore(userID, accountID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "deleted account not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
 }
 
 func (h *AccountHandler) GetActivities(c *gin.Context) {
@@ -169,3 +218,195 @@ func (h *AccountHandler) Sync(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "sync started"})
 }
+
+// BulkImport creates accounts from a CSV upload (text/csv body or a multipart "file")
+// or a JSON {"accounts": [...]} list, returning a result per row
+func (h *AccountHandler) BulkImport(c *gin.Context) {
+	userID := getUserID(c)
+
+	var rows []services.BulkAccountRow
+	switch c.ContentType() {
+	case "text/csv":
+		parsed, err := services.ParseAccountCSV(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		rows = parsed
+	case "multipart/form-data":
+		header, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer file.Close()
+		parsed, err := services.ParseAccountCSV(file)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		rows = parsed
+	default:
+		var req services.BulkImportAccountsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		rows = req.Accounts
+	}
+
+	if len(rows) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no accounts to import"})
+		return
+	}
+
+	results, err := h.services.Account.BulkImport(userID, rows)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrTooManyAccounts) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	created := 0
+	for _, result := range results {
+		if result.Status == services.BulkImportCreated {
+			created++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": results,
+		"created": created,
+		"failed":  len(results) - created,
+	})
+}
+
+// HealthCheck probes an account's platform and returns its health
+func (h *AccountHandler) HealthCheck(c *gin.Context) {
+	userID := getUserID(c)
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account ID"})
+		return
+	}
+
+	result, err := h.services.Account.HealthCheck(userID, accountID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, accounthealth.ErrUnsupported):
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *AccountHandler) RegisterFarcasterSigner(c *gin.Context) {
+	userID := getUserID(c)
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account ID"})
+		return
+	}
+
+	signer, err := h.services.Account.RegisterFarcasterSigner(userID, accountID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrNotFarcasterAccount):
+			status = http.StatusBadRequest
+		case errors.Is(err, platforms.ErrAppSignerNotConfigured):
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, signer)
+}
+
+// GetWarmup returns an account's warmup config and progress
+func (h *AccountHandler) GetWarmup(c *gin.Context) {
+	userID := getUserID(c)
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account ID"})
+		return
+	}
+
+	warmup, err := h.services.Account.GetWarmup(userID, accountID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, warmup)
+}
+
+// StartWarmup configures an account's warmup and starts or resumes it
+func (h *AccountHandler) StartWarmup(c *gin.Context) {
+	userID := getUserID(c)
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account ID"})
+		return
+	}
+
+	var req services.StartWarmupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	warmup, err := h.services.Account.StartWarmup(userID, accountID, &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrInvalidWarmup), errors.Is(err, services.ErrWarmupNotFarcaster):
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, warmup)
+}
+
+// StopWarmup pauses an account's warmup
+func (h *AccountHandler) StopWarmup(c *gin.Context) {
+	userID := getUserID(c)
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account ID"})
+		return
+	}
+
+	warmup, err := h.services.Account.StopWarmup(userID, accountID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, warmup)
+}
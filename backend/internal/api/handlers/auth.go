@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/web3airdropos/backend/internal/auth"
 	"github.com/web3airdropos/backend/internal/services"
 )
 
@@ -41,6 +43,10 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	resp, err := h.services.Auth.Login(&req)
 	if err != nil {
+		if errors.Is(err, auth.ErrAccountLocked) {
+			c.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
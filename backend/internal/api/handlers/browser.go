@@ -2,12 +2,16 @@ package handlers
 
 import (
 	"encoding/base64"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/web3airdropos/backend/internal/services"
+	"github.com/web3airdropos/backend/internal/websocket"
 )
 
 type BrowserHandler struct {
@@ -195,3 +199,82 @@ func (h *BrowserHandler) GetScreenshot(c *gin.Context) {
 		c.Data(http.StatusOK, "image/png", screenshot)
 	}
 }
+
+// GetRecording returns the screenshot timeline of a recorded session
+func (h *BrowserHandler) GetRecording(c *gin.Context) {
+	userID := getUserID(c)
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return
+	}
+
+	recording, err := h.services.Browser.GetRecording(userID, sessionID)
+	if err != nil {
+		c.JSON(recordingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, recording)
+}
+
+// GetRecordingFrame returns one frame of a session recording as a JPEG
+func (h *BrowserHandler) GetRecordingFrame(c *gin.Context) {
+	userID := getUserID(c)
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return
+	}
+	index, err := strconv.Atoi(c.Param("frame"))
+	if err != nil || index < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid frame index"})
+		return
+	}
+
+	frame, err := h.services.Browser.GetRecordingFrame(userID, sessionID, index)
+	if err != nil {
+		c.JSON(recordingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, "image/jpeg", frame)
+}
+
+func recordingErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, services.ErrNoRecording), errors.Is(err, services.ErrFrameNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// VNC relays the session's noVNC stream over an authenticated WebSocket
+func (h *BrowserHandler) VNC(c *gin.Context) {
+	userID := getUserID(c)
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return
+	}
+
+	// Ownership and liveness are checked before the upgrade so errors are plain HTTP
+	target, err := h.services.Browser.VNCEndpoint(userID, sessionID)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		case errors.Is(err, services.ErrSessionNotLive):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	conn, err := websocket.Upgrade(c.Writer, c.Request)
+	if err != nil {
+		return // The upgrader already wrote the error response
+	}
+	h.services.Browser.RelayVNC(userID, sessionID, target, conn)
+}
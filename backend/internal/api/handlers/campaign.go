@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/web3airdropos/backend/internal/services"
 )
@@ -42,6 +45,10 @@ func (h *CampaignHandler) Create(c *gin.Context) {
 
 	campaign, err := h.services.Campaign.Create(userID, &req)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidWarmupScore) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -82,6 +89,14 @@ func (h *CampaignHandler) Update(c *gin.Context) {
 
 	campaign, err := h.services.Campaign.Update(userID, campaignID, &req)
 	if err != nil {
+		if errors.Is(err, services.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidWarmupScore) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -105,6 +120,78 @@ func (h *CampaignHandler) Delete(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "campaign deleted"})
 }
 
+func (h *CampaignHandler) Clone(c *gin.Context) {
+	userID := getUserID(c)
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign ID"})
+		return
+	}
+
+	var req services.CloneCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	campaign, err := h.services.Campaign.Clone(userID, campaignID, req.Name, req.WalletGroupIDs)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "campaign not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, campaign)
+}
+
+func (h *CampaignHandler) Export(c *gin.Context) {
+	userID := getUserID(c)
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign ID"})
+		return
+	}
+
+	doc, err := h.services.Campaign.Export(userID, campaignID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "campaign not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="campaign-%s.json"`, campaignID))
+	c.JSON(http.StatusOK, doc)
+}
+
+func (h *CampaignHandler) Import(c *gin.Context) {
+	userID := getUserID(c)
+
+	var doc services.CampaignExport
+	if err := c.ShouldBindJSON(&doc); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	campaign, err := h.services.Campaign.Import(userID, &doc)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnsupportedExportVersion), errors.Is(err, services.ErrInvalidTemplate):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, campaign)
+}
+
 func (h *CampaignHandler) GetTasks(c *gin.Context) {
 	userID := getUserID(c)
 	campaignID, err := uuid.Parse(c.Param("id"))
@@ -145,6 +232,36 @@ func (h *CampaignHandler) AddTask(c *gin.Context) {
 	c.JSON(http.StatusCreated, task)
 }
 
+func (h *CampaignHandler) ReorderTasks(c *gin.Context) {
+	userID := getUserID(c)
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign ID"})
+		return
+	}
+
+	var req services.ReorderTasksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tasks, err := h.services.Campaign.ReorderTasks(userID, campaignID, req.TaskIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "campaign not found"})
+		case errors.Is(err, services.ErrInvalidTaskOrder):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tasks": tasks})
+}
+
 func (h *CampaignHandler) ExecuteBulk(c *gin.Context) {
 	userID := getUserID(c)
 	campaignID, err := uuid.Parse(c.Param("id"))
@@ -159,10 +276,15 @@ func (h *CampaignHandler) ExecuteBulk(c *gin.Context) {
 		return
 	}
 
-	if err := h.services.Campaign.ExecuteBulk(userID, campaignID, &req); err != nil {
+	plan, err := h.services.Campaign.ExecuteBulk(c.Request.Context(), userID, campaignID, &req)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if req.DryRun {
+		c.JSON(http.StatusOK, gin.H{"dry_run": true, "plan": plan})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "bulk execution started"})
 }
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/api/middleware"
+	"github.com/web3airdropos/backend/internal/services"
+)
+
+type CampaignTemplateHandler struct {
+	services *services.Container
+}
+
+func NewCampaignTemplateHandler(s *services.Container) *CampaignTemplateHandler {
+	return &CampaignTemplateHandler{services: s}
+}
+
+func (h *CampaignTemplateHandler) List(c *gin.Context) {
+	userID := getUserID(c)
+
+	templates, err := h.services.Campaign.ListTemplates(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+func (h *CampaignTemplateHandler) Get(c *gin.Context) {
+	userID := getUserID(c)
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template ID"})
+		return
+	}
+
+	template, err := h.services.Campaign.GetTemplate(userID, templateID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// Save stores one of the user's campaigns as a template. Only admins may
+// publish templates to every user.
+func (h *CampaignTemplateHandler) Save(c *gin.Context) {
+	userID := getUserID(c)
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign ID"})
+		return
+	}
+
+	var req services.SaveTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.IsPublic && !middleware.IsAdmin(c, h.services.DB, h.services.Config.AdminEmails) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required to publish templates"})
+		return
+	}
+
+	template, err := h.services.Campaign.SaveAsTemplate(userID, campaignID, &req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "campaign not found"})
+			return
+		}
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+func (h *CampaignTemplateHandler) CreateCampaign(c *gin.Context) {
+	userID := getUserID(c)
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template ID"})
+		return
+	}
+
+	var req services.CreateFromTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	campaign, err := h.services.Campaign.CreateFromTemplate(userID, templateID, &req)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, campaign)
+}
+
+func (h *CampaignTemplateHandler) Delete(c *gin.Context) {
+	userID := getUserID(c)
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template ID"})
+		return
+	}
+
+	if err := h.services.Campaign.DeleteTemplate(userID, templateID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "template deleted"})
+}
+
+func (h *CampaignTemplateHandler) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTemplateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrMissingTemplateParams), errors.Is(err, services.ErrInvalidTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -81,6 +82,10 @@ func (h *ContentHandler) UpdateDraft(c *gin.Context) {
 	}
 
 	draft, err := h.services.Content.UpdateDraft(userID, draftID, &req)
+	if errors.Is(err, services.ErrDraftFlagged) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -132,6 +137,10 @@ func (h *ContentHandler) Schedule(c *gin.Context) {
 	}
 
 	post, err := h.services.Content.Schedule(userID, &req)
+	if errors.Is(err, services.ErrDraftFlagged) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -163,9 +172,45 @@ func (h *ContentHandler) CancelScheduled(c *gin.Context) {
 	}
 
 	if err := h.services.Content.CancelScheduled(userID, postID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(scheduledPostErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "scheduled post cancelled"})
 }
+
+func (h *ContentHandler) Reschedule(c *gin.Context) {
+	userID := getUserID(c)
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
+		return
+	}
+
+	var req services.ReschedulePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	post, err := h.services.Content.Reschedule(userID, postID, req.ScheduledAt)
+	if err != nil {
+		c.JSON(scheduledPostErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
+func scheduledPostErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrScheduledPostNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrPostNotPending):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrScheduleInPast):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -16,10 +16,20 @@ func NewDashboardHandler(s *services.Container) *DashboardHandler {
 	return &DashboardHandler{services: s}
 }
 
+// GetStats accepts ?range=24h|7d|30d|90d or ?from=&to= (RFC3339 or YYYY-MM-DD),
+// and ?refresh=true to skip the stats cache
 func (h *DashboardHandler) GetStats(c *gin.Context) {
 	userID := getUserID(c)
 
-	stats, err := h.services.Dashboard.GetStats(userID)
+	window, err := services.ParseStatsRange(c.Query("range"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid range: use range=24h|7d|30d|90d or from/to spanning at most 366 days"})
+		return
+	}
+
+	refresh := c.Query("refresh") == "true"
+
+	stats, err := h.services.Dashboard.GetStats(userID, window, refresh)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -51,3 +61,29 @@ func (h *DashboardHandler) GetActiveCampaigns(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"campaigns": campaigns})
 }
+
+// GetRateLimits reports current rate limit usage for each active account
+func (h *DashboardHandler) GetRateLimits(c *gin.Context) {
+	userID := getUserID(c)
+
+	limits, err := h.services.Dashboard.GetRateLimits(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rate_limits": limits})
+}
+
+// GetCircuits reports the circuit breaker state of each active account
+func (h *DashboardHandler) GetCircuits(c *gin.Context) {
+	userID := getUserID(c)
+
+	circuits, err := h.services.Dashboard.GetCircuits(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"circuits": circuits})
+}
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/web3airdropos/backend/internal/services"
 )
@@ -43,6 +47,10 @@ func (h *JobHandler) Create(c *gin.Context) {
 
 	job, err := h.services.Job.Create(userID, &req)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidJobTimeout) || errors.Is(err, services.ErrInvalidJobType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -83,6 +91,10 @@ func (h *JobHandler) Update(c *gin.Context) {
 
 	job, err := h.services.Job.Update(userID, jobID, &req)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidJobTimeout) || errors.Is(err, services.ErrInvalidJobType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -114,7 +126,7 @@ func (h *JobHandler) Start(c *gin.Context) {
 		return
 	}
 
-	if err := h.services.Job.Start(userID, jobID); err != nil {
+	if err := h.services.Job.Start(c.Request.Context(), userID, jobID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -146,12 +158,38 @@ func (h *JobHandler) GetLogs(c *gin.Context) {
 		return
 	}
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	level := c.Query("level")
+	query := services.JobLogQuery{}
+	query.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "100"))
+	query.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if query.Limit <= 0 || query.Limit > 1000 {
+		query.Limit = 100
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+	// level may repeat or be comma-separated, e.g. level=warn,error
+	for _, value := range c.QueryArray("level") {
+		for _, level := range strings.Split(value, ",") {
+			if level = strings.TrimSpace(level); level != "" {
+				query.Levels = append(query.Levels, level)
+			}
+		}
+	}
+	if v := c.Query("since"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be RFC3339"})
+			return
+		}
+		query.Since = &since
+	}
 
-	logs, total, err := h.services.Job.GetLogs(userID, jobID, limit, offset, level)
+	logs, total, err := h.services.Job.GetLogs(userID, jobID, query)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -159,7 +197,52 @@ func (h *JobHandler) GetLogs(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"logs":   logs,
 		"total":  total,
-		"limit":  limit,
-		"offset": offset,
+		"limit":  query.Limit,
+		"offset": query.Offset,
+	})
+}
+
+func (h *JobHandler) ListDeadLetters(c *gin.Context) {
+	userID := getUserID(c)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	entries, total, err := h.services.Job.ListDeadLetters(userID, c.Query("source"), c.Query("status"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"dead_letters": entries,
+		"total":        total,
+		"limit":        limit,
+		"offset":       offset,
 	})
 }
+
+func (h *JobHandler) ReplayDeadLetter(c *gin.Context) {
+	userID := getUserID(c)
+	entryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dead-letter ID"})
+		return
+	}
+
+	entry, err := h.services.Job.ReplayDeadLetter(c.Request.Context(), userID, entryID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrDeadLetterNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrDeadLetterReplayed):
+			status = http.StatusConflict
+		case errors.Is(err, services.ErrReplayUnavailable):
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, entry)
+}
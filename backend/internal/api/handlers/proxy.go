@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -40,7 +41,7 @@ func (h *ProxyHandler) Create(c *gin.Context) {
 
 	proxy, err := h.services.Proxy.Create(userID, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writePoolError(c, err)
 		return
 	}
 
@@ -63,7 +64,7 @@ func (h *ProxyHandler) Update(c *gin.Context) {
 
 	proxy, err := h.services.Proxy.Update(userID, proxyID, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writePoolError(c, err)
 		return
 	}
 
@@ -120,3 +121,113 @@ func (h *ProxyHandler) BulkCreate(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, gin.H{"proxies": proxies, "count": len(proxies)})
 }
+
+// Import creates proxies from a pasted list, reporting each line's outcome
+func (h *ProxyHandler) Import(c *gin.Context) {
+	userID := getUserID(c)
+
+	var req services.ProxyImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.services.Proxy.BulkImport(c.Request.Context(), userID, req.Lines, req.ProxyImportOptions)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTooManyProxyLines), errors.Is(err, services.ErrInvalidProxyType),
+			errors.Is(err, services.ErrNoProxyLines):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			writePoolError(c, err)
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if result.Created > 0 {
+		status = http.StatusCreated
+	}
+	c.JSON(status, result)
+}
+
+func (h *ProxyHandler) ListPools(c *gin.Context) {
+	userID := getUserID(c)
+
+	pools, err := h.services.Proxy.ListPools(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pools": pools})
+}
+
+func (h *ProxyHandler) CreatePool(c *gin.Context) {
+	userID := getUserID(c)
+
+	var req services.ProxyPoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pool, err := h.services.Proxy.CreatePool(userID, &req)
+	if err != nil {
+		writePoolError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, pool)
+}
+
+func (h *ProxyHandler) UpdatePool(c *gin.Context) {
+	userID := getUserID(c)
+	poolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pool ID"})
+		return
+	}
+
+	var req services.ProxyPoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pool, err := h.services.Proxy.UpdatePool(userID, poolID, &req)
+	if err != nil {
+		writePoolError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pool)
+}
+
+func (h *ProxyHandler) DeletePool(c *gin.Context) {
+	userID := getUserID(c)
+	poolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pool ID"})
+		return
+	}
+
+	if err := h.services.Proxy.DeletePool(userID, poolID); err != nil {
+		writePoolError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "pool deleted"})
+}
+
+// writePoolError maps proxy pool errors to status codes
+func writePoolError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrProxyPoolNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidPoolPolicy):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/services"
+)
+
+// RPCEndpointHandler manages RPC endpoints, either the caller's own or the shared ones
+type RPCEndpointHandler struct {
+	services *services.Container
+	shared   bool
+}
+
+func NewRPCEndpointHandler(s *services.Container) *RPCEndpointHandler {
+	return &RPCEndpointHandler{services: s}
+}
+
+// NewSharedRPCEndpointHandler manages the endpoints every user falls back to.
+// Mount it behind admin-only routes.
+func NewSharedRPCEndpointHandler(s *services.Container) *RPCEndpointHandler {
+	return &RPCEndpointHandler{services: s, shared: true}
+}
+
+func (h *RPCEndpointHandler) owner(c *gin.Context) *uuid.UUID {
+	if h.shared {
+		return nil
+	}
+	userID := getUserID(c)
+	return &userID
+}
+
+func (h *RPCEndpointHandler) List(c *gin.Context) {
+	var chainID int64
+	if raw := c.Query("chain_id"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid chain ID"})
+			return
+		}
+		chainID = parsed
+	}
+
+	endpoints, err := h.services.RPCEndpoints.List(h.owner(c), chainID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"endpoints": endpoints})
+}
+
+func (h *RPCEndpointHandler) Create(c *gin.Context) {
+	var req services.CreateRPCEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endpoint, err := h.services.RPCEndpoints.Create(h.owner(c), &req)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, endpoint)
+}
+
+func (h *RPCEndpointHandler) Update(c *gin.Context) {
+	endpointID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid endpoint ID"})
+		return
+	}
+
+	var req services.UpdateRPCEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endpoint, err := h.services.RPCEndpoints.Update(h.owner(c), endpointID, &req)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoint)
+}
+
+func (h *RPCEndpointHandler) Delete(c *gin.Context) {
+	endpointID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid endpoint ID"})
+		return
+	}
+
+	if err := h.services.RPCEndpoints.Delete(h.owner(c), endpointID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "endpoint deleted"})
+}
+
+func (h *RPCEndpointHandler) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrRPCEndpointNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidRPCURL):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/web3airdropos/backend/internal/services"
+)
+
+type SearchHandler struct {
+	services *services.Container
+}
+
+func NewSearchHandler(s *services.Container) *SearchHandler {
+	return &SearchHandler{services: s}
+}
+
+// Search accepts ?q=, an optional comma-separated ?types=campaign,task,account,wallet
+// and ?limit= per type
+func (h *SearchHandler) Search(c *gin.Context) {
+	userID := getUserID(c)
+
+	var types []string
+	if raw := c.Query("types"); raw != "" {
+		types = strings.Split(raw, ",")
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	results, err := h.services.Search.Search(userID, c.Query("q"), types, limit)
+	if err != nil {
+		if errors.Is(err, services.ErrSearchQueryTooShort) || errors.Is(err, services.ErrUnknownSearchType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
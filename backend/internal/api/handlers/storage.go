@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/web3airdropos/backend/internal/services"
+	"github.com/web3airdropos/backend/internal/storage"
+)
+
+type StorageHandler struct {
+	services *services.Container
+}
+
+func NewStorageHandler(s *services.Container) *StorageHandler {
+	return &StorageHandler{services: s}
+}
+
+// Serve returns a locally stored object to holders of a signed URL. With the S3
+// backend signed URLs point at the bucket, so nothing is served here.
+func (h *StorageHandler) Serve(c *gin.Context) {
+	local, ok := h.services.Storage.(*storage.Local)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if err := local.Verify(key, c.Query("expires"), c.Query("signature")); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := local.Get(c.Request.Context(), key)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, http.DetectContentType(data), data)
+}
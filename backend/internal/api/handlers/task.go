@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"github.com/web3airdropos/backend/internal/circuit"
 	"github.com/web3airdropos/backend/internal/services"
 )
 
@@ -50,6 +53,10 @@ func (h *TaskHandler) Update(c *gin.Context) {
 
 	task, err := h.services.Task.Update(userID, taskID, &req)
 	if err != nil {
+		if errors.Is(err, services.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -71,8 +78,18 @@ func (h *TaskHandler) Execute(c *gin.Context) {
 		return
 	}
 
-	execution, err := h.services.Task.Execute(userID, taskID, &req)
+	execution, err := h.services.Task.Execute(c.Request.Context(), userID, taskID, &req)
 	if err != nil {
+		var openErr *circuit.OpenError
+		if errors.As(err, &openErr) {
+			c.Header("Retry-After", strconv.Itoa(int(openErr.RetryAfter.Seconds())+1))
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrAccountNotWarmedUp) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -80,6 +97,86 @@ func (h *TaskHandler) Execute(c *gin.Context) {
 	c.JSON(http.StatusOK, execution)
 }
 
+// ExecuteBatch runs an ordered list of tasks for one wallet or account
+func (h *TaskHandler) ExecuteBatch(c *gin.Context) {
+	userID := getUserID(c)
+
+	var req services.ExecuteBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.services.Task.ExecuteBatch(c.Request.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTaskBatch) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Schedule runs the task for one wallet or account at a later time
+func (h *TaskHandler) Schedule(c *gin.Context) {
+	userID := getUserID(c)
+	taskID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task ID"})
+		return
+	}
+
+	var req services.ScheduleExecutionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scheduled, err := h.services.Task.ScheduleExecution(userID, taskID, req.RunAt, &req.ExecuteTaskRequest)
+	if err != nil {
+		c.JSON(scheduledExecutionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, scheduled)
+}
+
+func (h *TaskHandler) ListScheduled(c *gin.Context) {
+	userID := getUserID(c)
+	taskID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task ID"})
+		return
+	}
+
+	scheduled, err := h.services.Task.ListScheduled(userID, taskID)
+	if err != nil {
+		c.JSON(scheduledExecutionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"scheduled": scheduled})
+}
+
+func (h *TaskHandler) CancelScheduled(c *gin.Context) {
+	userID := getUserID(c)
+	scheduledID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scheduled execution ID"})
+		return
+	}
+
+	if err := h.services.Task.CancelScheduled(userID, scheduledID); err != nil {
+		c.JSON(scheduledExecutionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "scheduled execution cancelled"})
+}
+
 func (h *TaskHandler) Continue(c *gin.Context) {
 	userID := getUserID(c)
 	taskID, err := uuid.Parse(c.Param("id"))
@@ -98,7 +195,11 @@ func (h *TaskHandler) Continue(c *gin.Context) {
 	}
 
 	if err := h.services.Task.Continue(userID, taskID, req.ExecutionID, req.Result); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidScreenshot) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -121,3 +222,16 @@ func (h *TaskHandler) GetExecutions(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"executions": executions})
 }
+
+func scheduledExecutionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrTaskNotFound), errors.Is(err, services.ErrScheduledExecutionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrScheduledExecutionNotPending):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrScheduleInPast):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
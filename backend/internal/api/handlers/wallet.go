@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
+	"github.com/web3airdropos/backend/internal/auth"
+	"github.com/web3airdropos/backend/internal/explorer"
 	"github.com/web3airdropos/backend/internal/models"
 	"github.com/web3airdropos/backend/internal/services"
 )
@@ -109,12 +116,57 @@ func (h *WalletHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.services.Wallet.Delete(userID, walletID); err != nil {
+	// Wallets with an imported key need ?confirm=<address>
+	if err := h.services.Wallet.Delete(userID, walletID, c.Query("confirm")); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "wallet not found"})
+		case errors.Is(err, services.ErrDeleteConfirmationRequired):
+			c.JSON(http.StatusPreconditionRequired, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "wallet deleted",
+		"restorable_for": h.services.Config.SoftDeleteRetention.String(),
+	})
+}
+
+// ListDeleted lists deleted wallets that can still be restored
+func (h *WalletHandler) ListDeleted(c *gin.Context) {
+	userID := getUserID(c)
+
+	wallets, err := h.services.Wallet.ListDeleted(userID)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "wallet deleted"})
+	c.JSON(http.StatusOK, gin.H{"wallets": wallets})
+}
+
+func (h *WalletHandler) Restore(c *gin.Context) {
+	userID := getUserID(c)
+	walletID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid wallet ID"})
+		return
+	}
+
+	wallet, err := h.services.Wallet.Restore(userID, walletID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "deleted wallet not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, wallet)
 }
 
 func (h *WalletHandler) GetBalance(c *gin.Context) {
@@ -124,6 +176,29 @@ func (h *WalletHandler) GetBalance(c *gin.Context) {
 		return
 	}
 
+	// ?chains=all returns every chain the wallet is active on, ?chains=1,8453 the listed ones
+	if chains := c.Query("chains"); chains != "" {
+		var chainIDs []int64
+		if chains != "all" {
+			for _, part := range strings.Split(chains, ",") {
+				chainID, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+				if err != nil || chainID <= 0 {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid chain ID: " + part})
+					return
+				}
+				chainIDs = append(chainIDs, chainID)
+			}
+		}
+
+		balances, err := h.services.Wallet.GetBalances(walletID, chainIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"balances": balances})
+		return
+	}
+
 	balance, err := h.services.Wallet.GetBalance(walletID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -150,6 +225,35 @@ func (h *WalletHandler) GetTransactions(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"transactions": transactions, "total": total})
 }
 
+func (h *WalletHandler) SyncTransactions(c *gin.Context) {
+	userID := getUserID(c)
+	walletID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid wallet ID"})
+		return
+	}
+
+	if _, err := h.services.Wallet.Get(userID, walletID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "wallet not found"})
+		return
+	}
+
+	fetched, err := h.services.Wallet.FetchOnchainHistory(walletID)
+	if err != nil {
+		switch {
+		case errors.Is(err, explorer.ErrUnsupported), errors.Is(err, explorer.ErrNotConfigured):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, explorer.ErrRateLimited):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"fetched": fetched})
+}
+
 func (h *WalletHandler) PrepareTransaction(c *gin.Context) {
 	userID := getUserID(c)
 	walletID, err := uuid.Parse(c.Param("id"))
@@ -173,6 +277,78 @@ func (h *WalletHandler) PrepareTransaction(c *gin.Context) {
 	c.JSON(http.StatusOK, prepared)
 }
 
+func (h *WalletHandler) SignMessage(c *gin.Context) {
+	userID := getUserID(c)
+	walletID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid wallet ID"})
+		return
+	}
+
+	var req services.SignMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	signed, err := h.services.Wallet.SignMessage(userID, walletID, req.Message, req.TypedData)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "wallet not found"})
+		case errors.Is(err, services.ErrServerSigningDisabled):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrSigningUnsupported), errors.Is(err, services.ErrInvalidTypedData):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, signed)
+}
+
+// Export returns the chosen wallets' keys as a file encrypted under the
+// caller's passphrase, after re-checking their password and 2FA code
+func (h *WalletHandler) Export(c *gin.Context) {
+	userID := getUserID(c)
+
+	var req services.ExportWalletsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	export, err := h.services.Wallet.Export(userID, req.WalletIDs, req.Passphrase, &services.IdentityConfirmation{
+		Password:  req.Password,
+		Code:      req.Code,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrAccountLocked):
+			c.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+		case errors.Is(err, auth.ErrInvalidCredentials), errors.Is(err, auth.ErrInvalidTwoFactorCode),
+			errors.Is(err, auth.ErrTwoFactorCodeRequired), errors.Is(err, auth.ErrTwoFactorNotEnrolled):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrWeakPassphrase):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "wallet not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	filename := fmt.Sprintf("web3airdropos-wallets-%s.json", export.CreatedAt.Format("20060102-150405"))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, export)
+}
+
 func (h *WalletHandler) Import(c *gin.Context) {
 	userID := getUserID(c)
 	
@@ -184,6 +360,10 @@ func (h *WalletHandler) Import(c *gin.Context) {
 
 	wallet, err := h.services.Wallet.Import(userID, &req)
 	if err != nil {
+		if errors.Is(err, services.ErrWalletDeleted) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -191,26 +371,46 @@ func (h *WalletHandler) Import(c *gin.Context) {
 	c.JSON(http.StatusCreated, wallet)
 }
 
+// BulkCreate creates small batches within the request. Larger counts, or any
+// count with "async": true, start a background job and answer 202 with its ID.
 func (h *WalletHandler) BulkCreate(c *gin.Context) {
 	userID := getUserID(c)
-	
+
 	var req struct {
-		Count    int               `json:"count" binding:"required,min=1,max=50"`
-		Type     models.WalletType `json:"type" binding:"required"`
-		GroupID  *uuid.UUID        `json:"group_id"`
+		Count   int               `json:"count" binding:"required,min=1"`
+		Type    models.WalletType `json:"type" binding:"required"`
+		GroupID *uuid.UUID        `json:"group_id"`
+		Async   bool              `json:"async"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	wallets, err := h.services.Wallet.BulkCreate(userID, req.Count, req.Type, req.GroupID)
+	if req.Async || req.Count > services.MaxSyncBulkWallets {
+		job, err := h.services.Wallet.BulkCreateAsync(userID, req.Count, req.Type, req.GroupID)
+		if err != nil {
+			c.JSON(bulkWalletErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"job_id": job.ID, "status": job.Status, "count": req.Count})
+		return
+	}
+
+	result, err := h.services.Wallet.BulkCreate(userID, req.Count, req.Type, req.GroupID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(bulkWalletErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"wallets": wallets, "count": len(wallets)})
+	c.JSON(http.StatusCreated, result)
+}
+
+func bulkWalletErrorStatus(err error) int {
+	if errors.Is(err, services.ErrTooManyWallets) || errors.Is(err, services.ErrUnsupportedWalletType) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
 }
 
 // Wallet Group Handler
@@ -338,3 +538,33 @@ func (h *WalletGroupHandler) RemoveWallets(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "wallets removed"})
 }
+
+func (h *WalletGroupHandler) Sweep(c *gin.Context) {
+	userID := getUserID(c)
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group ID"})
+		return
+	}
+
+	var req services.SweepRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	summary, err := h.services.Wallet.Sweep(userID, groupID, req.Destination, req.ChainID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidDestination):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
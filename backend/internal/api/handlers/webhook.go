@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/services"
+)
+
+type WebhookHandler struct {
+	services *services.Container
+}
+
+func NewWebhookHandler(s *services.Container) *WebhookHandler {
+	return &WebhookHandler{services: s}
+}
+
+func (h *WebhookHandler) List(c *gin.Context) {
+	userID := getUserID(c)
+
+	hooks, err := h.services.Webhooks.List(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhooks": hooks})
+}
+
+// Create returns the signing secret alongside the webhook; it is not shown again
+func (h *WebhookHandler) Create(c *gin.Context) {
+	userID := getUserID(c)
+
+	var req services.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hook, err := h.services.Webhooks.Create(userID, &req)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"webhook": hook, "secret": hook.Secret})
+}
+
+func (h *WebhookHandler) Update(c *gin.Context) {
+	userID := getUserID(c)
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook ID"})
+		return
+	}
+
+	var req services.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hook, err := h.services.Webhooks.Update(userID, webhookID, &req)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	if req.RotateSecret {
+		c.JSON(http.StatusOK, gin.H{"webhook": hook, "secret": hook.Secret})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"webhook": hook})
+}
+
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	userID := getUserID(c)
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook ID"})
+		return
+	}
+
+	if err := h.services.Webhooks.Delete(userID, webhookID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "webhook deleted"})
+}
+
+func (h *WebhookHandler) Test(c *gin.Context) {
+	userID := getUserID(c)
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook ID"})
+		return
+	}
+
+	delivery, err := h.services.Webhooks.Test(userID, webhookID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+func (h *WebhookHandler) Deliveries(c *gin.Context) {
+	userID := getUserID(c)
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook ID"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	deliveries, err := h.services.Webhooks.Deliveries(userID, webhookID, limit)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+func (h *WebhookHandler) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrWebhookNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidWebhookURL), errors.Is(err, services.ErrInvalidWebhookEvent):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
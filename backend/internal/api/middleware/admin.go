@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+// RequireAdmin allows only admins: users whose stored role is admin, or whose
+// token email is in emails. It must run after the auth middleware.
+func RequireAdmin(db *gorm.DB, emails []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsAdmin(c, db, emails) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Admin access required",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// IsAdmin reports whether the authenticated user has the admin role or an email
+// in emails. The role is read from the database rather than the token, so a
+// demoted admin loses access at once. The email list bootstraps admins before
+// any user has the role.
+func IsAdmin(c *gin.Context, db *gorm.DB, emails []string) bool {
+	email := c.GetString("email")
+	for _, admin := range emails {
+		if email != "" && strings.EqualFold(admin, email) {
+			return true
+		}
+	}
+
+	userID, ok := GetUserID(c)
+	if !ok {
+		return false
+	}
+	var user models.User
+	if err := db.WithContext(c.Request.Context()).Select("role").First(&user, "id = ?", userID).Error; err != nil {
+		return false
+	}
+	return user.Role == models.RoleAdmin
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+func TestRequireAdminReadsStoredRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		name   string
+		email  string
+		stored string // role in the database; empty when no query is expected
+		want   int
+	}{
+		{"admin", "a@example.com", models.RoleAdmin, http.StatusOK},
+		{"demoted admin", "a@example.com", models.RoleUser, http.StatusForbidden},
+		{"bootstrap email", "Root@Example.com", "", http.StatusOK},
+	}
+	for _, tc := range cases {
+		conn, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{Logger: logger.Discard})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.stored != "" {
+			mock.ExpectQuery(`SELECT "role" FROM "users" WHERE id = \$1`).
+				WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(tc.stored))
+		}
+
+		router := gin.New()
+		router.GET("/", func(c *gin.Context) {
+			c.Set("user_id", uuid.New())
+			c.Set("email", tc.email)
+		}, RequireAdmin(db, []string{"root@example.com"}), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, w.Code, tc.want)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+		conn.Close()
+	}
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	gorillaws "github.com/gorilla/websocket"
+
+	"github.com/web3airdropos/backend/internal/websocket"
 )
 
 type Claims struct {
@@ -18,6 +21,12 @@ type Claims struct {
 func Auth(jwtSecret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" && gorillaws.IsWebSocketUpgrade(c.Request) {
+			// Browsers can't set headers on WebSocket upgrades
+			if token, _ := websocket.TokenFromRequest(c.Request); token != "" {
+				authHeader = "Bearer " + token
+			}
+		}
 		if authHeader == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"error": "Authorization header required",
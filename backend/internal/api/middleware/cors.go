@@ -1,18 +1,107 @@
 package middleware
 
 import (
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 )
 
-func CORS() gin.HandlerFunc {
+// CORSConfig controls which browser origins may call the API
+type CORSConfig struct {
+	AllowedOrigins []string // Exact origins, e.g. https://app.example.com; "*" allows any origin without credentials
+	OriginPattern  string   // Optional regex the whole origin must match; it is anchored at both ends
+
+	// AllowAllWhenUnset echoes any origin when nothing is configured (development).
+	// Otherwise cross-origin requests are denied until origins are configured.
+	AllowAllWhenUnset bool
+}
+
+// originPolicy is a CORSConfig compiled for matching
+type originPolicy struct {
+	allowed  map[string]bool
+	wildcard bool
+	pattern  *regexp.Regexp
+	allowAny bool
+}
+
+func newOriginPolicy(cfg CORSConfig) *originPolicy {
+	p := &originPolicy{allowed: make(map[string]bool, len(cfg.AllowedOrigins))}
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		if origin == "*" {
+			p.wildcard = true
+			continue
+		}
+		if origin != "" {
+			p.allowed[origin] = true
+		}
+	}
+
+	if cfg.OriginPattern != "" {
+		// Anchor the pattern so https://app.example.com.evil.net cannot pass for
+		// https://app.example.com
+		var err error
+		if p.pattern, err = regexp.Compile("^(?:" + cfg.OriginPattern + ")$"); err != nil {
+			log.Printf("⚠️ Ignoring invalid CORS origin pattern %q: %v", cfg.OriginPattern, err)
+		}
+	}
+
+	p.allowAny = cfg.AllowAllWhenUnset && !p.wildcard && len(p.allowed) == 0 && p.pattern == nil
+	return p
+}
+
+// credentialed reports whether origin is allowed by name, pattern or AllowAllWhenUnset
+func (p *originPolicy) credentialed(origin string) bool {
+	return p.allowAny || p.allowed[origin] || (p.pattern != nil && p.pattern.MatchString(origin))
+}
+
+// OriginAllowed returns the CORS origin check for requests that bypass the
+// middleware's headers, such as WebSocket upgrades
+func OriginAllowed(cfg CORSConfig) func(origin string) bool {
+	p := newOriginPolicy(cfg)
+	return func(origin string) bool {
+		return p.wildcard || p.credentialed(origin)
+	}
+}
+
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	p := newOriginPolicy(cfg)
+
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			// Same-origin or non-browser request
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+		switch {
+		case p.credentialed(origin):
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Credentials", "true")
+		case p.wildcard:
+			// Browsers reject credentials with a wildcard origin, so don't offer them
+			c.Header("Access-Control-Allow-Origin", "*")
+		default:
+			if c.Request.Method == "OPTIONS" {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Idempotency-Key")
+		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
+		c.Header("Access-Control-Expose-Headers", "X-Request-ID, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, Retry-After, Idempotent-Replayed")
+		c.Header("Access-Control-Max-Age", "86400")
 
 		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORSOriginPatternIsAnchored(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(CORSConfig{OriginPattern: `https://[a-z0-9-]+\.example\.com`}))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	cases := map[string]bool{
+		"https://app.example.com":                  true,
+		"https://staging.example.com":              true,
+		"https://app.example.com.evil.net":         false,
+		"https://evil.net/https://app.example.com": false,
+		"http://app.example.com":                   false,
+	}
+	for origin, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		got := w.Header().Get("Access-Control-Allow-Origin") == origin
+		if got != want {
+			t.Errorf("origin %q allowed = %v, want %v", origin, got, want)
+		}
+	}
+}
+
+func TestOriginAllowedMatchesCORS(t *testing.T) {
+	cases := []struct {
+		cfg    CORSConfig
+		origin string
+		want   bool
+	}{
+		{CORSConfig{AllowedOrigins: []string{"https://app.example.com/"}}, "https://app.example.com", true},
+		{CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}, "https://evil.net", false},
+		{CORSConfig{OriginPattern: `https://[a-z]+\.example\.com`}, "https://app.example.com.evil.net", false},
+		{CORSConfig{AllowedOrigins: []string{"*"}}, "https://evil.net", true},
+		{CORSConfig{}, "https://evil.net", false},
+		{CORSConfig{AllowAllWhenUnset: true}, "https://evil.net", true},
+	}
+	for _, tc := range cases {
+		if got := OriginAllowed(tc.cfg)(tc.origin); got != tc.want {
+			t.Errorf("%+v: origin %q allowed = %v, want %v", tc.cfg, tc.origin, got, tc.want)
+		}
+	}
+}
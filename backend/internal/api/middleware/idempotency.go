@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// IdempotencyHeader names the client-chosen key for a write request
+	IdempotencyHeader = "Idempotency-Key"
+
+	// IdempotentReplayHeader is set on responses replayed from an earlier request
+	IdempotentReplayHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+
+	// idempotencyLockTTL bounds how long a crashed request can hold a key
+	idempotencyLockTTL = 5 * time.Minute
+)
+
+// storedResponse is what a finished request leaves under its key
+type storedResponse struct {
+	Fingerprint string `json:"fingerprint"` // Method, path and body of the original request
+	Pending     bool   `json:"pending,omitempty"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// Idempotent makes a write route safe to retry. A request carrying an
+// Idempotency-Key is run once per user and key: repeats within ttl get the first
+// response back instead of running the handler again. Server errors are not
+// kept, so those can be retried. Requests without the header, and every request
+// when client is nil, pass straight through. It must run after the auth middleware.
+func Idempotent(client *redis.Client, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyHeader)
+		if client == nil || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("%s must be at most %d characters", IdempotencyHeader, maxIdempotencyKeyLength),
+			})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		sum := sha256.Sum256(append([]byte(c.Request.Method+" "+c.Request.URL.Path+"\n"), body...))
+		fingerprint := hex.EncodeToString(sum[:])
+		userID, _ := c.Get("user_id")
+		redisKey := fmt.Sprintf("idempotency:%v:%s", userID, key)
+		ctx := c.Request.Context()
+
+		// Claim the key; whoever loses the race sees the pending marker or the result
+		pending, _ := json.Marshal(storedResponse{Fingerprint: fingerprint, Pending: true})
+		claimed, err := client.SetNX(ctx, redisKey, pending, idempotencyLockTTL).Result()
+		if err != nil {
+			// Redis being down should not take writes down with it
+			log.Printf("⚠️ Idempotency check skipped for %s: %v", c.Request.URL.Path, err)
+			c.Next()
+			return
+		}
+		if !claimed {
+			replay(c, client, redisKey, fingerprint)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// The request may be cancelled once the response is written; store regardless
+		storeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			client.Del(storeCtx, redisKey)
+			return
+		}
+		stored, _ := json.Marshal(storedResponse{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err := client.Set(storeCtx, redisKey, stored, ttl).Err(); err != nil {
+			log.Printf("⚠️ Failed to store idempotent response for %s: %v", c.Request.URL.Path, err)
+		}
+	}
+}
+
+// replay answers a repeated key with the stored response
+func replay(c *gin.Context, client *redis.Client, redisKey, fingerprint string) {
+	data, err := client.Get(c.Request.Context(), redisKey).Bytes()
+	if err != nil {
+		// Expired or released between SETNX and GET; let the client retry
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is in progress"})
+		return
+	}
+
+	var stored storedResponse
+	if err := json.Unmarshal(data, &stored); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "corrupt idempotency record"})
+		return
+	}
+	switch {
+	case stored.Fingerprint != fingerprint:
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Idempotency-Key was already used for a different request",
+		})
+	case stored.Pending:
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is in progress"})
+	default:
+		c.Header(IdempotentReplayHeader, "true")
+		c.Data(stored.Status, stored.ContentType, stored.Body)
+		c.Abort()
+	}
+}
+
+// responseRecorder keeps a copy of the response body as it is written
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
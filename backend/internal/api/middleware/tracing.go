@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// untracedPaths are polled by probes and scrapers and would drown out real traffic
+var untracedPaths = []string{"/health", "/healthz", "/readyz", "/livez", "/metrics"}
+
+// Tracing starts a server span per request, continuing the caller's trace when it
+// sends a traceparent header. Handlers reach the span through c.Request.Context().
+func Tracing(service string) gin.HandlerFunc {
+	return otelgin.Middleware(service, otelgin.WithFilter(func(r *http.Request) bool {
+		for _, path := range untracedPaths {
+			if r.URL.Path == path || strings.HasPrefix(r.URL.Path, path+"/") {
+				return false
+			}
+		}
+		return true
+	}))
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/web3airdropos/backend/internal/config"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+// productionRoutes builds the production router without connecting to anything
+func productionRoutes(t *testing.T) *ProductionServer {
+	t.Helper()
+	return NewProductionServer(&ProductionContainer{
+		Config: config.Load(),
+		WSHub:  websocket.NewHub(),
+	})
+}
+
+func TestAPIDocsMatchRoutes(t *testing.T) {
+	routes := productionRoutes(t).router.Routes()
+
+	for _, route := range undocumentedRoutes(routes) {
+		t.Errorf("route %s has no entry in apiOperations", route)
+	}
+	for _, op := range unservedOperations(routes) {
+		t.Errorf("apiOperations entry %s has no route", op)
+	}
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http/pprof"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerPprof mounts the net/http/pprof handlers on group, which must already
+// require an admin. The index at /debug/pprof/ lists every profile, including
+// goroutine (?debug=2 for full stacks), heap, allocs, block, mutex and threadcreate.
+func registerPprof(group *gin.RouterGroup) {
+	debug := group.Group("/debug/pprof")
+	{
+		debug.GET("/", gin.WrapF(pprof.Index))
+		debug.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+		debug.GET("/profile", gin.WrapF(pprof.Profile))
+		debug.POST("/symbol", gin.WrapF(pprof.Symbol))
+		debug.GET("/symbol", gin.WrapF(pprof.Symbol))
+		debug.GET("/trace", gin.WrapF(pprof.Trace))
+		// pprof.Index only resolves profile names under /debug/pprof/ at the root
+		debug.GET("/:profile", func(c *gin.Context) {
+			pprof.Handler(c.Param("profile")).ServeHTTP(c.Writer, c.Request)
+		})
+	}
+}
@@ -41,8 +41,17 @@ func NewServer(cfg *config.Config, db *gorm.DB, redis *redis.Client, wsHub *webs
 }
 
 func (s *Server) setupRoutes() {
-	// CORS middleware
-	s.router.Use(middleware.CORS())
+	s.router.Use(middleware.RequestID())
+	s.router.Use(middleware.Tracing(s.config.TracingService))
+
+	// CORS middleware; WebSocket upgrades get the same origin check
+	cors := middleware.CORSConfig{
+		AllowedOrigins:    s.config.CORSAllowedOrigins,
+		OriginPattern:     s.config.CORSOriginPattern,
+		AllowAllWhenUnset: true,
+	}
+	s.router.Use(middleware.CORS(cors))
+	websocket.SetAllowedOrigins(middleware.OriginAllowed(cors))
 
 	// Health check
 	s.router.GET("/health", func(c *gin.Context) {
@@ -64,24 +73,35 @@ func (s *Server) setupRoutes() {
 			auth.POST("/refresh", authHandler.RefreshToken)
 		}
 
+		// Stored proofs (public; the signed URL is the credential)
+		v1.GET("/storage/*key", handlers.NewStorageHandler(s.services).Serve)
+
 		// Protected routes
 		protected := v1.Group("")
 		protected.Use(middleware.Auth(s.config.JWTSecret))
 		{
+			// Retries carrying an Idempotency-Key replay the first response
+			idempotent := middleware.Idempotent(s.redis, s.config.IdempotencyTTL)
+
 			// Wallet routes
 			wallets := protected.Group("/wallets")
 			{
 				walletHandler := handlers.NewWalletHandler(s.services)
 				wallets.GET("", walletHandler.List)
-				wallets.POST("", walletHandler.Create)
+				wallets.POST("", idempotent, walletHandler.Create)
 				wallets.GET("/:id", walletHandler.Get)
 				wallets.PUT("/:id", walletHandler.Update)
 				wallets.DELETE("/:id", walletHandler.Delete)
 				wallets.GET("/:id/balance", walletHandler.GetBalance)
 				wallets.GET("/:id/transactions", walletHandler.GetTransactions)
+				wallets.POST("/:id/transactions/sync", walletHandler.SyncTransactions)
 				wallets.POST("/:id/prepare-tx", walletHandler.PrepareTransaction)
-				wallets.POST("/import", walletHandler.Import)
-				wallets.POST("/bulk", walletHandler.BulkCreate)
+				wallets.POST("/:id/sign", walletHandler.SignMessage)
+				wallets.POST("/import", idempotent, walletHandler.Import)
+				wallets.POST("/bulk", idempotent, walletHandler.BulkCreate)
+				wallets.POST("/export", walletHandler.Export)
+				wallets.GET("/deleted", walletHandler.ListDeleted)
+				wallets.POST("/:id/restore", walletHandler.Restore)
 			}
 
 			// Wallet groups
@@ -94,6 +114,7 @@ func (s *Server) setupRoutes() {
 				groups.DELETE("/:id", groupHandler.Delete)
 				groups.POST("/:id/wallets", groupHandler.AddWallets)
 				groups.DELETE("/:id/wallets", groupHandler.RemoveWallets)
+				groups.POST("/:id/sweep", groupHandler.Sweep)
 			}
 
 			// Platform accounts
@@ -102,12 +123,20 @@ func (s *Server) setupRoutes() {
 				accountHandler := handlers.NewAccountHandler(s.services)
 				accounts.GET("", accountHandler.List)
 				accounts.POST("", accountHandler.Create)
+				accounts.POST("/bulk", accountHandler.BulkImport)
 				accounts.GET("/:id", accountHandler.Get)
 				accounts.PUT("/:id", accountHandler.Update)
 				accounts.DELETE("/:id", accountHandler.Delete)
+				accounts.GET("/deleted", accountHandler.ListDeleted)
+				accounts.POST("/:id/restore", accountHandler.Restore)
 				accounts.GET("/:id/activities", accountHandler.GetActivities)
 				accounts.POST("/:id/link-wallet", accountHandler.LinkWallet)
 				accounts.POST("/:id/sync", accountHandler.Sync)
+				accounts.POST("/:id/health", accountHandler.HealthCheck)
+				accounts.GET("/:id/warmup", accountHandler.GetWarmup)
+				accounts.PUT("/:id/warmup", accountHandler.StartWarmup)
+				accounts.DELETE("/:id/warmup", accountHandler.StopWarmup)
+				accounts.POST("/:id/farcaster/signer", accountHandler.RegisterFarcasterSigner)
 			}
 
 			// Campaigns
@@ -116,24 +145,43 @@ func (s *Server) setupRoutes() {
 				campaignHandler := handlers.NewCampaignHandler(s.services)
 				campaigns.GET("", campaignHandler.List)
 				campaigns.POST("", campaignHandler.Create)
+				campaigns.POST("/import", campaignHandler.Import)
 				campaigns.GET("/:id", campaignHandler.Get)
 				campaigns.PUT("/:id", campaignHandler.Update)
 				campaigns.DELETE("/:id", campaignHandler.Delete)
+				campaigns.POST("/:id/clone", campaignHandler.Clone)
+				campaigns.GET("/:id/export", campaignHandler.Export)
 				campaigns.GET("/:id/tasks", campaignHandler.GetTasks)
 				campaigns.POST("/:id/tasks", campaignHandler.AddTask)
-				campaigns.POST("/:id/execute", campaignHandler.ExecuteBulk)
+				campaigns.PUT("/:id/tasks/order", campaignHandler.ReorderTasks)
+				campaigns.POST("/:id/execute", idempotent, campaignHandler.ExecuteBulk)
 				campaigns.GET("/:id/progress", campaignHandler.GetProgress)
 			}
 
+			// Campaign templates
+			templates := protected.Group("/campaign-templates")
+			{
+				templateHandler := handlers.NewCampaignTemplateHandler(s.services)
+				templates.GET("", templateHandler.List)
+				templates.GET("/:id", templateHandler.Get)
+				templates.DELETE("/:id", templateHandler.Delete)
+				templates.POST("/:id/campaigns", templateHandler.CreateCampaign)
+				campaigns.POST("/:id/template", templateHandler.Save)
+			}
+
 			// Tasks
 			tasks := protected.Group("/tasks")
 			{
 				taskHandler := handlers.NewTaskHandler(s.services)
+				tasks.POST("/batch", idempotent, taskHandler.ExecuteBatch)
 				tasks.GET("/:id", taskHandler.Get)
 				tasks.PUT("/:id", taskHandler.Update)
-				tasks.POST("/:id/execute", taskHandler.Execute)
+				tasks.POST("/:id/execute", idempotent, taskHandler.Execute)
 				tasks.POST("/:id/continue", taskHandler.Continue)
 				tasks.GET("/:id/executions", taskHandler.GetExecutions)
+				tasks.POST("/:id/schedule", idempotent, taskHandler.Schedule)
+				tasks.GET("/:id/scheduled", taskHandler.ListScheduled)
+				tasks.DELETE("/scheduled/:id", taskHandler.CancelScheduled)
 			}
 
 			// Browser sessions
@@ -150,6 +198,9 @@ func (s *Server) setupRoutes() {
 				browser.POST("/sessions/:id/action", browserHandler.ExecuteAction)
 				browser.POST("/sessions/:id/continue", browserHandler.ContinueTask)
 				browser.GET("/sessions/:id/screenshot", browserHandler.GetScreenshot)
+				browser.GET("/sessions/:id/recording", browserHandler.GetRecording)
+				browser.GET("/sessions/:id/recording/frames/:frame", browserHandler.GetRecordingFrame)
+				browser.GET("/sessions/:id/vnc", browserHandler.VNC)
 			}
 
 			// AI Content
@@ -162,9 +213,10 @@ func (s *Server) setupRoutes() {
 				content.PUT("/drafts/:id", contentHandler.UpdateDraft)
 				content.DELETE("/drafts/:id", contentHandler.DeleteDraft)
 				content.POST("/drafts/:id/approve", contentHandler.ApproveDraft)
-				content.POST("/schedule", contentHandler.Schedule)
+				content.POST("/schedule", idempotent, contentHandler.Schedule)
 				content.GET("/scheduled", contentHandler.ListScheduled)
 				content.DELETE("/scheduled/:id", contentHandler.CancelScheduled)
+				content.PUT("/scheduled/:id", contentHandler.Reschedule)
 			}
 
 			// Automation jobs
@@ -172,6 +224,8 @@ func (s *Server) setupRoutes() {
 			{
 				jobHandler := handlers.NewJobHandler(s.services)
 				jobs.GET("", jobHandler.List)
+				jobs.GET("/dead-letter", jobHandler.ListDeadLetters)
+				jobs.POST("/dead-letter/:id/replay", jobHandler.ReplayDeadLetter)
 				jobs.POST("", jobHandler.Create)
 				jobs.GET("/:id", jobHandler.Get)
 				jobs.PUT("/:id", jobHandler.Update)
@@ -191,8 +245,60 @@ func (s *Server) setupRoutes() {
 				proxies.DELETE("/:id", proxyHandler.Delete)
 				proxies.POST("/:id/test", proxyHandler.Test)
 				proxies.POST("/bulk", proxyHandler.BulkCreate)
+				proxies.POST("/import", idempotent, proxyHandler.Import)
+			}
+
+			// Proxy pools rotate accounts and browser profiles across their proxies
+			proxyPools := protected.Group("/proxy-pools")
+			{
+				proxyHandler := handlers.NewProxyHandler(s.services)
+				proxyPools.GET("", proxyHandler.ListPools)
+				proxyPools.POST("", proxyHandler.CreatePool)
+				proxyPools.PUT("/:id", proxyHandler.UpdatePool)
+				proxyPools.DELETE("/:id", proxyHandler.DeletePool)
+			}
+
+			// RPC endpoints the caller's wallets prefer
+			rpcEndpoints := protected.Group("/rpc-endpoints")
+			{
+				rpcHandler := handlers.NewRPCEndpointHandler(s.services)
+				rpcEndpoints.GET("", rpcHandler.List)
+				rpcEndpoints.POST("", rpcHandler.Create)
+				rpcEndpoints.PUT("/:id", rpcHandler.Update)
+				rpcEndpoints.DELETE("/:id", rpcHandler.Delete)
 			}
 
+			// Webhook notifications
+			hooks := protected.Group("/webhooks")
+			{
+				webhookHandler := handlers.NewWebhookHandler(s.services)
+				hooks.GET("", webhookHandler.List)
+				hooks.POST("", webhookHandler.Create)
+				hooks.PUT("/:id", webhookHandler.Update)
+				hooks.DELETE("/:id", webhookHandler.Delete)
+				hooks.POST("/:id/test", webhookHandler.Test)
+				hooks.GET("/:id/deliveries", webhookHandler.Deliveries)
+			}
+
+			// Admin: shared settings
+			admin := protected.Group("/admin")
+			admin.Use(middleware.RequireAdmin(s.db, s.config.AdminEmails))
+			{
+				sharedRPCHandler := handlers.NewSharedRPCEndpointHandler(s.services)
+				admin.GET("/rpc-endpoints", sharedRPCHandler.List)
+				admin.POST("/rpc-endpoints", sharedRPCHandler.Create)
+				admin.PUT("/rpc-endpoints/:id", sharedRPCHandler.Update)
+				admin.DELETE("/rpc-endpoints/:id", sharedRPCHandler.Delete)
+			}
+
+			// Runtime profiles, for diagnosing leaks in a running pod
+			if s.config.EnablePprof {
+				registerPprof(admin)
+			}
+
+			// Search across campaigns, tasks, accounts and wallets
+			protected.GET("/search", handlers.NewSearchHandler(s.services).Search)
+
 			// Dashboard stats
 			dashboard := protected.Group("/dashboard")
 			{
@@ -200,6 +306,8 @@ func (s *Server) setupRoutes() {
 				dashboard.GET("/stats", dashboardHandler.GetStats)
 				dashboard.GET("/activity", dashboardHandler.GetRecentActivity)
 				dashboard.GET("/campaigns/active", dashboardHandler.GetActiveCampaigns)
+				dashboard.GET("/rate-limits", dashboardHandler.GetRateLimits)
+				dashboard.GET("/circuits", dashboardHandler.GetCircuits)
 			}
 		}
 
@@ -208,6 +316,10 @@ func (s *Server) setupRoutes() {
 			websocket.ServeWs(s.wsHub, c.Writer, c.Request, s.config.JWTSecret)
 		})
 	}
+
+	// OpenAPI spec and Swagger UI
+	registerAPIDocs(s.router)
+	checkAPIDocs(s.router.Routes())
 }
 
 // Router returns the underlying gin.Engine
@@ -215,6 +327,16 @@ func (s *Server) Router() *gin.Engine {
 	return s.router
 }
 
+// Services returns the server's service container
+func (s *Server) Services() *services.Container {
+	return s.services
+}
+
+// Close releases the services' connections once the server has stopped
+func (s *Server) Close() {
+	s.services.Close()
+}
+
 func (s *Server) Run(addr string) error {
 	return s.router.Run(addr)
 }
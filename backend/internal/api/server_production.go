@@ -3,20 +3,31 @@ package api
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"gorm.io/gorm"
 
 	"github.com/web3airdropos/backend/internal/api/handlers"
+	"github.com/web3airdropos/backend/internal/api/middleware"
 	"github.com/web3airdropos/backend/internal/audit"
 	"github.com/web3airdropos/backend/internal/auth"
+	"github.com/web3airdropos/backend/internal/circuit"
 	"github.com/web3airdropos/backend/internal/config"
+	"github.com/web3airdropos/backend/internal/jobs"
 	"github.com/web3airdropos/backend/internal/locks"
+	"github.com/web3airdropos/backend/internal/metrics"
+	"github.com/web3airdropos/backend/internal/models"
 	"github.com/web3airdropos/backend/internal/queue"
+	"github.com/web3airdropos/backend/internal/requestid"
 	"github.com/web3airdropos/backend/internal/services"
 	"github.com/web3airdropos/backend/internal/tasks"
 	"github.com/web3airdropos/backend/internal/vault"
@@ -27,6 +38,7 @@ import (
 type ProductionContainer struct {
 	Config      *config.Config
 	DB          *gorm.DB
+	ReadDB      *gorm.DB // Read replica; nil keeps reads on DB
 	Redis       *redis.Client
 	WSHub       *websocket.Hub
 	AuditLogger *audit.Logger
@@ -36,6 +48,8 @@ type ProductionContainer struct {
 	AuthService *auth.AuthService
 	TaskQueue   *queue.Queue
 	TaskManager *tasks.TaskManager
+	Scheduler   *jobs.Scheduler
+	Circuits    *circuit.Breaker
 }
 
 // ProductionServer is the production-ready API server
@@ -43,6 +57,7 @@ type ProductionServer struct {
 	router    *gin.Engine
 	container *ProductionContainer
 	services  *services.Container
+	metrics   *prometheus.Registry
 }
 
 // NewProductionServer creates a new production-ready server
@@ -57,11 +72,35 @@ func NewProductionServer(container *ProductionContainer) *ProductionServer {
 		container.Redis,
 		container.WSHub,
 	)
+	svc.Auth.SetProductionAuth(container.AuthService)
+	svc.Job.SetTaskQueue(container.TaskQueue)
+	svc.Account.SetVault(container.Vault)
+	svc.Circuits = container.Circuits
+	svc.SetReadReplica(container.ReadDB)
+	if container.TaskManager != nil {
+		container.TaskManager.RegisterExecutor(string(models.TaskTypeTransaction), services.NewTransactionExecutor(svc))
+	}
+	if container.Scheduler != nil {
+		container.Scheduler.SetScheduledTaskRunner(svc.Task.RunScheduled)
+		container.Scheduler.SetReceiptPoller(svc.Task.PollTransactionReceipts)
+	}
+
+	// Prometheus collectors
+	sources := metrics.Sources{Redis: container.Redis}
+	if container.Scheduler != nil {
+		sources.Scheduler = container.Scheduler
+	}
+	if container.DB != nil {
+		if sqlDB, err := container.DB.DB(); err == nil {
+			sources.DB = sqlDB
+		}
+	}
 
 	server := &ProductionServer{
 		router:    gin.New(),
 		container: container,
 		services:  svc,
+		metrics:   metrics.NewRegistry(sources),
 	}
 
 	server.setupMiddleware()
@@ -75,6 +114,15 @@ func (s *ProductionServer) setupMiddleware() {
 	// Recovery middleware
 	s.router.Use(gin.Recovery())
 
+	// Request IDs, first so every later log line can carry one
+	s.router.Use(middleware.RequestID())
+
+	// Trace spans; requests to the health and metrics endpoints are not traced
+	s.router.Use(middleware.Tracing(s.container.Config.TracingService))
+
+	// Request duration metrics
+	s.router.Use(metrics.Middleware())
+
 	// Request logging (structured)
 	s.router.Use(s.requestLogger())
 
@@ -94,31 +142,33 @@ func (s *ProductionServer) setupMiddleware() {
 // requestLogger logs requests in structured format
 func (s *ProductionServer) requestLogger() gin.HandlerFunc {
 	return gin.LoggerWithConfig(gin.LoggerConfig{
-		SkipPaths: []string{"/health"},
+		SkipPaths: []string{"/health", "/metrics"},
+		Formatter: func(p gin.LogFormatterParams) string {
+			requestID, _ := p.Keys[requestid.Key].(string)
+			line := fmt.Sprintf("time=%s request_id=%s method=%s path=%q status=%d latency=%s ip=%s",
+				p.TimeStamp.Format(time.RFC3339), requestID, p.Method, p.Path, p.StatusCode, p.Latency, p.ClientIP)
+			if p.ErrorMessage != "" {
+				line += fmt.Sprintf(" error=%q", p.ErrorMessage)
+			}
+			return line + "\n"
+		},
 	})
 }
 
-// corsMiddleware handles CORS with configurable origin
+// corsMiddleware only lets configured origins through. With nothing configured,
+// cross-origin browser requests are denied. WebSocket upgrades, which CORS
+// doesn't cover, are held to the same origins.
 func (s *ProductionServer) corsMiddleware() gin.HandlerFunc {
-	origin := s.container.Config.CORSOrigin
-	if origin == "" {
-		origin = "*"
+	cfg := s.container.Config
+	if len(cfg.CORSAllowedOrigins) == 0 && cfg.CORSOriginPattern == "" {
+		log.Printf("⚠️ No CORS origins configured, cross-origin requests will be denied")
 	}
-
-	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", origin)
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, X-Requested-With")
-		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Max-Age", "86400")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusNoContent)
-			return
-		}
-
-		c.Next()
+	cors := middleware.CORSConfig{
+		AllowedOrigins: cfg.CORSAllowedOrigins,
+		OriginPattern:  cfg.CORSOriginPattern,
 	}
+	websocket.SetAllowedOrigins(middleware.OriginAllowed(cors))
+	return middleware.CORS(cors)
 }
 
 // securityHeaders adds security headers
@@ -152,11 +202,50 @@ func (s *ProductionServer) authRequired() gin.HandlerFunc {
 	return auth.AuthMiddleware(s.container.AuthService)
 }
 
+// adminRequired restricts a group to admins and audits every change made
+// through it, and every refused attempt, as an admin action
+func (s *ProductionServer) adminRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := auth.GetUserID(c)
+		entry := &audit.LogEntry{
+			UserID:     userID,
+			Action:     audit.ActionAdmin,
+			TargetType: "route",
+			TargetID:   c.Request.Method + " " + c.FullPath(),
+			IPAddress:  c.ClientIP(),
+			UserAgent:  c.GetHeader("User-Agent"),
+		}
+
+		if !middleware.IsAdmin(c, s.container.DB, s.container.Config.AdminEmails) {
+			entry.Result = audit.ResultFailed
+			entry.ErrorMessage = "admin access required"
+			s.container.AuditLogger.Log(c.Request.Context(), entry)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			return
+		}
+
+		c.Next()
+
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			return
+		}
+		entry.Result = audit.ResultSuccess
+		if c.Writer.Status() >= http.StatusBadRequest {
+			entry.Result = audit.ResultFailed
+			entry.ErrorMessage = http.StatusText(c.Writer.Status())
+		}
+		s.container.AuditLogger.Log(c.Request.Context(), entry)
+	}
+}
+
 // setupRoutes configures all API routes
 func (s *ProductionServer) setupRoutes() {
 	// Health check (public) with dependency verification
 	s.router.GET("/health", s.healthCheck())
 
+	// Prometheus scrape endpoint
+	s.router.GET("/metrics", metrics.RequireToken(s.container.Config.MetricsToken), metrics.Handler(s.metrics))
+
 	// API v1 routes
 	v1 := s.router.Group("/api/v1")
 	{
@@ -168,29 +257,54 @@ func (s *ProductionServer) setupRoutes() {
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
 			auth.POST("/refresh", authHandler.RefreshToken)
+			auth.POST("/2fa/login", s.completeTwoFactorLogin())
+			auth.POST("/password/forgot", s.requestPasswordReset())
+			auth.POST("/password/reset", s.resetPassword())
 		}
 
+		// Stored proofs (public; the signed URL is the credential)
+		v1.GET("/storage/*key", handlers.NewStorageHandler(s.services).Serve)
+
 		// Protected routes
 		protected := v1.Group("")
 		protected.Use(s.authRequired())
 		{
+			// Signed-in user
+			protected.GET("/auth/me", s.currentUser())
+
 			// Account logout
 			protected.POST("/auth/logout", s.logout())
 
+			// Signed-in devices
+			protected.GET("/auth/sessions", s.listSessions())
+			protected.DELETE("/auth/sessions/:id", s.revokeSession())
+
+			// Two-factor enrollment
+			protected.POST("/auth/2fa/enroll", s.enrollTOTP())
+			protected.POST("/auth/2fa/verify", s.verifyTOTP())
+
+			// Retries carrying an Idempotency-Key replay the first response
+			idempotent := middleware.Idempotent(s.container.Redis, s.container.Config.IdempotencyTTL)
+
 			// Wallet routes
 			wallets := protected.Group("/wallets")
 			{
 				walletHandler := handlers.NewWalletHandler(s.services)
 				wallets.GET("", walletHandler.List)
-				wallets.POST("", s.writeRateLimit(), walletHandler.Create)
+				wallets.POST("", s.writeRateLimit(), idempotent, walletHandler.Create)
 				wallets.GET("/:id", walletHandler.Get)
 				wallets.PUT("/:id", s.writeRateLimit(), walletHandler.Update)
 				wallets.DELETE("/:id", s.writeRateLimit(), walletHandler.Delete)
 				wallets.GET("/:id/balance", walletHandler.GetBalance)
 				wallets.GET("/:id/transactions", walletHandler.GetTransactions)
+				wallets.POST("/:id/transactions/sync", s.writeRateLimit(), walletHandler.SyncTransactions)
 				wallets.POST("/:id/prepare-tx", s.writeRateLimit(), walletHandler.PrepareTransaction)
-				wallets.POST("/import", s.writeRateLimit(), walletHandler.Import)
-				wallets.POST("/bulk", s.writeRateLimit(), walletHandler.BulkCreate)
+				wallets.POST("/:id/sign", s.writeRateLimit(), walletHandler.SignMessage)
+				wallets.POST("/import", s.writeRateLimit(), idempotent, walletHandler.Import)
+				wallets.POST("/bulk", s.writeRateLimit(), idempotent, walletHandler.BulkCreate)
+				wallets.POST("/export", s.writeRateLimit(), walletHandler.Export)
+				wallets.GET("/deleted", walletHandler.ListDeleted)
+				wallets.POST("/:id/restore", s.writeRateLimit(), walletHandler.Restore)
 			}
 
 			// Wallet groups
@@ -203,6 +317,7 @@ func (s *ProductionServer) setupRoutes() {
 				groups.DELETE("/:id", s.writeRateLimit(), groupHandler.Delete)
 				groups.POST("/:id/wallets", s.writeRateLimit(), groupHandler.AddWallets)
 				groups.DELETE("/:id/wallets", s.writeRateLimit(), groupHandler.RemoveWallets)
+				groups.POST("/:id/sweep", s.writeRateLimit(), groupHandler.Sweep)
 			}
 
 			// Platform accounts
@@ -211,12 +326,20 @@ func (s *ProductionServer) setupRoutes() {
 				accountHandler := handlers.NewAccountHandler(s.services)
 				accounts.GET("", accountHandler.List)
 				accounts.POST("", s.writeRateLimit(), accountHandler.Create)
+				accounts.POST("/bulk", s.writeRateLimit(), accountHandler.BulkImport)
 				accounts.GET("/:id", accountHandler.Get)
 				accounts.PUT("/:id", s.writeRateLimit(), accountHandler.Update)
 				accounts.DELETE("/:id", s.writeRateLimit(), accountHandler.Delete)
+				accounts.GET("/deleted", accountHandler.ListDeleted)
+				accounts.POST("/:id/restore", s.writeRateLimit(), accountHandler.Restore)
 				accounts.GET("/:id/activities", accountHandler.GetActivities)
 				accounts.POST("/:id/link-wallet", s.writeRateLimit(), accountHandler.LinkWallet)
 				accounts.POST("/:id/sync", s.writeRateLimit(), accountHandler.Sync)
+				accounts.POST("/:id/health", s.writeRateLimit(), accountHandler.HealthCheck)
+				accounts.GET("/:id/warmup", accountHandler.GetWarmup)
+				accounts.PUT("/:id/warmup", s.writeRateLimit(), accountHandler.StartWarmup)
+				accounts.DELETE("/:id/warmup", s.writeRateLimit(), accountHandler.StopWarmup)
+				accounts.POST("/:id/farcaster/signer", s.writeRateLimit(), accountHandler.RegisterFarcasterSigner)
 			}
 
 			// Campaigns
@@ -225,24 +348,43 @@ func (s *ProductionServer) setupRoutes() {
 				campaignHandler := handlers.NewCampaignHandler(s.services)
 				campaigns.GET("", campaignHandler.List)
 				campaigns.POST("", s.writeRateLimit(), campaignHandler.Create)
+				campaigns.POST("/import", s.writeRateLimit(), campaignHandler.Import)
 				campaigns.GET("/:id", campaignHandler.Get)
 				campaigns.PUT("/:id", s.writeRateLimit(), campaignHandler.Update)
 				campaigns.DELETE("/:id", s.writeRateLimit(), campaignHandler.Delete)
+				campaigns.POST("/:id/clone", s.writeRateLimit(), campaignHandler.Clone)
+				campaigns.GET("/:id/export", campaignHandler.Export)
 				campaigns.GET("/:id/tasks", campaignHandler.GetTasks)
 				campaigns.POST("/:id/tasks", s.writeRateLimit(), campaignHandler.AddTask)
-				campaigns.POST("/:id/execute", s.writeRateLimit(), campaignHandler.ExecuteBulk)
+				campaigns.PUT("/:id/tasks/order", s.writeRateLimit(), campaignHandler.ReorderTasks)
+				campaigns.POST("/:id/execute", s.writeRateLimit(), idempotent, campaignHandler.ExecuteBulk)
 				campaigns.GET("/:id/progress", campaignHandler.GetProgress)
 			}
 
+			// Campaign templates
+			templates := protected.Group("/campaign-templates")
+			{
+				templateHandler := handlers.NewCampaignTemplateHandler(s.services)
+				templates.GET("", templateHandler.List)
+				templates.GET("/:id", templateHandler.Get)
+				templates.DELETE("/:id", s.writeRateLimit(), templateHandler.Delete)
+				templates.POST("/:id/campaigns", s.writeRateLimit(), templateHandler.CreateCampaign)
+				campaigns.POST("/:id/template", s.writeRateLimit(), templateHandler.Save)
+			}
+
 			// Tasks
 			tasks := protected.Group("/tasks")
 			{
 				taskHandler := handlers.NewTaskHandler(s.services)
+				tasks.POST("/batch", s.writeRateLimit(), idempotent, taskHandler.ExecuteBatch)
 				tasks.GET("/:id", taskHandler.Get)
 				tasks.PUT("/:id", s.writeRateLimit(), taskHandler.Update)
-				tasks.POST("/:id/execute", s.writeRateLimit(), taskHandler.Execute)
+				tasks.POST("/:id/execute", s.writeRateLimit(), idempotent, taskHandler.Execute)
 				tasks.POST("/:id/continue", s.writeRateLimit(), taskHandler.Continue)
 				tasks.GET("/:id/executions", taskHandler.GetExecutions)
+				tasks.POST("/:id/schedule", s.writeRateLimit(), idempotent, taskHandler.Schedule)
+				tasks.GET("/:id/scheduled", taskHandler.ListScheduled)
+				tasks.DELETE("/scheduled/:id", s.writeRateLimit(), taskHandler.CancelScheduled)
 			}
 
 			// Browser sessions
@@ -259,6 +401,9 @@ func (s *ProductionServer) setupRoutes() {
 				browser.POST("/sessions/:id/action", s.writeRateLimit(), browserHandler.ExecuteAction)
 				browser.POST("/sessions/:id/continue", s.writeRateLimit(), browserHandler.ContinueTask)
 				browser.GET("/sessions/:id/screenshot", browserHandler.GetScreenshot)
+				browser.GET("/sessions/:id/recording", browserHandler.GetRecording)
+				browser.GET("/sessions/:id/recording/frames/:frame", browserHandler.GetRecordingFrame)
+				browser.GET("/sessions/:id/vnc", browserHandler.VNC)
 			}
 
 			// AI Content
@@ -271,9 +416,10 @@ func (s *ProductionServer) setupRoutes() {
 				content.PUT("/drafts/:id", s.writeRateLimit(), contentHandler.UpdateDraft)
 				content.DELETE("/drafts/:id", s.writeRateLimit(), contentHandler.DeleteDraft)
 				content.POST("/drafts/:id/approve", s.writeRateLimit(), contentHandler.ApproveDraft)
-				content.POST("/schedule", s.writeRateLimit(), contentHandler.Schedule)
+				content.POST("/schedule", s.writeRateLimit(), idempotent, contentHandler.Schedule)
 				content.GET("/scheduled", contentHandler.ListScheduled)
 				content.DELETE("/scheduled/:id", s.writeRateLimit(), contentHandler.CancelScheduled)
+				content.PUT("/scheduled/:id", s.writeRateLimit(), contentHandler.Reschedule)
 			}
 
 			// Automation jobs
@@ -281,6 +427,8 @@ func (s *ProductionServer) setupRoutes() {
 			{
 				jobHandler := handlers.NewJobHandler(s.services)
 				jobs.GET("", jobHandler.List)
+				jobs.GET("/dead-letter", jobHandler.ListDeadLetters)
+				jobs.POST("/dead-letter/:id/replay", s.writeRateLimit(), jobHandler.ReplayDeadLetter)
 				jobs.POST("", s.writeRateLimit(), jobHandler.Create)
 				jobs.GET("/:id", jobHandler.Get)
 				jobs.PUT("/:id", s.writeRateLimit(), jobHandler.Update)
@@ -300,8 +448,68 @@ func (s *ProductionServer) setupRoutes() {
 				proxies.DELETE("/:id", s.writeRateLimit(), proxyHandler.Delete)
 				proxies.POST("/:id/test", s.writeRateLimit(), proxyHandler.Test)
 				proxies.POST("/bulk", s.writeRateLimit(), proxyHandler.BulkCreate)
+				proxies.POST("/import", s.writeRateLimit(), idempotent, proxyHandler.Import)
 			}
 
+			// Proxy pools rotate accounts and browser profiles across their proxies
+			proxyPools := protected.Group("/proxy-pools")
+			{
+				proxyHandler := handlers.NewProxyHandler(s.services)
+				proxyPools.GET("", proxyHandler.ListPools)
+				proxyPools.POST("", s.writeRateLimit(), proxyHandler.CreatePool)
+				proxyPools.PUT("/:id", s.writeRateLimit(), proxyHandler.UpdatePool)
+				proxyPools.DELETE("/:id", s.writeRateLimit(), proxyHandler.DeletePool)
+			}
+
+			// RPC endpoints the caller's wallets prefer
+			rpcEndpoints := protected.Group("/rpc-endpoints")
+			{
+				rpcHandler := handlers.NewRPCEndpointHandler(s.services)
+				rpcEndpoints.GET("", rpcHandler.List)
+				rpcEndpoints.POST("", s.writeRateLimit(), rpcHandler.Create)
+				rpcEndpoints.PUT("/:id", s.writeRateLimit(), rpcHandler.Update)
+				rpcEndpoints.DELETE("/:id", s.writeRateLimit(), rpcHandler.Delete)
+			}
+
+			// Webhook notifications
+			hooks := protected.Group("/webhooks")
+			{
+				webhookHandler := handlers.NewWebhookHandler(s.services)
+				hooks.GET("", webhookHandler.List)
+				hooks.POST("", s.writeRateLimit(), webhookHandler.Create)
+				hooks.PUT("/:id", s.writeRateLimit(), webhookHandler.Update)
+				hooks.DELETE("/:id", s.writeRateLimit(), webhookHandler.Delete)
+				hooks.POST("/:id/test", s.writeRateLimit(), webhookHandler.Test)
+				hooks.GET("/:id/deliveries", webhookHandler.Deliveries)
+			}
+
+			// Admin: roles and shared settings
+			admin := protected.Group("/admin")
+			admin.Use(s.adminRequired())
+			{
+				// Roles; ADMIN_EMAILS names the first admins
+				admin.PUT("/users/:id/role", s.writeRateLimit(), s.setUserRole())
+
+				sharedRPCHandler := handlers.NewSharedRPCEndpointHandler(s.services)
+				admin.GET("/rpc-endpoints", sharedRPCHandler.List)
+				admin.POST("/rpc-endpoints", s.writeRateLimit(), sharedRPCHandler.Create)
+				admin.PUT("/rpc-endpoints/:id", s.writeRateLimit(), sharedRPCHandler.Update)
+				admin.DELETE("/rpc-endpoints/:id", s.writeRateLimit(), sharedRPCHandler.Delete)
+
+				// Org-wide vault key rotation
+				admin.POST("/vault/rotate", s.writeRateLimit(), s.rotateVaultKey())
+				admin.GET("/vault/rotations", s.listKeyRotations())
+				admin.GET("/vault/rotations/:id", s.getKeyRotation())
+			}
+
+			// Runtime profiles, for diagnosing leaks in a running pod
+			if s.container.Config.EnablePprof {
+				registerPprof(admin)
+			}
+
+			// Search across campaigns, tasks, accounts and wallets
+			protected.GET("/search", handlers.NewSearchHandler(s.services).Search)
+
 			// Dashboard stats
 			dashboard := protected.Group("/dashboard")
 			{
@@ -309,12 +517,15 @@ func (s *ProductionServer) setupRoutes() {
 				dashboard.GET("/stats", dashboardHandler.GetStats)
 				dashboard.GET("/activity", dashboardHandler.GetRecentActivity)
 				dashboard.GET("/campaigns/active", dashboardHandler.GetActiveCampaigns)
+				dashboard.GET("/rate-limits", dashboardHandler.GetRateLimits)
+				dashboard.GET("/circuits", dashboardHandler.GetCircuits)
 			}
 
 			// Audit logs
 			auditLogs := protected.Group("/audit")
 			{
 				auditLogs.GET("", s.getAuditLogs())
+				auditLogs.GET("/export", s.exportAuditLogs())
 				auditLogs.GET("/:id", s.getAuditLog())
 			}
 
@@ -326,17 +537,50 @@ func (s *ProductionServer) setupRoutes() {
 				secrets.GET("/:name", s.getSecret())
 				secrets.PUT("/:name", s.writeRateLimit(), s.updateSecret())
 				secrets.DELETE("/:name", s.writeRateLimit(), s.deleteSecret())
+				secrets.PUT("/:name/tags", s.writeRateLimit(), s.setSecretTags())
+				secrets.GET("/:name/versions", s.listSecretVersions())
+				secrets.POST("/:name/rollback", s.writeRateLimit(), s.rollbackSecret())
 			}
 		}
 	}
 
 	// WebSocket endpoint
-	s.router.GET("/ws", func(c *gin.Context) {
-		s.container.WSHub.HandleWebSocket(c.Writer, c.Request)
-	})
+	s.router.GET("/ws", s.handleWebSocket())
+
+	// OpenAPI spec and Swagger UI
+	registerAPIDocs(s.router)
+	checkAPIDocs(s.router.Routes())
+}
+
+// handleWebSocket authenticates the upgrade request before handing it to the hub
+func (s *ProductionServer) handleWebSocket() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, _ := websocket.TokenFromRequest(c.Request)
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Access token required",
+			})
+			return
+		}
+
+		claims, err := s.container.AuthService.ValidateAccessToken(token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or expired token",
+			})
+			return
+		}
+
+		s.container.WSHub.HandleWebSocket(c.Writer, c.Request, claims.UserID.String())
+	}
 }
 
 // Run starts the server
+// Close releases the services' connections once the server has stopped
+func (s *ProductionServer) Close() {
+	s.services.Close()
+}
+
 func (s *ProductionServer) Run(addr string) error {
 	return s.router.Run(addr)
 }
@@ -346,18 +590,24 @@ func (s *ProductionServer) getAuditLogs() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID, _ := auth.GetUserID(c)
 
-		logs, total, err := s.container.AuditLogger.Query(c.Request.Context(), &audit.QueryParams{
-			UserID: &userID,
-			Limit:  50,
-		})
+		params, err := auditQueryParams(c, userID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Query applies the default page size and the 1000 cap to params
+		logs, total, err := s.container.AuditLogger.Query(c.Request.Context(), params)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
 		c.JSON(http.StatusOK, gin.H{
-			"logs":  logs,
-			"total": total,
+			"logs":   logs,
+			"total":  total,
+			"limit":  params.Limit,
+			"offset": params.Offset,
 		})
 	}
 }
@@ -393,12 +643,122 @@ func (s *ProductionServer) getAuditLog() gin.HandlerFunc {
 	}
 }
 
+// exportAuditLogs streams the user's audit logs as CSV or NDJSON
+func (s *ProductionServer) exportAuditLogs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := auth.GetUserID(c)
+
+		format := audit.ExportFormat(c.DefaultQuery("format", string(audit.ExportCSV)))
+		contentType := "text/csv"
+		switch format {
+		case audit.ExportCSV:
+		case audit.ExportNDJSON:
+			contentType = "application/x-ndjson"
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or ndjson"})
+			return
+		}
+
+		params, err := auditQueryParams(c, userID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		filename := fmt.Sprintf("audit-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Status(http.StatusOK)
+
+		// Headers are already sent, so a mid-stream failure can only be logged
+		if _, err := s.container.AuditLogger.Export(c.Request.Context(), params, format, c.Writer); err != nil {
+			log.Printf("⚠️ Audit export for user %s failed: %v", userID, err)
+		}
+	}
+}
+
+// auditQueryParams builds audit filters from query parameters, scoped to the user
+func auditQueryParams(c *gin.Context, userID uuid.UUID) (*audit.QueryParams, error) {
+	params := &audit.QueryParams{UserID: &userID}
+
+	if v := c.Query("action"); v != "" {
+		action := audit.Action(v)
+		params.Action = &action
+	}
+	params.Platform = c.Query("platform")
+	params.RequestID = c.Query("request_id")
+	if v := c.Query("result"); v != "" {
+		result := audit.Result(v)
+		params.Result = &result
+	}
+	if v := c.Query("campaign_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid campaign_id")
+		}
+		params.CampaignID = &id
+	}
+	if v := c.Query("task_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid task_id")
+		}
+		params.TaskID = &id
+	}
+	if v := c.Query("start_time"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("start_time must be RFC3339")
+		}
+		params.StartTime = &t
+	}
+	if v := c.Query("end_time"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("end_time must be RFC3339")
+		}
+		params.EndTime = &t
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid limit")
+		}
+		params.Limit = limit
+	}
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("invalid offset")
+		}
+		params.Offset = offset
+	}
+
+	return params, nil
+}
+
 // Secrets vault handlers
+
+// vaultContext tags vault calls from the API (the vault writes the audit entries)
+func (s *ProductionServer) vaultContext(c *gin.Context) context.Context {
+	return vault.WithAccessContext(c.Request.Context(), vault.AccessContext{
+		Subsystem: "api",
+		IPAddress: c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+	})
+}
+
 func (s *ProductionServer) listSecrets() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID, _ := auth.GetUserID(c)
 
-		secrets, err := s.container.Vault.List(c.Request.Context(), userID)
+		// ?tag= may repeat or hold a comma-separated list; a secret must carry all of them
+		var tags []string
+		for _, tag := range c.QueryArray("tag") {
+			tags = append(tags, strings.Split(tag, ",")...)
+		}
+
+		secrets, err := s.container.Vault.Search(c.Request.Context(), userID, c.Query("q"), tags, vault.SecretType(c.Query("key_type")))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -417,26 +777,25 @@ func (s *ProductionServer) storeSecret() gin.HandlerFunc {
 			Value    string                 `json:"value" binding:"required"`
 			KeyType  vault.SecretType       `json:"key_type" binding:"required"`
 			Metadata map[string]interface{} `json:"metadata"`
+			Tags     []string               `json:"tags"`
 		}
 		if err := c.ShouldBindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		if len(req.Tags) > 0 {
+			if req.Metadata == nil {
+				req.Metadata = map[string]interface{}{}
+			}
+			req.Metadata["tags"] = req.Tags
+		}
 
-		secret, err := s.container.Vault.Store(c.Request.Context(), userID, req.Name, req.Value, req.KeyType, req.Metadata)
+		secret, err := s.container.Vault.Store(s.vaultContext(c), userID, req.Name, req.Value, req.KeyType, req.Metadata)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
-		// Log audit
-		s.container.AuditLogger.Log(c.Request.Context(), &audit.LogEntry{
-			UserID:   userID,
-			Action:   audit.ActionSecretCreate,
-			Result:   audit.ResultSuccess,
-			TargetID: secret.Name,
-		})
-
 		c.JSON(http.StatusCreated, gin.H{
 			"id":       secret.ID,
 			"name":     secret.Name,
@@ -450,7 +809,7 @@ func (s *ProductionServer) getSecret() gin.HandlerFunc {
 		userID, _ := auth.GetUserID(c)
 		name := c.Param("name")
 
-		value, err := s.container.Vault.Retrieve(c.Request.Context(), userID, name)
+		value, err := s.container.Vault.Retrieve(s.vaultContext(c), userID, name)
 		if err != nil {
 			if err == vault.ErrSecretNotFound {
 				c.JSON(http.StatusNotFound, gin.H{"error": "secret not found"})
@@ -460,14 +819,6 @@ func (s *ProductionServer) getSecret() gin.HandlerFunc {
 			return
 		}
 
-		// Log audit
-		s.container.AuditLogger.Log(c.Request.Context(), &audit.LogEntry{
-			UserID:   userID,
-			Action:   audit.ActionSecretAccess,
-			Result:   audit.ResultSuccess,
-			TargetID: name,
-		})
-
 		c.JSON(http.StatusOK, gin.H{"value": value})
 	}
 }
@@ -485,7 +836,7 @@ func (s *ProductionServer) updateSecret() gin.HandlerFunc {
 			return
 		}
 
-		if err := s.container.Vault.Update(c.Request.Context(), userID, name, req.Value); err != nil {
+		if err := s.container.Vault.Update(s.vaultContext(c), userID, name, req.Value); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
@@ -499,20 +850,217 @@ func (s *ProductionServer) deleteSecret() gin.HandlerFunc {
 		userID, _ := auth.GetUserID(c)
 		name := c.Param("name")
 
-		if err := s.container.Vault.Delete(c.Request.Context(), userID, name); err != nil {
+		if err := s.container.Vault.Delete(s.vaultContext(c), userID, name); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
-		// Log audit
-		s.container.AuditLogger.Log(c.Request.Context(), &audit.LogEntry{
-			UserID:   userID,
-			Action:   audit.ActionSecretDelete,
-			Result:   audit.ResultSuccess,
-			TargetID: name,
+		c.JSON(http.StatusOK, gin.H{"message": "secret deleted"})
+	}
+}
+
+func (s *ProductionServer) setSecretTags() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := auth.GetUserID(c)
+		name := c.Param("name")
+
+		var req struct {
+			Tags []string `json:"tags"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := s.container.Vault.SetTags(s.vaultContext(c), userID, name, req.Tags); err != nil {
+			if err == vault.ErrSecretNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "secret not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "secret tags updated"})
+	}
+}
+
+func (s *ProductionServer) listSecretVersions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := auth.GetUserID(c)
+		name := c.Param("name")
+
+		versions, err := s.container.Vault.ListVersions(c.Request.Context(), userID, name)
+		if err != nil {
+			if err == vault.ErrSecretNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "secret not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"versions": versions})
+	}
+}
+
+func (s *ProductionServer) rollbackSecret() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := auth.GetUserID(c)
+		name := c.Param("name")
+
+		var req struct {
+			Version int `json:"version" binding:"required,min=1"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := s.container.Vault.Rollback(s.vaultContext(c), userID, name, req.Version); err != nil {
+			if err == vault.ErrSecretNotFound || err == vault.ErrVersionNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "secret rolled back"})
+	}
+}
+
+// setUserRole makes a user an admin or a regular user
+func (s *ProductionServer) setUserRole() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID, _ := auth.GetUserID(c)
+		userID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+			return
+		}
+
+		var req struct {
+			Role string `json:"role" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		user, err := s.container.AuthService.SetRole(c.Request.Context(), adminID, userID, req.Role)
+		if err != nil {
+			switch {
+			case errors.Is(err, auth.ErrUserNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case errors.Is(err, auth.ErrInvalidRole):
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case errors.Is(err, auth.ErrLastAdmin):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, user)
+	}
+}
+
+// rotateVaultKey starts, or resumes, re-encrypting every user's secrets under a
+// new master key. The admin re-enters their password, and 2FA code when enabled.
+func (s *ProductionServer) rotateVaultKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := auth.GetUserID(c)
+
+		var req struct {
+			OldKey   string `json:"old_key" binding:"required"`
+			NewKey   string `json:"new_key" binding:"required,min=32"`
+			Password string `json:"password" binding:"required"`
+			Code     string `json:"code"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := s.services.Auth.ConfirmIdentity(userID, &services.IdentityConfirmation{
+			Password:  req.Password,
+			Code:      req.Code,
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		}); err != nil {
+			switch {
+			case errors.Is(err, auth.ErrAccountLocked):
+				c.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+			case errors.Is(err, auth.ErrInvalidCredentials), errors.Is(err, auth.ErrInvalidTwoFactorCode),
+				errors.Is(err, auth.ErrTwoFactorCodeRequired), errors.Is(err, auth.ErrTwoFactorNotEnrolled):
+				c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		ctx := vault.WithAccessContext(c.Request.Context(), vault.AccessContext{
+			Subsystem: "admin",
+			UserID:    userID,
+			IPAddress: c.ClientIP(),
+			UserAgent: c.GetHeader("User-Agent"),
 		})
+		job, err := s.container.Vault.StartRotateAll(ctx, vault.DeriveMasterKey(req.OldKey), vault.DeriveMasterKey(req.NewKey))
+		if err != nil {
+			switch {
+			case errors.Is(err, vault.ErrRotationInProgress):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			case errors.Is(err, vault.ErrRotationKeyMismatch), errors.Is(err, vault.ErrRotationSameKey):
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
 
-		c.JSON(http.StatusOK, gin.H{"message": "secret deleted"})
+		c.JSON(http.StatusAccepted, job)
+	}
+}
+
+func (s *ProductionServer) listKeyRotations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+		jobs, err := s.container.Vault.ListRotationJobs(c.Request.Context(), limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"jobs":                jobs,
+			"current_key_version": s.container.Vault.CurrentKeyVersion(),
+		})
+	}
+}
+
+func (s *ProductionServer) getKeyRotation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid job ID"})
+			return
+		}
+
+		job, err := s.container.Vault.RotationJob(c.Request.Context(), id)
+		if err != nil {
+			if err == vault.ErrRotationJobNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, job)
 	}
 }
 
@@ -577,6 +1125,29 @@ func (s *ProductionServer) healthCheck() gin.HandlerFunc {
 }
 
 // logout handles user logout by revoking all refresh tokens
+// currentUser returns the signed-in user's profile
+func (s *ProductionServer) currentUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		user, err := s.container.AuthService.GetCurrentUser(c.Request.Context(), userID)
+		if err != nil {
+			if errors.Is(err, auth.ErrUserNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, user)
+	}
+}
+
 func (s *ProductionServer) logout() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID, exists := auth.GetUserID(c)
@@ -603,3 +1174,199 @@ func (s *ProductionServer) logout() gin.HandlerFunc {
 		c.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
 	}
 }
+
+// enrollTOTP starts TOTP enrollment and returns the secret and otpauth URL
+func (s *ProductionServer) enrollTOTP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		enrollment, err := s.container.AuthService.EnrollTOTP(c.Request.Context(), userID)
+		if err != nil {
+			if errors.Is(err, auth.ErrTwoFactorAlreadyEnabled) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, enrollment)
+	}
+}
+
+// verifyTOTP confirms enrollment and returns one-time recovery codes
+func (s *ProductionServer) verifyTOTP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		var req struct {
+			Code string `json:"code" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		codes, err := s.container.AuthService.VerifyTOTP(c.Request.Context(), userID, req.Code)
+		if err != nil {
+			switch {
+			case errors.Is(err, auth.ErrInvalidTwoFactorCode):
+				c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			case errors.Is(err, auth.ErrTwoFactorNotEnrolled), errors.Is(err, auth.ErrTwoFactorAlreadyEnabled):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"enabled":        true,
+			"recovery_codes": codes,
+		})
+	}
+}
+
+// completeTwoFactorLogin exchanges a login challenge and code for tokens
+func (s *ProductionServer) completeTwoFactorLogin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req auth.TwoFactorLoginRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := s.container.AuthService.CompleteTwoFactorLogin(
+			c.Request.Context(), &req, c.ClientIP(), c.GetHeader("User-Agent"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"user":          result.User,
+			"access_token":  result.Tokens.AccessToken,
+			"refresh_token": result.Tokens.RefreshToken,
+			"expires_at":    result.Tokens.ExpiresAt,
+			"expires_in":    result.Tokens.ExpiresIn,
+		})
+	}
+}
+
+// requestPasswordReset issues a reset token; always succeeds to avoid leaking which emails exist
+func (s *ProductionServer) requestPasswordReset() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req auth.PasswordResetRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := s.container.AuthService.RequestPasswordReset(c.Request.Context(), req.Email, c.ClientIP()); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process password reset"})
+			return
+		}
+
+		c.JSON(http.StatusAccepted, gin.H{"message": "if the account exists, a reset link has been sent"})
+	}
+}
+
+// resetPassword sets a new password using a reset token
+func (s *ProductionServer) resetPassword() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req auth.ResetPasswordRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err := s.container.AuthService.ResetPassword(c.Request.Context(), req.Token, req.NewPassword, c.ClientIP())
+		if err != nil {
+			if errors.Is(err, auth.ErrInvalidResetToken) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reset password"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "password updated, please log in again"})
+	}
+}
+
+// listSessions returns the user's active sessions, flagging the one making the request
+func (s *ProductionServer) listSessions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		currentID, _ := auth.GetSessionID(c)
+
+		tokens, err := s.container.AuthService.GetActiveSessions(c.Request.Context(), userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		sessions := make([]gin.H, 0, len(tokens))
+		for _, t := range tokens {
+			sessions = append(sessions, gin.H{
+				"id":         t.ID,
+				"ip_address": t.IPAddress,
+				"user_agent": t.UserAgent,
+				"created_at": t.CreatedAt,
+				"expires_at": t.ExpiresAt,
+				"current":    t.ID == currentID,
+			})
+		}
+
+		c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+	}
+}
+
+// revokeSession signs out a single session (device)
+func (s *ProductionServer) revokeSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		sessionID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session id"})
+			return
+		}
+
+		if err := s.container.AuthService.LogoutSession(c.Request.Context(), userID, sessionID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		s.container.AuditLogger.Log(c.Request.Context(), &audit.LogEntry{
+			UserID:    userID,
+			Action:    audit.ActionLogout,
+			Result:    audit.ResultSuccess,
+			SessionID: &sessionID,
+			IPAddress: c.ClientIP(),
+			UserAgent: c.GetHeader("User-Agent"),
+		})
+
+		c.JSON(http.StatusOK, gin.H{"message": "session revoked"})
+	}
+}
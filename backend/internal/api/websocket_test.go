@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	gws "github.com/gorilla/websocket"
+
+	"github.com/web3airdropos/backend/internal/auth"
+	"github.com/web3airdropos/backend/internal/config"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+const wsTestSecret = "ws-test-secret"
+
+func accessToken(t *testing.T, secret string, tokenType auth.TokenType, userID uuid.UUID) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+		UserID:    userID,
+		TokenType: tokenType,
+		SessionID: uuid.New(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestProductionWebSocketRequiresAccessToken(t *testing.T) {
+	cfg := config.Load()
+	cfg.CORSAllowedOrigins = []string{"https://app.example.com"}
+	cfg.CORSOriginPattern = ""
+	redisClient := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	defer redisClient.Close()
+	hub := websocket.NewHub()
+	go hub.Run()
+	s := NewProductionServer(&ProductionContainer{
+		Config:      cfg,
+		AuthService: auth.NewAuthService(nil, wsTestSecret, 0, 0),
+		RateLimiter: auth.NewRateLimiter(redisClient),
+		WSHub:       hub,
+	})
+	server := httptest.NewServer(s.router)
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+	userID := uuid.New()
+	valid := accessToken(t, wsTestSecret, auth.TokenTypeAccess, userID)
+	rejected := map[string]struct {
+		url    string
+		header http.Header
+		status int
+	}{
+		"no token":      {url: wsURL, status: http.StatusUnauthorized},
+		"wrong secret":  {url: wsURL + "?token=" + accessToken(t, "other-secret", auth.TokenTypeAccess, userID), status: http.StatusUnauthorized},
+		"refresh token": {url: wsURL + "?token=" + accessToken(t, wsTestSecret, auth.TokenTypeRefresh, userID), status: http.StatusUnauthorized},
+		"foreign origin": {
+			url:    wsURL + "?token=" + valid,
+			header: http.Header{"Origin": []string{"https://evil.example"}},
+			status: http.StatusForbidden,
+		},
+	}
+	for name, tc := range rejected {
+		conn, resp, err := gws.DefaultDialer.Dial(tc.url, tc.header)
+		if err == nil {
+			conn.Close()
+			t.Errorf("%s: connection was accepted", name)
+			continue
+		}
+		if resp == nil || resp.StatusCode != tc.status {
+			t.Errorf("%s: got %v, want %d", name, resp, tc.status)
+		}
+	}
+
+	// A valid access token from an allowed origin reaches the user's hub client
+	conn, _, err := gws.DefaultDialer.Dial(wsURL+"?token="+valid, http.Header{"Origin": []string{"https://app.example.com"}})
+	if err != nil {
+		t.Fatalf("valid token: %v", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(hub.GetOnlineUsers()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	hub.BroadcastToUser(userID.String(), "ping", nil)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg websocket.Message
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("reading broadcast: %v", err)
+	}
+	if msg.Type != "ping" {
+		t.Errorf("got message %q, want ping", msg.Type)
+	}
+}
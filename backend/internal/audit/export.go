@@ -0,0 +1,150 @@
+package audit
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportFormat is the file format for audit exports
+type ExportFormat string
+
+const (
+	ExportCSV    ExportFormat = "csv"
+	ExportNDJSON ExportFormat = "ndjson"
+)
+
+// MaxExportRows is the hard ceiling on rows in a single export
+const MaxExportRows = 100000
+
+var csvHeader = []string{
+	"id", "created_at", "user_id", "action", "platform", "result",
+	"target_type", "target_id", "account_id", "wallet_id", "campaign_id",
+	"task_id", "execution_id", "job_id", "session_id", "error_code", "error_message",
+	"proof_type", "proof_value", "ip_address", "user_agent", "duration_ms",
+	"request_data", "response_data",
+}
+
+// Export streams matching audit logs to w as CSV or NDJSON, oldest first.
+// Rows are read with a cursor so large exports are never held in memory.
+// Limit defaults to and is capped at MaxExportRows; it returns the rows written.
+func (l *Logger) Export(ctx context.Context, params *QueryParams, format ExportFormat, w io.Writer) (int64, error) {
+	if format != ExportCSV && format != ExportNDJSON {
+		return 0, fmt.Errorf("unsupported export format: %s", format)
+	}
+
+	limit := params.Limit
+	if limit <= 0 || limit > MaxExportRows {
+		limit = MaxExportRows
+	}
+
+	rows, err := l.filterQuery(params).
+		WithContext(ctx).
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(params.Offset).
+		Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var csvWriter *csv.Writer
+	var encoder *json.Encoder
+	if format == ExportCSV {
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(csvHeader); err != nil {
+			return 0, err
+		}
+	} else {
+		encoder = json.NewEncoder(w)
+	}
+
+	var written int64
+	for rows.Next() {
+		var log AuditLog
+		if err := l.reader.ScanRows(rows, &log); err != nil {
+			return written, err
+		}
+
+		if csvWriter != nil {
+			err = csvWriter.Write(csvRecord(&log))
+			// Flush periodically so the response streams
+			if written%500 == 0 {
+				csvWriter.Flush()
+			}
+		} else {
+			err = encoder.Encode(&log)
+		}
+		if err != nil {
+			return written, err
+		}
+		written++
+	}
+
+	if csvWriter != nil {
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return written, err
+		}
+	}
+
+	return written, rows.Err()
+}
+
+func csvRecord(log *AuditLog) []string {
+	record := []string{
+		log.ID.String(),
+		log.CreatedAt.UTC().Format(time.RFC3339),
+		log.UserID.String(),
+		string(log.Action),
+		log.Platform,
+		string(log.Result),
+		log.TargetType,
+		log.TargetID,
+		optionalUUID(log.AccountID),
+		optionalUUID(log.WalletID),
+		optionalUUID(log.CampaignID),
+		optionalUUID(log.TaskID),
+		optionalUUID(log.ExecutionID),
+		optionalUUID(log.JobID),
+		optionalUUID(log.SessionID),
+		log.ErrorCode,
+		log.ErrorMessage,
+		log.ProofType,
+		log.ProofValue,
+		log.IPAddress,
+		log.UserAgent,
+		strconv.FormatInt(log.DurationMs, 10),
+		log.RequestData,
+		log.ResponseData,
+	}
+	for i := range record {
+		record[i] = csvCell(record[i])
+	}
+	return record
+}
+
+// csvCell quotes a value that a spreadsheet would otherwise run as a formula.
+// Target IDs, error messages and user agents come from outside, so an export
+// opened in Excel must not evaluate them.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func optionalUUID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
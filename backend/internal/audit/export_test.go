@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCSVRecordEscapesFormulas(t *testing.T) {
+	log := &AuditLog{
+		ID:           uuid.New(),
+		TargetID:     "=HYPERLINK(\"https://evil.example\")",
+		ErrorMessage: "-1+1",
+		UserAgent:    "@SUM(A1)",
+		ProofValue:   "+31",
+		IPAddress:    "10.0.0.1",
+		DurationMs:   -5,
+	}
+	record := csvRecord(log)
+	cell := func(name string) string {
+		for i, column := range csvHeader {
+			if column == name {
+				return record[i]
+			}
+		}
+		t.Fatalf("no %s column", name)
+		return ""
+	}
+
+	for column, want := range map[string]string{
+		"target_id":     "'=HYPERLINK(\"https://evil.example\")",
+		"error_message": "'-1+1",
+		"user_agent":    "'@SUM(A1)",
+		"proof_value":   "'+31",
+		"ip_address":    "10.0.0.1",
+		"duration_ms":   "'-5",
+		"platform":      "",
+	} {
+		if got := cell(column); got != want {
+			t.Errorf("%s = %q, want %q", column, got, want)
+		}
+	}
+}
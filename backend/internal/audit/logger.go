@@ -2,11 +2,12 @@ package audit
 
 import (
 	"context"
-	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/requestid"
 )
 
 // Action represents an auditable action
@@ -31,6 +32,7 @@ const (
 	ActionBridge        Action = "bridge"
 	ActionMint          Action = "mint"
 	ActionClaim         Action = "claim"
+	ActionSignature     Action = "signature"
 
 	// Content actions
 	ActionGenerate Action = "generate"
@@ -42,14 +44,24 @@ const (
 	// Account actions
 	ActionLogin        Action = "login"
 	ActionLogout       Action = "logout"
+	ActionAccountLocked Action = "account_locked"
 	ActionRegister     Action = "register"
 	ActionPasswordChange Action = "password_change"
+	ActionPasswordResetRequest Action = "password_reset_request"
+	ActionTwoFactorEnroll Action = "2fa_enroll"
+	ActionTwoFactorVerify Action = "2fa_verify"
+	ActionReauthenticate Action = "reauthenticate"
 	ActionAccountLink  Action = "account_link"
 	ActionWalletCreate Action = "wallet_create"
 	ActionWalletImport Action = "wallet_import"
 	ActionSecretAccess Action = "secret_access"
 	ActionSecretCreate Action = "secret_create"
+	ActionSecretUpdate Action = "secret_update"
 	ActionSecretDelete Action = "secret_delete"
+	ActionSecretRollback Action = "secret_rollback"
+	ActionKeyRotation  Action = "key_rotation"
+	ActionRoleChange   Action = "role_change"
+	ActionAdmin        Action = "admin_action" // Any change made through the /admin endpoints
 
 	// System actions
 	ActionTaskStart    Action = "task_start"
@@ -118,6 +130,9 @@ type AuditLog struct {
 	// Idempotency
 	IdempotencyKey string     `gorm:"size:200;uniqueIndex" json:"idempotency_key,omitempty"`
 	
+	// Ties the entry to the HTTP request and any jobs it started
+	RequestID      string     `gorm:"size:128;index" json:"request_id,omitempty"`
+	
 	CreatedAt      time.Time  `gorm:"index" json:"created_at"`
 }
 
@@ -155,23 +170,28 @@ type LogEntry struct {
 	UserAgent      string
 	
 	IdempotencyKey string
+	RequestID      string // Defaults to the request ID carried by ctx
 }
 
 // Logger handles audit logging
 type Logger struct {
 	db        *gorm.DB
+	reader    *gorm.DB // Queries and exports; db unless SetReader is called
 	batchSize int
 	batch     chan *AuditLog
 	stop      chan struct{}
+	redactor  *redactor
 }
 
 // NewLogger creates a new audit logger
 func NewLogger(db *gorm.DB) *Logger {
 	logger := &Logger{
 		db:        db,
+		reader:    db,
 		batchSize: 100,
 		batch:     make(chan *AuditLog, 1000),
 		stop:      make(chan struct{}),
+		redactor:  newRedactor(DefaultRedactedKeys),
 	}
 	
 	// Start background batch processor
@@ -180,6 +200,12 @@ func NewLogger(db *gorm.DB) *Logger {
 	return logger
 }
 
+// SetReader sends audit queries and exports to a read replica; writes and
+// cleanup stay on the primary
+func (l *Logger) SetReader(reader *gorm.DB) {
+	l.reader = reader
+}
+
 // Log creates an audit log entry
 func (l *Logger) Log(ctx context.Context, entry *LogEntry) (*AuditLog, error) {
 	log := &AuditLog{
@@ -206,23 +232,27 @@ func (l *Logger) Log(ctx context.Context, entry *LogEntry) (*AuditLog, error) {
 		UserAgent:      entry.UserAgent,
 		DurationMs:     entry.Duration.Milliseconds(),
 		IdempotencyKey: entry.IdempotencyKey,
+		RequestID:      entry.RequestID,
 		CreatedAt:      time.Now(),
 	}
+	if log.RequestID == "" {
+		log.RequestID = requestid.FromContext(ctx)
+	}
 
-	// Serialize JSON fields
+	// Serialize JSON fields, masking secrets before they hit the database
 	if entry.ProofData != nil {
-		if data, err := json.Marshal(entry.ProofData); err == nil {
-			log.ProofData = string(data)
+		if data, err := l.redactor.marshal(entry.ProofData); err == nil {
+			log.ProofData = data
 		}
 	}
 	if entry.RequestData != nil {
-		if data, err := json.Marshal(entry.RequestData); err == nil {
-			log.RequestData = string(data)
+		if data, err := l.redactor.marshal(entry.RequestData); err == nil {
+			log.RequestData = data
 		}
 	}
 	if entry.ResponseData != nil {
-		if data, err := json.Marshal(entry.ResponseData); err == nil {
-			log.ResponseData = string(data)
+		if data, err := l.redactor.marshal(entry.ResponseData); err == nil {
+			log.ResponseData = data
 		}
 	}
 
@@ -265,23 +295,27 @@ func (l *Logger) LogSync(ctx context.Context, entry *LogEntry) (*AuditLog, error
 		UserAgent:      entry.UserAgent,
 		DurationMs:     entry.Duration.Milliseconds(),
 		IdempotencyKey: entry.IdempotencyKey,
+		RequestID:      entry.RequestID,
 		CreatedAt:      time.Now(),
 	}
+	if log.RequestID == "" {
+		log.RequestID = requestid.FromContext(ctx)
+	}
 
-	// Serialize JSON fields
+	// Serialize JSON fields, masking secrets before they hit the database
 	if entry.ProofData != nil {
-		if data, err := json.Marshal(entry.ProofData); err == nil {
-			log.ProofData = string(data)
+		if data, err := l.redactor.marshal(entry.ProofData); err == nil {
+			log.ProofData = data
 		}
 	}
 	if entry.RequestData != nil {
-		if data, err := json.Marshal(entry.RequestData); err == nil {
-			log.RequestData = string(data)
+		if data, err := l.redactor.marshal(entry.RequestData); err == nil {
+			log.RequestData = data
 		}
 	}
 	if entry.ResponseData != nil {
-		if data, err := json.Marshal(entry.ResponseData); err == nil {
-			log.ResponseData = string(data)
+		if data, err := l.redactor.marshal(entry.ResponseData); err == nil {
+			log.ResponseData = data
 		}
 	}
 
@@ -366,6 +400,7 @@ type QueryParams struct {
 	Result     *Result
 	CampaignID *uuid.UUID
 	TaskID     *uuid.UUID
+	RequestID  string
 	StartTime  *time.Time
 	EndTime    *time.Time
 	Limit      int
@@ -377,7 +412,34 @@ func (l *Logger) Query(ctx context.Context, params *QueryParams) ([]AuditLog, in
 	var logs []AuditLog
 	var total int64
 
-	query := l.db.Model(&AuditLog{})
+	query := l.filterQuery(params)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination
+	if params.Limit <= 0 {
+		params.Limit = 50
+	}
+	if params.Limit > 1000 {
+		params.Limit = 1000
+	}
+
+	if err := query.Order("created_at DESC").
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
+
+// filterQuery applies QueryParams filters (everything except pagination)
+func (l *Logger) filterQuery(params *QueryParams) *gorm.DB {
+	query := l.reader.Model(&AuditLog{})
 
 	if params.UserID != nil {
 		query = query.Where("user_id = ?", *params.UserID)
@@ -403,6 +465,9 @@ func (l *Logger) Query(ctx context.Context, params *QueryParams) ([]AuditLog, in
 	if params.TaskID != nil {
 		query = query.Where("task_id = ?", *params.TaskID)
 	}
+	if params.RequestID != "" {
+		query = query.Where("request_id = ?", params.RequestID)
+	}
 	if params.StartTime != nil {
 		query = query.Where("created_at >= ?", *params.StartTime)
 	}
@@ -410,33 +475,13 @@ func (l *Logger) Query(ctx context.Context, params *QueryParams) ([]AuditLog, in
 		query = query.Where("created_at <= ?", *params.EndTime)
 	}
 
-	// Count total
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	// Apply pagination
-	if params.Limit <= 0 {
-		params.Limit = 50
-	}
-	if params.Limit > 1000 {
-		params.Limit = 1000
-	}
-
-	if err := query.Order("created_at DESC").
-		Limit(params.Limit).
-		Offset(params.Offset).
-		Find(&logs).Error; err != nil {
-		return nil, 0, err
-	}
-
-	return logs, total, nil
+	return query
 }
 
 // GetByID retrieves an audit log by ID
 func (l *Logger) GetByID(ctx context.Context, id uuid.UUID) (*AuditLog, error) {
 	var log AuditLog
-	if err := l.db.First(&log, id).Error; err != nil {
+	if err := l.reader.First(&log, id).Error; err != nil {
 		return nil, err
 	}
 	return &log, nil
@@ -451,7 +496,7 @@ func (l *Logger) GetUserActivity(ctx context.Context, userID uuid.UUID, days int
 		Count  int64
 	}
 	
-	if err := l.db.Model(&AuditLog{}).
+	if err := l.reader.Model(&AuditLog{}).
 		Select("action, count(*) as count").
 		Where("user_id = ? AND created_at >= ?", userID, since).
 		Group("action").
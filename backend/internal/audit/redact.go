@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// RedactedValue replaces sensitive values in stored request/response data
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactedKeys are JSON keys whose values never reach the audit table.
+// A key matches when it equals an entry or ends with "_<entry>" (so "new_password"
+// and "twitter_access_token" are covered), compared case-insensitively.
+var DefaultRedactedKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"private_key",
+	"privatekey",
+	"mnemonic",
+	"seed_phrase",
+	"access_token",
+	"refresh_token",
+	"token",
+	"api_key",
+	"apikey",
+	"signer_uuid",
+	"authorization",
+	"cookie",
+	"totp_code",
+	"recovery_code",
+}
+
+// redactor masks deny-listed keys in JSON-serializable data
+type redactor struct {
+	mu   sync.RWMutex
+	keys map[string]struct{}
+}
+
+func newRedactor(keys []string) *redactor {
+	r := &redactor{keys: make(map[string]struct{}, len(keys))}
+	r.add(keys...)
+	return r
+}
+
+func (r *redactor) add(keys ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range keys {
+		if key = normalizeKey(key); key != "" {
+			r.keys[key] = struct{}{}
+		}
+	}
+}
+
+func (r *redactor) matches(key string) bool {
+	key = normalizeKey(key)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.keys[key]; ok {
+		return true
+	}
+	for denied := range r.keys {
+		if strings.HasSuffix(key, "_"+denied) {
+			return true
+		}
+	}
+	return false
+}
+
+// marshal serializes v to JSON with sensitive keys masked
+func (r *redactor) marshal(v interface{}) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	// Round-trip through a generic value so structs are walked like maps
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return "", err
+	}
+
+	redacted, err := json.Marshal(r.walk(generic))
+	if err != nil {
+		return "", err
+	}
+	return string(redacted), nil
+}
+
+func (r *redactor) walk(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if r.matches(key) {
+				value[key] = RedactedValue
+				continue
+			}
+			value[key] = r.walk(child)
+		}
+		return value
+	case []interface{}:
+		for i, child := range value {
+			value[i] = r.walk(child)
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+func normalizeKey(key string) string {
+	key = strings.ToLower(strings.TrimSpace(key))
+	return strings.ReplaceAll(key, "-", "_")
+}
+
+// AddRedactedKeys extends the deny-list of keys masked before storage
+func (l *Logger) AddRedactedKeys(keys ...string) {
+	l.redactor.add(keys...)
+}
@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// testLogger queues entries without a database or the batch goroutine
+func testLogger() *Logger {
+	return &Logger{
+		batch:    make(chan *AuditLog, 10),
+		stop:     make(chan struct{}),
+		redactor: newRedactor(DefaultRedactedKeys),
+	}
+}
+
+func TestLoginPasswordIsNeverStored(t *testing.T) {
+	const password = "correct horse battery staple"
+	login := struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}{Email: "user@example.com", Password: password}
+
+	l := testLogger()
+	entry, err := l.Log(context.Background(), &LogEntry{
+		Action:       "auth.login",
+		RequestData:  login,
+		ResponseData: map[string]interface{}{"access_token": "eyJhbGciOi", "user": map[string]interface{}{"email": login.Email}},
+		ProofData:    map[string]interface{}{"attempts": []interface{}{map[string]interface{}{"Password": password}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored := <-l.batch
+	for name, data := range map[string]string{
+		"request":  stored.RequestData,
+		"response": stored.ResponseData,
+		"proof":    stored.ProofData,
+	} {
+		if strings.Contains(data, password) || strings.Contains(data, "eyJhbGciOi") {
+			t.Errorf("%s data stores a secret: %s", name, data)
+		}
+	}
+	if !strings.Contains(entry.RequestData, `"password":"`+RedactedValue+`"`) || !strings.Contains(entry.RequestData, login.Email) {
+		t.Errorf("request data = %s, want the email kept and the password redacted", entry.RequestData)
+	}
+}
+
+func TestRedactorMatchesKeys(t *testing.T) {
+	r := newRedactor(DefaultRedactedKeys)
+	r.add("X-Custom-Secret-Header")
+	cases := map[string]bool{
+		"password":               true,
+		"Password":               true,
+		"new_password":           true,
+		"twitter-access-token":   true,
+		"x_custom_secret_header": true,
+		"email":                  false,
+		"passwordless":           false,
+		"token_count":            false,
+	}
+	for key, want := range cases {
+		if got := r.matches(key); got != want {
+			t.Errorf("matches(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
@@ -14,7 +14,9 @@ import (
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 
+	"github.com/web3airdropos/backend/internal/audit"
 	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/vault"
 )
 
 // Common errors
@@ -25,6 +27,7 @@ var (
 	ErrInvalidToken           = errors.New("invalid or expired token")
 	ErrTokenRevoked           = errors.New("token has been revoked")
 	ErrTokenFamilyCompromised = errors.New("token family compromised - all sessions revoked")
+	ErrAccountLocked          = errors.New("account temporarily locked due to too many failed login attempts")
 )
 
 // TokenType represents the type of JWT token
@@ -33,6 +36,8 @@ type TokenType string
 const (
 	TokenTypeAccess  TokenType = "access"
 	TokenTypeRefresh TokenType = "refresh"
+	// TokenTypeTwoFactor is a short-lived challenge issued when login needs a second factor
+	TokenTypeTwoFactor TokenType = "2fa_challenge"
 )
 
 // Claims represents JWT claims for access tokens
@@ -71,6 +76,7 @@ type TokenPair struct {
 	AccessToken  string    `json:"access_token"`
 	RefreshToken string    `json:"refresh_token"`
 	ExpiresAt    time.Time `json:"expires_at"`
+	ExpiresIn    int64     `json:"expires_in"` // Access token lifetime in seconds
 	TokenType    string    `json:"token_type"`
 }
 
@@ -80,18 +86,40 @@ type AuthService struct {
 	jwtSecret            []byte
 	accessTokenDuration  time.Duration
 	refreshTokenDuration time.Duration
+	vault                *vault.Vault
+	auditLogger          *audit.Logger
+	lockout              *accountLockout
+	resetNotifier        PasswordResetNotifier
 }
 
+// Default token lifetimes, used when NewAuthService is given zero durations
+const (
+	DefaultAccessTokenTTL  = 15 * time.Minute   // Short-lived access tokens
+	DefaultRefreshTokenTTL = 7 * 24 * time.Hour // 7-day refresh tokens
+)
+
 // NewAuthService creates a new auth service
-func NewAuthService(db *gorm.DB, jwtSecret string) *AuthService {
+func NewAuthService(db *gorm.DB, jwtSecret string, accessTokenTTL, refreshTokenTTL time.Duration) *AuthService {
+	if accessTokenTTL <= 0 {
+		accessTokenTTL = DefaultAccessTokenTTL
+	}
+	if refreshTokenTTL <= 0 {
+		refreshTokenTTL = DefaultRefreshTokenTTL
+	}
+
 	return &AuthService{
 		db:                   db,
 		jwtSecret:            []byte(jwtSecret),
-		accessTokenDuration:  15 * time.Minute,   // Short-lived access tokens
-		refreshTokenDuration: 7 * 24 * time.Hour, // 7-day refresh tokens
+		accessTokenDuration:  accessTokenTTL,
+		refreshTokenDuration: refreshTokenTTL,
 	}
 }
 
+// AccessTokenTTL returns how long issued access tokens stay valid
+func (s *AuthService) AccessTokenTTL() time.Duration {
+	return s.accessTokenDuration
+}
+
 // RegisterRequest represents a registration request
 type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email"`
@@ -109,6 +137,11 @@ type LoginRequest struct {
 type AuthResponse struct {
 	User   *models.User `json:"user"`
 	Tokens TokenPair    `json:"tokens"`
+
+	// Set instead of Tokens when the user has 2FA enabled
+	TwoFactorRequired  bool       `json:"2fa_required,omitempty"`
+	Challenge          string     `json:"challenge,omitempty"`
+	ChallengeExpiresAt *time.Time `json:"challenge_expires_at,omitempty"`
 }
 
 // Register creates a new user account
@@ -132,6 +165,7 @@ func (s *AuthService) Register(ctx context.Context, req *RegisterRequest, ipAddr
 		PasswordHash: string(hashedPassword),
 		Name:         req.Name,
 		IsActive:     true,
+		Role:         models.RoleUser,
 	}
 
 	if err := s.db.Create(user).Error; err != nil {
@@ -155,8 +189,13 @@ func (s *AuthService) Register(ctx context.Context, req *RegisterRequest, ipAddr
 
 // Login authenticates a user
 func (s *AuthService) Login(ctx context.Context, req *LoginRequest, ipAddress, userAgent string) (*AuthResponse, error) {
+	if err := s.checkAccountLocked(ctx, req.Email); err != nil {
+		return nil, err
+	}
+
 	var user models.User
 	if err := s.db.Where("email = ?", req.Email).First(&user).Error; err != nil {
+		s.recordFailedLogin(ctx, req.Email, nil, ipAddress, userAgent)
 		return nil, ErrInvalidCredentials
 	}
 
@@ -166,9 +205,28 @@ func (s *AuthService) Login(ctx context.Context, req *LoginRequest, ipAddress, u
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		s.recordFailedLogin(ctx, req.Email, &user, ipAddress, userAgent)
 		return nil, ErrInvalidCredentials
 	}
 
+	// Tokens are only issued once the second factor is verified, which is also
+	// when the failed attempt count is cleared
+	if user.TOTPEnabled {
+		challenge, expiresAt, err := s.twoFactorChallenge(&user)
+		if err != nil {
+			return nil, err
+		}
+		user.PasswordHash = ""
+		return &AuthResponse{
+			User:               &user,
+			TwoFactorRequired:  true,
+			Challenge:          challenge,
+			ChallengeExpiresAt: &expiresAt,
+		}, nil
+	}
+
+	s.resetFailedLogins(ctx, req.Email)
+
 	// Update last login
 	now := time.Now()
 	s.db.Model(&user).Update("last_login_at", now)
@@ -363,6 +421,7 @@ func (s *AuthService) generateTokenPairWithFamily(ctx context.Context, user *mod
 		AccessToken:  accessTokenString,
 		RefreshToken: refreshTokenString,
 		ExpiresAt:    accessExpiry,
+		ExpiresIn:    int64(s.accessTokenDuration.Seconds()),
 		TokenType:    "Bearer",
 	}, storedToken, nil
 }
@@ -377,8 +436,53 @@ func (s *AuthService) revokeTokenFamily(ctx context.Context, familyID uuid.UUID)
 
 // CleanupExpiredTokens removes expired tokens
 func (s *AuthService) CleanupExpiredTokens(ctx context.Context) (int64, error) {
-	result := s.db.Where("expires_at < ?", time.Now()).Delete(&RefreshToken{})
-	return result.RowsAffected, result.Error
+	now := time.Now()
+	result := s.db.Where("expires_at < ?", now).Delete(&RefreshToken{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	// Reset tokens are useless once expired or used
+	resets := s.db.Where("expires_at < ? OR used_at IS NOT NULL", now).Delete(&PasswordResetToken{})
+	if resets.Error != nil {
+		return result.RowsAffected, resets.Error
+	}
+
+	// So are two-factor challenges
+	challenges := s.db.Where("expires_at < ? OR used_at IS NOT NULL", now).Delete(&TwoFactorChallenge{})
+	return result.RowsAffected + resets.RowsAffected + challenges.RowsAffected, challenges.Error
+}
+
+// CurrentUser is the signed-in user's profile with the flags the frontend
+// checks, under the names it uses
+type CurrentUser struct {
+	*models.User
+	TwoFactorEnabled bool `json:"2fa_enabled"`
+	EmailVerified    bool `json:"email_verified"`
+}
+
+// GetCurrentUser returns the profile of an active user
+func (s *AuthService) GetCurrentUser(ctx context.Context, userID uuid.UUID) (*CurrentUser, error) {
+	var user models.User
+	if err := s.db.WithContext(ctx).First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrUserNotFound
+	}
+
+	// Clear sensitive data
+	user.PasswordHash = ""
+	user.OpenAIKey = ""
+
+	return &CurrentUser{
+		User:             &user,
+		TwoFactorEnabled: user.TOTPEnabled,
+		EmailVerified:    user.EmailVerifiedAt != nil,
+	}, nil
 }
 
 // GetActiveSessions returns active sessions for a user
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/audit"
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+// LockoutConfig controls account lockout after repeated failed logins
+type LockoutConfig struct {
+	MaxAttempts  int           // Consecutive failures before the account is locked
+	Window       time.Duration // How long failed attempts are remembered
+	BaseDuration time.Duration // First lockout duration, doubled on each repeat lockout
+	MaxDuration  time.Duration // Upper bound for the lockout duration
+}
+
+// DefaultLockoutConfig returns the default lockout policy
+func DefaultLockoutConfig() LockoutConfig {
+	return LockoutConfig{
+		MaxAttempts:  5,
+		Window:       15 * time.Minute,
+		BaseDuration: 5 * time.Minute,
+		MaxDuration:  24 * time.Hour,
+	}
+}
+
+// LockoutNotifier is called when an account gets locked so the real user can be told
+type LockoutNotifier func(ctx context.Context, userID uuid.UUID, lockedUntil time.Time)
+
+// accountLockout tracks failed logins per email in Redis
+type accountLockout struct {
+	redis    *redis.Client
+	config   LockoutConfig
+	notifier LockoutNotifier
+}
+
+// SetAccountLockout enables per-account lockout backed by Redis
+func (s *AuthService) SetAccountLockout(client *redis.Client, config LockoutConfig) {
+	defaults := DefaultLockoutConfig()
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.Window <= 0 {
+		config.Window = defaults.Window
+	}
+	if config.BaseDuration <= 0 {
+		config.BaseDuration = defaults.BaseDuration
+	}
+	if config.MaxDuration < config.BaseDuration {
+		config.MaxDuration = config.BaseDuration
+	}
+
+	var notifier LockoutNotifier
+	if s.lockout != nil {
+		notifier = s.lockout.notifier
+	}
+	s.lockout = &accountLockout{redis: client, config: config, notifier: notifier}
+}
+
+// SetLockoutNotifier sets the callback invoked when an account is locked
+func (s *AuthService) SetLockoutNotifier(notifier LockoutNotifier) {
+	if s.lockout == nil {
+		return
+	}
+	s.lockout.notifier = notifier
+}
+
+func lockoutKey(kind, email string) string {
+	return fmt.Sprintf("auth:lockout:%s:%s", kind, strings.ToLower(strings.TrimSpace(email)))
+}
+
+// checkAccountLocked returns ErrAccountLocked while a lockout is active
+func (s *AuthService) checkAccountLocked(ctx context.Context, email string) error {
+	if s.lockout == nil || s.lockout.redis == nil {
+		return nil
+	}
+
+	ttl, err := s.lockout.redis.TTL(ctx, lockoutKey("locked", email)).Result()
+	if err != nil {
+		// Fail open - the IP rate limiter still applies
+		return nil
+	}
+	if ttl > 0 {
+		return ErrAccountLocked
+	}
+	return nil
+}
+
+// recordFailedLogin counts a failed attempt and locks the account once the threshold is hit.
+// user is nil when the email does not belong to an account.
+func (s *AuthService) recordFailedLogin(ctx context.Context, email string, user *models.User, ipAddress, userAgent string) {
+	if s.lockout == nil || s.lockout.redis == nil {
+		return
+	}
+	client := s.lockout.redis
+	config := s.lockout.config
+
+	failedKey := lockoutKey("failed", email)
+	attempts, err := client.Incr(ctx, failedKey).Result()
+	if err != nil {
+		return
+	}
+	if attempts == 1 {
+		client.Expire(ctx, failedKey, config.Window)
+	}
+	if attempts < int64(config.MaxAttempts) {
+		return
+	}
+
+	// Each repeat lockout doubles the duration, remembered for MaxDuration
+	countKey := lockoutKey("count", email)
+	lockouts, err := client.Incr(ctx, countKey).Result()
+	if err != nil {
+		lockouts = 1
+	}
+	client.Expire(ctx, countKey, config.MaxDuration)
+
+	duration := config.BaseDuration
+	for i := int64(1); i < lockouts && duration < config.MaxDuration; i++ {
+		duration *= 2
+	}
+	if duration > config.MaxDuration {
+		duration = config.MaxDuration
+	}
+
+	lockedUntil := time.Now().Add(duration)
+	client.Set(ctx, lockoutKey("locked", email), lockedUntil.Unix(), duration)
+	client.Del(ctx, failedKey)
+
+	if user == nil {
+		return
+	}
+
+	if s.auditLogger != nil {
+		s.auditLogger.Log(ctx, &audit.LogEntry{
+			UserID:       user.ID,
+			Action:       audit.ActionAccountLocked,
+			Result:       audit.ResultFailed,
+			IPAddress:    ipAddress,
+			UserAgent:    userAgent,
+			ErrorMessage: fmt.Sprintf("locked for %s after %d failed login attempts", duration, attempts),
+		})
+	}
+
+	if s.lockout.notifier != nil {
+		s.lockout.notifier(ctx, user.ID, lockedUntil)
+	}
+}
+
+// resetFailedLogins clears the failed attempt counter after a successful login
+func (s *AuthService) resetFailedLogins(ctx context.Context, email string) {
+	if s.lockout == nil || s.lockout.redis == nil {
+		return
+	}
+	s.lockout.redis.Del(ctx, lockoutKey("failed", email), lockoutKey("count", email))
+}
@@ -6,12 +6,21 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	gorillaws "github.com/gorilla/websocket"
+
+	"github.com/web3airdropos/backend/internal/websocket"
 )
 
 // AuthMiddleware returns a Gin middleware for JWT authentication
 func AuthMiddleware(authService *AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" && gorillaws.IsWebSocketUpgrade(c.Request) {
+			// Browsers can't set headers on WebSocket upgrades
+			if token, _ := websocket.TokenFromRequest(c.Request); token != "" {
+				authHeader = "Bearer " + token
+			}
+		}
 		if authHeader == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"error": "Authorization header required",
@@ -62,12 +71,9 @@ func RateLimitMiddleware(limiter *RateLimiter, config RateLimitConfig) gin.Handl
 			return
 		}
 
-		// Set rate limit headers
-		c.Header("X-RateLimit-Limit", string(rune(result.Limit)))
-		c.Header("X-RateLimit-Remaining", string(rune(result.Remaining)))
+		limiter.SetRateLimitHeaders(c.Writer, result)
 
 		if !result.Allowed {
-			c.Header("Retry-After", string(rune(int(result.RetryAfter.Seconds()))))
 			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
 				"error":       "Rate limit exceeded",
 				"retry_after": result.RetryAfter.Seconds(),
@@ -97,6 +103,8 @@ func UserRateLimitMiddleware(limiter *RateLimiter, config RateLimitConfig) gin.H
 			return
 		}
 
+		limiter.SetRateLimitHeaders(c.Writer, result)
+
 		if !result.Allowed {
 			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
 				"error":       "Rate limit exceeded",
@@ -136,20 +144,36 @@ func GetClaims(c *gin.Context) (*Claims, bool) {
 	return claims.(*Claims), true
 }
 
-// RequireRole middleware enforces role-based access control
-// Note: Role checking will be implemented when role system is added
-func RequireRole(roles ...string) gin.HandlerFunc {
+// RequireRole middleware enforces role-based access control. It must run after
+// AuthMiddleware. The role is loaded from the database, not the access token,
+// so a role change applies to tokens already issued.
+func RequireRole(authService *AuthService, roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Verify user is authenticated
-		_, exists := c.Get("user_id")
+		userID, exists := GetUserID(c)
 		if !exists {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"error": "Authentication required",
 			})
 			return
 		}
-		// TODO: Add role checking when role system is implemented
-		c.Next()
+
+		role, err := authService.CurrentRole(c.Request.Context(), userID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Insufficient permissions",
+			})
+			return
+		}
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error": "Insufficient permissions",
+		})
 	}
 }
 
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/audit"
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+// ErrInvalidResetToken is returned for unknown, used or expired reset tokens
+var ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+
+const passwordResetTTL = time.Hour
+
+// PasswordResetToken stores the hash of a single-use password reset token
+type PasswordResetToken struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	TokenHash string    `gorm:"size:64;uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+	IPAddress string `gorm:"size:50"`
+}
+
+// PasswordResetNotifier delivers the plaintext reset token to the user (e.g. by email)
+type PasswordResetNotifier func(ctx context.Context, user *models.User, token string, expiresAt time.Time)
+
+// PasswordResetRequest starts the password reset flow
+type PasswordResetRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest completes the password reset flow
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
+// SetPasswordResetNotifier sets the callback used to deliver reset tokens
+func (s *AuthService) SetPasswordResetNotifier(notifier PasswordResetNotifier) {
+	s.resetNotifier = notifier
+}
+
+// RequestPasswordReset issues a reset token for the account, if it exists.
+// Unknown emails succeed silently so the endpoint can't be used to enumerate users.
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email, ipAddress string) error {
+	var user models.User
+	if err := s.db.Where("email = ?", email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !user.IsActive {
+		return nil
+	}
+
+	token, err := generateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(passwordResetTTL)
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Only the most recent token stays valid
+		if err := tx.Model(&PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", user.ID).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&PasswordResetToken{
+			ID:        uuid.New(),
+			UserID:    user.ID,
+			TokenHash: hashToken(token),
+			ExpiresAt: expiresAt,
+			CreatedAt: now,
+			IPAddress: ipAddress,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.auditLogger != nil {
+		s.auditLogger.Log(ctx, &audit.LogEntry{
+			UserID:    user.ID,
+			Action:    audit.ActionPasswordResetRequest,
+			Result:    audit.ResultSuccess,
+			IPAddress: ipAddress,
+		})
+	}
+
+	if s.resetNotifier != nil {
+		s.resetNotifier(ctx, &user, token, expiresAt)
+	}
+
+	return nil
+}
+
+// ResetPassword validates a reset token, sets the new password and revokes all sessions
+func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword, ipAddress string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	var resetToken PasswordResetToken
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("token_hash = ?", hashToken(token)).First(&resetToken).Error; err != nil {
+			return ErrInvalidResetToken
+		}
+
+		now := time.Now()
+		if resetToken.UsedAt != nil || now.After(resetToken.ExpiresAt) {
+			return ErrInvalidResetToken
+		}
+
+		// Conditional update so concurrent requests can't both use the token
+		result := tx.Model(&PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", resetToken.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidResetToken
+		}
+
+		return tx.Model(&models.User{}).
+			Where("id = ?", resetToken.UserID).
+			Update("password_hash", string(hashedPassword)).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// Sign out everywhere - whoever knew the old password loses access
+	if err := s.Logout(ctx, resetToken.UserID); err != nil {
+		return err
+	}
+
+	if s.auditLogger != nil {
+		s.auditLogger.Log(ctx, &audit.LogEntry{
+			UserID:    resetToken.UserID,
+			Action:    audit.ActionPasswordChange,
+			Result:    audit.ResultSuccess,
+			IPAddress: ipAddress,
+		})
+	}
+
+	return nil
+}
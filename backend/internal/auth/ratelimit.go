@@ -12,6 +12,7 @@ import (
 
 // RateLimitConfig defines rate limit parameters
 type RateLimitConfig struct {
+	Name     string        // Tier name; each tier keeps its own count
 	Requests int           // Maximum requests
 	Window   time.Duration // Time window
 	BurstSize int          // Additional burst capacity
@@ -20,10 +21,10 @@ type RateLimitConfig struct {
 // Default rate limit configurations
 var (
 	// API rate limits
-	RateLimitDefault = RateLimitConfig{Requests: 100, Window: time.Minute, BurstSize: 20}
-	RateLimitAuth    = RateLimitConfig{Requests: 5, Window: time.Minute, BurstSize: 0}      // Strict for auth
-	RateLimitWrite   = RateLimitConfig{Requests: 30, Window: time.Minute, BurstSize: 5}
-	RateLimitRead    = RateLimitConfig{Requests: 200, Window: time.Minute, BurstSize: 50}
+	RateLimitDefault = RateLimitConfig{Name: "default", Requests: 100, Window: time.Minute, BurstSize: 20}
+	RateLimitAuth    = RateLimitConfig{Name: "auth", Requests: 5, Window: time.Minute, BurstSize: 0}      // Strict for auth
+	RateLimitWrite   = RateLimitConfig{Name: "write", Requests: 30, Window: time.Minute, BurstSize: 5}
+	RateLimitRead    = RateLimitConfig{Name: "read", Requests: 200, Window: time.Minute, BurstSize: 50}
 
 	// Platform-specific rate limits
 	PlatformRateLimits = map[string]RateLimitConfig{
@@ -85,16 +86,19 @@ func (r *RateLimiter) Check(ctx context.Context, identifier string, config RateL
 			-- Add the new request
 			redis.call('ZADD', key, now, now .. '-' .. math.random(100000))
 			redis.call('PEXPIRE', key, window_ms)
-			return {1, total_allowed - current_count - 1, 0}
-		else
-			-- Rate limited - find oldest entry to calculate retry time
-			local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
-			local retry_after = 0
-			if #oldest >= 2 then
-				retry_after = tonumber(oldest[2]) + window_ms - now
-			end
-			return {0, 0, retry_after}
 		end
+
+		-- The window frees up a slot when its oldest entry expires
+		local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+		local reset_after = window_ms
+		if #oldest >= 2 then
+			reset_after = tonumber(oldest[2]) + window_ms - now
+		end
+
+		if current_count < total_allowed then
+			return {1, total_allowed - current_count - 1, 0, reset_after}
+		end
+		return {0, 0, reset_after, reset_after}
 	`)
 
 	result, err := script.Run(ctx, r.redis, []string{key},
@@ -113,14 +117,12 @@ func (r *RateLimiter) Check(ctx context.Context, identifier string, config RateL
 	allowed := values[0].(int64) == 1
 	remaining := int(values[1].(int64))
 	retryAfterMs := values[2].(int64)
-
-	// Calculate reset time
-	resetAfter := config.Window
+	resetAfterMs := values[3].(int64)
 
 	return &RateLimitResult{
 		Allowed:    allowed,
 		Remaining:  remaining,
-		ResetAfter: resetAfter,
+		ResetAfter: time.Duration(resetAfterMs) * time.Millisecond,
 		RetryAfter: time.Duration(retryAfterMs) * time.Millisecond,
 		Limit:      config.Requests + config.BurstSize,
 		Window:     config.Window,
@@ -129,12 +131,20 @@ func (r *RateLimiter) Check(ctx context.Context, identifier string, config RateL
 
 // CheckIP rate limits by IP address
 func (r *RateLimiter) CheckIP(ctx context.Context, ip string, config RateLimitConfig) (*RateLimitResult, error) {
-	return r.Check(ctx, "ip:"+ip, config)
+	return r.Check(ctx, "ip:"+tierKey(config)+ip, config)
 }
 
 // CheckUser rate limits by user ID
 func (r *RateLimiter) CheckUser(ctx context.Context, userID string, config RateLimitConfig) (*RateLimitResult, error) {
-	return r.Check(ctx, "user:"+userID, config)
+	return r.Check(ctx, "user:"+tierKey(config)+userID, config)
+}
+
+// tierKey keeps the tiers from sharing one counter
+func tierKey(config RateLimitConfig) string {
+	if config.Name == "" {
+		return ""
+	}
+	return config.Name + ":"
 }
 
 // CheckEndpoint rate limits by user+endpoint combination
@@ -151,15 +161,25 @@ func (r *RateLimiter) CheckPlatform(ctx context.Context, platform, accountID str
 	return r.Check(ctx, "platform:"+platform+":"+accountID, config)
 }
 
-// SetRateLimitHeaders adds rate limit headers to HTTP response
+// SetRateLimitHeaders adds rate limit headers to HTTP response. When several tiers
+// apply to a request, the last one checked (the most specific) wins.
 func (r *RateLimiter) SetRateLimitHeaders(w http.ResponseWriter, result *RateLimitResult) {
 	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
 	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
 	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(result.ResetAfter).Unix(), 10))
 	
 	if !result.Allowed {
-		w.Header().Set("Retry-After", strconv.FormatInt(int64(result.RetryAfter.Seconds()), 10))
+		w.Header().Set("Retry-After", strconv.FormatInt(retryAfterSeconds(result.RetryAfter), 10))
+	}
+}
+
+// retryAfterSeconds rounds up so clients never retry a moment too early
+func retryAfterSeconds(d time.Duration) int64 {
+	secs := int64((d + time.Second - 1) / time.Second)
+	if secs < 1 {
+		secs = 1
 	}
+	return secs
 }
 
 // Reset clears rate limit for an identifier
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/web3airdropos/backend/internal/audit"
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+var (
+	ErrInvalidRole = errors.New("role must be user or admin")
+	ErrLastAdmin   = errors.New("cannot remove the last admin")
+)
+
+// SetRole changes a user's role. Roles are read from the database on each
+// admin check, so it takes effect at once. The change is audited against the
+// admin who made it.
+func (s *AuthService) SetRole(ctx context.Context, adminID, userID uuid.UUID, role string) (*models.User, error) {
+	if role != models.RoleUser && role != models.RoleAdmin {
+		return nil, ErrInvalidRole
+	}
+
+	var user models.User
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&user, "id = ?", userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		if user.Role == role {
+			return nil
+		}
+
+		if user.Role == models.RoleAdmin {
+			// Locking every admin row queues concurrent demotions behind this
+			// one, so the last two admins cannot demote each other at once
+			var admins []uuid.UUID
+			if err := tx.Model(&models.User{}).
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("role = ?", models.RoleAdmin).
+				Order("id").
+				Pluck("id", &admins).Error; err != nil {
+				return err
+			}
+			if len(admins) <= 1 {
+				return ErrLastAdmin
+			}
+		}
+		return tx.Model(&user).Update("role", role).Error
+	})
+
+	if s.auditLogger != nil {
+		entry := &audit.LogEntry{
+			UserID:      adminID,
+			Action:      audit.ActionRoleChange,
+			TargetType:  "user",
+			TargetID:    userID.String(),
+			Result:      audit.ResultSuccess,
+			RequestData: map[string]interface{}{"role": role},
+		}
+		if err != nil {
+			entry.Result = audit.ResultFailed
+			entry.ErrorMessage = err.Error()
+		}
+		s.auditLogger.Log(ctx, entry)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user.PasswordHash = ""
+	user.OpenAIKey = ""
+	return &user, nil
+}
+
+// CurrentRole returns the user's role as stored now, which may differ from the
+// one they had when their access token was issued
+func (s *AuthService) CurrentRole(ctx context.Context, userID uuid.UUID) (string, error) {
+	var user models.User
+	if err := s.db.WithContext(ctx).Select("role").First(&user, "id = ?", userID).Error; err != nil {
+		return "", err
+	}
+	return user.Role, nil
+}
+
+// BootstrapAdmins gives the admin role to the users with the given emails, so
+// the first admins can be named in config before anyone holds the role
+func (s *AuthService) BootstrapAdmins(ctx context.Context, emails []string) (int64, error) {
+	if len(emails) == 0 {
+		return 0, nil
+	}
+	lowered := make([]string, 0, len(emails))
+	for _, email := range emails {
+		lowered = append(lowered, strings.ToLower(email))
+	}
+
+	result := s.db.WithContext(ctx).Model(&models.User{}).
+		Where("LOWER(email) IN ? AND role <> ?", lowered, models.RoleAdmin).
+		Update("role", models.RoleAdmin)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+// mockService returns an AuthService backed by sqlmock
+func mockService(t *testing.T) (*AuthService, sqlmock.Sqlmock) {
+	t.Helper()
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewAuthService(db, "secret", 0, 0), mock
+}
+
+func expectAdmin(mock sqlmock.Sqlmock, userID uuid.UUID, others ...uuid.UUID) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE id = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "role"}).AddRow(userID, "a@example.com", models.RoleAdmin))
+	admins := sqlmock.NewRows([]string{"id"}).AddRow(userID)
+	for _, id := range others {
+		admins.AddRow(id)
+	}
+	mock.ExpectQuery(`SELECT "id" FROM "users" WHERE role = \$1 .*ORDER BY id FOR UPDATE`).
+		WithArgs(models.RoleAdmin).
+		WillReturnRows(admins)
+}
+
+func TestSetRoleKeepsLastAdmin(t *testing.T) {
+	s, mock := mockService(t)
+	userID := uuid.New()
+
+	expectAdmin(mock, userID)
+	mock.ExpectRollback()
+
+	if _, err := s.SetRole(context.Background(), userID, userID, models.RoleUser); err != ErrLastAdmin {
+		t.Fatalf("SetRole = %v, want ErrLastAdmin", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetRoleDemotesAdmin(t *testing.T) {
+	s, mock := mockService(t)
+	adminID, userID := uuid.New(), uuid.New()
+
+	expectAdmin(mock, userID, adminID)
+	mock.ExpectExec(`UPDATE "users" SET "role"=\$1`).
+		WithArgs(models.RoleUser, sqlmock.AnyArg(), userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	user, err := s.SetRole(context.Background(), adminID, userID, models.RoleUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Role != models.RoleUser {
+		t.Fatalf("role = %q, want %q", user.Role, models.RoleUser)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRequireRoleUsesStoredRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := map[string]int{
+		models.RoleAdmin: http.StatusOK,
+		models.RoleUser:  http.StatusForbidden,
+	}
+	for stored, want := range cases {
+		s, mock := mockService(t)
+		userID := uuid.New()
+		mock.ExpectQuery(`SELECT "role" FROM "users" WHERE id = \$1`).
+			WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(stored))
+
+		router := gin.New()
+		router.GET("/", func(c *gin.Context) {
+			c.Set("user_id", userID)
+		}, RequireRole(s, models.RoleAdmin), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != want {
+			t.Errorf("stored role %q: status = %d, want %d", stored, w.Code, want)
+		}
+	}
+}
@@ -0,0 +1,482 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/audit"
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/vault"
+)
+
+// Two-factor errors
+var (
+	ErrTwoFactorUnavailable    = errors.New("two-factor authentication is not configured")
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor authentication not enrolled")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	ErrTwoFactorCodeRequired   = errors.New("two-factor code required")
+	ErrTwoFactorChallengeSpent = errors.New("two-factor challenge already used or has no attempts left; log in again")
+)
+
+const (
+	totpIssuer            = "Web3AirdropOS"
+	totpSecretName        = "totp_secret"
+	totpPeriod            = 30 // seconds
+	totpDigits            = 6
+	totpSkew              = 1 // accept one period either side for clock drift
+	recoveryCodeCount     = 10
+	twoFactorChallengeTTL = 5 * time.Minute
+	twoFactorMaxAttempts  = 5 // codes one challenge may try before it is spent
+)
+
+// RecoveryCode is a single-use backup code for two-factor login
+type RecoveryCode struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	CodeHash  string    `gorm:"size:64;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// TableName overrides the default table name
+func (RecoveryCode) TableName() string {
+	return "two_factor_recovery_codes"
+}
+
+// TwoFactorChallenge tracks one login challenge, so it can be used once and
+// only tried a few times
+type TwoFactorChallenge struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	Attempts  int       `gorm:"not null;default:0"`
+	UsedAt    *time.Time
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+// TableName overrides the default table name
+func (TwoFactorChallenge) TableName() string {
+	return "two_factor_challenges"
+}
+
+// TOTPEnrollment is returned when a user starts TOTP enrollment
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	// OTPAuthURL is the otpauth:// URI; the frontend renders it as a QR code
+	OTPAuthURL string `json:"otpauth_url"`
+}
+
+// TwoFactorLoginRequest completes a login that returned a 2FA challenge
+type TwoFactorLoginRequest struct {
+	Challenge string `json:"challenge" binding:"required"`
+	Code      string `json:"code" binding:"required"`
+}
+
+// SetVault sets the vault used to store TOTP secrets
+func (s *AuthService) SetVault(v *vault.Vault) {
+	s.vault = v
+}
+
+// SetAuditLogger sets the audit logger for security events
+func (s *AuthService) SetAuditLogger(logger *audit.Logger) {
+	s.auditLogger = logger
+}
+
+// EnrollTOTP generates a new TOTP secret for the user and stores it in the vault.
+// Enrollment is not active until confirmed with VerifyTOTP.
+func (s *AuthService) EnrollTOTP(ctx context.Context, userID uuid.UUID) (*TOTPEnrollment, error) {
+	if s.vault == nil {
+		return nil, ErrTwoFactorUnavailable
+	}
+
+	var user models.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	raw := make([]byte, 20)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw)
+
+	// Replace any unconfirmed secret from a previous enrollment attempt
+	exists, err := s.vault.Exists(ctx, userID, totpSecretName)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		err = s.vault.Update(vault.WithSubsystem(ctx, "auth"), userID, totpSecretName, secret)
+	} else {
+		_, err = s.vault.Store(vault.WithSubsystem(ctx, "auth"), userID, totpSecretName, secret, vault.SecretTypeTOTP, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to store TOTP secret: %w", err)
+	}
+
+	s.logSecurityEvent(ctx, userID, audit.ActionTwoFactorEnroll, audit.ResultPending, nil)
+
+	return &TOTPEnrollment{
+		Secret:     secret,
+		OTPAuthURL: totpURL(user.Email, secret),
+	}, nil
+}
+
+// VerifyTOTP confirms enrollment with a code from the authenticator app,
+// enables 2FA and returns the plaintext recovery codes (shown only once)
+func (s *AuthService) VerifyTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	if s.vault == nil {
+		return nil, ErrTwoFactorUnavailable
+	}
+
+	var user models.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := s.vault.Retrieve(vault.WithSubsystem(ctx, "auth"), userID, totpSecretName)
+	if err != nil {
+		if errors.Is(err, vault.ErrSecretNotFound) {
+			return nil, ErrTwoFactorNotEnrolled
+		}
+		return nil, err
+	}
+
+	step, ok := validateTOTP(secret, code, time.Now())
+	if !ok || !s.acceptTOTPStep(userID, step) {
+		s.logSecurityEvent(ctx, userID, audit.ActionTwoFactorEnroll, audit.ResultFailed, ErrInvalidTwoFactorCode)
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, err := s.regenerateRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(&user).Update("totp_enabled", true).Error; err != nil {
+		return nil, err
+	}
+
+	s.logSecurityEvent(ctx, userID, audit.ActionTwoFactorEnroll, audit.ResultSuccess, nil)
+
+	return codes, nil
+}
+
+// CompleteTwoFactorLogin exchanges a login challenge and a TOTP or recovery code
+// for tokens. A challenge is spent by its first success or after
+// twoFactorMaxAttempts codes, and wrong codes count towards account lockout.
+func (s *AuthService) CompleteTwoFactorLogin(ctx context.Context, req *TwoFactorLoginRequest, ipAddress, userAgent string) (*AuthResponse, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(req.Challenge, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.jwtSecret, nil
+	})
+	if err != nil || !token.Valid || claims.TokenType != TokenTypeTwoFactor {
+		return nil, ErrInvalidToken
+	}
+	challengeID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var user models.User
+	if err := s.db.First(&user, claims.UserID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.IsActive || !user.TOTPEnabled {
+		return nil, ErrInvalidCredentials
+	}
+	if err := s.checkAccountLocked(ctx, user.Email); err != nil {
+		return nil, err
+	}
+
+	// Claim an attempt first, so concurrent guesses cannot exceed the limit
+	now := time.Now()
+	claimed := s.db.Model(&TwoFactorChallenge{}).
+		Where("id = ? AND user_id = ? AND used_at IS NULL AND attempts < ? AND expires_at > ?", challengeID, user.ID, twoFactorMaxAttempts, now).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if claimed.Error != nil {
+		return nil, claimed.Error
+	}
+	if claimed.RowsAffected == 0 {
+		return nil, ErrTwoFactorChallengeSpent
+	}
+
+	if err := s.verifySecondFactor(ctx, user.ID, req.Code); err != nil {
+		s.logSecurityEvent(ctx, user.ID, audit.ActionTwoFactorVerify, audit.ResultFailed, err)
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			s.recordFailedLogin(ctx, user.Email, &user, ipAddress, userAgent)
+		}
+		return nil, err
+	}
+
+	spent := s.db.Model(&TwoFactorChallenge{}).
+		Where("id = ? AND used_at IS NULL", challengeID).
+		Update("used_at", now)
+	if spent.Error != nil {
+		return nil, spent.Error
+	}
+	if spent.RowsAffected == 0 {
+		return nil, ErrTwoFactorChallengeSpent
+	}
+	s.resetFailedLogins(ctx, user.Email)
+	s.logSecurityEvent(ctx, user.ID, audit.ActionTwoFactorVerify, audit.ResultSuccess, nil)
+
+	s.db.Model(&user).Update("last_login_at", now)
+
+	tokens, err := s.generateTokenPair(ctx, &user, ipAddress, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	user.PasswordHash = ""
+
+	return &AuthResponse{
+		User:   &user,
+		Tokens: *tokens,
+	}, nil
+}
+
+// ConfirmIdentity re-checks the password, and the second factor when 2FA is
+// enabled, before a sensitive action. Failed passwords count towards lockout
+// the same way failed logins do.
+func (s *AuthService) ConfirmIdentity(ctx context.Context, userID uuid.UUID, password, code, ipAddress, userAgent string) (err error) {
+	defer func() {
+		result := audit.ResultSuccess
+		if err != nil {
+			result = audit.ResultFailed
+		}
+		s.logSecurityEvent(ctx, userID, audit.ActionReauthenticate, result, err)
+	}()
+
+	var user models.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		return ErrUserNotFound
+	}
+	if err := s.checkAccountLocked(ctx, user.Email); err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		s.recordFailedLogin(ctx, user.Email, &user, ipAddress, userAgent)
+		return ErrInvalidCredentials
+	}
+
+	// The failure count is only cleared once every factor has passed
+	if user.TOTPEnabled {
+		if code == "" {
+			return ErrTwoFactorCodeRequired
+		}
+		if err := s.verifySecondFactor(ctx, userID, code); err != nil {
+			if errors.Is(err, ErrInvalidTwoFactorCode) {
+				s.recordFailedLogin(ctx, user.Email, &user, ipAddress, userAgent)
+			}
+			return err
+		}
+	}
+	s.resetFailedLogins(ctx, user.Email)
+	return nil
+}
+
+// twoFactorChallenge issues a short-lived token proving the password step
+// passed. The token's ID names the TwoFactorChallenge row tracking its use.
+func (s *AuthService) twoFactorChallenge(user *models.User) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(twoFactorChallengeTTL)
+
+	challenge := TwoFactorChallenge{ID: uuid.New(), UserID: user.ID, ExpiresAt: expiresAt}
+	if err := s.db.Create(&challenge).Error; err != nil {
+		return "", time.Time{}, err
+	}
+
+	claims := Claims{
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenType: TokenTypeTwoFactor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID.String(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ID:        challenge.ID.String(),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.jwtSecret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// verifySecondFactor accepts either a current TOTP code not used before, or an
+// unused recovery code
+func (s *AuthService) verifySecondFactor(ctx context.Context, userID uuid.UUID, code string) error {
+	if s.vault == nil {
+		return ErrTwoFactorUnavailable
+	}
+
+	secret, err := s.vault.Retrieve(vault.WithSubsystem(ctx, "auth"), userID, totpSecretName)
+	if err != nil {
+		if errors.Is(err, vault.ErrSecretNotFound) {
+			return ErrTwoFactorNotEnrolled
+		}
+		return err
+	}
+
+	if step, ok := validateTOTP(secret, code, time.Now()); ok {
+		if !s.acceptTOTPStep(userID, step) {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	// Fall back to recovery codes
+	now := time.Now()
+	result := s.db.Model(&RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashToken(normalizeRecoveryCode(code))).
+		Update("used_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// acceptTOTPStep records the time step of an accepted TOTP code, refusing any
+// step at or below the last one accepted so a seen code cannot be replayed
+func (s *AuthService) acceptTOTPStep(userID uuid.UUID, step uint64) bool {
+	result := s.db.Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+// regenerateRecoveryCodes replaces a user's recovery codes with a fresh set
+func (s *AuthService) regenerateRecoveryCodes(userID uuid.UUID) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	records := make([]RecoveryCode, recoveryCodeCount)
+	now := time.Now()
+
+	for i := range codes {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		encoded := hex.EncodeToString(raw)
+		codes[i] = encoded[:5] + "-" + encoded[5:]
+		records[i] = RecoveryCode{
+			ID:        uuid.New(),
+			UserID:    userID,
+			CodeHash:  hashToken(normalizeRecoveryCode(codes[i])),
+			CreatedAt: now,
+		}
+	}
+
+	if err := s.db.Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.Create(&records).Error; err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// logSecurityEvent records a 2FA event when an audit logger is configured
+func (s *AuthService) logSecurityEvent(ctx context.Context, userID uuid.UUID, action audit.Action, result audit.Result, err error) {
+	if s.auditLogger == nil {
+		return
+	}
+
+	entry := &audit.LogEntry{
+		UserID: userID,
+		Action: action,
+		Result: result,
+	}
+	if err != nil {
+		entry.ErrorMessage = err.Error()
+	}
+	s.auditLogger.Log(ctx, entry)
+}
+
+// totpURL builds the otpauth:// URI understood by authenticator apps
+func totpURL(email, secret string) string {
+	label := url.PathEscape(totpIssuer + ":" + email)
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", totpIssuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprintf("%d", totpDigits))
+	params.Set("period", fmt.Sprintf("%d", totpPeriod))
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// validateTOTP checks a code against the secret, allowing for clock skew (RFC 6238),
+// and returns the time step it matched
+func validateTOTP(secret, code string, now time.Time) (uint64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+
+	counter := uint64(now.Unix() / totpPeriod)
+	for offset := -totpSkew; offset <= totpSkew; offset++ {
+		step := counter + uint64(offset)
+		if subtle.ConstantTimeCompare([]byte(generateTOTP(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// generateTOTP computes the HOTP value for a counter (RFC 4226)
+func generateTOTP(key []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%06d", value%1000000)
+}
+
+// normalizeRecoveryCode strips formatting so "ABCDE-12345" matches "abcde12345"
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
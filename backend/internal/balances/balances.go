@@ -0,0 +1,211 @@
+// Package balances reads native wallet balances per chain, from the Blockchair
+// API when a key is configured and from the chain's RPC endpoints otherwise. The
+// wallet API and the balance sync job both read through it.
+package balances
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/web3airdropos/backend/internal/config"
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/readretry"
+	"github.com/web3airdropos/backend/internal/rpc"
+	"github.com/web3airdropos/backend/internal/tracing"
+)
+
+// blockchairChains maps EVM chain IDs to Blockchair chain names
+var blockchairChains = map[int64]string{
+	1:     "ethereum",
+	56:    "binance-smart-chain",
+	137:   "polygon",
+	42161: "arbitrum",
+	10:    "optimism",
+	8453:  "base",
+}
+
+// Fetcher reads native balances. It is safe for concurrent use.
+type Fetcher struct {
+	rpc           *rpc.Resolver
+	http          *http.Client // Shared so balance reads reuse keep-alive connections
+	blockchairKey string
+}
+
+// NewFetcher creates a fetcher that resolves RPC endpoints through resolver
+func NewFetcher(resolver *rpc.Resolver, cfg *config.Config) *Fetcher {
+	return &Fetcher{
+		rpc:           resolver,
+		http:          &http.Client{Timeout: 15 * time.Second, Transport: tracing.Transport(nil)},
+		blockchairKey: cfg.BlockchairAPIKey,
+	}
+}
+
+// Close releases idle connections
+func (f *Fetcher) Close() {
+	f.http.CloseIdleConnections()
+}
+
+// Fetch returns the wallet's native balance on chainID as a decimal string in the
+// smallest unit (wei, lamports, satoshi). chainID only matters for EVM wallets.
+func (f *Fetcher) Fetch(ctx context.Context, wallet *models.Wallet, chainID int64) (string, error) {
+	// Try Blockchair API first if key is available (supports multiple chains)
+	if f.blockchairKey != "" && wallet.Type != models.WalletTypeSolana {
+		balance, err := f.fetchFromBlockchair(ctx, wallet, chainID)
+		if err == nil {
+			return balance, nil
+		}
+		// Fall back to direct RPC on Blockchair failure
+		log.Printf("Blockchair API failed for %s, falling back to RPC: %v", wallet.Address, err)
+	}
+
+	switch wallet.Type {
+	case models.WalletTypeEVM:
+		// Use Ethereum JSON-RPC to get balance
+		return readretry.FromEndpoints(ctx, f.rpc.Endpoints(ctx, wallet.UserID, chainID), func(rpcURL string) (string, error) {
+			var result string
+			if err := f.rpcRead(ctx, rpcURL, "eth_getBalance", []interface{}{wallet.Address, "latest"}, &result); err != nil {
+				return "", err
+			}
+			wei, ok := new(big.Int).SetString(strings.TrimPrefix(result, "0x"), 16)
+			if !ok {
+				return "", fmt.Errorf("invalid balance %q from RPC", result)
+			}
+			return wei.String(), nil
+		})
+
+	case models.WalletTypeSolana:
+		// Use Solana JSON-RPC to get balance
+		return readretry.FromEndpoints(ctx, f.rpc.Endpoints(ctx, wallet.UserID, rpc.SolanaChainID), func(rpcURL string) (string, error) {
+			var result struct {
+				Value uint64 `json:"value"`
+			}
+			if err := f.rpcRead(ctx, rpcURL, "getBalance", []interface{}{wallet.Address}, &result); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d", result.Value), nil
+		})
+
+	case "bitcoin":
+		// Bitcoin requires Blockchair or similar API
+		return f.fetchFromBlockchair(ctx, wallet, chainID)
+
+	default:
+		return "", fmt.Errorf("unsupported wallet type: %s", wallet.Type)
+	}
+}
+
+// rpcRead makes a read-only JSON-RPC call, retrying transient failures
+func (f *Fetcher) rpcRead(ctx context.Context, rpcURL, method string, params []interface{}, result interface{}) error {
+	payloadBytes, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  params,
+		"id":      1,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := readretry.Do(ctx, f.http, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", rpcURL, bytes.NewReader(payloadBytes))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &readretry.StatusError{URL: resp.Request.URL.Host, StatusCode: resp.StatusCode}
+	}
+
+	var rpcResp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return err
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("rpc error: %s", rpcResp.Error.Message)
+	}
+	return json.Unmarshal(rpcResp.Result, result)
+}
+
+// fetchFromBlockchair fetches balance using Blockchair API (multi-chain support)
+func (f *Fetcher) fetchFromBlockchair(ctx context.Context, wallet *models.Wallet, chainID int64) (string, error) {
+	if f.blockchairKey == "" {
+		return "", fmt.Errorf("BLOCKCHAIR_API_KEY not configured")
+	}
+
+	// Map wallet type to Blockchair chain name
+	var chain string
+	switch wallet.Type {
+	case models.WalletTypeEVM:
+		var ok bool
+		if chain, ok = blockchairChains[chainID]; !ok {
+			return "", fmt.Errorf("chain %d not supported via Blockchair", chainID)
+		}
+	case "bitcoin":
+		chain = "bitcoin"
+	default:
+		return "", fmt.Errorf("unsupported chain for Blockchair: %s", wallet.Type)
+	}
+
+	url := fmt.Sprintf("https://api.blockchair.com/%s/dashboards/address/%s?key=%s",
+		chain, wallet.Address, f.blockchairKey)
+
+	resp, err := readretry.Do(ctx, f.http, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", url, nil)
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("blockchair API error: status %d", resp.StatusCode)
+	}
+
+	var blockchairResp struct {
+		Data map[string]struct {
+			Address struct {
+				Balance json.Number `json:"balance"` // In smallest unit (wei, satoshi)
+			} `json:"address"`
+		} `json:"data"`
+		Context struct {
+			Error string `json:"error"`
+		} `json:"context"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&blockchairResp); err != nil {
+		return "", err
+	}
+
+	if blockchairResp.Context.Error != "" {
+		return "", fmt.Errorf("blockchair error: %s", blockchairResp.Context.Error)
+	}
+
+	// Get balance from response
+	for _, data := range blockchairResp.Data {
+		if data.Address.Balance == "" {
+			return "0", nil
+		}
+		return data.Address.Balance.String(), nil
+	}
+
+	return "0", nil
+}
@@ -0,0 +1,191 @@
+// Package circuit stops hammering a platform account that keeps failing. After
+// Threshold consecutive failures within Window a key's circuit opens and calls
+// fail fast with ErrCircuitOpen for Cooldown; then one trial call is let through
+// (half-open) and its outcome closes or reopens the circuit.
+package circuit
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned while a key's circuit is open
+var ErrCircuitOpen = errors.New("circuit open")
+
+// State is the state of one key's circuit
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half_open"
+)
+
+// OpenError is returned by Allow while a circuit is open
+type OpenError struct {
+	Key        string
+	RetryAfter time.Duration
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("%s for %s (retry after %s)", ErrCircuitOpen.Error(), e.Key, e.RetryAfter.Round(time.Second))
+}
+
+func (e *OpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// Config sets when circuits open. A Threshold of zero disables breaking.
+type Config struct {
+	Threshold int           // Consecutive failures that open the circuit
+	Window    time.Duration // Failures further apart than this start a new count
+	Cooldown  time.Duration // How long an open circuit fails fast
+}
+
+// Status describes a key's circuit for display
+type Status struct {
+	Key                 string     `json:"key"`
+	State               State      `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"`
+}
+
+type entry struct {
+	state       State
+	failures    int
+	lastFailure time.Time
+	lastError   string
+	openedAt    time.Time
+	trialAt     time.Time // When the half-open trial call started; zero if none is in flight
+}
+
+// Breaker tracks circuits by key. It is safe for concurrent use; the zero of
+// *Breaker (nil) allows everything.
+type Breaker struct {
+	cfg     Config
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// New creates a breaker, or returns nil when cfg.Threshold is zero
+func New(cfg Config) *Breaker {
+	if cfg.Threshold <= 0 {
+		return nil
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = 5 * time.Minute
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 2 * time.Minute
+	}
+	return &Breaker{cfg: cfg, entries: make(map[string]*entry)}
+}
+
+// Key builds the key for a platform account
+func Key(platform, accountID string) string {
+	return platform + ":" + accountID
+}
+
+// Allow reports whether a call for key may go ahead. It returns an *OpenError
+// while the circuit is open, and lets a single trial call through once the
+// cooldown has passed.
+func (b *Breaker) Allow(key string) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	e, ok := b.entries[key]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	switch e.state {
+	case StateOpen:
+		if wait := e.openedAt.Add(b.cfg.Cooldown).Sub(now); wait > 0 {
+			return &OpenError{Key: key, RetryAfter: wait}
+		}
+		e.state = StateHalfOpen
+		e.trialAt = now
+		log.Printf("🔌 Circuit %s half-open, trying one call", key)
+		return nil
+	case StateHalfOpen:
+		// A trial that never reported back must not hold the circuit forever
+		if !e.trialAt.IsZero() && now.Sub(e.trialAt) < b.cfg.Cooldown {
+			return &OpenError{Key: key, RetryAfter: e.trialAt.Add(b.cfg.Cooldown).Sub(now)}
+		}
+		e.trialAt = now
+	}
+	return nil
+}
+
+// Record reports the outcome of an allowed call. A nil err closes the circuit.
+func (b *Breaker) Record(key string, err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	e, ok := b.entries[key]
+	if err == nil {
+		if ok {
+			if e.state != StateClosed {
+				log.Printf("✅ Circuit %s closed", key)
+			}
+			delete(b.entries, key)
+		}
+		return
+	}
+
+	now := time.Now()
+	if !ok {
+		e = &entry{state: StateClosed}
+		b.entries[key] = e
+	}
+	if e.state == StateClosed && now.Sub(e.lastFailure) > b.cfg.Window {
+		e.failures = 0
+	}
+	e.failures++
+	e.lastFailure = now
+	e.lastError = err.Error()
+	e.trialAt = time.Time{}
+
+	if e.state == StateHalfOpen || (e.state == StateClosed && e.failures >= b.cfg.Threshold) {
+		e.state = StateOpen
+		e.openedAt = now
+		log.Printf("🔌 Circuit %s opened after %d consecutive failures, cooling down for %s: %s",
+			key, e.failures, b.cfg.Cooldown, e.lastError)
+	}
+}
+
+// Status returns the circuit for key; keys with no recent failures are closed
+func (b *Breaker) Status(key string) Status {
+	status := Status{Key: key, State: StateClosed}
+	if b == nil {
+		return status
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	e, ok := b.entries[key]
+	if !ok {
+		return status
+	}
+	status.State = e.state
+	status.ConsecutiveFailures = e.failures
+	status.LastError = e.lastError
+	if e.state != StateClosed {
+		openedAt := e.openedAt
+		retryAt := e.openedAt.Add(b.cfg.Cooldown)
+		status.OpenedAt = &openedAt
+		status.RetryAt = &retryAt
+	}
+	return status
+}
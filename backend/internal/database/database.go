@@ -33,6 +33,21 @@ func Connect(databaseURL string) (*gorm.DB, error) {
 	return db, nil
 }
 
+// ConnectReplica opens the read replica at replicaURL. It returns nil when no
+// replica is configured or it cannot be reached, so reads stay on the primary.
+func ConnectReplica(replicaURL string) *gorm.DB {
+	if replicaURL == "" {
+		return nil
+	}
+	replica, err := Connect(replicaURL)
+	if err != nil {
+		log.Printf("⚠️ Read replica unavailable, reading from the primary: %v", err)
+		return nil
+	}
+	log.Println("✅ Read replica connected")
+	return replica
+}
+
 func Migrate(db *gorm.DB) error {
 	log.Println("🔄 Running database migrations...")
 	
@@ -46,24 +61,32 @@ func Migrate(db *gorm.DB) error {
 		&models.WalletTag{},
 		&models.WalletGroup{},
 		&models.Transaction{},
+		&models.RPCEndpoint{},
 		
 		// Platform account models
 		&models.PlatformAccount{},
 		&models.AccountActivity{},
 		&models.Proxy{},
+		&models.ProxyPool{},
 		
 		// Campaign models
 		&models.Campaign{},
 		&models.CampaignTask{},
 		&models.TaskExecution{},
+		&models.ScheduledTaskExecution{},
+		&models.CampaignTemplate{},
 		
 		// Automation models
 		&models.AutomationJob{},
 		&models.JobLog{},
+		&models.DeadLetterJob{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
 		
 		// Content models
 		&models.ContentDraft{},
 		&models.ScheduledPost{},
+		&models.ThreadPart{},
 		
 		// Browser session models
 		&models.BrowserSession{},
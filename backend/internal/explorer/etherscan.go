@@ -0,0 +1,248 @@
+// Package explorer imports on-chain transaction history from Etherscan-family
+// block explorers.
+package explorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/web3airdropos/backend/internal/config"
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+const (
+	// pageSize is Etherscan's maximum; page*offset may not exceed 10,000
+	pageSize = 1000
+	maxPages = 10
+
+	// pageDelay keeps paging under the free tier's 5 requests per second
+	pageDelay = 250 * time.Millisecond
+)
+
+var (
+	ErrNotConfigured = errors.New("explorer API key not configured for chain")
+	ErrUnsupported   = errors.New("transaction history is only available for EVM wallets")
+	ErrRateLimited   = errors.New("explorer rate limit reached")
+)
+
+// Client talks to the Etherscan V2 API, which serves every supported chain from
+// one URL selected by chainid
+type Client struct {
+	baseURL    string
+	defaultKey string
+	chainKeys  map[string]string
+	http       *http.Client
+}
+
+func NewClient(cfg *config.Config) *Client {
+	return &Client{
+		baseURL:    cfg.EtherscanAPIURL,
+		defaultKey: cfg.EtherscanAPIKey,
+		chainKeys:  cfg.EtherscanAPIKeys,
+		http:       &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// apiKey returns the chain's own key, falling back to the shared one
+func (c *Client) apiKey(chainID int64) string {
+	if key := c.chainKeys[strconv.FormatInt(chainID, 10)]; key != "" {
+		return key
+	}
+	return c.defaultKey
+}
+
+type etherscanTx struct {
+	Hash            string `json:"hash"`
+	BlockNumber     string `json:"blockNumber"`
+	TimeStamp       string `json:"timeStamp"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Value           string `json:"value"`
+	GasUsed         string `json:"gasUsed"`
+	GasPrice        string `json:"gasPrice"`
+	IsError         string `json:"isError"`
+	TxReceiptStatus string `json:"txreceipt_status"`
+	FunctionName    string `json:"functionName"`
+	ContractAddress string `json:"contractAddress"`
+}
+
+// transactions lists the normal transactions of an address from startBlock on,
+// oldest first, following pages until the explorer runs out or the page cap is hit
+func (c *Client) transactions(ctx context.Context, address string, chainID, startBlock int64) ([]etherscanTx, error) {
+	key := c.apiKey(chainID)
+	if key == "" {
+		return nil, fmt.Errorf("%w %d", ErrNotConfigured, chainID)
+	}
+
+	var all []etherscanTx
+	for page := 1; page <= maxPages; page++ {
+		if page > 1 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(pageDelay):
+			}
+		}
+
+		txs, err := c.page(ctx, key, address, chainID, startBlock, page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, txs...)
+		if len(txs) < pageSize {
+			break
+		}
+	}
+	return all, nil
+}
+
+func (c *Client) page(ctx context.Context, key, address string, chainID, startBlock int64, page int) ([]etherscanTx, error) {
+	query := url.Values{
+		"chainid":    {strconv.FormatInt(chainID, 10)},
+		"module":     {"account"},
+		"action":     {"txlist"},
+		"address":    {address},
+		"startblock": {strconv.FormatInt(startBlock, 10)},
+		"endblock":   {"99999999"},
+		"page":       {strconv.Itoa(page)},
+		"offset":     {strconv.Itoa(pageSize)},
+		"sort":       {"asc"},
+		"apikey":     {key},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, ErrRateLimited
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("explorer API error: status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Status  string          `json:"status"`
+		Message string          `json:"message"`
+		Result  json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	// Failures come back as status "0" with the reason in result
+	if body.Status != "1" {
+		var reason string
+		_ = json.Unmarshal(body.Result, &reason)
+		switch {
+		case strings.HasPrefix(body.Message, "No transactions found"):
+			return nil, nil
+		case strings.Contains(strings.ToLower(reason), "rate limit"):
+			return nil, ErrRateLimited
+		default:
+			return nil, fmt.Errorf("explorer error: %s %s", body.Message, reason)
+		}
+	}
+
+	var txs []etherscanTx
+	if err := json.Unmarshal(body.Result, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// SyncWallet imports a wallet's transactions into the transactions table. It resumes
+// from the newest stored block, and rows are de-duplicated by hash. It returns how
+// many transactions the explorer reported. A transaction between two tracked
+// wallets is stored once, under whichever wallet synced it first.
+func (c *Client) SyncWallet(ctx context.Context, db *gorm.DB, wallet *models.Wallet) (int, error) {
+	if wallet.Type != models.WalletTypeEVM {
+		return 0, ErrUnsupported
+	}
+	chainID := int64(wallet.ChainID)
+	if chainID == 0 {
+		chainID = 1
+	}
+
+	// Re-reading the last block picks up transactions that landed after the previous sync
+	var startBlock int64
+	db.WithContext(ctx).Model(&models.Transaction{}).
+		Where("wallet_id = ?", wallet.ID).
+		Select("COALESCE(MAX(block_number), 0)").
+		Scan(&startBlock)
+
+	txs, err := c.transactions(ctx, wallet.Address, chainID, startBlock)
+	if err != nil {
+		return 0, err
+	}
+	if len(txs) == 0 {
+		return 0, nil
+	}
+
+	records := make([]models.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		records = append(records, toTransaction(wallet, int(chainID), tx))
+	}
+
+	err = db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "hash"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "block_number", "gas_used", "timestamp"}),
+	}).CreateInBatches(records, 200).Error
+	if err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+func toTransaction(wallet *models.Wallet, chainID int, tx etherscanTx) models.Transaction {
+	status := "success"
+	if tx.IsError == "1" || tx.TxReceiptStatus == "0" {
+		status = "failed"
+	}
+
+	to := tx.To
+	if to == "" {
+		to = tx.ContractAddress // Contract creation
+	}
+
+	blockNumber, _ := strconv.ParseInt(tx.BlockNumber, 10, 64)
+	unix, _ := strconv.ParseInt(tx.TimeStamp, 10, 64)
+
+	decoded := "{}"
+	if tx.FunctionName != "" {
+		if data, err := json.Marshal(map[string]string{"function": tx.FunctionName}); err == nil {
+			decoded = string(data)
+		}
+	}
+
+	return models.Transaction{
+		WalletID:    wallet.ID,
+		Hash:        tx.Hash,
+		ChainID:     chainID,
+		FromAddress: tx.From,
+		ToAddress:   to,
+		Value:       tx.Value,
+		GasUsed:     tx.GasUsed,
+		GasPrice:    tx.GasPrice,
+		Status:      status,
+		BlockNumber: blockNumber,
+		Timestamp:   time.Unix(unix, 0).UTC(),
+		DecodedData: decoded,
+	}
+}
@@ -0,0 +1,181 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dependencyTimeout bounds each dependency probe
+const dependencyTimeout = 3 * time.Second
+
+// Dependencies are the external services probed alongside the database and Redis.
+// An empty field skips that probe, so deployments without e.g. Docker stay green.
+type Dependencies struct {
+	AIServiceURL string // GET {url}/health
+	DockerHost   string // unix:///path/to/docker.sock or tcp://host:port
+	RPCURL       string // EVM JSON-RPC endpoint asked for eth_blockNumber
+}
+
+// SetDependencies configures the optional dependency probes. Failing dependencies
+// are reported but do not make the service unready: every replica shares them, so
+// taking replicas out of rotation would not help.
+func (c *Checker) SetDependencies(deps Dependencies) {
+	c.deps = deps
+	if deps.DockerHost != "" {
+		c.docker = dockerClient(deps.DockerHost)
+	}
+}
+
+// checkDependencies runs the configured probes concurrently
+func (c *Checker) checkDependencies() map[string]Check {
+	probes := make(map[string]func(context.Context) (string, error))
+	if c.deps.AIServiceURL != "" {
+		probes["ai_service"] = c.checkAIService
+	}
+	if c.deps.DockerHost != "" {
+		probes["docker"] = c.checkDocker
+	}
+	if c.deps.RPCURL != "" {
+		probes["rpc"] = c.checkRPC
+	}
+
+	checks := make(map[string]Check, len(probes))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, probe := range probes {
+		wg.Add(1)
+		go func(name string, probe func(context.Context) (string, error)) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), dependencyTimeout)
+			defer cancel()
+
+			start := time.Now()
+			message, err := probe(ctx)
+			check := Check{Status: "healthy", Message: message, Duration: time.Since(start).String()}
+			if err != nil {
+				check.Status = "unhealthy"
+				check.Message = err.Error()
+			}
+
+			mu.Lock()
+			checks[name] = check
+			mu.Unlock()
+		}(name, probe)
+	}
+	wg.Wait()
+	return checks
+}
+
+func (c *Checker) checkAIService(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", strings.TrimRight(c.deps.AIServiceURL, "/")+"/health", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("AI service returned status %d", resp.StatusCode)
+	}
+	return "", nil
+}
+
+func (c *Checker) checkDocker(ctx context.Context) (string, error) {
+	if c.docker == nil {
+		return "", fmt.Errorf("unsupported DOCKER_HOST %q", c.deps.DockerHost)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.docker.base+"/_ping", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.docker.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64))
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("docker ping returned status %d", resp.StatusCode)
+	}
+	return "api " + resp.Header.Get("Api-Version") + ", " + strings.TrimSpace(string(body)), nil
+}
+
+func (c *Checker) checkRPC(ctx context.Context) (string, error) {
+	payload := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.deps.RPCURL, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("RPC returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("invalid RPC response: %w", err)
+	}
+	if result.Error != nil {
+		return "", fmt.Errorf("RPC error: %s", result.Error.Message)
+	}
+	block, err := strconv.ParseUint(strings.TrimPrefix(result.Result, "0x"), 16, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid block number %q", result.Result)
+	}
+	return fmt.Sprintf("block %d", block), nil
+}
+
+// docker reaches the Docker Engine API
+type docker struct {
+	client *http.Client
+	base   string
+}
+
+// dockerClient builds a client for a DOCKER_HOST value, or nil if it is not a unix
+// or tcp address
+func dockerClient(host string) *docker {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil
+	}
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}
+		return &docker{client: &http.Client{Transport: transport}, base: "http://docker"}
+	case "tcp":
+		return &docker{client: &http.Client{}, base: "http://" + u.Host}
+	}
+	return nil
+}
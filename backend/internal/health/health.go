@@ -3,6 +3,7 @@ package health
 import (
 	"context"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
@@ -18,6 +19,11 @@ type Checker struct {
 	isReady     bool
 	readyMu     sync.RWMutex
 	startupTime time.Time
+
+	// Optional dependency probes, see SetDependencies
+	deps   Dependencies
+	docker *docker
+	http   *http.Client
 }
 
 // NewChecker creates a new health checker
@@ -27,6 +33,7 @@ func NewChecker(db *gorm.DB, redis *redis.Client) *Checker {
 		redis:       redis,
 		isReady:     false,
 		startupTime: time.Now(),
+		http:        &http.Client{Timeout: dependencyTimeout},
 	}
 }
 
@@ -51,6 +58,7 @@ type CheckStatus struct {
 	Uptime    string           `json:"uptime"`
 	Version   string           `json:"version"`
 	Checks    map[string]Check `json:"checks,omitempty"`
+	Degraded  []string         `json:"degraded,omitempty"` // Failing optional dependencies
 }
 
 // Check represents a single health check
@@ -105,6 +113,9 @@ func (c *Checker) Readyz(ctx *gin.Context) {
 		Checks:    checks,
 	}
 
+	// Optional dependencies are reported without affecting readiness
+	status.Degraded = mergeDependencies(checks, c.checkDependencies())
+
 	if !allHealthy {
 		status.Status = "degraded"
 		ctx.JSON(http.StatusServiceUnavailable, status)
@@ -138,9 +149,13 @@ func (c *Checker) Health(ctx *gin.Context) {
 		Version:   "1.0.0",
 		Checks:    checks,
 	}
+	status.Degraded = mergeDependencies(checks, c.checkDependencies())
 
 	if allHealthy {
 		status.Status = "healthy"
+		if len(status.Degraded) > 0 {
+			status.Status = "degraded"
+		}
 		ctx.JSON(http.StatusOK, status)
 	} else {
 		status.Status = "unhealthy"
@@ -191,6 +206,19 @@ func (c *Checker) checkRedis() Check {
 	}
 }
 
+// mergeDependencies adds dependency checks to checks and returns the failing ones
+func mergeDependencies(checks, deps map[string]Check) []string {
+	var failing []string
+	for name, check := range deps {
+		checks[name] = check
+		if check.Status != "healthy" {
+			failing = append(failing, name)
+		}
+	}
+	sort.Strings(failing)
+	return failing
+}
+
 // RegisterRoutes registers health check routes
 func (c *Checker) RegisterRoutes(r *gin.Engine) {
 	r.GET("/healthz", c.Healthz)
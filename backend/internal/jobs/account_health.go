@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/web3airdropos/backend/internal/accounthealth"
+	"github.com/web3airdropos/backend/internal/locks"
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+// accountHealthPause spaces out probes so a large account list does not burst
+// into the platforms' APIs
+const accountHealthPause = time.Second
+
+// accountHealthChecks periodically probes active accounts, until Stop
+func (s *Scheduler) accountHealthChecks() {
+	interval := s.config.AccountHealthInterval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// One replica checks per tick; the lock is left to expire
+			if s.locks != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				_, err := s.locks.Acquire(ctx, locks.ResourceScheduler, "account-health", interval*9/10)
+				cancel()
+				if err != nil {
+					continue
+				}
+			}
+			s.checkAccountHealth()
+
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// checkAccountHealth probes every active account on a platform that supports it.
+// Suspended accounts are deactivated by the checker, so the job loop skips them from
+// then on.
+func (s *Scheduler) checkAccountHealth() {
+	var accounts []models.PlatformAccount
+	if err := s.db.Select("id").
+		Where("is_active = ? AND platform IN ?", true, accounthealth.Platforms).
+		Find(&accounts).Error; err != nil {
+		log.Printf("⚠️ Failed to load accounts for health checks: %v", err)
+		return
+	}
+
+	unhealthy := 0
+	for _, account := range accounts {
+		ctx, cancel := context.WithTimeout(s.runCtx, 30*time.Second)
+		result, err := s.health.Check(ctx, account.ID)
+		cancel()
+		if err != nil {
+			log.Printf("⚠️ Health check failed for account %s: %v", account.ID, err)
+		} else if result.Health != models.AccountHealthActive {
+			unhealthy++
+		}
+
+		select {
+		case <-time.After(accountHealthPause):
+		case <-s.stopChan:
+			return
+		}
+	}
+
+	if unhealthy > 0 {
+		log.Printf("⚠️ %d of %d accounts are unhealthy", unhealthy, len(accounts))
+	}
+}
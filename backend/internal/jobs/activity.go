@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+// logActivity adds a successful automated action to the account's activity feed.
+// Its Type is the action as the dashboard counts it (post, reply, like, follow,
+// recast) and AutomatedBy the ID of the job that took it.
+func (s *Scheduler) logActivity(account *models.PlatformAccount, activity *models.AccountActivity, metadata map[string]interface{}) {
+	metadataJSON := []byte("{}")
+	if len(metadata) > 0 {
+		metadataJSON, _ = json.Marshal(metadata)
+	}
+
+	activity.ID = uuid.New()
+	activity.AccountID = account.ID
+	activity.Metadata = string(metadataJSON)
+	activity.Status = "success"
+	activity.CreatedAt = time.Now()
+
+	if err := s.db.Create(activity).Error; err != nil {
+		log.Printf("⚠️ Failed to log %s activity for account %s: %v", activity.Type, account.ID, err)
+	}
+}
+
+// activityType maps a platform action to the activity type it is logged as
+func activityType(action string) string {
+	if action == "send" {
+		return "post"
+	}
+	return action
+}
@@ -0,0 +1,99 @@
+package jobs
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/config"
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+// captured records every argument it is matched against
+type captured struct{ values []driver.Value }
+
+func (c *captured) Match(v driver.Value) bool {
+	c.values = append(c.values, v)
+	return true
+}
+
+func (c *captured) has(v driver.Value) bool {
+	for _, got := range c.values {
+		if got == v {
+			return true
+		}
+	}
+	return false
+}
+
+func TestScheduledPostIsLoggedAsActivity(t *testing.T) {
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"id": "111", "channel_id": "222", "guild_id": "333"})
+	}))
+	defer webhook.Close()
+
+	db, mock := mockDB(t)
+	s := NewScheduler(db, nil, websocket.NewHub(), &config.Config{DiscordWebhookURL: webhook.URL})
+
+	userID, accountID, postID := uuid.New(), uuid.New(), uuid.New()
+	job := &models.AutomationJob{ID: uuid.New(), UserID: userID, Type: models.JobTypeScheduledPost}
+
+	mock.ExpectQuery(`SELECT \* FROM "scheduled_posts" WHERE user_id = \$1 AND status = \$2 AND scheduled_for <= \$3`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "account_id", "platform", "content", "status"}).
+			AddRow(postID, userID, accountID, "discord", "gm", "pending"))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "scheduled_posts" SET "status"=\$1`).
+		WithArgs("processing", sqlmock.AnyArg(), postID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT \* FROM "platform_accounts" WHERE "platform_accounts"."id" = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "platform"}).
+			AddRow(accountID, userID, "discord"))
+	mock.ExpectQuery(`SELECT \* FROM "thread_parts" WHERE scheduled_post_id = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "scheduled_posts" SET .*"status"=\$`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	args := &captured{}
+	insertArgs := make([]driver.Value, 12)
+	for i := range insertArgs {
+		insertArgs[i] = args
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "account_activities"`).
+		WithArgs(insertArgs...).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.handleScheduledPost(ctx, &JobContext{Job: job, UserID: userID}, s); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []driver.Value{
+		accountID.String(),
+		"post",
+		"111",
+		"https://discord.com/channels/333/222/111",
+		"success",
+		job.ID.String(),
+	} {
+		if !args.has(want) {
+			t.Errorf("activity insert args %v are missing %v", args.values, want)
+		}
+	}
+}
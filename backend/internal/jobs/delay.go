@@ -0,0 +1,125 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DelayRange bounds the pause between two automated actions
+type DelayRange struct {
+	MinSeconds float64 `json:"min_seconds"`
+	MaxSeconds float64 `json:"max_seconds"`
+}
+
+// DelayConfig is the part of a job config that tunes pacing. Platform ranges
+// take precedence over Delay, which takes precedence over the server defaults.
+type DelayConfig struct {
+	Delay          *DelayRange           `json:"delay,omitempty"`
+	PlatformDelays map[string]DelayRange `json:"platform_delays,omitempty"` // Keyed by platform, e.g. "farcaster"
+}
+
+// delayPolicy picks randomized pauses between actions so runs don't follow a fixed rhythm
+type delayPolicy struct {
+	fallback  DelayRange
+	platforms map[string]DelayRange
+}
+
+// delayPolicy builds the pacing policy for a job from the server defaults and its config
+func (s *Scheduler) delayPolicy(cfg DelayConfig) *delayPolicy {
+	p := &delayPolicy{
+		fallback: DelayRange{
+			MinSeconds: s.config.ActionDelayMin.Seconds(),
+			MaxSeconds: s.config.ActionDelayMax.Seconds(),
+		},
+		platforms: make(map[string]DelayRange, len(cfg.PlatformDelays)),
+	}
+	if cfg.Delay != nil {
+		p.fallback = *cfg.Delay
+	}
+	for platform, r := range cfg.PlatformDelays {
+		p.platforms[strings.ToLower(platform)] = r
+	}
+	return p
+}
+
+// next returns a uniformly random delay within the platform's range
+func (p *delayPolicy) next(platform string) time.Duration {
+	r, ok := p.platforms[strings.ToLower(platform)]
+	if !ok {
+		r = p.fallback
+	}
+
+	min := time.Duration(r.MinSeconds * float64(time.Second))
+	max := time.Duration(r.MaxSeconds * float64(time.Second))
+	if min < 0 {
+		min = 0
+	}
+	if max <= min {
+		return min
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max-min)))
+	if err != nil {
+		return min + (max-min)/2
+	}
+	return min + time.Duration(n.Int64())
+}
+
+// wait sleeps for the platform's next delay, returning early if ctx is cancelled
+func (p *delayPolicy) wait(ctx context.Context, platform string) error {
+	timer := time.NewTimer(p.next(platform))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseDelayConfig reads the pacing settings from a job config, ignoring anything else
+func parseDelayConfig(raw string) DelayConfig {
+	var cfg DelayConfig
+	if raw != "" {
+		_ = json.Unmarshal([]byte(raw), &cfg)
+	}
+	return cfg
+}
+
+// dailyActionsKey counts an account's automated actions for the current UTC day
+func dailyActionsKey(accountID uuid.UUID) string {
+	return fmt.Sprintf("automation:actions:%s:%s", accountID, time.Now().UTC().Format("2006-01-02"))
+}
+
+// dailyActionCapReached reports whether the account has used its daily action budget.
+// Without Redis there is no shared counter, so the cap is not enforced.
+func (s *Scheduler) dailyActionCapReached(ctx context.Context, accountID uuid.UUID, limit int) bool {
+	if limit <= 0 || s.redis == nil {
+		return false
+	}
+	count, err := s.redis.Get(ctx, dailyActionsKey(accountID)).Int()
+	if err != nil {
+		return false
+	}
+	return count >= limit
+}
+
+// recordDailyAction counts one action against the account's daily cap
+func (s *Scheduler) recordDailyAction(ctx context.Context, accountID uuid.UUID) {
+	if s.redis == nil {
+		return
+	}
+	key := dailyActionsKey(accountID)
+	pipe := s.redis.TxPipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, 48*time.Hour)
+	pipe.Exec(ctx)
+}
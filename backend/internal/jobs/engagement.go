@@ -0,0 +1,145 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/services/platforms"
+)
+
+// engagementFeedSize is how many casts an engagement run fetches to pick from
+const engagementFeedSize = 50
+
+// EngagementTarget selects the casts an engagement job acts on. TargetUser wins
+// over Channel, which wins over a plain keyword search; a Keyword given with
+// either of the others filters their feed instead.
+type EngagementTarget struct {
+	Channel    string   `json:"channel,omitempty"`     // Channel ID, e.g. "base"
+	Keyword    string   `json:"keyword,omitempty"`     // Search term, or filter for the channel/user feed
+	TargetUser string   `json:"target_user,omitempty"` // Username or FID whose casts to engage with
+	Replies    []string `json:"replies,omitempty"`     // Reply texts, one picked at random per reply
+}
+
+func (t EngagementTarget) empty() bool {
+	return t.Channel == "" && t.Keyword == "" && t.TargetUser == ""
+}
+
+// engagementCandidates fetches the casts an engagement job may act on. Reads
+// use the shared Neynar key rather than an account, so they are not routed
+// through account proxies.
+func (s *Scheduler) engagementCandidates(ctx context.Context, target EngagementTarget) ([]platforms.NeynarCast, error) {
+	if target.empty() {
+		return nil, errors.New("engagement job needs a channel, keyword or target_user")
+	}
+	client, err := platforms.NewFarcasterClient(&platforms.AccountCredentials{APIKey: s.config.NeynarAPIKey})
+	if err != nil {
+		return nil, err
+	}
+
+	var casts []platforms.NeynarCast
+	filter := target.Keyword
+	switch {
+	case target.TargetUser != "":
+		fid := strings.TrimPrefix(target.TargetUser, "@")
+		if _, err := strconv.ParseUint(fid, 10, 64); err != nil {
+			profile, err := client.GetUserByUsername(ctx, fid)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve target user %s: %w", target.TargetUser, err)
+			}
+			fid = profile.ID
+		}
+		casts, _, err = client.GetUserCasts(ctx, fid, "", engagementFeedSize)
+	case target.Channel != "":
+		casts, _, err = client.GetChannelFeed(ctx, target.Channel, "", engagementFeedSize)
+	default:
+		casts, _, err = client.SearchCasts(ctx, target.Keyword, "", engagementFeedSize)
+		filter = ""
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch casts: %w", err)
+	}
+
+	filter = strings.ToLower(filter)
+	candidates := make([]platforms.NeynarCast, 0, len(casts))
+	for _, cast := range casts {
+		if cast.Hash == "" {
+			continue
+		}
+		if filter != "" && !strings.Contains(strings.ToLower(cast.Text), filter) {
+			continue
+		}
+		candidates = append(candidates, cast)
+	}
+	return candidates, nil
+}
+
+// engagementTargetID is what an action is aimed at: the author's FID for
+// follows, the cast hash otherwise
+func engagementTargetID(action string, cast platforms.NeynarCast) string {
+	if action == "follow" {
+		return strconv.FormatUint(cast.Author.FID, 10)
+	}
+	return cast.Hash
+}
+
+// nextEngagementCast picks the first candidate the account has not already
+// taken action on. The account's own casts are skipped.
+func (s *Scheduler) nextEngagementCast(account *models.PlatformAccount, action string, candidates []platforms.NeynarCast) (*platforms.NeynarCast, bool) {
+	targetIDs := make([]string, 0, len(candidates))
+	for _, cast := range candidates {
+		targetIDs = append(targetIDs, engagementTargetID(action, cast))
+	}
+
+	var engaged []string
+	s.db.Model(&models.AccountActivity{}).
+		Where("account_id = ? AND type = ? AND status = ? AND target_id IN ?", account.ID, action, "success", targetIDs).
+		Pluck("target_id", &engaged)
+	done := make(map[string]bool, len(engaged))
+	for _, id := range engaged {
+		done[id] = true
+	}
+
+	for i := range candidates {
+		cast := &candidates[i]
+		if strings.EqualFold(cast.Author.Username, account.Username) {
+			continue
+		}
+		if !done[engagementTargetID(action, *cast)] {
+			return cast, true
+		}
+	}
+	return nil, false
+}
+
+// recordEngagement stores a successful action as account activity, which is
+// what later runs de-duplicate against
+func (s *Scheduler) recordEngagement(jctx *JobContext, account *models.PlatformAccount, action string, cast *platforms.NeynarCast, content string, metadata map[string]interface{}) {
+	targetURL := fmt.Sprintf("https://warpcast.com/%s/%s", cast.Author.Username, shortCastHash(cast.Hash))
+	if action == "follow" {
+		targetURL = "https://warpcast.com/" + cast.Author.Username
+	}
+	s.logActivity(account, &models.AccountActivity{
+		Type:        action,
+		TargetID:    engagementTargetID(action, *cast),
+		TargetURL:   targetURL,
+		Content:     content,
+		AutomatedBy: jctx.Job.ID.String(),
+	}, metadata)
+}
+
+// shortCastHash trims a cast hash to the prefix Warpcast URLs use
+func shortCastHash(hash string) string {
+	if len(hash) > 10 {
+		return hash[:10]
+	}
+	return hash
+}
+
+func pickReply(replies []string) string {
+	return replies[rand.Intn(len(replies))]
+}
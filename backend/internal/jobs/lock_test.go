@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/config"
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+func TestTwoSchedulersNeverRunTheSameJobAtOnce(t *testing.T) {
+	server := miniredis.RunT(t)
+	jobID, userID := uuid.New(), uuid.New()
+	const triggersPerScheduler = 5
+
+	// Two replicas sharing Redis, each with its own database connection
+	newReplica := func() *Scheduler {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { client.Close() })
+		db, mock := mockDB(t)
+		mock.MatchExpectationsInOrder(false)
+		// Each trigger loads the job; the last two below come after the first run
+		for i := 0; i < triggersPerScheduler+2; i++ {
+			mock.ExpectQuery(`SELECT \* FROM "automation_jobs"`).WillReturnRows(
+				sqlmock.NewRows([]string{"id", "user_id", "type", "name", "is_active", "status"}).
+					AddRow(jobID, userID, models.JobTypeBalanceSync, "Sync", true, "idle"))
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE "automation_jobs"`).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}
+		return NewScheduler(db, client, websocket.NewHub(), &config.Config{})
+	}
+	replicas := []*Scheduler{newReplica(), newReplica()}
+
+	// Both replicas hear the same job many times at once
+	var wg sync.WaitGroup
+	for _, s := range replicas {
+		for i := 0; i < triggersPerScheduler; i++ {
+			wg.Add(1)
+			go func(s *Scheduler) {
+				defer wg.Done()
+				if err := s.EnqueueJob(jobID); err != nil {
+					t.Error(err)
+				}
+			}(s)
+		}
+	}
+	wg.Wait()
+
+	var winner, other *Scheduler
+	queued := 0
+	for i, s := range replicas {
+		if n := len(s.jobQueue); n > 0 {
+			queued += n
+			winner, other = s, replicas[1-i]
+		}
+	}
+	if queued != 1 {
+		t.Fatalf("job was queued %d times across replicas, want once", queued)
+	}
+
+	// While the run holds the lock the other replica still skips it
+	jctx := <-winner.jobQueue
+	if err := other.EnqueueJob(jobID); err != nil || len(other.jobQueue) != 0 {
+		t.Fatalf("other replica queued the job while it was running (err %v)", err)
+	}
+
+	// Once the run finishes and releases the lock, the job can run again
+	winner.releaseJobLock(jctx.Lock)
+	jctx.Cancel()
+	if err := other.EnqueueJob(jobID); err != nil || len(other.jobQueue) != 1 {
+		t.Fatalf("job was not queued after the lock was released (err %v)", err)
+	}
+	(<-other.jobQueue).Cancel()
+}
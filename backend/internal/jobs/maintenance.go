@@ -0,0 +1,78 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/web3airdropos/backend/internal/locks"
+)
+
+// maintenanceTimeout bounds a single cleanup routine, so a slow delete cannot
+// hold the maintenance run past its lock
+const maintenanceTimeout = 5 * time.Minute
+
+// CleanupFunc deletes rows past their retention and returns how many it removed
+type CleanupFunc func(ctx context.Context) (int64, error)
+
+// maintenanceTask is a named cleanup routine run by the maintenance job
+type maintenanceTask struct {
+	name string
+	run  CleanupFunc
+}
+
+// AddCleanup registers a cleanup routine for the maintenance job, named by what
+// it removes ("expired tokens"). Routines run one after another in the order
+// they were added.
+func (s *Scheduler) AddCleanup(name string, fn CleanupFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cleanups = append(s.cleanups, maintenanceTask{name: name, run: fn})
+}
+
+// maintenance periodically runs the registered cleanup routines, until Stop
+func (s *Scheduler) maintenance() {
+	interval := s.config.MaintenanceInterval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// One replica cleans up per tick; the lock is left to expire
+			if s.locks != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				_, err := s.locks.Acquire(ctx, locks.ResourceScheduler, "maintenance", interval*9/10)
+				cancel()
+				if err != nil {
+					continue
+				}
+			}
+			s.runMaintenance()
+
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// runMaintenance runs each cleanup routine and logs what it removed. A failing
+// routine does not stop the ones after it.
+func (s *Scheduler) runMaintenance() {
+	s.mu.RLock()
+	cleanups := append([]maintenanceTask(nil), s.cleanups...)
+	s.mu.RUnlock()
+
+	for _, task := range cleanups {
+		ctx, cancel := context.WithTimeout(s.runCtx, maintenanceTimeout)
+		removed, err := task.run(ctx)
+		cancel()
+		if err != nil {
+			log.Printf("⚠️ Failed to clean up %s: %v", task.name, err)
+			continue
+		}
+		if removed > 0 {
+			log.Printf("🧹 Removed %d %s", removed, task.name)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/web3airdropos/backend/internal/locks"
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/proxypool"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+// proxyHealthChecks periodically tests active proxies, until Stop
+func (s *Scheduler) proxyHealthChecks() {
+	interval := s.config.ProxyHealthInterval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// One replica checks per tick; the lock is left to expire
+			if s.locks != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				_, err := s.locks.Acquire(ctx, locks.ResourceScheduler, "proxy-health", interval*9/10)
+				cancel()
+				if err != nil {
+					continue
+				}
+			}
+			s.checkProxyHealth()
+
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// checkProxyHealth tests every active proxy through the echo service and records
+// the result. Owners are notified when a proxy with active accounts goes down.
+func (s *Scheduler) checkProxyHealth() {
+	var proxies []models.Proxy
+	if err := s.db.Where("is_active = ?", true).Find(&proxies).Error; err != nil {
+		log.Printf("⚠️ Failed to load proxies for health checks: %v", err)
+		return
+	}
+
+	down := 0
+	for i := range proxies {
+		proxy := &proxies[i]
+
+		ctx, cancel := context.WithTimeout(s.runCtx, 15*time.Second)
+		result := proxypool.Check(ctx, proxy, s.config.ProxyEchoURL)
+		cancel()
+
+		wentDown, disabled := s.proxies.RecordCheck(proxy, result, s.config.ProxyDisableAfter)
+		if !result.Healthy {
+			down++
+		}
+		if wentDown || disabled {
+			s.notifyProxyDown(proxy, result, disabled)
+		}
+
+		select {
+		case <-s.stopChan:
+			return
+		default:
+		}
+	}
+
+	if down > 0 {
+		log.Printf("⚠️ %d of %d proxies failed their health check", down, len(proxies))
+	}
+}
+
+// notifyProxyDown tells the owner a proxy failed, if active accounts use it
+func (s *Scheduler) notifyProxyDown(proxy *models.Proxy, result *proxypool.CheckResult, disabled bool) {
+	var accounts int64
+	s.db.Model(&models.PlatformAccount{}).
+		Where("proxy_id = ? AND is_active = ?", proxy.ID, true).
+		Count(&accounts)
+	if accounts == 0 {
+		return
+	}
+
+	name := proxy.Name
+	if name == "" {
+		name = fmt.Sprintf("%s:%d", proxy.Host, proxy.Port)
+	}
+	message := fmt.Sprintf("🔌 Proxy %s is down (%d active account(s)): %s", name, accounts, result.Error)
+	if disabled {
+		message = fmt.Sprintf("🔌 Proxy %s was disabled after repeated failed checks (%d active account(s)): %s", name, accounts, result.Error)
+	}
+	details := map[string]interface{}{
+		"proxy_id":        proxy.ID,
+		"name":            proxy.Name,
+		"host":            proxy.Host,
+		"error":           result.Error,
+		"disabled":        disabled,
+		"active_accounts": accounts,
+	}
+
+	userID := proxy.UserID.String()
+	s.wsHub.BroadcastTerminal(userID, websocket.TerminalMessage{
+		Level:   "error",
+		Source:  "proxy",
+		Message: message,
+		Details: details,
+	})
+	s.wsHub.BroadcastToUser(userID, "proxy:down", details)
+	s.webhooks.Dispatch(proxy.UserID, models.WebhookEventProxyDown, details)
+}
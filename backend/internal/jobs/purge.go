@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/web3airdropos/backend/internal/locks"
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+// softDeletePurges periodically hard-deletes wallets and accounts deleted longer
+// ago than the retention period, until Stop
+func (s *Scheduler) softDeletePurges() {
+	interval := s.config.SoftDeletePurgeInterval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// One replica purges per tick; the lock is left to expire
+			if s.locks != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				_, err := s.locks.Acquire(ctx, locks.ResourceScheduler, "soft-delete-purge", interval*9/10)
+				cancel()
+				if err != nil {
+					continue
+				}
+			}
+			s.purgeSoftDeleted()
+
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// purgeSoftDeleted removes expired soft-deleted rows for good. A purged wallet's
+// encrypted key is gone with it; dependent rows follow by cascade.
+func (s *Scheduler) purgeSoftDeleted() {
+	cutoff := time.Now().Add(-s.config.SoftDeleteRetention)
+
+	wallets := s.db.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).Delete(&models.Wallet{})
+	if wallets.Error != nil {
+		log.Printf("⚠️ Failed to purge deleted wallets: %v", wallets.Error)
+	}
+
+	accounts := s.db.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).Delete(&models.PlatformAccount{})
+	if accounts.Error != nil {
+		log.Printf("⚠️ Failed to purge deleted accounts: %v", accounts.Error)
+	}
+
+	if wallets.RowsAffected > 0 || accounts.RowsAffected > 0 {
+		log.Printf("🗑️ Purged %d wallets and %d accounts deleted before %s", wallets.RowsAffected, accounts.RowsAffected, cutoff.Format(time.RFC3339))
+	}
+}
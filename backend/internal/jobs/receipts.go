@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/web3airdropos/backend/internal/locks"
+)
+
+// ReceiptPoller checks the receipts of task executions whose transaction is
+// still pending, completing or failing the ones that were mined or dropped
+type ReceiptPoller func(ctx context.Context) error
+
+// SetReceiptPoller sets how pending task transactions are checked. Without one
+// their executions stay verifying.
+func (s *Scheduler) SetReceiptPoller(poller ReceiptPoller) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.receipts = poller
+}
+
+// receiptPolls periodically runs the receipt poller, until Stop
+func (s *Scheduler) receiptPolls() {
+	interval := s.config.TxReceiptPollInterval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.RLock()
+			poller := s.receipts
+			s.mu.RUnlock()
+			if poller == nil {
+				continue
+			}
+
+			// One replica polls per tick; the lock is left to expire
+			if s.locks != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				_, err := s.locks.Acquire(ctx, locks.ResourceScheduler, "receipt-poll", interval*9/10)
+				cancel()
+				if err != nil {
+					continue
+				}
+			}
+			if err := poller(s.runCtx); err != nil {
+				log.Printf("⚠️ Failed to poll transaction receipts: %v", err)
+			}
+
+		case <-s.stopChan:
+			return
+		}
+	}
+}
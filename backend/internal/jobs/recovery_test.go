@@ -0,0 +1,58 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/config"
+	"github.com/web3airdropos/backend/internal/locks"
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+func TestRecoverStaleJobsResetsOnlyUnlockedRuns(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	db, mock := mockDB(t)
+	s := NewScheduler(db, client, websocket.NewHub(), &config.Config{})
+
+	leftover, elsewhere := uuid.New(), uuid.New()
+	lastRun := time.Now().Add(-time.Hour)
+
+	// Another replica is still running one of the jobs
+	other := locks.NewLockManager(client)
+	if _, err := other.Acquire(context.Background(), locks.ResourceJob, elsewhere.String(), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectQuery(`SELECT \* FROM "automation_jobs" WHERE \(status = .* AND \(last_run_at IS NULL OR last_run_at <= `).
+		WithArgs("running", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type", "name", "status", "last_run_at"}).
+			AddRow(leftover, uuid.New(), models.JobTypeBalanceSync, "Leftover", "running", lastRun).
+			AddRow(elsewhere, uuid.New(), models.JobTypeBalanceSync, "Elsewhere", "running", lastRun))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "automation_jobs" SET "next_run_at"=\$1,"status"=\$2`).
+		WithArgs(sqlmock.AnyArg(), "idle", sqlmock.AnyArg(), leftover, "running").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT \* FROM "task_executions"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	s.recoverStaleJobs(time.Minute)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Recovery released the lock it took on the leftover job
+	if _, err := other.Acquire(context.Background(), locks.ResourceJob, leftover.String(), time.Minute); err != nil {
+		t.Fatalf("leftover job is still locked after recovery: %v", err)
+	}
+}
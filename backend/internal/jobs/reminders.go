@@ -0,0 +1,149 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/web3airdropos/backend/internal/locks"
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+// deadlineReminders periodically reminds users of campaign deadlines, until Stop
+func (s *Scheduler) deadlineReminders() {
+	interval := s.config.CampaignReminderInterval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// One replica sends per tick; the lock is left to expire
+			if s.locks != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				_, err := s.locks.Acquire(ctx, locks.ResourceScheduler, "deadline-reminders", interval*9/10)
+				cancel()
+				if err != nil {
+					continue
+				}
+			}
+			s.sendDeadlineReminders(time.Now())
+
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// sendDeadlineReminders reminds the owner of every active campaign with incomplete
+// tasks whose deadline falls inside a reminder window not yet sent. A campaign
+// inside several unsent windows gets one reminder, for the closest.
+func (s *Scheduler) sendDeadlineReminders(now time.Time) {
+	windows := append([]time.Duration(nil), s.config.CampaignReminderWindows...)
+	if len(windows) == 0 {
+		return
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i] < windows[j] })
+
+	var campaigns []models.Campaign
+	if err := s.db.Where("status = ? AND deadline > ? AND deadline <= ?", "active", now, now.Add(windows[len(windows)-1])).
+		Find(&campaigns).Error; err != nil {
+		log.Printf("⚠️ Failed to load campaigns for deadline reminders: %v", err)
+		return
+	}
+
+	for i := range campaigns {
+		campaign := &campaigns[i]
+		left := campaign.Deadline.Sub(now)
+
+		var sent []string
+		if campaign.RemindersSent != "" {
+			_ = json.Unmarshal([]byte(campaign.RemindersSent), &sent)
+		}
+		already := make(map[string]bool, len(sent))
+		for _, label := range sent {
+			already[label] = true
+		}
+
+		// Windows are ascending, so the first one due is the closest
+		var due []string
+		for _, window := range windows {
+			if label := windowLabel(window); left <= window && !already[label] {
+				due = append(due, label)
+			}
+		}
+		if len(due) == 0 {
+			continue
+		}
+
+		remaining, err := s.incompleteTasks(campaign)
+		if err != nil || remaining == 0 {
+			continue
+		}
+
+		// Every due window counts as sent so a late check does not send a burst
+		recorded, _ := json.Marshal(append(sent, due...))
+		if err := s.db.Model(&models.Campaign{}).Where("id = ?", campaign.ID).
+			Update("reminders_sent", string(recorded)).Error; err != nil {
+			log.Printf("⚠️ Failed to record deadline reminder for campaign %s: %v", campaign.ID, err)
+			continue
+		}
+		s.remindDeadline(campaign, due[0], left, remaining)
+	}
+}
+
+// incompleteTasks counts the campaign's tasks with no completed execution
+func (s *Scheduler) incompleteTasks(campaign *models.Campaign) (int64, error) {
+	var total, done int64
+	if err := s.db.Model(&models.CampaignTask{}).Where("campaign_id = ?", campaign.ID).Count(&total).Error; err != nil {
+		return 0, err
+	}
+	if err := s.db.Model(&models.TaskExecution{}).
+		Joins("JOIN campaign_tasks ON campaign_tasks.id = task_executions.task_id").
+		Where("campaign_tasks.campaign_id = ? AND task_executions.status = ?", campaign.ID, "completed").
+		Distinct("task_executions.task_id").
+		Count(&done).Error; err != nil {
+		return 0, err
+	}
+	return total - done, nil
+}
+
+func (s *Scheduler) remindDeadline(campaign *models.Campaign, window string, left time.Duration, remaining int64) {
+	userID := campaign.UserID.String()
+	message := fmt.Sprintf("⏰ %s ends in %s with %d task(s) left", campaign.Name, left.Round(time.Minute), remaining)
+	details := map[string]interface{}{
+		"campaign_id":     campaign.ID,
+		"name":            campaign.Name,
+		"deadline":        campaign.Deadline,
+		"window":          window,
+		"tasks_remaining": remaining,
+	}
+
+	s.wsHub.BroadcastTerminal(userID, websocket.TerminalMessage{
+		Level:      "warn",
+		Source:     "campaign",
+		CampaignID: campaign.ID.String(),
+		Message:    message,
+		Details:    details,
+	})
+	s.wsHub.BroadcastToUser(userID, "campaign:deadline", details)
+	s.webhooks.Dispatch(campaign.UserID, models.WebhookEventCampaignDeadline, details)
+}
+
+// windowLabel names a reminder window compactly, e.g. "24h" or "30m"
+func windowLabel(window time.Duration) string {
+	switch {
+	case window%time.Hour == 0:
+		return fmt.Sprintf("%dh", window/time.Hour)
+	case window%time.Minute == 0:
+		return fmt.Sprintf("%dm", window/time.Minute)
+	}
+	return window.String()
+}
@@ -0,0 +1,72 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+// scheduledTaskTimeout bounds one scheduled task execution
+const scheduledTaskTimeout = 10 * time.Minute
+
+// ScheduledTaskRunner executes a claimed scheduled task execution through the
+// normal task execution path and records its outcome
+type ScheduledTaskRunner func(ctx context.Context, scheduled *models.ScheduledTaskExecution) error
+
+// SetScheduledTaskRunner sets how due scheduled task executions are run. Without
+// one they stay pending.
+func (s *Scheduler) SetScheduledTaskRunner(runner ScheduledTaskRunner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.taskRunner = runner
+}
+
+// runDueScheduledTasks claims the scheduled task executions whose time has come
+// and runs them one after another in the background. Claiming flips the status
+// from pending, so a cancellation or another replica racing for the same row
+// loses cleanly.
+func (s *Scheduler) runDueScheduledTasks(now time.Time) {
+	s.mu.RLock()
+	runner := s.taskRunner
+	s.mu.RUnlock()
+	if runner == nil {
+		return
+	}
+
+	var due []models.ScheduledTaskExecution
+	if err := s.db.Where("status = ? AND run_at <= ?", "pending", now).
+		Order("run_at ASC").
+		Find(&due).Error; err != nil {
+		log.Printf("⚠️ Failed to load scheduled task executions: %v", err)
+		return
+	}
+
+	var claimed []models.ScheduledTaskExecution
+	for _, scheduled := range due {
+		result := s.db.Model(&models.ScheduledTaskExecution{}).
+			Where("id = ? AND status = ?", scheduled.ID, "pending").
+			Updates(map[string]interface{}{"status": "running", "updated_at": now})
+		if result.Error == nil && result.RowsAffected > 0 {
+			scheduled.Status = "running"
+			claimed = append(claimed, scheduled)
+		}
+	}
+	if len(claimed) == 0 {
+		return
+	}
+
+	go func() {
+		for i := range claimed {
+			scheduled := &claimed[i]
+			ctx, cancel := context.WithTimeout(s.runCtx, scheduledTaskTimeout)
+			if err := runner(ctx, scheduled); err != nil {
+				log.Printf("❌ Scheduled execution %s of task %s failed: %v", scheduled.ID, scheduled.TaskID, err)
+			} else {
+				log.Printf("✅ Ran scheduled execution %s of task %s", scheduled.ID, scheduled.TaskID)
+			}
+			cancel()
+		}
+	}()
+}
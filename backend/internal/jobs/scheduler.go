@@ -4,42 +4,120 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 
+	"github.com/web3airdropos/backend/internal/accounthealth"
+	"github.com/web3airdropos/backend/internal/balances"
 	"github.com/web3airdropos/backend/internal/config"
+	"github.com/web3airdropos/backend/internal/explorer"
+	"github.com/web3airdropos/backend/internal/locks"
+	"github.com/web3airdropos/backend/internal/logger"
+	"github.com/web3airdropos/backend/internal/metrics"
 	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/proxypool"
+	"github.com/web3airdropos/backend/internal/readretry"
+	"github.com/web3airdropos/backend/internal/requestid"
+	"github.com/web3airdropos/backend/internal/rpc"
+	"github.com/web3airdropos/backend/internal/services/ai"
+	"github.com/web3airdropos/backend/internal/services/platforms"
+	"github.com/web3airdropos/backend/internal/tasks"
+	"github.com/web3airdropos/backend/internal/tracing"
+	"github.com/web3airdropos/backend/internal/webhooks"
 	"github.com/web3airdropos/backend/internal/websocket"
 )
 
 // Scheduler manages all background jobs
 type Scheduler struct {
-	db       *gorm.DB
-	redis    *redis.Client
-	wsHub    *websocket.Hub
-	cron     *cron.Cron
-	config   *config.Config
-	workers  map[string]*Worker
-	jobQueue chan *JobContext
-	stopChan chan struct{}
-	mu       sync.RWMutex
+	db         *gorm.DB
+	redis      *redis.Client
+	wsHub      *websocket.Hub
+	locks      *locks.LockManager // Nil without Redis; jobs then run unlocked
+	rpc        *rpc.Resolver
+	balances   *balances.Fetcher
+	explorer   *explorer.Client
+	webhooks   *webhooks.Dispatcher
+	health     *accounthealth.Checker
+	proxies    *proxypool.Pool
+	cron       *cron.Cron
+	config     *config.Config
+	ai         ai.Provider
+	moderator  ai.Moderator
+	retry      tasks.RetryConfig
+	taskRunner ScheduledTaskRunner // Runs due scheduled task executions; nil leaves them pending
+	cleanups   []maintenanceTask   // Routines the maintenance job runs
+	receipts   ReceiptPoller       // Checks sent task transactions; nil leaves them verifying
+	workers    map[string]*Worker
+	jobQueue   chan *JobContext
+	stopChan   chan struct{}
+	stopOnce   sync.Once
+	busy       int32             // Workers currently processing a job
+	inFlight   map[uuid.UUID]int // Queued or running jobs per user
+	mu         sync.RWMutex
+
+	// Shutdown draining: job contexts derive from runCtx, which Stop cancels
+	// once the drain timeout passes
+	runCtx     context.Context
+	cancelRuns context.CancelFunc
+	workerWG   sync.WaitGroup
+	running    map[uuid.UUID]*JobContext // Jobs a worker is processing, by job ID
 }
 
+const (
+	// jobLockMargin is how much longer than its run timeout a job lock lasts, so
+	// the lock outlives the run it guards
+	jobLockMargin = 5 * time.Minute
+
+	// jobCheckLockTTL keeps other replicas from running the due job check in the same minute
+	jobCheckLockTTL = 50 * time.Second
+
+	// recoveryLockTTL bounds a stale job recovery pass
+	recoveryLockTTL = time.Minute
+)
+
+// ErrUnknownJobType is returned when enqueueing a job the scheduler has no handler for
+var ErrUnknownJobType = errors.New("unknown job type")
+
+// userLimitRetryDelay is how long a job deferred by the per-user cap waits before trying again
+const userLimitRetryDelay = 15 * time.Second
+
 // JobContext contains all context for a job execution
 type JobContext struct {
 	Job         *models.AutomationJob
 	UserID      uuid.UUID
 	ExecutionID uuid.UUID
 	Cancel      context.CancelFunc
+	Lock        *locks.DistributedLock // Held until the run completes
+	Attempt     int                    // 0 on the first run, incremented on each retry
+	NoRetry     bool                   // Set when retrying cannot help, e.g. an unknown job type
+	ErrorCode   string                 // Why the run failed, when known, e.g. TIMEOUT
+	Log         zerolog.Logger         // Tagged with the job, its user and request; set when the run starts
+	RequestID   string                 // HTTP request that started the job, if any
+	TraceParent string                 // Trace context of that request, so the run joins its trace
+}
+
+// jobOrigin ties a queued job to the HTTP request that started it, and follows the
+// job through deferrals and retries
+type jobOrigin struct {
+	RequestID   string
+	TraceParent string
 }
 
 // Worker processes jobs from the queue
@@ -55,22 +133,62 @@ type JobHandler func(ctx context.Context, jctx *JobContext, s *Scheduler) error
 
 // NewScheduler creates a new job scheduler
 func NewScheduler(db *gorm.DB, redis *redis.Client, wsHub *websocket.Hub, cfg *config.Config) *Scheduler {
+	var lockManager *locks.LockManager
+	if redis != nil {
+		lockManager = locks.NewLockManager(redis)
+	}
+	runCtx, cancelRuns := context.WithCancel(context.Background())
+	resolver := rpc.NewResolver(db, cfg)
+
 	return &Scheduler{
-		db:       db,
-		redis:    redis,
-		wsHub:    wsHub,
-		cron:     cron.New(cron.WithSeconds()),
-		config:   cfg,
-		workers:  make(map[string]*Worker),
-		jobQueue: make(chan *JobContext, 100),
-		stopChan: make(chan struct{}),
+		db:        db,
+		redis:     redis,
+		wsHub:     wsHub,
+		locks:     lockManager,
+		rpc:       resolver,
+		balances:  balances.NewFetcher(resolver, cfg),
+		explorer:  explorer.NewClient(cfg),
+		webhooks:  webhooks.NewDispatcher(db),
+		health:    accounthealth.NewChecker(db, wsHub, cfg),
+		proxies:   proxypool.New(db),
+		cron:      cron.New(cron.WithSeconds()),
+		config:    cfg,
+		ai:        ai.NewFromConfig(cfg),
+		moderator: ai.NewModeratorFromConfig(cfg),
+		retry:     jobRetryConfig(cfg),
+		workers:   make(map[string]*Worker),
+		inFlight:  make(map[uuid.UUID]int),
+		jobQueue:  make(chan *JobContext, 100),
+		stopChan:  make(chan struct{}),
+
+		runCtx:     runCtx,
+		cancelRuns: cancelRuns,
+		running:    make(map[uuid.UUID]*JobContext),
 	}
 }
 
+// jobRetryConfig builds the job retry policy; a zero JOB_MAX_RETRIES dead-letters on the first failure
+func jobRetryConfig(cfg *config.Config) tasks.RetryConfig {
+	retry := tasks.DefaultRetryConfig()
+	if cfg.JobMaxRetries >= 0 {
+		retry.MaxRetries = cfg.JobMaxRetries
+	}
+	if cfg.JobRetryBaseBackoff > 0 {
+		retry.BaseBackoff = cfg.JobRetryBaseBackoff
+	}
+	if cfg.JobRetryMaxBackoff > 0 {
+		retry.MaxBackoff = cfg.JobRetryMaxBackoff
+	}
+	return retry
+}
+
 // Start starts the scheduler
 func (s *Scheduler) Start() {
 	log.Println("🚀 Starting job scheduler...")
 
+	// Jobs left "running" by a previous process would never be picked up again
+	s.recoverStaleJobs(0)
+
 	// Start cron scheduler
 	s.cron.Start()
 
@@ -79,6 +197,7 @@ func (s *Scheduler) Start() {
 
 	// Start worker pool
 	numWorkers := 5
+	s.mu.Lock()
 	for i := 0; i < numWorkers; i++ {
 		worker := &Worker{
 			id:       i,
@@ -87,8 +206,10 @@ func (s *Scheduler) Start() {
 			handlers: s.getJobHandlers(),
 		}
 		s.workers[uuid.New().String()] = worker
+		s.workerWG.Add(1)
 		go worker.run(s)
 	}
+	s.mu.Unlock()
 
 	// Start job checker (checks for pending jobs every minute)
 	go s.jobChecker()
@@ -96,17 +217,204 @@ func (s *Scheduler) Start() {
 	// Start Redis queue listener
 	go s.redisQueueListener()
 
+	// Remind users of approaching campaign deadlines
+	if len(s.config.CampaignReminderWindows) > 0 {
+		go s.deadlineReminders()
+	}
+
+	// Deactivate accounts their platform has suspended
+	if s.config.AccountHealthInterval > 0 {
+		go s.accountHealthChecks()
+	}
+
+	// Test proxies and deactivate the ones that keep failing
+	if s.config.ProxyHealthInterval > 0 {
+		go s.proxyHealthChecks()
+	}
+
+	// Purge wallets and accounts deleted past the retention period
+	if s.config.SoftDeletePurgeInterval > 0 {
+		go s.softDeletePurges()
+	}
+
+	// Remove expired tokens, old audit logs and other rows past their retention
+	if s.config.MaintenanceInterval > 0 {
+		go s.maintenance()
+	}
+
+	// Complete or fail task executions once their transaction is mined
+	if s.config.TxReceiptPollInterval > 0 {
+		go s.receiptPolls()
+	}
+
 	log.Println("✅ Job scheduler started")
 }
 
+// QueueLength returns the number of jobs waiting for a worker
+func (s *Scheduler) QueueLength() int {
+	return len(s.jobQueue)
+}
+
+// WorkerCount returns the number of started workers
+func (s *Scheduler) WorkerCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.workers)
+}
+
+// BusyWorkers returns the number of workers processing a job
+func (s *Scheduler) BusyWorkers() int {
+	return int(atomic.LoadInt32(&s.busy))
+}
+
 // Stop stops the scheduler
+// Stop stops accepting jobs and waits up to JobShutdownTimeout for running jobs to
+// finish. Jobs still running after that are cancelled and, like jobs still waiting
+// for a worker, set back to idle so they run again after restart.
 func (s *Scheduler) Stop() {
+	s.stopOnce.Do(s.stop)
+}
+
+func (s *Scheduler) stop() {
 	log.Println("🛑 Stopping job scheduler...")
 	close(s.stopChan)
 	s.cron.Stop()
+
+	s.mu.RLock()
 	for _, worker := range s.workers {
 		close(worker.stop)
 	}
+	s.mu.RUnlock()
+
+	drained := make(chan struct{})
+	go func() {
+		s.workerWG.Wait()
+		close(drained)
+	}()
+
+	timeout := s.config.JobShutdownTimeout
+	if timeout <= 0 {
+		timeout = 25 * time.Second
+	}
+	select {
+	case <-drained:
+		log.Println("✅ Running jobs finished")
+	case <-time.After(timeout):
+		s.mu.RLock()
+		log.Printf("⚠️ %d job(s) still running after %s, cancelling", len(s.running), timeout)
+		s.mu.RUnlock()
+		s.cancelRuns()
+	}
+
+	s.releaseUnfinishedJobs()
+	s.cancelRuns()
+	s.rpc.Close()
+	s.balances.Close()
+	s.webhooks.Close()
+	log.Println("✅ Job scheduler stopped")
+}
+
+// releaseUnfinishedJobs resets jobs that were queued or cut off by shutdown so
+// the next start (or another replica) picks them up again
+func (s *Scheduler) releaseUnfinishedJobs() {
+	var unfinished []*JobContext
+	for {
+		select {
+		case jctx := <-s.jobQueue:
+			s.releaseUserSlot(jctx.UserID)
+			unfinished = append(unfinished, jctx)
+			continue
+		default:
+		}
+		break
+	}
+	s.mu.RLock()
+	for _, jctx := range s.running {
+		unfinished = append(unfinished, jctx)
+	}
+	s.mu.RUnlock()
+
+	for _, jctx := range unfinished {
+		if jctx.Cancel != nil {
+			jctx.Cancel()
+		}
+		s.db.Model(&models.AutomationJob{}).
+			Where("id = ? AND status = ?", jctx.Job.ID, "running").
+			Updates(map[string]interface{}{"status": "idle", "next_run_at": time.Now()})
+		s.releaseJobLock(jctx.Lock)
+		log.Printf("↩️ Job %s (%s) released for resumption", jctx.Job.Name, jctx.Job.ID)
+	}
+}
+
+// recoverStaleJobs resets jobs and task executions left running by a process that
+// died mid-run. Only jobs that have been running for at least minAge are examined;
+// with Redis, a job whose lock is still held is running on another replica and is
+// left alone. One replica performs recovery at a time.
+func (s *Scheduler) recoverStaleJobs(minAge time.Duration) {
+	if s.locks != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		lock, err := s.locks.Acquire(ctx, locks.ResourceScheduler, "recovery", recoveryLockTTL)
+		cancel()
+		if err != nil {
+			return
+		}
+		defer s.releaseJobLock(lock)
+	}
+
+	var stale []models.AutomationJob
+	if err := s.db.Where("status = ? AND (last_run_at IS NULL OR last_run_at <= ?)", "running", time.Now().Add(-minAge)).
+		Find(&stale).Error; err != nil {
+		log.Printf("⚠️ Failed to look up stale jobs: %v", err)
+		return
+	}
+
+	for _, job := range stale {
+		lock, err := s.acquireJobLock(&job)
+		if err != nil {
+			continue
+		}
+		result := s.db.Model(&models.AutomationJob{}).
+			Where("id = ? AND status = ?", job.ID, "running").
+			Updates(map[string]interface{}{"status": "idle", "next_run_at": time.Now()})
+		s.releaseJobLock(lock)
+		if result.RowsAffected > 0 {
+			log.Printf("♻️ Recovered stale running job %s (%s), last run %v", job.Name, job.ID, job.LastRunAt)
+		}
+	}
+
+	// Executions have no lock to consult, so they must be older than any run could last.
+	// A lone process at startup knows nothing else is running them.
+	cutoff := s.maxJobLockTTL()
+	if s.locks == nil && minAge == 0 {
+		cutoff = 0
+	}
+	s.recoverStaleExecutions(time.Now().Add(-cutoff))
+}
+
+// recoverStaleExecutions fails task executions stuck running since before cutoff,
+// which makes them retryable
+func (s *Scheduler) recoverStaleExecutions(cutoff time.Time) {
+	var stale []models.TaskExecution
+	running := models.ExecutionStatusValues(models.ExecutionInProgress)
+	if err := s.db.Where("status::text IN ? AND started_at <= ?", running, cutoff).
+		Find(&stale).Error; err != nil {
+		log.Printf("⚠️ Failed to look up stale task executions: %v", err)
+		return
+	}
+
+	for _, execution := range stale {
+		result := s.db.Model(&models.TaskExecution{}).
+			Where("id = ? AND status IN ?", execution.ID, running).
+			Updates(map[string]interface{}{
+				"status":        models.ExecutionFailed,
+				"error_message": "Execution was interrupted before it finished",
+				"error_code":    tasks.ErrorCodeInterrupted,
+				"completed_at":  time.Now(),
+			})
+		if result.RowsAffected > 0 {
+			log.Printf("♻️ Marked stale task execution %s (task %s) as failed", execution.ID, execution.TaskID)
+		}
+	}
 }
 
 func (s *Scheduler) loadScheduledJobs() {
@@ -124,6 +432,10 @@ func (s *Scheduler) scheduleJob(job *models.AutomationJob) {
 	if job.CronExpression == "" {
 		return
 	}
+	if !s.hasHandler(job.Type) {
+		s.deactivateUnknownJob(job)
+		return
+	}
 
 	_, err := s.cron.AddFunc(job.CronExpression, func() {
 		s.EnqueueJob(job.ID)
@@ -136,18 +448,51 @@ func (s *Scheduler) scheduleJob(job *models.AutomationJob) {
 
 // EnqueueJob adds a job to the processing queue
 func (s *Scheduler) EnqueueJob(jobID uuid.UUID) error {
+	return s.enqueue(jobID, 0, jobOrigin{})
+}
+
+func (s *Scheduler) enqueue(jobID uuid.UUID, attempt int, origin jobOrigin) error {
 	var job models.AutomationJob
 	if err := s.db.First(&job, jobID).Error; err != nil {
 		return err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	// Retrying cannot help a job no handler runs, so it is switched off rather
+	// than failed on every check
+	if !s.hasHandler(job.Type) {
+		s.deactivateUnknownJob(&job)
+		return fmt.Errorf("%w %q for job %s", ErrUnknownJobType, job.Type, job.ID)
+	}
+
+	// Every replica hears the Redis queue and runs the job checker, so only
+	// the one holding the job lock runs it
+	lock, err := s.acquireJobLock(&job)
+	if err != nil {
+		if errors.Is(err, locks.ErrLockNotAcquired) {
+			log.Printf("⏭️ Job %s is already queued or running elsewhere, skipping", job.ID)
+			return nil
+		}
+		return err
+	}
+
+	// Keep one user's bulk runs from starving everyone else on the shared workers
+	if !s.acquireUserSlot(job.UserID) {
+		s.releaseJobLock(lock)
+		s.deferJob(&job, attempt, origin)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.jobTimeout(&job))
 
 	jctx := &JobContext{
 		Job:         &job,
 		UserID:      job.UserID,
 		ExecutionID: uuid.New(),
 		Cancel:      cancel,
+		Lock:        lock,
+		Attempt:     attempt,
+		RequestID:   origin.RequestID,
+		TraceParent: origin.TraceParent,
 	}
 
 	// Update job status
@@ -169,15 +514,119 @@ func (s *Scheduler) EnqueueJob(jobID uuid.UUID) error {
 		return nil
 	case <-ctx.Done():
 		cancel()
+		s.releaseUserSlot(job.UserID)
+		s.releaseJobLock(lock)
 		return ctx.Err()
+	case <-s.stopChan:
+		cancel()
+		s.releaseUserSlot(job.UserID)
+		s.releaseJobLock(lock)
+		s.db.Model(&job).Update("status", "idle")
+		return errors.New("scheduler is stopping")
+	}
+}
+
+// acquireJobLock takes the distributed lock for one run of a job. It covers the
+// wait for a worker; processJob extends it to cover the run itself.
+func (s *Scheduler) acquireJobLock(job *models.AutomationJob) (*locks.DistributedLock, error) {
+	if s.locks == nil {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.locks.Acquire(ctx, locks.ResourceJob, job.ID.String(), s.jobLockTTL(job))
+}
+
+// jobLockTTL is how long a job's lock lasts: its run timeout plus a margin
+func (s *Scheduler) jobLockTTL(job *models.AutomationJob) time.Duration {
+	return s.jobTimeout(job) + jobLockMargin
+}
+
+// maxJobLockTTL is the longest any job lock can last, since validation caps
+// job timeouts at JobMaxTimeout
+func (s *Scheduler) maxJobLockTTL() time.Duration {
+	longest := max(defaultJobTimeout, s.jobTimeout(&models.AutomationJob{}))
+	if s.config != nil {
+		longest = max(longest, s.config.JobMaxTimeout)
+	}
+	return longest + jobLockMargin
+}
+
+// releaseJobLock releases a lock taken by acquireJobLock
+func (s *Scheduler) releaseJobLock(lock *locks.DistributedLock) {
+	if lock == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := lock.Release(ctx); err != nil && !errors.Is(err, locks.ErrLockNotOwned) {
+		log.Printf("⚠️  Failed to release job lock: %v", err)
+	}
+}
+
+// acquireUserSlot reserves one of the user's concurrent job slots
+func (s *Scheduler) acquireUserSlot(userID uuid.UUID) bool {
+	limit := s.config.JobMaxConcurrentPerUser
+	if limit <= 0 {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inFlight[userID] >= limit {
+		return false
+	}
+	s.inFlight[userID]++
+	return true
+}
+
+// releaseUserSlot frees a slot taken by acquireUserSlot
+func (s *Scheduler) releaseUserSlot(userID uuid.UUID) {
+	if s.config.JobMaxConcurrentPerUser <= 0 {
+		return
 	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inFlight[userID] <= 1 {
+		delete(s.inFlight, userID)
+		return
+	}
+	s.inFlight[userID]--
+}
+
+// deferJob parks a job that hit the per-user cap and tries it again shortly
+func (s *Scheduler) deferJob(job *models.AutomationJob, attempt int, origin jobOrigin) {
+	if job.Status != "deferred" {
+		s.db.Model(job).Update("status", "deferred")
+		s.wsHub.BroadcastTerminal(job.UserID.String(), websocket.TerminalMessage{
+			Level:   "warn",
+			Source:  "job",
+			JobID:   job.ID.String(),
+			Message: fmt.Sprintf("Job %s deferred: %d of your jobs are already running", job.Name, s.config.JobMaxConcurrentPerUser),
+		})
+	}
+
+	jobID := job.ID
+	time.AfterFunc(userLimitRetryDelay, func() {
+		select {
+		case <-s.stopChan:
+			return
+		default:
+		}
+		if err := s.enqueue(jobID, attempt, origin); err != nil {
+			log.Printf("❌ Failed to enqueue deferred job %s: %v", jobID, err)
+		}
+	})
 }
 
 // EnqueueJobFromRedis adds a job from Redis queue
 func (s *Scheduler) EnqueueJobFromRedis(data string) error {
 	var payload struct {
-		JobID  string `json:"job_id"`
-		UserID string `json:"user_id"`
+		JobID       string `json:"job_id"`
+		UserID      string `json:"user_id"`
+		RequestID   string `json:"request_id"`
+		TraceParent string `json:"traceparent"`
 	}
 	if err := json.Unmarshal([]byte(data), &payload); err != nil {
 		return err
@@ -188,7 +637,7 @@ func (s *Scheduler) EnqueueJobFromRedis(data string) error {
 		return err
 	}
 
-	return s.EnqueueJob(jobID)
+	return s.enqueue(jobID, 0, jobOrigin{RequestID: payload.RequestID, TraceParent: payload.TraceParent})
 }
 
 func (s *Scheduler) jobChecker() {
@@ -198,15 +647,34 @@ func (s *Scheduler) jobChecker() {
 	for {
 		select {
 		case <-ticker.C:
+			// One replica checks per tick; the lock is left to expire
+			if s.locks != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				_, err := s.locks.Acquire(ctx, locks.ResourceScheduler, "job-checker", jobCheckLockTTL)
+				cancel()
+				if err != nil {
+					continue
+				}
+			}
+
 			// Check for jobs that should be run
 			var jobs []models.AutomationJob
-			s.db.Where("is_active = ? AND next_run_at <= ? AND status != ?",
-				true, time.Now(), "running").Find(&jobs)
+			s.db.Where("is_active = ? AND next_run_at <= ? AND status NOT IN ?",
+				true, time.Now(), []string{"running", "retrying", "deferred"}).Find(&jobs)
 
 			for _, job := range jobs {
 				s.EnqueueJob(job.ID)
 			}
 
+			// One-off task executions scheduled for a later time
+			s.runDueScheduledTasks(time.Now())
+
+			// Runs orphaned by a crashed replica surface here; without Redis there
+			// is no lock to tell them apart from this process's own, so only startup recovers
+			if s.locks != nil {
+				s.recoverStaleJobs(s.config.JobStaleAfter)
+			}
+
 		case <-s.stopChan:
 			return
 		}
@@ -231,9 +699,18 @@ func (s *Scheduler) redisQueueListener() {
 }
 
 func (w *Worker) run(s *Scheduler) {
+	defer s.workerWG.Done()
 	log.Printf("👷 Worker %d started", w.id)
 
 	for {
+		// Don't pick up new work once stopping, even if the queue is non-empty
+		select {
+		case <-w.stop:
+			log.Printf("👷 Worker %d stopped", w.id)
+			return
+		default:
+		}
+
 		select {
 		case jctx := <-w.queue:
 			w.processJob(jctx, s)
@@ -245,23 +722,44 @@ func (w *Worker) run(s *Scheduler) {
 }
 
 func (w *Worker) processJob(jctx *JobContext, s *Scheduler) {
+	atomic.AddInt32(&s.busy, 1)
+	defer atomic.AddInt32(&s.busy, -1)
+
 	startTime := time.Now()
-	log.Printf("⚙️ Worker %d processing job: %s (%s)", w.id, jctx.Job.Name, jctx.Job.Type)
+	jctx.Log = logger.ForJob(jctx.UserID, jctx.Job.ID, string(jctx.Job.Type), jctx.RequestID).
+		With().Int("attempt", jctx.Attempt).Logger()
+	jctx.Log.Info().Int("worker", w.id).Str("job_name", jctx.Job.Name).Msg("Processing job")
+
+	// The lock was taken when the job was queued; restart it so it outlasts
+	// this run. A lock that expired in the queue may now be another replica's.
+	timeout := s.jobTimeout(jctx.Job)
+	if jctx.Lock != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := jctx.Lock.Extend(ctx, timeout+jobLockMargin)
+		cancel()
+		if errors.Is(err, locks.ErrLockExpired) {
+			jctx.Cancel()
+			s.releaseUserSlot(jctx.UserID)
+			jctx.Log.Warn().Msg("Job lock expired while queued, skipping run")
+			return
+		}
+		if err != nil {
+			jctx.Log.Warn().Err(err).Msg("Failed to extend job lock")
+		}
+	}
 
 	// Create log entry
-	jobLog := &models.JobLog{
-		ID:        uuid.New(),
-		JobID:     jctx.Job.ID,
-		Level:     "info",
-		Message:   "Job started",
-		CreatedAt: time.Now(),
+	var details map[string]interface{}
+	if jctx.RequestID != "" {
+		details = map[string]interface{}{requestid.Key: jctx.RequestID}
 	}
-	s.db.Create(jobLog)
+	s.logJob(jctx.Job.ID, models.JobLogInfo, "Job started", details)
 
 	// Send terminal message
 	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 		Level:   "info",
 		Source:  "job",
+		JobID:   jctx.Job.ID.String(),
 		Message: "Starting job: " + jctx.Job.Name,
 		Details: map[string]interface{}{
 			"job_id": jctx.Job.ID,
@@ -269,12 +767,34 @@ func (w *Worker) processJob(jctx *JobContext, s *Scheduler) {
 		},
 	})
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	ctx, cancel := context.WithTimeout(logger.NewContext(requestid.WithContext(s.runCtx, jctx.RequestID), jctx.Log), timeout)
 	defer cancel()
+	go s.warnNearDeadline(ctx, jctx, timeout)
+
+	// The run is a child of the request that queued it, so its DB, RPC and platform
+	// calls show up under that request's trace
+	ctx, span := tracing.Start(tracing.Extract(ctx, jctx.TraceParent), "job."+string(jctx.Job.Type),
+		trace.WithAttributes(
+			attribute.String("job.id", jctx.Job.ID.String()),
+			attribute.String("job.type", string(jctx.Job.Type)),
+			attribute.String("user.id", jctx.UserID.String()),
+			attribute.Int("job.attempt", jctx.Attempt),
+		))
+	defer span.End()
+
+	s.mu.Lock()
+	s.running[jctx.Job.ID] = jctx
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, jctx.Job.ID)
+		s.mu.Unlock()
+	}()
 
 	// Get handler for job type
 	handler, ok := w.handlers[jctx.Job.Type]
 	if !ok {
+		jctx.NoRetry = true
 		s.completeJob(jctx, "failed", "Unknown job type", startTime)
 		return
 	}
@@ -282,19 +802,105 @@ func (w *Worker) processJob(jctx *JobContext, s *Scheduler) {
 	// Execute job
 	err := handler(ctx, jctx, s)
 	if err != nil {
-		s.completeJob(jctx, "failed", err.Error(), startTime)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		message := err.Error()
+		// Running out of time again would not help, so a timeout fails for good
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			jctx.NoRetry = true
+			jctx.ErrorCode = tasks.ErrorCodeTimeout
+			message = fmt.Sprintf("Job timed out after %s: %v", timeout, err)
+		}
+		s.completeJob(jctx, "failed", message, startTime)
 		return
 	}
 
 	s.completeJob(jctx, "completed", "Job completed successfully", startTime)
 }
 
+// defaultJobTimeout applies when neither the job nor the config sets a timeout
+const defaultJobTimeout = 30 * time.Minute
+
+// jobTimeout returns how long one run of a job may take
+func (s *Scheduler) jobTimeout(job *models.AutomationJob) time.Duration {
+	if job.TimeoutSeconds > 0 {
+		return time.Duration(job.TimeoutSeconds) * time.Second
+	}
+	if s.config != nil && s.config.JobDefaultTimeout > 0 {
+		return s.config.JobDefaultTimeout
+	}
+	return defaultJobTimeout
+}
+
+// warnNearDeadline tells the terminal how long a running job has left: once a
+// fifth of its timeout remains, and again a minute before for longer runs.
+// It returns when ctx is done.
+func (s *Scheduler) warnNearDeadline(ctx context.Context, jctx *JobContext, timeout time.Duration) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+	warnings := []time.Duration{timeout / 5}
+	if timeout >= 10*time.Minute {
+		warnings = append(warnings, time.Minute)
+	}
+
+	for _, remaining := range warnings {
+		timer := time.NewTimer(time.Until(deadline.Add(-remaining)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+			Level:   "warn",
+			Source:  "job",
+			JobID:   jctx.Job.ID.String(),
+			Message: fmt.Sprintf("⏳ %s has %s left before it times out", jctx.Job.Name, time.Until(deadline).Round(time.Second)),
+			Details: map[string]interface{}{
+				"deadline": deadline,
+				"timeout":  timeout.String(),
+			},
+		})
+	}
+}
+
+// logJob writes a job log entry. Every job log goes through here so details are
+// always stored as valid JSON.
+func (s *Scheduler) logJob(jobID uuid.UUID, level, message string, details map[string]interface{}) {
+	if err := s.db.Create(models.NewJobLog(jobID, level, message, details)).Error; err != nil {
+		log.Printf("⚠️ Failed to write log for job %s: %v", jobID, err)
+	}
+}
+
 func (s *Scheduler) completeJob(jctx *JobContext, status, message string, startTime time.Time) {
 	duration := time.Since(startTime)
+	s.releaseUserSlot(jctx.UserID)
+	defer s.releaseJobLock(jctx.Lock)
+
+	// A run cut off by shutdown is resumed later rather than counted as a failure
+	if status == "failed" && s.runCtx.Err() != nil {
+		s.db.Model(&jctx.Job).Updates(map[string]interface{}{"status": "idle", "next_run_at": time.Now()})
+		jctx.Log.Warn().Str("reason", message).Msg("Job interrupted by shutdown")
+		return
+	}
+
+	// Failed runs are retried with backoff, then dead-lettered
+	failed := status == "failed"
+	retry := failed && !jctx.NoRetry && jctx.Attempt < s.retry.MaxRetries
+
+	logEvent := jctx.Log.Info()
+	if failed {
+		logEvent = jctx.Log.Warn().Str("error_code", jctx.ErrorCode).Bool("retry", retry)
+	}
+	logEvent.Str("status", status).Dur("duration", duration).Msg(message)
 
 	// Update job status
 	updates := map[string]interface{}{
 		"status":     "idle",
+		"error_code": jctx.ErrorCode,
 		"total_runs": gorm.Expr("total_runs + 1"),
 	}
 
@@ -303,41 +909,192 @@ func (s *Scheduler) completeJob(jctx *JobContext, status, message string, startT
 	} else {
 		updates["failed_runs"] = gorm.Expr("failed_runs + 1")
 	}
+	switch {
+	case retry:
+		updates["status"] = "retrying"
+	case failed:
+		updates["status"] = "failed"
+	}
 
 	s.db.Model(&jctx.Job).Updates(updates)
 
+	metrics.JobsProcessed.WithLabelValues(string(jctx.Job.Type), status).Inc()
+	metrics.JobDuration.WithLabelValues(string(jctx.Job.Type)).Observe(duration.Seconds())
+
 	// Log completion
-	level := "success"
+	level := models.JobLogSuccess
 	if status == "failed" {
-		level = "error"
+		level = models.JobLogError
 	}
 
-	s.db.Create(&models.JobLog{
-		ID:        uuid.New(),
-		JobID:     jctx.Job.ID,
-		Level:     level,
-		Message:   message,
-		Details:   `{"duration_ms": ` + string(rune(duration.Milliseconds())) + `}`,
-		CreatedAt: time.Now(),
-	})
+	s.logJob(jctx.Job.ID, level, message, map[string]interface{}{"duration_ms": duration.Milliseconds()})
 
 	// Notify via WebSocket
 	s.wsHub.BroadcastToUser(jctx.UserID.String(), "job:completed", map[string]interface{}{
-		"job_id":   jctx.Job.ID,
-		"status":   status,
-		"message":  message,
-		"duration": duration.String(),
+		"job_id":     jctx.Job.ID,
+		"status":     status,
+		"message":    message,
+		"error_code": jctx.ErrorCode,
+		"duration":   duration.String(),
 	})
 
 	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 		Level:   level,
 		Source:  "job",
+		JobID:   jctx.Job.ID.String(),
 		Message: message,
 		Details: map[string]interface{}{
 			"job_id":   jctx.Job.ID,
 			"duration": duration.String(),
 		},
 	})
+
+	if retry {
+		s.scheduleRetry(jctx)
+		return
+	}
+	if failed {
+		s.deadLetter(jctx, message)
+	}
+
+	event := models.WebhookEventJobCompleted
+	if failed {
+		event = models.WebhookEventJobFailed
+	}
+	s.webhooks.Dispatch(jctx.UserID, event, map[string]interface{}{
+		"job_id":     jctx.Job.ID,
+		"name":       jctx.Job.Name,
+		"type":       jctx.Job.Type,
+		"status":     status,
+		"message":    message,
+		"error_code": jctx.ErrorCode,
+		"attempt":    jctx.Attempt,
+		"duration":   duration.String(),
+	})
+}
+
+// scheduleRetry re-enqueues a failed job after the backoff for its attempt
+func (s *Scheduler) scheduleRetry(jctx *JobContext) {
+	backoff := s.retry.Backoff(jctx.Attempt)
+	attempt := jctx.Attempt + 1
+
+	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+		Level:   "warn",
+		Source:  "job",
+		JobID:   jctx.Job.ID.String(),
+		Message: fmt.Sprintf("Retrying %s in %s (attempt %d of %d)", jctx.Job.Name, backoff.Round(time.Second), attempt, s.retry.MaxRetries),
+	})
+
+	jobID, origin := jctx.Job.ID, jobOrigin{RequestID: jctx.RequestID, TraceParent: jctx.TraceParent}
+	time.AfterFunc(backoff, func() {
+		select {
+		case <-s.stopChan:
+			return
+		default:
+		}
+		if err := s.enqueue(jobID, attempt, origin); err != nil {
+			jctx.Log.Error().Err(err).Int("next_attempt", attempt).Msg("Failed to retry job")
+		}
+	})
+}
+
+// deadLetter records a job that failed its last attempt so it can be inspected and replayed
+func (s *Scheduler) deadLetter(jctx *JobContext, lastError string) {
+	job := jctx.Job
+	jobContext, _ := json.Marshal(map[string]interface{}{
+		"execution_id":    jctx.ExecutionID,
+		"cron_expression": job.CronExpression,
+		"config":          json.RawMessage(jsonOrNull(job.Config)),
+		"wallet_ids":      json.RawMessage(jsonOrNull(job.WalletIDs)),
+		"account_ids":     json.RawMessage(jsonOrNull(job.AccountIDs)),
+		"campaign_id":     job.CampaignID,
+		"request_id":      jctx.RequestID,
+	})
+
+	entry := &models.DeadLetterJob{
+		ID:        uuid.New(),
+		UserID:    jctx.UserID,
+		Source:    models.DeadLetterSourceJob,
+		JobID:     &job.ID,
+		Type:      string(job.Type),
+		Name:      job.Name,
+		Attempts:  jctx.Attempt + 1,
+		LastError: lastError,
+		Context:   string(jobContext),
+		Status:    "dead",
+	}
+	if err := s.db.Create(entry).Error; err != nil {
+		jctx.Log.Error().Err(err).Msg("Failed to dead-letter job")
+		return
+	}
+
+	s.wsHub.BroadcastToUser(jctx.UserID.String(), "job:dead_lettered", entry)
+	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+		Level:   "error",
+		Source:  "job",
+		JobID:   job.ID.String(),
+		Message: fmt.Sprintf("Job %s moved to the dead-letter queue after %d attempts: %s", job.Name, entry.Attempts, lastError),
+		Details: map[string]interface{}{
+			"dead_letter_id": entry.ID,
+			"job_id":         job.ID,
+		},
+	})
+}
+
+// jsonOrNull keeps empty jsonb columns valid when embedding them in other JSON
+func jsonOrNull(raw string) string {
+	if raw == "" || !json.Valid([]byte(raw)) {
+		return "null"
+	}
+	return raw
+}
+
+// hasHandler reports whether the scheduler can run jobs of the given type
+func (s *Scheduler) hasHandler(jobType models.JobType) bool {
+	_, ok := s.getJobHandlers()[jobType]
+	return ok
+}
+
+// Runs reports whether the scheduler has a handler for jobs of the given type.
+// Other job types are only records left by the services that run them.
+func Runs(jobType models.JobType) bool {
+	// The handler set does not depend on the scheduler's state
+	return (&Scheduler{}).hasHandler(jobType)
+}
+
+// deactivateUnknownJob fails and deactivates a job of a type the scheduler has
+// no handler for, warning its owner the first time
+func (s *Scheduler) deactivateUnknownJob(job *models.AutomationJob) {
+	result := s.db.Model(&models.AutomationJob{}).
+		Where("id = ? AND is_active = ?", job.ID, true).
+		Updates(map[string]interface{}{
+			"is_active":   false,
+			"status":      "failed",
+			"error_code":  tasks.ErrorCodeNotSupported,
+			"next_run_at": nil,
+		})
+	if result.Error != nil {
+		log.Printf("⚠️ Failed to deactivate job %s with unknown type %q: %v", job.ID, job.Type, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("Job %s has unknown type %q and was deactivated", job.Name, job.Type)
+	log.Printf("⚠️ %s (%s)", message, job.ID)
+	s.logJob(job.ID, models.JobLogWarn, message, map[string]interface{}{"type": job.Type})
+	s.wsHub.BroadcastTerminal(job.UserID.String(), websocket.TerminalMessage{
+		Level:   "warn",
+		Source:  "job",
+		JobID:   job.ID.String(),
+		Message: message,
+	})
+	s.wsHub.BroadcastToUser(job.UserID.String(), "job:deactivated", map[string]interface{}{
+		"job_id":     job.ID,
+		"type":       job.Type,
+		"error_code": tasks.ErrorCodeNotSupported,
+	})
 }
 
 func (s *Scheduler) getJobHandlers() map[models.JobType]JobHandler {
@@ -345,10 +1102,12 @@ func (s *Scheduler) getJobHandlers() map[models.JobType]JobHandler {
 		models.JobTypeScheduledPost:   s.handleScheduledPost,
 		models.JobTypeCampaignTask:    s.handleCampaignTask,
 		models.JobTypeBalanceSync:     s.handleBalanceSync,
+		models.JobTypeHistorySync:     s.handleHistorySync,
 		models.JobTypePlatformSync:    s.handlePlatformSync,
 		models.JobTypeEngagement:      s.handleEngagement,
 		models.JobTypeContentGenerate: s.handleContentGenerate,
 		models.JobTypeBulkExecute:     s.handleBulkExecute,
+		models.JobTypeWarmup:          s.handleWarmup,
 	}
 }
 
@@ -358,9 +1117,12 @@ func (s *Scheduler) handleScheduledPost(ctx context.Context, jctx *JobContext, s
 	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 		Level:   "info",
 		Source:  "post",
+		JobID:   jctx.Job.ID.String(),
 		Message: "Processing scheduled posts...",
 	})
 
+	delays := s.delayPolicy(parseDelayConfig(jctx.Job.Config))
+
 	// Get pending scheduled posts
 	var posts []models.ScheduledPost
 	if err := s.db.Where("user_id = ? AND status = ? AND scheduled_for <= ?",
@@ -377,6 +1139,7 @@ func (s *Scheduler) handleScheduledPost(ctx context.Context, jctx *JobContext, s
 			s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 				Level:     "info",
 				Source:    "post",
+				JobID:     jctx.Job.ID.String(),
 				Message:   "Publishing post to " + post.Platform,
 				AccountID: post.AccountID.String(),
 			})
@@ -394,17 +1157,35 @@ func (s *Scheduler) handleScheduledPost(ctx context.Context, jctx *JobContext, s
 				continue
 			}
 
-			// Publish via platform adapter
-			var postURL string
+			// Publish via platform adapter, part by part for threads
+			var parts []models.ThreadPart
+			s.db.Where("scheduled_post_id = ?", post.ID).Order("position ASC").Find(&parts)
+
+			var postID, postURL string
 			var pubErr error
+			if len(parts) > 0 {
+				postURL, pubErr = s.publishThread(ctx, &account, &post, parts)
+			} else {
+				postID, postURL, pubErr = s.publish(ctx, &account, post.Content, post.ReplyToID)
+			}
 
-			switch account.Platform {
-			case models.PlatformFarcaster:
-				postURL, pubErr = s.publishToFarcaster(&account, post.Content)
-			case models.PlatformTelegram:
-				postURL, pubErr = s.publishToTelegram(&account, post.Content)
-			default:
-				pubErr = fmt.Errorf("platform %s not supported for automated publishing", account.Platform)
+			// Rate limited: keep the post pending and retry once the window resets
+			var rlErr *platforms.RateLimitError
+			if errors.As(pubErr, &rlErr) {
+				retryAt := time.Now().Add(max(rlErr.RetryAfter, time.Second))
+				s.db.Model(&post).Updates(map[string]interface{}{
+					"status":        "pending",
+					"scheduled_for": retryAt,
+					"error_message": pubErr.Error(),
+				})
+				s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+					Level:     "warn",
+					Source:    "post",
+					JobID:     jctx.Job.ID.String(),
+					Message:   "Rate limited, retrying at " + retryAt.Format(time.RFC3339),
+					AccountID: post.AccountID.String(),
+				})
+				continue
 			}
 
 			if pubErr != nil {
@@ -415,6 +1196,7 @@ func (s *Scheduler) handleScheduledPost(ctx context.Context, jctx *JobContext, s
 				s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 					Level:     "error",
 					Source:    "post",
+					JobID:     jctx.Job.ID.String(),
 					Message:   "Failed to publish: " + pubErr.Error(),
 					AccountID: post.AccountID.String(),
 				})
@@ -426,9 +1208,28 @@ func (s *Scheduler) handleScheduledPost(ctx context.Context, jctx *JobContext, s
 				"posted_at": time.Now(),
 				"post_url":  postURL,
 			})
+			s.recordDailyAction(ctx, account.ID)
+
+			activity := &models.AccountActivity{
+				Type:        "post",
+				TargetID:    postID,
+				TargetURL:   postURL,
+				Content:     post.Content,
+				AutomatedBy: jctx.Job.ID.String(),
+			}
+			if post.ReplyToID != "" {
+				activity.Type = "reply"
+			}
+			s.logActivity(&account, activity, map[string]interface{}{
+				"scheduled_post_id": post.ID,
+				"thread_parts":      len(parts),
+				"reply_to_url":      post.ReplyToURL,
+			})
 
-			// Add random delay between posts (human-like behavior)
-			time.Sleep(time.Duration(2+jctx.ExecutionID.ID()%5) * time.Second)
+			// Randomized pause between posts (human-like behavior)
+			if err := delays.wait(ctx, post.Platform); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -446,9 +1247,11 @@ func (s *Scheduler) handleCampaignTask(ctx context.Context, jctx *JobContext, sc
 	}
 
 	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
-		Level:   "info",
-		Source:  "campaign",
-		Message: "Processing campaign tasks...",
+		Level:      "info",
+		Source:     "campaign",
+		JobID:      jctx.Job.ID.String(),
+		Message:    "Processing campaign tasks...",
+		CampaignID: config.CampaignID,
 	})
 
 	// Get tasks
@@ -464,10 +1267,12 @@ func (s *Scheduler) handleCampaignTask(ctx context.Context, jctx *JobContext, sc
 			}
 
 			s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
-				Level:   "info",
-				Source:  "task",
-				Message: "Executing task: " + task.Name,
-				TaskID:  taskID.String(),
+				Level:      "info",
+				Source:     "task",
+				JobID:      jctx.Job.ID.String(),
+				Message:    "Executing task: " + task.Name,
+				TaskID:     taskID.String(),
+				CampaignID: task.CampaignID.String(),
 			})
 
 			// Create execution record
@@ -482,14 +1287,17 @@ func (s *Scheduler) handleCampaignTask(ctx context.Context, jctx *JobContext, sc
 			// Check if task requires manual intervention
 			if task.RequiresManual {
 				s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
-					Level:   "warn",
-					Source:  "task",
-					Message: "⚠️ Manual action required: " + task.Name,
-					TaskID:  taskID.String(),
+					Level:      "warn",
+					Source:     "task",
+					JobID:      jctx.Job.ID.String(),
+					Message:    "⚠️ Manual action required: " + task.Name,
+					TaskID:     taskID.String(),
+					CampaignID: task.CampaignID.String(),
 				})
 
 				s.wsHub.BroadcastTaskUpdate(jctx.UserID.String(), websocket.TaskStatusUpdate{
 					TaskID:         taskID.String(),
+					CampaignID:     task.CampaignID.String(),
 					Status:         "waiting_manual",
 					Message:        task.RequiredAction,
 					RequiresManual: true,
@@ -504,7 +1312,7 @@ func (s *Scheduler) handleCampaignTask(ctx context.Context, jctx *JobContext, sc
 			var execErr error
 			switch task.Type {
 			case models.TaskTypeFollow, models.TaskTypeLike, models.TaskTypeRecast, models.TaskTypeReply, models.TaskTypePost:
-				execErr = s.executeSocialAction(ctx, jctx.UserID, &task, execution)
+				execErr = s.executeSocialAction(ctx, jctx, &task, execution)
 			case models.TaskTypeTransaction:
 				execErr = s.executeTransaction(ctx, jctx.UserID, &task, execution)
 			default:
@@ -515,13 +1323,16 @@ func (s *Scheduler) handleCampaignTask(ctx context.Context, jctx *JobContext, sc
 				s.db.Model(execution).Updates(map[string]interface{}{
 					"status":        "failed",
 					"error_message": execErr.Error(),
+					"error_code":    tasks.ErrorCodeFor(execErr),
 					"completed_at":  time.Now(),
 				})
 				s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
-					Level:   "error",
-					Source:  "task",
-					Message: "Task failed: " + execErr.Error(),
-					TaskID:  taskID.String(),
+					Level:      "error",
+					Source:     "task",
+					JobID:      jctx.Job.ID.String(),
+					Message:    "Task failed: " + execErr.Error(),
+					TaskID:     taskID.String(),
+					CampaignID: task.CampaignID.String(),
 				})
 				continue
 			}
@@ -540,6 +1351,7 @@ func (s *Scheduler) handleBalanceSync(ctx context.Context, jctx *JobContext, sch
 	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 		Level:   "info",
 		Source:  "wallet",
+		JobID:   jctx.Job.ID.String(),
 		Message: "Syncing wallet balances...",
 	})
 
@@ -556,29 +1368,99 @@ func (s *Scheduler) handleBalanceSync(ctx context.Context, jctx *JobContext, sch
 			s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 				Level:    "debug",
 				Source:   "wallet",
+				JobID:    jctx.Job.ID.String(),
 				Message:  "Syncing balance for: " + wallet.Address[:10] + "...",
 				WalletID: wallet.ID.String(),
 			})
 
-			// Fetch balance from RPC based on chain type
-			balance, err := s.fetchWalletBalance(ctx, &wallet)
+			// Fetch the balance on the wallet's default chain
+			balance, err := s.balances.Fetch(ctx, &wallet, int64(wallet.ChainID))
 			if err != nil {
-				log.Printf("Failed to fetch balance for %s: %v", wallet.Address, err)
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				jctx.Log.Warn().Err(err).Str("wallet", wallet.Address).Msg("Failed to fetch balance")
+				s.db.Model(&wallet).Update("last_sync_error", err.Error())
+
+				// Back off before the next wallet instead of hammering a throttled provider
+				if retryAfter, limited := readretry.RetryAfter(err); limited {
+					s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+						Level:    "warn",
+						Source:   "wallet",
+						JobID:    jctx.Job.ID.String(),
+						Message:  "Balance provider rate limited: " + err.Error(),
+						WalletID: wallet.ID.String(),
+					})
+					if retryAfter <= 0 || retryAfter > readretry.MaxBackoff {
+						retryAfter = readretry.MaxBackoff
+					}
+					if err := readretry.Sleep(ctx, retryAfter); err != nil {
+						return err
+					}
+				}
 			} else {
 				s.db.Model(&wallet).Updates(map[string]interface{}{
 					"balance":           balance,
 					"last_balance_sync": time.Now(),
+					"last_sync_error":   "",
 				})
 			}
 
-			time.Sleep(500 * time.Millisecond) // Rate limiting
+			time.Sleep(500 * time.Millisecond) // Rate limiting
+		}
+	}
+
+	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+		Level:   "success",
+		Source:  "wallet",
+		JobID:   jctx.Job.ID.String(),
+		Message: "Balance sync completed for " + string(rune(len(wallets))) + " wallets",
+	})
+
+	return nil
+}
+
+// handleHistorySync imports on-chain transaction history for the user's EVM wallets,
+// typically scheduled alongside balance_sync
+func (s *Scheduler) handleHistorySync(ctx context.Context, jctx *JobContext, scheduler *Scheduler) error {
+	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+		Level:   "info",
+		Source:  "wallet",
+		JobID:   jctx.Job.ID.String(),
+		Message: "Importing on-chain transaction history...",
+	})
+
+	var wallets []models.Wallet
+	if err := s.db.Where("user_id = ? AND type = ?", jctx.UserID, models.WalletTypeEVM).Find(&wallets).Error; err != nil {
+		return err
+	}
+
+	imported := 0
+	for _, wallet := range wallets {
+		fetched, err := s.explorer.SyncWallet(ctx, s.db, &wallet)
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.Is(err, explorer.ErrNotConfigured):
+			// Retrying will not help until a key is configured
+			return err
+		case errors.Is(err, explorer.ErrRateLimited):
+			jctx.Log.Warn().Str("wallet", wallet.Address).Msg("Explorer rate limited, pausing history sync")
+			if err := readretry.Sleep(ctx, readretry.MaxBackoff); err != nil {
+				return err
+			}
+		case err != nil:
+			jctx.Log.Warn().Err(err).Str("wallet", wallet.Address).Msg("Failed to import history")
+		default:
+			imported += fetched
 		}
 	}
 
 	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 		Level:   "success",
 		Source:  "wallet",
-		Message: "Balance sync completed for " + string(rune(len(wallets))) + " wallets",
+		JobID:   jctx.Job.ID.String(),
+		Message: fmt.Sprintf("Imported %d transactions for %d wallets", imported, len(wallets)),
 	})
 
 	return nil
@@ -588,6 +1470,7 @@ func (s *Scheduler) handlePlatformSync(ctx context.Context, jctx *JobContext, sc
 	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 		Level:   "info",
 		Source:  "platform",
+		JobID:   jctx.Job.ID.String(),
 		Message: "Syncing platform accounts...",
 	})
 
@@ -604,13 +1487,14 @@ func (s *Scheduler) handlePlatformSync(ctx context.Context, jctx *JobContext, sc
 			s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 				Level:     "debug",
 				Source:    "platform",
+				JobID:     jctx.Job.ID.String(),
 				Message:   "Syncing " + string(account.Platform) + " account: " + account.Username,
 				AccountID: account.ID.String(),
 			})
 
 			// Sync account data from platform API
 			if err := s.syncAccountFromPlatform(ctx, &account); err != nil {
-				log.Printf("Failed to sync account %s: %v", account.Username, err)
+				jctx.Log.Warn().Err(err).Str("account_id", account.ID.String()).Msg("Failed to sync account")
 			}
 
 			time.Sleep(1 * time.Second) // Rate limiting
@@ -622,22 +1506,45 @@ func (s *Scheduler) handlePlatformSync(ctx context.Context, jctx *JobContext, sc
 
 func (s *Scheduler) handleEngagement(ctx context.Context, jctx *JobContext, scheduler *Scheduler) error {
 	var config struct {
-		AccountIDs []string `json:"account_ids"`
-		Actions    []string `json:"actions"` // like, reply, follow, recast
-		MaxActions int      `json:"max_actions"`
+		AccountIDs     []string `json:"account_ids"`
+		Actions        []string `json:"actions"` // like, reply, follow, recast
+		MaxActions     int      `json:"max_actions"`
+		DailyActionCap *int     `json:"daily_action_cap,omitempty"` // Per account per UTC day; 0 disables
+		EngagementTarget
+		DelayConfig
 	}
 
 	if err := json.Unmarshal([]byte(jctx.Job.Config), &config); err != nil {
 		return err
 	}
+	if s.config.NeynarAPIKey == "" {
+		return fmt.Errorf("NEYNAR_API_KEY not configured")
+	}
+
+	delays := s.delayPolicy(config.DelayConfig)
+	dailyCap := s.config.ActionDailyCap
+	if config.DailyActionCap != nil {
+		dailyCap = *config.DailyActionCap
+	}
 
 	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 		Level:   "info",
 		Source:  "engagement",
+		JobID:   jctx.Job.ID.String(),
 		Message: "Starting engagement automation...",
 	})
 
-	// Execute engagement actions via platform adapters
+	candidates, err := s.engagementCandidates(ctx, config.EngagementTarget)
+	if err != nil {
+		return err
+	}
+	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+		Level:   "info",
+		Source:  "engagement",
+		JobID:   jctx.Job.ID.String(),
+		Message: fmt.Sprintf("Found %d candidate casts", len(candidates)),
+	})
+
 	actionCount := 0
 	maxActions := config.MaxActions
 	if maxActions == 0 {
@@ -658,31 +1565,77 @@ func (s *Scheduler) handleEngagement(ctx context.Context, jctx *JobContext, sche
 		if err := s.db.First(&account, accountID).Error; err != nil {
 			continue
 		}
+		// Candidates are casts, so only Farcaster accounts can act on them
+		if account.Platform != models.PlatformFarcaster {
+			s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+				Level:     "warn",
+				Source:    "engagement",
+				JobID:     jctx.Job.ID.String(),
+				Message:   fmt.Sprintf("Skipping @%s: engagement only supports Farcaster accounts", account.Username),
+				AccountID: account.ID.String(),
+			})
+			continue
+		}
 
 		for _, action := range config.Actions {
 			if actionCount >= maxActions {
 				break
 			}
+			if action == "reply" && len(config.Replies) == 0 {
+				continue
+			}
+
+			if s.dailyActionCapReached(ctx, account.ID, dailyCap) {
+				s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+					Level:     "warn",
+					Source:    "engagement",
+					JobID:     jctx.Job.ID.String(),
+					Message:   fmt.Sprintf("Daily action cap of %d reached for @%s, skipping", dailyCap, account.Username),
+					AccountID: account.ID.String(),
+				})
+				break
+			}
+
+			cast, ok := s.nextEngagementCast(&account, action, candidates)
+			if !ok {
+				s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+					Level:     "info",
+					Source:    "engagement",
+					JobID:     jctx.Job.ID.String(),
+					Message:   fmt.Sprintf("No new casts to %s for @%s", action, account.Username),
+					AccountID: account.ID.String(),
+				})
+				continue
+			}
+			var content string
+			if action == "reply" {
+				content = pickReply(config.Replies)
+			}
 
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
 			default:
 				// Execute the action directly with the account
-				if err := s.executeDirectSocialAction(ctx, &account, action, ""); err != nil {
-					log.Printf("Engagement action failed: %v", err)
+				if err := s.executeDirectSocialAction(ctx, &account, action, engagementTargetID(action, *cast), content); err != nil {
+					jctx.Log.Warn().Err(err).Str("account_id", account.ID.String()).Str("action", action).Msg("Engagement action failed")
 				} else {
 					actionCount++
+					s.recordDailyAction(ctx, account.ID)
+					s.recordEngagement(jctx, &account, action, cast, content, nil)
 					s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 						Level:     "success",
 						Source:    "engagement",
-						Message:   fmt.Sprintf("Completed %s action", action),
+						JobID:     jctx.Job.ID.String(),
+						Message:   fmt.Sprintf("Completed %s on @%s's cast %s", action, cast.Author.Username, shortCastHash(cast.Hash)),
 						AccountID: account.ID.String(),
 					})
 				}
 
-				// Rate limiting delay
-				time.Sleep(2 * time.Second)
+				// Randomized pause between actions
+				if err := delays.wait(ctx, string(account.Platform)); err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -690,6 +1643,7 @@ func (s *Scheduler) handleEngagement(ctx context.Context, jctx *JobContext, sche
 	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 		Level:   "success",
 		Source:  "engagement",
+		JobID:   jctx.Job.ID.String(),
 		Message: fmt.Sprintf("Engagement automation completed: %d actions", actionCount),
 	})
 
@@ -700,6 +1654,7 @@ func (s *Scheduler) handleContentGenerate(ctx context.Context, jctx *JobContext,
 	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 		Level:   "info",
 		Source:  "ai",
+		JobID:   jctx.Job.ID.String(),
 		Message: "Generating AI content...",
 	})
 
@@ -719,65 +1674,52 @@ func (s *Scheduler) handleContentGenerate(ctx context.Context, jctx *JobContext,
 		config.Quantity = 1
 	}
 
-	// Call AI microservice
-	aiServiceURL := s.config.AIServiceURL
-	if aiServiceURL == "" {
-		aiServiceURL = "http://ai-service:8000"
-	}
-
 	for i := 0; i < config.Quantity; i++ {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
-			payload := map[string]interface{}{
-				"prompt":       config.Prompt,
-				"content_type": config.ContentType,
-			}
-			payloadBytes, _ := json.Marshal(payload)
-
-			req, err := http.NewRequestWithContext(ctx, "POST", aiServiceURL+"/generate", bytes.NewReader(payloadBytes))
-			if err != nil {
-				log.Printf("Failed to create AI request: %v", err)
-				continue
-			}
-			req.Header.Set("Content-Type", "application/json")
-
-			client := &http.Client{Timeout: 60 * time.Second}
-			resp, err := client.Do(req)
+			contents, err := s.ai.Generate(ctx, &ai.Prompt{
+				Prompt:     config.Prompt,
+				Type:       config.ContentType,
+				NumOptions: 1,
+			})
 			if err != nil {
-				log.Printf("AI service request failed: %v", err)
-				continue
-			}
-
-			var aiResp struct {
-				Content string `json:"content"`
-				Error   string `json:"error"`
-			}
-			if err := json.NewDecoder(resp.Body).Decode(&aiResp); err != nil {
-				resp.Body.Close()
-				continue
-			}
-			resp.Body.Close()
-
-			if aiResp.Error != "" {
-				log.Printf("AI service error: %s", aiResp.Error)
+				jctx.Log.Warn().Err(err).Msg("AI content generation failed")
 				continue
 			}
 
 			// Save as draft if requested
-			if config.SaveAsDrafts && aiResp.Content != "" {
+			if config.SaveAsDrafts && contents[0].Content != "" {
 				draft := &models.ContentDraft{
 					UserID:  jctx.UserID,
-					Content: aiResp.Content,
+					Content: contents[0].Content,
+					AIModel: contents[0].Model,
 					Status:  "draft",
 				}
+				if s.moderator != nil {
+					moderation, err := s.moderator.Check(ctx, draft.Content)
+					if err != nil {
+						continue
+					}
+					if moderation.Flagged {
+						if s.config.ModerationAction == ai.ModerationActionDrop {
+							jctx.Log.Info().Str("reason", moderation.Reason()).Msg("Dropped generated content that failed moderation")
+							continue
+						}
+						draft.Status = "flagged"
+					}
+					moderationJSON, _ := json.Marshal(moderation)
+					draft.Moderation = string(moderationJSON)
+					draft.ModerationReason = moderation.Reason()
+				}
 				s.db.Create(draft)
 			}
 
 			s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 				Level:   "success",
 				Source:  "ai",
+				JobID:   jctx.Job.ID.String(),
 				Message: fmt.Sprintf("Generated content %d/%d", i+1, config.Quantity),
 			})
 		}
@@ -794,15 +1736,29 @@ func (s *Scheduler) handleBulkExecute(ctx context.Context, jctx *JobContext, sch
 		TaskIDs     []string `json:"task_ids"`
 		Parallel    bool     `json:"parallel"`
 		MaxParallel int      `json:"max_parallel"`
+		DryRun      bool     `json:"dry_run"`
 	}
 
 	if err := json.Unmarshal([]byte(jctx.Job.Config), &config); err != nil {
 		return err
 	}
 
+	// Dry runs are planned when requested and never queued; one that was
+	// queued anyway must not execute
+	if config.DryRun {
+		s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+			Level:   "warn",
+			Source:  "bulk",
+			JobID:   jctx.Job.ID.String(),
+			Message: "Dry-run bulk job skipped; nothing was executed",
+		})
+		return nil
+	}
+
 	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 		Level:   "info",
 		Source:  "bulk",
+		JobID:   jctx.Job.ID.String(),
 		Message: "Starting bulk execution...",
 		Details: map[string]interface{}{
 			"wallets":  len(config.WalletIDs),
@@ -820,7 +1776,7 @@ func (s *Scheduler) handleBulkExecute(ctx context.Context, jctx *JobContext, sch
 	// Create semaphore for parallelism control
 	sem := make(chan struct{}, maxParallel)
 	var wg sync.WaitGroup
-	var completedCount, failedCount int32
+	var completedCount, failedCount, manualCount, skippedCount int32
 	var mu sync.Mutex
 
 	// Get tasks to execute
@@ -831,48 +1787,99 @@ func (s *Scheduler) handleBulkExecute(ctx context.Context, jctx *JobContext, sch
 		}
 
 		var task models.CampaignTask
-		if err := s.db.First(&task, taskID).Error; err != nil {
+		if err := s.db.Where("id = ? AND campaign_id = ?", taskID, config.CampaignID).First(&task).Error; err != nil {
 			continue
 		}
+		if !s.bulkDependencyMet(&task) {
+			skippedCount++
+			continue
+		}
+
+		// Wallet tasks pair with the wallets and are left for the user to sign;
+		// the rest run for each account
+		walletTask := isBulkWalletTask(task.Type)
+		targetIDs := config.AccountIDs
+		if walletTask {
+			targetIDs = config.WalletIDs
+		}
 
-		// Execute for each account
-		for _, accountIDStr := range config.AccountIDs {
-			accountID, err := uuid.Parse(accountIDStr)
+		for _, targetIDStr := range targetIDs {
+			targetID, err := uuid.Parse(targetIDStr)
 			if err != nil {
 				continue
 			}
+			if s.bulkAlreadyCompleted(&task, targetID, walletTask) {
+				skippedCount++
+				continue
+			}
+			if !walletTask {
+				if score, minScore, below := s.belowWarmup(task.CampaignID, targetID); below {
+					s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+						Level:      "warn",
+						Source:     "bulk",
+						JobID:      jctx.Job.ID.String(),
+						Message:    fmt.Sprintf("Skipping %s: account warmup score %d is below the campaign's minimum of %d", task.Name, score, minScore),
+						TaskID:     task.ID.String(),
+						CampaignID: task.CampaignID.String(),
+						AccountID:  targetID.String(),
+					})
+					skippedCount++
+					continue
+				}
+			}
 
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
 			case sem <- struct{}{}:
 				wg.Add(1)
-				go func(t models.CampaignTask, accID uuid.UUID) {
+				go func(t models.CampaignTask, targetID uuid.UUID) {
 					defer wg.Done()
 					defer func() { <-sem }()
 
 					// Create execution record
 					execution := &models.TaskExecution{
 						TaskID:    t.ID,
-						AccountID: &accID,
-						Status:    "running",
+						Status:    models.ExecutionInProgress,
 						StartedAt: time.Now(),
 					}
+					if walletTask {
+						execution.WalletID = &targetID
+					} else {
+						execution.AccountID = &targetID
+					}
 					s.db.Create(execution)
 
+					if walletTask || t.RequiresManual {
+						mu.Lock()
+						manualCount++
+						mu.Unlock()
+						s.db.Model(execution).Update("status", models.ExecutionWaitingManual)
+						return
+					}
+
 					// Execute the task
 					var execErr error
 					switch t.Type {
 					case models.TaskTypeFollow, models.TaskTypeLike, models.TaskTypeRecast, models.TaskTypeReply:
 						var account models.PlatformAccount
-						if err := s.db.First(&account, accID).Error; err == nil {
-							execErr = s.executeDirectSocialAction(ctx, &account, string(t.Type), t.TargetURL)
+						if err := s.db.First(&account, targetID).Error; err == nil {
+							execErr = s.executeDirectSocialAction(ctx, &account, string(t.Type), t.TargetURL, "")
+							if execErr == nil {
+								s.logActivity(&account, &models.AccountActivity{
+									Type:        string(t.Type),
+									TargetURL:   t.TargetURL,
+									CampaignID:  &t.CampaignID,
+									AutomatedBy: jctx.Job.ID.String(),
+								}, map[string]interface{}{"task_id": t.ID, "execution_id": execution.ID})
+							}
 						}
 					default:
 						// Other task types
 						s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 							Level:   "info",
 							Source:  "bulk",
+							JobID:   jctx.Job.ID.String(),
 							Message: fmt.Sprintf("Executing %s task", t.Type),
 						})
 					}
@@ -880,18 +1887,18 @@ func (s *Scheduler) handleBulkExecute(ctx context.Context, jctx *JobContext, sch
 					mu.Lock()
 					if execErr != nil {
 						failedCount++
-						execution.Status = "failed"
+						execution.Status = models.ExecutionFailed
 						execution.ErrorMessage = execErr.Error()
 					} else {
 						completedCount++
-						execution.Status = "completed"
+						execution.Status = models.ExecutionCompleted
 					}
 					mu.Unlock()
 
 					now := time.Now()
 					execution.CompletedAt = &now
 					s.db.Save(execution)
-				}(task, accountID)
+				}(task, targetID)
 			}
 		}
 	}
@@ -901,53 +1908,158 @@ func (s *Scheduler) handleBulkExecute(ctx context.Context, jctx *JobContext, sch
 	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
 		Level:   "success",
 		Source:  "bulk",
-		Message: fmt.Sprintf("Bulk execution completed: %d succeeded, %d failed", completedCount, failedCount),
+		JobID:   jctx.Job.ID.String(),
+		Message: fmt.Sprintf("Bulk execution completed: %d succeeded, %d failed, %d waiting for you, %d skipped", completedCount, failedCount, manualCount, skippedCount),
 	})
 
 	return nil
 }
 
-// PublishToRedis publishes a job to Redis for distributed processing
-func (s *Scheduler) PublishToRedis(jobID, userID uuid.UUID) error {
-	ctx := context.Background()
+// isBulkWalletTask reports whether a bulk execution runs a task type from its
+// wallets rather than its accounts
+func isBulkWalletTask(taskType models.TaskType) bool {
+	switch taskType {
+	case models.TaskTypeConnect, models.TaskTypeTransaction, models.TaskTypeClaim, models.TaskTypeApprove:
+		return true
+	}
+	return false
+}
+
+// bulkDependencyMet reports whether the task's dependency has a completed execution
+func (s *Scheduler) bulkDependencyMet(task *models.CampaignTask) bool {
+	if task.DependsOn == nil {
+		return true
+	}
+	var count int64
+	s.db.Model(&models.TaskExecution{}).
+		Where("task_id = ? AND status IN ?", *task.DependsOn, models.ExecutionStatusValues(models.ExecutionCompleted)).
+		Count(&count)
+	return count > 0
+}
+
+// bulkAlreadyCompleted reports whether the task is already completed for the
+// wallet or account
+func (s *Scheduler) bulkAlreadyCompleted(task *models.CampaignTask, targetID uuid.UUID, wallet bool) bool {
+	column := "account_id"
+	if wallet {
+		column = "wallet_id"
+	}
+	var count int64
+	s.db.Model(&models.TaskExecution{}).
+		Where("task_id = ? AND "+column+" = ? AND status IN ?", task.ID, targetID, models.ExecutionStatusValues(models.ExecutionCompleted)).
+		Count(&count)
+	return count > 0
+}
+
+// PublishToRedis publishes a job to Redis for distributed processing. The request
+// ID and trace carried by ctx, if any, follow the job to whichever replica runs it.
+func (s *Scheduler) PublishToRedis(ctx context.Context, jobID, userID uuid.UUID) error {
 	payload, _ := json.Marshal(map[string]string{
-		"job_id":  jobID.String(),
-		"user_id": userID.String(),
+		"job_id":      jobID.String(),
+		"user_id":     userID.String(),
+		"request_id":  requestid.FromContext(ctx),
+		"traceparent": tracing.Inject(ctx),
 	})
 	return s.redis.Publish(ctx, "jobs:queue", string(payload)).Err()
 }
 
-// publishToFarcaster publishes content to Farcaster via Neynar
-func (s *Scheduler) publishToFarcaster(account *models.PlatformAccount, content string) (string, error) {
+// publish posts content from account, as a reply when replyTo is set, and
+// returns the platform post ID and URL
+func (s *Scheduler) publish(ctx context.Context, account *models.PlatformAccount, content, replyTo string) (string, string, error) {
+	switch account.Platform {
+	case models.PlatformFarcaster:
+		return s.publishToFarcaster(ctx, account, content, replyTo)
+	case models.PlatformTelegram:
+		return s.publishToTelegram(ctx, account, content, replyTo)
+	case models.PlatformDiscord:
+		return s.publishToDiscord(ctx, account, content, replyTo)
+	default:
+		return "", "", fmt.Errorf("automated publishing is not supported for platform %q (supported: farcaster, telegram, discord)", account.Platform)
+	}
+}
+
+// publishThread publishes each part as a reply to the previous one. On failure it
+// stops and leaves the remaining parts pending; parts posted by an earlier run
+// are skipped so a retry continues the same thread. Returns the first part's URL.
+func (s *Scheduler) publishThread(ctx context.Context, account *models.PlatformAccount, post *models.ScheduledPost, parts []models.ThreadPart) (string, error) {
+	parent := post.ReplyToID
+	var threadURL string
+
+	for i := range parts {
+		part := &parts[i]
+		if part.Status == "posted" {
+			parent = part.PostID
+			if threadURL == "" {
+				threadURL = part.PostURL
+			}
+			continue
+		}
+
+		postID, postURL, err := s.publish(ctx, account, part.Content, parent)
+		if err != nil {
+			s.db.Model(part).Updates(map[string]interface{}{
+				"status":        "failed",
+				"error_message": err.Error(),
+			})
+			return threadURL, fmt.Errorf("thread part %d of %d failed: %w", i+1, len(parts), err)
+		}
+
+		now := time.Now()
+		s.db.Model(part).Updates(map[string]interface{}{
+			"status":        "posted",
+			"post_id":       postID,
+			"post_url":      postURL,
+			"posted_at":     now,
+			"error_message": "",
+		})
+
+		parent = postID
+		if threadURL == "" {
+			threadURL = postURL
+		}
+	}
+
+	return threadURL, nil
+}
+
+// publishToFarcaster publishes content to Farcaster via Neynar, replying to the
+// cast hash in replyTo when set
+func (s *Scheduler) publishToFarcaster(ctx context.Context, account *models.PlatformAccount, content, replyTo string) (string, string, error) {
 	if s.config.NeynarAPIKey == "" {
-		return "", fmt.Errorf("NEYNAR_API_KEY not configured")
+		return "", "", fmt.Errorf("NEYNAR_API_KEY not configured")
 	}
 
 	// Post via Neynar API
-	client := &http.Client{Timeout: 30 * time.Second}
+	client, err := s.accountClient(ctx, account)
+	if err != nil {
+		return "", "", err
+	}
 
 	payload := map[string]interface{}{
 		"signer_uuid": account.PlatformUserID,
 		"text":        content,
 	}
+	if replyTo != "" {
+		payload["parent"] = replyTo
+	}
 	payloadBytes, _ := json.Marshal(payload)
 
-	req, err := http.NewRequest("POST", "https://api.neynar.com/v2/farcaster/cast", bytes.NewBuffer(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.neynar.com/v2/farcaster/cast", bytes.NewBuffer(payloadBytes))
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 	req.Header.Set("api_key", s.config.NeynarAPIKey)
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("neynar API error: %w", err)
+		return "", "", fmt.Errorf("neynar API error: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("neynar API error: %s", string(body))
+		return "", "", fmt.Errorf("neynar API error: %s", string(body))
 	}
 
 	var result struct {
@@ -956,37 +2068,46 @@ func (s *Scheduler) publishToFarcaster(account *models.PlatformAccount, content
 		} `json:"cast"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", err
+		return "", "", err
 	}
 
-	return fmt.Sprintf("https://warpcast.com/%s/%s", account.Username, result.Cast.Hash), nil
+	return result.Cast.Hash, fmt.Sprintf("https://warpcast.com/%s/%s", account.Username, result.Cast.Hash), nil
 }
 
-// publishToTelegram publishes content to Telegram
-func (s *Scheduler) publishToTelegram(account *models.PlatformAccount, content string) (string, error) {
+// publishToTelegram publishes content to Telegram, replying to the message ID
+// in replyTo when set
+func (s *Scheduler) publishToTelegram(ctx context.Context, account *models.PlatformAccount, content, replyTo string) (string, string, error) {
 	if s.config.TelegramBotToken == "" {
-		return "", fmt.Errorf("TELEGRAM_BOT_TOKEN not configured")
+		return "", "", fmt.Errorf("TELEGRAM_BOT_TOKEN not configured")
 	}
 
 	// Send message via Telegram Bot API
-	client := &http.Client{Timeout: 30 * time.Second}
+	client, err := s.accountClient(ctx, account)
+	if err != nil {
+		return "", "", err
+	}
 
 	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", s.config.TelegramBotToken)
 	payload := map[string]interface{}{
 		"chat_id": account.PlatformUserID,
 		"text":    content,
 	}
+	if replyTo != "" {
+		if messageID, err := strconv.Atoi(replyTo); err == nil {
+			payload["reply_to_message_id"] = messageID
+		}
+	}
 	payloadBytes, _ := json.Marshal(payload)
 
-	resp, err := client.Post(url, "application/json", bytes.NewBuffer(payloadBytes))
+	resp, err := postJSON(ctx, client, url, payloadBytes)
 	if err != nil {
-		return "", fmt.Errorf("telegram API error: %w", err)
+		return "", "", fmt.Errorf("telegram API error: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("telegram API error: %s", string(body))
+		return "", "", fmt.Errorf("telegram API error: %s", string(body))
 	}
 
 	var result struct {
@@ -995,26 +2116,125 @@ func (s *Scheduler) publishToTelegram(account *models.PlatformAccount, content s
 		} `json:"result"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", err
+		return "", "", err
+	}
+
+	messageID := strconv.Itoa(result.Result.MessageID)
+	return messageID, fmt.Sprintf("https://t.me/c/%s/%s", account.PlatformUserID, messageID), nil
+}
+
+// publishToDiscord posts to the channel in account.PlatformUserID with a bot token,
+// or through a webhook when the account's token (or DISCORD_WEBHOOK_URL) is a webhook URL.
+// replyTo is a message ID to reply to. A 429 response returns a platforms.RateLimitError.
+func (s *Scheduler) publishToDiscord(ctx context.Context, account *models.PlatformAccount, content, replyTo string) (string, string, error) {
+	if len([]rune(content)) > discordMaxMessageLength {
+		return "", "", fmt.Errorf("discord messages are limited to %d characters", discordMaxMessageLength)
+	}
+
+	token := account.AccessToken
+	if token == "" {
+		token = s.config.DiscordBotToken
+	}
+	webhookURL := ""
+	if strings.HasPrefix(token, discordWebhookPrefix) {
+		webhookURL, token = token, ""
+	} else if token == "" {
+		webhookURL = s.config.DiscordWebhookURL
+	}
+
+	var endpoint string
+	switch {
+	case token != "":
+		if account.PlatformUserID == "" {
+			return "", "", fmt.Errorf("discord account has no channel ID")
+		}
+		endpoint = fmt.Sprintf("https://discord.com/api/v10/channels/%s/messages", account.PlatformUserID)
+	case webhookURL != "":
+		// wait=true makes the webhook return the created message
+		endpoint = webhookURL + "?wait=true"
+	default:
+		return "", "", fmt.Errorf("DISCORD_BOT_TOKEN or DISCORD_WEBHOOK_URL not configured")
+	}
+
+	payload := map[string]interface{}{
+		"content": content,
+	}
+	if replyTo != "" {
+		payload["message_reference"] = map[string]string{"message_id": replyTo}
+	}
+	payloadBytes, _ := json.Marshal(payload)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bot "+token)
+	}
+
+	client, err := s.accountClient(ctx, account)
+	if err != nil {
+		return "", "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("discord API error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		// retry_after is in seconds and may be fractional
+		var limited struct {
+			RetryAfter float64 `json:"retry_after"`
+		}
+		json.Unmarshal(body, &limited)
+		return "", "", &platforms.RateLimitError{
+			RetryAfter: time.Duration(limited.RetryAfter * float64(time.Second)),
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("discord API error: %s", string(body))
+	}
+
+	var result struct {
+		ID        string `json:"id"`
+		ChannelID string `json:"channel_id"`
+		GuildID   string `json:"guild_id"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", "", err
 	}
 
-	return fmt.Sprintf("https://t.me/c/%s/%d", account.PlatformUserID, result.Result.MessageID), nil
+	guild := result.GuildID
+	if guild == "" {
+		guild = "@me"
+	}
+	return result.ID, fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guild, result.ChannelID, result.ID), nil
 }
 
+const (
+	discordMaxMessageLength = 2000
+	discordWebhookPrefix    = "https://discord.com/api/webhooks/"
+)
+
 // executeDirectSocialAction executes a social action directly with an account (for engagement automation)
-func (s *Scheduler) executeDirectSocialAction(ctx context.Context, account *models.PlatformAccount, action, target string) error {
+func (s *Scheduler) executeDirectSocialAction(ctx context.Context, account *models.PlatformAccount, action, target, content string) error {
 	switch account.Platform {
 	case models.PlatformFarcaster:
-		return s.executeFarcasterAction(account, action, target, "", nil)
+		return s.executeFarcasterAction(ctx, account, action, target, content, nil)
 	case models.PlatformTelegram:
-		return s.executeTelegramAction(account, action, target, "", nil)
+		return s.executeTelegramAction(ctx, account, action, target, content, nil)
 	default:
 		return fmt.Errorf("platform %s not supported for direct social actions", account.Platform)
 	}
 }
 
 // executeSocialAction executes a social media action
-func (s *Scheduler) executeSocialAction(ctx context.Context, userID uuid.UUID, task *models.CampaignTask, execution *models.TaskExecution) error {
+func (s *Scheduler) executeSocialAction(ctx context.Context, jctx *JobContext, task *models.CampaignTask, execution *models.TaskExecution) error {
 	// Get account for the action
 	var config struct {
 		AccountID string `json:"account_id"`
@@ -1040,25 +2260,43 @@ func (s *Scheduler) executeSocialAction(ctx context.Context, userID uuid.UUID, t
 	if err := s.db.First(&account, accountID).Error; err != nil {
 		return fmt.Errorf("account not found: %w", err)
 	}
+	if score, minScore, below := s.belowWarmup(task.CampaignID, account.ID); below {
+		return fmt.Errorf("account warmup score %d is below the campaign's minimum of %d", score, minScore)
+	}
 
 	// Execute based on platform and action
 	switch account.Platform {
 	case models.PlatformFarcaster:
-		return s.executeFarcasterAction(&account, config.Action, config.Target, config.Content, execution)
+		err = s.executeFarcasterAction(ctx, &account, config.Action, config.Target, config.Content, execution)
 	case models.PlatformTelegram:
-		return s.executeTelegramAction(&account, config.Action, config.Target, config.Content, execution)
+		err = s.executeTelegramAction(ctx, &account, config.Action, config.Target, config.Content, execution)
 	default:
 		return fmt.Errorf("platform %s not supported for social actions", account.Platform)
 	}
+	if err != nil {
+		return err
+	}
+
+	s.logActivity(&account, &models.AccountActivity{
+		Type:        activityType(config.Action),
+		TargetID:    config.Target,
+		Content:     config.Content,
+		CampaignID:  &task.CampaignID,
+		AutomatedBy: jctx.Job.ID.String(),
+	}, map[string]interface{}{"task_id": task.ID, "execution_id": execution.ID})
+	return nil
 }
 
 // executeFarcasterAction executes a Farcaster action
-func (s *Scheduler) executeFarcasterAction(account *models.PlatformAccount, action, target, content string, execution *models.TaskExecution) error {
+func (s *Scheduler) executeFarcasterAction(ctx context.Context, account *models.PlatformAccount, action, target, content string, execution *models.TaskExecution) error {
 	if s.config.NeynarAPIKey == "" {
 		return fmt.Errorf("NEYNAR_API_KEY not configured")
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client, err := s.accountClient(ctx, account)
+	if err != nil {
+		return err
+	}
 	var endpoint string
 	var payload map[string]interface{}
 
@@ -1095,7 +2333,7 @@ func (s *Scheduler) executeFarcasterAction(account *models.PlatformAccount, acti
 	}
 
 	payloadBytes, _ := json.Marshal(payload)
-	req, _ := http.NewRequest("POST", endpoint, bytes.NewBuffer(payloadBytes))
+	req, _ := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(payloadBytes))
 	req.Header.Set("api_key", s.config.NeynarAPIKey)
 	req.Header.Set("Content-Type", "application/json")
 
@@ -1113,19 +2351,24 @@ func (s *Scheduler) executeFarcasterAction(account *models.PlatformAccount, acti
 	// Store proof
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
-	proofData, _ := json.Marshal(result)
-	s.db.Model(execution).Update("proof_data", string(proofData))
+	if execution != nil {
+		proofData, _ := json.Marshal(result)
+		s.db.Model(execution).Update("proof_data", string(proofData))
+	}
 
 	return nil
 }
 
 // executeTelegramAction executes a Telegram action
-func (s *Scheduler) executeTelegramAction(account *models.PlatformAccount, action, target, content string, execution *models.TaskExecution) error {
+func (s *Scheduler) executeTelegramAction(ctx context.Context, account *models.PlatformAccount, action, target, content string, execution *models.TaskExecution) error {
 	if s.config.TelegramBotToken == "" {
 		return fmt.Errorf("TELEGRAM_BOT_TOKEN not configured")
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client, err := s.accountClient(ctx, account)
+	if err != nil {
+		return err
+	}
 	baseURL := fmt.Sprintf("https://api.telegram.org/bot%s", s.config.TelegramBotToken)
 
 	switch action {
@@ -1136,7 +2379,7 @@ func (s *Scheduler) executeTelegramAction(account *models.PlatformAccount, actio
 			"text":    content,
 		}
 		payloadBytes, _ := json.Marshal(payload)
-		resp, err := client.Post(url, "application/json", bytes.NewBuffer(payloadBytes))
+		resp, err := postJSON(ctx, client, url, payloadBytes)
 		if err != nil {
 			return err
 		}
@@ -1160,6 +2403,7 @@ func (s *Scheduler) executeTransaction(ctx context.Context, userID uuid.UUID, ta
 
 	s.wsHub.BroadcastTaskUpdate(userID.String(), websocket.TaskStatusUpdate{
 		TaskID:         execution.TaskID.String(),
+		CampaignID:     task.CampaignID.String(),
 		Status:         "waiting_manual",
 		Message:        "Transaction requires manual approval",
 		RequiresManual: true,
@@ -1168,190 +2412,33 @@ func (s *Scheduler) executeTransaction(ctx context.Context, userID uuid.UUID, ta
 	return fmt.Errorf("transaction requires manual approval")
 }
 
-// fetchWalletBalance fetches the balance for a wallet from the appropriate RPC or Blockchair API
-func (s *Scheduler) fetchWalletBalance(ctx context.Context, wallet *models.Wallet) (string, error) {
-	client := &http.Client{Timeout: 15 * time.Second}
-
-	// Try Blockchair API first if key is available (supports multiple chains)
-	if s.config.BlockchairAPIKey != "" {
-		balance, err := s.fetchBalanceFromBlockchair(ctx, client, wallet)
-		if err == nil {
-			return balance, nil
-		}
-		// Fall back to direct RPC on Blockchair failure
-		log.Printf("Blockchair API failed for %s, falling back to RPC: %v", wallet.Address, err)
-	}
-
-	switch wallet.Type {
-	case "evm":
-		// Use Ethereum JSON-RPC to get balance
-		rpcURL := s.config.EthereumRPCURL
-		if rpcURL == "" {
-			rpcURL = "https://eth.llamarpc.com" // Public fallback
-		}
-
-		payload := map[string]interface{}{
-			"jsonrpc": "2.0",
-			"method":  "eth_getBalance",
-			"params":  []interface{}{wallet.Address, "latest"},
-			"id":      1,
-		}
-		payloadBytes, _ := json.Marshal(payload)
-
-		req, err := http.NewRequestWithContext(ctx, "POST", rpcURL, bytes.NewReader(payloadBytes))
-		if err != nil {
-			return "", err
-		}
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err := client.Do(req)
-		if err != nil {
-			return "", err
-		}
-		defer resp.Body.Close()
-
-		var rpcResp struct {
-			Result string `json:"result"`
-			Error  *struct {
-				Message string `json:"message"`
-			} `json:"error"`
-		}
-		if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
-			return "", err
-		}
-		if rpcResp.Error != nil {
-			return "", fmt.Errorf("rpc error: %s", rpcResp.Error.Message)
-		}
-
-		return rpcResp.Result, nil
-
-	case "solana":
-		// Use Solana JSON-RPC to get balance
-		rpcURL := s.config.SolanaRPCURL
-		if rpcURL == "" {
-			rpcURL = "https://api.mainnet-beta.solana.com"
-		}
-
-		payload := map[string]interface{}{
-			"jsonrpc": "2.0",
-			"method":  "getBalance",
-			"params":  []interface{}{wallet.Address},
-			"id":      1,
-		}
-		payloadBytes, _ := json.Marshal(payload)
-
-		req, err := http.NewRequestWithContext(ctx, "POST", rpcURL, bytes.NewReader(payloadBytes))
-		if err != nil {
-			return "", err
-		}
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err := client.Do(req)
-		if err != nil {
-			return "", err
-		}
-		defer resp.Body.Close()
-
-		var rpcResp struct {
-			Result struct {
-				Value uint64 `json:"value"`
-			} `json:"result"`
-		}
-		if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
-			return "", err
-		}
-
-		return fmt.Sprintf("%d", rpcResp.Result.Value), nil
-
-	case "bitcoin":
-		// Bitcoin requires Blockchair or similar API
-		return s.fetchBalanceFromBlockchair(ctx, client, wallet)
-
-	default:
-		return "", fmt.Errorf("unsupported wallet type: %s", wallet.Type)
-	}
-}
-
-// fetchBalanceFromBlockchair fetches balance using Blockchair API (multi-chain support)
-func (s *Scheduler) fetchBalanceFromBlockchair(ctx context.Context, client *http.Client, wallet *models.Wallet) (string, error) {
-	if s.config.BlockchairAPIKey == "" {
-		return "", fmt.Errorf("BLOCKCHAIR_API_KEY not configured")
-	}
-
-	// Map wallet type to Blockchair chain name
-	var chain string
-	switch wallet.Type {
-	case "evm":
-		chain = "ethereum"
-		if wallet.ChainID == 56 {
-			chain = "binance-smart-chain"
-		} else if wallet.ChainID == 137 {
-			chain = "polygon"
-		} else if wallet.ChainID == 42161 {
-			chain = "arbitrum"
-		} else if wallet.ChainID == 10 {
-			chain = "optimism"
-		} else if wallet.ChainID == 8453 {
-			chain = "base"
-		}
-	case "bitcoin":
-		chain = "bitcoin"
-	case "solana":
-		// Blockchair doesn't support Solana well, skip
-		return "", fmt.Errorf("solana not supported via Blockchair")
-	default:
-		return "", fmt.Errorf("unsupported chain for Blockchair: %s", wallet.Type)
-	}
-
-	url := fmt.Sprintf("https://api.blockchair.com/%s/dashboards/address/%s?key=%s",
-		chain, wallet.Address, s.config.BlockchairAPIKey)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// accountClient returns the HTTP client for an account's platform calls, routed
+// through the proxy its pool hands out. An account whose proxy is down fails
+// rather than falling back to a direct connection.
+func (s *Scheduler) accountClient(ctx context.Context, account *models.PlatformAccount) (*http.Client, error) {
+	proxy, err := s.proxies.AcquireForAccount(ctx, account)
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("failed to acquire proxy: %w", err)
 	}
+	return s.proxies.Client(proxy, 30*time.Second)
+}
 
-	resp, err := client.Do(req)
+// postJSON posts a JSON body with ctx, so the call is traced under the job's span
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
 	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("blockchair API error: status %d", resp.StatusCode)
-	}
-
-	var blockchairResp struct {
-		Data map[string]struct {
-			Address struct {
-				Balance    int64   `json:"balance"` // In smallest unit (wei, satoshi)
-				BalanceUSD float64 `json:"balance_usd"`
-			} `json:"address"`
-		} `json:"data"`
-		Context struct {
-			Error string `json:"error"`
-		} `json:"context"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&blockchairResp); err != nil {
-		return "", err
-	}
-
-	if blockchairResp.Context.Error != "" {
-		return "", fmt.Errorf("blockchair error: %s", blockchairResp.Context.Error)
-	}
-
-	// Get balance from response
-	for _, data := range blockchairResp.Data {
-		return fmt.Sprintf("%d", data.Address.Balance), nil
+		return nil, err
 	}
-
-	return "0", nil
+	req.Header.Set("Content-Type", "application/json")
+	return client.Do(req)
 }
 
 // syncAccountFromPlatform syncs account data from the respective platform API
 func (s *Scheduler) syncAccountFromPlatform(ctx context.Context, account *models.PlatformAccount) error {
-	client := &http.Client{Timeout: 30 * time.Second}
+	client, err := s.accountClient(ctx, account)
+	if err != nil {
+		return err
+	}
 
 	switch account.Platform {
 	case "farcaster":
@@ -1361,13 +2448,14 @@ func (s *Scheduler) syncAccountFromPlatform(ctx context.Context, account *models
 
 		// Fetch user data from Neynar
 		url := fmt.Sprintf("https://api.neynar.com/v2/farcaster/user/bulk?fids=%s", account.PlatformUserID)
-		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if err != nil {
-			return err
-		}
-		req.Header.Set("api_key", s.config.NeynarAPIKey)
-
-		resp, err := client.Do(req)
+		resp, err := readretry.Do(ctx, client, func() (*http.Request, error) {
+			req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("api_key", s.config.NeynarAPIKey)
+			return req, nil
+		})
 		if err != nil {
 			return err
 		}
@@ -1409,13 +2497,14 @@ func (s *Scheduler) syncAccountFromPlatform(ctx context.Context, account *models
 		}
 
 		url := fmt.Sprintf("https://api.twitter.com/2/users/%s?user.fields=public_metrics,profile_image_url", account.PlatformUserID)
-		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if err != nil {
-			return err
-		}
-		req.Header.Set("Authorization", "Bearer "+s.config.TwitterBearerToken)
-
-		resp, err := client.Do(req)
+		resp, err := readretry.Do(ctx, client, func() (*http.Request, error) {
+			req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Authorization", "Bearer "+s.config.TwitterBearerToken)
+			return req, nil
+		})
 		if err != nil {
 			return err
 		}
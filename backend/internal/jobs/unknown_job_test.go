@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/web3airdropos/backend/internal/config"
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/tasks"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+// mockDB opens a gorm connection backed by sqlmock
+func mockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, mock
+}
+
+func TestRunsMatchesHandlers(t *testing.T) {
+	for jobType := range (&Scheduler{}).getJobHandlers() {
+		if !Runs(jobType) {
+			t.Errorf("Runs(%q) = false, but the scheduler has a handler for it", jobType)
+		}
+	}
+	for _, jobType := range []models.JobType{"", "bogus", models.JobTypeWalletBulkCreate} {
+		if Runs(jobType) {
+			t.Errorf("Runs(%q) = true, want false", jobType)
+		}
+	}
+}
+
+func TestUnknownJobIsDeactivatedNotRequeued(t *testing.T) {
+	db, mock := mockDB(t)
+	s := NewScheduler(db, nil, websocket.NewHub(), &config.Config{})
+	jobID, userID := uuid.New(), uuid.New()
+	jobRow := func(active bool) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "user_id", "type", "name", "is_active", "status"}).
+			AddRow(jobID, userID, "bogus", "Legacy job", active, "idle")
+	}
+
+	// First enqueue: the job is switched off and its owner warned once
+	mock.ExpectQuery(`SELECT \* FROM "automation_jobs"`).WillReturnRows(jobRow(true))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "automation_jobs" SET "error_code"=\$1,"is_active"=\$2,"next_run_at"=\$3,"status"=\$4`).
+		WithArgs(tasks.ErrorCodeNotSupported, false, nil, "failed", sqlmock.AnyArg(), jobID, true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "job_logs"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	if err := s.EnqueueJob(jobID); !errors.Is(err, ErrUnknownJobType) {
+		t.Fatalf("enqueue = %v, want ErrUnknownJobType", err)
+	}
+
+	// A stale trigger finds it already inactive: no second warning, no run
+	mock.ExpectQuery(`SELECT \* FROM "automation_jobs"`).WillReturnRows(jobRow(false))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "automation_jobs"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	if err := s.EnqueueJob(jobID); !errors.Is(err, ErrUnknownJobType) {
+		t.Fatalf("second enqueue = %v, want ErrUnknownJobType", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.jobQueue) != 0 {
+		t.Fatalf("%d runs queued for a job with no handler", len(s.jobQueue))
+	}
+}
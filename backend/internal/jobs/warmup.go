@@ -0,0 +1,272 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/services/platforms"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+// warmupFeedSize is how many casts a warmup browse reads
+const warmupFeedSize = 25
+
+// defaultWarmupChannels are browsed when an account's warmup names no channels
+var defaultWarmupChannels = []string{"farcaster", "base", "memes", "music", "photography"}
+
+// handleWarmup runs a warmup session for each warming account the job names, or
+// for all of the user's warming accounts when it names none. A session takes a
+// random share of the day's remaining actions, so a job that runs a few times a
+// day spreads them out.
+func (s *Scheduler) handleWarmup(ctx context.Context, jctx *JobContext, scheduler *Scheduler) error {
+	var config struct {
+		AccountIDs []string `json:"account_ids"`
+		DelayConfig
+	}
+
+	if err := json.Unmarshal([]byte(jctx.Job.Config), &config); err != nil {
+		return err
+	}
+	if s.config.NeynarAPIKey == "" {
+		return fmt.Errorf("NEYNAR_API_KEY not configured")
+	}
+
+	query := s.db.Where("user_id = ? AND warmup_status = ? AND is_active = ?", jctx.UserID, models.WarmupWarming, true)
+	if len(config.AccountIDs) > 0 {
+		accountIDs := make([]uuid.UUID, 0, len(config.AccountIDs))
+		for _, idStr := range config.AccountIDs {
+			if id, err := uuid.Parse(idStr); err == nil {
+				accountIDs = append(accountIDs, id)
+			}
+		}
+		query = query.Where("id IN ?", accountIDs)
+	}
+	var accounts []models.PlatformAccount
+	if err := query.Find(&accounts).Error; err != nil {
+		return err
+	}
+
+	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+		Level:   "info",
+		Source:  "warmup",
+		JobID:   jctx.Job.ID.String(),
+		Message: fmt.Sprintf("Warming up %d accounts", len(accounts)),
+	})
+
+	// Feeds are read with the shared Neynar key, like engagement candidates
+	client, err := platforms.NewFarcasterClient(&platforms.AccountCredentials{APIKey: s.config.NeynarAPIKey})
+	if err != nil {
+		return err
+	}
+	delays := s.delayPolicy(config.DelayConfig)
+
+	for i := range accounts {
+		account := &accounts[i]
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if account.Platform != models.PlatformFarcaster {
+			s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+				Level:     "warn",
+				Source:    "warmup",
+				JobID:     jctx.Job.ID.String(),
+				Message:   fmt.Sprintf("Skipping @%s: warmup only supports Farcaster accounts", account.Username),
+				AccountID: account.ID.String(),
+			})
+			continue
+		}
+
+		if err := s.warmupSession(ctx, jctx, client, account, delays); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			jctx.Log.Warn().Err(err).Str("account_id", account.ID.String()).Msg("Warmup session failed")
+			s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+				Level:     "error",
+				Source:    "warmup",
+				JobID:     jctx.Job.ID.String(),
+				Message:   fmt.Sprintf("Warmup failed for @%s: %v", account.Username, err),
+				AccountID: account.ID.String(),
+			})
+		}
+	}
+
+	return nil
+}
+
+// warmupSession browses feeds for the account and occasionally likes or
+// recasts what it reads. Reactions grow more likely as the warmup progresses.
+// The first action of a UTC day counts the day towards the warmup score.
+func (s *Scheduler) warmupSession(ctx context.Context, jctx *JobContext, client *platforms.FarcasterClient, account *models.PlatformAccount, delays *delayPolicy) error {
+	var cfg models.WarmupConfig
+	if account.WarmupConfig != "" {
+		if err := json.Unmarshal([]byte(account.WarmupConfig), &cfg); err != nil {
+			return fmt.Errorf("invalid warmup config: %w", err)
+		}
+	}
+	cfg = cfg.WithDefaults()
+	channels := cfg.Channels
+	if len(channels) == 0 {
+		channels = defaultWarmupChannels
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	activeToday := account.WarmupLastActionAt != nil && !account.WarmupLastActionAt.Before(today)
+	day := account.WarmupDaysDone
+	if activeToday {
+		day--
+	}
+
+	var done int64
+	s.db.Model(&models.AccountActivity{}).
+		Where("account_id = ? AND created_at >= ? AND metadata->>'warmup' = 'true'", account.ID, today).
+		Count(&done)
+	remaining := warmupBudget(cfg, day, account.ID, today) - int(done)
+	if remaining <= 0 {
+		s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+			Level:     "info",
+			Source:    "warmup",
+			JobID:     jctx.Job.ID.String(),
+			Message:   fmt.Sprintf("@%s finished today's warmup (day %d of %d)", account.Username, day+1, cfg.Days),
+			AccountID: account.ID.String(),
+		})
+		return nil
+	}
+
+	session := 1 + rand.Intn(remaining)
+	progress := float64(day) / float64(max(cfg.Days-1, 1))
+	likeChance := 0.2 + 0.3*progress
+	recastChance := 0.05 + 0.1*progress
+	metadata := map[string]interface{}{"warmup": true, "warmup_day": day + 1}
+
+	taken := 0
+	for taken < session {
+		if taken > 0 {
+			if err := delays.wait(ctx, string(account.Platform)); err != nil {
+				return err
+			}
+		}
+
+		channel := channels[rand.Intn(len(channels))]
+		casts, _, err := client.GetChannelFeed(ctx, channel, "", warmupFeedSize)
+		if err != nil {
+			if taken == 0 {
+				return fmt.Errorf("failed to browse /%s: %w", channel, err)
+			}
+			break
+		}
+		s.logActivity(account, &models.AccountActivity{
+			Type:        "browse",
+			TargetID:    channel,
+			TargetURL:   "https://warpcast.com/~/channel/" + channel,
+			AutomatedBy: jctx.Job.ID.String(),
+		}, metadata)
+		taken++
+
+		if taken >= session || s.dailyActionCapReached(ctx, account.ID, s.config.ActionDailyCap) {
+			continue
+		}
+		var action string
+		switch roll := rand.Float64(); {
+		case roll < likeChance:
+			action = "like"
+		case roll < likeChance+recastChance:
+			action = "recast"
+		default:
+			continue
+		}
+		cast, ok := s.nextEngagementCast(account, action, casts)
+		if !ok {
+			continue
+		}
+
+		if err := delays.wait(ctx, string(account.Platform)); err != nil {
+			return err
+		}
+		if err := s.executeDirectSocialAction(ctx, account, action, cast.Hash, ""); err != nil {
+			jctx.Log.Warn().Err(err).Str("account_id", account.ID.String()).Str("action", action).Msg("Warmup action failed")
+			continue
+		}
+		s.recordDailyAction(ctx, account.ID)
+		s.recordEngagement(jctx, account, action, cast, "", metadata)
+		taken++
+	}
+
+	if taken == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{"warmup_last_action_at": now}
+	if !activeToday {
+		account.WarmupDaysDone++
+		account.WarmupScore = warmupScore(account.WarmupDaysDone, cfg.Days)
+		updates["warmup_days_done"] = account.WarmupDaysDone
+		updates["warmup_score"] = account.WarmupScore
+		if account.WarmupScore >= 100 {
+			updates["warmup_status"] = models.WarmupWarm
+		}
+	}
+	if err := s.db.Model(account).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	s.wsHub.BroadcastTerminal(jctx.UserID.String(), websocket.TerminalMessage{
+		Level:     "success",
+		Source:    "warmup",
+		JobID:     jctx.Job.ID.String(),
+		Message:   fmt.Sprintf("@%s took %d warmup actions (day %d of %d, score %d)", account.Username, taken, day+1, cfg.Days, account.WarmupScore),
+		AccountID: account.ID.String(),
+	})
+	if account.WarmupScore >= 100 && !activeToday {
+		s.wsHub.BroadcastToUser(jctx.UserID.String(), "account:warm", map[string]interface{}{
+			"account_id":   account.ID,
+			"warmup_score": account.WarmupScore,
+		})
+	}
+	return nil
+}
+
+// warmupBudget is how many warmup actions an account takes on a day of its
+// warmup: a linear ramp over the period, jittered by up to a quarter either
+// way. The jitter is fixed per account and day so repeated runs agree on it.
+func warmupBudget(cfg models.WarmupConfig, day int, accountID uuid.UUID, date time.Time) int {
+	progress := 1.0
+	if cfg.Days > 1 && day < cfg.Days-1 {
+		progress = float64(max(day, 0)) / float64(cfg.Days-1)
+	}
+	base := float64(cfg.MinDailyActions) + float64(cfg.MaxDailyActions-cfg.MinDailyActions)*progress
+
+	h := fnv.New32a()
+	h.Write([]byte(accountID.String() + date.Format("2006-01-02")))
+	jitter := 0.75 + 0.5*float64(h.Sum32()%1000)/999
+
+	return max(int(math.Round(base*jitter)), 1)
+}
+
+// warmupScore is the share of the warmup's days the account has been active, out of 100
+func warmupScore(daysDone, days int) int {
+	if days <= 0 {
+		return 100
+	}
+	return min(daysDone*100/days, 100)
+}
+
+// belowWarmup reports whether an account's warmup score is under the campaign's
+// minimum, returning both for the error message
+func (s *Scheduler) belowWarmup(campaignID, accountID uuid.UUID) (score, minScore int, below bool) {
+	s.db.Model(&models.Campaign{}).Select("min_warmup_score").Where("id = ?", campaignID).Scan(&minScore)
+	if minScore <= 0 {
+		return 0, 0, false
+	}
+	s.db.Model(&models.PlatformAccount{}).Select("warmup_score").Where("id = ?", accountID).Scan(&score)
+	return score, minScore, score < minScore
+}
@@ -21,11 +21,13 @@ var (
 type ResourceType string
 
 const (
-	ResourceAccount  ResourceType = "account"  // Lock per platform account (for rate limiting)
-	ResourceWallet   ResourceType = "wallet"   // Lock per wallet (for transactions)
-	ResourceTask     ResourceType = "task"     // Lock per task execution
-	ResourceBrowser  ResourceType = "browser"  // Lock per browser session
-	ResourceCampaign ResourceType = "campaign" // Lock per campaign execution
+	ResourceAccount   ResourceType = "account"   // Lock per platform account (for rate limiting)
+	ResourceWallet    ResourceType = "wallet"    // Lock per wallet (for transactions)
+	ResourceTask      ResourceType = "task"      // Lock per task execution
+	ResourceBrowser   ResourceType = "browser"   // Lock per browser session
+	ResourceCampaign  ResourceType = "campaign"  // Lock per campaign execution
+	ResourceJob       ResourceType = "job"       // Lock per scheduler job run
+	ResourceScheduler ResourceType = "scheduler" // Lock per scheduler-wide task, e.g. the due job check
 )
 
 // DistributedLock represents a distributed lock backed by Redis
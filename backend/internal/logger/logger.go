@@ -24,9 +24,20 @@ const (
 // Config for logger
 type Config struct {
 	Level      string // debug, info, warn, error
-	Pretty     bool   // human-readable output (dev only)
+	Pretty     bool   // human-readable console output (dev only); JSON otherwise
 	Output     io.Writer
-	TimeFormat string
+	TimeFormat string    // Timestamp layout; defaults to RFC3339
+	Caller     bool      // Tag each line with the file and line that logged it
+	Sampling   *Sampling // Nil logs everything
+}
+
+// Sampling thins out high-volume debug and info logs. In each Period the first
+// Burst lines at a level are kept, then one in every Thereafter; the rest are
+// dropped. Warnings and errors are never sampled.
+type Sampling struct {
+	Burst      uint32
+	Period     time.Duration
+	Thereafter uint32 // 0 drops everything past the burst
 }
 
 // Init initializes the global logger
@@ -49,12 +60,30 @@ func Init(cfg Config) {
 	}
 
 	level := parseLevel(cfg.Level)
-	log = zerolog.New(output).
+	ctx := zerolog.New(output).
 		Level(level).
 		With().
-		Timestamp().
-		Caller().
-		Logger()
+		Timestamp()
+	if cfg.Caller {
+		ctx = ctx.Caller()
+	}
+	log = ctx.Logger()
+
+	if cfg.Sampling != nil && cfg.Sampling.Burst > 0 {
+		sampler := &zerolog.BurstSampler{
+			Burst:  cfg.Sampling.Burst,
+			Period: cfg.Sampling.Period,
+		}
+		if cfg.Sampling.Thereafter > 0 {
+			sampler.NextSampler = &zerolog.BasicSampler{N: cfg.Sampling.Thereafter}
+		}
+		// Separate samplers so info bursts don't use up the debug budget
+		debugSampler := *sampler
+		log = log.Sample(zerolog.LevelSampler{
+			DebugSampler: &debugSampler,
+			InfoSampler:  sampler,
+		})
+	}
 }
 
 func parseLevel(level string) zerolog.Level {
@@ -87,8 +116,35 @@ func WithUserID(ctx context.Context, userID uuid.UUID) context.Context {
 	return context.WithValue(ctx, UserIDKey, userID)
 }
 
-// FromContext gets a logger with context values
+// With returns a sub-logger with fields preset on every line
+func With(fields map[string]interface{}) zerolog.Logger {
+	return log.With().Fields(fields).Logger()
+}
+
+// ForJob returns a sub-logger for one run of a job, tagged with its user and
+// job, and with the request that started it when there was one
+func ForJob(userID, jobID uuid.UUID, jobType, requestID string) zerolog.Logger {
+	l := log.With().
+		Str("user_id", userID.String()).
+		Str("job_id", jobID.String()).
+		Str("job_type", jobType)
+	if requestID != "" {
+		l = l.Str("request_id", requestID)
+	}
+	return l.Logger()
+}
+
+// NewContext returns ctx carrying l, which FromContext then returns
+func NewContext(ctx context.Context, l zerolog.Logger) context.Context {
+	return l.WithContext(ctx)
+}
+
+// FromContext gets a logger with context values. A logger stored with
+// NewContext is returned as is.
 func FromContext(ctx context.Context) zerolog.Logger {
+	if l := zerolog.Ctx(ctx); l.GetLevel() != zerolog.Disabled {
+		return *l
+	}
 	l := log.With()
 
 	if requestID, ok := ctx.Value(RequestIDKey).(string); ok {
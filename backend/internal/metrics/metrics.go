@@ -0,0 +1,196 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "web3airdropos"
+
+var (
+	// JobsProcessed counts scheduler jobs by type and final status
+	JobsProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "jobs",
+		Name:      "processed_total",
+		Help:      "Automation jobs processed by the scheduler, by type and status.",
+	}, []string{"type", "status"})
+
+	// JobDuration tracks how long scheduler jobs take
+	JobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "jobs",
+		Name:      "duration_seconds",
+		Help:      "Automation job run time in seconds, by type.",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 15, 30, 60, 300, 900, 1800},
+	}, []string{"type"})
+
+	// TaskExecutions counts task manager executions by task type and result
+	TaskExecutions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "tasks",
+		Name:      "executions_total",
+		Help:      "Task executions by task type and result.",
+	}, []string{"type", "result"})
+
+	// HTTPRequestDuration tracks API latency by route
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency in seconds, by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// Task execution results
+const (
+	TaskResultSuccess        = "success"
+	TaskResultFailed         = "failed"
+	TaskResultManualRequired = "manual_required"
+)
+
+// SchedulerStats is implemented by the job scheduler
+type SchedulerStats interface {
+	QueueLength() int
+	WorkerCount() int
+	BusyWorkers() int
+}
+
+// Sources are the components whose state is exported as gauges. Nil fields are skipped.
+type Sources struct {
+	Scheduler SchedulerStats
+	DB        *sql.DB
+	Redis     *redis.Client
+}
+
+// NewRegistry creates a registry with the process, runtime and application collectors
+func NewRegistry(src Sources) *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		JobsProcessed,
+		JobDuration,
+		TaskExecutions,
+		HTTPRequestDuration,
+	)
+
+	if src.Scheduler != nil {
+		reg.MustRegister(
+			prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: "scheduler",
+				Name:      "queue_length",
+				Help:      "Jobs waiting in the scheduler queue.",
+			}, func() float64 { return float64(src.Scheduler.QueueLength()) }),
+			prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: "scheduler",
+				Name:      "workers",
+				Help:      "Scheduler workers started.",
+			}, func() float64 { return float64(src.Scheduler.WorkerCount()) }),
+			prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: "scheduler",
+				Name:      "workers_busy",
+				Help:      "Scheduler workers currently processing a job.",
+			}, func() float64 { return float64(src.Scheduler.BusyWorkers()) }),
+		)
+	}
+	if src.DB != nil {
+		reg.MustRegister(collectors.NewDBStatsCollector(src.DB, "postgres"))
+	}
+	if src.Redis != nil {
+		reg.MustRegister(newRedisPoolCollector(src.Redis))
+	}
+	return reg
+}
+
+// Handler serves the registry in the Prometheus text format
+func Handler(reg *prometheus.Registry) gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+}
+
+// Middleware records request latency. Unmatched paths share one label so
+// scanners cannot blow up the series count.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		HTTPRequestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// RequireToken protects the metrics endpoint with a static bearer token.
+// An empty token leaves it open.
+func RequireToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token != "" && c.GetHeader("Authorization") != "Bearer "+token {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	}
+}
+
+// redisPoolCollector exports go-redis connection pool stats
+type redisPoolCollector struct {
+	client *redis.Client
+
+	hits       *prometheus.Desc
+	misses     *prometheus.Desc
+	timeouts   *prometheus.Desc
+	totalConns *prometheus.Desc
+	idleConns  *prometheus.Desc
+	staleConns *prometheus.Desc
+}
+
+func newRedisPoolCollector(client *redis.Client) *redisPoolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "redis_pool", name), help, nil, nil)
+	}
+	return &redisPoolCollector{
+		client:     client,
+		hits:       desc("hits_total", "Times a free connection was found in the pool."),
+		misses:     desc("misses_total", "Times a free connection was not found in the pool."),
+		timeouts:   desc("timeouts_total", "Times a wait for a connection timed out."),
+		totalConns: desc("connections", "Connections in the pool."),
+		idleConns:  desc("idle_connections", "Idle connections in the pool."),
+		staleConns: desc("stale_connections_total", "Stale connections removed from the pool."),
+	}
+}
+
+func (c *redisPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.timeouts
+	ch <- c.totalConns
+	ch <- c.idleConns
+	ch <- c.staleConns
+}
+
+func (c *redisPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.timeouts, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.staleConns, prometheus.CounterValue, float64(stats.StaleConns))
+}
@@ -20,6 +20,7 @@ const (
 	ActionReply      AuditLogAction = "reply"
 	ActionQuote      AuditLogAction = "quote"
 	ActionDelete     AuditLogAction = "delete"
+	ActionDirectMessage AuditLogAction = "direct_message"
 	
 	// Wallet actions
 	ActionTransaction   AuditLogAction = "transaction"
@@ -28,6 +29,8 @@ const (
 	ActionBridge        AuditLogAction = "bridge"
 	ActionMint          AuditLogAction = "mint"
 	ActionClaim         AuditLogAction = "claim"
+	ActionSignature     AuditLogAction = "signature"
+	ActionSecretAccess  AuditLogAction = "secret_access" // A stored private key was decrypted
 	
 	// Content actions
 	ActionGenerate AuditLogAction = "generate"
@@ -41,6 +44,11 @@ const (
 	ActionAccountLink  AuditLogAction = "account_link"
 	ActionWalletCreate AuditLogAction = "wallet_create"
 	ActionWalletImport AuditLogAction = "wallet_import"
+	ActionWalletExport AuditLogAction = "wallet_export"
+	ActionWalletDelete AuditLogAction = "wallet_delete"
+	ActionWalletRestore AuditLogAction = "wallet_restore"
+	ActionAccountDelete AuditLogAction = "account_delete"
+	ActionAccountRestore AuditLogAction = "account_restore"
 	
 	// System actions
 	ActionTaskStart    AuditLogAction = "task_start"
@@ -104,6 +112,7 @@ type AuditLog struct {
 	
 	// Idempotency
 	IdempotencyKey string      `gorm:"size:200;uniqueIndex" json:"idempotency_key,omitempty"`
+	RequestID   string         `gorm:"size:128;index" json:"request_id,omitempty"` // HTTP request that started the action
 	
 	CreatedAt   time.Time      `gorm:"index" json:"created_at"`
 }
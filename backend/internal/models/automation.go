@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,13 +11,16 @@ import (
 type JobType string
 
 const (
-	JobTypeScheduledPost   JobType = "scheduled_post"
-	JobTypeCampaignTask    JobType = "campaign_task"
-	JobTypeBalanceSync     JobType = "balance_sync"
-	JobTypePlatformSync    JobType = "platform_sync"
-	JobTypeEngagement      JobType = "engagement"
-	JobTypeContentGenerate JobType = "content_generate"
-	JobTypeBulkExecute     JobType = "bulk_execute"
+	JobTypeScheduledPost    JobType = "scheduled_post"
+	JobTypeCampaignTask     JobType = "campaign_task"
+	JobTypeBalanceSync      JobType = "balance_sync"
+	JobTypeHistorySync      JobType = "history_sync" // Imports on-chain transaction history
+	JobTypePlatformSync     JobType = "platform_sync"
+	JobTypeEngagement       JobType = "engagement"
+	JobTypeContentGenerate  JobType = "content_generate"
+	JobTypeBulkExecute      JobType = "bulk_execute"
+	JobTypeWalletBulkCreate JobType = "wallet_bulk_create" // Runs in the API process, not the scheduler
+	JobTypeWarmup           JobType = "warmup"
 )
 
 type AutomationJob struct {
@@ -34,6 +38,10 @@ type AutomationJob struct {
 	// Status
 	IsActive     bool           `gorm:"default:true" json:"is_active"`
 	Status       string         `gorm:"size:30" json:"status"` // idle, running, paused, failed
+	ErrorCode    string         `gorm:"size:50" json:"error_code,omitempty"` // Why the last run failed, e.g. TIMEOUT
+	
+	// Run timeout; 0 uses the configured default
+	TimeoutSeconds int          `gorm:"default:0" json:"timeout_seconds"`
 	
 	// Configuration
 	Config       string         `gorm:"type:jsonb" json:"config"` // job-specific configuration
@@ -71,6 +79,65 @@ type JobLog struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// Job log levels
+const (
+	JobLogDebug   = "debug"
+	JobLogInfo    = "info"
+	JobLogSuccess = "success"
+	JobLogWarn    = "warn"
+	JobLogError   = "error"
+)
+
+// NewJobLog builds a log entry for a job. Details is marshalled so the stored
+// value is always valid JSON; nil details are stored as {}.
+func NewJobLog(jobID uuid.UUID, level, message string, details interface{}) *JobLog {
+	detailsJSON := []byte("{}")
+	if details != nil {
+		if raw, err := json.Marshal(details); err == nil && string(raw) != "null" {
+			detailsJSON = raw
+		}
+	}
+
+	return &JobLog{
+		ID:        uuid.New(),
+		JobID:     jobID,
+		Level:     level,
+		Message:   message,
+		Details:   string(detailsJSON),
+		CreatedAt: time.Now(),
+	}
+}
+
+// Dead-letter sources
+const (
+	DeadLetterSourceJob  = "job"  // Scheduler automation job
+	DeadLetterSourceTask = "task" // Task manager execution
+)
+
+// DeadLetterJob records a job or task execution that failed after its last retry,
+// with enough context to inspect and replay it
+type DeadLetterJob struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Source          string     `gorm:"size:20;not null" json:"source"` // job, task
+	JobID           *uuid.UUID `gorm:"type:uuid" json:"job_id,omitempty"`
+	TaskID          *uuid.UUID `gorm:"type:uuid" json:"task_id,omitempty"`
+	TaskExecutionID *uuid.UUID `gorm:"type:uuid" json:"task_execution_id,omitempty"`
+	Type            string     `gorm:"size:50" json:"type"` // Job type or task type
+	Name            string     `gorm:"size:200" json:"name"`
+	Attempts        int        `gorm:"default:0" json:"attempts"`
+	LastError       string     `gorm:"type:text" json:"last_error"`
+	Context         string     `gorm:"type:jsonb" json:"context,omitempty"` // Everything needed to replay
+
+	// Status: dead -> replayed
+	Status      string     `gorm:"size:20;default:'dead'" json:"status"`
+	ReplayCount int        `gorm:"default:0" json:"replay_count"`
+	ReplayedAt  *time.Time `json:"replayed_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 type ContentDraft struct {
 	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	UserID      uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
@@ -85,7 +152,12 @@ type ContentDraft struct {
 	Tone        string     `gorm:"size:30" json:"tone,omitempty"` // casual, professional, funny, etc.
 	
 	// Status: drafted -> awaiting_approval -> approved -> scheduled -> published -> failed
+	// Drafts failing moderation are "flagged" until explicitly approved
 	Status      string     `gorm:"size:30;default:'drafted'" json:"status"`
+
+	// Moderation
+	ModerationReason string `gorm:"type:text" json:"moderation_reason,omitempty"`
+	Moderation       string `gorm:"type:jsonb" json:"moderation,omitempty"` // ai.ModerationResult
 	
 	// Approval workflow
 	ApprovedAt   *time.Time `json:"approved_at,omitempty"`
@@ -103,6 +175,9 @@ type ContentDraft struct {
 	
 	// Engagement prediction
 	PredictedEngagement string `gorm:"type:jsonb" json:"predicted_engagement,omitempty"`
+
+	// Ordered posts when Type is "thread"; Content holds the parts joined for preview
+	ThreadParts []ThreadPart `gorm:"foreignKey:DraftID" json:"thread_parts,omitempty"`
 	
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
@@ -133,7 +208,27 @@ type ScheduledPost struct {
 	PostID        string     `gorm:"size:200" json:"post_id,omitempty"` // ID of the actual post
 	PostURL       string     `gorm:"size:500" json:"post_url,omitempty"`
 	ErrorMessage  string     `gorm:"type:text" json:"error_message,omitempty"`
+
+	// Thread parts published in order, each replying to the previous one
+	ThreadParts   []ThreadPart `gorm:"foreignKey:ScheduledPostID" json:"thread_parts,omitempty"`
 	
 	CreatedAt     time.Time  `json:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at"`
 }
+
+// ThreadPart is one post of a thread. Parts belong to a draft while editing and
+// are copied to the scheduled post, which tracks publishing per part.
+type ThreadPart struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	DraftID         *uuid.UUID `gorm:"type:uuid;index" json:"draft_id,omitempty"`
+	ScheduledPostID *uuid.UUID `gorm:"type:uuid;index" json:"scheduled_post_id,omitempty"`
+	Position        int        `gorm:"not null" json:"position"` // 0-based order in the thread
+	Content         string     `gorm:"type:text;not null" json:"content"`
+	Status          string     `gorm:"size:30;default:'pending'" json:"status"` // pending, posted, failed
+	PostID          string     `gorm:"size:200" json:"post_id,omitempty"`
+	PostURL         string     `gorm:"size:500" json:"post_url,omitempty"`
+	ErrorMessage    string     `gorm:"type:text" json:"error_message,omitempty"`
+	PostedAt        *time.Time `json:"posted_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
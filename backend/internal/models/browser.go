@@ -24,8 +24,9 @@ type BrowserProfile struct {
 	WebGLVendor     string         `gorm:"size:200" json:"webgl_vendor"`
 	WebGLRenderer   string         `gorm:"size:200" json:"webgl_renderer"`
 	
-	// Proxy settings
+	// Proxy settings; with a pool, ProxyID is the proxy the pool last handed out
 	ProxyID         *uuid.UUID     `gorm:"type:uuid" json:"proxy_id,omitempty"`
+	ProxyPoolID     *uuid.UUID     `gorm:"type:uuid" json:"proxy_pool_id,omitempty"`
 	
 	// Cookie storage path
 	CookiePath      string         `gorm:"size:500" json:"cookie_path"`
@@ -66,10 +67,16 @@ type BrowserSession struct {
 	ManualRequired  bool       `gorm:"default:false" json:"manual_required"`
 	ManualMessage   string     `gorm:"type:text" json:"manual_message,omitempty"`
 	
+	// Recording: opt-in screenshot timeline stored under RecordingPath
+	Recording       bool       `gorm:"default:false" json:"recording"`
+	RecordingPath   string     `gorm:"size:500" json:"-"`
+	RecordingFrames int        `gorm:"default:0" json:"recording_frames"`
+	
 	// Timing
 	StartedAt       time.Time  `json:"started_at"`
 	LastActivityAt  time.Time  `json:"last_activity_at"`
 	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	StoppedAt       *time.Time `json:"stopped_at,omitempty"`
 	
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
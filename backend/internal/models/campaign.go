@@ -36,6 +36,9 @@ type Campaign struct {
 	Status   string `gorm:"size:30;default:'active'" json:"status"` // active, paused, completed, expired
 	Priority int    `gorm:"default:0" json:"priority"`
 
+	// Deadline reminder windows already sent, e.g. ["24h","1h"]
+	RemindersSent string `gorm:"type:jsonb;default:'[]'" json:"reminders_sent,omitempty"`
+
 	// Rewards
 	EstimatedReward string `gorm:"size:100" json:"estimated_reward"`
 	RewardType      string `gorm:"size:50" json:"reward_type"` // token, nft, points, unknown
@@ -49,9 +52,15 @@ type Campaign struct {
 	CompletedTasks  int     `json:"completed_tasks"`
 	ProgressPercent float64 `json:"progress_percent"`
 
+	// Accounts below this warmup score don't execute its tasks; 0 disables
+	MinWarmupScore int `gorm:"default:0" json:"min_warmup_score"`
+
 	// Metadata
 	Metadata string `gorm:"type:jsonb" json:"metadata"`
 
+	// Bumped by every edit; updates carrying an older version are rejected
+	Version int `gorm:"not null;default:1" json:"version"`
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -60,18 +69,20 @@ type Campaign struct {
 type TaskType string
 
 const (
-	TaskTypeConnect     TaskType = "wallet_connect"
-	TaskTypeTransaction TaskType = "transaction"
-	TaskTypeClaim       TaskType = "claim"
-	TaskTypeFollow      TaskType = "follow"
-	TaskTypeJoin        TaskType = "join"
-	TaskTypePost        TaskType = "post"
-	TaskTypeReply       TaskType = "reply"
-	TaskTypeLike        TaskType = "like"
-	TaskTypeRecast      TaskType = "recast"
-	TaskTypeVerify      TaskType = "verify"
-	TaskTypeQuiz        TaskType = "quiz"
-	TaskTypeCustom      TaskType = "custom"
+	TaskTypeConnect       TaskType = "wallet_connect"
+	TaskTypeTransaction   TaskType = "transaction"
+	TaskTypeClaim         TaskType = "claim"
+	TaskTypeApprove       TaskType = "approve"
+	TaskTypeFollow        TaskType = "follow"
+	TaskTypeJoin          TaskType = "join"
+	TaskTypePost          TaskType = "post"
+	TaskTypeReply         TaskType = "reply"
+	TaskTypeLike          TaskType = "like"
+	TaskTypeRecast        TaskType = "recast"
+	TaskTypeDirectMessage TaskType = "direct_message"
+	TaskTypeVerify        TaskType = "verify"
+	TaskTypeQuiz          TaskType = "quiz"
+	TaskTypeCustom        TaskType = "custom"
 )
 
 type CampaignTask struct {
@@ -92,6 +103,7 @@ type CampaignTask struct {
 	IsAutomatable    bool   `gorm:"default:true" json:"is_automatable"`
 	AutomationScript string `gorm:"type:text" json:"automation_script,omitempty"`
 	RequiresManual   bool   `gorm:"default:false" json:"requires_manual"` // needs human intervention
+	VerifyAfter      bool   `gorm:"default:false" json:"verify_after"`    // confirm proof with the platform before completing
 
 	// Order and dependencies
 	Order     int        `gorm:"default:0" json:"order"`
@@ -103,10 +115,74 @@ type CampaignTask struct {
 	// Execution tracking
 	Executions []TaskExecution `gorm:"foreignKey:TaskID" json:"executions,omitempty"`
 
+	// Bumped by every edit; updates carrying an older version are rejected
+	Version int `gorm:"not null;default:1" json:"version"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// TaskExecutionStatus is the status of a task execution. These lowercase values
+// are canonical for executions from TaskService and tasks.TaskManager alike.
+type TaskExecutionStatus string
+
+const (
+	ExecutionPending       TaskExecutionStatus = "pending"        // Created, or waiting on the browser
+	ExecutionInProgress    TaskExecutionStatus = "in_progress"    // Running
+	ExecutionWaitingManual TaskExecutionStatus = "waiting_manual" // Waiting for the user to act
+	ExecutionVerifying     TaskExecutionStatus = "verifying"      // Proof is being confirmed with the platform
+	ExecutionCompleted     TaskExecutionStatus = "completed"
+	ExecutionUnverified    TaskExecutionStatus = "unverified" // Done, but the platform could not confirm it
+	ExecutionFailed        TaskExecutionStatus = "failed"
+	ExecutionSkipped       TaskExecutionStatus = "skipped" // Cancelled, or not run
+)
+
+// TxStatus is the on-chain status of a task execution's transaction
+type TxStatus string
+
+const (
+	TxPending   TxStatus = "pending"   // Broadcast, waiting to be mined
+	TxConfirmed TxStatus = "confirmed" // Mined and succeeded
+	TxReverted  TxStatus = "reverted"  // Mined but reverted
+	TxDropped   TxStatus = "dropped"   // Not mined before the timeout: dropped or replaced
+)
+
+// legacyExecutionStatuses maps the uppercase values tasks.TaskManager used to
+// store, and the scheduler's "running", to canonical statuses. Migration 028
+// rewrites existing rows; queries match both spellings during the transition.
+var legacyExecutionStatuses = map[string]TaskExecutionStatus{
+	"PENDING":         ExecutionPending,
+	"RUNNING":         ExecutionInProgress,
+	"running":         ExecutionInProgress,
+	"MANUAL_REQUIRED": ExecutionWaitingManual,
+	"DONE":            ExecutionCompleted,
+	"FAILED":          ExecutionFailed,
+	"SKIPPED":         ExecutionSkipped,
+}
+
+// NormalizeExecutionStatus returns the canonical spelling of a stored status
+func NormalizeExecutionStatus(status TaskExecutionStatus) TaskExecutionStatus {
+	if canonical, ok := legacyExecutionStatuses[string(status)]; ok {
+		return canonical
+	}
+	return status
+}
+
+// ExecutionStatusValues returns every stored spelling of statuses, canonical
+// and legacy, for queries that must also count rows written before normalization
+func ExecutionStatusValues(statuses ...TaskExecutionStatus) []string {
+	values := make([]string, 0, len(statuses)*2)
+	for _, status := range statuses {
+		values = append(values, string(status))
+		for legacy, canonical := range legacyExecutionStatuses {
+			if canonical == status {
+				values = append(values, legacy)
+			}
+		}
+	}
+	return values
+}
+
 type TaskExecution struct {
 	ID        uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	TaskID    uuid.UUID     `gorm:"type:uuid;not null" json:"task_id"`
@@ -114,9 +190,9 @@ type TaskExecution struct {
 	WalletID  *uuid.UUID    `gorm:"type:uuid" json:"wallet_id,omitempty"`
 	AccountID *uuid.UUID    `gorm:"type:uuid" json:"account_id,omitempty"`
 
-	Status      string     `gorm:"size:30;not null" json:"status"` // pending, in_progress, waiting_manual, completed, failed, skipped
-	StartedAt   time.Time  `json:"started_at"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Status      TaskExecutionStatus `gorm:"size:30;not null" json:"status"`
+	StartedAt   time.Time           `json:"started_at"`
+	CompletedAt *time.Time          `json:"completed_at,omitempty"`
 
 	// Idempotency - prevents duplicate executions
 	IdempotencyKey string `gorm:"size:200;uniqueIndex" json:"idempotency_key"` // taskID+accountID+date or taskID+walletID+date
@@ -124,8 +200,9 @@ type TaskExecution struct {
 	// Proof of completion
 	ProofType      string `gorm:"size:50" json:"proof_type,omitempty"` // post_url, tx_hash, cast_hash, screenshot
 	ProofValue     string `gorm:"size:500" json:"proof_value,omitempty"`
-	ProofData      string `gorm:"type:jsonb" json:"proof_data,omitempty"` // Full proof object
-	ScreenshotPath string `gorm:"size:500" json:"screenshot_path,omitempty"`
+	ProofData      string `gorm:"type:jsonb" json:"proof_data,omitempty"`    // Full proof object
+	ScreenshotPath string `gorm:"size:500" json:"screenshot_path,omitempty"` // Storage key
+	ScreenshotURL  string `gorm:"-" json:"screenshot_url,omitempty"`         // Signed URL, filled in when served
 
 	// Result
 	TransactionHash string `gorm:"size:100" json:"transaction_hash,omitempty"`
@@ -133,6 +210,14 @@ type TaskExecution struct {
 	PostURL         string `gorm:"size:500" json:"post_url,omitempty"`
 	ResultData      string `gorm:"type:jsonb" json:"result_data,omitempty"`
 	ErrorMessage    string `gorm:"type:text" json:"error_message,omitempty"`
+	ErrorCode       string `gorm:"size:50" json:"error_code,omitempty"` // Machine-readable failure class, see tasks.ErrorCodeFor
+
+	// On-chain outcome of TransactionHash, filled in by the receipt poller. The
+	// execution stays verifying while TxStatus is pending.
+	TxStatus      TxStatus   `gorm:"size:20" json:"tx_status,omitempty"`
+	TxSubmittedAt *time.Time `json:"tx_submitted_at,omitempty"` // When the hash was recorded; the drop timeout counts from here
+	GasUsed       uint64     `json:"gas_used,omitempty"`
+	BlockNumber   uint64     `json:"block_number,omitempty"`
 
 	// Browser session
 	BrowserSessionID *uuid.UUID `gorm:"type:uuid" json:"browser_session_id,omitempty"`
@@ -147,3 +232,29 @@ type TaskExecution struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// AfterFind reports rows stored before status normalization with canonical statuses
+func (e *TaskExecution) AfterFind(tx *gorm.DB) error {
+	e.Status = NormalizeExecutionStatus(e.Status)
+	return nil
+}
+
+// ScheduledTaskExecution is a task execution requested for a later time. The
+// scheduler runs it through the normal execution path once RunAt passes.
+type ScheduledTaskExecution struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	TaskID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"task_id"`
+	WalletID  *uuid.UUID `gorm:"type:uuid" json:"wallet_id,omitempty"`
+	AccountID *uuid.UUID `gorm:"type:uuid" json:"account_id,omitempty"`
+	Force     bool       `gorm:"default:false" json:"force"`
+
+	RunAt        time.Time  `gorm:"not null;index" json:"run_at"`
+	Status       string     `gorm:"size:30;default:'pending'" json:"status"` // pending, running, completed, failed, cancelled
+	ExecutionID  *uuid.UUID `gorm:"type:uuid" json:"execution_id,omitempty"` // The TaskExecution it produced
+	ErrorMessage string     `gorm:"type:text" json:"error_message,omitempty"`
+	RanAt        *time.Time `json:"ran_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CampaignTemplate is a reusable campaign definition with no user data. Text may
+// hold {{param}} placeholders that are filled in when a campaign is created from it.
+type CampaignTemplate struct {
+	ID               uuid.UUID    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID           *uuid.UUID   `gorm:"type:uuid;index" json:"user_id,omitempty"` // Author
+	SourceCampaignID *uuid.UUID   `gorm:"type:uuid" json:"-"`
+	Name             string       `gorm:"size:200;not null" json:"name"`
+	Description      string       `gorm:"type:text" json:"description"`
+	IsPublic         bool         `gorm:"default:false;index" json:"is_public"`
+	Type             CampaignType `gorm:"size:50;not null" json:"type"`
+	URL              string       `gorm:"size:500" json:"url"`
+	ImageURL         string       `gorm:"size:500" json:"image_url"`
+	EstimatedReward  string       `gorm:"size:100" json:"estimated_reward"`
+	RewardType       string       `gorm:"size:50" json:"reward_type"`
+	Metadata         string       `gorm:"type:jsonb" json:"metadata,omitempty"`
+	Tasks            string       `gorm:"type:jsonb" json:"tasks"`  // []TemplateTask
+	Params           string       `gorm:"type:jsonb" json:"params"` // Placeholder names
+	UsageCount       int          `gorm:"default:0" json:"usage_count"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
+
+// TemplateTask is a campaign task without IDs. Dependencies refer to another
+// task's Key, which is stable within one template or export.
+type TemplateTask struct {
+	Key              string   `json:"key"`
+	Name             string   `json:"name"`
+	Description      string   `json:"description,omitempty"`
+	Type             TaskType `json:"type"`
+	TargetURL        string   `json:"target_url,omitempty"`
+	TargetPlatform   string   `json:"target_platform,omitempty"`
+	TargetAccount    string   `json:"target_account,omitempty"`
+	RequiredAction   string   `json:"required_action,omitempty"`
+	Config           string   `json:"config,omitempty"`
+	IsAutomatable    bool     `json:"is_automatable"`
+	AutomationScript string   `json:"automation_script,omitempty"`
+	RequiresManual   bool     `json:"requires_manual"`
+	VerifyAfter      bool     `json:"verify_after"`
+	Order            int      `json:"order"`
+	DependsOn        string   `json:"depends_on,omitempty"` // Key of another task
+	Points           int      `json:"points"`
+}
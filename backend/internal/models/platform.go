@@ -14,8 +14,58 @@ const (
 	PlatformTwitter   PlatformType = "twitter"
 	PlatformTelegram  PlatformType = "telegram"
 	PlatformDiscord   PlatformType = "discord"
+	PlatformLens      PlatformType = "lens"
 )
 
+// Account health, as last seen by a health check
+const (
+	AccountHealthActive      = "active"
+	AccountHealthSuspended   = "suspended" // Banned, suspended or deleted; the account is deactivated
+	AccountHealthRateLimited = "rate_limited"
+	AccountHealthError       = "error"
+)
+
+// Account warmup statuses
+const (
+	WarmupNone    = "none"
+	WarmupWarming = "warming" // Warmup jobs act for the account
+	WarmupWarm    = "warm"    // Score reached 100
+	WarmupPaused  = "paused"
+)
+
+// WarmupConfig schedules an account's warmup. Daily actions ramp linearly from
+// MinDailyActions on the first day to MaxDailyActions on the last.
+type WarmupConfig struct {
+	Days            int      `json:"days"`
+	MinDailyActions int      `json:"min_daily_actions"`
+	MaxDailyActions int      `json:"max_daily_actions"`
+	Channels        []string `json:"channels,omitempty"` // Farcaster channels browsed; empty uses the defaults
+}
+
+// Warmup defaults
+const (
+	DefaultWarmupDays            = 14
+	DefaultWarmupMinDailyActions = 2
+	DefaultWarmupMaxDailyActions = 15
+)
+
+// WithDefaults fills unset fields with the warmup defaults
+func (c WarmupConfig) WithDefaults() WarmupConfig {
+	if c.Days <= 0 {
+		c.Days = DefaultWarmupDays
+	}
+	if c.MinDailyActions <= 0 {
+		c.MinDailyActions = DefaultWarmupMinDailyActions
+	}
+	if c.MaxDailyActions <= 0 {
+		c.MaxDailyActions = DefaultWarmupMaxDailyActions
+	}
+	if c.MaxDailyActions < c.MinDailyActions {
+		c.MaxDailyActions = c.MinDailyActions
+	}
+	return c
+}
+
 type PlatformAccount struct {
 	ID               uuid.UUID         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	UserID           uuid.UUID         `gorm:"type:uuid;not null" json:"user_id"`
@@ -38,14 +88,29 @@ type PlatformAccount struct {
 	IsActive         bool              `gorm:"default:true" json:"is_active"`
 	LastLoginAt      time.Time         `json:"last_login_at"`
 	LastActivityAt   time.Time         `json:"last_activity_at"`
+	Health           string            `gorm:"size:20;default:'active'" json:"health"`
+	HealthReason     string            `gorm:"type:text" json:"health_reason,omitempty"`
+	HealthCheckedAt  *time.Time        `json:"health_checked_at,omitempty"`
 	
 	// Stats
 	FollowerCount    int               `json:"follower_count"`
 	FollowingCount   int               `json:"following_count"`
 	PostCount        int               `json:"post_count"`
 	
-	// Proxy settings
+	// Proxy settings; with a pool, ProxyID is the proxy the pool last handed out
 	ProxyID          *uuid.UUID        `gorm:"type:uuid" json:"proxy_id,omitempty"`
+	ProxyPoolID      *uuid.UUID        `gorm:"type:uuid" json:"proxy_pool_id,omitempty"`
+
+	// Rate limit override for accounts with elevated API access, e.g. "300/15m"
+	RateLimit        string            `gorm:"size:50" json:"rate_limit,omitempty"`
+
+	// Warmup: light organic activity before the account takes on campaign tasks
+	WarmupStatus     string            `gorm:"size:20;default:'none'" json:"warmup_status"`
+	WarmupScore      int               `gorm:"default:0" json:"warmup_score"` // 0-100, the share of warmup days done
+	WarmupConfig     string            `gorm:"type:jsonb" json:"warmup_config,omitempty"`
+	WarmupDaysDone   int               `gorm:"default:0" json:"warmup_days_done"` // UTC days with at least one warmup action
+	WarmupStartedAt  *time.Time        `json:"warmup_started_at,omitempty"`
+	WarmupLastActionAt *time.Time      `json:"warmup_last_action_at,omitempty"`
 	
 	// Relations
 	Activities       []AccountActivity `gorm:"foreignKey:AccountID" json:"activities,omitempty"`
@@ -67,7 +132,7 @@ type AccountActivity struct {
 	Status      string       `gorm:"size:30" json:"status"` // success, failed, pending
 	ErrorMsg    string       `gorm:"type:text" json:"error_msg,omitempty"`
 	CampaignID  *uuid.UUID   `gorm:"type:uuid" json:"campaign_id,omitempty"`
-	AutomatedBy string       `gorm:"size:50" json:"automated_by"` // manual, scheduled, ai, campaign
+	AutomatedBy string       `gorm:"size:50" json:"automated_by"` // manual, or the ID of the job or task that took the action
 	CreatedAt   time.Time    `json:"created_at"`
 }
 
@@ -84,6 +149,27 @@ type Proxy struct {
 	IsActive  bool           `gorm:"default:true" json:"is_active"`
 	LastCheck time.Time      `json:"last_check"`
 	Latency   int            `json:"latency"` // in milliseconds
+
+	// Rotation: unhealthy proxies are out of rotation until a passing Test or health check
+	PoolID              *uuid.UUID `gorm:"type:uuid;index" json:"pool_id,omitempty"`
+	IsHealthy           bool       `gorm:"default:true" json:"is_healthy"`
+	LastUsedAt          *time.Time `json:"last_used_at,omitempty"`
+	LastError           string     `gorm:"type:text" json:"last_error,omitempty"`
+	SuccessCount        int64      `gorm:"default:0" json:"success_count"`
+	FailureCount        int64      `gorm:"default:0" json:"failure_count"`
+	ConsecutiveFailures int        `gorm:"default:0" json:"consecutive_failures"`
+
+	// Health checks: DisabledAt is set when repeated failed checks deactivated the proxy
+	ExternalIP string     `gorm:"size:45" json:"external_ip,omitempty"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+
+	// Exit IP geo data from the last Test; Country above is what the user declared
+	GeoCountry  string `gorm:"size:10" json:"geo_country,omitempty"`
+	GeoCity     string `gorm:"size:100" json:"geo_city,omitempty"`
+	GeoTimezone string `gorm:"size:50" json:"geo_timezone,omitempty"`
+	GeoASN      string `gorm:"size:20" json:"geo_asn,omitempty"`
+	GeoOrg      string `gorm:"size:200" json:"geo_org,omitempty"`
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Proxy pool rotation policies
+const (
+	ProxyPolicyRoundRobin = "round_robin"
+	ProxyPolicyLRU        = "least_recently_used"
+	ProxyPolicySticky     = "sticky" // Each account keeps its proxy until that proxy dies
+)
+
+// ProxyPool is a set of interchangeable proxies. Accounts and browser profiles that
+// reference a pool are handed a healthy member by its policy instead of a fixed proxy.
+type ProxyPool struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name      string         `gorm:"size:100;not null" json:"name"`
+	Policy    string         `gorm:"size:30;default:'round_robin'" json:"policy"`
+	Proxies   []Proxy        `gorm:"foreignKey:PoolID" json:"proxies,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
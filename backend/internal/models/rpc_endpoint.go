@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RPCEndpoint is a configured RPC URL for a chain. Endpoints without a user are
+// shared by everyone and managed by admins; a user's own endpoints are tried first.
+type RPCEndpoint struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    *uuid.UUID `gorm:"type:uuid;index" json:"user_id,omitempty"`
+	ChainID   int64      `gorm:"not null;index" json:"chain_id"` // EVM chain ID, 101 for Solana
+	Name      string     `gorm:"size:100" json:"name"`
+	URL       string     `gorm:"size:500;not null" json:"url"`      // May contain {api_key}
+	Priority  int        `gorm:"default:0" json:"priority"`         // Lower is tried first
+	APIKey    string     `gorm:"column:api_key;type:text" json:"-"` // Encrypted with ENCRYPTION_KEY
+	HasAPIKey bool       `gorm:"-" json:"has_api_key"`
+	IsActive  bool       `gorm:"default:true" json:"is_active"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
@@ -7,6 +7,12 @@ import (
 	"gorm.io/gorm"
 )
 
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin" // May use the /admin endpoints and publish shared templates
+)
+
 type User struct {
 	ID            uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	Email         string         `gorm:"size:255;uniqueIndex;not null" json:"email"`
@@ -22,6 +28,10 @@ type User struct {
 	// Status
 	IsActive      bool           `gorm:"default:true" json:"is_active"`
 	LastLoginAt   *time.Time     `json:"last_login_at,omitempty"`
+	TOTPEnabled   bool           `gorm:"default:false" json:"totp_enabled"`
+	TOTPLastStep  uint64         `gorm:"default:0" json:"-"` // Time step of the last accepted TOTP code
+	Role          string         `gorm:"size:20;not null;default:'user'" json:"role"`
+	EmailVerifiedAt *time.Time   `json:"email_verified_at,omitempty"`
 	
 	// Relations
 	Wallets          []Wallet          `gorm:"foreignKey:UserID" json:"wallets,omitempty"`
@@ -15,25 +15,28 @@ const (
 )
 
 type Wallet struct {
-	ID              uuid.UUID         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	UserID          uuid.UUID         `gorm:"type:uuid;not null" json:"user_id"`
-	Name            string            `gorm:"size:100" json:"name"`
-	Address         string            `gorm:"size:100;not null;uniqueIndex" json:"address"`
-	Type            WalletType        `gorm:"size:20;not null" json:"type"`
-	ChainID         int               `gorm:"default:1" json:"chain_id"` // 1=Ethereum, 56=BSC, 137=Polygon, etc.
-	EncryptedKey    string            `gorm:"type:text" json:"-"`        // Encrypted private key (stored securely)
-	PublicKey       string            `gorm:"size:200" json:"public_key"`
-	IsImported      bool              `gorm:"default:false" json:"is_imported"`
-	IsWatchOnly     bool              `gorm:"default:false" json:"is_watch_only"`
-	Balance         string            `gorm:"size:100;default:'0'" json:"balance"`
-	LastBalanceSync time.Time         `json:"last_balance_sync"`
-	Tags            []WalletTag       `gorm:"many2many:wallet_wallet_tags;" json:"tags"`
-	Groups          []WalletGroup     `gorm:"many2many:wallet_groups_wallets;" json:"groups"`
-	LinkedAccounts  []PlatformAccount `gorm:"foreignKey:WalletID" json:"linked_accounts"`
-	Transactions    []Transaction     `gorm:"foreignKey:WalletID" json:"transactions,omitempty"`
-	CreatedAt       time.Time         `json:"created_at"`
-	UpdatedAt       time.Time         `json:"updated_at"`
-	DeletedAt       gorm.DeletedAt    `gorm:"index" json:"-"`
+	ID                 uuid.UUID         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID             uuid.UUID         `gorm:"type:uuid;not null" json:"user_id"`
+	Name               string            `gorm:"size:100" json:"name"`
+	Address            string            `gorm:"size:100;not null;uniqueIndex" json:"address"`
+	Type               WalletType        `gorm:"size:20;not null" json:"type"`
+	ChainID            int               `gorm:"default:1" json:"chain_id"`                                // 1=Ethereum, 56=BSC, 137=Polygon, etc.
+	ExtraChainIDs      string            `gorm:"type:jsonb;default:'[]'" json:"extra_chain_ids,omitempty"` // array of other chain IDs the wallet is active on
+	EncryptedKey       string            `gorm:"type:text" json:"-"`                                       // Encrypted private key (stored securely)
+	PublicKey          string            `gorm:"size:200" json:"public_key"`
+	IsImported         bool              `gorm:"default:false" json:"is_imported"`
+	IsWatchOnly        bool              `gorm:"default:false" json:"is_watch_only"`
+	AllowServerSigning bool              `gorm:"default:false" json:"allow_server_signing"` // Lets the server sign with the stored key
+	Balance            string            `gorm:"size:100;default:'0'" json:"balance"`
+	LastBalanceSync    time.Time         `json:"last_balance_sync"`
+	LastSyncError      string            `gorm:"type:text" json:"last_sync_error,omitempty"` // Cleared on the next successful sync
+	Tags               []WalletTag       `gorm:"many2many:wallet_wallet_tags;" json:"tags"`
+	Groups             []WalletGroup     `gorm:"many2many:wallet_groups_wallets;" json:"groups"`
+	LinkedAccounts     []PlatformAccount `gorm:"foreignKey:WalletID" json:"linked_accounts"`
+	Transactions       []Transaction     `gorm:"foreignKey:WalletID" json:"transactions,omitempty"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt    `gorm:"index" json:"-"`
 }
 
 type WalletTag struct {
@@ -66,7 +69,7 @@ type Transaction struct {
 	Value           string     `gorm:"size:100" json:"value"`
 	GasUsed         string     `gorm:"size:50" json:"gas_used"`
 	GasPrice        string     `gorm:"size:50" json:"gas_price"`
-	Status          string     `gorm:"size:20" json:"status"` // pending, success, failed
+	Status          string     `gorm:"size:20" json:"status"` // pending, success, failed, dropped
 	BlockNumber     int64      `json:"block_number"`
 	Timestamp       time.Time  `json:"timestamp"`
 	RawTransaction  string     `gorm:"type:text" json:"raw_transaction,omitempty"`
@@ -78,9 +81,11 @@ type Transaction struct {
 // WalletBalance represents cached balance info
 type WalletBalance struct {
 	Address       string         `json:"address"`
+	ChainID       int64          `json:"chain_id"`
 	NativeBalance string         `json:"native_balance"`
 	Tokens        []TokenBalance `json:"tokens"`
 	UpdatedAt     time.Time      `json:"updated_at"`
+	Error         string         `json:"error,omitempty"` // Set when the chain could not be read
 }
 
 type TokenBalance struct {
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook events
+const (
+	WebhookEventTaskCompleted     = "task.completed"
+	WebhookEventTaskFailed        = "task.failed"
+	WebhookEventJobCompleted      = "job.completed"
+	WebhookEventJobFailed         = "job.failed"
+	WebhookEventCampaignCompleted = "campaign.completed"
+	WebhookEventCampaignDeadline  = "campaign.deadline_approaching"
+	WebhookEventProxyDown         = "proxy.down"
+	WebhookEventTest              = "webhook.test"
+)
+
+// WebhookEvents lists the events a webhook can subscribe to
+var WebhookEvents = []string{
+	WebhookEventTaskCompleted,
+	WebhookEventTaskFailed,
+	WebhookEventJobCompleted,
+	WebhookEventJobFailed,
+	WebhookEventCampaignCompleted,
+	WebhookEventCampaignDeadline,
+	WebhookEventProxyDown,
+}
+
+// Webhook is a user's endpoint to notify about events. Payloads are signed with
+// HMAC-SHA256 over the body using Secret.
+type Webhook struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	URL         string    `gorm:"size:500;not null" json:"url"`
+	Description string    `gorm:"size:200" json:"description"`
+	Secret      string    `gorm:"size:100;not null" json:"-"`
+	Events      string    `gorm:"type:jsonb" json:"events"` // Array of event names
+	IsActive    bool      `gorm:"default:true" json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery records one event sent to a webhook and its delivery attempts
+type WebhookDelivery struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	WebhookID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"webhook_id"`
+	Event          string     `gorm:"size:50;not null" json:"event"`
+	Payload        string     `gorm:"type:jsonb" json:"payload"`
+	Status         string     `gorm:"size:20;not null;index" json:"status"` // pending, delivered, failed
+	Attempts       int        `gorm:"default:0" json:"attempts"`
+	ResponseStatus int        `json:"response_status,omitempty"`
+	LastError      string     `gorm:"type:text" json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,100 @@
+// Package netguard keeps outbound requests to user-supplied URLs away from the
+// server's own network: loopback, private, link-local (including the cloud
+// metadata address) and unspecified addresses are refused when dialing.
+package netguard
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrBlockedDestination is returned when a URL points at an address outbound
+// requests may not reach
+var ErrBlockedDestination = errors.New("destination address is not allowed")
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which
+// net.IP.IsPrivate does not cover
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// Blocked reports whether ip is one outbound requests may not reach
+func Blocked(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip)
+}
+
+// CheckURL rejects URLs that are not http(s), and ones whose host is a blocked
+// IP literal or localhost. Hostnames are checked again at dial time, after they
+// resolve, so a name pointing at a blocked address still fails then.
+func CheckURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.New("URL must be an absolute http(s) URL")
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrBlockedDestination
+	}
+	if ip := net.ParseIP(host); ip != nil && Blocked(ip) {
+		return ErrBlockedDestination
+	}
+	return nil
+}
+
+// Dialer returns a dialer that refuses to connect to blocked addresses. The
+// check runs on the resolved address of every connection, so DNS rebinding
+// cannot slip past it.
+func Dialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || Blocked(ip) {
+				return ErrBlockedDestination
+			}
+			return nil
+		},
+	}
+}
+
+// Client returns an HTTP client whose connections go through Dialer. It never
+// uses a proxy, which would dial on its behalf. With followRedirects false a
+// redirect is returned as the response rather than followed.
+func Client(timeout time.Duration, followRedirects bool) *http.Client {
+	dialer := Dialer(10 * time.Second)
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          20,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+	if !followRedirects {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	return client
+}
+
+// IsBlocked reports whether err came from refusing a blocked destination
+func IsBlocked(err error) bool {
+	return errors.Is(err, ErrBlockedDestination)
+}
@@ -0,0 +1,67 @@
+package netguard
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBlocked(t *testing.T) {
+	cases := map[string]bool{
+		"127.0.0.1":       true,
+		"10.1.2.3":        true,
+		"172.16.0.1":      true,
+		"192.168.1.1":     true,
+		"169.254.169.254": true,
+		"100.64.0.1":      true,
+		"0.0.0.0":         true,
+		"::1":             true,
+		"fe80::1":         true,
+		"fd00::1":         true,
+		"::ffff:10.0.0.1": true,
+		"8.8.8.8":         false,
+		"2606:4700::1111": false,
+	}
+	for addr, want := range cases {
+		if got := Blocked(net.ParseIP(addr)); got != want {
+			t.Errorf("Blocked(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	cases := map[string]bool{
+		"https://hooks.example.com/x":         true,
+		"http://8.8.8.8/":                     true,
+		"ftp://hooks.example.com":             false,
+		"/relative":                           false,
+		"http://localhost:8080/":              false,
+		"http://api.localhost/":               false,
+		"http://127.0.0.1/":                   false,
+		"http://169.254.169.254/latest/meta/": false,
+		"http://[::1]:9000/":                  false,
+	}
+	for raw, ok := range cases {
+		if err := CheckURL(raw); (err == nil) != ok {
+			t.Errorf("CheckURL(%q) = %v, want ok=%v", raw, err, ok)
+		}
+	}
+}
+
+func TestClientRefusesLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer server.Close()
+
+	resp, err := Client(5*time.Second, false).Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatalf("request to %s succeeded with status %d", server.URL, resp.StatusCode)
+	}
+	if !IsBlocked(err) {
+		t.Fatalf("error = %v, want ErrBlockedDestination", err)
+	}
+}
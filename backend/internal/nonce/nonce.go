@@ -0,0 +1,159 @@
+// Package nonce hands out EVM transaction nonces per wallet so concurrent
+// prepares and sends from one address get distinct, sequential nonces. Each
+// nonce handed out is reserved for TTL: an allocation takes the lowest nonce at
+// or above the chain's pending nonce that no live reservation holds. A
+// reservation ends when its transaction reaches the chain, when it is released,
+// or when it lapses, so a prepare that is never signed and sent gives its nonce
+// back instead of holding later transactions behind a gap.
+package nonce
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-redis/redis/v8"
+)
+
+const keyPrefix = "web3airdropos:nonces:"
+
+// PendingNoncer reports an address's next nonce including mempool transactions.
+// *ethclient.Client satisfies it.
+type PendingNoncer interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// allocateScript drops reservations that lapsed or that the chain has passed,
+// then reserves the lowest free nonce from the chain's pending one. Reservations
+// are members of a sorted set scored by when they lapse.
+var allocateScript = redis.NewScript(`
+	local pending = tonumber(ARGV[1])
+	local now = tonumber(ARGV[2])
+	redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now)
+	local held = {}
+	for _, member in ipairs(redis.call("ZRANGE", KEYS[1], 0, -1)) do
+		local n = tonumber(member)
+		if n < pending then
+			redis.call("ZREM", KEYS[1], member)
+		else
+			held[n] = true
+		end
+	end
+	local nonce = pending
+	while held[nonce] do
+		nonce = nonce + 1
+	end
+	redis.call("ZADD", KEYS[1], now + tonumber(ARGV[3]), string.format("%d", nonce))
+	redis.call("PEXPIRE", KEYS[1], ARGV[3])
+	return nonce
+`)
+
+// Manager allocates nonces. With Redis, reservations are shared by every
+// instance; without it they are tracked in memory for this process only.
+type Manager struct {
+	redis *redis.Client
+	ttl   time.Duration
+
+	mu    sync.Mutex
+	local map[string]map[uint64]time.Time // key -> reserved nonce -> when it lapses
+}
+
+// New creates a manager. redisClient may be nil.
+func New(redisClient *redis.Client, ttl time.Duration) *Manager {
+	if ttl <= 0 {
+		ttl = 2 * time.Minute
+	}
+	return &Manager{
+		redis: redisClient,
+		ttl:   ttl,
+		local: make(map[string]map[uint64]time.Time),
+	}
+}
+
+func key(chainID int64, address common.Address) string {
+	return fmt.Sprintf("%s%d:%s", keyPrefix, chainID, strings.ToLower(address.Hex()))
+}
+
+// Next reserves the next nonce for address on chainID
+func (m *Manager) Next(ctx context.Context, chainID int64, address common.Address, chain PendingNoncer) (uint64, error) {
+	pending, err := chain.PendingNonceAt(ctx, address)
+	if err != nil {
+		return 0, err
+	}
+
+	k := key(chainID, address)
+	now := time.Now()
+	if m.redis != nil {
+		nonce, err := allocateScript.Run(ctx, m.redis, []string{k}, pending, now.UnixMilli(), m.ttl.Milliseconds()).Int64()
+		if err == nil {
+			return uint64(nonce), nil
+		}
+		// Redis is unavailable; memory still keeps this instance's sends apart
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	held := m.local[k]
+	if held == nil {
+		held = make(map[uint64]time.Time)
+		m.local[k] = held
+	}
+	for n, lapses := range held {
+		if n < pending || !now.Before(lapses) {
+			delete(held, n)
+		}
+	}
+	nonce := pending
+	for {
+		if _, ok := held[nonce]; !ok {
+			break
+		}
+		nonce++
+	}
+	held[nonce] = now.Add(m.ttl)
+	return nonce, nil
+}
+
+// Release ends the reservation of a nonce whose transaction was never
+// broadcast, so the next allocation can take it
+func (m *Manager) Release(ctx context.Context, chainID int64, address common.Address, nonce uint64) {
+	k := key(chainID, address)
+	if m.redis != nil {
+		if err := m.redis.ZRem(ctx, k, fmt.Sprintf("%d", nonce)).Err(); err == nil {
+			return
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.local[k], nonce)
+}
+
+// Reset forgets every reservation so the next allocation starts from the
+// chain's pending nonce, e.g. after the node rejects one as too low or too high
+func (m *Manager) Reset(ctx context.Context, chainID int64, address common.Address) {
+	k := key(chainID, address)
+	if m.redis != nil {
+		m.redis.Del(ctx, k)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.local, k)
+}
+
+// IsNonceError reports whether a node rejected a transaction for its nonce,
+// meaning the tracked nonce has drifted from the chain
+func IsNonceError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "nonce too high") ||
+		strings.Contains(msg, "replacement transaction underpriced")
+}
@@ -0,0 +1,129 @@
+package nonce
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-redis/redis/v8"
+)
+
+// fakeChain reports a fixed pending nonce
+type fakeChain struct {
+	mu      sync.Mutex
+	pending uint64
+}
+
+func (c *fakeChain) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pending, nil
+}
+
+func (c *fakeChain) set(pending uint64) {
+	c.mu.Lock()
+	c.pending = pending
+	c.mu.Unlock()
+}
+
+var testAddress = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+// managers returns a Redis-backed and an in-memory manager with the same TTL
+func managers(t *testing.T, ttl time.Duration) map[string]*Manager {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return map[string]*Manager{
+		"redis":  New(client, ttl),
+		"memory": New(nil, ttl),
+	}
+}
+
+func TestConcurrentAllocationsAreDistinctAndSequential(t *testing.T) {
+	const prepares = 50
+	for name, m := range managers(t, time.Minute) {
+		t.Run(name, func(t *testing.T) {
+			chain := &fakeChain{pending: 7}
+			nonces := make([]uint64, prepares)
+			var wg sync.WaitGroup
+			for i := 0; i < prepares; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					n, err := m.Next(context.Background(), 1, testAddress, chain)
+					if err != nil {
+						t.Error(err)
+					}
+					nonces[i] = n
+				}(i)
+			}
+			wg.Wait()
+
+			sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+			for i, n := range nonces {
+				if n != uint64(7+i) {
+					t.Fatalf("nonces = %v, want 7..%d each once", nonces, 7+prepares-1)
+				}
+			}
+		})
+	}
+}
+
+func TestUnsentReservationIsHandedOutAgain(t *testing.T) {
+	ctx := context.Background()
+	for name, m := range managers(t, 60*time.Millisecond) {
+		t.Run(name, func(t *testing.T) {
+			chain := &fakeChain{pending: 3}
+			if n, _ := m.Next(ctx, 1, testAddress, chain); n != 3 { // prepared, never sent
+				t.Fatalf("got %d, want 3", n)
+			}
+
+			// Later allocations do not keep the unsent reservation alive
+			time.Sleep(30 * time.Millisecond)
+			if n, _ := m.Next(ctx, 1, testAddress, chain); n != 4 {
+				t.Fatalf("got %d, want 4", n)
+			}
+			time.Sleep(40 * time.Millisecond)
+			if n, _ := m.Next(ctx, 1, testAddress, chain); n != 3 {
+				t.Fatalf("after its reservation lapsed got %d, want 3 again", n)
+			}
+			if n, _ := m.Next(ctx, 1, testAddress, chain); n != 5 {
+				t.Fatalf("got %d, want 5 while 4 is still held", n)
+			}
+		})
+	}
+}
+
+func TestReleaseAndChainProgress(t *testing.T) {
+	ctx := context.Background()
+	for name, m := range managers(t, time.Minute) {
+		t.Run(name, func(t *testing.T) {
+			chain := &fakeChain{pending: 10}
+			m.Next(ctx, 1, testAddress, chain) // 10
+			m.Next(ctx, 1, testAddress, chain) // 11
+			m.Next(ctx, 1, testAddress, chain) // 12
+
+			// A released nonce from the middle is the next one handed out
+			m.Release(ctx, 1, testAddress, 11)
+			if n, _ := m.Next(ctx, 1, testAddress, chain); n != 11 {
+				t.Fatalf("after release got %d, want 11", n)
+			}
+
+			// Reservations the chain has passed no longer count
+			chain.set(20)
+			if n, _ := m.Next(ctx, 1, testAddress, chain); n != 20 {
+				t.Fatalf("after the chain moved on got %d, want 20", n)
+			}
+
+			m.Reset(ctx, 1, testAddress)
+			if n, _ := m.Next(ctx, 1, testAddress, chain); n != 20 {
+				t.Fatalf("after reset got %d, want 20", n)
+			}
+		})
+	}
+}
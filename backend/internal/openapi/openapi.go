@@ -0,0 +1,200 @@
+// Package openapi builds an OpenAPI 3 document from a table of operations whose
+// request and response bodies are the Go structs the handlers bind and return, so
+// the schemas cannot drift from the code.
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Operation describes one route. Request and Response are example values of the
+// bound and returned types, e.g. services.CreateWalletRequest{} or
+// []models.Wallet{}; Fields builds an inline object for gin.H responses.
+type Operation struct {
+	Method   string
+	Path     string // Gin syntax, e.g. /wallets/:id
+	Tag      string
+	Summary  string
+	Public   bool // No bearer token required
+	Query    []Param
+	Request  interface{}
+	Response interface{}
+	Status   int // Success status; defaults to 200
+}
+
+// Param is a query parameter
+type Param struct {
+	Name        string
+	Description string
+	Type        string // string, integer or boolean; defaults to string
+}
+
+// Fields is an inline object schema whose property values are example values of
+// their types. A []Fields with one element is an array of that object.
+type Fields map[string]interface{}
+
+// Info is the document's info object
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Document is an OpenAPI 3.0 document
+type Document struct {
+	OpenAPI    string                        `json:"openapi"`
+	Info       Info                          `json:"info"`
+	Servers    []map[string]string           `json:"servers,omitempty"`
+	Tags       []map[string]string           `json:"tags,omitempty"`
+	Paths      map[string]map[string]*pathOp `json:"paths"`
+	Components components                    `json:"components"`
+
+	operations map[string]bool // "METHOD /path" for Documents
+}
+
+type components struct {
+	Schemas         map[string]*Schema           `json:"schemas"`
+	SecuritySchemes map[string]map[string]string `json:"securitySchemes"`
+}
+
+type pathOp struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []parameter           `json:"parameters,omitempty"`
+	RequestBody *body                 `json:"requestBody,omitempty"`
+	Responses   map[string]*response  `json:"responses"`
+	Security    []map[string][]string `json:"security"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type body struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Build assembles the document. Paths are relative to basePath, which becomes the
+// server URL.
+func Build(info Info, basePath string, ops []Operation) *Document {
+	doc := &Document{
+		OpenAPI:    "3.0.3",
+		Info:       info,
+		Servers:    []map[string]string{{"url": basePath}},
+		Paths:      make(map[string]map[string]*pathOp),
+		operations: make(map[string]bool),
+	}
+	reg := newRegistry()
+	errorSchema := reg.schemaFor(Fields{"error": ""})
+
+	tags := make(map[string]bool)
+	for _, op := range ops {
+		path, params := convertPath(op.Path)
+		method := strings.ToLower(op.Method)
+		doc.operations[op.Method+" "+path] = true
+		if op.Tag != "" && !tags[op.Tag] {
+			tags[op.Tag] = true
+			doc.Tags = append(doc.Tags, map[string]string{"name": op.Tag})
+		}
+
+		out := &pathOp{
+			Summary:     op.Summary,
+			OperationID: operationID(op.Method, path),
+			Parameters:  params,
+			Responses:   make(map[string]*response),
+			Security:    []map[string][]string{{"bearerAuth": {}}},
+		}
+		if op.Tag != "" {
+			out.Tags = []string{op.Tag}
+		}
+		if op.Public {
+			out.Security = []map[string][]string{}
+		}
+		for _, q := range op.Query {
+			typ := q.Type
+			if typ == "" {
+				typ = "string"
+			}
+			out.Parameters = append(out.Parameters, parameter{Name: q.Name, In: "query", Description: q.Description, Schema: &Schema{Type: typ}})
+		}
+		if op.Request != nil {
+			out.RequestBody = &body{Required: true, Content: map[string]mediaType{"application/json": {Schema: reg.schemaFor(op.Request)}}}
+		}
+
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := &response{Description: http.StatusText(status)}
+		if op.Response != nil {
+			success.Content = map[string]mediaType{"application/json": {Schema: reg.schemaFor(op.Response)}}
+		}
+		out.Responses[strconv.Itoa(status)] = success
+		out.Responses["default"] = &response{
+			Description: "Error",
+			Content:     map[string]mediaType{"application/json": {Schema: errorSchema}},
+		}
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*pathOp)
+		}
+		doc.Paths[path][method] = out
+	}
+
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i]["name"] < doc.Tags[j]["name"] })
+	doc.Components = components{
+		Schemas: reg.components,
+		SecuritySchemes: map[string]map[string]string{
+			"bearerAuth": {"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+		},
+	}
+	return doc
+}
+
+// Documents reports whether the document has an operation for a route given in
+// Gin syntax
+func (d *Document) Documents(method, path string) bool {
+	converted, _ := convertPath(path)
+	return d.operations[method+" "+converted]
+}
+
+// convertPath turns /wallets/:id into /wallets/{id} and returns its path parameters
+func convertPath(path string) (string, []parameter) {
+	segments := strings.Split(path, "/")
+	var params []parameter
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			name := segment[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// operationID derives a stable ID such as postWalletsIdSign
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '-' || r == '{' || r == '}' }) {
+		id += strings.ToUpper(segment[:1]) + segment[1:]
+	}
+	return id
+}
@@ -0,0 +1,90 @@
+package proxypool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+// GeoInfo is what a geo-IP service reports for a proxy's exit IP
+type GeoInfo struct {
+	IP       string `json:"ip"`
+	Country  string `json:"country"` // ISO 3166-1 alpha-2
+	City     string `json:"city"`
+	Timezone string `json:"timezone"` // IANA name, e.g. Europe/Berlin
+	ASN      string `json:"asn"`      // e.g. AS3320
+	Org      string `json:"org"`
+}
+
+// LookupGeo asks geoURL for the geo data of the exit IP of picked, or of this
+// host when picked is nil. geoURL should answer like ipapi.co/json or
+// ipinfo.io/json.
+func LookupGeo(ctx context.Context, picked *models.Proxy, geoURL string) (*GeoInfo, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	if picked != nil {
+		transport, err := Transport(picked)
+		if err != nil {
+			return nil, err
+		}
+		defer transport.CloseIdleConnections()
+		client.Transport = transport
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", geoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geo-IP service returned status %d", resp.StatusCode)
+	}
+
+	// ipapi.co names the ISO code country_code and has a separate asn; ipinfo.io
+	// puts the ISO code in country and the ASN at the start of org
+	var raw struct {
+		IP          string `json:"ip"`
+		Country     string `json:"country"`
+		CountryCode string `json:"country_code"`
+		City        string `json:"city"`
+		Timezone    string `json:"timezone"`
+		ASN         string `json:"asn"`
+		Org         string `json:"org"`
+		Error       bool   `json:"error"`
+		Reason      string `json:"reason"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid geo-IP response: %w", err)
+	}
+	if raw.Error {
+		return nil, fmt.Errorf("geo-IP service error: %s", raw.Reason)
+	}
+
+	geo := &GeoInfo{
+		IP:       raw.IP,
+		Country:  strings.ToUpper(raw.CountryCode),
+		City:     raw.City,
+		Timezone: raw.Timezone,
+		ASN:      raw.ASN,
+		Org:      raw.Org,
+	}
+	if geo.Country == "" && len(raw.Country) == 2 {
+		geo.Country = strings.ToUpper(raw.Country)
+	}
+	if geo.ASN == "" && strings.HasPrefix(raw.Org, "AS") {
+		if asn, org, ok := strings.Cut(raw.Org, " "); ok {
+			geo.ASN, geo.Org = asn, org
+		}
+	}
+	return geo, nil
+}
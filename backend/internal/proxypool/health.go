@@ -0,0 +1,99 @@
+package proxypool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+// CheckResult is the outcome of one request through a proxy to an IP echo service
+type CheckResult struct {
+	Healthy    bool
+	LatencyMs  int
+	ExternalIP string
+	Error      string
+}
+
+// Check sends a request through the proxy to echoURL, which should answer with
+// the caller's IP as {"ip": "..."} or plain text
+func Check(ctx context.Context, picked *models.Proxy, echoURL string) *CheckResult {
+	result := &CheckResult{}
+
+	transport, err := Transport(picked)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	client := &http.Client{Transport: transport, Timeout: 10 * time.Second}
+	defer transport.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", echoURL, nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+	result.LatencyMs = int(time.Since(start).Milliseconds())
+
+	if resp.StatusCode != http.StatusOK {
+		result.Error = fmt.Sprintf("echo service returned status %d", resp.StatusCode)
+		return result
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	var ipResp struct {
+		IP string `json:"ip"`
+	}
+	if err := json.Unmarshal(body, &ipResp); err == nil {
+		result.ExternalIP = ipResp.IP
+	} else {
+		result.ExternalIP = strings.TrimSpace(string(body))
+	}
+	result.Healthy = true
+	return result
+}
+
+// RecordCheck stores a check result. A failed check takes the proxy out of
+// rotation and a passing one puts it back; after disableAfter failed checks in a
+// row (zero never) the proxy is deactivated. It reports whether the proxy just
+// went down and whether it was deactivated.
+func (p *Pool) RecordCheck(picked *models.Proxy, result *CheckResult, disableAfter int) (wentDown, disabled bool) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"last_check": now,
+		"is_healthy": result.Healthy,
+		"last_error": result.Error,
+	}
+
+	if result.Healthy {
+		updates["latency"] = result.LatencyMs
+		updates["external_ip"] = result.ExternalIP
+		updates["consecutive_failures"] = 0
+	} else {
+		updates["consecutive_failures"] = gorm.Expr("consecutive_failures + 1")
+		wentDown = picked.IsHealthy
+		if disableAfter > 0 && picked.IsActive && picked.ConsecutiveFailures+1 >= disableAfter {
+			updates["is_active"] = false
+			updates["disabled_at"] = now
+			disabled = true
+		}
+	}
+
+	p.db.Model(&models.Proxy{}).Where("id = ?", picked.ID).Updates(updates)
+	return wentDown, disabled
+}
@@ -0,0 +1,261 @@
+// Package proxypool hands out proxies to accounts and browser profiles, rotating
+// through a pool's healthy members and taking failing proxies out of rotation.
+package proxypool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/net/proxy"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/tracing"
+)
+
+// maxConsecutiveFailures takes a proxy out of rotation after this many failed
+// requests in a row
+const maxConsecutiveFailures = 3
+
+var (
+	ErrNoHealthyProxy = errors.New("no healthy proxy available")
+	ErrUnhealthyProxy = errors.New("assigned proxy is out of rotation")
+)
+
+// Pool picks proxies and tracks their health. Round-robin positions are kept in
+// memory, so replicas each cycle through the pool independently.
+type Pool struct {
+	db *gorm.DB
+
+	mu   sync.Mutex
+	next map[uuid.UUID]int // Round-robin position per pool
+}
+
+func New(db *gorm.DB) *Pool {
+	return &Pool{db: db, next: make(map[uuid.UUID]int)}
+}
+
+// AcquireForAccount returns the proxy an account should use: one from its pool if
+// it has one, otherwise its fixed proxy. Accounts with neither get nil, nil.
+func (p *Pool) AcquireForAccount(ctx context.Context, account *models.PlatformAccount) (*models.Proxy, error) {
+	return p.acquire(ctx, account.UserID, account.ProxyPoolID, account.ProxyID, &models.PlatformAccount{ID: account.ID})
+}
+
+// AcquireForProfile is AcquireForAccount for a browser profile
+func (p *Pool) AcquireForProfile(ctx context.Context, profile *models.BrowserProfile) (*models.Proxy, error) {
+	return p.acquire(ctx, profile.UserID, profile.ProxyPoolID, profile.ProxyID, &models.BrowserProfile{ID: profile.ID})
+}
+
+// acquire picks a proxy and, when it came from a pool, records it on owner's proxy_id
+// so a sticky policy can hand it out again
+func (p *Pool) acquire(ctx context.Context, userID uuid.UUID, poolID, proxyID *uuid.UUID, owner interface{}) (*models.Proxy, error) {
+	if poolID == nil {
+		if proxyID == nil {
+			return nil, nil
+		}
+		var fixed models.Proxy
+		if err := p.db.WithContext(ctx).Where("id = ? AND user_id = ?", *proxyID, userID).First(&fixed).Error; err != nil {
+			return nil, err
+		}
+		if !fixed.IsActive || !fixed.IsHealthy {
+			return nil, ErrUnhealthyProxy
+		}
+		p.touch(ctx, &fixed)
+		return &fixed, nil
+	}
+
+	var pool models.ProxyPool
+	if err := p.db.WithContext(ctx).Where("id = ? AND user_id = ?", *poolID, userID).First(&pool).Error; err != nil {
+		return nil, err
+	}
+
+	var picked *models.Proxy
+	var err error
+	switch pool.Policy {
+	case models.ProxyPolicySticky:
+		picked, err = p.pickSticky(ctx, &pool, proxyID)
+	case models.ProxyPolicyLRU:
+		picked, err = p.pickLeastRecentlyUsed(ctx, &pool)
+	default:
+		picked, err = p.pickRoundRobin(ctx, &pool)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if proxyID == nil || *proxyID != picked.ID {
+		p.db.WithContext(ctx).Model(owner).Update("proxy_id", picked.ID)
+	}
+	p.touch(ctx, picked)
+	return picked, nil
+}
+
+// healthy scopes a query to a pool's members that are in rotation
+func (p *Pool) healthy(ctx context.Context, pool *models.ProxyPool) *gorm.DB {
+	return p.db.WithContext(ctx).Model(&models.Proxy{}).
+		Where("pool_id = ? AND user_id = ? AND is_active = ? AND is_healthy = ?", pool.ID, pool.UserID, true, true)
+}
+
+func (p *Pool) pickRoundRobin(ctx context.Context, pool *models.ProxyPool) (*models.Proxy, error) {
+	var proxies []models.Proxy
+	if err := p.healthy(ctx, pool).Order("created_at, id").Find(&proxies).Error; err != nil {
+		return nil, err
+	}
+	if len(proxies) == 0 {
+		return nil, ErrNoHealthyProxy
+	}
+
+	p.mu.Lock()
+	i := p.next[pool.ID] % len(proxies)
+	p.next[pool.ID] = i + 1
+	p.mu.Unlock()
+
+	return &proxies[i], nil
+}
+
+func (p *Pool) pickLeastRecentlyUsed(ctx context.Context, pool *models.ProxyPool) (*models.Proxy, error) {
+	var picked models.Proxy
+	// Locked so concurrent acquires do not all take the same oldest proxy
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Proxy{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("pool_id = ? AND user_id = ? AND is_active = ? AND is_healthy = ?", pool.ID, pool.UserID, true, true).
+			Order("last_used_at ASC NULLS FIRST").
+			First(&picked).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		picked.LastUsedAt = &now
+		return tx.Model(&picked).Update("last_used_at", now).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNoHealthyProxy
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &picked, nil
+}
+
+// stickyLoad orders proxies by how many accounts and profiles are assigned to them
+const stickyLoad = "(SELECT COUNT(*) FROM platform_accounts a WHERE a.proxy_id = proxies.id AND a.deleted_at IS NULL) + " +
+	"(SELECT COUNT(*) FROM browser_profiles b WHERE b.proxy_id = proxies.id AND b.deleted_at IS NULL) ASC"
+
+// pickSticky keeps the current proxy while it is healthy and in the pool, otherwise
+// moves to the member with the fewest sticky accounts and profiles on it
+func (p *Pool) pickSticky(ctx context.Context, pool *models.ProxyPool, current *uuid.UUID) (*models.Proxy, error) {
+	if current != nil {
+		var kept models.Proxy
+		if err := p.healthy(ctx, pool).Where("id = ?", *current).First(&kept).Error; err == nil {
+			return &kept, nil
+		}
+	}
+
+	var picked models.Proxy
+	err := p.healthy(ctx, pool).
+		Order(stickyLoad).
+		Order("created_at").
+		First(&picked).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNoHealthyProxy
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &picked, nil
+}
+
+// touch records that a proxy was handed out
+func (p *Pool) touch(ctx context.Context, picked *models.Proxy) {
+	now := time.Now()
+	picked.LastUsedAt = &now
+	p.db.WithContext(ctx).Model(&models.Proxy{}).Where("id = ?", picked.ID).Update("last_used_at", now)
+}
+
+// Record counts a request made through a proxy. A nil err is a success; after
+// maxConsecutiveFailures failures in a row the proxy leaves rotation.
+func (p *Pool) Record(proxyID uuid.UUID, err error) {
+	query := p.db.Model(&models.Proxy{}).Where("id = ?", proxyID)
+	if err == nil {
+		query.Updates(map[string]interface{}{
+			"success_count":        gorm.Expr("success_count + 1"),
+			"consecutive_failures": 0,
+		})
+		return
+	}
+
+	query.Updates(map[string]interface{}{
+		"failure_count":        gorm.Expr("failure_count + 1"),
+		"consecutive_failures": gorm.Expr("consecutive_failures + 1"),
+		"last_error":           err.Error(),
+		"is_healthy":           gorm.Expr("is_healthy AND consecutive_failures + 1 < ?", maxConsecutiveFailures),
+	})
+}
+
+// Client returns an HTTP client that sends through picked and reports every
+// request's outcome to Record. A nil proxy gives a direct client. Requests are
+// traced either way.
+func (p *Pool) Client(picked *models.Proxy, timeout time.Duration) (*http.Client, error) {
+	if picked == nil {
+		return &http.Client{Timeout: timeout, Transport: tracing.Transport(nil)}, nil
+	}
+	transport, err := Transport(picked)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{
+		Transport: tracing.Transport(&recordingTransport{base: transport, pool: p, proxyID: picked.ID}),
+		Timeout:   timeout,
+	}, nil
+}
+
+// Transport builds a transport that routes through the proxy
+func Transport(picked *models.Proxy) (*http.Transport, error) {
+	switch picked.Type {
+	case "socks5":
+		var auth *proxy.Auth
+		if picked.Username != "" {
+			auth = &proxy.Auth{User: picked.Username, Password: picked.Password}
+		}
+		dialer, err := proxy.SOCKS5("tcp", fmt.Sprintf("%s:%d", picked.Host, picked.Port), auth, proxy.Direct)
+		if err != nil {
+			return nil, err
+		}
+		return &http.Transport{Dial: dialer.Dial}, nil
+
+	case "http", "residential":
+		proxyURL := &url.URL{Scheme: "http", Host: fmt.Sprintf("%s:%d", picked.Host, picked.Port)}
+		if picked.Username != "" {
+			proxyURL.User = url.UserPassword(picked.Username, picked.Password)
+		}
+		return &http.Transport{Proxy: http.ProxyURL(proxyURL)}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported proxy type: %s", picked.Type)
+	}
+}
+
+// recordingTransport reports transport errors as proxy failures. Any response,
+// even an error status, shows the proxy itself works.
+type recordingTransport struct {
+	base    http.RoundTripper
+	pool    *Pool
+	proxyID uuid.UUID
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil && req.Context().Err() != nil {
+		// The caller gave up; that says nothing about the proxy
+		return resp, err
+	}
+	t.pool.Record(t.proxyID, err)
+	return resp, err
+}
@@ -9,6 +9,9 @@ import (
 
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/requestid"
+	"github.com/web3airdropos/backend/internal/tracing"
 )
 
 // JobStatus represents the status of a queued job
@@ -53,6 +56,9 @@ type Job struct {
 	
 	// Deduplication
 	DedupeKey   string          `json:"dedupe_key,omitempty"`
+	
+	// Context carried to the handler, e.g. the request ID that enqueued the job
+	Metadata    map[string]string `json:"metadata,omitempty"`
 }
 
 // Queue represents a Redis-backed job queue
@@ -95,6 +101,18 @@ func (q *Queue) Enqueue(ctx context.Context, jobType string, payload interface{}
 	for _, opt := range opts {
 		opt(job)
 	}
+	// Tie the job to the request and trace that queued it
+	for key, value := range map[string]string{requestid.Key: requestid.FromContext(ctx), tracing.Key: tracing.Inject(ctx)} {
+		if value == "" {
+			continue
+		}
+		if job.Metadata == nil {
+			job.Metadata = map[string]string{}
+		}
+		if job.Metadata[key] == "" {
+			job.Metadata[key] = value
+		}
+	}
 
 	// Marshal payload
 	payloadBytes, err := json.Marshal(payload)
@@ -418,6 +436,16 @@ func WithDeduplication(key string) JobOption {
 	}
 }
 
+// WithMetadata attaches a metadata value to the job
+func WithMetadata(key, value string) JobOption {
+	return func(j *Job) {
+		if j.Metadata == nil {
+			j.Metadata = map[string]string{}
+		}
+		j.Metadata[key] = value
+	}
+}
+
 // Common errors
 var (
 	ErrJobNotFound  = errors.New("job not found")
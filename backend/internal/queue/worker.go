@@ -6,6 +6,13 @@ import (
 	"log"
 	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/web3airdropos/backend/internal/requestid"
+	"github.com/web3airdropos/backend/internal/tracing"
 )
 
 // Handler is a function that processes a job
@@ -145,19 +152,31 @@ func (w *Worker) processJob(ctx context.Context, job *Job) {
 		return
 	}
 
-	log.Printf("🔄 Processing job %s (type: %s, attempt: %d/%d)",
-		job.ID, job.Type, job.RetryCount+1, job.MaxRetries)
+	requestID := job.Metadata[requestid.Key]
+	log.Printf("🔄 Processing job %s (type: %s, attempt: %d/%d, request_id: %s)",
+		job.ID, job.Type, job.RetryCount+1, job.MaxRetries, requestID)
 
 	// Create timeout context
-	jobCtx, cancel := context.WithTimeout(ctx, w.lockDuration-30*time.Second)
+	jobCtx, cancel := context.WithTimeout(requestid.WithContext(ctx, requestID), w.lockDuration-30*time.Second)
 	defer cancel()
 
+	// Continue the trace of the request that queued the job
+	jobCtx, span := tracing.Start(tracing.Extract(jobCtx, job.Metadata[tracing.Key]), "queue."+job.Type,
+		trace.WithAttributes(
+			attribute.String("job.id", job.ID),
+			attribute.String("job.type", job.Type),
+			attribute.Int("job.attempt", job.RetryCount+1),
+		))
+	defer span.End()
+
 	// Execute handler
 	startTime := time.Now()
 	err := handler(jobCtx, job)
 	duration := time.Since(startTime)
 
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		log.Printf("❌ Job %s failed in %v: %v", job.ID, duration, err)
 		if failErr := w.queue.Fail(ctx, job.ID, err); failErr != nil {
 			log.Printf("⚠️ Failed to mark job as failed: %v", failErr)
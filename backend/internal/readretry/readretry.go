@@ -0,0 +1,158 @@
+// Package readretry retries idempotent HTTP reads against RPCs and platform
+// APIs, and fails over between endpoints.
+package readretry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Retry policy for idempotent reads against RPCs and platform APIs
+const (
+	Attempts    = 3
+	BaseBackoff = 500 * time.Millisecond
+	MaxBackoff  = 5 * time.Second
+)
+
+// ErrRateLimited marks a read rejected with 429
+var ErrRateLimited = errors.New("rate limited")
+
+// StatusError is a non-2xx response to an idempotent read
+type StatusError struct {
+	URL        string
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	if e.StatusCode == http.StatusTooManyRequests {
+		if e.RetryAfter > 0 {
+			return fmt.Sprintf("rate limited by %s (retry after %s)", e.URL, e.RetryAfter)
+		}
+		return fmt.Sprintf("rate limited by %s", e.URL)
+	}
+	return fmt.Sprintf("%s returned status %d", e.URL, e.StatusCode)
+}
+
+func (e *StatusError) Unwrap() error {
+	if e.StatusCode == http.StatusTooManyRequests {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// transientStatus reports whether a status is worth retrying
+func transientStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// Do sends an idempotent request built by newReq, retrying network errors and
+// 5xx responses with exponential backoff. A 429 is returned at once so callers can
+// move to another endpoint. Other responses are returned for the caller to handle.
+func Do(ctx context.Context, client *http.Client, newReq func() (*http.Request, error)) (*http.Response, error) {
+	var lastErr error
+	backoff := BaseBackoff
+
+	for attempt := 0; attempt < Attempts; attempt++ {
+		if attempt > 0 {
+			if err := Sleep(ctx, backoff); err != nil {
+				return nil, err
+			}
+			if backoff *= 2; backoff > MaxBackoff {
+				backoff = MaxBackoff
+			}
+		}
+
+		req, err := newReq()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+		if !transientStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		statusErr := &StatusError{URL: req.URL.Host, StatusCode: resp.StatusCode}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			statusErr.RetryAfter = ParseRetryAfter(resp.Header.Get("Retry-After"))
+			return nil, statusErr
+		}
+		lastErr = statusErr
+	}
+	return nil, lastErr
+}
+
+// FromEndpoints runs read against each URL in order until one succeeds.
+// Every URL gets Do's retries; a rate limit moves straight to the next one.
+func FromEndpoints(ctx context.Context, urls []string, read func(url string) (string, error)) (string, error) {
+	if len(urls) == 0 {
+		return "", fmt.Errorf("no RPC endpoints configured")
+	}
+
+	var lastErr error
+	for i, url := range urls {
+		result, err := read(url)
+		if err == nil {
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		lastErr = err
+		if i < len(urls)-1 {
+			log.Printf("RPC %d/%d failed, trying fallback: %v", i+1, len(urls), err)
+		}
+	}
+	return "", lastErr
+}
+
+// RetryAfter returns how long a rate-limited read asked us to wait, if it was one
+func RetryAfter(err error) (time.Duration, bool) {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests {
+		return statusErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+// ParseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func ParseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// Sleep waits for d, returning early if ctx is cancelled
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,56 @@
+// Package requestid carries the ID of the HTTP request that started a piece of
+// work, so logs, audit entries and async jobs can be tied back to it.
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Header is read from incoming requests and set on responses
+	Header = "X-Request-ID"
+
+	// Key is the Gin context key and the metadata key on queued work
+	Key = "request_id"
+
+	maxLength = 128
+)
+
+type contextKey struct{}
+
+// New generates a request ID
+func New() string {
+	return uuid.New().String()
+}
+
+// Valid reports whether a client-supplied ID is safe to adopt and echo into logs
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// WithContext returns ctx carrying the request ID
+func WithContext(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx, or ""
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
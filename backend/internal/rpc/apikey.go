@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+)
+
+// SealAPIKey encrypts an endpoint API key for storage with AES-256-GCM, keyed the
+// same way as wallet private keys
+func SealAPIKey(encryptionKey, apiKey string) (string, error) {
+	gcm, err := newGCM(encryptionKey)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(gcm.Seal(nonce, nonce, []byte(apiKey), nil)), nil
+}
+
+// OpenAPIKey decrypts a key sealed by SealAPIKey
+func OpenAPIKey(encryptionKey, sealed string) (string, error) {
+	gcm, err := newGCM(encryptionKey)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := hex.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return "", errors.New("sealed API key is too short")
+	}
+
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func newGCM(encryptionKey string) (cipher.AEAD, error) {
+	key := []byte(encryptionKey)
+	if len(key) < 32 {
+		key = append(key, make([]byte, 32-len(key))...)
+	}
+
+	block, err := aes.NewCipher(key[:32])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
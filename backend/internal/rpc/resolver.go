@@ -0,0 +1,263 @@
+// Package rpc resolves the RPC endpoints to use for a chain, combining endpoints
+// stored in the database with configured and public defaults.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/config"
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+// SolanaChainID stands in for Solana mainnet, which has no EVM chain ID
+const SolanaChainID int64 = 101
+
+// apiKeyPlaceholder in an endpoint URL is replaced with its decrypted API key
+const apiKeyPlaceholder = "{api_key}"
+
+// probeTimeout bounds the health check made before an EVM endpoint is first used
+const probeTimeout = 5 * time.Second
+
+// failureCooldown is how long an endpoint that failed is skipped while others remain
+const failureCooldown = 30 * time.Second
+
+var ErrNoEndpoints = errors.New("no RPC endpoints for chain")
+
+// publicEndpoints are the last resort for well-known chains. They are rate-limited.
+var publicEndpoints = map[int64][]string{
+	1:             {"https://eth.llamarpc.com"},
+	10:            {"https://mainnet.optimism.io"},
+	137:           {"https://polygon-rpc.com"},
+	8453:          {"https://mainnet.base.org"},
+	42161:         {"https://arb1.arbitrum.io/rpc"},
+	SolanaChainID: {"https://api.mainnet-beta.solana.com"},
+}
+
+// Resolver orders the RPC endpoints for a chain and keeps one EVM client open per endpoint
+type Resolver struct {
+	db     *gorm.DB
+	config *config.Config
+
+	mu      sync.Mutex
+	clients map[string]*ethclient.Client // By endpoint URL
+	failed  map[string]time.Time         // When an endpoint last failed
+	urls    map[*ethclient.Client]string
+}
+
+func NewResolver(db *gorm.DB, cfg *config.Config) *Resolver {
+	return &Resolver{
+		db:      db,
+		config:  cfg,
+		clients: make(map[string]*ethclient.Client),
+		failed:  make(map[string]time.Time),
+		urls:    make(map[*ethclient.Client]string),
+	}
+}
+
+// Endpoints returns the RPC URLs for a chain in the order they should be tried: the
+// user's own endpoints, shared endpoints, the configured URLs, then public defaults.
+// Pass uuid.Nil to skip user endpoints.
+func (r *Resolver) Endpoints(ctx context.Context, userID uuid.UUID, chainID int64) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	add := func(url string) {
+		if url != "" && !seen[url] {
+			seen[url] = true
+			urls = append(urls, url)
+		}
+	}
+
+	for _, endpoint := range r.stored(ctx, userID, chainID) {
+		url, err := r.expand(&endpoint)
+		if err != nil {
+			log.Printf("⚠️ Skipping RPC endpoint %s: %v", endpoint.ID, err)
+			continue
+		}
+		add(url)
+	}
+
+	switch chainID {
+	case 1:
+		add(r.config.EthereumRPCURL)
+	case SolanaChainID:
+		add(r.config.SolanaRPCURL)
+	}
+	for _, url := range r.config.RPCFallbackURLs[configKey(chainID)] {
+		add(url)
+	}
+	for _, url := range publicEndpoints[chainID] {
+		add(url)
+	}
+	return urls
+}
+
+// EVMClient returns a connected client for the first endpoint of the chain that
+// answers with the right chain ID. Clients are shared and stay open: callers must
+// not close them, and should pass one to Discard when a call on it fails so the
+// next caller reconnects or fails over.
+func (r *Resolver) EVMClient(ctx context.Context, userID uuid.UUID, chainID int64) (*ethclient.Client, error) {
+	urls := r.Endpoints(ctx, userID, chainID)
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("%w %d", ErrNoEndpoints, chainID)
+	}
+
+	// Use the first connected endpoint unless a preferred one is worth dialing. Endpoints
+	// that failed recently go last rather than being retried first every time.
+	r.mu.Lock()
+	healthy := make([]string, 0, len(urls))
+	var cooling []string
+	for _, url := range urls {
+		if client, ok := r.clients[url]; ok && len(healthy) == 0 {
+			r.mu.Unlock()
+			return client, nil
+		}
+		if time.Since(r.failed[url]) < failureCooldown {
+			cooling = append(cooling, url)
+		} else {
+			healthy = append(healthy, url)
+		}
+	}
+	r.mu.Unlock()
+
+	var lastErr error
+	for _, url := range append(healthy, cooling...) {
+		r.mu.Lock()
+		client, ok := r.clients[url]
+		r.mu.Unlock()
+		if ok {
+			return client, nil
+		}
+
+		client, err := dialEVM(ctx, url, chainID)
+		if err != nil {
+			r.mu.Lock()
+			r.failed[url] = time.Now()
+			r.mu.Unlock()
+			lastErr = err
+			continue
+		}
+
+		r.mu.Lock()
+		if existing, ok := r.clients[url]; ok {
+			// Another caller connected first
+			r.mu.Unlock()
+			client.Close()
+			return existing, nil
+		}
+		r.clients[url] = client
+		r.urls[client] = url
+		delete(r.failed, url)
+		r.mu.Unlock()
+		return client, nil
+	}
+	return nil, fmt.Errorf("all RPC endpoints for chain %d failed: %w", chainID, lastErr)
+}
+
+// Discard drops a client after a failed call. It is closed and its endpoint sits
+// out the failure cooldown. Discarding a client twice is harmless.
+func (r *Resolver) Discard(client *ethclient.Client) {
+	if client == nil {
+		return
+	}
+
+	r.mu.Lock()
+	url, ok := r.urls[client]
+	if ok {
+		delete(r.urls, client)
+		delete(r.clients, url)
+		r.failed[url] = time.Now()
+	}
+	r.mu.Unlock()
+
+	if ok {
+		client.Close()
+	}
+}
+
+// Close closes every pooled client
+func (r *Resolver) Close() {
+	r.mu.Lock()
+	clients := r.clients
+	r.clients = make(map[string]*ethclient.Client)
+	r.urls = make(map[*ethclient.Client]string)
+	r.mu.Unlock()
+
+	for _, client := range clients {
+		client.Close()
+	}
+}
+
+// dialEVM connects to an endpoint and checks it serves the expected chain
+func dialEVM(ctx context.Context, url string, chainID int64) (*ethclient.Client, error) {
+	client, err := ethclient.DialContext(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	id, err := client.ChainID(probeCtx)
+	cancel()
+	if err == nil && id.Int64() != chainID {
+		err = fmt.Errorf("endpoint serves chain %s", id)
+	}
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// stored loads the active database endpoints for a chain, the user's before shared ones
+func (r *Resolver) stored(ctx context.Context, userID uuid.UUID, chainID int64) []models.RPCEndpoint {
+	if r.db == nil {
+		return nil
+	}
+
+	var endpoints []models.RPCEndpoint
+	query := r.db.WithContext(ctx).Where("chain_id = ? AND is_active = ?", chainID, true)
+	if userID != uuid.Nil {
+		query = query.Where("user_id = ? OR user_id IS NULL", userID)
+	} else {
+		query = query.Where("user_id IS NULL")
+	}
+	if err := query.Order("user_id IS NULL, priority, created_at").Find(&endpoints).Error; err != nil {
+		log.Printf("⚠️ Failed to load RPC endpoints for chain %d: %v", chainID, err)
+		return nil
+	}
+	return endpoints
+}
+
+// expand fills an endpoint's API key into its URL. Keys go where {api_key}
+// appears, or are appended as the last path segment as Alchemy and Infura expect.
+func (r *Resolver) expand(endpoint *models.RPCEndpoint) (string, error) {
+	if endpoint.APIKey == "" {
+		return endpoint.URL, nil
+	}
+	key, err := OpenAPIKey(r.config.EncryptionKey, endpoint.APIKey)
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(endpoint.URL, apiKeyPlaceholder) {
+		return strings.ReplaceAll(endpoint.URL, apiKeyPlaceholder, key), nil
+	}
+	return strings.TrimRight(endpoint.URL, "/") + "/" + key, nil
+}
+
+// configKey is the RPC_FALLBACK_URLS_<chain> suffix for a chain
+func configKey(chainID int64) string {
+	if chainID == SolanaChainID {
+		return "solana"
+	}
+	return strconv.FormatInt(chainID, 10)
+}
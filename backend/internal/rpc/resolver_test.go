@@ -0,0 +1,120 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/config"
+)
+
+// benchChainID is a local dev chain with no public endpoints to fall back to
+const benchChainID int64 = 31337
+
+// fakeNode is a JSON-RPC server answering eth_chainId and eth_getBalance. It
+// counts the requests it serves and the connections opened to it.
+type fakeNode struct {
+	*httptest.Server
+	calls atomic.Int64
+	conns atomic.Int64
+}
+
+func newFakeNode(tb testing.TB) *fakeNode {
+	node := &fakeNode{}
+	node.Server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		node.calls.Add(1)
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result := "0xde0b6b3a7640000" // 1 ETH
+		if req.Method == "eth_chainId" {
+			result = fmt.Sprintf("0x%x", benchChainID)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	node.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			node.conns.Add(1)
+		}
+	}
+	node.Start()
+	tb.Cleanup(node.Close)
+	return node
+}
+
+// BenchmarkBalanceSync100Wallets reads 100 wallet balances per op, once through
+// the resolver's pooled client and once dialing (and probing) per wallet as
+// the balance sync did before clients were pooled
+func BenchmarkBalanceSync100Wallets(b *testing.B) {
+	const wallets = 100
+	addresses := make([]common.Address, wallets)
+	for i := range addresses {
+		addresses[i] = common.BytesToAddress([]byte{byte(i + 1)})
+	}
+	ctx := context.Background()
+
+	run := func(b *testing.B, sync func(node *fakeNode) error) {
+		node := newFakeNode(b)
+		b.ResetTimer()
+		node.calls.Store(0)
+		node.conns.Store(0)
+		for i := 0; i < b.N; i++ {
+			if err := sync(node); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.StopTimer()
+		b.ReportMetric(float64(node.calls.Load())/float64(b.N), "rpc-calls/op")
+		b.ReportMetric(float64(node.conns.Load())/float64(b.N), "conns/op")
+	}
+
+	b.Run("pooled", func(b *testing.B) {
+		run(b, func(node *fakeNode) error {
+			r := NewResolver(nil, &config.Config{
+				RPCFallbackURLs: map[string][]string{configKey(benchChainID): {node.URL}},
+			})
+			defer r.Close()
+			for _, address := range addresses {
+				client, err := r.EVMClient(ctx, uuid.Nil, benchChainID)
+				if err != nil {
+					return err
+				}
+				if _, err := client.BalanceAt(ctx, address, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+
+	b.Run("dial-per-wallet", func(b *testing.B) {
+		run(b, func(node *fakeNode) error {
+			for _, address := range addresses {
+				client, err := dialEVM(ctx, node.URL, benchChainID)
+				if err != nil {
+					return err
+				}
+				_, err = client.BalanceAt(ctx, address, nil)
+				client.Close()
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,16 +11,23 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"github.com/web3airdropos/backend/internal/accounthealth"
 	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/vault"
 	"github.com/web3airdropos/backend/internal/websocket"
 )
 
 type AccountService struct {
 	container *Container
+	vault     *vault.Vault
+	health    *accounthealth.Checker
 }
 
 func NewAccountService(c *Container) *AccountService {
-	return &AccountService{container: c}
+	return &AccountService{
+		container: c,
+		health:    accounthealth.NewChecker(c.DB, c.WSHub, c.Config),
+	}
 }
 
 type CreateAccountRequest struct {
@@ -31,6 +39,7 @@ type CreateAccountRequest struct {
 	WalletID         *uuid.UUID          `json:"wallet_id"`
 	BrowserProfileID *uuid.UUID          `json:"browser_profile_id"`
 	ProxyID          *uuid.UUID          `json:"proxy_id"`
+	ProxyPoolID      *uuid.UUID          `json:"proxy_pool_id"`
 	AccessToken      string              `json:"access_token"`
 	RefreshToken     string              `json:"refresh_token"`
 }
@@ -40,12 +49,14 @@ type UpdateAccountRequest struct {
 	DisplayName string     `json:"display_name"`
 	WalletID    *uuid.UUID `json:"wallet_id"`
 	ProxyID     *uuid.UUID `json:"proxy_id"`
+	ProxyPoolID *uuid.UUID `json:"proxy_pool_id"` // uuid.Nil goes back to the fixed proxy
 	IsActive    *bool      `json:"is_active"`
+	RateLimit   *string    `json:"rate_limit"` // Empty restores the platform limit
 }
 
 func (s *AccountService) List(userID uuid.UUID, platform string) ([]models.PlatformAccount, error) {
 	var accounts []models.PlatformAccount
-	query := s.container.DB.Where("user_id = ?", userID)
+	query := s.container.Reader.Where("user_id = ?", userID)
 
 	if platform != "" {
 		query = query.Where("platform = ?", platform)
@@ -81,11 +92,15 @@ func (s *AccountService) Create(userID uuid.UUID, req *CreateAccountRequest) (*m
 		WalletID:         req.WalletID,
 		BrowserProfileID: req.BrowserProfileID,
 		ProxyID:          req.ProxyID,
+		ProxyPoolID:      req.ProxyPoolID,
 		AccessToken:      req.AccessToken,
 		RefreshToken:     req.RefreshToken,
 		IsActive:         true,
 		LastLoginAt:      time.Now(),
 	}
+	if err := s.container.Proxy.checkPool(userID, req.ProxyPoolID); err != nil {
+		return nil, err
+	}
 
 	if err := s.container.DB.Create(account).Error; err != nil {
 		return nil, err
@@ -93,6 +108,7 @@ func (s *AccountService) Create(userID uuid.UUID, req *CreateAccountRequest) (*m
 
 	// Broadcast event
 	s.container.WSHub.BroadcastToUser(userID.String(), "account:created", account)
+	s.container.Dashboard.InvalidateStats(userID)
 
 	return account, nil
 }
@@ -116,9 +132,27 @@ func (s *AccountService) Update(userID, accountID uuid.UUID, req *UpdateAccountR
 	if req.ProxyID != nil {
 		updates["proxy_id"] = req.ProxyID
 	}
+	if req.ProxyPoolID != nil {
+		if *req.ProxyPoolID == uuid.Nil {
+			updates["proxy_pool_id"] = nil
+		} else {
+			if err := s.container.Proxy.checkPool(userID, req.ProxyPoolID); err != nil {
+				return nil, err
+			}
+			updates["proxy_pool_id"] = *req.ProxyPoolID
+		}
+	}
 	if req.IsActive != nil {
 		updates["is_active"] = *req.IsActive
 	}
+	if req.RateLimit != nil {
+		if *req.RateLimit != "" {
+			if _, err := ParseRateLimit(*req.RateLimit, DefaultRateLimits["default"]); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidRateLimit, err)
+			}
+		}
+		updates["rate_limit"] = *req.RateLimit
+	}
 
 	if err := s.container.DB.Model(&account).Updates(updates).Error; err != nil {
 		return nil, err
@@ -128,12 +162,31 @@ func (s *AccountService) Update(userID, accountID uuid.UUID, req *UpdateAccountR
 	return &account, nil
 }
 
+// Delete soft-deletes an account; it can be restored until the purge after the
+// retention period
 func (s *AccountService) Delete(userID, accountID uuid.UUID) error {
-	result := s.container.DB.Where("id = ? AND user_id = ?", accountID, userID).Delete(&models.PlatformAccount{})
-	if result.RowsAffected == 0 {
+	var account models.PlatformAccount
+	if err := s.container.DB.Where("id = ? AND user_id = ?", accountID, userID).First(&account).Error; err != nil {
 		return errors.New("account not found")
 	}
+	if err := s.container.DB.Delete(&account).Error; err != nil {
+		return err
+	}
+
+	s.container.Audit.Log(context.Background(), &LogEntry{
+		UserID:     userID,
+		Action:     models.ActionAccountDelete,
+		Result:     models.ResultSuccess,
+		AccountID:  &account.ID,
+		Platform:   string(account.Platform),
+		TargetType: "account",
+		TargetID:   account.Username,
+		RequestData: map[string]interface{}{
+			"purge_after": time.Now().Add(s.container.Config.SoftDeleteRetention),
+		},
+	})
 	s.container.WSHub.BroadcastToUser(userID.String(), "account:deleted", map[string]string{"id": accountID.String()})
+	s.container.Dashboard.InvalidateStats(userID)
 	return nil
 }
 
@@ -375,6 +428,25 @@ func (s *AccountService) syncDiscord(account *models.PlatformAccount) error {
 	return nil
 }
 
+// HealthCheck probes the account's platform and records whether it is active,
+// suspended, rate limited or erroring. Suspended accounts are deactivated.
+func (s *AccountService) HealthCheck(userID, accountID uuid.UUID) (*accounthealth.Result, error) {
+	var account models.PlatformAccount
+	if err := s.container.DB.Select("id").Where("id = ? AND user_id = ?", accountID, userID).First(&account).Error; err != nil {
+		return nil, err
+	}
+
+	result, err := s.health.Check(context.Background(), accountID)
+	if err != nil {
+		return nil, err
+	}
+	if result.Deactivated {
+		s.container.Dashboard.InvalidateStats(userID)
+	}
+	s.container.WSHub.BroadcastToUser(userID.String(), "account:health", result)
+	return result, nil
+}
+
 // LogActivity creates an activity record for an account
 func (s *AccountService) LogActivity(accountID uuid.UUID, activityType string, content string, metadata map[string]interface{}, campaignID *uuid.UUID, automatedBy string) error {
 	metadataJSON, _ := json.Marshal(metadata)
@@ -393,3 +465,15 @@ func (s *AccountService) LogActivity(accountID uuid.UUID, activityType string, c
 
 	return s.container.DB.Create(activity).Error
 }
+
+// rateLimitOverride returns an account's rate limit override, looked up by the
+// rate limiter before each check
+func (s *AccountService) rateLimitOverride(ctx context.Context, accountID string) string {
+	var account models.PlatformAccount
+	if err := s.container.DB.WithContext(ctx).Select("rate_limit").
+		Where("id = ?", accountID).
+		Take(&account).Error; err != nil {
+		return ""
+	}
+	return account.RateLimit
+}
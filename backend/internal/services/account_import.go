@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/services/platforms"
+)
+
+// maxBulkAccounts bounds one import so it fits comfortably in a single transaction
+const maxBulkAccounts = 500
+
+var (
+	ErrTooManyAccounts = fmt.Errorf("at most %d accounts can be imported at once", maxBulkAccounts)
+	ErrInvalidCSV      = errors.New("invalid CSV")
+)
+
+// Bulk import row outcomes
+const (
+	BulkImportCreated = "created"
+	BulkImportFailed  = "failed"
+)
+
+// BulkAccountRow is one account to import. Proxy and BrowserProfile accept an ID or
+// a name; PlatformUserID is looked up from the username when left empty.
+type BulkAccountRow struct {
+	Platform       models.PlatformType `json:"platform"`
+	Username       string              `json:"username"`
+	DisplayName    string              `json:"display_name"`
+	PlatformUserID string              `json:"platform_user_id"`
+	AccessToken    string              `json:"access_token"`
+	RefreshToken   string              `json:"refresh_token"`
+	Proxy          string              `json:"proxy"`
+	BrowserProfile string              `json:"browser_profile"`
+}
+
+type BulkImportAccountsRequest struct {
+	Accounts []BulkAccountRow `json:"accounts" binding:"required"`
+}
+
+// BulkImportResult is what happened to one row. Row counts from 1.
+type BulkImportResult struct {
+	Row       int        `json:"row"`
+	Platform  string     `json:"platform"`
+	Username  string     `json:"username"`
+	Status    string     `json:"status"`
+	AccountID *uuid.UUID `json:"account_id,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	Warning   string     `json:"warning,omitempty"`
+}
+
+// csvColumns maps accepted CSV headers to row fields
+var csvColumns = map[string]func(*BulkAccountRow, string){
+	"platform":         func(r *BulkAccountRow, v string) { r.Platform = models.PlatformType(strings.ToLower(v)) },
+	"username":         func(r *BulkAccountRow, v string) { r.Username = v },
+	"display_name":     func(r *BulkAccountRow, v string) { r.DisplayName = v },
+	"platform_user_id": func(r *BulkAccountRow, v string) { r.PlatformUserID = v },
+	"fid":              func(r *BulkAccountRow, v string) { r.PlatformUserID = v },
+	"token":            func(r *BulkAccountRow, v string) { r.AccessToken = v },
+	"access_token":     func(r *BulkAccountRow, v string) { r.AccessToken = v },
+	"refresh_token":    func(r *BulkAccountRow, v string) { r.RefreshToken = v },
+	"proxy":            func(r *BulkAccountRow, v string) { r.Proxy = v },
+	"browser_profile":  func(r *BulkAccountRow, v string) { r.BrowserProfile = v },
+}
+
+// ParseAccountCSV reads accounts from CSV with a header row. Headers are matched
+// case-insensitively and unknown columns are ignored; platform and username are
+// required.
+func ParseAccountCSV(r io.Reader) ([]BulkAccountRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+	}
+	setters := make([]func(*BulkAccountRow, string), len(header))
+	seen := make(map[string]bool)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		setters[i] = csvColumns[name]
+		seen[name] = true
+	}
+	if !seen["platform"] || !seen["username"] {
+		return nil, fmt.Errorf("%w: header must include platform and username", ErrInvalidCSV)
+	}
+
+	var rows []BulkAccountRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+
+		var row BulkAccountRow
+		for i, value := range record {
+			if i < len(setters) && setters[i] != nil {
+				setters[i](&row, strings.TrimSpace(value))
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// BulkImport creates accounts from rows in one transaction. Each row succeeds or
+// fails on its own: the result for every row says which, with the reason. Tokens
+// are stored as Create stores them, and platform user IDs left empty are looked up
+// through the platform's adapter when one is registered.
+func (s *AccountService) BulkImport(userID uuid.UUID, rows []BulkAccountRow) ([]BulkImportResult, error) {
+	if len(rows) > maxBulkAccounts {
+		return nil, ErrTooManyAccounts
+	}
+
+	ctx := context.Background()
+	results := make([]BulkImportResult, len(rows))
+	accounts := make([]*models.PlatformAccount, len(rows))
+	batch := make(map[string]int) // platform:username -> row, to catch duplicates in the batch
+
+	for i := range rows {
+		row := &rows[i]
+		results[i] = BulkImportResult{Row: i + 1, Platform: string(row.Platform), Username: row.Username, Status: BulkImportFailed}
+
+		account, warning, err := s.prepareImport(ctx, userID, row)
+		if err == nil {
+			key := string(account.Platform) + ":" + strings.ToLower(account.Username)
+			if first, ok := batch[key]; ok {
+				err = fmt.Errorf("duplicate of row %d", first)
+			} else {
+				batch[key] = i + 1
+			}
+		}
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].Warning = warning
+		accounts[i] = account
+	}
+
+	created := 0
+	err := s.container.DB.Transaction(func(tx *gorm.DB) error {
+		for i, account := range accounts {
+			if account == nil {
+				continue
+			}
+			// Nested transactions are savepoints, so a failed row does not abort the rest
+			if err := tx.Transaction(func(row *gorm.DB) error {
+				return row.Create(account).Error
+			}); err != nil {
+				results[i].Error = err.Error()
+				continue
+			}
+			results[i].Status = BulkImportCreated
+			results[i].AccountID = &account.ID
+			created++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if created > 0 {
+		s.container.WSHub.BroadcastToUser(userID.String(), "accounts:imported", map[string]interface{}{
+			"created": created,
+			"failed":  len(rows) - created,
+		})
+		s.container.Dashboard.InvalidateStats(userID)
+	}
+
+	return results, nil
+}
+
+// prepareImport validates a row and builds its account, resolving references. A
+// platform user ID that could not be looked up is a warning, not a failure.
+func (s *AccountService) prepareImport(ctx context.Context, userID uuid.UUID, row *BulkAccountRow) (*models.PlatformAccount, string, error) {
+	row.Username = strings.TrimPrefix(strings.TrimSpace(row.Username), "@")
+	switch row.Platform {
+	case models.PlatformFarcaster, models.PlatformTwitter, models.PlatformTelegram, models.PlatformDiscord, models.PlatformLens:
+	default:
+		return nil, "", fmt.Errorf("unsupported platform: %q", row.Platform)
+	}
+	if row.Username == "" {
+		return nil, "", errors.New("username is required")
+	}
+
+	var existing int64
+	s.container.DB.Model(&models.PlatformAccount{}).
+		Where("user_id = ? AND platform = ? AND LOWER(username) = LOWER(?)", userID, row.Platform, row.Username).
+		Count(&existing)
+	if existing > 0 {
+		return nil, "", errors.New("account already exists")
+	}
+
+	account := &models.PlatformAccount{
+		ID:             uuid.New(),
+		UserID:         userID,
+		Platform:       row.Platform,
+		Username:       row.Username,
+		DisplayName:    row.DisplayName,
+		PlatformUserID: row.PlatformUserID,
+		AccessToken:    row.AccessToken,
+		RefreshToken:   row.RefreshToken,
+		IsActive:       true,
+		LastLoginAt:    time.Now(),
+	}
+
+	if row.Proxy != "" {
+		var proxy models.Proxy
+		if err := s.findByReference(userID, row.Proxy).First(&proxy).Error; err != nil {
+			return nil, "", fmt.Errorf("proxy %q not found", row.Proxy)
+		}
+		account.ProxyID = &proxy.ID
+	}
+	if row.BrowserProfile != "" {
+		var profile models.BrowserProfile
+		if err := s.findByReference(userID, row.BrowserProfile).First(&profile).Error; err != nil {
+			return nil, "", fmt.Errorf("browser profile %q not found", row.BrowserProfile)
+		}
+		account.BrowserProfileID = &profile.ID
+	}
+
+	var warning string
+	if account.PlatformUserID == "" {
+		adapter, err := s.container.Task.GetAdapter(string(row.Platform))
+		if err != nil {
+			return account, "platform user ID not resolved: no adapter configured", nil
+		}
+		lookupCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		profile, err := adapter.GetUserByUsername(lookupCtx, row.Username)
+		cancel()
+		switch {
+		case errors.Is(err, platforms.ErrUserNotFound):
+			return nil, "", fmt.Errorf("user %s not found on %s", row.Username, row.Platform)
+		case err != nil:
+			warning = fmt.Sprintf("platform user ID not resolved: %v", err)
+		default:
+			account.PlatformUserID = profile.ID
+			if account.DisplayName == "" {
+				account.DisplayName = profile.DisplayName
+			}
+			account.AvatarURL = profile.AvatarURL
+		}
+	}
+
+	return account, warning, nil
+}
+
+// findByReference scopes a query to the user's record with the given ID or name
+func (s *AccountService) findByReference(userID uuid.UUID, ref string) *gorm.DB {
+	query := s.container.DB.Where("user_id = ?", userID)
+	if id, err := uuid.Parse(ref); err == nil {
+		return query.Where("id = ?", id)
+	}
+	return query.Where("name = ?", ref)
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+const maxWarmupDays = 90
+
+var (
+	ErrInvalidWarmup      = errors.New("invalid warmup config")
+	ErrInvalidWarmupScore = errors.New("min_warmup_score must be between 0 and 100")
+	ErrWarmupNotFarcaster = errors.New("warmup only supports Farcaster accounts")
+	ErrAccountNotWarmedUp = errors.New("account's warmup score is below the campaign's minimum")
+)
+
+// StartWarmupRequest configures and starts an account's warmup. Unset fields
+// take the defaults.
+type StartWarmupRequest struct {
+	Days            int      `json:"days"`
+	MinDailyActions int      `json:"min_daily_actions"`
+	MaxDailyActions int      `json:"max_daily_actions"`
+	Channels        []string `json:"channels"`
+	Restart         bool     `json:"restart"` // Start over from day one instead of resuming
+}
+
+// AccountWarmup is an account's warmup config and progress
+type AccountWarmup struct {
+	AccountID    uuid.UUID           `json:"account_id"`
+	Status       string              `json:"status"`
+	Score        int                 `json:"score"`
+	DaysDone     int                 `json:"days_done"`
+	Config       models.WarmupConfig `json:"config"`
+	StartedAt    *time.Time          `json:"started_at,omitempty"`
+	LastActionAt *time.Time          `json:"last_action_at,omitempty"`
+}
+
+// GetWarmup returns an account's warmup config and progress
+func (s *AccountService) GetWarmup(userID, accountID uuid.UUID) (*AccountWarmup, error) {
+	var account models.PlatformAccount
+	if err := s.container.DB.Where("id = ? AND user_id = ?", accountID, userID).First(&account).Error; err != nil {
+		return nil, err
+	}
+	return warmupOf(&account), nil
+}
+
+// StartWarmup sets an account's warmup config and marks it warming, so warmup
+// jobs act for it. A paused or reconfigured warmup resumes where it left off
+// unless Restart is set.
+func (s *AccountService) StartWarmup(userID, accountID uuid.UUID, req *StartWarmupRequest) (*AccountWarmup, error) {
+	var account models.PlatformAccount
+	if err := s.container.DB.Where("id = ? AND user_id = ?", accountID, userID).First(&account).Error; err != nil {
+		return nil, err
+	}
+	if account.Platform != models.PlatformFarcaster {
+		return nil, ErrWarmupNotFarcaster
+	}
+
+	cfg := models.WarmupConfig{
+		Days:            req.Days,
+		MinDailyActions: req.MinDailyActions,
+		MaxDailyActions: req.MaxDailyActions,
+		Channels:        req.Channels,
+	}
+	if cfg.Days < 0 || cfg.Days > maxWarmupDays || cfg.MinDailyActions < 0 || cfg.MaxDailyActions < 0 {
+		return nil, fmt.Errorf("%w: days must be at most %d and action counts positive", ErrInvalidWarmup, maxWarmupDays)
+	}
+	if cfg.MaxDailyActions > 0 && cfg.MaxDailyActions < cfg.MinDailyActions {
+		return nil, fmt.Errorf("%w: max_daily_actions is below min_daily_actions", ErrInvalidWarmup)
+	}
+	cfg = cfg.WithDefaults()
+	cfgJSON, _ := json.Marshal(cfg)
+
+	daysDone := account.WarmupDaysDone
+	if req.Restart {
+		daysDone = 0
+	}
+	score := min(daysDone*100/cfg.Days, 100)
+	status := models.WarmupWarming
+	if score >= 100 {
+		status = models.WarmupWarm
+	}
+
+	updates := map[string]interface{}{
+		"warmup_status":    status,
+		"warmup_config":    string(cfgJSON),
+		"warmup_days_done": daysDone,
+		"warmup_score":     score,
+	}
+	if req.Restart || account.WarmupStartedAt == nil {
+		updates["warmup_started_at"] = time.Now()
+		updates["warmup_last_action_at"] = nil
+	}
+	if err := s.container.DB.Model(&account).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	if err := s.container.DB.First(&account, "id = ?", account.ID).Error; err != nil {
+		return nil, err
+	}
+
+	warmup := warmupOf(&account)
+	s.container.WSHub.BroadcastToUser(userID.String(), "account:warmup", warmup)
+	return warmup, nil
+}
+
+// StopWarmup pauses an account's warmup, keeping its progress
+func (s *AccountService) StopWarmup(userID, accountID uuid.UUID) (*AccountWarmup, error) {
+	var account models.PlatformAccount
+	if err := s.container.DB.Where("id = ? AND user_id = ?", accountID, userID).First(&account).Error; err != nil {
+		return nil, err
+	}
+
+	if account.WarmupStatus == models.WarmupWarming {
+		if err := s.container.DB.Model(&account).Update("warmup_status", models.WarmupPaused).Error; err != nil {
+			return nil, err
+		}
+		account.WarmupStatus = models.WarmupPaused
+	}
+
+	warmup := warmupOf(&account)
+	s.container.WSHub.BroadcastToUser(userID.String(), "account:warmup", warmup)
+	return warmup, nil
+}
+
+func warmupOf(account *models.PlatformAccount) *AccountWarmup {
+	var cfg models.WarmupConfig
+	if account.WarmupConfig != "" {
+		json.Unmarshal([]byte(account.WarmupConfig), &cfg)
+	}
+	status := account.WarmupStatus
+	if status == "" {
+		status = models.WarmupNone
+	}
+	return &AccountWarmup{
+		AccountID:    account.ID,
+		Status:       status,
+		Score:        account.WarmupScore,
+		DaysDone:     account.WarmupDaysDone,
+		Config:       cfg.WithDefaults(),
+		StartedAt:    account.WarmupStartedAt,
+		LastActionAt: account.WarmupLastActionAt,
+	}
+}
+
+// checkWarmup returns ErrAccountNotWarmedUp when the account's warmup score is
+// under the campaign's minimum
+func (s *CampaignService) checkWarmup(campaignID, accountID uuid.UUID) error {
+	var minScore int
+	s.container.DB.Model(&models.Campaign{}).Select("min_warmup_score").Where("id = ?", campaignID).Scan(&minScore)
+	if minScore <= 0 {
+		return nil
+	}
+
+	var score int
+	s.container.DB.Model(&models.PlatformAccount{}).Select("warmup_score").Where("id = ?", accountID).Scan(&score)
+	if score < minScore {
+		return fmt.Errorf("%w: %d of %d", ErrAccountNotWarmedUp, score, minScore)
+	}
+	return nil
+}
+
+func validMinWarmupScore(score int) error {
+	if score < 0 || score > 100 {
+		return ErrInvalidWarmupScore
+	}
+	return nil
+}
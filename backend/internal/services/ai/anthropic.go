@@ -0,0 +1,108 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	anthropicEndpoint = "https://api.anthropic.com/v1/messages"
+	anthropicVersion  = "2023-06-01"
+)
+
+// AnthropicProvider generates content with the Anthropic Messages API
+type AnthropicProvider struct {
+	httpClient *http.Client
+	apiKey     string
+	model      string
+}
+
+// NewAnthropicProvider creates an Anthropic provider
+func NewAnthropicProvider(httpClient *http.Client, apiKey, model string) (*AnthropicProvider, error) {
+	if apiKey == "" {
+		return nil, errors.New("ANTHROPIC_API_KEY is not set")
+	}
+	return &AnthropicProvider{httpClient: httpClient, apiKey: apiKey, model: model}, nil
+}
+
+// Name returns the provider name
+func (p *AnthropicProvider) Name() string {
+	return ProviderAnthropic
+}
+
+// Generate makes one request per option, since the API returns a single message
+func (p *AnthropicProvider) Generate(ctx context.Context, prompt *Prompt) ([]GeneratedContent, error) {
+	var contents []GeneratedContent
+	for i := 0; i < numOptions(prompt); i++ {
+		content, err := p.generateOne(ctx, prompt)
+		if err != nil {
+			// Keep the options already generated
+			if len(contents) > 0 {
+				return contents, nil
+			}
+			return nil, err
+		}
+		contents = append(contents, content)
+	}
+	return contents, nil
+}
+
+func (p *AnthropicProvider) generateOne(ctx context.Context, prompt *Prompt) (GeneratedContent, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"model":      p.model,
+		"max_tokens": 1024,
+		"system":     "You write social media content for web3 communities.",
+		"messages": []map[string]string{
+			{"role": "user", "content": instructions(prompt)},
+		},
+	})
+	if err != nil {
+		return GeneratedContent{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", anthropicEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return GeneratedContent{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return GeneratedContent{}, fmt.Errorf("anthropic request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return GeneratedContent{}, fmt.Errorf("anthropic error %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result struct {
+		Model   string `json:"model"`
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return GeneratedContent{}, err
+	}
+
+	var text string
+	for _, block := range result.Content {
+		if block.Type == "text" {
+			text += block.Text
+		}
+	}
+	if text == "" {
+		return GeneratedContent{}, ErrEmptyResponse
+	}
+	return newContent(prompt, text, result.Model), nil
+}
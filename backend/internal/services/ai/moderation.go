@@ -0,0 +1,239 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/web3airdropos/backend/internal/config"
+)
+
+// Moderation providers accepted in MODERATION_PROVIDER
+const (
+	ModerationNone    = "none"
+	ModerationPhrases = "phrases" // Local banned-phrase list
+	ModerationOpenAI  = "openai"  // OpenAI moderation endpoint plus the phrase list
+)
+
+// Actions for flagged content
+const (
+	ModerationActionFlag = "flag" // Save the draft with status "flagged"
+	ModerationActionDrop = "drop" // Discard the draft
+)
+
+const openAIModerationEndpoint = "https://api.openai.com/v1/moderations"
+
+// DefaultBannedPhrases catch the scam and shilling patterns that get accounts suspended.
+// Deployments extend them with MODERATION_BANNED_PHRASES.
+var DefaultBannedPhrases = []string{
+	"guaranteed profit",
+	"guaranteed returns",
+	"100x guaranteed",
+	"risk free investment",
+	"send eth to",
+	"send sol to",
+	"double your crypto",
+	"connect your wallet to claim",
+	"share your seed phrase",
+	"dm me your private key",
+}
+
+// ModerationResult is the outcome of a moderation check, stored with the draft
+type ModerationResult struct {
+	Flagged    bool      `json:"flagged"`
+	Reasons    []string  `json:"reasons,omitempty"`
+	Provider   string    `json:"provider"`
+	CheckedAt  time.Time `json:"checked_at"`
+	Unverified bool      `json:"unverified,omitempty"` // The provider failed, so the content was flagged to be safe
+}
+
+// Reason joins the reasons into a single line for review
+func (r *ModerationResult) Reason() string {
+	return strings.Join(r.Reasons, "; ")
+}
+
+// Moderator checks generated content before it is saved
+type Moderator interface {
+	Name() string
+	Check(ctx context.Context, content string) (*ModerationResult, error)
+}
+
+// PhraseModerator flags content containing a banned phrase
+type PhraseModerator struct {
+	patterns map[string]*regexp.Regexp
+}
+
+// NewPhraseModerator matches phrases case-insensitively on word boundaries
+func NewPhraseModerator(phrases []string) *PhraseModerator {
+	m := &PhraseModerator{patterns: make(map[string]*regexp.Regexp, len(phrases))}
+	for _, phrase := range phrases {
+		phrase = strings.ToLower(strings.TrimSpace(phrase))
+		if phrase == "" {
+			continue
+		}
+		words := strings.Fields(phrase)
+		for i, w := range words {
+			words[i] = regexp.QuoteMeta(w)
+		}
+		m.patterns[phrase] = regexp.MustCompile(`(?i)\b` + strings.Join(words, `\s+`) + `\b`)
+	}
+	return m
+}
+
+// Name returns the moderator name
+func (m *PhraseModerator) Name() string {
+	return ModerationPhrases
+}
+
+// Check reports every banned phrase found in content
+func (m *PhraseModerator) Check(ctx context.Context, content string) (*ModerationResult, error) {
+	result := &ModerationResult{Provider: m.Name(), CheckedAt: time.Now()}
+	for phrase, pattern := range m.patterns {
+		if pattern.MatchString(content) {
+			result.Reasons = append(result.Reasons, "banned phrase: "+phrase)
+		}
+	}
+	sort.Strings(result.Reasons)
+	result.Flagged = len(result.Reasons) > 0
+	return result, nil
+}
+
+// OpenAIModerator uses OpenAI's moderation endpoint
+type OpenAIModerator struct {
+	httpClient *http.Client
+	apiKey     string
+}
+
+// NewOpenAIModerator creates an OpenAI moderator
+func NewOpenAIModerator(httpClient *http.Client, apiKey string) (*OpenAIModerator, error) {
+	if apiKey == "" {
+		return nil, errors.New("OPENAI_API_KEY is not set")
+	}
+	return &OpenAIModerator{httpClient: httpClient, apiKey: apiKey}, nil
+}
+
+// Name returns the moderator name
+func (m *OpenAIModerator) Name() string {
+	return ModerationOpenAI
+}
+
+// Check flags content in any category OpenAI marks as violating
+func (m *OpenAIModerator) Check(ctx context.Context, content string) (*ModerationResult, error) {
+	body, err := json.Marshal(map[string]string{"input": content})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", openAIModerationEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+m.apiKey)
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai moderation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openai moderation error %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var parsed struct {
+		Results []struct {
+			Flagged    bool            `json:"flagged"`
+			Categories map[string]bool `json:"categories"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, err
+	}
+
+	result := &ModerationResult{Provider: m.Name(), CheckedAt: time.Now()}
+	for _, r := range parsed.Results {
+		if !r.Flagged {
+			continue
+		}
+		result.Flagged = true
+		for category, hit := range r.Categories {
+			if hit {
+				result.Reasons = append(result.Reasons, "category: "+category)
+			}
+		}
+	}
+	sort.Strings(result.Reasons)
+	return result, nil
+}
+
+// ModeratorChain runs every moderator and merges their findings
+type ModeratorChain struct {
+	moderators []Moderator
+}
+
+// Name returns the names of the chained moderators
+func (c *ModeratorChain) Name() string {
+	names := make([]string, len(c.moderators))
+	for i, m := range c.moderators {
+		names[i] = m.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+// Check flags content if any moderator flags it. A moderator that fails flags
+// the content as unverified rather than letting it through unchecked.
+func (c *ModeratorChain) Check(ctx context.Context, content string) (*ModerationResult, error) {
+	merged := &ModerationResult{Provider: c.Name(), CheckedAt: time.Now()}
+	for _, m := range c.moderators {
+		result, err := m.Check(ctx, content)
+		if err != nil {
+			log.Printf("Moderation via %s failed: %v", m.Name(), err)
+			merged.Flagged = true
+			merged.Unverified = true
+			merged.Reasons = append(merged.Reasons, m.Name()+" moderation unavailable")
+			continue
+		}
+		if result.Flagged {
+			merged.Flagged = true
+			merged.Reasons = append(merged.Reasons, result.Reasons...)
+		}
+	}
+	return merged, nil
+}
+
+// NewModeratorFromConfig builds the moderator selected by cfg.ModerationProvider,
+// or nil when moderation is disabled
+func NewModeratorFromConfig(cfg *config.Config) Moderator {
+	provider := strings.ToLower(cfg.ModerationProvider)
+	if provider == ModerationNone || provider == "" {
+		return nil
+	}
+
+	phrases := append(append([]string{}, DefaultBannedPhrases...), cfg.ModerationBannedPhrases...)
+	chain := &ModeratorChain{moderators: []Moderator{NewPhraseModerator(phrases)}}
+
+	switch provider {
+	case ModerationPhrases:
+	case ModerationOpenAI:
+		m, err := NewOpenAIModerator(&http.Client{Timeout: 15 * time.Second}, cfg.OpenAIKey)
+		if err != nil {
+			log.Printf("⚠️  OpenAI moderation disabled, using phrase list only: %v", err)
+			break
+		}
+		chain.moderators = append(chain.moderators, m)
+	default:
+		log.Printf("⚠️  Unknown moderation provider %q, using phrase list only", cfg.ModerationProvider)
+	}
+	return chain
+}
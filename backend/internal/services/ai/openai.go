@@ -0,0 +1,86 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const openAIEndpoint = "https://api.openai.com/v1/chat/completions"
+
+// OpenAIProvider generates content with the OpenAI chat completions API
+type OpenAIProvider struct {
+	httpClient *http.Client
+	apiKey     string
+	model      string
+}
+
+// NewOpenAIProvider creates an OpenAI provider
+func NewOpenAIProvider(httpClient *http.Client, apiKey, model string) (*OpenAIProvider, error) {
+	if apiKey == "" {
+		return nil, errors.New("OPENAI_API_KEY is not set")
+	}
+	return &OpenAIProvider{httpClient: httpClient, apiKey: apiKey, model: model}, nil
+}
+
+// Name returns the provider name
+func (p *OpenAIProvider) Name() string {
+	return ProviderOpenAI
+}
+
+// Generate requests NumOptions completions in a single call
+func (p *OpenAIProvider) Generate(ctx context.Context, prompt *Prompt) ([]GeneratedContent, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"model": p.model,
+		"n":     numOptions(prompt),
+		"messages": []map[string]string{
+			{"role": "system", "content": "You write social media content for web3 communities."},
+			{"role": "user", "content": instructions(prompt)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", openAIEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openai error %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result struct {
+		Model   string `json:"model"`
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, err
+	}
+
+	contents := make([]GeneratedContent, 0, len(result.Choices))
+	for _, choice := range result.Choices {
+		if choice.Message.Content != "" {
+			contents = append(contents, newContent(prompt, choice.Message.Content, result.Model))
+		}
+	}
+	return contents, nil
+}
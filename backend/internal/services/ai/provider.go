@@ -0,0 +1,233 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/web3airdropos/backend/internal/config"
+)
+
+// Provider names accepted in AI_PROVIDERS
+const (
+	ProviderService   = "service" // Internal AI microservice
+	ProviderOpenAI    = "openai"
+	ProviderAnthropic = "anthropic"
+)
+
+var (
+	ErrNoProviders     = errors.New("no AI providers configured")
+	ErrEmptyResponse   = errors.New("AI provider returned no content")
+	ErrUnknownProvider = errors.New("unknown AI provider")
+)
+
+// Prompt describes the content to generate
+type Prompt struct {
+	Platform   string   `json:"platform"`
+	Type       string   `json:"type"`
+	Prompt     string   `json:"prompt"`
+	Tone       string   `json:"tone"`
+	Context    string   `json:"context"`
+	ReplyTo    string   `json:"reply_to"`
+	MaxLength  int      `json:"max_length"`
+	NumOptions int      `json:"num_options"`
+	Keywords   []string `json:"keywords"`
+	Hashtags   bool     `json:"hashtags"`
+}
+
+// GeneratedContent is one generated content option
+type GeneratedContent struct {
+	Content          string   `json:"content"`
+	Tone             string   `json:"tone"`
+	Platform         string   `json:"platform"`
+	Hashtags         []string `json:"hashtags,omitempty"`
+	Parts            []string `json:"parts,omitempty"` // Ordered posts for threads
+	Model            string   `json:"model,omitempty"`
+	PredictedMetrics struct {
+		EngagementScore float64 `json:"engagement_score"`
+		ViralPotential  float64 `json:"viral_potential"`
+	} `json:"predicted_metrics"`
+}
+
+// Provider generates content from a prompt
+type Provider interface {
+	Name() string
+	Generate(ctx context.Context, prompt *Prompt) ([]GeneratedContent, error)
+}
+
+// Fallback tries each provider in order until one returns content
+type Fallback struct {
+	providers []Provider
+}
+
+// NewFallback creates a provider chain
+func NewFallback(providers ...Provider) *Fallback {
+	return &Fallback{providers: providers}
+}
+
+// Name returns the names of the chained providers
+func (f *Fallback) Name() string {
+	names := make([]string, len(f.providers))
+	for i, p := range f.providers {
+		names[i] = p.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+// Generate returns the first successful provider's content
+func (f *Fallback) Generate(ctx context.Context, prompt *Prompt) ([]GeneratedContent, error) {
+	if len(f.providers) == 0 {
+		return nil, ErrNoProviders
+	}
+
+	var errs []error
+	for _, p := range f.providers {
+		contents, err := p.Generate(ctx, prompt)
+		if err == nil && len(contents) == 0 {
+			err = ErrEmptyResponse
+		}
+		if err == nil {
+			return contents, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Printf("AI provider %s failed, trying next: %v", p.Name(), err)
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+	}
+	return nil, errors.Join(errs...)
+}
+
+// NewFromConfig builds the fallback chain from cfg.AIProviders. Providers that
+// are missing credentials are skipped with a warning.
+func NewFromConfig(cfg *config.Config) *Fallback {
+	httpClient := &http.Client{Timeout: 60 * time.Second}
+
+	var providers []Provider
+	for _, name := range cfg.AIProviders {
+		p, err := newProvider(strings.ToLower(name), cfg, httpClient)
+		if err != nil {
+			log.Printf("⚠️  Skipping AI provider %q: %v", name, err)
+			continue
+		}
+		providers = append(providers, p)
+	}
+	return NewFallback(providers...)
+}
+
+func newProvider(name string, cfg *config.Config, httpClient *http.Client) (Provider, error) {
+	switch name {
+	case ProviderService:
+		return NewServiceProvider(httpClient, cfg.AIServiceURL)
+	case ProviderOpenAI:
+		return NewOpenAIProvider(httpClient, cfg.OpenAIKey, cfg.OpenAIModel)
+	case ProviderAnthropic:
+		return NewAnthropicProvider(httpClient, cfg.AnthropicAPIKey, cfg.AnthropicModel)
+	default:
+		return nil, ErrUnknownProvider
+	}
+}
+
+// instructions turns a prompt into a plain-text request for chat-style models
+func instructions(p *Prompt) string {
+	var b strings.Builder
+	contentType := p.Type
+	if contentType == "" {
+		contentType = "post"
+	}
+	fmt.Fprintf(&b, "Write a %s", contentType)
+	if p.Platform != "" {
+		fmt.Fprintf(&b, " for %s", p.Platform)
+	}
+	b.WriteString(".")
+	if p.Tone != "" {
+		fmt.Fprintf(&b, " Tone: %s.", p.Tone)
+	}
+	if p.MaxLength > 0 {
+		fmt.Fprintf(&b, " Keep it under %d characters.", p.MaxLength)
+	}
+	if len(p.Keywords) > 0 {
+		fmt.Fprintf(&b, " Include these keywords: %s.", strings.Join(p.Keywords, ", "))
+	}
+	if p.Hashtags {
+		b.WriteString(" Add a few relevant hashtags.")
+	} else {
+		b.WriteString(" Do not use hashtags.")
+	}
+	if contentType == "thread" {
+		fmt.Fprintf(&b, " Write 3 to 6 connected posts and put a line containing only %s between them.", threadSeparator)
+	}
+	if p.ReplyTo != "" {
+		fmt.Fprintf(&b, "\n\nYou are replying to:\n%s", p.ReplyTo)
+	}
+	if p.Context != "" {
+		fmt.Fprintf(&b, "\n\nContext:\n%s", p.Context)
+	}
+	if p.Prompt != "" {
+		fmt.Fprintf(&b, "\n\nTopic:\n%s", p.Prompt)
+	}
+	b.WriteString("\n\nReply with the content only, no preamble or quotes.")
+	return b.String()
+}
+
+// numOptions returns how many variants to generate
+func numOptions(p *Prompt) int {
+	if p.NumOptions <= 0 {
+		return 1
+	}
+	return p.NumOptions
+}
+
+func newContent(p *Prompt, text, model string) GeneratedContent {
+	content := GeneratedContent{
+		Content:  strings.TrimSpace(text),
+		Tone:     p.Tone,
+		Platform: p.Platform,
+		Model:    model,
+	}
+	if p.Type == "thread" {
+		content.Parts = SplitThread(content.Content)
+	}
+	return content
+}
+
+// threadSeparator is the line chat models are asked to put between thread posts
+const threadSeparator = "---"
+
+// SplitThread splits thread text into parts on separator lines, falling back
+// to blank lines when the model ignored the separator
+func SplitThread(text string) []string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var parts []string
+	var current []string
+	flush := func() {
+		if part := strings.TrimSpace(strings.Join(current, "\n")); part != "" {
+			parts = append(parts, part)
+		}
+		current = nil
+	}
+
+	hasSeparator := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == threadSeparator {
+			hasSeparator = true
+			break
+		}
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if (hasSeparator && trimmed == threadSeparator) || (!hasSeparator && trimmed == "") {
+			flush()
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+	return parts
+}
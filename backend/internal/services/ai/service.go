@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ServiceProvider calls the internal AI microservice
+type ServiceProvider struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewServiceProvider creates a provider for the AI microservice at baseURL
+func NewServiceProvider(httpClient *http.Client, baseURL string) (*ServiceProvider, error) {
+	if baseURL == "" {
+		return nil, errors.New("AI_SERVICE_URL is not set")
+	}
+	return &ServiceProvider{httpClient: httpClient, baseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+// Name returns the provider name
+func (p *ServiceProvider) Name() string {
+	return ProviderService
+}
+
+// Generate posts the prompt to /generate
+func (p *ServiceProvider) Generate(ctx context.Context, prompt *Prompt) ([]GeneratedContent, error) {
+	body, err := json.Marshal(prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/generate", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to AI service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+
+	// The service returns {"contents": [...]}; older builds return a single {"content": "..."}
+	var result struct {
+		Contents []GeneratedContent `json:"contents"`
+		Content  string             `json:"content"`
+		Error    string             `json:"error,omitempty"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("AI service returned %d: %s", resp.StatusCode, string(respBody))
+	}
+	if result.Error != "" {
+		return nil, errors.New(result.Error)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("AI service returned %d", resp.StatusCode)
+	}
+
+	if len(result.Contents) == 0 && result.Content != "" {
+		result.Contents = []GeneratedContent{newContent(prompt, result.Content, "")}
+	}
+	return result.Contents, nil
+}
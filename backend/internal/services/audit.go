@@ -9,16 +9,23 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/requestid"
 	"github.com/web3airdropos/backend/internal/services/platforms"
 )
 
 // AuditService handles audit logging
 type AuditService struct {
-	db *gorm.DB
+	db     *gorm.DB
+	reader *gorm.DB // Queries and stats; db unless SetReader is called
 }
 
 func NewAuditService(db *gorm.DB) *AuditService {
-	return &AuditService{db: db}
+	return &AuditService{db: db, reader: db}
+}
+
+// SetReader sends audit queries and stats to a read replica
+func (s *AuditService) SetReader(reader *gorm.DB) {
+	s.reader = reader
 }
 
 // LogEntry represents a simplified entry for logging
@@ -52,6 +59,7 @@ type LogEntry struct {
 	UserAgent    string
 	
 	IdempotencyKey string
+	RequestID      string // Defaults to the request ID carried by ctx
 }
 
 // Log creates an audit log entry
@@ -78,8 +86,12 @@ func (s *AuditService) Log(ctx context.Context, entry *LogEntry) (*models.AuditL
 		UserAgent:    entry.UserAgent,
 		Duration:     entry.Duration.Milliseconds(),
 		IdempotencyKey: entry.IdempotencyKey,
+		RequestID:    entry.RequestID,
 		CreatedAt:    time.Now(),
 	}
+	if log.RequestID == "" {
+		log.RequestID = requestid.FromContext(ctx)
+	}
 
 	// Serialize proof
 	if entry.Proof != nil {
@@ -162,6 +174,7 @@ func (s *AuditService) LogTaskExecution(ctx context.Context, exec *models.TaskEx
 
 	if err != nil {
 		entry.ErrorMessage = err.Error()
+		entry.ErrorCode = exec.ErrorCode
 	}
 
 	return s.Log(ctx, entry)
@@ -172,7 +185,7 @@ func (s *AuditService) Query(ctx context.Context, query *models.AuditLogQuery) (
 	var logs []models.AuditLog
 	var total int64
 
-	db := s.db.Model(&models.AuditLog{})
+	db := s.reader.Model(&models.AuditLog{})
 
 	if query.UserID != nil {
 		db = db.Where("user_id = ?", *query.UserID)
@@ -242,7 +255,7 @@ func (s *AuditService) GetByIdempotencyKey(ctx context.Context, key string) (*mo
 // GetRecentByAccount gets recent actions for an account
 func (s *AuditService) GetRecentByAccount(ctx context.Context, accountID uuid.UUID, limit int) ([]models.AuditLog, error) {
 	var logs []models.AuditLog
-	if err := s.db.Where("account_id = ?", accountID).
+	if err := s.reader.Where("account_id = ?", accountID).
 		Order("created_at DESC").
 		Limit(limit).
 		Find(&logs).Error; err != nil {
@@ -257,12 +270,12 @@ func (s *AuditService) GetStats(ctx context.Context, userID uuid.UUID, since tim
 
 	// Total actions
 	var totalActions int64
-	s.db.Model(&models.AuditLog{}).Where("user_id = ? AND created_at >= ?", userID, since).Count(&totalActions)
+	s.reader.Model(&models.AuditLog{}).Where("user_id = ? AND created_at >= ?", userID, since).Count(&totalActions)
 	stats["total_actions"] = totalActions
 
 	// Success rate
 	var successCount int64
-	s.db.Model(&models.AuditLog{}).Where("user_id = ? AND created_at >= ? AND result = ?", userID, since, models.ResultSuccess).Count(&successCount)
+	s.reader.Model(&models.AuditLog{}).Where("user_id = ? AND created_at >= ? AND result = ?", userID, since, models.ResultSuccess).Count(&successCount)
 	if totalActions > 0 {
 		stats["success_rate"] = float64(successCount) / float64(totalActions) * 100
 	} else {
@@ -275,7 +288,7 @@ func (s *AuditService) GetStats(ctx context.Context, userID uuid.UUID, since tim
 		Count    int64
 	}
 	var platformCounts []PlatformCount
-	s.db.Model(&models.AuditLog{}).
+	s.reader.Model(&models.AuditLog{}).
 		Select("platform, count(*) as count").
 		Where("user_id = ? AND created_at >= ?", userID, since).
 		Group("platform").
@@ -293,7 +306,7 @@ func (s *AuditService) GetStats(ctx context.Context, userID uuid.UUID, since tim
 		Count  int64
 	}
 	var actionCounts []ActionCount
-	s.db.Model(&models.AuditLog{}).
+	s.reader.Model(&models.AuditLog{}).
 		Select("action, count(*) as count").
 		Where("user_id = ? AND created_at >= ?", userID, since).
 		Group("action").
@@ -311,6 +324,9 @@ func (s *AuditService) GetStats(ctx context.Context, userID uuid.UUID, since tim
 // Helper functions
 
 func getProofType(proof *platforms.ActionProof) string {
+	if isAlreadyDone(proof) {
+		return "already_done"
+	}
 	if proof.TxHash != "" {
 		return "tx_hash"
 	}
@@ -330,6 +346,9 @@ func getProofType(proof *platforms.ActionProof) string {
 }
 
 func getProofValue(proof *platforms.ActionProof) string {
+	if isAlreadyDone(proof) {
+		return proof.Metadata["target"]
+	}
 	if proof.TxHash != "" {
 		return proof.TxHash
 	}
@@ -360,6 +379,8 @@ func taskTypeToAction(taskType models.TaskType) models.AuditLogAction {
 		return models.ActionPost
 	case models.TaskTypeReply:
 		return models.ActionReply
+	case models.TaskTypeDirectMessage:
+		return models.ActionDirectMessage
 	case models.TaskTypeTransaction:
 		return models.ActionTransaction
 	case models.TaskTypeClaim:
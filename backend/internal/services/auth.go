@@ -43,6 +43,11 @@ type AuthResponse struct {
 	AccessToken  string       `json:"access_token"`
 	RefreshToken string       `json:"refresh_token"`
 	ExpiresAt    time.Time    `json:"expires_at"`
+	ExpiresIn    int64        `json:"expires_in"` // Seconds until the access token expires
+
+	// Set instead of tokens when the user has 2FA enabled; complete via /auth/2fa/login
+	TwoFactorRequired bool   `json:"2fa_required,omitempty"`
+	Challenge         string `json:"challenge,omitempty"`
 }
 
 func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
@@ -65,6 +70,7 @@ func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
 			AccessToken:  result.Tokens.AccessToken,
 			RefreshToken: result.Tokens.RefreshToken,
 			ExpiresAt:    result.Tokens.ExpiresAt,
+			ExpiresIn:    result.Tokens.ExpiresIn,
 		}, nil
 	}
 
@@ -91,6 +97,7 @@ func (s *AuthService) registerBasic(req *RegisterRequest) (*AuthResponse, error)
 		Email:        req.Email,
 		PasswordHash: string(hashedPassword),
 		Name:         req.Name,
+		Role:         models.RoleUser,
 	}
 
 	if err := s.container.DB.Create(user).Error; err != nil {
@@ -115,11 +122,21 @@ func (s *AuthService) Login(req *LoginRequest) (*AuthResponse, error) {
 			return nil, err
 		}
 
+		if result.TwoFactorRequired {
+			return &AuthResponse{
+				User:              result.User,
+				ExpiresAt:         *result.ChallengeExpiresAt,
+				TwoFactorRequired: true,
+				Challenge:         result.Challenge,
+			}, nil
+		}
+
 		return &AuthResponse{
 			User:         result.User,
 			AccessToken:  result.Tokens.AccessToken,
 			RefreshToken: result.Tokens.RefreshToken,
 			ExpiresAt:    result.Tokens.ExpiresAt,
+			ExpiresIn:    result.Tokens.ExpiresIn,
 		}, nil
 	}
 
@@ -144,6 +161,38 @@ func (s *AuthService) loginBasic(req *LoginRequest) (*AuthResponse, error) {
 	return s.generateTokens(&user)
 }
 
+// IdentityConfirmation re-proves who the caller is before a sensitive action
+type IdentityConfirmation struct {
+	Password  string
+	Code      string // TOTP or recovery code; required when 2FA is enabled
+	IPAddress string
+	UserAgent string
+}
+
+// ConfirmIdentity checks the user's password and, when 2FA is enabled, their
+// second factor. A nil confirmation is refused.
+func (s *AuthService) ConfirmIdentity(userID uuid.UUID, confirm *IdentityConfirmation) error {
+	if confirm == nil {
+		return auth.ErrInvalidCredentials
+	}
+	if s.productionAuth != nil {
+		return s.productionAuth.ConfirmIdentity(context.Background(), userID, confirm.Password, confirm.Code, confirm.IPAddress, confirm.UserAgent)
+	}
+
+	var user models.User
+	if err := s.container.DB.First(&user, "id = ?", userID).Error; err != nil {
+		return auth.ErrUserNotFound
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(confirm.Password)); err != nil {
+		return auth.ErrInvalidCredentials
+	}
+	// Second factors are verified by the production auth service only
+	if user.TOTPEnabled {
+		return auth.ErrTwoFactorUnavailable
+	}
+	return nil
+}
+
 func (s *AuthService) RefreshToken(refreshToken string) (*AuthResponse, error) {
 	ctx := context.Background()
 
@@ -173,6 +222,7 @@ func (s *AuthService) RefreshToken(refreshToken string) (*AuthResponse, error) {
 			AccessToken:  tokens.AccessToken,
 			RefreshToken: tokens.RefreshToken,
 			ExpiresAt:    tokens.ExpiresAt,
+			ExpiresIn:    tokens.ExpiresIn,
 		}, nil
 	}
 
@@ -205,7 +255,8 @@ func (s *AuthService) refreshTokenBasic(refreshToken string) (*AuthResponse, err
 }
 
 func (s *AuthService) generateTokens(user *models.User) (*AuthResponse, error) {
-	expiresAt := time.Now().Add(24 * time.Hour)
+	accessTTL, refreshTTL := s.tokenTTLs()
+	expiresAt := time.Now().Add(accessTTL)
 
 	// Access token
 	accessClaims := jwt.MapClaims{
@@ -219,10 +270,10 @@ func (s *AuthService) generateTokens(user *models.User) (*AuthResponse, error) {
 		return nil, err
 	}
 
-	// Refresh token (7 days)
+	// Refresh token
 	refreshClaims := jwt.RegisteredClaims{
 		Subject:   user.ID.String(),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(refreshTTL)),
 	}
 	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
 	refreshTokenString, err := refreshToken.SignedString([]byte(s.container.Config.JWTSecret))
@@ -238,5 +289,19 @@ func (s *AuthService) generateTokens(user *models.User) (*AuthResponse, error) {
 		AccessToken:  accessTokenString,
 		RefreshToken: refreshTokenString,
 		ExpiresAt:    expiresAt,
+		ExpiresIn:    int64(time.Until(expiresAt).Seconds()),
 	}, nil
 }
+
+// tokenTTLs returns the configured access and refresh token lifetimes, falling
+// back to the production auth defaults when unset
+func (s *AuthService) tokenTTLs() (access, refresh time.Duration) {
+	access, refresh = s.container.Config.AccessTokenTTL, s.container.Config.RefreshTokenTTL
+	if access <= 0 {
+		access = auth.DefaultAccessTokenTTL
+	}
+	if refresh <= 0 {
+		refresh = auth.DefaultRefreshTokenTTL
+	}
+	return access, refresh
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/config"
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+func TestGenerateTokensUsesConfiguredTTLs(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "test-secret", AccessTokenTTL: 10 * time.Minute, RefreshTokenTTL: 48 * time.Hour}
+	s := NewAuthService(&Container{Config: cfg})
+
+	resp, err := s.generateTokens(&models.User{ID: uuid.New(), Email: "user@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiry := func(token string) time.Time {
+		t.Helper()
+		claims := jwt.RegisteredClaims{}
+		if _, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+			return []byte(cfg.JWTSecret), nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return claims.ExpiresAt.Time
+	}
+	within := func(got time.Time, ttl time.Duration) bool {
+		d := time.Until(got)
+		return d > ttl-time.Minute && d <= ttl
+	}
+
+	if got := expiry(resp.AccessToken); !within(got, cfg.AccessTokenTTL) {
+		t.Errorf("access token expires at %s, want about %s from now", got, cfg.AccessTokenTTL)
+	}
+	if !within(resp.ExpiresAt, cfg.AccessTokenTTL) {
+		t.Errorf("ExpiresAt = %s, want about %s from now", resp.ExpiresAt, cfg.AccessTokenTTL)
+	}
+	if got := expiry(resp.RefreshToken); !within(got, cfg.RefreshTokenTTL) {
+		t.Errorf("refresh token expires at %s, want about %s from now", got, cfg.RefreshTokenTTL)
+	}
+}
@@ -1,17 +1,16 @@
 package services
 
 import (
-	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -25,6 +24,9 @@ type BrowserService struct {
 	container       *Container
 	sessions        map[uuid.UUID]*BrowserSession
 	dockerAvailable bool
+
+	stop     chan struct{} // Stops the session reaper
+	stopOnce sync.Once
 }
 
 type BrowserSession struct {
@@ -64,11 +66,16 @@ func NewBrowserService(c *Container) *BrowserService {
 		fmt.Printf("⚠️ Docker not available: %v\n", err)
 	}
 
-	return &BrowserService{
+	s := &BrowserService{
 		container:       c,
 		sessions:        make(map[uuid.UUID]*BrowserSession),
 		dockerAvailable: dockerAvailable,
+		stop:            make(chan struct{}),
+	}
+	if c.Config.BrowserReaperInterval > 0 {
+		go s.reap()
 	}
+	return s
 }
 
 type CreateProfileRequest struct {
@@ -80,6 +87,7 @@ type CreateProfileRequest struct {
 	Timezone     string     `json:"timezone"`
 	Platform     string     `json:"platform"`
 	ProxyID      *uuid.UUID `json:"proxy_id"`
+	ProxyPoolID  *uuid.UUID `json:"proxy_pool_id"`
 }
 
 func (s *BrowserService) ListProfiles(userID uuid.UUID) ([]models.BrowserProfile, error) {
@@ -102,6 +110,7 @@ func (s *BrowserService) CreateProfile(userID uuid.UUID, req *CreateProfileReque
 		Timezone:     req.Timezone,
 		Platform:     req.Platform,
 		ProxyID:      req.ProxyID,
+		ProxyPoolID:  req.ProxyPoolID,
 		IsActive:     true,
 	}
 
@@ -121,6 +130,9 @@ func (s *BrowserService) CreateProfile(userID uuid.UUID, req *CreateProfileReque
 	if profile.Platform == "" {
 		profile.Platform = "Windows"
 	}
+	if err := s.container.Proxy.checkPool(userID, req.ProxyPoolID); err != nil {
+		return nil, err
+	}
 
 	if err := s.container.DB.Create(profile).Error; err != nil {
 		return nil, err
@@ -141,6 +153,7 @@ type StartSessionRequest struct {
 	ProfileID       uuid.UUID  `json:"profile_id" binding:"required"`
 	TaskExecutionID *uuid.UUID `json:"task_execution_id"`
 	StartURL        string     `json:"start_url"`
+	Record          bool       `json:"record"` // Capture a screenshot timeline of the session
 }
 
 func (s *BrowserService) StartSession(userID uuid.UUID, req *StartSessionRequest) (*models.BrowserSession, error) {
@@ -150,16 +163,17 @@ func (s *BrowserService) StartSession(userID uuid.UUID, req *StartSessionRequest
 		return nil, errors.New("profile not found")
 	}
 
-	// Get proxy if configured
+	// Get proxy if configured; a profile whose proxy is down does not start without one
 	var proxyConfig string
-	if profile.ProxyID != nil {
-		var proxy models.Proxy
-		if err := s.container.DB.First(&proxy, profile.ProxyID).Error; err == nil {
-			if proxy.Username != "" {
-				proxyConfig = fmt.Sprintf("%s:%s@%s:%d", proxy.Username, proxy.Password, proxy.Host, proxy.Port)
-			} else {
-				proxyConfig = fmt.Sprintf("%s:%d", proxy.Host, proxy.Port)
-			}
+	proxy, err := s.container.Proxy.AcquireForProfile(&profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire proxy: %w", err)
+	}
+	if proxy != nil {
+		if proxy.Username != "" {
+			proxyConfig = fmt.Sprintf("%s:%s@%s:%d", proxy.Username, proxy.Password, proxy.Host, proxy.Port)
+		} else {
+			proxyConfig = fmt.Sprintf("%s:%d", proxy.Host, proxy.Port)
 		}
 	}
 
@@ -174,6 +188,10 @@ func (s *BrowserService) StartSession(userID uuid.UUID, req *StartSessionRequest
 		StartedAt:       time.Now(),
 		LastActivityAt:  time.Now(),
 	}
+	if req.Record {
+		session.Recording = true
+		session.RecordingPath = filepath.Join(s.container.Config.BrowserRecordingPath, sessionID.String())
+	}
 
 	if err := s.container.DB.Create(session).Error; err != nil {
 		return nil, err
@@ -220,9 +238,10 @@ func (s *BrowserService) startBrowserContainer(userID uuid.UUID, session *models
 
 	containerName := fmt.Sprintf("browser-%s", session.ID.String()[:8])
 
-	// Build docker run command
+	// Build docker run command. Ports are only published on loopback, and raw VNC
+	// not at all; viewers reach noVNC through the backend's authenticated proxy.
 	args := []string{"run", "-d", "--rm", "--name", containerName,
-		"-p", "0:5900", "-p", "0:9222", "-p", "0:8080",
+		"-p", "127.0.0.1:0:9222", "-p", "127.0.0.1:0:8080",
 		"--memory", "1g", "--cpus", "1"}
 	args = append(args, envArgs...)
 	args = append(args, "browser-automation:latest")
@@ -258,14 +277,9 @@ func (s *BrowserService) startBrowserContainer(userID uuid.UUID, session *models
 
 	// Parse ports (simple parsing)
 	portLines := strings.Split(string(portOutput), "\n")
-	var vncPort, debugPort, wsPort string
+	var debugPort, wsPort string
 	for _, line := range portLines {
-		if strings.Contains(line, "5900") {
-			parts := strings.Split(line, ":")
-			if len(parts) > 1 {
-				vncPort = strings.TrimSpace(parts[len(parts)-1])
-			}
-		} else if strings.Contains(line, "9222") {
+		if strings.Contains(line, "9222") {
 			parts := strings.Split(line, ":")
 			if len(parts) > 1 {
 				debugPort = strings.TrimSpace(parts[len(parts)-1])
@@ -279,23 +293,32 @@ func (s *BrowserService) startBrowserContainer(userID uuid.UUID, session *models
 	}
 
 	// Update session with container info
+	vncURL := vncProxyPath(session.ID)
 	s.container.DB.Model(session).Updates(map[string]interface{}{
 		"container_id":  containerID,
-		"vnc_url":       fmt.Sprintf("vnc://localhost:%s", vncPort),
-		"debugger_url":  fmt.Sprintf("http://localhost:%s", debugPort),
-		"websocket_url": fmt.Sprintf("ws://localhost:%s", wsPort),
+		"vnc_url":       vncURL,
+		"debugger_url":  fmt.Sprintf("http://127.0.0.1:%s", debugPort),
+		"websocket_url": fmt.Sprintf("ws://127.0.0.1:%s", wsPort),
 		"status":        "ready",
 	})
 
 	// Store in memory
-	s.sessions[session.ID] = &BrowserSession{
+	memSession := &BrowserSession{
 		ID:           session.ID,
 		UserID:       userID,
 		ProfileID:    session.ProfileID,
 		ContainerID:  containerID,
-		VNCURL:       fmt.Sprintf("vnc://localhost:%s", vncPort),
-		DebuggerURL:  fmt.Sprintf("http://localhost:%s", debugPort),
-		WebSocketURL: fmt.Sprintf("ws://localhost:%s", wsPort),
+		VNCURL:       vncURL,
+		DebuggerURL:  fmt.Sprintf("http://127.0.0.1:%s", debugPort),
+		WebSocketURL: fmt.Sprintf("ws://127.0.0.1:%s", wsPort),
+	}
+	s.sessions[session.ID] = memSession
+
+	// Recording runs until the session is stopped or killed
+	if session.Recording {
+		ctx, cancel := context.WithCancel(context.Background())
+		memSession.cancel = cancel
+		go s.recordSession(ctx, session.ID, memSession.DebuggerURL, session.RecordingPath)
 	}
 
 	s.container.WSHub.BroadcastTerminal(userID.String(), ws.TerminalMessage{
@@ -304,15 +327,13 @@ func (s *BrowserService) startBrowserContainer(userID uuid.UUID, session *models
 		Message: "Browser session ready",
 		Details: map[string]interface{}{
 			"session_id": session.ID.String(),
-			"vnc_url":    fmt.Sprintf("vnc://localhost:%s", vncPort),
+			"vnc_url":    vncURL,
 		},
 	})
 
 	s.container.WSHub.BroadcastToUser(userID.String(), "browser:ready", map[string]interface{}{
-		"session_id":    session.ID.String(),
-		"vnc_url":       fmt.Sprintf("vnc://localhost:%s", vncPort),
-		"debugger_url":  fmt.Sprintf("http://localhost:%s", debugPort),
-		"websocket_url": fmt.Sprintf("ws://localhost:%s", wsPort),
+		"session_id": session.ID.String(),
+		"vnc_url":    vncURL,
 	})
 }
 
@@ -346,9 +367,15 @@ func (s *BrowserService) StopSession(userID, sessionID uuid.UUID) error {
 	}
 
 	// Update status
-	s.container.DB.Model(session).Update("status", "stopped")
+	s.container.DB.Model(session).Updates(map[string]interface{}{
+		"status":     "stopped",
+		"stopped_at": time.Now(),
+	})
 
-	// Remove from memory
+	// Remove from memory, ending any recording
+	if memSession, ok := s.sessions[sessionID]; ok && memSession.cancel != nil {
+		memSession.cancel()
+	}
 	delete(s.sessions, sessionID)
 
 	s.container.WSHub.BroadcastTerminal(userID.String(), ws.TerminalMessage{
@@ -579,8 +606,7 @@ func (s *BrowserService) GetScreenshot(userID, sessionID uuid.UUID) ([]byte, err
 		return nil, errors.New("no screenshot data")
 	}
 
-	// Decode base64
-	return io.ReadAll(bytes.NewReader([]byte(dataStr)))
+	return base64.StdEncoding.DecodeString(dataStr)
 }
 
 // ========================= Session Lifecycle Management =========================
@@ -868,42 +894,25 @@ func (s *BrowserService) StartSessionWithTask(userID uuid.UUID, profileID uuid.U
 	return session, nil
 }
 
-// TakeScreenshotProof takes a screenshot and saves it as proof for a task
+// TakeScreenshotProof takes a screenshot and saves it as proof for a task,
+// returning its storage key
 func (s *BrowserService) TakeScreenshotProof(userID, sessionID uuid.UUID, taskExecutionID uuid.UUID) (string, error) {
 	screenshotData, err := s.GetScreenshot(userID, sessionID)
 	if err != nil {
 		return "", err
 	}
 
-	// Generate filename
-	timestamp := time.Now().Format("20060102_150405")
-	filename := fmt.Sprintf("proof_%s_%s.png", taskExecutionID.String()[:8], timestamp)
-
-	// Save screenshot to configured storage path
-	storagePath := s.container.Config.ProofStoragePath
-	if storagePath == "" {
-		storagePath = "./storage/proofs"
-	}
-
-	// Ensure directory exists
-	if err := os.MkdirAll(storagePath, 0755); err != nil {
-		return "", fmt.Errorf("failed to create storage directory: %w", err)
-	}
-
-	// Save file
-	filePath := filepath.Join(storagePath, filename)
-	if err := os.WriteFile(filePath, screenshotData, 0644); err != nil {
+	// Keep proofs of one execution together
+	key := fmt.Sprintf("proofs/%s/screenshot_%s.png", taskExecutionID, time.Now().Format("20060102_150405"))
+	if err := s.container.Storage.Put(context.Background(), key, screenshotData, "image/png"); err != nil {
 		return "", fmt.Errorf("failed to save screenshot: %w", err)
 	}
 
-	// Use relative path for database storage
-	screenshotPath := "/proofs/" + filename
-
 	// Update task execution with proof
 	s.container.DB.Model(&models.TaskExecution{}).Where("id = ?", taskExecutionID).Updates(map[string]interface{}{
-		"screenshot_path": screenshotPath,
+		"screenshot_path": key,
 		"proof_type":      "screenshot",
-		"proof_value":     screenshotPath,
+		"proof_value":     key,
 	})
 
 	s.container.WSHub.BroadcastTerminal(userID.String(), ws.TerminalMessage{
@@ -911,12 +920,12 @@ func (s *BrowserService) TakeScreenshotProof(userID, sessionID uuid.UUID, taskEx
 		Source:  "browser",
 		Message: "Screenshot captured for proof",
 		Details: map[string]interface{}{
-			"path": screenshotPath,
+			"path": key,
 			"size": len(screenshotData),
 		},
 	})
 
-	return screenshotPath, nil
+	return key, nil
 }
 
 // ListActiveSessions returns all active sessions for a user
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+var (
+	ErrNoRecording   = errors.New("session has no recording")
+	ErrFrameNotFound = errors.New("recording frame not found")
+)
+
+const recordingFrameExt = ".jpg"
+
+// RecordingFrame is one screenshot of a session recording
+type RecordingFrame struct {
+	Index      int       `json:"index"`
+	CapturedAt time.Time `json:"captured_at"`
+	URL        string    `json:"url"`
+}
+
+// SessionRecording is the screenshot timeline of a browser session
+type SessionRecording struct {
+	SessionID       uuid.UUID        `json:"session_id"`
+	TaskExecutionID *uuid.UUID       `json:"task_execution_id,omitempty"`
+	Status          string           `json:"status"` // Session status; frames keep coming while it is live
+	Frames          []RecordingFrame `json:"frames"`
+}
+
+// recordingURL is where a session's timeline is served, used as its proof value
+func recordingURL(sessionID uuid.UUID) string {
+	return "/api/v1/browser/sessions/" + sessionID.String() + "/recording"
+}
+
+// recordSession captures a frame every BrowserRecordingInterval until ctx is
+// cancelled or the frame cap is hit, then attaches the recording to the
+// session's task execution
+func (s *BrowserService) recordSession(ctx context.Context, sessionID uuid.UUID, debuggerURL, dir string) {
+	defer s.finishRecording(sessionID)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Printf("⚠️ Recording disabled for session %s: %v", sessionID, err)
+		return
+	}
+
+	interval := s.container.Config.BrowserRecordingInterval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	frames := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if max := s.container.Config.BrowserRecordingMaxFrames; max > 0 && frames >= max {
+			return
+		}
+
+		data, err := s.captureFrame(debuggerURL)
+		if err != nil {
+			// Pages mid-navigation fail to capture; the next tick tries again
+			continue
+		}
+		name := fmt.Sprintf("%06d_%d%s", frames, time.Now().UnixMilli(), recordingFrameExt)
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			log.Printf("⚠️ Failed to save recording frame for session %s: %v", sessionID, err)
+			continue
+		}
+		frames++
+		s.container.DB.Model(&models.BrowserSession{}).Where("id = ?", sessionID).Update("recording_frames", frames)
+	}
+}
+
+// captureFrame takes a compressed screenshot for a recording
+func (s *BrowserService) captureFrame(debuggerURL string) ([]byte, error) {
+	result, err := s.cdpSend(debuggerURL, "Page.captureScreenshot", map[string]interface{}{
+		"format":  "jpeg",
+		"quality": 60,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resultMap, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid screenshot result")
+	}
+	dataStr, ok := resultMap["data"].(string)
+	if !ok {
+		return nil, errors.New("no screenshot data")
+	}
+	return base64.StdEncoding.DecodeString(dataStr)
+}
+
+// finishRecording links a recording with frames to the session's task execution.
+// The recording becomes the execution's proof unless it already has one.
+func (s *BrowserService) finishRecording(sessionID uuid.UUID) {
+	var session models.BrowserSession
+	if err := s.container.DB.First(&session, "id = ?", sessionID).Error; err != nil {
+		return
+	}
+	if session.TaskExecutionID == nil || session.RecordingFrames == 0 {
+		return
+	}
+
+	s.container.DB.Model(&models.TaskExecution{}).Where("id = ?", *session.TaskExecutionID).
+		Update("browser_session_id", sessionID)
+	s.container.DB.Model(&models.TaskExecution{}).
+		Where("id = ? AND (proof_type = '' OR proof_type IS NULL)", *session.TaskExecutionID).
+		Updates(map[string]interface{}{
+			"proof_type":  "recording",
+			"proof_value": recordingURL(sessionID),
+		})
+}
+
+// GetRecording returns the frame timeline of a recorded session
+func (s *BrowserService) GetRecording(userID, sessionID uuid.UUID) (*SessionRecording, error) {
+	session, err := s.GetSession(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.RecordingPath == "" {
+		return nil, ErrNoRecording
+	}
+
+	frames, err := listRecordingFrames(session.RecordingPath)
+	if err != nil {
+		return nil, err
+	}
+	base := recordingURL(sessionID) + "/frames/"
+	for i := range frames {
+		frames[i].URL = base + strconv.Itoa(frames[i].Index)
+	}
+
+	return &SessionRecording{
+		SessionID:       sessionID,
+		TaskExecutionID: session.TaskExecutionID,
+		Status:          session.Status,
+		Frames:          frames,
+	}, nil
+}
+
+// GetRecordingFrame returns one JPEG frame of a recorded session
+func (s *BrowserService) GetRecordingFrame(userID, sessionID uuid.UUID, index int) ([]byte, error) {
+	session, err := s.GetSession(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.RecordingPath == "" {
+		return nil, ErrNoRecording
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(session.RecordingPath, fmt.Sprintf("%06d_*%s", index, recordingFrameExt)))
+	if len(matches) == 0 {
+		return nil, ErrFrameNotFound
+	}
+	return os.ReadFile(matches[0])
+}
+
+// listRecordingFrames reads the timeline from frame file names, which hold the
+// frame index and capture time in milliseconds
+func listRecordingFrames(dir string) ([]RecordingFrame, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []RecordingFrame{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	frames := make([]RecordingFrame, 0, len(entries))
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), recordingFrameExt)
+		indexPart, msPart, ok := strings.Cut(name, "_")
+		if !ok {
+			continue
+		}
+		index, err1 := strconv.Atoi(indexPart)
+		ms, err2 := strconv.ParseInt(msPart, 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		frames = append(frames, RecordingFrame{Index: index, CapturedAt: time.UnixMilli(ms)})
+	}
+	sort.Slice(frames, func(i, j int) bool { return frames[i].Index < frames[j].Index })
+	return frames, nil
+}
+
+// reap periodically kills idle sessions and deletes expired recordings, until Close
+func (s *BrowserService) reap() {
+	ticker := time.NewTicker(s.container.Config.BrowserReaperInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if idle := s.container.Config.BrowserSessionIdleTimeout; idle > 0 {
+				if err := s.CleanupStaleSessions(idle); err != nil {
+					log.Printf("⚠️ Failed to clean up stale browser sessions: %v", err)
+				}
+			}
+			if retention := s.container.Config.BrowserRecordingRetention; retention > 0 {
+				s.purgeRecordings(retention)
+			}
+
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// purgeRecordings deletes the frames of sessions that ended more than retention ago
+func (s *BrowserService) purgeRecordings(retention time.Duration) {
+	var sessions []models.BrowserSession
+	cutoff := time.Now().Add(-retention)
+	if err := s.container.DB.Select("id", "recording_path").
+		Where("recording_path <> '' AND status IN (?, ?) AND COALESCE(stopped_at, updated_at) < ?", "stopped", "failed", cutoff).
+		Find(&sessions).Error; err != nil {
+		log.Printf("⚠️ Failed to load expired recordings: %v", err)
+		return
+	}
+
+	for _, session := range sessions {
+		if err := os.RemoveAll(session.RecordingPath); err != nil {
+			log.Printf("⚠️ Failed to delete recording of session %s: %v", session.ID, err)
+			continue
+		}
+		s.container.DB.Model(&models.BrowserSession{}).Where("id = ?", session.ID).Updates(map[string]interface{}{
+			"recording_path":   "",
+			"recording_frames": 0,
+		})
+		// The proof pointed at frames that no longer exist
+		s.container.DB.Model(&models.TaskExecution{}).
+			Where("browser_session_id = ? AND proof_type = ?", session.ID, "recording").
+			Updates(map[string]interface{}{"proof_type": "", "proof_value": ""})
+	}
+}
+
+// Close stops the session reaper
+func (s *BrowserService) Close() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	"github.com/web3airdropos/backend/internal/models"
+	ws "github.com/web3airdropos/backend/internal/websocket"
+)
+
+// ErrSessionNotLive is returned when a session has no running container to view
+var ErrSessionNotLive = errors.New("browser session is not running")
+
+// vncActivityInterval is how often a connected viewer marks its session active,
+// so the reaper leaves watched sessions alone
+const vncActivityInterval = time.Minute
+
+// vncProxyPath is the backend endpoint clients view a session through
+func vncProxyPath(sessionID uuid.UUID) string {
+	return "/api/v1/browser/sessions/" + sessionID.String() + "/vnc"
+}
+
+// VNCEndpoint returns the internal noVNC WebSocket address of a running session
+// the user owns
+func (s *BrowserService) VNCEndpoint(userID, sessionID uuid.UUID) (string, error) {
+	session, err := s.GetSession(userID, sessionID)
+	if err != nil {
+		return "", err
+	}
+	switch session.Status {
+	case "ready", "busy", "paused":
+	default:
+		return "", ErrSessionNotLive
+	}
+	if session.WebSocketURL == "" {
+		return "", ErrSessionNotLive
+	}
+	return session.WebSocketURL, nil
+}
+
+// RelayVNC relays frames between a viewer and the session's noVNC WebSocket
+// until either side disconnects, then closes both
+func (s *BrowserService) RelayVNC(userID, sessionID uuid.UUID, target string, client *websocket.Conn) error {
+	defer client.Close()
+
+	// Clear any deadlines the HTTP server left on the hijacked connection
+	client.SetReadDeadline(time.Time{})
+	client.SetWriteDeadline(time.Time{})
+
+	dialer := websocket.Dialer{HandshakeTimeout: 10 * time.Second, Subprotocols: []string{"binary"}}
+	upstream, _, err := dialer.Dial(target, http.Header{})
+	if err != nil {
+		client.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "browser VNC unavailable"),
+			time.Now().Add(time.Second))
+		return err
+	}
+	defer upstream.Close()
+
+	s.container.WSHub.BroadcastTerminal(userID.String(), ws.TerminalMessage{
+		Level:   "info",
+		Source:  "browser",
+		Message: "VNC viewer connected to session " + sessionID.String()[:8],
+	})
+
+	done := make(chan error, 2)
+	go relayFrames(upstream, client, done)
+	go relayFrames(client, upstream, done)
+
+	ticker := time.NewTicker(vncActivityInterval)
+	defer ticker.Stop()
+	s.touchSession(sessionID)
+
+	for {
+		select {
+		case err := <-done:
+			// Closing both ends unblocks the other relay
+			client.Close()
+			upstream.Close()
+			s.touchSession(sessionID)
+			s.container.WSHub.BroadcastTerminal(userID.String(), ws.TerminalMessage{
+				Level:   "info",
+				Source:  "browser",
+				Message: "VNC viewer disconnected from session " + sessionID.String()[:8],
+			})
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return nil
+			}
+			return err
+		case <-ticker.C:
+			s.touchSession(sessionID)
+		}
+	}
+}
+
+// relayFrames copies messages from src to dst until a read or write fails
+func relayFrames(dst, src *websocket.Conn, done chan<- error) {
+	for {
+		messageType, data, err := src.ReadMessage()
+		if err != nil {
+			done <- err
+			return
+		}
+		if err := dst.WriteMessage(messageType, data); err != nil {
+			done <- err
+			return
+		}
+	}
+}
+
+func (s *BrowserService) touchSession(sessionID uuid.UUID) {
+	s.container.DB.Model(&models.BrowserSession{}).Where("id = ?", sessionID).Update("last_activity_at", time.Now())
+}
@@ -1,17 +1,28 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/web3airdropos/backend/internal/models"
 	"github.com/web3airdropos/backend/internal/websocket"
 )
 
+var (
+	ErrInvalidTaskOrder = errors.New("invalid task order")
+
+	// ErrConflict is returned when an update carries a version that another
+	// edit has since replaced
+	ErrConflict = errors.New("modified by another update since it was loaded; reload and try again")
+)
+
 type CampaignService struct {
 	container *Container
 }
@@ -32,6 +43,7 @@ type CreateCampaignRequest struct {
 	EstimatedReward string                 `json:"estimated_reward"`
 	RewardType      string                 `json:"reward_type"`
 	WalletGroupIDs  []uuid.UUID            `json:"wallet_group_ids"`
+	MinWarmupScore  int                    `json:"min_warmup_score"`
 	Metadata        map[string]interface{} `json:"metadata"`
 }
 
@@ -44,6 +56,8 @@ type UpdateCampaignRequest struct {
 	EndDate         *time.Time `json:"end_date"`
 	Deadline        *time.Time `json:"deadline"`
 	EstimatedReward string     `json:"estimated_reward"`
+	MinWarmupScore  *int       `json:"min_warmup_score"`
+	Version         *int       `json:"version"` // Version the edit was based on; a stale one fails with ErrConflict
 }
 
 type CampaignProgress struct {
@@ -74,7 +88,7 @@ type AccountTaskProgress struct {
 
 func (s *CampaignService) List(userID uuid.UUID, status string, campaignType string) ([]models.Campaign, error) {
 	var campaigns []models.Campaign
-	query := s.container.DB.Where("user_id = ?", userID).
+	query := s.container.Reader.Where("user_id = ?", userID).
 		Preload("WalletGroups").
 		Preload("Tasks")
 
@@ -113,6 +127,9 @@ func (s *CampaignService) Get(userID, campaignID uuid.UUID) (*models.Campaign, e
 }
 
 func (s *CampaignService) Create(userID uuid.UUID, req *CreateCampaignRequest) (*models.Campaign, error) {
+	if err := validMinWarmupScore(req.MinWarmupScore); err != nil {
+		return nil, err
+	}
 	metadataJSON, _ := json.Marshal(req.Metadata)
 
 	campaign := &models.Campaign{
@@ -129,6 +146,7 @@ func (s *CampaignService) Create(userID uuid.UUID, req *CreateCampaignRequest) (
 		Status:          "active",
 		EstimatedReward: req.EstimatedReward,
 		RewardType:      req.RewardType,
+		MinWarmupScore:  req.MinWarmupScore,
 		Metadata:        string(metadataJSON),
 	}
 
@@ -143,7 +161,8 @@ func (s *CampaignService) Create(userID uuid.UUID, req *CreateCampaignRequest) (
 		s.container.DB.Model(campaign).Association("WalletGroups").Append(&groups)
 	}
 
-	s.container.WSHub.BroadcastToUser(userID.String(), "campaign:created", campaign)
+	s.broadcastCampaign(userID, campaign.ID, "campaign:created", campaign)
+	s.container.Dashboard.InvalidateStats(userID)
 	return campaign, nil
 }
 
@@ -174,25 +193,59 @@ func (s *CampaignService) Update(userID, campaignID uuid.UUID, req *UpdateCampai
 	}
 	if req.Deadline != nil {
 		updates["deadline"] = *req.Deadline
+		updates["reminders_sent"] = "[]" // A new deadline gets its own reminders
 	}
 	if req.EstimatedReward != "" {
 		updates["estimated_reward"] = req.EstimatedReward
 	}
+	if req.MinWarmupScore != nil {
+		if err := validMinWarmupScore(*req.MinWarmupScore); err != nil {
+			return nil, err
+		}
+		updates["min_warmup_score"] = *req.MinWarmupScore
+	}
 
-	if err := s.container.DB.Model(&campaign).Updates(updates).Error; err != nil {
+	wasCompleted := campaign.Status == "completed"
+	if err := updateVersioned(s.container.DB.Model(&campaign), req.Version, updates); err != nil {
+		return nil, err
+	}
+	if err := s.container.DB.First(&campaign, "id = ?", campaign.ID).Error; err != nil {
 		return nil, err
 	}
+	if !wasCompleted && req.Status == "completed" {
+		s.notifyCompleted(userID, &campaign)
+	}
 
-	s.container.WSHub.BroadcastToUser(userID.String(), "campaign:updated", campaign)
+	s.broadcastCampaign(userID, campaign.ID, "campaign:updated", campaign)
+	s.container.Dashboard.InvalidateStats(userID)
 	return &campaign, nil
 }
 
+// updateVersioned applies updates to the row query selects and bumps its version.
+// With an expected version the row must still be at it, or nothing is written and
+// ErrConflict is returned.
+func updateVersioned(query *gorm.DB, expected *int, updates map[string]interface{}) error {
+	updates["version"] = gorm.Expr("version + 1")
+	if expected != nil {
+		query = query.Where("version = ?", *expected)
+	}
+	result := query.Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrConflict
+	}
+	return nil
+}
+
 func (s *CampaignService) Delete(userID, campaignID uuid.UUID) error {
 	result := s.container.DB.Where("id = ? AND user_id = ?", campaignID, userID).Delete(&models.Campaign{})
 	if result.RowsAffected == 0 {
 		return errors.New("campaign not found")
 	}
-	s.container.WSHub.BroadcastToUser(userID.String(), "campaign:deleted", map[string]string{"id": campaignID.String()})
+	s.broadcastCampaign(userID, campaignID, "campaign:deleted", map[string]string{"id": campaignID.String()})
+	s.container.Dashboard.InvalidateStats(userID)
 	return nil
 }
 
@@ -206,7 +259,7 @@ func (s *CampaignService) GetTasks(userID, campaignID uuid.UUID) ([]models.Campa
 	var tasks []models.CampaignTask
 	if err := s.container.DB.Where("campaign_id = ?", campaignID).
 		Preload("Executions").
-		Order("order ASC").
+		Order(`"order" ASC`).
 		Find(&tasks).Error; err != nil {
 		return nil, err
 	}
@@ -224,6 +277,7 @@ type AddTaskRequest struct {
 	RequiredAction string          `json:"required_action"`
 	IsAutomatable  bool            `json:"is_automatable"`
 	RequiresManual bool            `json:"requires_manual"`
+	VerifyAfter    bool            `json:"verify_after"`
 	Points         int             `json:"points"`
 	Order          int             `json:"order"`
 	DependsOn      *uuid.UUID      `json:"depends_on"`
@@ -248,6 +302,7 @@ func (s *CampaignService) AddTask(userID, campaignID uuid.UUID, req *AddTaskRequ
 		RequiredAction: req.RequiredAction,
 		IsAutomatable:  req.IsAutomatable,
 		RequiresManual: req.RequiresManual,
+		VerifyAfter:    req.VerifyAfter,
 		Points:         req.Points,
 		Order:          req.Order,
 		DependsOn:      req.DependsOn,
@@ -260,23 +315,103 @@ func (s *CampaignService) AddTask(userID, campaignID uuid.UUID, req *AddTaskRequ
 	// Update campaign task count
 	s.container.DB.Model(&campaign).Update("total_tasks", gorm.Expr("total_tasks + 1"))
 
-	s.container.WSHub.BroadcastToUser(userID.String(), "task:created", task)
+	s.container.WSHub.BroadcastToUserTopics(userID.String(), []string{
+		"task",
+		websocket.EntityTopic("task", task.ID.String()),
+		websocket.EntityTopic("campaign", task.CampaignID.String()),
+	}, "task:created", task)
 	return task, nil
 }
 
+type ReorderTasksRequest struct {
+	TaskIDs []uuid.UUID `json:"task_ids" binding:"required"`
+}
+
+// ReorderTasks sets the campaign's task order to orderedTaskIDs, which must list
+// every task of the campaign exactly once. Orders are assigned from 1.
+func (s *CampaignService) ReorderTasks(userID, campaignID uuid.UUID, orderedTaskIDs []uuid.UUID) ([]models.CampaignTask, error) {
+	var campaign models.Campaign
+	if err := s.container.DB.Where("id = ? AND user_id = ?", campaignID, userID).First(&campaign).Error; err != nil {
+		return nil, err
+	}
+
+	err := s.container.DB.Transaction(func(tx *gorm.DB) error {
+		var tasks []models.CampaignTask
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("campaign_id = ?", campaignID).
+			Find(&tasks).Error; err != nil {
+			return err
+		}
+
+		if len(orderedTaskIDs) != len(tasks) {
+			return fmt.Errorf("%w: expected %d task IDs, got %d", ErrInvalidTaskOrder, len(tasks), len(orderedTaskIDs))
+		}
+		existing := make(map[uuid.UUID]bool, len(tasks))
+		for _, task := range tasks {
+			existing[task.ID] = true
+		}
+		seen := make(map[uuid.UUID]bool, len(orderedTaskIDs))
+		for _, id := range orderedTaskIDs {
+			if !existing[id] {
+				return fmt.Errorf("%w: task %s is not in this campaign", ErrInvalidTaskOrder, id)
+			}
+			if seen[id] {
+				return fmt.Errorf("%w: task %s is listed twice", ErrInvalidTaskOrder, id)
+			}
+			seen[id] = true
+		}
+
+		for i, id := range orderedTaskIDs {
+			if err := tx.Model(&models.CampaignTask{}).Where("id = ?", id).Updates(map[string]interface{}{
+				"order":   i + 1,
+				"version": gorm.Expr("version + 1"),
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.GetTasks(userID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.broadcastCampaign(userID, campaignID, "campaign:tasks_reordered", map[string]interface{}{
+		"campaign_id": campaignID,
+		"task_ids":    orderedTaskIDs,
+	})
+	return tasks, nil
+}
+
 type BulkExecuteRequest struct {
 	WalletIDs   []uuid.UUID `json:"wallet_ids"`
 	AccountIDs  []uuid.UUID `json:"account_ids"`
 	TaskIDs     []uuid.UUID `json:"task_ids"`
 	Parallel    bool        `json:"parallel"`
 	MaxParallel int         `json:"max_parallel"`
+	DryRun      bool        `json:"dry_run"` // Return and stream the plan without executing anything
 }
 
-func (s *CampaignService) ExecuteBulk(userID, campaignID uuid.UUID, req *BulkExecuteRequest) error {
+// ExecuteBulk queues a bulk execution job. A dry run queues nothing; it returns
+// the plan from PlanBulk and streams it to the terminal instead.
+func (s *CampaignService) ExecuteBulk(ctx context.Context, userID, campaignID uuid.UUID, req *BulkExecuteRequest) (*BulkExecutionPlan, error) {
 	// Verify ownership
 	var campaign models.Campaign
 	if err := s.container.DB.Where("id = ? AND user_id = ?", campaignID, userID).First(&campaign).Error; err != nil {
-		return err
+		return nil, err
+	}
+
+	if req.DryRun {
+		plan, err := s.PlanBulk(ctx, userID, campaignID, req)
+		if err != nil {
+			return nil, err
+		}
+		s.streamPlan(userID, &campaign, plan)
+		return plan, nil
 	}
 
 	// Create automation job for bulk execution
@@ -303,7 +438,7 @@ func (s *CampaignService) ExecuteBulk(userID, campaignID uuid.UUID, req *BulkExe
 	}
 
 	if err := s.container.DB.Create(job).Error; err != nil {
-		return err
+		return nil, err
 	}
 
 	// Notify terminal
@@ -327,7 +462,7 @@ func (s *CampaignService) ExecuteBulk(userID, campaignID uuid.UUID, req *BulkExe
 	})
 	s.container.Redis.LPush(s.container.Redis.Context(), "job:queue", string(jobPayload))
 
-	return nil
+	return nil, nil
 }
 
 func (s *CampaignService) GetProgress(userID, campaignID uuid.UUID) (*CampaignProgress, error) {
@@ -385,7 +520,7 @@ func (s *CampaignService) GetProgress(userID, campaignID uuid.UUID) (*CampaignPr
 	for _, task := range campaign.Tasks {
 		for _, exec := range task.Executions {
 			if exec.WalletID != nil {
-				if wp, ok := walletProgressMap[*exec.WalletID]; ok && exec.Status == "completed" {
+				if wp, ok := walletProgressMap[*exec.WalletID]; ok && exec.Status == models.ExecutionCompleted {
 					wp.CompletedTasks++
 				}
 			}
@@ -403,7 +538,7 @@ func (s *CampaignService) calculateProgress(campaign *models.Campaign) {
 	var completed int64
 	s.container.DB.Model(&models.TaskExecution{}).
 		Joins("JOIN campaign_tasks ON campaign_tasks.id = task_executions.task_id").
-		Where("campaign_tasks.campaign_id = ? AND task_executions.status = ?", campaign.ID, "completed").
+		Where("campaign_tasks.campaign_id = ? AND task_executions.status IN ?", campaign.ID, models.ExecutionStatusValues(models.ExecutionCompleted)).
 		Count(&completed)
 
 	campaign.CompletedTasks = int(completed)
@@ -411,3 +546,55 @@ func (s *CampaignService) calculateProgress(campaign *models.Campaign) {
 		campaign.ProgressPercent = float64(completed) / float64(campaign.TotalTasks) * 100
 	}
 }
+
+// completeIfDone marks an active campaign completed once every task has a completed
+// execution, and notifies the user's webhooks
+func (s *CampaignService) completeIfDone(userID, campaignID uuid.UUID) {
+	var campaign models.Campaign
+	if err := s.container.DB.Where("id = ? AND user_id = ?", campaignID, userID).First(&campaign).Error; err != nil {
+		return
+	}
+	if campaign.Status != "active" {
+		return
+	}
+
+	var total, done int64
+	s.container.DB.Model(&models.CampaignTask{}).Where("campaign_id = ?", campaignID).Count(&total)
+	s.container.DB.Model(&models.TaskExecution{}).
+		Joins("JOIN campaign_tasks ON campaign_tasks.id = task_executions.task_id").
+		Where("campaign_tasks.campaign_id = ? AND task_executions.status IN ?", campaignID, models.ExecutionStatusValues(models.ExecutionCompleted)).
+		Distinct("task_executions.task_id").
+		Count(&done)
+	if total == 0 || done < total {
+		return
+	}
+
+	// Conditional so concurrent task completions notify once
+	result := s.container.DB.Model(&models.Campaign{}).
+		Where("id = ? AND status = ?", campaignID, "active").
+		Update("status", "completed")
+	if result.Error != nil || result.RowsAffected == 0 {
+		return
+	}
+	campaign.Status = "completed"
+
+	s.broadcastCampaign(userID, campaign.ID, "campaign:updated", campaign)
+	s.container.Dashboard.InvalidateStats(userID)
+	s.notifyCompleted(userID, &campaign)
+}
+
+// broadcastCampaign sends a campaign event to the user, tagged with the
+// campaign's own topic so clients following only that campaign receive it
+func (s *CampaignService) broadcastCampaign(userID, campaignID uuid.UUID, msgType string, payload interface{}) {
+	s.container.WSHub.BroadcastToUserTopics(userID.String(),
+		[]string{"campaign", websocket.EntityTopic("campaign", campaignID.String())}, msgType, payload)
+}
+
+func (s *CampaignService) notifyCompleted(userID uuid.UUID, campaign *models.Campaign) {
+	s.container.WebhookDispatcher.Dispatch(userID, models.WebhookEventCampaignCompleted, map[string]interface{}{
+		"campaign_id": campaign.ID,
+		"name":        campaign.Name,
+		"type":        campaign.Type,
+		"status":      campaign.Status,
+	})
+}
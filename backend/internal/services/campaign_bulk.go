@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+// Bulk plan step actions
+const (
+	BulkActionExecute = "execute" // Run by the bulk job
+	BulkActionManual  = "manual"  // Recorded as waiting for the user, e.g. to sign
+	BulkActionSkip    = "skip"    // Left out of the run
+)
+
+// BulkPlanStep is one task and wallet or account combination of a bulk execution
+type BulkPlanStep struct {
+	TaskID    uuid.UUID            `json:"task_id"`
+	TaskName  string               `json:"task_name,omitempty"`
+	TaskType  models.TaskType      `json:"task_type,omitempty"`
+	WalletID  *uuid.UUID           `json:"wallet_id,omitempty"`
+	AccountID *uuid.UUID           `json:"account_id,omitempty"`
+	Target    string               `json:"target,omitempty"` // Wallet address or account username
+	Action    string               `json:"action"`
+	Reason    string               `json:"reason,omitempty"`
+	ChainID   int64                `json:"chain_id,omitempty"`
+	Gas       *TransactionEstimate `json:"gas,omitempty"`
+}
+
+// BulkExecutionPlan is what a bulk execution would do. EstimatedCost sums the
+// gas estimates per chain ID, in wei.
+type BulkExecutionPlan struct {
+	CampaignID    uuid.UUID        `json:"campaign_id"`
+	Actions       []BulkPlanStep   `json:"actions"`
+	Skipped       []BulkPlanStep   `json:"skipped"`
+	Execute       int              `json:"execute"`
+	Manual        int              `json:"manual"`
+	EstimatedCost map[int64]string `json:"estimated_cost"`
+}
+
+// isWalletTask reports whether a task type runs from a wallet rather than an account
+func isWalletTask(taskType models.TaskType) bool {
+	switch taskType {
+	case models.TaskTypeConnect, models.TaskTypeTransaction, models.TaskTypeClaim, models.TaskTypeApprove:
+		return true
+	}
+	return false
+}
+
+// PlanBulk resolves every task of req against its wallets or accounts and runs
+// the checks a bulk execution would, without side effects: no executions are
+// recorded, no nonces reserved and nothing is queued. Wallet tasks pair with the
+// wallets and the rest with the accounts.
+func (s *CampaignService) PlanBulk(ctx context.Context, userID, campaignID uuid.UUID, req *BulkExecuteRequest) (*BulkExecutionPlan, error) {
+	var campaign models.Campaign
+	if err := s.container.DB.Where("id = ? AND user_id = ?", campaignID, userID).First(&campaign).Error; err != nil {
+		return nil, err
+	}
+
+	plan := &BulkExecutionPlan{
+		CampaignID:    campaignID,
+		Actions:       []BulkPlanStep{},
+		Skipped:       []BulkPlanStep{},
+		EstimatedCost: map[int64]string{},
+	}
+
+	var tasks []models.CampaignTask
+	if len(req.TaskIDs) > 0 {
+		if err := s.container.DB.Where("campaign_id = ? AND id IN ?", campaignID, req.TaskIDs).
+			Order(`"order" ASC`).Find(&tasks).Error; err != nil {
+			return nil, err
+		}
+	}
+	found := make(map[uuid.UUID]bool, len(tasks))
+	for _, task := range tasks {
+		found[task.ID] = true
+	}
+	for _, taskID := range req.TaskIDs {
+		if !found[taskID] {
+			plan.Skipped = append(plan.Skipped, BulkPlanStep{TaskID: taskID, Action: BulkActionSkip, Reason: "task not found in this campaign"})
+		}
+	}
+
+	var wallets []models.Wallet
+	if len(req.WalletIDs) > 0 {
+		if err := s.container.DB.Where("id IN ? AND user_id = ?", req.WalletIDs, userID).Find(&wallets).Error; err != nil {
+			return nil, err
+		}
+	}
+	var accounts []models.PlatformAccount
+	if len(req.AccountIDs) > 0 {
+		if err := s.container.DB.Where("id IN ? AND user_id = ?", req.AccountIDs, userID).Find(&accounts).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	plan.Skipped = append(plan.Skipped, missingTargets(req.WalletIDs, wallets, req.AccountIDs, accounts)...)
+
+	costs := map[int64]*big.Int{}
+	for i := range tasks {
+		task := &tasks[i]
+		depErr := s.checkDependency(task)
+
+		if isWalletTask(task.Type) {
+			for j := range wallets {
+				wallet := &wallets[j]
+				step := BulkPlanStep{
+					TaskID:   task.ID,
+					TaskName: task.Name,
+					TaskType: task.Type,
+					WalletID: &wallet.ID,
+					Target:   wallet.Address,
+				}
+				s.planStep(userID, task, &step, depErr, &ExecuteTaskRequest{WalletID: &wallet.ID})
+				if step.Action != BulkActionSkip {
+					s.planWalletStep(ctx, userID, task, wallet, &step)
+				}
+				plan.add(step, costs)
+			}
+			continue
+		}
+
+		for j := range accounts {
+			account := &accounts[j]
+			step := BulkPlanStep{
+				TaskID:    task.ID,
+				TaskName:  task.Name,
+				TaskType:  task.Type,
+				AccountID: &account.ID,
+				Target:    account.Username,
+			}
+			if !account.IsActive {
+				step.Action, step.Reason = BulkActionSkip, "account is inactive"
+			} else if campaign.MinWarmupScore > 0 && account.WarmupScore < campaign.MinWarmupScore {
+				step.Action = BulkActionSkip
+				step.Reason = fmt.Sprintf("account warmup score %d is below the campaign's minimum of %d", account.WarmupScore, campaign.MinWarmupScore)
+			} else {
+				s.planStep(userID, task, &step, depErr, &ExecuteTaskRequest{AccountID: &account.ID})
+			}
+			plan.add(step, costs)
+		}
+	}
+
+	for chainID, cost := range costs {
+		plan.EstimatedCost[chainID] = cost.String()
+	}
+	return plan, nil
+}
+
+// missingTargets returns a skipped step for each requested wallet or account
+// the user does not have
+func missingTargets(walletIDs []uuid.UUID, wallets []models.Wallet, accountIDs []uuid.UUID, accounts []models.PlatformAccount) []BulkPlanStep {
+	have := make(map[uuid.UUID]bool, len(wallets)+len(accounts))
+	for _, wallet := range wallets {
+		have[wallet.ID] = true
+	}
+	for _, account := range accounts {
+		have[account.ID] = true
+	}
+
+	var missing []BulkPlanStep
+	for i := range walletIDs {
+		if !have[walletIDs[i]] {
+			missing = append(missing, BulkPlanStep{WalletID: &walletIDs[i], Action: BulkActionSkip, Reason: "wallet not found"})
+		}
+	}
+	for i := range accountIDs {
+		if !have[accountIDs[i]] {
+			missing = append(missing, BulkPlanStep{AccountID: &accountIDs[i], Action: BulkActionSkip, Reason: "account not found"})
+		}
+	}
+	return missing
+}
+
+// checkDependency returns ErrDependencyNotCompleted while the task's dependency
+// has no completed execution
+func (s *CampaignService) checkDependency(task *models.CampaignTask) error {
+	if task.DependsOn == nil {
+		return nil
+	}
+	var count int64
+	s.container.DB.Model(&models.TaskExecution{}).
+		Where("task_id = ? AND status IN ?", *task.DependsOn, models.ExecutionStatusValues(models.ExecutionCompleted)).
+		Count(&count)
+	if count == 0 {
+		return ErrDependencyNotCompleted
+	}
+	return nil
+}
+
+// planStep applies the dependency and idempotency checks to a step and picks its
+// action; step.Action is left set to skip when either fails
+func (s *CampaignService) planStep(userID uuid.UUID, task *models.CampaignTask, step *BulkPlanStep, depErr error, req *ExecuteTaskRequest) {
+	if depErr != nil {
+		step.Action, step.Reason = BulkActionSkip, depErr.Error()
+		return
+	}
+
+	key := s.container.Task.generateIdempotencyKey(userID, task.ID, req)
+	var executed int64
+	s.container.DB.Model(&models.TaskExecution{}).Where("idempotency_key = ?", key).Count(&executed)
+	if executed > 0 {
+		step.Action, step.Reason = BulkActionSkip, "already executed today"
+		return
+	}
+
+	query := s.container.DB.Model(&models.TaskExecution{}).
+		Where("task_id = ? AND status IN ?", task.ID, models.ExecutionStatusValues(models.ExecutionCompleted))
+	if req.WalletID != nil {
+		query = query.Where("wallet_id = ?", *req.WalletID)
+	} else {
+		query = query.Where("account_id = ?", *req.AccountID)
+	}
+	var completed int64
+	query.Count(&completed)
+	if completed > 0 {
+		step.Action, step.Reason = BulkActionSkip, "already completed"
+		return
+	}
+
+	step.Action = BulkActionExecute
+	if task.RequiresManual {
+		step.Action, step.Reason = BulkActionManual, "task requires manual completion"
+	}
+}
+
+// planWalletStep estimates the gas of a wallet step. Bulk runs leave wallet tasks
+// for the user to sign, so the step becomes manual unless a check skips it.
+func (s *CampaignService) planWalletStep(ctx context.Context, userID uuid.UUID, task *models.CampaignTask, wallet *models.Wallet, step *BulkPlanStep) {
+	step.Action, step.Reason = BulkActionManual, "left for you to sign"
+
+	var tx *PrepareTransactionRequest
+	switch task.Type {
+	case models.TaskTypeTransaction:
+		cfg, err := parseTransactionConfig(task.Config)
+		if errors.Is(err, errTransactionNeedsBrowser) {
+			step.Reason = err.Error()
+			return
+		}
+		if err != nil {
+			step.Action, step.Reason = BulkActionSkip, err.Error()
+			return
+		}
+		tx = &PrepareTransactionRequest{ChainID: cfg.ChainID, To: cfg.To, Value: cfg.Value, Data: cfg.Data, GasLimit: cfg.GasLimit}
+
+	case models.TaskTypeApprove:
+		cfg, amount, err := parseApproveConfig(task.Config)
+		if err != nil {
+			step.Action, step.Reason = BulkActionSkip, err.Error()
+			return
+		}
+		token, spender := common.HexToAddress(cfg.Token), common.HexToAddress(cfg.Spender)
+		if wallet.Type == models.WalletTypeEVM {
+			allowance, err := s.container.Task.allowance(ctx, userID, cfg.ChainID, token, common.HexToAddress(wallet.Address), spender)
+			if err == nil && allowance.Cmp(amount) >= 0 {
+				step.Action, step.Reason = BulkActionSkip, "allowance already sufficient"
+				return
+			}
+		}
+		tx = &PrepareTransactionRequest{ChainID: cfg.ChainID, To: token.Hex(), Data: hex.EncodeToString(approveCalldata(spender, amount))}
+
+	default:
+		return
+	}
+
+	if wallet.Type != models.WalletTypeEVM {
+		step.Action, step.Reason = BulkActionSkip, "not an EVM wallet"
+		return
+	}
+	step.ChainID = tx.ChainID
+	estimate, err := s.container.Wallet.EstimateTransaction(ctx, userID, wallet.ID, tx)
+	if err != nil {
+		step.Reason = "gas estimate failed: " + err.Error()
+		return
+	}
+	step.Gas = estimate
+}
+
+// add files a step under actions or skipped and counts its gas toward costs
+func (p *BulkExecutionPlan) add(step BulkPlanStep, costs map[int64]*big.Int) {
+	switch step.Action {
+	case BulkActionSkip:
+		p.Skipped = append(p.Skipped, step)
+		return
+	case BulkActionManual:
+		p.Manual++
+	default:
+		p.Execute++
+	}
+	p.Actions = append(p.Actions, step)
+
+	if step.Gas != nil {
+		cost, ok := new(big.Int).SetString(step.Gas.Cost, 10)
+		if !ok {
+			return
+		}
+		if costs[step.ChainID] == nil {
+			costs[step.ChainID] = new(big.Int)
+		}
+		costs[step.ChainID].Add(costs[step.ChainID], cost)
+	}
+}
+
+// streamPlan writes a plan to the user's terminal, one line per step and a summary
+func (s *CampaignService) streamPlan(userID uuid.UUID, campaign *models.Campaign, plan *BulkExecutionPlan) {
+	terminal := func(level, message, taskID string, details interface{}) {
+		s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+			Level:      level,
+			Source:     "bulk",
+			Message:    message,
+			Details:    details,
+			TaskID:     taskID,
+			CampaignID: campaign.ID.String(),
+		})
+	}
+
+	terminal("info", "Dry run for "+campaign.Name+" - nothing will be executed", "", nil)
+	for _, step := range append(append([]BulkPlanStep{}, plan.Actions...), plan.Skipped...) {
+		level, message := "info", fmt.Sprintf("[dry run] %s %s %q", step.Action, step.TaskType, step.TaskName)
+		if step.Target != "" {
+			message += " with " + step.Target
+		}
+		if step.Action != BulkActionExecute {
+			level = "warn"
+		}
+		switch {
+		case step.TaskName != "":
+		case step.WalletID != nil:
+			message = "[dry run] skip wallet " + step.WalletID.String()
+		case step.AccountID != nil:
+			message = "[dry run] skip account " + step.AccountID.String()
+		default:
+			message = "[dry run] skip task " + step.TaskID.String()
+		}
+		if step.Reason != "" {
+			message += ": " + step.Reason
+		}
+		if step.Gas != nil {
+			message += fmt.Sprintf(" (gas %d, ~%s wei on chain %d)", step.Gas.GasLimit, step.Gas.Cost, step.ChainID)
+		}
+		terminal(level, message, step.TaskID.String(), step)
+	}
+	terminal("success", fmt.Sprintf("Dry run complete: %d to execute, %d manual, %d skipped",
+		plan.Execute, plan.Manual, len(plan.Skipped)), "", map[string]interface{}{"estimated_cost": plan.EstimatedCost})
+}
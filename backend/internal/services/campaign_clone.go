@@ -0,0 +1,110 @@
+package services
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+type CloneCampaignRequest struct {
+	Name           string       `json:"name" binding:"required"`
+	WalletGroupIDs *[]uuid.UUID `json:"wallet_group_ids"` // Omit to keep the source campaign's groups
+}
+
+// Clone copies a campaign and its tasks into a new active campaign with no
+// executions or progress. Task order and config are kept and dependencies point at
+// the copied tasks. walletGroupIDs replaces the wallet groups when not nil.
+func (s *CampaignService) Clone(userID, campaignID uuid.UUID, newName string, walletGroupIDs *[]uuid.UUID) (*models.Campaign, error) {
+	var source models.Campaign
+	if err := s.container.DB.Where("id = ? AND user_id = ?", campaignID, userID).
+		Preload("WalletGroups").
+		Preload("Tasks").
+		First(&source).Error; err != nil {
+		return nil, err
+	}
+
+	campaign := &models.Campaign{
+		ID:              uuid.New(),
+		UserID:          userID,
+		Name:            newName,
+		Description:     source.Description,
+		Type:            source.Type,
+		URL:             source.URL,
+		ImageURL:        source.ImageURL,
+		StartDate:       source.StartDate,
+		EndDate:         source.EndDate,
+		Deadline:        source.Deadline,
+		Status:          "active",
+		Priority:        source.Priority,
+		EstimatedReward: source.EstimatedReward,
+		RewardType:      source.RewardType,
+		TotalTasks:      len(source.Tasks),
+		Metadata:        source.Metadata,
+	}
+	tasks := cloneTasks(source.Tasks, campaign.ID)
+
+	groups := source.WalletGroups
+	if walletGroupIDs != nil {
+		groups = nil
+		if len(*walletGroupIDs) > 0 {
+			if err := s.container.DB.Where("id IN ? AND user_id = ?", *walletGroupIDs, userID).Find(&groups).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err := s.container.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("WalletGroups", "Tasks").Create(campaign).Error; err != nil {
+			return err
+		}
+		if len(tasks) > 0 {
+			if err := tx.Omit("Executions").Create(&tasks).Error; err != nil {
+				return err
+			}
+		}
+		if len(groups) > 0 {
+			return tx.Model(campaign).Association("WalletGroups").Append(&groups)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	campaign.Tasks = tasks
+	campaign.WalletGroups = groups
+	s.broadcastCampaign(userID, campaign.ID, "campaign:created", campaign)
+	s.container.Dashboard.InvalidateStats(userID)
+	return campaign, nil
+}
+
+// cloneTasks copies tasks into a campaign under new IDs. A dependency on another
+// task in the set is remapped to that task's copy; one outside it is kept as is.
+func cloneTasks(tasks []models.CampaignTask, campaignID uuid.UUID) []models.CampaignTask {
+	ids := make(map[uuid.UUID]uuid.UUID, len(tasks))
+	for _, task := range tasks {
+		ids[task.ID] = uuid.New()
+	}
+
+	clones := make([]models.CampaignTask, 0, len(tasks))
+	for _, task := range tasks {
+		clone := task
+		clone.ID = ids[task.ID]
+		clone.CampaignID = campaignID
+		clone.Executions = nil
+		clone.CreatedAt, clone.UpdatedAt = time.Time{}, time.Time{}
+		if task.DependsOn != nil {
+			if mapped, ok := ids[*task.DependsOn]; ok {
+				clone.DependsOn = &mapped
+			} else {
+				dependsOn := *task.DependsOn
+				clone.DependsOn = &dependsOn
+			}
+		}
+		clones = append(clones, clone)
+	}
+	return clones
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+func TestCloneTasksRemapsDependencies(t *testing.T) {
+	oldCampaign, newCampaign := uuid.New(), uuid.New()
+	a, b, c, d, outside := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	source := []models.CampaignTask{
+		{ID: a, CampaignID: oldCampaign, Name: "follow", Order: 1, Config: `{"x":1}`},
+		{ID: b, CampaignID: oldCampaign, Name: "like", Order: 2, DependsOn: &a},
+		{ID: c, CampaignID: oldCampaign, Name: "recast", Order: 3, DependsOn: &b,
+			Executions: []models.TaskExecution{{ID: uuid.New()}}},
+		{ID: d, CampaignID: oldCampaign, Name: "post", Order: 4, DependsOn: &outside},
+	}
+
+	clones := cloneTasks(source, newCampaign)
+	if len(clones) != len(source) {
+		t.Fatalf("got %d clones, want %d", len(clones), len(source))
+	}
+
+	byName := make(map[string]models.CampaignTask, len(clones))
+	oldIDs := map[uuid.UUID]bool{a: true, b: true, c: true, d: true}
+	for i, clone := range clones {
+		if oldIDs[clone.ID] {
+			t.Errorf("clone %s kept its source ID", clone.Name)
+		}
+		if clone.CampaignID != newCampaign {
+			t.Errorf("clone %s is in campaign %s, want %s", clone.Name, clone.CampaignID, newCampaign)
+		}
+		if clone.Order != source[i].Order || clone.Config != source[i].Config {
+			t.Errorf("clone %s lost its order or config", clone.Name)
+		}
+		if len(clone.Executions) != 0 {
+			t.Errorf("clone %s carries %d executions", clone.Name, len(clone.Executions))
+		}
+		byName[clone.Name] = clone
+	}
+
+	dependsOn := func(name string) uuid.UUID {
+		if byName[name].DependsOn == nil {
+			t.Fatalf("clone %s lost its dependency", name)
+		}
+		return *byName[name].DependsOn
+	}
+	if byName["follow"].DependsOn != nil {
+		t.Errorf("follow gained a dependency")
+	}
+	if dependsOn("like") != byName["follow"].ID {
+		t.Errorf("like depends on %s, want the copied follow %s", dependsOn("like"), byName["follow"].ID)
+	}
+	if dependsOn("recast") != byName["like"].ID {
+		t.Errorf("recast depends on %s, want the copied like %s", dependsOn("recast"), byName["like"].ID)
+	}
+	if dependsOn("post") != outside {
+		t.Errorf("post depends on %s, want the task outside the campaign %s kept", dependsOn("post"), outside)
+	}
+
+	// The source tasks are left untouched
+	if *source[1].DependsOn != a || *source[2].DependsOn != b || source[0].CampaignID != oldCampaign {
+		t.Error("cloning modified the source tasks")
+	}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+// CampaignExportVersion is the current export schema. Bump it when the format
+// changes and teach migrateExport to upgrade older documents.
+const CampaignExportVersion = 1
+
+var ErrUnsupportedExportVersion = errors.New("unsupported campaign export version")
+
+// CampaignExport is a portable campaign definition. It carries no IDs, executions,
+// wallet groups or progress, and dependencies refer to task keys.
+type CampaignExport struct {
+	SchemaVersion int                   `json:"schema_version" binding:"required"`
+	ExportedAt    time.Time             `json:"exported_at"`
+	Campaign      ExportedCampaign      `json:"campaign" binding:"required"`
+	Tasks         []models.TemplateTask `json:"tasks"`
+}
+
+type ExportedCampaign struct {
+	Name            string              `json:"name" binding:"required"`
+	Description     string              `json:"description,omitempty"`
+	Type            models.CampaignType `json:"type" binding:"required"`
+	URL             string              `json:"url,omitempty"`
+	ImageURL        string              `json:"image_url,omitempty"`
+	StartDate       time.Time           `json:"start_date"`
+	EndDate         time.Time           `json:"end_date"`
+	Deadline        *time.Time          `json:"deadline,omitempty"`
+	Priority        int                 `json:"priority"`
+	EstimatedReward string              `json:"estimated_reward,omitempty"`
+	RewardType      string              `json:"reward_type,omitempty"`
+	Metadata        json.RawMessage     `json:"metadata,omitempty"`
+}
+
+func (s *CampaignService) Export(userID, campaignID uuid.UUID) (*CampaignExport, error) {
+	var campaign models.Campaign
+	if err := s.container.DB.Where("id = ? AND user_id = ?", campaignID, userID).
+		Preload("Tasks").
+		First(&campaign).Error; err != nil {
+		return nil, err
+	}
+
+	doc := &CampaignExport{
+		SchemaVersion: CampaignExportVersion,
+		ExportedAt:    time.Now().UTC(),
+		Campaign: ExportedCampaign{
+			Name:            campaign.Name,
+			Description:     campaign.Description,
+			Type:            campaign.Type,
+			URL:             campaign.URL,
+			ImageURL:        campaign.ImageURL,
+			StartDate:       campaign.StartDate,
+			EndDate:         campaign.EndDate,
+			Deadline:        campaign.Deadline,
+			Priority:        campaign.Priority,
+			EstimatedReward: campaign.EstimatedReward,
+			RewardType:      campaign.RewardType,
+		},
+		Tasks: toTemplateTasks(campaign.Tasks),
+	}
+	if json.Valid([]byte(campaign.Metadata)) {
+		doc.Campaign.Metadata = json.RawMessage(campaign.Metadata)
+	}
+	return doc, nil
+}
+
+// Import creates a new active campaign from an export, upgrading older schema
+// versions first
+func (s *CampaignService) Import(userID uuid.UUID, doc *CampaignExport) (*models.Campaign, error) {
+	if err := migrateExport(doc); err != nil {
+		return nil, err
+	}
+
+	exported := doc.Campaign
+	campaign := &models.Campaign{
+		ID:              uuid.New(),
+		UserID:          userID,
+		Name:            exported.Name,
+		Description:     exported.Description,
+		Type:            exported.Type,
+		URL:             exported.URL,
+		ImageURL:        exported.ImageURL,
+		StartDate:       exported.StartDate,
+		EndDate:         exported.EndDate,
+		Deadline:        exported.Deadline,
+		Status:          "active",
+		Priority:        exported.Priority,
+		EstimatedReward: exported.EstimatedReward,
+		RewardType:      exported.RewardType,
+		Metadata:        string(exported.Metadata),
+	}
+	if err := s.createWithTasks(userID, campaign, doc.Tasks, nil); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// migrateExport upgrades a document to CampaignExportVersion in place
+func migrateExport(doc *CampaignExport) error {
+	switch {
+	case doc.SchemaVersion < 1, doc.SchemaVersion > CampaignExportVersion:
+		return fmt.Errorf("%w: %d", ErrUnsupportedExportVersion, doc.SchemaVersion)
+	}
+	// Version 1 is current; upgrades from older versions go here in order
+	return nil
+}
@@ -0,0 +1,391 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+var (
+	ErrTemplateNotFound      = errors.New("campaign template not found")
+	ErrMissingTemplateParams = errors.New("missing template params")
+	ErrInvalidTemplate       = errors.New("invalid campaign template")
+)
+
+// templateParam matches a {{name}} placeholder
+var templateParam = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+type SaveTemplateRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	IsPublic    bool   `json:"is_public"`
+	// Params turns concrete values into placeholders: {"target_account": "vitalik"}
+	// saves every "vitalik" as {{target_account}}
+	Params map[string]string `json:"params"`
+}
+
+type CreateFromTemplateRequest struct {
+	Name           string            `json:"name"` // Defaults to the template's name
+	Params         map[string]string `json:"params"`
+	WalletGroupIDs []uuid.UUID       `json:"wallet_group_ids"`
+}
+
+// SaveAsTemplate stores a campaign as a template. Wallet groups, executions,
+// progress and dates are left out, and values named in params become placeholders.
+func (s *CampaignService) SaveAsTemplate(userID, campaignID uuid.UUID, req *SaveTemplateRequest) (*models.CampaignTemplate, error) {
+	var campaign models.Campaign
+	if err := s.container.DB.Where("id = ? AND user_id = ?", campaignID, userID).
+		Preload("Tasks").
+		First(&campaign).Error; err != nil {
+		return nil, err
+	}
+
+	parameterize := newParameterizer(req.Params)
+	specs := toTemplateTasks(campaign.Tasks)
+	for i := range specs {
+		spec := &specs[i]
+		spec.Name = parameterize(spec.Name, false)
+		spec.Description = parameterize(spec.Description, false)
+		spec.TargetURL = parameterize(spec.TargetURL, false)
+		spec.TargetAccount = parameterize(spec.TargetAccount, false)
+		spec.RequiredAction = parameterize(spec.RequiredAction, false)
+		spec.Config = parameterize(spec.Config, true)
+	}
+	tasksJSON, err := json.Marshal(specs)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &models.CampaignTemplate{
+		ID:               uuid.New(),
+		UserID:           &userID,
+		SourceCampaignID: &campaign.ID,
+		Name:             req.Name,
+		Description:      req.Description,
+		IsPublic:         req.IsPublic,
+		Type:             campaign.Type,
+		URL:              parameterize(campaign.URL, false),
+		ImageURL:         campaign.ImageURL,
+		EstimatedReward:  campaign.EstimatedReward,
+		RewardType:       campaign.RewardType,
+		Metadata:         parameterize(campaign.Metadata, true),
+		Tasks:            string(tasksJSON),
+	}
+	if template.Metadata == "" {
+		template.Metadata = "{}"
+	}
+	if template.Description == "" {
+		template.Description = parameterize(campaign.Description, false)
+	}
+	paramsJSON, _ := json.Marshal(templateParams(template))
+	template.Params = string(paramsJSON)
+
+	if err := s.container.DB.Create(template).Error; err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// ListTemplates returns public templates and the user's own, most used first
+func (s *CampaignService) ListTemplates(userID uuid.UUID) ([]models.CampaignTemplate, error) {
+	var templates []models.CampaignTemplate
+	if err := s.container.DB.Where("is_public = ? OR user_id = ?", true, userID).
+		Order("usage_count DESC, created_at DESC").
+		Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// GetTemplate returns a template that is public or the user's own
+func (s *CampaignService) GetTemplate(userID, templateID uuid.UUID) (*models.CampaignTemplate, error) {
+	var template models.CampaignTemplate
+	if err := s.container.DB.Where("id = ? AND (is_public = ? OR user_id = ?)", templateID, true, userID).
+		First(&template).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (s *CampaignService) DeleteTemplate(userID, templateID uuid.UUID) error {
+	result := s.container.DB.Where("id = ? AND user_id = ?", templateID, userID).Delete(&models.CampaignTemplate{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// CreateFromTemplate creates a campaign with its tasks from a template, filling
+// every placeholder from params
+func (s *CampaignService) CreateFromTemplate(userID, templateID uuid.UUID, req *CreateFromTemplateRequest) (*models.Campaign, error) {
+	template, err := s.GetTemplate(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, name := range templateParams(template) {
+		if _, ok := req.Params[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMissingTemplateParams, strings.Join(missing, ", "))
+	}
+	fill := newFiller(req.Params)
+
+	var specs []models.TemplateTask
+	if template.Tasks != "" {
+		if err := json.Unmarshal([]byte(template.Tasks), &specs); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+		}
+	}
+	for i := range specs {
+		spec := &specs[i]
+		spec.Name = fill(spec.Name, false)
+		spec.Description = fill(spec.Description, false)
+		spec.TargetURL = fill(spec.TargetURL, false)
+		spec.TargetAccount = fill(spec.TargetAccount, false)
+		spec.RequiredAction = fill(spec.RequiredAction, false)
+		spec.Config = fill(spec.Config, true)
+	}
+
+	name := req.Name
+	if name == "" {
+		name = fill(template.Name, false)
+	}
+	campaign := &models.Campaign{
+		ID:              uuid.New(),
+		UserID:          userID,
+		Name:            name,
+		Description:     fill(template.Description, false),
+		Type:            template.Type,
+		URL:             fill(template.URL, false),
+		ImageURL:        template.ImageURL,
+		Status:          "active",
+		EstimatedReward: template.EstimatedReward,
+		RewardType:      template.RewardType,
+		Metadata:        fill(template.Metadata, true),
+	}
+	if err := s.createWithTasks(userID, campaign, specs, req.WalletGroupIDs); err != nil {
+		return nil, err
+	}
+
+	s.container.DB.Model(template).Update("usage_count", gorm.Expr("usage_count + 1"))
+	return campaign, nil
+}
+
+// createWithTasks saves a new campaign with tasks built from specs and links the
+// user's wallet groups among groupIDs
+func (s *CampaignService) createWithTasks(userID uuid.UUID, campaign *models.Campaign, specs []models.TemplateTask, groupIDs []uuid.UUID) error {
+	tasks, err := fromTemplateTasks(specs, campaign.ID)
+	if err != nil {
+		return err
+	}
+	campaign.TotalTasks = len(tasks)
+	if campaign.Metadata == "" {
+		campaign.Metadata = "{}"
+	}
+
+	var groups []models.WalletGroup
+	if len(groupIDs) > 0 {
+		if err := s.container.DB.Where("id IN ? AND user_id = ?", groupIDs, userID).Find(&groups).Error; err != nil {
+			return err
+		}
+	}
+
+	err = s.container.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("WalletGroups", "Tasks").Create(campaign).Error; err != nil {
+			return err
+		}
+		if len(tasks) > 0 {
+			if err := tx.Omit("Executions").Create(&tasks).Error; err != nil {
+				return err
+			}
+		}
+		if len(groups) > 0 {
+			return tx.Model(campaign).Association("WalletGroups").Append(&groups)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	campaign.Tasks = tasks
+	campaign.WalletGroups = groups
+	s.broadcastCampaign(userID, campaign.ID, "campaign:created", campaign)
+	s.container.Dashboard.InvalidateStats(userID)
+	return nil
+}
+
+// toTemplateTasks converts tasks to specs keyed task-1, task-2, ... in task order.
+// Dependencies on tasks outside the set are dropped.
+func toTemplateTasks(tasks []models.CampaignTask) []models.TemplateTask {
+	sorted := append([]models.CampaignTask(nil), tasks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Order != sorted[j].Order {
+			return sorted[i].Order < sorted[j].Order
+		}
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	keys := make(map[uuid.UUID]string, len(sorted))
+	for i, task := range sorted {
+		keys[task.ID] = "task-" + strconv.Itoa(i+1)
+	}
+
+	specs := make([]models.TemplateTask, 0, len(sorted))
+	for _, task := range sorted {
+		spec := models.TemplateTask{
+			Key:              keys[task.ID],
+			Name:             task.Name,
+			Description:      task.Description,
+			Type:             task.Type,
+			TargetURL:        task.TargetURL,
+			TargetPlatform:   task.TargetPlatform,
+			TargetAccount:    task.TargetAccount,
+			RequiredAction:   task.RequiredAction,
+			Config:           task.Config,
+			IsAutomatable:    task.IsAutomatable,
+			AutomationScript: task.AutomationScript,
+			RequiresManual:   task.RequiresManual,
+			VerifyAfter:      task.VerifyAfter,
+			Order:            task.Order,
+			Points:           task.Points,
+		}
+		if task.DependsOn != nil {
+			spec.DependsOn = keys[*task.DependsOn]
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// fromTemplateTasks builds new tasks for a campaign, resolving dependency keys
+func fromTemplateTasks(specs []models.TemplateTask, campaignID uuid.UUID) ([]models.CampaignTask, error) {
+	ids := make(map[string]uuid.UUID, len(specs))
+	for _, spec := range specs {
+		if spec.Key == "" {
+			return nil, fmt.Errorf("%w: task %q has no key", ErrInvalidTemplate, spec.Name)
+		}
+		if _, dup := ids[spec.Key]; dup {
+			return nil, fmt.Errorf("%w: duplicate task key %q", ErrInvalidTemplate, spec.Key)
+		}
+		ids[spec.Key] = uuid.New()
+	}
+
+	tasks := make([]models.CampaignTask, 0, len(specs))
+	for _, spec := range specs {
+		if spec.Name == "" || spec.Type == "" {
+			return nil, fmt.Errorf("%w: task %q needs a name and type", ErrInvalidTemplate, spec.Key)
+		}
+		task := models.CampaignTask{
+			ID:               ids[spec.Key],
+			CampaignID:       campaignID,
+			Name:             spec.Name,
+			Description:      spec.Description,
+			Type:             spec.Type,
+			TargetURL:        spec.TargetURL,
+			TargetPlatform:   spec.TargetPlatform,
+			TargetAccount:    spec.TargetAccount,
+			RequiredAction:   spec.RequiredAction,
+			Config:           spec.Config,
+			IsAutomatable:    spec.IsAutomatable,
+			AutomationScript: spec.AutomationScript,
+			RequiresManual:   spec.RequiresManual,
+			VerifyAfter:      spec.VerifyAfter,
+			Order:            spec.Order,
+			Points:           spec.Points,
+		}
+		if task.Config == "" {
+			task.Config = "{}"
+		}
+		if spec.DependsOn != "" {
+			dependsOn, ok := ids[spec.DependsOn]
+			if !ok || spec.DependsOn == spec.Key {
+				return nil, fmt.Errorf("%w: task %q depends on unknown task %q", ErrInvalidTemplate, spec.Key, spec.DependsOn)
+			}
+			task.DependsOn = &dependsOn
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// templateParams lists the placeholder names used anywhere in a template
+func templateParams(template *models.CampaignTemplate) []string {
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, text := range []string{template.Name, template.Description, template.URL, template.Metadata, template.Tasks} {
+		for _, match := range templateParam.FindAllStringSubmatch(text, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				names = append(names, match[1])
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newParameterizer returns a function replacing param values with placeholders.
+// Longer values go first so one value inside another is not split.
+func newParameterizer(params map[string]string) func(text string, isJSON bool) string {
+	names := make([]string, 0, len(params))
+	for name, value := range params {
+		if value != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return len(params[names[i]]) > len(params[names[j]]) })
+
+	return func(text string, isJSON bool) string {
+		for _, name := range names {
+			value := params[name]
+			if isJSON {
+				value = jsonEscape(value)
+			}
+			text = strings.ReplaceAll(text, value, "{{"+name+"}}")
+		}
+		return text
+	}
+}
+
+// newFiller returns a function replacing placeholders with param values, escaped
+// when the text is JSON
+func newFiller(params map[string]string) func(text string, isJSON bool) string {
+	return func(text string, isJSON bool) string {
+		return templateParam.ReplaceAllStringFunc(text, func(placeholder string) string {
+			value, ok := params[templateParam.FindStringSubmatch(placeholder)[1]]
+			if !ok {
+				return placeholder
+			}
+			if isJSON {
+				return jsonEscape(value)
+			}
+			return value
+		})
+	}
+}
+
+// jsonEscape escapes a value for use inside a JSON string
+func jsonEscape(value string) string {
+	quoted, _ := json.Marshal(value)
+	return string(quoted[1 : len(quoted)-1])
+}
@@ -1,11 +1,20 @@
 package services
 
 import (
+	"log"
+
 	"github.com/go-redis/redis/v8"
 	"gorm.io/gorm"
 
+	"github.com/web3airdropos/backend/internal/balances"
+	"github.com/web3airdropos/backend/internal/circuit"
 	"github.com/web3airdropos/backend/internal/config"
+	"github.com/web3airdropos/backend/internal/explorer"
+	"github.com/web3airdropos/backend/internal/nonce"
+	"github.com/web3airdropos/backend/internal/rpc"
 	"github.com/web3airdropos/backend/internal/services/platforms"
+	"github.com/web3airdropos/backend/internal/storage"
+	"github.com/web3airdropos/backend/internal/webhooks"
 	"github.com/web3airdropos/backend/internal/websocket"
 )
 
@@ -16,6 +25,10 @@ type Container struct {
 	Redis  *redis.Client
 	WSHub  *websocket.Hub
 
+	// Reader serves heavy reads that tolerate replica lag: dashboard stats, audit
+	// queries and list endpoints. It is DB unless a read replica is configured.
+	Reader *gorm.DB
+
 	// Core Services
 	Auth      *AuthService
 	Wallet    *WalletService
@@ -27,35 +40,84 @@ type Container struct {
 	Job       *JobService
 	Proxy     *ProxyService
 	Dashboard *DashboardService
+	Search    *SearchService
+	Prices    *PriceService
+
+	// RPC endpoints: RPC resolves the endpoints to use for a chain
+	RPC          *rpc.Resolver
+	RPCEndpoints *RPCEndpointService
+
+	// Balances reads native balances per chain, shared with the balance sync job's code path
+	Balances *balances.Fetcher
+
+	// Explorer imports on-chain transaction history
+	Explorer *explorer.Client
+
+	// Storage holds proof screenshots on disk or in an S3-compatible bucket
+	Storage storage.Storage
+
+	// Webhooks: WebhookDispatcher delivers events to users' webhook URLs
+	Webhooks          *WebhookService
+	WebhookDispatcher *webhooks.Dispatcher
 
 	// Production Services
 	RateLimiter *RateLimiter
 	Audit       *AuditService
+
+	// Circuits fast-fails executions for platform accounts that keep failing; nil disables it
+	Circuits *circuit.Breaker
+
+	// Nonces allocates transaction nonces per wallet across concurrent sends
+	Nonces *nonce.Manager
 }
 
 func NewContainer(cfg *config.Config, db *gorm.DB, redis *redis.Client, wsHub *websocket.Hub) *Container {
 	container := &Container{
 		Config: cfg,
 		DB:     db,
+		Reader: db,
 		Redis:  redis,
 		WSHub:  wsHub,
 	}
 
 	// Initialize production services first (they have no dependencies)
 	container.RateLimiter = NewRateLimiter(redis)
+	container.RateLimiter.SetAlgorithm(cfg.RateLimitAlgorithm)
 	container.Audit = NewAuditService(db)
+	container.RPC = rpc.NewResolver(db, cfg)
+	container.Balances = balances.NewFetcher(container.RPC, cfg)
+	container.Explorer = explorer.NewClient(cfg)
+	store, err := storage.New(cfg)
+	if err != nil {
+		log.Printf("⚠️ Storage backend %q unavailable, keeping proofs on local disk: %v", cfg.StorageBackend, err)
+		store = storage.NewLocal(cfg.StoragePath, cfg.JWTSecret)
+	}
+	container.Storage = store
+	container.WebhookDispatcher = webhooks.NewDispatcher(db)
+	container.Circuits = circuit.New(circuit.Config{
+		Threshold: cfg.CircuitThreshold,
+		Window:    cfg.CircuitWindow,
+		Cooldown:  cfg.CircuitCooldown,
+	})
+	container.Nonces = nonce.New(redis, cfg.NonceReservationTTL)
 
 	// Initialize all services
 	container.Auth = NewAuthService(container)
 	container.Wallet = NewWalletService(container)
 	container.Account = NewAccountService(container)
+	container.RateLimiter.SetPlatformLimits(cfg.PlatformRateLimits)
+	container.RateLimiter.SetAccountLimitLookup(container.Account.rateLimitOverride)
 	container.Campaign = NewCampaignService(container)
 	container.Task = NewTaskService(container)
 	container.Browser = NewBrowserService(container)
 	container.Content = NewContentService(container)
 	container.Job = NewJobService(container)
 	container.Proxy = NewProxyService(container)
+	container.Prices = NewPriceService(container)
 	container.Dashboard = NewDashboardService(container)
+	container.Search = NewSearchService(container)
+	container.RPCEndpoints = NewRPCEndpointService(container)
+	container.Webhooks = NewWebhookService(container)
 
 	// Register platform adapters with Task service
 	container.registerPlatformAdapters(cfg)
@@ -63,12 +125,23 @@ func NewContainer(cfg *config.Config, db *gorm.DB, redis *redis.Client, wsHub *w
 	return container
 }
 
+// SetReadReplica routes read-heavy queries to replica. Writes, and reads that
+// must see them, stay on DB.
+func (c *Container) SetReadReplica(replica *gorm.DB) {
+	if replica == nil {
+		return
+	}
+	c.Reader = replica
+	c.Audit.SetReader(replica)
+}
+
 // registerPlatformAdapters sets up platform adapters based on configuration
 func (c *Container) registerPlatformAdapters(cfg *config.Config) {
 	// Farcaster (Neynar)
 	if cfg.NeynarAPIKey != "" {
 		farcasterAdapter, err := platforms.NewFarcasterClient(&platforms.AccountCredentials{
 			APIKey: cfg.NeynarAPIKey,
+			Extra:  map[string]string{"warpcast_api_key": cfg.WarpcastAPIKey},
 		})
 		if err == nil {
 			c.Task.RegisterAdapter("farcaster", farcasterAdapter)
@@ -97,4 +170,27 @@ func (c *Container) registerPlatformAdapters(cfg *config.Config) {
 			c.Task.RegisterAdapter("x", twitterAdapter)
 		}
 	}
+
+	// Lens
+	if cfg.LensAccessToken != "" {
+		lensAdapter, err := platforms.NewLensClient(&platforms.AccountCredentials{
+			AccessToken:  cfg.LensAccessToken,
+			RefreshToken: cfg.LensRefreshToken,
+			Extra: map[string]string{
+				"api_url":    cfg.LensAPIURL,
+				"profile_id": cfg.LensProfileID,
+			},
+		})
+		if err == nil {
+			c.Task.RegisterAdapter("lens", lensAdapter)
+		}
+	}
+}
+
+// Close releases connections held by the services
+func (c *Container) Close() {
+	c.RPC.Close()
+	c.Balances.Close()
+	c.WebhookDispatcher.Close()
+	c.Browser.Close()
 }
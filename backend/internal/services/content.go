@@ -2,25 +2,48 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/services/ai"
 	"github.com/web3airdropos/backend/internal/websocket"
 )
 
+var (
+	// ErrDraftFlagged is returned when scheduling a draft that failed moderation
+	ErrDraftFlagged = errors.New("draft was flagged by moderation and must be approved first")
+
+	ErrScheduledPostNotFound = errors.New("scheduled post not found")
+	ErrPostNotPending        = errors.New("scheduled post is no longer pending")
+	ErrScheduleInPast        = errors.New("scheduled time must be in the future")
+)
+
 type ContentService struct {
 	container *Container
+	ai        ai.Provider
+	moderator ai.Moderator
 }
 
 func NewContentService(c *Container) *ContentService {
-	return &ContentService{container: c}
+	return &ContentService{
+		container: c,
+		ai:        ai.NewFromConfig(c.Config),
+		moderator: ai.NewModeratorFromConfig(c.Config),
+	}
+}
+
+// SetAIProvider replaces the configured provider chain
+func (s *ContentService) SetAIProvider(provider ai.Provider) {
+	s.ai = provider
 }
 
 type GenerateContentRequest struct {
@@ -37,21 +60,7 @@ type GenerateContentRequest struct {
 	CampaignID  *uuid.UUID        `json:"campaign_id"`
 }
 
-type GeneratedContent struct {
-	Content         string   `json:"content"`
-	Tone            string   `json:"tone"`
-	Platform        string   `json:"platform"`
-	Hashtags        []string `json:"hashtags,omitempty"`
-	PredictedMetrics struct {
-		EngagementScore float64 `json:"engagement_score"`
-		ViralPotential  float64 `json:"viral_potential"`
-	} `json:"predicted_metrics"`
-}
-
-type AIServiceResponse struct {
-	Contents []GeneratedContent `json:"contents"`
-	Error    string             `json:"error,omitempty"`
-}
+type GeneratedContent = ai.GeneratedContent
 
 func (s *ContentService) Generate(userID uuid.UUID, req *GenerateContentRequest) ([]models.ContentDraft, error) {
 	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
@@ -64,53 +73,61 @@ func (s *ContentService) Generate(userID uuid.UUID, req *GenerateContentRequest)
 		req.NumOptions = 3
 	}
 
-	// Call AI microservice
-	aiReq := map[string]interface{}{
-		"platform":    req.Platform,
-		"type":        req.Type,
-		"prompt":      req.Prompt,
-		"tone":        req.Tone,
-		"context":     req.Context,
-		"reply_to":    req.ReplyTo,
-		"max_length":  req.MaxLength,
-		"num_options": req.NumOptions,
-		"keywords":    req.Keywords,
-		"hashtags":    req.Hashtags,
-	}
-
-	body, _ := json.Marshal(aiReq)
-	resp, err := http.Post(
-		s.container.Config.AIServiceURL+"/generate",
-		"application/json",
-		bytes.NewBuffer(body),
-	)
-
+	// Try each configured AI provider in order
+	contents, err := s.ai.Generate(context.Background(), &ai.Prompt{
+		Platform:   req.Platform,
+		Type:       req.Type,
+		Prompt:     req.Prompt,
+		Tone:       req.Tone,
+		Context:    req.Context,
+		ReplyTo:    req.ReplyTo,
+		MaxLength:  req.MaxLength,
+		NumOptions: req.NumOptions,
+		Keywords:   req.Keywords,
+		Hashtags:   req.Hashtags,
+	})
 	if err != nil {
 		s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
 			Level:   "error",
 			Source:  "ai",
-			Message: "Failed to connect to AI service: " + err.Error(),
+			Message: "Content generation failed: " + err.Error(),
 		})
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	respBody, _ := io.ReadAll(resp.Body)
-
-	var aiResp AIServiceResponse
-	if err := json.Unmarshal(respBody, &aiResp); err != nil {
-		return nil, err
-	}
-
-	if aiResp.Error != "" {
-		return nil, errors.New(aiResp.Error)
-	}
 
 	// Create drafts from generated content
 	var drafts []models.ContentDraft
-	for _, content := range aiResp.Contents {
+	dropped := 0
+	for _, content := range contents {
+		// Threads are saved as ordered parts plus the joined text for preview
+		var parts []string
+		if req.Type == "thread" {
+			parts = content.Parts
+			if len(parts) == 0 {
+				parts = ai.SplitThread(content.Content)
+			}
+			content.Content = strings.Join(parts, "\n\n")
+		}
+
+		status := "draft"
+		moderation, err := s.moderate(content.Content)
+		if err != nil {
+			return nil, err
+		}
+		if moderation != nil && moderation.Flagged {
+			if s.container.Config.ModerationAction == ai.ModerationActionDrop {
+				dropped++
+				continue
+			}
+			status = "flagged"
+		}
+
 		metricsJSON, _ := json.Marshal(content.PredictedMetrics)
-		
+		model := content.Model
+		if model == "" {
+			model = "gpt-4"
+		}
+
 		draft := models.ContentDraft{
 			ID:                  uuid.New(),
 			UserID:              userID,
@@ -118,18 +135,42 @@ func (s *ContentService) Generate(userID uuid.UUID, req *GenerateContentRequest)
 			Type:                req.Type,
 			Content:             content.Content,
 			Prompt:              req.Prompt,
-			AIModel:             "gpt-4",
+			AIModel:             model,
 			Tone:                content.Tone,
-			Status:              "draft",
+			Status:              status,
 			PredictedEngagement: string(metricsJSON),
 			CreatedAt:           time.Now(),
 			UpdatedAt:           time.Now(),
 		}
 
+		if moderation != nil {
+			moderationJSON, _ := json.Marshal(moderation)
+			draft.Moderation = string(moderationJSON)
+			draft.ModerationReason = moderation.Reason()
+		}
+		draft.ThreadParts = newThreadParts(parts)
+
+		// Creates the thread parts along with the draft
 		if err := s.container.DB.Create(&draft).Error; err != nil {
 			continue
 		}
 		drafts = append(drafts, draft)
+
+		if status == "flagged" {
+			s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+				Level:   "warn",
+				Source:  "ai",
+				Message: "Draft flagged by moderation: " + draft.ModerationReason,
+			})
+		}
+	}
+
+	if dropped > 0 {
+		s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+			Level:   "warn",
+			Source:  "ai",
+			Message: fmt.Sprintf("Dropped %d content options that failed moderation", dropped),
+		})
 	}
 
 	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
@@ -141,9 +182,40 @@ func (s *ContentService) Generate(userID uuid.UUID, req *GenerateContentRequest)
 	return drafts, nil
 }
 
+// newThreadParts builds ordered, unsaved parts from thread text
+func newThreadParts(parts []string) []models.ThreadPart {
+	if len(parts) == 0 {
+		return nil
+	}
+	threadParts := make([]models.ThreadPart, len(parts))
+	for i, content := range parts {
+		threadParts[i] = models.ThreadPart{
+			ID:       uuid.New(),
+			Position: i,
+			Content:  content,
+			Status:   "pending",
+		}
+	}
+	return threadParts
+}
+
+func orderedThreadParts(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+// moderate checks content with the configured moderator; nil means moderation is off
+func (s *ContentService) moderate(content string) (*ai.ModerationResult, error) {
+	if s.moderator == nil {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	return s.moderator.Check(ctx, content)
+}
+
 func (s *ContentService) ListDrafts(userID uuid.UUID, platform string, status string) ([]models.ContentDraft, error) {
 	var drafts []models.ContentDraft
-	query := s.container.DB.Where("user_id = ?", userID)
+	query := s.container.Reader.Where("user_id = ?", userID)
 
 	if platform != "" {
 		query = query.Where("platform = ?", platform)
@@ -152,7 +224,7 @@ func (s *ContentService) ListDrafts(userID uuid.UUID, platform string, status st
 		query = query.Where("status = ?", status)
 	}
 
-	if err := query.Order("created_at DESC").Find(&drafts).Error; err != nil {
+	if err := query.Preload("ThreadParts", orderedThreadParts).Order("created_at DESC").Find(&drafts).Error; err != nil {
 		return nil, err
 	}
 	return drafts, nil
@@ -160,7 +232,9 @@ func (s *ContentService) ListDrafts(userID uuid.UUID, platform string, status st
 
 func (s *ContentService) GetDraft(userID, draftID uuid.UUID) (*models.ContentDraft, error) {
 	var draft models.ContentDraft
-	if err := s.container.DB.Where("id = ? AND user_id = ?", draftID, userID).First(&draft).Error; err != nil {
+	if err := s.container.DB.Where("id = ? AND user_id = ?", draftID, userID).
+		Preload("ThreadParts", orderedThreadParts).
+		First(&draft).Error; err != nil {
 		return nil, err
 	}
 	return &draft, nil
@@ -178,9 +252,30 @@ func (s *ContentService) UpdateDraft(userID, draftID uuid.UUID, req *UpdateDraft
 		return nil, err
 	}
 
+	// Leaving the flagged state goes through ApproveDraft
+	if draft.Status == "flagged" && req.Status != "" && req.Status != "flagged" {
+		return nil, ErrDraftFlagged
+	}
+
 	updates := make(map[string]interface{})
 	if req.Content != "" {
 		updates["content"] = req.Content
+
+		// Edited content is moderated again
+		moderation, err := s.moderate(req.Content)
+		if err != nil {
+			return nil, err
+		}
+		if moderation != nil {
+			moderationJSON, _ := json.Marshal(moderation)
+			updates["moderation"] = string(moderationJSON)
+			updates["moderation_reason"] = moderation.Reason()
+			if moderation.Flagged {
+				req.Status = "flagged"
+			} else if draft.Status == "flagged" {
+				req.Status = "draft"
+			}
+		}
 	}
 	if req.Tone != "" {
 		updates["tone"] = req.Tone
@@ -210,7 +305,13 @@ func (s *ContentService) ApproveDraft(userID, draftID uuid.UUID) (*models.Conten
 		return nil, err
 	}
 
-	if err := s.container.DB.Model(draft).Update("status", "approved").Error; err != nil {
+	// Approving a flagged draft is the explicit override that makes it schedulable
+	now := time.Now()
+	if err := s.container.DB.Model(draft).Updates(map[string]interface{}{
+		"status":      "approved",
+		"approved_at": now,
+		"approved_by": userID,
+	}).Error; err != nil {
 		return nil, err
 	}
 
@@ -225,10 +326,12 @@ type SchedulePostRequest struct {
 	Platform    string     `json:"platform" binding:"required"`
 	ScheduledAt time.Time  `json:"scheduled_at" binding:"required"`
 	MediaURLs   []string   `json:"media_urls"`
+	ThreadParts []string   `json:"thread_parts"` // Publishes a thread; ignored when the draft is a thread
 }
 
 func (s *ContentService) Schedule(userID uuid.UUID, req *SchedulePostRequest) (*models.ScheduledPost, error) {
 	content := req.Content
+	parts := req.ThreadParts
 
 	// If draft ID provided, get content from draft
 	if req.DraftID != nil {
@@ -236,7 +339,21 @@ func (s *ContentService) Schedule(userID uuid.UUID, req *SchedulePostRequest) (*
 		if err != nil {
 			return nil, err
 		}
+		if draft.Status == "flagged" {
+			return nil, ErrDraftFlagged
+		}
 		content = draft.Content
+		if len(draft.ThreadParts) > 0 {
+			parts = make([]string, len(draft.ThreadParts))
+			for i, part := range draft.ThreadParts {
+				parts[i] = part.Content
+			}
+		}
+	}
+
+	// A thread's post content is its first part
+	if len(parts) > 0 {
+		content = parts[0]
 	}
 
 	if content == "" {
@@ -252,16 +369,19 @@ func (s *ContentService) Schedule(userID uuid.UUID, req *SchedulePostRequest) (*
 	mediaJSON, _ := json.Marshal(req.MediaURLs)
 
 	post := &models.ScheduledPost{
-		ID:          uuid.New(),
-		UserID:      userID,
-		AccountID:   req.AccountID,
-		Platform:    req.Platform,
-		Content:     content,
-		MediaURLs:   string(mediaJSON),
-		ScheduledAt: req.ScheduledAt,
-		Status:      "pending",
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:           uuid.New(),
+		UserID:       userID,
+		AccountID:    req.AccountID,
+		DraftID:      req.DraftID,
+		Platform:     req.Platform,
+		Content:      content,
+		MediaURLs:    string(mediaJSON),
+		ScheduledFor: req.ScheduledAt,
+		ScheduledAt:  req.ScheduledAt,
+		Status:       "pending",
+		ThreadParts:  newThreadParts(parts),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
 	}
 
 	if err := s.container.DB.Create(post).Error; err != nil {
@@ -280,7 +400,7 @@ func (s *ContentService) Schedule(userID uuid.UUID, req *SchedulePostRequest) (*
 
 func (s *ContentService) ListScheduled(userID uuid.UUID, platform string, status string) ([]models.ScheduledPost, error) {
 	var posts []models.ScheduledPost
-	query := s.container.DB.Where("user_id = ?", userID)
+	query := s.container.Reader.Where("user_id = ?", userID)
 
 	if platform != "" {
 		query = query.Where("platform = ?", platform)
@@ -289,23 +409,92 @@ func (s *ContentService) ListScheduled(userID uuid.UUID, platform string, status
 		query = query.Where("status = ?", status)
 	}
 
-	if err := query.Order("scheduled_at ASC").Find(&posts).Error; err != nil {
+	if err := query.Preload("ThreadParts", orderedThreadParts).Order("scheduled_at ASC").Find(&posts).Error; err != nil {
 		return nil, err
 	}
 	return posts, nil
 }
 
+// getPendingPost loads a user's scheduled post and checks it hasn't started publishing
+func (s *ContentService) getPendingPost(userID, postID uuid.UUID) (*models.ScheduledPost, error) {
+	var post models.ScheduledPost
+	if err := s.container.DB.Where("id = ? AND user_id = ?", postID, userID).First(&post).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrScheduledPostNotFound
+		}
+		return nil, err
+	}
+	if post.Status != "pending" {
+		return nil, fmt.Errorf("%w: status is %s", ErrPostNotPending, post.Status)
+	}
+	return &post, nil
+}
+
+// CancelScheduled cancels a post that is still pending. Posts already
+// processing, posted, failed or cancelled return ErrPostNotPending.
 func (s *ContentService) CancelScheduled(userID, postID uuid.UUID) error {
-	result := s.container.DB.Where("id = ? AND user_id = ? AND status = ?", postID, userID, "pending").
-		Update("status", "cancelled")
+	post, err := s.getPendingPost(userID, postID)
+	if err != nil {
+		return err
+	}
+
+	// The status condition guards against the scheduler picking the post up meanwhile
+	result := s.container.DB.Model(&models.ScheduledPost{}).
+		Where("id = ? AND user_id = ? AND status = ?", post.ID, userID, "pending").
+		Updates(map[string]interface{}{"status": "cancelled", "updated_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
 	if result.RowsAffected == 0 {
-		return errors.New("scheduled post not found or already processed")
+		return ErrPostNotPending
 	}
 
 	s.container.WSHub.BroadcastToUser(userID.String(), "post:cancelled", map[string]string{"id": postID.String()})
 	return nil
 }
 
+type ReschedulePostRequest struct {
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"`
+}
+
+// Reschedule moves a pending post to newTime, which must be in the future
+func (s *ContentService) Reschedule(userID, postID uuid.UUID, newTime time.Time) (*models.ScheduledPost, error) {
+	if !newTime.After(time.Now()) {
+		return nil, ErrScheduleInPast
+	}
+
+	post, err := s.getPendingPost(userID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.container.DB.Model(&models.ScheduledPost{}).
+		Where("id = ? AND user_id = ? AND status = ?", post.ID, userID, "pending").
+		Updates(map[string]interface{}{
+			"scheduled_for": newTime,
+			"scheduled_at":  newTime,
+			"updated_at":    time.Now(),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrPostNotPending
+	}
+
+	post.ScheduledFor = newTime
+	post.ScheduledAt = newTime
+
+	s.container.WSHub.BroadcastToUser(userID.String(), "post:rescheduled", post)
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:   "info",
+		Source:  "content",
+		Message: fmt.Sprintf("Post rescheduled for %s at %s", post.Platform, newTime.Format(time.RFC3339)),
+	})
+
+	return post, nil
+}
+
 // GenerateEngagementPlan generates a weekly engagement plan
 type EngagementPlanRequest struct {
 	AccountID     uuid.UUID   `json:"account_id" binding:"required"`
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+func TestCancelScheduled(t *testing.T) {
+	userID, postID := uuid.New(), uuid.New()
+	post := func(status string) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "user_id", "status"}).AddRow(postID, userID, status)
+	}
+
+	t.Run("already posted", func(t *testing.T) {
+		c, mock := mockContainer(t)
+		mock.ExpectQuery(`SELECT \* FROM "scheduled_posts"`).WillReturnRows(post("posted"))
+
+		err := NewContentService(c).CancelScheduled(userID, postID)
+		if !errors.Is(err, ErrPostNotPending) {
+			t.Fatalf("err = %v, want ErrPostNotPending", err)
+		}
+		// Nothing is written
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("published while cancelling", func(t *testing.T) {
+		c, mock := mockContainer(t)
+		mock.ExpectQuery(`SELECT \* FROM "scheduled_posts"`).WillReturnRows(post("pending"))
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "scheduled_posts" SET .* WHERE id = .* AND status = `).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		if err := NewContentService(c).CancelScheduled(userID, postID); !errors.Is(err, ErrPostNotPending) {
+			t.Fatalf("err = %v, want ErrPostNotPending", err)
+		}
+	})
+
+	t.Run("pending", func(t *testing.T) {
+		c, mock := mockContainer(t)
+		mock.ExpectQuery(`SELECT \* FROM "scheduled_posts"`).WillReturnRows(post("pending"))
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "scheduled_posts" SET .*"status"=`).
+			WithArgs("cancelled", sqlmock.AnyArg(), postID, userID, "pending").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		if err := NewContentService(c).CancelScheduled(userID, postID); err != nil {
+			t.Fatal(err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -1,11 +1,17 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/web3airdropos/backend/internal/circuit"
 	"github.com/web3airdropos/backend/internal/models"
 )
 
@@ -49,31 +55,175 @@ type DashboardStats struct {
 	PendingDrafts  int `json:"pending_drafts"`
 	ScheduledPosts int `json:"scheduled_posts"`
 
-	// Activity
-	TodayTransactions int   `json:"today_transactions"`
-	TodayPosts        int   `json:"today_posts"`
-	WeeklyActivity    []int `json:"weekly_activity"` // Last 7 days activity count
+	// Activity. Without a range these cover today and the last 7 days; with one,
+	// the counts cover the whole window and WeeklyActivity holds one entry per bucket.
+	TodayTransactions int         `json:"today_transactions"`
+	TodayPosts        int         `json:"today_posts"`
+	WeeklyActivity    []int       `json:"weekly_activity"`
+	Period            StatsPeriod `json:"period"`
 }
 
-func (s *DashboardService) GetStats(userID uuid.UUID) (*DashboardStats, error) {
+// StatsPeriod describes the window and bucket size used for activity stats
+type StatsPeriod struct {
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Bucket string    `json:"bucket"` // hour, day, week
+}
+
+// StatsRange is a custom activity window for GetStats
+type StatsRange struct {
+	From time.Time
+	To   time.Time
+
+	key string // Identifies the requested range in the stats cache
+}
+
+// MaxStatsRange bounds custom windows to keep the activity queries cheap
+const MaxStatsRange = 366 * 24 * time.Hour
+
+var ErrInvalidStatsRange = errors.New("invalid stats range")
+
+var statsRangePresets = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+	"90d": 90 * 24 * time.Hour,
+}
+
+// ParseStatsRange builds a window from either a preset ("24h", "7d", "30d", "90d")
+// or from/to timestamps (RFC3339 or YYYY-MM-DD, to defaults to now). It returns
+// nil when nothing is given so GetStats keeps its default windows.
+func ParseStatsRange(preset, from, to string) (*StatsRange, error) {
+	now := time.Now()
+
+	if preset != "" {
+		d, ok := statsRangePresets[strings.ToLower(preset)]
+		if !ok {
+			return nil, ErrInvalidStatsRange
+		}
+		return &StatsRange{From: now.Add(-d), To: now, key: strings.ToLower(preset)}, nil
+	}
+
+	if from == "" && to == "" {
+		return nil, nil
+	}
+	if from == "" {
+		return nil, ErrInvalidStatsRange
+	}
+
+	r := &StatsRange{To: now, key: from + "|" + to}
+	var err error
+	if r.From, err = parseStatsTime(from); err != nil {
+		return nil, ErrInvalidStatsRange
+	}
+	if to != "" {
+		if r.To, err = parseStatsTime(to); err != nil {
+			return nil, ErrInvalidStatsRange
+		}
+	}
+	if !r.To.After(r.From) || r.To.Sub(r.From) > MaxStatsRange {
+		return nil, ErrInvalidStatsRange
+	}
+	return r, nil
+}
+
+func parseStatsTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// statsBucket picks a bucket size so a window never yields more than ~53 buckets
+func statsBucket(window time.Duration) (string, time.Duration) {
+	switch {
+	case window <= 48*time.Hour:
+		return "hour", time.Hour
+	case window <= 31*24*time.Hour:
+		return "day", 24 * time.Hour
+	default:
+		return "week", 7 * 24 * time.Hour
+	}
+}
+
+func statsCacheKey(userID uuid.UUID, window *StatsRange) string {
+	variant := "default"
+	if window != nil {
+		variant = window.key
+	}
+	return "dashboard:stats:" + userID.String() + ":" + variant
+}
+
+// GetStats returns dashboard stats, served from Redis for DashboardCacheTTL.
+// refresh bypasses the cache. A nil window keeps the default "today" and
+// "last 7 days" activity windows.
+func (s *DashboardService) GetStats(userID uuid.UUID, window *StatsRange, refresh bool) (*DashboardStats, error) {
+	ttl := s.container.Config.DashboardCacheTTL
+	if ttl <= 0 {
+		return s.computeStats(userID, window)
+	}
+
+	ctx := context.Background()
+	cacheKey := statsCacheKey(userID, window)
+	if !refresh {
+		if cached, err := s.container.Redis.Get(ctx, cacheKey).Result(); err == nil {
+			var stats DashboardStats
+			if json.Unmarshal([]byte(cached), &stats) == nil {
+				return &stats, nil
+			}
+		}
+	}
+
+	stats, err := s.computeStats(userID, window)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(stats); err == nil {
+		s.container.Redis.Set(ctx, cacheKey, data, ttl)
+	}
+
+	return stats, nil
+}
+
+// InvalidateStats drops a user's cached stats after a change that affects them
+func (s *DashboardService) InvalidateStats(userID uuid.UUID) {
+	ctx := context.Background()
+	iter := s.container.Redis.Scan(ctx, 0, "dashboard:stats:"+userID.String()+":*", 100).Iterator()
+
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		log.Printf("Failed to scan dashboard cache for %s: %v", userID, err)
+		return
+	}
+	if len(keys) > 0 {
+		s.container.Redis.Del(ctx, keys...)
+	}
+}
+
+func (s *DashboardService) computeStats(userID uuid.UUID, window *StatsRange) (*DashboardStats, error) {
 	stats := &DashboardStats{}
 
 	// Wallet stats
 	var totalWallets, evmWallets, solanaWallets int64
-	s.container.DB.Model(&models.Wallet{}).Where("user_id = ?", userID).Count(&totalWallets)
-	s.container.DB.Model(&models.Wallet{}).Where("user_id = ? AND type = ?", userID, models.WalletTypeEVM).Count(&evmWallets)
-	s.container.DB.Model(&models.Wallet{}).Where("user_id = ? AND type = ?", userID, models.WalletTypeSolana).Count(&solanaWallets)
+	s.container.Reader.Model(&models.Wallet{}).Where("user_id = ?", userID).Count(&totalWallets)
+	s.container.Reader.Model(&models.Wallet{}).Where("user_id = ? AND type = ?", userID, models.WalletTypeEVM).Count(&evmWallets)
+	s.container.Reader.Model(&models.Wallet{}).Where("user_id = ? AND type = ?", userID, models.WalletTypeSolana).Count(&solanaWallets)
 	stats.TotalWallets = int(totalWallets)
 	stats.EVMWallets = int(evmWallets)
 	stats.SolanaWallets = int(solanaWallets)
+	stats.TotalBalanceUSD = s.totalBalanceUSD(userID)
 
 	// Account stats
 	var totalAccounts, farcasterAccounts, twitterAccounts, discordAccounts, telegramAccounts int64
-	s.container.DB.Model(&models.PlatformAccount{}).Where("user_id = ?", userID).Count(&totalAccounts)
-	s.container.DB.Model(&models.PlatformAccount{}).Where("user_id = ? AND platform = ?", userID, models.PlatformFarcaster).Count(&farcasterAccounts)
-	s.container.DB.Model(&models.PlatformAccount{}).Where("user_id = ? AND platform = ?", userID, models.PlatformTwitter).Count(&twitterAccounts)
-	s.container.DB.Model(&models.PlatformAccount{}).Where("user_id = ? AND platform = ?", userID, models.PlatformDiscord).Count(&discordAccounts)
-	s.container.DB.Model(&models.PlatformAccount{}).Where("user_id = ? AND platform = ?", userID, models.PlatformTelegram).Count(&telegramAccounts)
+	s.container.Reader.Model(&models.PlatformAccount{}).Where("user_id = ?", userID).Count(&totalAccounts)
+	s.container.Reader.Model(&models.PlatformAccount{}).Where("user_id = ? AND platform = ?", userID, models.PlatformFarcaster).Count(&farcasterAccounts)
+	s.container.Reader.Model(&models.PlatformAccount{}).Where("user_id = ? AND platform = ?", userID, models.PlatformTwitter).Count(&twitterAccounts)
+	s.container.Reader.Model(&models.PlatformAccount{}).Where("user_id = ? AND platform = ?", userID, models.PlatformDiscord).Count(&discordAccounts)
+	s.container.Reader.Model(&models.PlatformAccount{}).Where("user_id = ? AND platform = ?", userID, models.PlatformTelegram).Count(&telegramAccounts)
 	stats.TotalAccounts = int(totalAccounts)
 	stats.FarcasterAccounts = int(farcasterAccounts)
 	stats.TwitterAccounts = int(twitterAccounts)
@@ -82,83 +232,165 @@ func (s *DashboardService) GetStats(userID uuid.UUID) (*DashboardStats, error) {
 
 	// Campaign stats
 	var activeCampaigns, completedCampaigns int64
-	s.container.DB.Model(&models.Campaign{}).Where("user_id = ? AND status = ?", userID, "active").Count(&activeCampaigns)
-	s.container.DB.Model(&models.Campaign{}).Where("user_id = ? AND status = ?", userID, "completed").Count(&completedCampaigns)
+	s.container.Reader.Model(&models.Campaign{}).Where("user_id = ? AND status = ?", userID, "active").Count(&activeCampaigns)
+	s.container.Reader.Model(&models.Campaign{}).Where("user_id = ? AND status = ?", userID, "completed").Count(&completedCampaigns)
 	stats.ActiveCampaigns = int(activeCampaigns)
 	stats.CompletedCampaigns = int(completedCampaigns)
 
 	// Task stats from campaigns
 	var totalTasks, completedTasks, pendingTasks int64
-	s.container.DB.Model(&models.CampaignTask{}).
+	s.container.Reader.Model(&models.CampaignTask{}).
 		Joins("JOIN campaigns ON campaign_tasks.campaign_id = campaigns.id").
 		Where("campaigns.user_id = ?", userID).
 		Count(&totalTasks)
 	stats.TotalTasks = int(totalTasks)
 
-	s.container.DB.Model(&models.TaskExecution{}).
+	s.container.Reader.Model(&models.TaskExecution{}).
 		Joins("JOIN campaign_tasks ON task_executions.task_id = campaign_tasks.id").
 		Joins("JOIN campaigns ON campaign_tasks.campaign_id = campaigns.id").
-		Where("campaigns.user_id = ? AND task_executions.status = ?", userID, "completed").
+		Where("campaigns.user_id = ? AND task_executions.status IN ?", userID, models.ExecutionStatusValues(models.ExecutionCompleted)).
 		Count(&completedTasks)
 	stats.CompletedTasks = int(completedTasks)
 
-	s.container.DB.Model(&models.TaskExecution{}).
+	s.container.Reader.Model(&models.TaskExecution{}).
 		Joins("JOIN campaign_tasks ON task_executions.task_id = campaign_tasks.id").
 		Joins("JOIN campaigns ON campaign_tasks.campaign_id = campaigns.id").
-		Where("campaigns.user_id = ? AND task_executions.status IN ?", userID, []string{"pending", "in_progress", "waiting_manual"}).
+		Where("campaigns.user_id = ? AND task_executions.status IN ?", userID,
+			models.ExecutionStatusValues(models.ExecutionPending, models.ExecutionInProgress, models.ExecutionWaitingManual)).
 		Count(&pendingTasks)
 	stats.PendingTasks = int(pendingTasks)
 
 	// Job stats
 	var activeJobs, runningJobs int64
-	s.container.DB.Model(&models.AutomationJob{}).Where("user_id = ? AND is_active = ?", userID, true).Count(&activeJobs)
-	s.container.DB.Model(&models.AutomationJob{}).Where("user_id = ? AND status = ?", userID, "running").Count(&runningJobs)
+	s.container.Reader.Model(&models.AutomationJob{}).Where("user_id = ? AND is_active = ?", userID, true).Count(&activeJobs)
+	s.container.Reader.Model(&models.AutomationJob{}).Where("user_id = ? AND status = ?", userID, "running").Count(&runningJobs)
 	stats.ActiveJobs = int(activeJobs)
 	stats.RunningJobs = int(runningJobs)
 
 	// Browser sessions
 	var activeSessions int64
-	s.container.DB.Model(&models.BrowserSession{}).Where("user_id = ? AND status != ?", userID, "stopped").Count(&activeSessions)
+	s.container.Reader.Model(&models.BrowserSession{}).Where("user_id = ? AND status != ?", userID, "stopped").Count(&activeSessions)
 	stats.ActiveSessions = int(activeSessions)
 
 	// Content stats
 	var pendingDrafts, scheduledPosts int64
-	s.container.DB.Model(&models.ContentDraft{}).Where("user_id = ? AND status = ?", userID, "draft").Count(&pendingDrafts)
-	s.container.DB.Model(&models.ScheduledPost{}).Where("user_id = ? AND status = ?", userID, "pending").Count(&scheduledPosts)
+	s.container.Reader.Model(&models.ContentDraft{}).Where("user_id = ? AND status = ?", userID, "draft").Count(&pendingDrafts)
+	s.container.Reader.Model(&models.ScheduledPost{}).Where("user_id = ? AND status = ?", userID, "pending").Count(&scheduledPosts)
 	stats.PendingDrafts = int(pendingDrafts)
 	stats.ScheduledPosts = int(scheduledPosts)
 
-	// Today's activity
+	// Activity window
 	today := time.Now().Truncate(24 * time.Hour)
+	activityFrom, activityTo := today, today.Add(24*time.Hour)
+	bucketName, bucket := "day", 24*time.Hour
+	bucketsFrom := today.AddDate(0, 0, -6)
+	buckets := 7
+	if window != nil {
+		activityFrom, activityTo = window.From, window.To
+		bucketName, bucket = statsBucket(window.To.Sub(window.From))
+		bucketsFrom = window.From
+		buckets = int((window.To.Sub(window.From) + bucket - 1) / bucket)
+	}
+	stats.Period = StatsPeriod{From: bucketsFrom, To: activityTo, Bucket: bucketName}
+
 	var todayTransactions, todayPosts int64
-	s.container.DB.Model(&models.Transaction{}).
+	s.container.Reader.Model(&models.Transaction{}).
 		Joins("JOIN wallets ON transactions.wallet_id = wallets.id").
-		Where("wallets.user_id = ? AND transactions.created_at >= ?", userID, today).
+		Where("wallets.user_id = ? AND wallets.deleted_at IS NULL AND transactions.created_at >= ? AND transactions.created_at < ?", userID, activityFrom, activityTo).
 		Count(&todayTransactions)
 	stats.TodayTransactions = int(todayTransactions)
 
-	s.container.DB.Model(&models.AccountActivity{}).
+	s.container.Reader.Model(&models.AccountActivity{}).
 		Joins("JOIN platform_accounts ON account_activities.account_id = platform_accounts.id").
-		Where("platform_accounts.user_id = ? AND account_activities.created_at >= ? AND account_activities.type = ?", userID, today, "post").
+		Where("platform_accounts.user_id = ? AND platform_accounts.deleted_at IS NULL AND account_activities.created_at >= ? AND account_activities.created_at < ? AND account_activities.type = ?", userID, activityFrom, activityTo, "post").
 		Count(&todayPosts)
 	stats.TodayPosts = int(todayPosts)
 
-	// Weekly activity
-	stats.WeeklyActivity = make([]int, 7)
-	for i := 6; i >= 0; i-- {
-		dayStart := time.Now().AddDate(0, 0, -i).Truncate(24 * time.Hour)
-		dayEnd := dayStart.Add(24 * time.Hour)
+	// Activity per bucket, counted in one grouped query
+	var counts []struct {
+		Bucket int
+		Count  int64
+	}
+	s.container.Reader.Model(&models.AccountActivity{}).
+		Select("FLOOR(EXTRACT(EPOCH FROM (account_activities.created_at - ?)) / ?)::int AS bucket, COUNT(*) AS count", bucketsFrom, bucket.Seconds()).
+		Joins("JOIN platform_accounts ON account_activities.account_id = platform_accounts.id").
+		Where("platform_accounts.user_id = ? AND platform_accounts.deleted_at IS NULL AND account_activities.created_at >= ? AND account_activities.created_at < ?", userID, bucketsFrom, activityTo).
+		Group("bucket").
+		Scan(&counts)
+
+	stats.WeeklyActivity = make([]int, buckets)
+	for _, c := range counts {
+		if c.Bucket >= 0 && c.Bucket < buckets {
+			stats.WeeklyActivity[c.Bucket] = int(c.Count)
+		}
+	}
+
+	return stats, nil
+}
 
-		var count int64
-		s.container.DB.Model(&models.AccountActivity{}).
-			Joins("JOIN platform_accounts ON account_activities.account_id = platform_accounts.id").
-			Where("platform_accounts.user_id = ? AND account_activities.created_at >= ? AND account_activities.created_at < ?", userID, dayStart, dayEnd).
-			Count(&count)
+// totalBalanceUSD values native and cached token balances at cached USD prices.
+// It never calls an RPC or price API; assets without a cached price are skipped.
+func (s *DashboardService) totalBalanceUSD(userID uuid.UUID) float64 {
+	var wallets []models.Wallet
+	if err := s.container.Reader.Select("id, address, type, chain_id, balance").
+		Where("user_id = ?", userID).
+		Find(&wallets).Error; err != nil || len(wallets) == 0 {
+		return 0
+	}
 
-		stats.WeeklyActivity[6-i] = int(count)
+	type holding struct {
+		asset  PriceAsset
+		amount float64
 	}
+	var holdings []holding
+
+	ctx := context.Background()
+	for i := range wallets {
+		for j, chainID := range WalletChains(&wallets[i]) {
+			// Prices depend on the chain, so price each chain as its own wallet
+			wallet := wallets[i]
+			wallet.ChainID = int(chainID)
+
+			// The stored balance is the default chain's; other chains and token
+			// balances are only known from the balance cache
+			var native string
+			if j == 0 {
+				native = wallet.Balance
+			}
+			var cached models.WalletBalance
+			if raw, err := s.container.Redis.Get(ctx, BalanceCacheKey(wallet.Address, chainID)).Result(); err == nil &&
+				json.Unmarshal([]byte(raw), &cached) == nil {
+				if cached.NativeBalance != "" {
+					native = cached.NativeBalance
+				}
+				for _, token := range cached.Tokens {
+					if asset, ok := TokenAsset(&wallet, token.ContractAddress); ok {
+						holdings = append(holdings, holding{asset, toUnits(token.Balance, token.Decimals)})
+					}
+				}
+			}
 
-	return stats, nil
+			if asset, decimals, ok := NativeAsset(&wallet); ok && native != "" {
+				holdings = append(holdings, holding{asset, toUnits(native, decimals)})
+			}
+		}
+	}
+
+	assets := make([]PriceAsset, 0, len(holdings))
+	seen := make(map[PriceAsset]bool)
+	for _, h := range holdings {
+		if h.amount > 0 && !seen[h.asset] {
+			seen[h.asset] = true
+			assets = append(assets, h.asset)
+		}
+	}
+
+	prices := s.container.Prices.CachedUSDPrices(ctx, assets)
+	var total float64
+	for _, h := range holdings {
+		total += h.amount * prices[h.asset]
+	}
+	return total
 }
 
 type RecentActivity struct {
@@ -181,9 +413,9 @@ func (s *DashboardService) GetRecentActivity(userID uuid.UUID, limit int) ([]Rec
 
 	// Get recent account activities
 	var accountActivities []models.AccountActivity
-	s.container.DB.Model(&models.AccountActivity{}).
+	s.container.Reader.Model(&models.AccountActivity{}).
 		Joins("JOIN platform_accounts ON account_activities.account_id = platform_accounts.id").
-		Where("platform_accounts.user_id = ?", userID).
+		Where("platform_accounts.user_id = ? AND platform_accounts.deleted_at IS NULL", userID).
 		Order("account_activities.created_at DESC").
 		Limit(limit / 2).
 		Find(&accountActivities)
@@ -201,7 +433,7 @@ func (s *DashboardService) GetRecentActivity(userID uuid.UUID, limit int) ([]Rec
 
 	// Get recent task executions
 	var taskExecutions []models.TaskExecution
-	s.container.DB.Model(&models.TaskExecution{}).
+	s.container.Reader.Model(&models.TaskExecution{}).
 		Joins("JOIN campaign_tasks ON task_executions.task_id = campaign_tasks.id").
 		Joins("JOIN campaigns ON campaign_tasks.campaign_id = campaigns.id").
 		Where("campaigns.user_id = ?", userID).
@@ -257,7 +489,7 @@ type ActiveCampaignInfo struct {
 
 func (s *DashboardService) GetActiveCampaigns(userID uuid.UUID) ([]ActiveCampaignInfo, error) {
 	var campaigns []models.Campaign
-	if err := s.container.DB.Where("user_id = ? AND status = ?", userID, "active").
+	if err := s.container.Reader.Where("user_id = ? AND status = ?", userID, "active").
 		Preload("Tasks").
 		Order("priority DESC, deadline ASC").
 		Limit(10).
@@ -278,9 +510,9 @@ func (s *DashboardService) GetActiveCampaigns(userID uuid.UUID) ([]ActiveCampaig
 
 		// Count completed executions
 		var completed int64
-		s.container.DB.Model(&models.TaskExecution{}).
+		s.container.Reader.Model(&models.TaskExecution{}).
 			Joins("JOIN campaign_tasks ON task_executions.task_id = campaign_tasks.id").
-			Where("campaign_tasks.campaign_id = ? AND task_executions.status = ?", c.ID, "completed").
+			Where("campaign_tasks.campaign_id = ? AND task_executions.status IN ?", c.ID, models.ExecutionStatusValues(models.ExecutionCompleted)).
 			Count(&completed)
 
 		info.CompletedTasks = int(completed)
@@ -293,3 +525,54 @@ func (s *DashboardService) GetActiveCampaigns(userID uuid.UUID) ([]ActiveCampaig
 
 	return result, nil
 }
+
+// GetRateLimits reports each active account's rate limit usage so the dashboard
+// can show how much headroom is left
+func (s *DashboardService) GetRateLimits(ctx context.Context, userID uuid.UUID) ([]RateLimitUsage, error) {
+	var accounts []models.PlatformAccount
+	if err := s.container.Reader.Where("user_id = ? AND is_active = ?", userID, true).
+		Order("platform, username").
+		Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+
+	usage := make([]RateLimitUsage, 0, len(accounts))
+	for _, account := range accounts {
+		current, err := s.container.RateLimiter.Usage(ctx, string(account.Platform), account.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		usage = append(usage, *current)
+	}
+	return usage, nil
+}
+
+// AccountCircuit is the circuit breaker state of one platform account
+type AccountCircuit struct {
+	AccountID uuid.UUID `json:"account_id"`
+	Platform  string    `json:"platform"`
+	Username  string    `json:"username"`
+	circuit.Status
+}
+
+// GetCircuits reports the circuit state of each active account, so the dashboard
+// can show which accounts are failing fast
+func (s *DashboardService) GetCircuits(userID uuid.UUID) ([]AccountCircuit, error) {
+	var accounts []models.PlatformAccount
+	if err := s.container.Reader.Where("user_id = ? AND is_active = ?", userID, true).
+		Order("platform, username").
+		Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+
+	circuits := make([]AccountCircuit, 0, len(accounts))
+	for _, account := range accounts {
+		circuits = append(circuits, AccountCircuit{
+			AccountID: account.ID,
+			Platform:  string(account.Platform),
+			Username:  account.Username,
+			Status:    s.container.Circuits.Status(circuit.Key(string(account.Platform), account.ID.String())),
+		})
+	}
+	return circuits, nil
+}
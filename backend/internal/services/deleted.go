@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+var (
+	// ErrDeleteConfirmationRequired is returned when deleting a wallet with an
+	// imported key without repeating its address
+	ErrDeleteConfirmationRequired = errors.New("wallet holds an imported private key; confirm deletion with its address")
+
+	// ErrWalletDeleted is returned when importing a wallet the user deleted and can still restore
+	ErrWalletDeleted = errors.New("wallet was deleted; restore it instead")
+)
+
+// DeletedWallet is a soft-deleted wallet awaiting restore or purge
+type DeletedWallet struct {
+	models.Wallet
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// DeletedAccount is a soft-deleted account awaiting restore or purge
+type DeletedAccount struct {
+	models.PlatformAccount
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// ListDeleted returns the user's deleted wallets, most recently deleted first
+func (s *WalletService) ListDeleted(userID uuid.UUID) ([]DeletedWallet, error) {
+	var wallets []models.Wallet
+	if err := s.container.DB.Unscoped().
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&wallets).Error; err != nil {
+		return nil, err
+	}
+
+	deleted := make([]DeletedWallet, len(wallets))
+	for i, wallet := range wallets {
+		deleted[i] = DeletedWallet{
+			Wallet:    wallet,
+			DeletedAt: wallet.DeletedAt.Time,
+			PurgeAt:   wallet.DeletedAt.Time.Add(s.container.Config.SoftDeleteRetention),
+		}
+	}
+	return deleted, nil
+}
+
+// Restore brings back a deleted wallet that has not been purged yet
+func (s *WalletService) Restore(userID, walletID uuid.UUID) (*models.Wallet, error) {
+	var wallet models.Wallet
+	if err := s.container.DB.Unscoped().
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", walletID, userID).
+		First(&wallet).Error; err != nil {
+		return nil, err
+	}
+	deletedAt := wallet.DeletedAt.Time
+
+	if err := s.container.DB.Unscoped().Model(&wallet).Update("deleted_at", nil).Error; err != nil {
+		return nil, err
+	}
+	wallet.DeletedAt = gorm.DeletedAt{}
+
+	s.container.Audit.Log(context.Background(), &LogEntry{
+		UserID:      userID,
+		WalletID:    &wallet.ID,
+		Action:      models.ActionWalletRestore,
+		Platform:    string(wallet.Type),
+		TargetType:  "wallet",
+		TargetID:    wallet.Address,
+		RequestData: map[string]interface{}{"name": wallet.Name, "deleted_at": deletedAt},
+		Result:      models.ResultSuccess,
+	})
+	s.container.WSHub.BroadcastToUser(userID.String(), "wallet:restored", wallet)
+	s.container.Dashboard.InvalidateStats(userID)
+	return &wallet, nil
+}
+
+// ListDeleted returns the user's deleted accounts, most recently deleted first
+func (s *AccountService) ListDeleted(userID uuid.UUID) ([]DeletedAccount, error) {
+	var accounts []models.PlatformAccount
+	if err := s.container.DB.Unscoped().
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+
+	deleted := make([]DeletedAccount, len(accounts))
+	for i, account := range accounts {
+		deleted[i] = DeletedAccount{
+			PlatformAccount: account,
+			DeletedAt:       account.DeletedAt.Time,
+			PurgeAt:         account.DeletedAt.Time.Add(s.container.Config.SoftDeleteRetention),
+		}
+	}
+	return deleted, nil
+}
+
+// Restore brings back a deleted account that has not been purged yet
+func (s *AccountService) Restore(userID, accountID uuid.UUID) (*models.PlatformAccount, error) {
+	var account models.PlatformAccount
+	if err := s.container.DB.Unscoped().
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", accountID, userID).
+		First(&account).Error; err != nil {
+		return nil, err
+	}
+	deletedAt := account.DeletedAt.Time
+
+	if err := s.container.DB.Unscoped().Model(&account).Update("deleted_at", nil).Error; err != nil {
+		return nil, err
+	}
+	account.DeletedAt = gorm.DeletedAt{}
+
+	s.container.Audit.Log(context.Background(), &LogEntry{
+		UserID:      userID,
+		AccountID:   &account.ID,
+		Action:      models.ActionAccountRestore,
+		Platform:    string(account.Platform),
+		TargetType:  "account",
+		TargetID:    account.Username,
+		RequestData: map[string]interface{}{"deleted_at": deletedAt},
+		Result:      models.ResultSuccess,
+	})
+	s.container.WSHub.BroadcastToUser(userID.String(), "account:restored", account)
+	s.container.Dashboard.InvalidateStats(userID)
+	return &account, nil
+}
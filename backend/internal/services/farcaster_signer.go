@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/services/platforms"
+	"github.com/web3airdropos/backend/internal/vault"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+const (
+	// signerApprovalTimeout is how long we wait for the user to approve in Warpcast
+	signerApprovalTimeout = 30 * time.Minute
+	signerPollInterval    = 5 * time.Second
+)
+
+var ErrNotFarcasterAccount = errors.New("account is not a Farcaster account")
+
+// farcasterSignerSecret names the vault entry holding an account's Neynar signer UUID
+func farcasterSignerSecret(accountID uuid.UUID) string {
+	return "farcaster_signer:" + accountID.String()
+}
+
+// SetVault enables storing platform credentials in the secrets vault
+func (s *AccountService) SetVault(v *vault.Vault) {
+	s.vault = v
+}
+
+// RegisterFarcasterSigner starts onboarding a Farcaster account: it creates a
+// Neynar managed signer and returns the Warpcast approval link. Approval is
+// awaited in the background; the result is broadcast over WebSocket.
+func (s *AccountService) RegisterFarcasterSigner(userID, accountID uuid.UUID) (*platforms.SignerRegistration, error) {
+	account, err := s.Get(userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Platform != models.PlatformFarcaster {
+		return nil, ErrNotFarcasterAccount
+	}
+
+	client, err := s.farcasterSignerClient()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	signer, err := client.RegisterSigner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.container.WSHub.BroadcastToUser(userID.String(), "farcaster:signer_approval", map[string]interface{}{
+		"account_id":   accountID,
+		"signer_uuid":  signer.SignerUUID,
+		"approval_url": signer.ApprovalURL,
+		"status":       signer.Status,
+		"expires_at":   time.Now().Add(signerApprovalTimeout),
+	})
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:     "info",
+		Source:    "platform",
+		Message:   fmt.Sprintf("Approve the new signer for %s in Warpcast", account.Username),
+		AccountID: accountID.String(),
+	})
+
+	go s.awaitFarcasterSigner(userID, *account, client, signer.SignerUUID)
+	return signer, nil
+}
+
+func (s *AccountService) farcasterSignerClient() (*platforms.FarcasterClient, error) {
+	cfg := s.container.Config
+	if cfg.NeynarAPIKey == "" {
+		return nil, errors.New("NEYNAR_API_KEY not configured")
+	}
+	if cfg.FarcasterAppFID == 0 || cfg.FarcasterAppPrivateKey == "" {
+		return nil, platforms.ErrAppSignerNotConfigured
+	}
+
+	client, err := platforms.NewFarcasterClient(&platforms.AccountCredentials{APIKey: cfg.NeynarAPIKey})
+	if err != nil {
+		return nil, err
+	}
+	if err := client.SetAppSigner(cfg.FarcasterAppFID, cfg.FarcasterAppPrivateKey); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// awaitFarcasterSigner polls until the signer is approved, then saves it to the account
+func (s *AccountService) awaitFarcasterSigner(userID uuid.UUID, account models.PlatformAccount, client *platforms.FarcasterClient, signerUUID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), signerApprovalTimeout)
+	defer cancel()
+
+	signer, err := client.PollSignerStatus(ctx, signerUUID, signerPollInterval)
+	if err == nil {
+		err = s.saveFarcasterSigner(ctx, userID, &account, signer)
+	}
+
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = errors.New("signer was not approved in time")
+		}
+		log.Printf("Farcaster signer registration for account %s failed: %v", account.ID, err)
+		s.container.WSHub.BroadcastToUser(userID.String(), "farcaster:signer_failed", map[string]interface{}{
+			"account_id":  account.ID,
+			"signer_uuid": signerUUID,
+			"error":       err.Error(),
+		})
+		s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+			Level:     "error",
+			Source:    "platform",
+			Message:   fmt.Sprintf("Signer registration for %s failed: %v", account.Username, err),
+			AccountID: account.ID.String(),
+		})
+		return
+	}
+
+	s.container.WSHub.BroadcastToUser(userID.String(), "farcaster:signer_approved", map[string]interface{}{
+		"account_id":  account.ID,
+		"signer_uuid": signer.SignerUUID,
+		"fid":         signer.FID,
+	})
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:     "success",
+		Source:    "platform",
+		Message:   fmt.Sprintf("Signer approved for %s (FID %d)", account.Username, signer.FID),
+		AccountID: account.ID.String(),
+	})
+}
+
+// saveFarcasterSigner stores the approved signer in the vault and on the account,
+// where the Farcaster adapter reads it
+func (s *AccountService) saveFarcasterSigner(ctx context.Context, userID uuid.UUID, account *models.PlatformAccount, signer *platforms.SignerRegistration) error {
+	if s.vault != nil {
+		vctx := vault.WithSubsystem(ctx, "accounts")
+		name := farcasterSignerSecret(account.ID)
+		exists, err := s.vault.Exists(vctx, userID, name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			err = s.vault.Update(vctx, userID, name, signer.SignerUUID)
+		} else {
+			_, err = s.vault.Store(vctx, userID, name, signer.SignerUUID, vault.SecretTypeToken, map[string]interface{}{
+				"account_id": account.ID,
+				"fid":        signer.FID,
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to store signer in vault: %w", err)
+		}
+	}
+
+	return s.container.DB.Model(account).Updates(map[string]interface{}{
+		"access_token":     signer.SignerUUID,
+		"platform_user_id": strconv.FormatUint(signer.FID, 10),
+		"last_login_at":    time.Now(),
+	}).Error
+}
@@ -1,20 +1,38 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/robfig/cron/v3"
 	"gorm.io/gorm"
 
+	"github.com/web3airdropos/backend/internal/jobs"
 	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/queue"
+	"github.com/web3airdropos/backend/internal/requestid"
+	"github.com/web3airdropos/backend/internal/tracing"
 	"github.com/web3airdropos/backend/internal/websocket"
 )
 
+var (
+	ErrDeadLetterNotFound = errors.New("dead-letter entry not found")
+	ErrDeadLetterReplayed = errors.New("dead-letter entry was already replayed")
+	ErrReplayUnavailable  = errors.New("replay is unavailable: no worker is listening")
+	ErrInvalidJobTimeout  = errors.New("invalid job timeout")
+	ErrInvalidJobType     = errors.New("unknown job type")
+)
+
+// minJobTimeout is the shortest run timeout a job may set
+const minJobTimeout = 10 * time.Second
+
 type JobService struct {
 	container *Container
+	taskQueue *queue.Queue
 }
 
 func NewJobService(c *Container) *JobService {
@@ -31,19 +49,22 @@ type CreateJobRequest struct {
 	AccountIDs     []uuid.UUID    `json:"account_ids"`
 	CampaignID     *uuid.UUID     `json:"campaign_id"`
 	IsActive       bool           `json:"is_active"`
+	TimeoutSeconds int            `json:"timeout_seconds"` // 0 uses the configured default
 }
 
 type UpdateJobRequest struct {
-	Name           string      `json:"name"`
-	Description    string      `json:"description"`
-	CronExpression string      `json:"cron_expression"`
-	Config         interface{} `json:"config"`
-	IsActive       *bool       `json:"is_active"`
+	Type           models.JobType `json:"type"`
+	Name           string         `json:"name"`
+	Description    string         `json:"description"`
+	CronExpression string         `json:"cron_expression"`
+	Config         interface{}    `json:"config"`
+	IsActive       *bool          `json:"is_active"`
+	TimeoutSeconds *int           `json:"timeout_seconds"` // 0 restores the configured default
 }
 
 func (s *JobService) List(userID uuid.UUID, jobType string, status string) ([]models.AutomationJob, error) {
 	var jobs []models.AutomationJob
-	query := s.container.DB.Where("user_id = ?", userID)
+	query := s.container.Reader.Where("user_id = ?", userID)
 
 	if jobType != "" {
 		query = query.Where("type = ?", jobType)
@@ -71,6 +92,13 @@ func (s *JobService) Get(userID, jobID uuid.UUID) (*models.AutomationJob, error)
 }
 
 func (s *JobService) Create(userID uuid.UUID, req *CreateJobRequest) (*models.AutomationJob, error) {
+	if err := validateJobType(req.Type); err != nil {
+		return nil, err
+	}
+	if err := s.validateTimeout(req.TimeoutSeconds); err != nil {
+		return nil, err
+	}
+
 	configJSON, _ := json.Marshal(req.Config)
 	walletIDsJSON, _ := json.Marshal(req.WalletIDs)
 	accountIDsJSON, _ := json.Marshal(req.AccountIDs)
@@ -88,6 +116,7 @@ func (s *JobService) Create(userID uuid.UUID, req *CreateJobRequest) (*models.Au
 		CampaignID:     req.CampaignID,
 		IsActive:       req.IsActive,
 		Status:         "idle",
+		TimeoutSeconds: req.TimeoutSeconds,
 	}
 
 	// Calculate next run time if cron expression provided
@@ -116,6 +145,17 @@ func (s *JobService) Update(userID, jobID uuid.UUID, req *UpdateJobRequest) (*mo
 	}
 
 	updates := make(map[string]interface{})
+	if req.Type != "" {
+		if err := validateJobType(req.Type); err != nil {
+			return nil, err
+		}
+		updates["type"] = req.Type
+	} else if req.IsActive != nil && *req.IsActive {
+		// A job deactivated for its unknown type stays off until it gets a valid one
+		if err := validateJobType(job.Type); err != nil {
+			return nil, err
+		}
+	}
 	if req.Name != "" {
 		updates["name"] = req.Name
 	}
@@ -132,6 +172,12 @@ func (s *JobService) Update(userID, jobID uuid.UUID, req *UpdateJobRequest) (*mo
 	if req.IsActive != nil {
 		updates["is_active"] = *req.IsActive
 	}
+	if req.TimeoutSeconds != nil {
+		if err := s.validateTimeout(*req.TimeoutSeconds); err != nil {
+			return nil, err
+		}
+		updates["timeout_seconds"] = *req.TimeoutSeconds
+	}
 
 	if err := s.container.DB.Model(job).Updates(updates).Error; err != nil {
 		return nil, err
@@ -141,6 +187,27 @@ func (s *JobService) Update(userID, jobID uuid.UUID, req *UpdateJobRequest) (*mo
 	return job, nil
 }
 
+// validateJobType rejects job types the scheduler does not run
+func validateJobType(jobType models.JobType) error {
+	if !jobs.Runs(jobType) {
+		return fmt.Errorf("%w: %q", ErrInvalidJobType, jobType)
+	}
+	return nil
+}
+
+// validateTimeout checks a job's timeout_seconds against the allowed range
+func (s *JobService) validateTimeout(seconds int) error {
+	if seconds == 0 {
+		return nil
+	}
+	max := s.container.Config.JobMaxTimeout
+	if timeout := time.Duration(seconds) * time.Second; seconds < 0 || timeout < minJobTimeout || timeout > max {
+		return fmt.Errorf("%w: timeout_seconds must be between %d and %d, or 0 for the default",
+			ErrInvalidJobTimeout, int(minJobTimeout.Seconds()), int(max.Seconds()))
+	}
+	return nil
+}
+
 func (s *JobService) Delete(userID, jobID uuid.UUID) error {
 	result := s.container.DB.Where("id = ? AND user_id = ?", jobID, userID).Delete(&models.AutomationJob{})
 	if result.RowsAffected == 0 {
@@ -150,7 +217,7 @@ func (s *JobService) Delete(userID, jobID uuid.UUID) error {
 	return nil
 }
 
-func (s *JobService) Start(userID, jobID uuid.UUID) error {
+func (s *JobService) Start(ctx context.Context, userID, jobID uuid.UUID) error {
 	job, err := s.Get(userID, jobID)
 	if err != nil {
 		return err
@@ -174,9 +241,11 @@ func (s *JobService) Start(userID, jobID uuid.UUID) error {
 
 	// Enqueue job for execution via Redis queue
 	jobPayload, _ := json.Marshal(map[string]interface{}{
-		"job_id":  jobID.String(),
-		"user_id": userID.String(),
-		"type":    job.Type,
+		"job_id":      jobID.String(),
+		"user_id":     userID.String(),
+		"type":        job.Type,
+		"request_id":  requestid.FromContext(ctx),
+		"traceparent": tracing.Inject(ctx),
 	})
 	s.container.Redis.LPush(s.container.Redis.Context(), "job:queue", string(jobPayload))
 
@@ -210,10 +279,19 @@ func (s *JobService) Stop(userID, jobID uuid.UUID) error {
 	return nil
 }
 
-func (s *JobService) GetLogs(userID, jobID uuid.UUID, limit int, offset int, level string) ([]models.JobLog, int64, error) {
-	// Verify ownership
-	_, err := s.Get(userID, jobID)
-	if err != nil {
+// JobLogQuery filters a job's logs. Empty fields match everything.
+type JobLogQuery struct {
+	Levels []string   // Any of these levels
+	Since  *time.Time // Entries at or after this time
+	Limit  int        // Defaults to 100, capped at 1000
+	Offset int
+}
+
+// GetLogs returns a page of a job's logs, newest first, with the total matching the filters
+func (s *JobService) GetLogs(userID, jobID uuid.UUID, q JobLogQuery) ([]models.JobLog, int64, error) {
+	// Verify ownership without preloading logs
+	var job models.AutomationJob
+	if err := s.container.DB.Select("id").Where("id = ? AND user_id = ?", jobID, userID).First(&job).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -221,17 +299,25 @@ func (s *JobService) GetLogs(userID, jobID uuid.UUID, limit int, offset int, lev
 	var total int64
 
 	query := s.container.DB.Model(&models.JobLog{}).Where("job_id = ?", jobID)
-	if level != "" {
-		query = query.Where("level = ?", level)
+	if len(q.Levels) > 0 {
+		query = query.Where("level IN ?", q.Levels)
+	}
+	if q.Since != nil {
+		query = query.Where("created_at >= ?", *q.Since)
 	}
 
-	query.Count(&total)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
 
-	if limit == 0 {
-		limit = 100
+	if q.Limit <= 0 {
+		q.Limit = 100
+	}
+	if q.Limit > 1000 {
+		q.Limit = 1000
 	}
 
-	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&logs).Error; err != nil {
+	if err := query.Order("created_at DESC").Limit(q.Limit).Offset(q.Offset).Find(&logs).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -240,16 +326,133 @@ func (s *JobService) GetLogs(userID, jobID uuid.UUID, limit int, offset int, lev
 
 // AddLog adds a log entry for a job
 func (s *JobService) AddLog(jobID uuid.UUID, level, message string, details interface{}) error {
-	detailsJSON, _ := json.Marshal(details)
+	return s.container.DB.Create(models.NewJobLog(jobID, level, message, details)).Error
+}
 
-	log := &models.JobLog{
-		ID:        uuid.New(),
-		JobID:     jobID,
-		Level:     level,
-		Message:   message,
-		Details:   string(detailsJSON),
-		CreatedAt: time.Now(),
+// SetTaskQueue enables replaying dead-lettered task executions
+func (s *JobService) SetTaskQueue(q *queue.Queue) {
+	s.taskQueue = q
+}
+
+// ListDeadLetters returns the user's dead-lettered jobs and task executions, newest first
+func (s *JobService) ListDeadLetters(userID uuid.UUID, source, status string, limit, offset int) ([]models.DeadLetterJob, int64, error) {
+	query := s.container.Reader.Model(&models.DeadLetterJob{}).Where("user_id = ?", userID)
+	if source != "" {
+		query = query.Where("source = ?", source)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
 	}
 
-	return s.container.DB.Create(log).Error
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	var entries []models.DeadLetterJob
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// ReplayDeadLetter re-enqueues a dead-lettered job or task execution. An entry is
+// replayed once; if the replay fails again it is dead-lettered as a new entry.
+func (s *JobService) ReplayDeadLetter(ctx context.Context, userID, entryID uuid.UUID) (*models.DeadLetterJob, error) {
+	var entry models.DeadLetterJob
+	if err := s.container.DB.Where("id = ? AND user_id = ?", entryID, userID).First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDeadLetterNotFound
+		}
+		return nil, err
+	}
+	if entry.Status != "dead" {
+		return nil, ErrDeadLetterReplayed
+	}
+
+	var job *models.AutomationJob
+	switch entry.Source {
+	case models.DeadLetterSourceJob:
+		if entry.JobID == nil {
+			return nil, ErrDeadLetterNotFound
+		}
+		var err error
+		if job, err = s.Get(userID, *entry.JobID); err != nil {
+			return nil, fmt.Errorf("%w: job no longer exists", ErrDeadLetterNotFound)
+		}
+	case models.DeadLetterSourceTask:
+		if s.taskQueue == nil {
+			return nil, ErrReplayUnavailable
+		}
+	default:
+		return nil, fmt.Errorf("unknown dead-letter source %q", entry.Source)
+	}
+
+	// Claim the entry first so concurrent requests cannot replay it twice
+	now := time.Now()
+	result := s.container.DB.Model(&models.DeadLetterJob{}).
+		Where("id = ? AND status = ?", entry.ID, "dead").
+		Updates(map[string]interface{}{
+			"status":       "replayed",
+			"replay_count": gorm.Expr("replay_count + 1"),
+			"replayed_at":  now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrDeadLetterReplayed
+	}
+
+	if err := s.enqueueReplay(ctx, &entry, job); err != nil {
+		s.container.DB.Model(&models.DeadLetterJob{}).Where("id = ?", entry.ID).Updates(map[string]interface{}{
+			"status":       "dead",
+			"replay_count": gorm.Expr("replay_count - 1"),
+			"replayed_at":  nil,
+		})
+		return nil, err
+	}
+
+	entry.Status = "replayed"
+	entry.ReplayCount++
+	entry.ReplayedAt = &now
+
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:   "info",
+		Source:  "job",
+		Message: "Replaying dead-lettered " + entry.Source + ": " + entry.Name,
+	})
+	return &entry, nil
+}
+
+// enqueueReplay hands the entry back to the task queue or the scheduler. The request ID
+// carried by ctx goes with it.
+func (s *JobService) enqueueReplay(ctx context.Context, entry *models.DeadLetterJob, job *models.AutomationJob) error {
+	if entry.Source == models.DeadLetterSourceTask {
+		_, err := s.taskQueue.Enqueue(ctx, "task_replay", map[string]interface{}{
+			"dead_letter_id": entry.ID,
+		})
+		return err
+	}
+
+	s.container.DB.Model(job).Update("status", "idle")
+	payload, _ := json.Marshal(map[string]string{
+		"job_id":      job.ID.String(),
+		"user_id":     job.UserID.String(),
+		"request_id":  requestid.FromContext(ctx),
+		"traceparent": tracing.Inject(ctx),
+	})
+	receivers, err := s.container.Redis.Publish(ctx, "jobs:queue", string(payload)).Result()
+	if err != nil {
+		return err
+	}
+	if receivers == 0 {
+		// Nothing is listening, so the message would be lost
+		return ErrReplayUnavailable
+	}
+	return nil
 }
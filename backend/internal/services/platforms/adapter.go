@@ -3,6 +3,8 @@ package platforms
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -15,6 +17,7 @@ const (
 	PlatformTwitter   PlatformType = "twitter"
 	PlatformTelegram  PlatformType = "telegram"
 	PlatformDiscord   PlatformType = "discord"
+	PlatformLens      PlatformType = "lens"
 )
 
 // Common errors
@@ -29,6 +32,24 @@ var (
 	ErrAlreadyLiked       = errors.New("already liked this post")
 )
 
+// RateLimitError is returned when a platform rejects a request with 429.
+// It matches ErrRateLimited via errors.Is and carries how long to wait.
+type RateLimitError struct {
+	RetryAfter time.Duration
+	ResetAt    time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s (retry after %s)", ErrRateLimited.Error(), e.RetryAfter)
+	}
+	return ErrRateLimited.Error()
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
 // ActionProof contains proof of a completed action
 type ActionProof struct {
 	PostID       string            `json:"post_id,omitempty"`
@@ -90,6 +111,13 @@ type PlatformAdapter interface {
 	Quote(ctx context.Context, postID string, content *PostContent) (*ActionProof, error)
 	DeletePost(ctx context.Context, postID string) error
 	
+	// Direct messages (recipient is a chat ID on Telegram, an FID on Farcaster)
+	SendDirectMessage(ctx context.Context, recipient, content string) (*ActionProof, error)
+	
+	// Feed reading (cursor-paginated, returns the next cursor or "" on the last page)
+	GetUserCasts(ctx context.Context, fid string, cursor string, limit int) ([]NeynarCast, string, error)
+	GetChannelFeed(ctx context.Context, channelID string, cursor string, limit int) ([]NeynarCast, string, error)
+	
 	// Verification
 	VerifyAction(ctx context.Context, actionType string, proof *ActionProof) (bool, error)
 	
@@ -138,6 +166,8 @@ func (f *AdapterFactory) CreateAdapter(creds *AccountCredentials) (PlatformAdapt
 		return NewTelegramClient(creds)
 	case PlatformTwitter:
 		return NewTwitterClient(creds)
+	case PlatformLens:
+		return NewLensClient(creds)
 	case PlatformDiscord:
 		return nil, errors.New("discord adapter is notification-only, no user automation")
 	default:
@@ -3,6 +3,7 @@ package platforms
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
 	"crypto/ed25519"
 	"encoding/hex"
 	"encoding/json"
@@ -10,7 +11,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/web3airdropos/backend/internal/tracing"
 )
 
 // FarcasterClient implements PlatformAdapter for Farcaster (via Neynar/Hubble APIs)
@@ -22,6 +29,18 @@ type FarcasterClient struct {
 	hubbleURL     string
 	authenticated bool
 	signerKey     ed25519.PrivateKey
+
+	// Developer app that sponsors new signers (see RegisterSigner)
+	appFID uint64
+	appKey *ecdsa.PrivateKey
+
+	// Warpcast API key of the account, required for direct casts
+	warpcastAPIKey  string
+	warpcastBaseURL string
+
+	// Last rate limit headers seen from Neynar
+	rateMu        sync.RWMutex
+	lastRateLimit *RateLimitStatus
 }
 
 // Neynar API response structures
@@ -54,6 +73,14 @@ type NeynarCast struct {
 	} `json:"embeds"`
 }
 
+// NeynarFeedResponse is a page of casts from the Neynar feed endpoints
+type NeynarFeedResponse struct {
+	Casts []NeynarCast `json:"casts"`
+	Next  struct {
+		Cursor string `json:"cursor"`
+	} `json:"next"`
+}
+
 type NeynarPostResponse struct {
 	Success bool `json:"success"`
 	Cast    struct {
@@ -81,11 +108,14 @@ func NewFarcasterClient(creds *AccountCredentials) (*FarcasterClient, error) {
 
 	client := &FarcasterClient{
 		creds:         creds,
-		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		httpClient:    &http.Client{Timeout: 30 * time.Second, Transport: tracing.Transport(nil)},
 		neynarAPIKey:  creds.APIKey,
 		neynarBaseURL: "https://api.neynar.com/v2/farcaster",
 		hubbleURL:     "https://hub.farcaster.standardcrypto.vc:2281", // Public hub
 		authenticated: false,
+
+		warpcastAPIKey:  creds.Extra["warpcast_api_key"],
+		warpcastBaseURL: "https://api.warpcast.com/v2",
 	}
 
 	// Parse signer key if provided
@@ -142,7 +172,7 @@ func (c *FarcasterClient) GetProfile(ctx context.Context) (*UserProfile, error)
 	}
 	req.Header.Set("api_key", c.neynarAPIKey)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -173,7 +203,7 @@ func (c *FarcasterClient) GetProfile(ctx context.Context) (*UserProfile, error)
 }
 
 func (c *FarcasterClient) GetUserByUsername(ctx context.Context, username string) (*UserProfile, error) {
-	url := fmt.Sprintf("%s/user/by_username?username=%s", c.neynarBaseURL, username)
+	url := fmt.Sprintf("%s/user/by_username?username=%s", c.neynarBaseURL, url.QueryEscape(username))
 	
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -181,7 +211,7 @@ func (c *FarcasterClient) GetUserByUsername(ctx context.Context, username string
 	}
 	req.Header.Set("api_key", c.neynarAPIKey)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -228,7 +258,7 @@ func (c *FarcasterClient) Follow(ctx context.Context, targetFID string) (*Action
 	req.Header.Set("api_key", c.neynarAPIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -240,7 +270,7 @@ func (c *FarcasterClient) Follow(ctx context.Context, targetFID string) (*Action
 		return nil, ErrAlreadyFollowing
 	}
 	if resp.StatusCode == http.StatusTooManyRequests {
-		return nil, ErrRateLimited
+		return nil, rateLimitError(resp)
 	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("follow failed: %s", string(respBody))
@@ -275,7 +305,7 @@ func (c *FarcasterClient) Unfollow(ctx context.Context, targetFID string) (*Acti
 	req.Header.Set("api_key", c.neynarAPIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -314,7 +344,7 @@ func (c *FarcasterClient) Like(ctx context.Context, castHash string) (*ActionPro
 	req.Header.Set("api_key", c.neynarAPIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -326,7 +356,7 @@ func (c *FarcasterClient) Like(ctx context.Context, castHash string) (*ActionPro
 		return nil, ErrAlreadyLiked
 	}
 	if resp.StatusCode == http.StatusTooManyRequests {
-		return nil, ErrRateLimited
+		return nil, rateLimitError(resp)
 	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("like failed: %s", string(respBody))
@@ -363,7 +393,7 @@ func (c *FarcasterClient) Unlike(ctx context.Context, castHash string) (*ActionP
 	req.Header.Set("api_key", c.neynarAPIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -398,7 +428,7 @@ func (c *FarcasterClient) Repost(ctx context.Context, castHash string) (*ActionP
 	req.Header.Set("api_key", c.neynarAPIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -407,7 +437,7 @@ func (c *FarcasterClient) Repost(ctx context.Context, castHash string) (*ActionP
 	respBody, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode == http.StatusTooManyRequests {
-		return nil, ErrRateLimited
+		return nil, rateLimitError(resp)
 	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("recast failed: %s", string(respBody))
@@ -452,7 +482,7 @@ func (c *FarcasterClient) Post(ctx context.Context, content *PostContent) (*Acti
 	req.Header.Set("api_key", c.neynarAPIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -461,7 +491,7 @@ func (c *FarcasterClient) Post(ctx context.Context, content *PostContent) (*Acti
 	respBody, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode == http.StatusTooManyRequests {
-		return nil, ErrRateLimited
+		return nil, rateLimitError(resp)
 	}
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		return nil, fmt.Errorf("post failed: %s", string(respBody))
@@ -504,7 +534,7 @@ func (c *FarcasterClient) Reply(ctx context.Context, parentHash string, content
 	req.Header.Set("api_key", c.neynarAPIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -513,7 +543,7 @@ func (c *FarcasterClient) Reply(ctx context.Context, parentHash string, content
 	respBody, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode == http.StatusTooManyRequests {
-		return nil, ErrRateLimited
+		return nil, rateLimitError(resp)
 	}
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		return nil, fmt.Errorf("reply failed: %s", string(respBody))
@@ -566,7 +596,7 @@ func (c *FarcasterClient) DeletePost(ctx context.Context, castHash string) error
 	req.Header.Set("api_key", c.neynarAPIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -580,7 +610,105 @@ func (c *FarcasterClient) DeletePost(ctx context.Context, castHash string) error
 	return nil
 }
 
+// GetUserCasts returns a page of casts authored by the given FID
+func (c *FarcasterClient) GetUserCasts(ctx context.Context, fid string, cursor string, limit int) ([]NeynarCast, string, error) {
+	if fid == "" {
+		return nil, "", errors.New("fid required")
+	}
+
+	params := url.Values{}
+	params.Set("fid", fid)
+	return c.getFeed(ctx, "/feed/user/casts", params, cursor, limit)
+}
+
+// GetChannelFeed returns a page of casts from a channel (e.g. "dev")
+func (c *FarcasterClient) GetChannelFeed(ctx context.Context, channelID string, cursor string, limit int) ([]NeynarCast, string, error) {
+	if channelID == "" {
+		return nil, "", errors.New("channel ID required")
+	}
+
+	params := url.Values{}
+	params.Set("channel_ids", channelID)
+	params.Set("with_recasts", "false")
+	return c.getFeed(ctx, "/feed/channels", params, cursor, limit)
+}
+
+// SearchCasts returns a page of recent casts matching a search query
+func (c *FarcasterClient) SearchCasts(ctx context.Context, query string, cursor string, limit int) ([]NeynarCast, string, error) {
+	if query == "" {
+		return nil, "", errors.New("search query required")
+	}
+
+	params := url.Values{}
+	params.Set("q", query)
+	var result struct {
+		Result NeynarFeedResponse `json:"result"`
+	}
+	if err := c.getPage(ctx, "/cast/search", params, cursor, limit, &result); err != nil {
+		return nil, "", err
+	}
+	return result.Result.Casts, result.Result.Next.Cursor, nil
+}
+
+// getFeed fetches one page from a Neynar feed endpoint
+func (c *FarcasterClient) getFeed(ctx context.Context, path string, params url.Values, cursor string, limit int) ([]NeynarCast, string, error) {
+	var result NeynarFeedResponse
+	if err := c.getPage(ctx, path, params, cursor, limit, &result); err != nil {
+		return nil, "", err
+	}
+	return result.Casts, result.Next.Cursor, nil
+}
+
+// getPage fetches one page from a paginated Neynar endpoint and decodes it into out
+func (c *FarcasterClient) getPage(ctx context.Context, path string, params url.Values, cursor string, limit int, out interface{}) error {
+	// Neynar caps feed pages at 100
+	if limit <= 0 || limit > 100 {
+		limit = 25
+	}
+	params.Set("limit", strconv.Itoa(limit))
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	}
+	if c.creds.FID > 0 {
+		params.Set("viewer_fid", strconv.FormatUint(c.creds.FID, 10))
+	}
+
+	reqURL := fmt.Sprintf("%s%s?%s", c.neynarBaseURL, path, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("api_key", c.neynarAPIKey)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return rateLimitError(resp)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
 func (c *FarcasterClient) VerifyAction(ctx context.Context, actionType string, proof *ActionProof) (bool, error) {
+	switch actionType {
+	case "follow":
+		return c.verifyFollow(ctx, proof)
+	case "like", "recast":
+		return c.verifyReaction(ctx, actionType, proof)
+	case "direct_message":
+		// Direct casts can't be read back; the accepted send is the proof
+		return proof.PostID != "", nil
+	}
+
 	if proof.CastHash == "" {
 		return false, errors.New("no cast hash in proof")
 	}
@@ -594,7 +722,7 @@ func (c *FarcasterClient) VerifyAction(ctx context.Context, actionType string, p
 	}
 	req.Header.Set("api_key", c.neynarAPIKey)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return false, err
 	}
@@ -603,12 +731,238 @@ func (c *FarcasterClient) VerifyAction(ctx context.Context, actionType string, p
 	return resp.StatusCode == http.StatusOK, nil
 }
 
+// verifyFollow checks that our FID now follows the target FID
+func (c *FarcasterClient) verifyFollow(ctx context.Context, proof *ActionProof) (bool, error) {
+	targetFID, err := c.resolveFID(ctx, proof.Metadata["target_fid"])
+	if err != nil {
+		return false, err
+	}
+
+	url := fmt.Sprintf("%s/user/bulk?fids=%s&viewer_fid=%d", c.neynarBaseURL, targetFID, c.creds.FID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("api_key", c.neynarAPIKey)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return false, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Users []struct {
+			ViewerContext struct {
+				Following bool `json:"following"`
+			} `json:"viewer_context"`
+		} `json:"users"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+
+	return len(result.Users) > 0 && result.Users[0].ViewerContext.Following, nil
+}
+
+// resolveFID returns target as a FID. Tasks may name the account by username,
+// with or without a leading @, which is looked up.
+func (c *FarcasterClient) resolveFID(ctx context.Context, target string) (string, error) {
+	target = strings.TrimPrefix(target, "@")
+	if target == "" {
+		return "", errors.New("no target fid in proof")
+	}
+	if _, err := strconv.ParseUint(target, 10, 64); err == nil {
+		return target, nil
+	}
+	profile, err := c.GetUserByUsername(ctx, target)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve target user %s: %w", target, err)
+	}
+	return profile.ID, nil
+}
+
+// verifyReaction checks that our FID has liked or recasted the cast
+func (c *FarcasterClient) verifyReaction(ctx context.Context, reactionType string, proof *ActionProof) (bool, error) {
+	if proof.CastHash == "" {
+		return false, errors.New("no cast hash in proof")
+	}
+
+	url := fmt.Sprintf("%s/cast?identifier=%s&type=hash&viewer_fid=%d", c.neynarBaseURL, proof.CastHash, c.creds.FID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("api_key", c.neynarAPIKey)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, ErrPostNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return false, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Cast struct {
+			ViewerContext struct {
+				Liked    bool `json:"liked"`
+				Recasted bool `json:"recasted"`
+			} `json:"viewer_context"`
+		} `json:"cast"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+
+	if reactionType == "recast" {
+		return result.Cast.ViewerContext.Recasted, nil
+	}
+	return result.Cast.ViewerContext.Liked, nil
+}
+
 func (c *FarcasterClient) GetRateLimitStatus(ctx context.Context) (*RateLimitStatus, error) {
-	// Neynar doesn't expose rate limits directly in responses
-	// Return estimated limits based on their docs
-	return &RateLimitStatus{
-		Remaining: 100, // Conservative estimate
-		Limit:     300, // Per minute
-		ResetAt:   time.Now().Add(time.Minute).Unix(),
-	}, nil
+	c.rateMu.RLock()
+	last := c.lastRateLimit
+	c.rateMu.RUnlock()
+
+	if last == nil || (last.ResetAt > 0 && time.Now().Unix() >= last.ResetAt) {
+		// Nothing seen yet (or the window has passed) - fall back to Neynar's documented limits
+		return &RateLimitStatus{
+			Remaining: 100, // Conservative estimate
+			Limit:     300, // Per minute
+			ResetAt:   time.Now().Add(time.Minute).Unix(),
+		}, nil
+	}
+
+	status := *last
+	if status.RetryAfter > 0 && status.ResetAt > 0 {
+		// Report the time still left rather than the original value
+		status.RetryAfter = int(time.Until(time.Unix(status.ResetAt, 0)).Seconds())
+		if status.RetryAfter < 0 {
+			status.RetryAfter = 0
+		}
+	}
+	return &status, nil
+}
+
+// do executes a Neynar request and records the rate limit headers
+func (c *FarcasterClient) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	c.recordRateLimit(resp)
+	return resp, nil
+}
+
+// recordRateLimit stores the X-RateLimit-* / Retry-After headers from a response
+func (c *FarcasterClient) recordRateLimit(resp *http.Response) {
+	limit, hasLimit := headerInt(resp.Header, "X-RateLimit-Limit")
+	remaining, hasRemaining := headerInt(resp.Header, "X-RateLimit-Remaining")
+	resetAt := parseRateLimitReset(resp.Header)
+	retryAfter := parseRetryAfter(resp.Header)
+
+	if !hasLimit && !hasRemaining && resetAt.IsZero() && retryAfter == 0 {
+		return
+	}
+
+	status := &RateLimitStatus{
+		Limit:     limit,
+		Remaining: remaining,
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		status.Remaining = 0
+		if retryAfter == 0 && !resetAt.IsZero() {
+			retryAfter = time.Until(resetAt)
+		}
+		if retryAfter > 0 {
+			status.RetryAfter = int(retryAfter.Seconds())
+			if resetAt.IsZero() {
+				resetAt = time.Now().Add(retryAfter)
+			}
+		}
+	}
+	if !resetAt.IsZero() {
+		status.ResetAt = resetAt.Unix()
+	}
+
+	c.rateMu.Lock()
+	c.lastRateLimit = status
+	c.rateMu.Unlock()
+}
+
+// rateLimitError builds a RateLimitError from a 429 response
+func rateLimitError(resp *http.Response) error {
+	rlErr := &RateLimitError{
+		RetryAfter: parseRetryAfter(resp.Header),
+		ResetAt:    parseRateLimitReset(resp.Header),
+	}
+	if rlErr.RetryAfter == 0 && !rlErr.ResetAt.IsZero() {
+		if wait := time.Until(rlErr.ResetAt); wait > 0 {
+			rlErr.RetryAfter = wait
+		}
+	}
+	return rlErr
+}
+
+// parseRetryAfter reads Retry-After as either delay-seconds or an HTTP date
+func parseRetryAfter(h http.Header) time.Duration {
+	value := h.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(t); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// parseRateLimitReset reads X-RateLimit-Reset as a unix timestamp (seconds or milliseconds)
+func parseRateLimitReset(h http.Header) time.Time {
+	value := h.Get("X-RateLimit-Reset")
+	if value == "" {
+		return time.Time{}
+	}
+	reset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || reset <= 0 {
+		return time.Time{}
+	}
+	if reset > 1e12 {
+		return time.UnixMilli(reset)
+	}
+	return time.Unix(reset, 0)
+}
+
+func headerInt(h http.Header, key string) (int, bool) {
+	value := h.Get(key)
+	if value == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
 }
@@ -0,0 +1,54 @@
+package platforms
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVerifyFollowResolvesUsernames(t *testing.T) {
+	var lookedUp []string
+	neynar := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user/by_username":
+			lookedUp = append(lookedUp, r.URL.Query().Get("username"))
+			if r.URL.Query().Get("username") != "alice" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"user": map[string]interface{}{"fid": 42, "username": "alice"}})
+		case "/user/bulk":
+			following := r.URL.Query().Get("fids") == "42"
+			json.NewEncoder(w).Encode(map[string]interface{}{"users": []interface{}{
+				map[string]interface{}{"viewer_context": map[string]bool{"following": following}},
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer neynar.Close()
+
+	client, err := NewFarcasterClient(&AccountCredentials{APIKey: "key", FID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.neynarBaseURL = neynar.URL
+
+	for _, target := range []string{"42", "alice", "@alice"} {
+		ok, err := client.VerifyAction(context.Background(), "follow", &ActionProof{Metadata: map[string]string{"target_fid": target}})
+		if err != nil || !ok {
+			t.Errorf("target %q: verified = %v, %v; want true", target, ok, err)
+		}
+	}
+	if len(lookedUp) != 2 {
+		t.Errorf("looked up %v, want only the two usernames", lookedUp)
+	}
+
+	for _, target := range []string{"", "nobody"} {
+		if _, err := client.VerifyAction(context.Background(), "follow", &ActionProof{Metadata: map[string]string{"target_fid": target}}); err == nil {
+			t.Errorf("target %q: verified without error, want one", target)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package platforms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrWarpcastKeyRequired = errors.New("warpcast API key required for direct casts")
+
+type warpcastDirectCastResponse struct {
+	Result struct {
+		Success bool `json:"success"`
+	} `json:"result"`
+}
+
+// SendDirectMessage sends a Warpcast direct cast to the recipient FID. The API
+// returns no message ID, so the idempotency key is kept as the message reference.
+func (c *FarcasterClient) SendDirectMessage(ctx context.Context, recipient, content string) (*ActionProof, error) {
+	if c.warpcastAPIKey == "" {
+		return nil, ErrWarpcastKeyRequired
+	}
+	recipientFID, err := strconv.ParseUint(recipient, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient FID %q", recipient)
+	}
+
+	messageID := uuid.New().String()
+	body, _ := json.Marshal(map[string]interface{}{
+		"recipientFid":   recipientFID,
+		"message":        content,
+		"idempotencyKey": messageID,
+	})
+	req, err := http.NewRequestWithContext(ctx, "PUT", c.warpcastBaseURL+"/ext-send-direct-cast", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.warpcastAPIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, rateLimitError(resp)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("direct cast failed: %s", string(respBody))
+	}
+
+	var result warpcastDirectCastResponse
+	if err := json.Unmarshal(respBody, &result); err != nil || !result.Result.Success {
+		return nil, fmt.Errorf("direct cast failed: %s", string(respBody))
+	}
+
+	return &ActionProof{
+		PostID:      messageID,
+		Timestamp:   time.Now().Unix(),
+		RawResponse: string(respBody),
+		Metadata: map[string]string{
+			"recipient_fid": recipient,
+			"message_id":    messageID,
+			"action":        "direct_message",
+		},
+	}, nil
+}
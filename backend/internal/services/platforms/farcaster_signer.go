@@ -0,0 +1,230 @@
+package platforms
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Neynar managed signer statuses
+const (
+	SignerStatusGenerated       = "generated"
+	SignerStatusPendingApproval = "pending_approval"
+	SignerStatusApproved        = "approved"
+	SignerStatusRevoked         = "revoked"
+)
+
+// Farcaster's SignedKeyRequestValidator on Optimism, which checks the app's signature
+// over a new signer key
+const (
+	signedKeyRequestDomainName = "Farcaster SignedKeyRequestValidator"
+	signedKeyRequestChainID    = 10
+	signedKeyRequestValidator  = "0x00000000FC700472606ED4fA22623Acf62c60553"
+
+	signedKeyRequestTTL = 24 * time.Hour
+)
+
+var (
+	ErrAppSignerNotConfigured = errors.New("farcaster app FID and key are required to register signers")
+	ErrSignerRevoked          = errors.New("farcaster signer was revoked")
+)
+
+// SignerRegistration is the state of a Neynar managed signer
+type SignerRegistration struct {
+	SignerUUID  string `json:"signer_uuid"`
+	PublicKey   string `json:"public_key"`
+	Status      string `json:"status"`
+	ApprovalURL string `json:"signer_approval_url,omitempty"` // Warpcast deep link; render as a QR code on desktop
+	FID         uint64 `json:"fid,omitempty"`                 // Set once approved
+}
+
+// SetAppSigner sets the developer app that sponsors new signers. The key is the
+// custody key of the app's FID.
+func (c *FarcasterClient) SetAppSigner(appFID uint64, appKeyHex string) error {
+	key, err := crypto.HexToECDSA(trimHexPrefix(appKeyHex))
+	if err != nil {
+		return fmt.Errorf("invalid farcaster app key: %w", err)
+	}
+	c.appFID = appFID
+	c.appKey = key
+	return nil
+}
+
+// RegisterSigner creates a Neynar managed signer and requests approval for it.
+// The user approves by opening ApprovalURL in Warpcast; call PollSignerStatus
+// to wait for that.
+func (c *FarcasterClient) RegisterSigner(ctx context.Context) (*SignerRegistration, error) {
+	if c.appFID == 0 || c.appKey == nil {
+		return nil, ErrAppSignerNotConfigured
+	}
+
+	var signer SignerRegistration
+	if err := c.signerRequest(ctx, "POST", "/signer", nil, &signer); err != nil {
+		return nil, fmt.Errorf("failed to create signer: %w", err)
+	}
+
+	deadline := time.Now().Add(signedKeyRequestTTL).Unix()
+	signature, err := signKeyRequest(c.appKey, c.appFID, signer.PublicKey, deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	var registered SignerRegistration
+	if err := c.signerRequest(ctx, "POST", "/signer/signed_key", map[string]interface{}{
+		"signer_uuid": signer.SignerUUID,
+		"app_fid":     c.appFID,
+		"deadline":    deadline,
+		"signature":   signature,
+	}, &registered); err != nil {
+		return nil, fmt.Errorf("failed to register signed key: %w", err)
+	}
+
+	c.creds.AccessToken = registered.SignerUUID
+	return &registered, nil
+}
+
+// GetSignerStatus looks up a signer
+func (c *FarcasterClient) GetSignerStatus(ctx context.Context, signerUUID string) (*SignerRegistration, error) {
+	var signer SignerRegistration
+	path := "/signer?signer_uuid=" + url.QueryEscape(signerUUID)
+	if err := c.signerRequest(ctx, "GET", path, nil, &signer); err != nil {
+		return nil, err
+	}
+	return &signer, nil
+}
+
+// PollSignerStatus waits until the user approves the signer in Warpcast, then
+// adopts it as the client's signer. It stops when ctx is done or the signer is revoked.
+func (c *FarcasterClient) PollSignerStatus(ctx context.Context, signerUUID string, interval time.Duration) (*SignerRegistration, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		signer, err := c.GetSignerStatus(ctx, signerUUID)
+		if err != nil && ctx.Err() == nil {
+			// Transient lookup failures are retried until ctx gives up
+			var rlErr *RateLimitError
+			if !errors.As(err, &rlErr) {
+				return nil, err
+			}
+		}
+		if err == nil {
+			switch signer.Status {
+			case SignerStatusApproved:
+				c.creds.AccessToken = signer.SignerUUID
+				c.creds.FID = signer.FID
+				return signer, nil
+			case SignerStatusRevoked:
+				return signer, ErrSignerRevoked
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *FarcasterClient) signerRequest(ctx context.Context, method, path string, payload interface{}, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.neynarBaseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("api_key", c.neynarAPIKey)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return rateLimitError(resp)
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+	}
+	return json.Unmarshal(respBody, out)
+}
+
+// signKeyRequest produces the app's EIP-712 SignedKeyRequest signature over a signer public key
+func signKeyRequest(appKey *ecdsa.PrivateKey, appFID uint64, publicKey string, deadline int64) (string, error) {
+	key, err := hexutil.Decode(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid signer public key: %w", err)
+	}
+
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"SignedKeyRequest": {
+				{Name: "requestFid", Type: "uint256"},
+				{Name: "key", Type: "bytes"},
+				{Name: "deadline", Type: "uint256"},
+			},
+		},
+		PrimaryType: "SignedKeyRequest",
+		Domain: apitypes.TypedDataDomain{
+			Name:              signedKeyRequestDomainName,
+			Version:           "1",
+			ChainId:           math.NewHexOrDecimal256(signedKeyRequestChainID),
+			VerifyingContract: signedKeyRequestValidator,
+		},
+		Message: apitypes.TypedDataMessage{
+			"requestFid": new(big.Int).SetUint64(appFID),
+			"key":        hexutil.Bytes(key),
+			"deadline":   big.NewInt(deadline),
+		},
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash signed key request: %w", err)
+	}
+	sig, err := crypto.Sign(hash, appKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign key request: %w", err)
+	}
+	sig[64] += 27 // Ethereum-style recovery id
+	return hexutil.Encode(sig), nil
+}
+
+func trimHexPrefix(s string) string {
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		return s[2:]
+	}
+	return s
+}
@@ -0,0 +1,652 @@
+package platforms
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/tracing"
+)
+
+// LensClient implements PlatformAdapter for Lens Protocol via the Lens API.
+// Writes are relayed through the profile manager (dispatcher), so the profile
+// must have signless actions enabled. Credentials: AccessToken and RefreshToken
+// from Lens authentication, and Extra["profile_id"] for the acting profile.
+type LensClient struct {
+	creds         *AccountCredentials
+	httpClient    *http.Client
+	apiURL        string
+	accessToken   string
+	refreshToken  string
+	profileID     string
+	authenticated bool
+}
+
+// lensRelayResult is the RelayResult union returned by Lens write mutations
+type lensRelayResult struct {
+	Typename string `json:"__typename"`
+	TxHash   string `json:"txHash"`
+	TxID     string `json:"txId"`
+	Reason   string `json:"reason"`
+}
+
+const lensRelayFields = `__typename
+		... on RelaySuccess { txHash txId }
+		... on LensProfileManagerRelayError { reason }`
+
+// lensProfile is the subset of a Lens profile we read
+type lensProfile struct {
+	ID     string `json:"id"`
+	Handle *struct {
+		FullHandle string `json:"fullHandle"`
+		LocalName  string `json:"localName"`
+	} `json:"handle"`
+	Metadata *struct {
+		DisplayName string `json:"displayName"`
+		Bio         string `json:"bio"`
+		Picture     *struct {
+			Optimized *struct {
+				URI string `json:"uri"`
+			} `json:"optimized"`
+		} `json:"picture"`
+	} `json:"metadata"`
+	Stats struct {
+		Followers int `json:"followers"`
+		Following int `json:"following"`
+	} `json:"stats"`
+}
+
+const lensProfileFields = `id
+		handle { fullHandle localName }
+		metadata { displayName bio picture { ... on ImageSet { optimized { uri } } } }
+		stats { followers following }`
+
+func NewLensClient(creds *AccountCredentials) (*LensClient, error) {
+	if creds.AccessToken == "" {
+		return nil, errors.New("lens access token required")
+	}
+
+	apiURL := creds.Extra["api_url"]
+	if apiURL == "" {
+		apiURL = "https://api-v2.lens.dev"
+	}
+
+	return &LensClient{
+		creds:         creds,
+		httpClient:    &http.Client{Timeout: 30 * time.Second, Transport: tracing.Transport(nil)},
+		apiURL:        apiURL,
+		accessToken:   creds.AccessToken,
+		refreshToken:  creds.RefreshToken,
+		profileID:     creds.Extra["profile_id"],
+		authenticated: false,
+	}, nil
+}
+
+func (c *LensClient) GetPlatformType() PlatformType {
+	return PlatformLens
+}
+
+func (c *LensClient) Authenticate(ctx context.Context, credentials map[string]string) error {
+	if token := credentials["access_token"]; token != "" {
+		c.accessToken = token
+	}
+	if token := credentials["refresh_token"]; token != "" {
+		c.refreshToken = token
+	}
+	if id := credentials["profile_id"]; id != "" {
+		c.profileID = id
+	}
+	if c.profileID == "" {
+		return errors.New("lens profile ID required for authentication")
+	}
+
+	if _, err := c.GetProfile(ctx); err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+
+	c.authenticated = true
+	return nil
+}
+
+func (c *LensClient) IsAuthenticated() bool {
+	return c.authenticated && c.profileID != ""
+}
+
+// RefreshAuth exchanges the refresh token for a new token pair. Lens access
+// tokens last about 30 minutes.
+func (c *LensClient) RefreshAuth(ctx context.Context) error {
+	if c.refreshToken == "" {
+		return fmt.Errorf("%w: no lens refresh token", ErrAuthenticationFailed)
+	}
+
+	var result struct {
+		Refresh struct {
+			AccessToken  string `json:"accessToken"`
+			RefreshToken string `json:"refreshToken"`
+		} `json:"refresh"`
+	}
+	err := c.graphql(ctx, `mutation Refresh($token: Jwt!) {
+		refresh(request: { refreshToken: $token }) { accessToken refreshToken }
+	}`, map[string]interface{}{"token": c.refreshToken}, &result)
+	if err != nil {
+		return err
+	}
+
+	c.accessToken = result.Refresh.AccessToken
+	c.refreshToken = result.Refresh.RefreshToken
+	return nil
+}
+
+func (c *LensClient) GetProfile(ctx context.Context) (*UserProfile, error) {
+	if c.profileID == "" {
+		return nil, errors.New("lens profile ID required")
+	}
+	return c.getProfile(ctx, map[string]interface{}{"forProfileId": c.profileID})
+}
+
+// GetUserByUsername looks a profile up by handle, e.g. "stani" or "lens/stani"
+func (c *LensClient) GetUserByUsername(ctx context.Context, username string) (*UserProfile, error) {
+	return c.getProfile(ctx, map[string]interface{}{"forHandle": lensHandle(username)})
+}
+
+func (c *LensClient) getProfile(ctx context.Context, request map[string]interface{}) (*UserProfile, error) {
+	var result struct {
+		Profile *lensProfile `json:"profile"`
+	}
+	err := c.graphql(ctx, `query Profile($request: ProfileRequest!) {
+		profile(request: $request) { `+lensProfileFields+` }
+	}`, map[string]interface{}{"request": request}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.Profile == nil {
+		return nil, ErrUserNotFound
+	}
+
+	p := result.Profile
+	profile := &UserProfile{
+		ID:        p.ID,
+		Followers: p.Stats.Followers,
+		Following: p.Stats.Following,
+	}
+	if p.Handle != nil {
+		profile.Username = p.Handle.LocalName
+	}
+	if p.Metadata != nil {
+		profile.DisplayName = p.Metadata.DisplayName
+		profile.Bio = p.Metadata.Bio
+		if p.Metadata.Picture != nil && p.Metadata.Picture.Optimized != nil {
+			profile.AvatarURL = p.Metadata.Picture.Optimized.URI
+		}
+	}
+	return profile, nil
+}
+
+// Follow follows a profile, given by profile ID (0x...) or handle
+func (c *LensClient) Follow(ctx context.Context, targetUserID string) (*ActionProof, error) {
+	profileID, err := c.resolveProfileID(ctx, targetUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Follow lensRelayResult `json:"follow"`
+	}
+	err = c.graphql(ctx, `mutation Follow($id: ProfileId!) {
+		follow(request: { follow: [{ profileId: $id }] }) { `+lensRelayFields+` }
+	}`, map[string]interface{}{"id": profileID}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Follow.err(); err != nil {
+		return nil, err
+	}
+
+	return result.Follow.proof("follow", map[string]string{"target_profile_id": profileID}), nil
+}
+
+func (c *LensClient) Unfollow(ctx context.Context, targetUserID string) (*ActionProof, error) {
+	profileID, err := c.resolveProfileID(ctx, targetUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Unfollow lensRelayResult `json:"unfollow"`
+	}
+	err = c.graphql(ctx, `mutation Unfollow($id: ProfileId!) {
+		unfollow(request: { unfollow: [$id] }) { `+lensRelayFields+` }
+	}`, map[string]interface{}{"id": profileID}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Unfollow.err(); err != nil {
+		return nil, err
+	}
+
+	return result.Unfollow.proof("unfollow", map[string]string{"target_profile_id": profileID}), nil
+}
+
+// Like upvotes a publication. Reactions are off-chain, so there is no tx.
+func (c *LensClient) Like(ctx context.Context, postID string) (*ActionProof, error) {
+	if err := c.react(ctx, "addReaction", postID); err != nil {
+		return nil, err
+	}
+
+	return &ActionProof{
+		PostID:    postID,
+		PostURL:   lensPostURL(postID),
+		Timestamp: time.Now().Unix(),
+		Metadata: map[string]string{
+			"reaction_type": "upvote",
+		},
+	}, nil
+}
+
+func (c *LensClient) Unlike(ctx context.Context, postID string) (*ActionProof, error) {
+	if err := c.react(ctx, "removeReaction", postID); err != nil {
+		return nil, err
+	}
+
+	return &ActionProof{
+		PostID:    postID,
+		Timestamp: time.Now().Unix(),
+		Metadata: map[string]string{
+			"reaction_type": "upvote",
+			"action":        "removed",
+		},
+	}, nil
+}
+
+func (c *LensClient) react(ctx context.Context, mutation, postID string) error {
+	if postID == "" {
+		return errors.New("publication ID required")
+	}
+	return c.graphql(ctx, fmt.Sprintf(`mutation React($id: PublicationId!) {
+		%s(request: { for: $id, reaction: UPVOTE })
+	}`, mutation), map[string]interface{}{"id": postID}, nil)
+}
+
+// Repost mirrors a publication
+func (c *LensClient) Repost(ctx context.Context, postID string) (*ActionProof, error) {
+	if postID == "" {
+		return nil, errors.New("publication ID required")
+	}
+
+	var result struct {
+		Mirror lensRelayResult `json:"mirrorOnchain"`
+	}
+	err := c.graphql(ctx, `mutation Mirror($id: PublicationId!) {
+		mirrorOnchain(request: { mirrorOn: $id }) { `+lensRelayFields+` }
+	}`, map[string]interface{}{"id": postID}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Mirror.err(); err != nil {
+		return nil, err
+	}
+
+	proof := result.Mirror.proof("mirror", map[string]string{"mirror_of": postID})
+	proof.PostID = postID
+	proof.PostURL = lensPostURL(postID)
+	return proof, nil
+}
+
+// Collect collects a publication through its simple collect open action
+func (c *LensClient) Collect(ctx context.Context, postID string) (*ActionProof, error) {
+	if postID == "" {
+		return nil, errors.New("publication ID required")
+	}
+
+	var result struct {
+		Act lensRelayResult `json:"actOnOpenAction"`
+	}
+	err := c.graphql(ctx, `mutation Collect($id: PublicationId!) {
+		actOnOpenAction(request: { for: $id, actOn: { simpleCollectOpenAction: true } }) { `+lensRelayFields+` }
+	}`, map[string]interface{}{"id": postID}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Act.err(); err != nil {
+		return nil, err
+	}
+
+	proof := result.Act.proof("collect", nil)
+	proof.PostID = postID
+	proof.PostURL = lensPostURL(postID)
+	return proof, nil
+}
+
+func (c *LensClient) Post(ctx context.Context, content *PostContent) (*ActionProof, error) {
+	var result struct {
+		Post lensRelayResult `json:"postOnchain"`
+	}
+	err := c.graphql(ctx, `mutation Post($uri: URI!) {
+		postOnchain(request: { contentURI: $uri }) { `+lensRelayFields+` }
+	}`, map[string]interface{}{"uri": lensContentURI(content)}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Post.err(); err != nil {
+		return nil, err
+	}
+
+	return result.Post.proof("post", nil), nil
+}
+
+func (c *LensClient) Reply(ctx context.Context, postID string, content *PostContent) (*ActionProof, error) {
+	if postID == "" {
+		return nil, errors.New("publication ID required")
+	}
+
+	var result struct {
+		Comment lensRelayResult `json:"commentOnchain"`
+	}
+	err := c.graphql(ctx, `mutation Comment($id: PublicationId!, $uri: URI!) {
+		commentOnchain(request: { commentOn: $id, contentURI: $uri }) { `+lensRelayFields+` }
+	}`, map[string]interface{}{"id": postID, "uri": lensContentURI(content)}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Comment.err(); err != nil {
+		return nil, err
+	}
+
+	return result.Comment.proof("comment", map[string]string{"parent_id": postID}), nil
+}
+
+func (c *LensClient) Quote(ctx context.Context, postID string, content *PostContent) (*ActionProof, error) {
+	if postID == "" {
+		return nil, errors.New("publication ID required")
+	}
+
+	var result struct {
+		Quote lensRelayResult `json:"quoteOnchain"`
+	}
+	err := c.graphql(ctx, `mutation Quote($id: PublicationId!, $uri: URI!) {
+		quoteOnchain(request: { quoteOn: $id, contentURI: $uri }) { `+lensRelayFields+` }
+	}`, map[string]interface{}{"id": postID, "uri": lensContentURI(content)}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Quote.err(); err != nil {
+		return nil, err
+	}
+
+	return result.Quote.proof("quote", map[string]string{"quoted_id": postID}), nil
+}
+
+// DeletePost hides a publication; on-chain publications cannot be removed
+func (c *LensClient) DeletePost(ctx context.Context, postID string) error {
+	return c.graphql(ctx, `mutation Hide($id: PublicationId!) {
+		hidePublication(request: { for: $id })
+	}`, map[string]interface{}{"id": postID}, nil)
+}
+
+func (c *LensClient) SendDirectMessage(ctx context.Context, recipient, content string) (*ActionProof, error) {
+	// Lens messaging runs over XMTP, outside the Lens API
+	return nil, ErrNotImplemented
+}
+
+func (c *LensClient) GetUserCasts(ctx context.Context, fid string, cursor string, limit int) ([]NeynarCast, string, error) {
+	return nil, "", ErrNotImplemented
+}
+
+func (c *LensClient) GetChannelFeed(ctx context.Context, channelID string, cursor string, limit int) ([]NeynarCast, string, error) {
+	return nil, "", ErrNotImplemented
+}
+
+// VerifyAction checks the action against the Lens indexer: follows and upvotes
+// by reading the profile's state, relayed writes by their transaction status.
+func (c *LensClient) VerifyAction(ctx context.Context, actionType string, proof *ActionProof) (bool, error) {
+	switch actionType {
+	case "follow":
+		if id := proof.Metadata["target_profile_id"]; id != "" {
+			return c.verifyFollow(ctx, id)
+		}
+	case "like":
+		return c.verifyUpvote(ctx, proof.PostID)
+	}
+
+	txID := proof.Metadata["tx_id"]
+	if txID == "" {
+		return false, errors.New("no lens transaction ID in proof")
+	}
+
+	var result struct {
+		Status *struct {
+			Status string `json:"status"`
+			Reason string `json:"reason"`
+		} `json:"lensTransactionStatus"`
+	}
+	err := c.graphql(ctx, `query TxStatus($id: TxId!) {
+		lensTransactionStatus(request: { forTxId: $id }) { status reason }
+	}`, map[string]interface{}{"id": txID}, &result)
+	if err != nil {
+		return false, err
+	}
+	if result.Status == nil {
+		return false, nil
+	}
+
+	switch result.Status.Status {
+	case "COMPLETE", "OPTIMISTICALLY_UPDATED":
+		return true, nil
+	case "FAILED":
+		return false, fmt.Errorf("lens transaction failed: %s", result.Status.Reason)
+	}
+	return false, nil
+}
+
+func (c *LensClient) verifyFollow(ctx context.Context, profileID string) (bool, error) {
+	var result struct {
+		Profile *struct {
+			Operations struct {
+				IsFollowedByMe struct {
+					Value bool `json:"value"`
+				} `json:"isFollowedByMe"`
+			} `json:"operations"`
+		} `json:"profile"`
+	}
+	err := c.graphql(ctx, `query FollowStatus($id: ProfileId!) {
+		profile(request: { forProfileId: $id }) { operations { isFollowedByMe { value } } }
+	}`, map[string]interface{}{"id": profileID}, &result)
+	if err != nil {
+		return false, err
+	}
+	if result.Profile == nil {
+		return false, ErrUserNotFound
+	}
+	return result.Profile.Operations.IsFollowedByMe.Value, nil
+}
+
+func (c *LensClient) verifyUpvote(ctx context.Context, postID string) (bool, error) {
+	if postID == "" {
+		return false, errors.New("no publication ID in proof")
+	}
+
+	var result struct {
+		Publication *struct {
+			Operations struct {
+				HasUpvoted bool `json:"hasUpvoted"`
+			} `json:"operations"`
+		} `json:"publication"`
+	}
+	err := c.graphql(ctx, `query UpvoteStatus($id: PublicationId!) {
+		publication(request: { forId: $id }) {
+			... on Post { operations { hasUpvoted: hasReacted(request: { type: UPVOTE }) } }
+			... on Comment { operations { hasUpvoted: hasReacted(request: { type: UPVOTE }) } }
+			... on Quote { operations { hasUpvoted: hasReacted(request: { type: UPVOTE }) } }
+		}
+	}`, map[string]interface{}{"id": postID}, &result)
+	if err != nil {
+		return false, err
+	}
+	if result.Publication == nil {
+		return false, ErrPostNotFound
+	}
+	return result.Publication.Operations.HasUpvoted, nil
+}
+
+func (c *LensClient) GetRateLimitStatus(ctx context.Context) (*RateLimitStatus, error) {
+	// The Lens API does not expose rate limit headers; report a conservative
+	// hourly allowance for relayed actions
+	return &RateLimitStatus{
+		Remaining: 50,
+		Limit:     50,
+		ResetAt:   time.Now().Add(time.Hour).Unix(),
+	}, nil
+}
+
+// resolveProfileID accepts a profile ID as is and looks handles up
+func (c *LensClient) resolveProfileID(ctx context.Context, target string) (string, error) {
+	if strings.HasPrefix(target, "0x") {
+		return target, nil
+	}
+	profile, err := c.GetUserByUsername(ctx, target)
+	if err != nil {
+		return "", err
+	}
+	return profile.ID, nil
+}
+
+// graphql runs one Lens API operation and decodes its data into out (if not nil)
+func (c *LensClient) graphql(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	body, _ := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	req, err := http.NewRequestWithContext(ctx, "POST", c.apiURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	if origin := c.creds.Extra["origin"]; origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return rateLimitError(resp)
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("lens API error %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message    string `json:"message"`
+			Extensions struct {
+				Code string `json:"code"`
+			} `json:"extensions"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		return err
+	}
+	if len(envelope.Errors) > 0 {
+		return lensError(envelope.Errors[0].Extensions.Code, envelope.Errors[0].Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
+
+// lensError maps a Lens API error to the shared sentinels where one fits
+func lensError(code, message string) error {
+	lower := strings.ToLower(message)
+	switch {
+	case code == "UNAUTHENTICATED" || code == "FORBIDDEN":
+		return fmt.Errorf("%w: %s", ErrAuthenticationFailed, message)
+	case strings.Contains(lower, "already following"):
+		return ErrAlreadyFollowing
+	case strings.Contains(lower, "already reacted"):
+		return ErrAlreadyLiked
+	case strings.Contains(lower, "publication") && strings.Contains(lower, "not found"):
+		return ErrPostNotFound
+	case strings.Contains(lower, "profile") && strings.Contains(lower, "not found"):
+		return ErrUserNotFound
+	case strings.Contains(lower, "rate limit"):
+		return &RateLimitError{}
+	}
+	return fmt.Errorf("lens API error: %s", message)
+}
+
+// err reports a relay the profile manager refused
+func (r lensRelayResult) err() error {
+	if r.Typename != "LensProfileManagerRelayError" {
+		return nil
+	}
+	switch r.Reason {
+	case "RATE_LIMITED":
+		return &RateLimitError{}
+	case "REQUIRES_SIGNATURE", "APP_NOT_ALLOWED", "NOT_SPONSORED":
+		return fmt.Errorf("%w: lens relay refused (%s); enable the profile manager for this app", ErrAuthenticationFailed, r.Reason)
+	}
+	return fmt.Errorf("lens relay failed: %s", r.Reason)
+}
+
+func (r lensRelayResult) proof(action string, metadata map[string]string) *ActionProof {
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	metadata["action"] = action
+	if r.TxID != "" {
+		metadata["tx_id"] = r.TxID
+	}
+	return &ActionProof{
+		TxHash:    r.TxHash,
+		Timestamp: time.Now().Unix(),
+		Metadata:  metadata,
+	}
+}
+
+// lensContentURI builds text-only publication metadata (Lens metadata standard
+// 3.0) and inlines it as a data URI, which saves hosting it on Arweave/IPFS
+func lensContentURI(content *PostContent) string {
+	lens := map[string]interface{}{
+		"id":               uuid.New().String(),
+		"locale":           "en",
+		"mainContentFocus": "TEXT_ONLY",
+		"content":          content.Text,
+	}
+	if len(content.EmbedURLs) > 0 {
+		lens["content"] = content.Text + "\n\n" + strings.Join(content.EmbedURLs, "\n")
+	}
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"$schema": "https://json-schemas.lens.dev/publications/text-only/3.0.0.json",
+		"lens":    lens,
+	})
+	return "data:application/json;base64," + base64.StdEncoding.EncodeToString(metadata)
+}
+
+func lensPostURL(postID string) string {
+	return "https://hey.xyz/posts/" + postID
+}
+
+// lensHandle qualifies a bare handle with the lens/ namespace
+func lensHandle(handle string) string {
+	handle = strings.TrimPrefix(strings.TrimSuffix(handle, ".lens"), "@")
+	if !strings.Contains(handle, "/") {
+		handle = "lens/" + handle
+	}
+	return handle
+}
@@ -10,6 +10,8 @@ import (
 	"net/http"
 	"net/url"
 	"time"
+
+	"github.com/web3airdropos/backend/internal/tracing"
 )
 
 // TelegramClient implements PlatformAdapter for Telegram Bot API
@@ -61,7 +63,7 @@ func NewTelegramClient(creds *AccountCredentials) (*TelegramClient, error) {
 
 	return &TelegramClient{
 		creds:       creds,
-		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		httpClient:  &http.Client{Timeout: 30 * time.Second, Transport: tracing.Transport(nil)},
 		botToken:    creds.AccessToken,
 		baseURL:     "https://api.telegram.org",
 		authenticated: false,
@@ -362,6 +364,45 @@ func (c *TelegramClient) JoinChat(ctx context.Context, inviteLink string) (*Acti
 	}, nil
 }
 
+// SendDirectMessage sends a private message to a chat. The bot can only message
+// users who have started a conversation with it.
+func (c *TelegramClient) SendDirectMessage(ctx context.Context, recipient, content string) (*ActionProof, error) {
+	if recipient == "" {
+		return nil, errors.New("recipient chat_id required for Telegram")
+	}
+
+	resp, err := c.apiCall(ctx, "sendMessage", map[string]interface{}{
+		"chat_id": recipient,
+		"text":    content,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var msg TelegramMessage
+	if err := json.Unmarshal(resp.Result, &msg); err != nil {
+		return nil, err
+	}
+
+	return &ActionProof{
+		PostID:    fmt.Sprintf("%d", msg.MessageID),
+		Timestamp: time.Now().Unix(),
+		Metadata: map[string]string{
+			"chat_id":    recipient,
+			"message_id": fmt.Sprintf("%d", msg.MessageID),
+			"action":     "direct_message",
+		},
+	}, nil
+}
+
+func (c *TelegramClient) GetUserCasts(ctx context.Context, fid string, cursor string, limit int) ([]NeynarCast, string, error) {
+	return nil, "", ErrNotImplemented
+}
+
+func (c *TelegramClient) GetChannelFeed(ctx context.Context, channelID string, cursor string, limit int) ([]NeynarCast, string, error) {
+	return nil, "", ErrNotImplemented
+}
+
 func (c *TelegramClient) VerifyAction(ctx context.Context, actionType string, proof *ActionProof) (bool, error) {
 	// For messages, we can verify they still exist
 	if proof.PostID == "" {
@@ -94,6 +94,18 @@ func (c *TwitterClient) DeletePost(ctx context.Context, tweetID string) error {
 	return ErrNotImplemented
 }
 
+func (c *TwitterClient) SendDirectMessage(ctx context.Context, recipient, content string) (*ActionProof, error) {
+	return nil, ErrNotImplemented
+}
+
+func (c *TwitterClient) GetUserCasts(ctx context.Context, fid string, cursor string, limit int) ([]NeynarCast, string, error) {
+	return nil, "", ErrNotImplemented
+}
+
+func (c *TwitterClient) GetChannelFeed(ctx context.Context, channelID string, cursor string, limit int) ([]NeynarCast, string, error) {
+	return nil, "", ErrNotImplemented
+}
+
 func (c *TwitterClient) VerifyAction(ctx context.Context, actionType string, proof *ActionProof) (bool, error) {
 	return false, ErrNotImplemented
 }
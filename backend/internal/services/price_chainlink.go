@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/rpc"
+)
+
+// Chainlink Feed Registry on Ethereum mainnet, and the denomination addresses it
+// keys feeds by
+var (
+	chainlinkFeedRegistry = common.HexToAddress("0x47Fb2585D2C56Fe188D0E6ec628a38b74fCeeeDf")
+	chainlinkUSD          = common.HexToAddress("0x0000000000000000000000000000000000000348")
+	chainlinkETH          = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+)
+
+// Feed Registry function selectors
+var (
+	selectorLatestRoundData = []byte{0xbc, 0xfd, 0x03, 0x2d} // latestRoundData(address,address)
+	selectorFeedDecimals    = []byte{0x58, 0xe2, 0xd3, 0xa8} // decimals(address,address)
+)
+
+// chainlinkMaxAge is how old a feed answer may be before it is ignored
+const chainlinkMaxAge = 24 * time.Hour
+
+// chainlinkOracle reads USD prices from the Chainlink Feed Registry. It only
+// covers ETH and Ethereum mainnet tokens that have a USD feed.
+type chainlinkOracle struct {
+	rpc *rpc.Resolver
+}
+
+func (o *chainlinkOracle) Name() string { return "chainlink" }
+
+func (o *chainlinkOracle) Quote(ctx context.Context, assets []PriceAsset) (map[PriceAsset]float64, error) {
+	fetched := make(map[PriceAsset]float64)
+
+	var bases []PriceAsset
+	for _, asset := range assets {
+		if chainlinkBase(asset) != (common.Address{}) {
+			bases = append(bases, asset)
+		}
+	}
+	if len(bases) == 0 {
+		return fetched, nil
+	}
+
+	client, err := o.rpc.EVMClient(ctx, uuid.Nil, 1)
+	if err != nil {
+		return fetched, err
+	}
+
+	for _, asset := range bases {
+		price, err := o.latestPrice(ctx, client, chainlinkBase(asset))
+		if err != nil {
+			if isRevert(err) {
+				// No USD feed for this asset
+				continue
+			}
+			o.rpc.Discard(client)
+			return fetched, err
+		}
+		if price > 0 {
+			fetched[asset] = price
+		}
+	}
+	return fetched, nil
+}
+
+// latestPrice reads the latest USD answer for base. Stale answers return 0.
+func (o *chainlinkOracle) latestPrice(ctx context.Context, client *ethclient.Client, base common.Address) (float64, error) {
+	args := append(common.LeftPadBytes(base.Bytes(), 32), common.LeftPadBytes(chainlinkUSD.Bytes(), 32)...)
+
+	out, err := client.CallContract(ctx, ethereum.CallMsg{
+		To:   &chainlinkFeedRegistry,
+		Data: append(append([]byte{}, selectorLatestRoundData...), args...),
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+	// (roundId, answer, startedAt, updatedAt, answeredInRound)
+	if len(out) < 5*32 {
+		return 0, errors.New("feed registry returned a short round")
+	}
+	answer := new(big.Int).SetBytes(out[32:64])
+	if out[32]&0x80 != 0 {
+		// Negative int256 answers are not prices
+		return 0, nil
+	}
+	updatedAt := new(big.Int).SetBytes(out[96:128]).Int64()
+	if time.Since(time.Unix(updatedAt, 0)) > chainlinkMaxAge {
+		return 0, nil
+	}
+
+	out, err = client.CallContract(ctx, ethereum.CallMsg{
+		To:   &chainlinkFeedRegistry,
+		Data: append(append([]byte{}, selectorFeedDecimals...), args...),
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(out) < 32 {
+		return 0, errors.New("feed registry did not return decimals")
+	}
+	decimals := new(big.Int).SetBytes(out[:32]).Int64()
+
+	price, _ := new(big.Float).Quo(new(big.Float).SetInt(answer), big.NewFloat(math.Pow10(int(decimals)))).Float64()
+	return price, nil
+}
+
+// chainlinkBase is the registry base address of an asset, or the zero address
+// if the registry can't price it
+func chainlinkBase(asset PriceAsset) common.Address {
+	switch {
+	case asset.Contract == "" && asset.CoinID == "ethereum":
+		return chainlinkETH
+	case asset.Platform == "ethereum" && common.IsHexAddress(asset.Contract):
+		return common.HexToAddress(asset.Contract)
+	}
+	return common.Address{}
+}
+
+// isRevert reports whether a call failed because the contract reverted, rather
+// than because the node could not be reached
+func isRevert(err error) bool {
+	var dataErr interface{ ErrorData() interface{} }
+	return errors.As(err, &dataErr)
+}
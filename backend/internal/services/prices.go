@@ -0,0 +1,422 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/rpc"
+)
+
+// priceCacheMiss marks assets the price source has no quote for, so they are
+// skipped until the cache entry expires instead of being re-fetched every request
+const priceCacheMiss = "none"
+
+// Native coin IDs on the price source (CoinGecko) per EVM chain
+var nativeCoinIDs = map[int]string{
+	1:     "ethereum",
+	10:    "ethereum",
+	42161: "ethereum",
+	8453:  "ethereum",
+	56:    "binancecoin",
+	137:   "matic-network",
+	43114: "avalanche-2",
+}
+
+// Asset platform IDs on the price source, used for token contract prices
+var assetPlatforms = map[int]string{
+	1:     "ethereum",
+	10:    "optimistic-ethereum",
+	42161: "arbitrum-one",
+	8453:  "base",
+	56:    "binance-smart-chain",
+	137:   "polygon-pos",
+	43114: "avalanche",
+}
+
+const (
+	solanaCoinID   = "solana"
+	solanaDecimals = 9
+	evmDecimals    = 18
+)
+
+// PriceAsset identifies an asset on the price source. Native coins only set
+// CoinID, tokens set Platform and Contract.
+type PriceAsset struct {
+	CoinID   string
+	Platform string
+	Contract string
+}
+
+func (a PriceAsset) cacheKey() string {
+	if a.Contract != "" {
+		return "price:usd:" + a.Platform + ":" + a.Contract
+	}
+	return "price:usd:" + a.CoinID
+}
+
+// NativeAsset returns the native coin of a wallet's chain, or false if it isn't priced
+func NativeAsset(wallet *models.Wallet) (PriceAsset, int, bool) {
+	if wallet.Type == models.WalletTypeSolana {
+		return PriceAsset{CoinID: solanaCoinID}, solanaDecimals, true
+	}
+	coinID, ok := nativeCoinIDs[wallet.ChainID]
+	return PriceAsset{CoinID: coinID}, evmDecimals, ok
+}
+
+// TokenAsset returns the asset for a token contract on a wallet's chain
+func TokenAsset(wallet *models.Wallet, contract string) (PriceAsset, bool) {
+	platform := solanaCoinID
+	if wallet.Type != models.WalletTypeSolana {
+		var ok bool
+		if platform, ok = assetPlatforms[wallet.ChainID]; !ok {
+			return PriceAsset{}, false
+		}
+	}
+	return PriceAsset{Platform: platform, Contract: normalizeContract(platform, contract)}, contract != ""
+}
+
+// normalizeContract lowercases EVM addresses; Solana mints are case-sensitive
+func normalizeContract(platform, contract string) string {
+	if platform == solanaCoinID {
+		return contract
+	}
+	return strings.ToLower(contract)
+}
+
+// ErrPriceUnavailable is returned when no oracle has a USD price for an asset
+var ErrPriceUnavailable = errors.New("no USD price for asset")
+
+// PriceToken identifies a token by chain and contract. An empty Address is the
+// chain's native coin; Solana uses rpc.SolanaChainID.
+type PriceToken struct {
+	ChainID int64  `json:"chain_id"`
+	Address string `json:"address,omitempty"`
+}
+
+// AssetFor returns the asset for a token on a chain, or false if the chain isn't priced
+func AssetFor(token PriceToken) (PriceAsset, bool) {
+	if token.ChainID == rpc.SolanaChainID {
+		if token.Address == "" {
+			return PriceAsset{CoinID: solanaCoinID}, true
+		}
+		return PriceAsset{Platform: solanaCoinID, Contract: token.Address}, true
+	}
+	if token.Address == "" {
+		coinID, ok := nativeCoinIDs[int(token.ChainID)]
+		return PriceAsset{CoinID: coinID}, ok
+	}
+	platform, ok := assetPlatforms[int(token.ChainID)]
+	if !ok {
+		return PriceAsset{}, false
+	}
+	return PriceAsset{Platform: platform, Contract: normalizeContract(platform, token.Address)}, true
+}
+
+// PriceOracle quotes USD prices. Assets it has no price for are left out of the
+// result; an error means the oracle could not answer at all.
+type PriceOracle interface {
+	Name() string
+	Quote(ctx context.Context, assets []PriceAsset) (map[PriceAsset]float64, error)
+}
+
+// PriceService provides USD prices from the configured oracles, tried in order,
+// cached in Redis
+type PriceService struct {
+	container  *Container
+	oracles    []PriceOracle
+	refreshing sync.Mutex
+}
+
+func NewPriceService(c *Container) *PriceService {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+
+	var oracles []PriceOracle
+	for _, name := range c.Config.PriceOracles {
+		oracle, err := newPriceOracle(strings.ToLower(name), c, httpClient)
+		if err != nil {
+			log.Printf("⚠️  Skipping price oracle %q: %v", name, err)
+			continue
+		}
+		oracles = append(oracles, oracle)
+	}
+	return &PriceService{container: c, oracles: oracles}
+}
+
+func newPriceOracle(name string, c *Container, httpClient *http.Client) (PriceOracle, error) {
+	switch name {
+	case "coingecko":
+		return &coinGeckoOracle{
+			httpClient: httpClient,
+			baseURL:    strings.TrimRight(c.Config.PriceAPIURL, "/"),
+			apiKey:     c.Config.PriceAPIKey,
+			keyHeader:  c.Config.PriceAPIKeyHeader,
+		}, nil
+	case "chainlink":
+		return &chainlinkOracle{rpc: c.RPC}, nil
+	default:
+		return nil, errors.New("unknown price oracle")
+	}
+}
+
+// GetUSD returns the USD price of a token, or of the chain's native coin when
+// tokenAddress is empty. Unpriced tokens return ErrPriceUnavailable.
+func (s *PriceService) GetUSD(ctx context.Context, chainID int64, tokenAddress string) (float64, error) {
+	token := PriceToken{ChainID: chainID, Address: tokenAddress}
+	if price, ok := s.GetUSDBatch(ctx, []PriceToken{token})[token]; ok {
+		return price, nil
+	}
+	return 0, ErrPriceUnavailable
+}
+
+// GetUSDBatch prices a set of tokens, asking the oracles once for everything the
+// cache lacks. Tokens without a price are left out of the result.
+func (s *PriceService) GetUSDBatch(ctx context.Context, tokens []PriceToken) map[PriceToken]float64 {
+	assets := make([]PriceAsset, 0, len(tokens))
+	seen := make(map[PriceAsset]bool)
+	for _, token := range tokens {
+		if asset, ok := AssetFor(token); ok && !seen[asset] {
+			seen[asset] = true
+			assets = append(assets, asset)
+		}
+	}
+
+	prices, missing := s.cached(ctx, assets)
+	if len(missing) > 0 {
+		fetched, complete := s.quote(ctx, missing)
+		s.store(ctx, missing, fetched, complete)
+		for asset, price := range fetched {
+			prices[asset] = price
+		}
+	}
+
+	result := make(map[PriceToken]float64, len(tokens))
+	for _, token := range tokens {
+		asset, ok := AssetFor(token)
+		if !ok {
+			continue
+		}
+		if price, ok := prices[asset]; ok {
+			result[token] = price
+		}
+	}
+	return result
+}
+
+// CachedUSDPrices returns the cached USD price of each asset without calling the
+// price source. Assets missing from the cache are refreshed in the background
+// and left out of the result.
+func (s *PriceService) CachedUSDPrices(ctx context.Context, assets []PriceAsset) map[PriceAsset]float64 {
+	prices, missing := s.cached(ctx, assets)
+	if len(missing) > 0 {
+		go s.refresh(missing)
+	}
+	return prices
+}
+
+// cached reads assets from the cache, returning the prices found and the assets
+// with no cache entry. Assets cached as having no quote are in neither.
+func (s *PriceService) cached(ctx context.Context, assets []PriceAsset) (map[PriceAsset]float64, []PriceAsset) {
+	prices := make(map[PriceAsset]float64, len(assets))
+	if len(assets) == 0 {
+		return prices, nil
+	}
+
+	keys := make([]string, len(assets))
+	for i, asset := range assets {
+		keys[i] = asset.cacheKey()
+	}
+
+	values, err := s.container.Redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		return prices, assets
+	}
+
+	var missing []PriceAsset
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			missing = append(missing, assets[i])
+			continue
+		}
+		if raw == priceCacheMiss {
+			continue
+		}
+		if price, err := strconv.ParseFloat(raw, 64); err == nil {
+			prices[assets[i]] = price
+		}
+	}
+	return prices, missing
+}
+
+// refresh fetches prices for assets and caches them. Only one refresh runs at
+// a time; concurrent requests are dropped and picked up on a later read.
+func (s *PriceService) refresh(assets []PriceAsset) {
+	if !s.refreshing.TryLock() {
+		return
+	}
+	defer s.refreshing.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	fetched, complete := s.quote(ctx, assets)
+	s.store(ctx, assets, fetched, complete)
+}
+
+// quote asks each oracle in turn for the assets the previous ones had no price
+// for. complete is false if an oracle failed, so a missing price may not mean
+// the asset is unknown.
+func (s *PriceService) quote(ctx context.Context, assets []PriceAsset) (map[PriceAsset]float64, bool) {
+	fetched := make(map[PriceAsset]float64)
+	complete := true
+	remaining := assets
+
+	for _, oracle := range s.oracles {
+		if len(remaining) == 0 {
+			break
+		}
+		quotes, err := oracle.Quote(ctx, remaining)
+		if err != nil {
+			log.Printf("Price refresh from %s failed: %v", oracle.Name(), err)
+			complete = false
+		}
+		var next []PriceAsset
+		for _, asset := range remaining {
+			if price, ok := quotes[asset]; ok {
+				fetched[asset] = price
+			} else {
+				next = append(next, asset)
+			}
+		}
+		remaining = next
+	}
+	return fetched, complete
+}
+
+// store caches fetched prices, and marks the assets no oracle knows when every
+// oracle answered
+func (s *PriceService) store(ctx context.Context, assets []PriceAsset, fetched map[PriceAsset]float64, complete bool) {
+	ttl := s.container.Config.PriceCacheTTL
+	pipe := s.container.Redis.Pipeline()
+	for _, asset := range assets {
+		if price, ok := fetched[asset]; ok {
+			pipe.Set(ctx, asset.cacheKey(), strconv.FormatFloat(price, 'f', -1, 64), ttl)
+		} else if complete {
+			// The oracles answered but have no quote for this asset
+			pipe.Set(ctx, asset.cacheKey(), priceCacheMiss, ttl)
+		}
+	}
+	pipe.Exec(ctx)
+}
+
+// coinGeckoOracle quotes prices from a CoinGecko-compatible simple price API
+type coinGeckoOracle struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	keyHeader  string
+}
+
+func (o *coinGeckoOracle) Name() string { return "coingecko" }
+
+func (o *coinGeckoOracle) Quote(ctx context.Context, assets []PriceAsset) (map[PriceAsset]float64, error) {
+	var coinIDs []string
+	tokens := make(map[string][]string) // platform -> contracts
+	for _, asset := range assets {
+		if asset.Contract != "" {
+			tokens[asset.Platform] = append(tokens[asset.Platform], asset.Contract)
+		} else {
+			coinIDs = append(coinIDs, asset.CoinID)
+		}
+	}
+
+	fetched := make(map[PriceAsset]float64)
+	var lastErr error
+
+	if len(coinIDs) > 0 {
+		quotes, err := o.fetch(ctx, "/simple/price", url.Values{"ids": {strings.Join(coinIDs, ",")}})
+		if err != nil {
+			lastErr = err
+		}
+		for id, price := range quotes {
+			fetched[PriceAsset{CoinID: id}] = price
+		}
+	}
+
+	for platform, contracts := range tokens {
+		quotes, err := o.fetch(ctx, "/simple/token_price/"+platform, url.Values{"contract_addresses": {strings.Join(contracts, ",")}})
+		if err != nil {
+			lastErr = fmt.Errorf("token prices on %s: %w", platform, err)
+			continue
+		}
+		for contract, price := range quotes {
+			fetched[PriceAsset{Platform: platform, Contract: normalizeContract(platform, contract)}] = price
+		}
+	}
+
+	return fetched, lastErr
+}
+
+// fetch calls a CoinGecko-style simple price endpoint and returns id -> USD price
+func (o *coinGeckoOracle) fetch(ctx context.Context, path string, params url.Values) (map[string]float64, error) {
+	params.Set("vs_currencies", "usd")
+	endpoint := o.baseURL + path + "?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set(o.keyHeader, o.apiKey)
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("price API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result map[string]struct {
+		USD float64 `json:"usd"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]float64, len(result))
+	for id, quote := range result {
+		if quote.USD > 0 {
+			prices[id] = quote.USD
+		}
+	}
+	return prices, nil
+}
+
+// toUnits converts a base-unit integer string (wei, lamports) to whole units
+func toUnits(amount string, decimals int) float64 {
+	value, ok := new(big.Float).SetString(amount)
+	if !ok || value.Sign() <= 0 {
+		return 0
+	}
+	divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	units, _ := new(big.Float).Quo(value, divisor).Float64()
+	return units
+}
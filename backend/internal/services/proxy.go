@@ -1,26 +1,32 @@
 package services
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
-	"net/url"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/net/proxy"
 
 	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/proxypool"
 )
 
 type ProxyService struct {
 	container *Container
+	pool      *proxypool.Pool
+
+	hostIPMu sync.Mutex
+	hostIP   string // This host's own exit IP, to spot proxies that leak it
+	hostIPAt time.Time
 }
 
 func NewProxyService(c *Container) *ProxyService {
-	return &ProxyService{container: c}
+	return &ProxyService{container: c, pool: proxypool.New(c.DB)}
 }
 
 type CreateProxyRequest struct {
@@ -31,6 +37,8 @@ type CreateProxyRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 	Country  string `json:"country"`
+
+	PoolID *uuid.UUID `json:"pool_id"`
 }
 
 type UpdateProxyRequest struct {
@@ -41,6 +49,8 @@ type UpdateProxyRequest struct {
 	Password string `json:"password"`
 	Country  string `json:"country"`
 	IsActive *bool  `json:"is_active"`
+
+	PoolID *uuid.UUID `json:"pool_id"` // uuid.Nil removes the proxy from its pool
 }
 
 type ProxyTestResult struct {
@@ -49,6 +59,14 @@ type ProxyTestResult struct {
 	ExternalIP string `json:"external_ip"`
 	Country    string `json:"country"`
 	Error      string `json:"error,omitempty"`
+
+	// Exit IP geo data, and what about it could give away the profiles using the proxy
+	City     string         `json:"city,omitempty"`
+	Timezone string         `json:"timezone,omitempty"`
+	ASN      string         `json:"asn,omitempty"`
+	Org      string         `json:"org,omitempty"`
+	GeoError string         `json:"geo_error,omitempty"`
+	Warnings []ProxyWarning `json:"warnings,omitempty"`
 }
 
 func (s *ProxyService) List(userID uuid.UUID) ([]models.Proxy, error) {
@@ -70,8 +88,12 @@ func (s *ProxyService) Create(userID uuid.UUID, req *CreateProxyRequest) (*model
 		Username: req.Username,
 		Password: req.Password,
 		Country:  req.Country,
+		PoolID:   req.PoolID,
 		IsActive: true,
 	}
+	if err := s.checkPool(userID, req.PoolID); err != nil {
+		return nil, err
+	}
 
 	if err := s.container.DB.Create(proxy).Error; err != nil {
 		return nil, err
@@ -107,6 +129,21 @@ func (s *ProxyService) Update(userID, proxyID uuid.UUID, req *UpdateProxyRequest
 	}
 	if req.IsActive != nil {
 		updates["is_active"] = *req.IsActive
+		if *req.IsActive && !proxyRecord.IsActive {
+			// Re-enabled proxies start counting health check failures afresh
+			updates["disabled_at"] = nil
+			updates["consecutive_failures"] = 0
+		}
+	}
+	if req.PoolID != nil {
+		if *req.PoolID == uuid.Nil {
+			updates["pool_id"] = nil
+		} else {
+			if err := s.checkPool(userID, req.PoolID); err != nil {
+				return nil, err
+			}
+			updates["pool_id"] = *req.PoolID
+		}
 	}
 
 	if err := s.container.DB.Model(&proxyRecord).Updates(updates).Error; err != nil {
@@ -130,87 +167,23 @@ func (s *ProxyService) Test(userID, proxyID uuid.UUID) (*ProxyTestResult, error)
 		return nil, errors.New("proxy not found")
 	}
 
-	return s.testProxy(&proxyRecord)
-}
-
-func (s *ProxyService) testProxy(proxyRecord *models.Proxy) (*ProxyTestResult, error) {
-	result := &ProxyTestResult{}
-	startTime := time.Now()
-
-	var httpClient *http.Client
-
-	switch proxyRecord.Type {
-	case "socks5":
-		auth := &proxy.Auth{}
-		if proxyRecord.Username != "" {
-			auth.User = proxyRecord.Username
-			auth.Password = proxyRecord.Password
-		} else {
-			auth = nil
-		}
-
-		dialer, err := proxy.SOCKS5("tcp", fmt.Sprintf("%s:%d", proxyRecord.Host, proxyRecord.Port), auth, proxy.Direct)
-		if err != nil {
-			result.Error = err.Error()
-			return result, nil
-		}
-
-		httpClient = &http.Client{
-			Transport: &http.Transport{
-				Dial: dialer.Dial,
-			},
-			Timeout: 10 * time.Second,
-		}
-
-	case "http":
-		proxyURL := fmt.Sprintf("http://%s:%d", proxyRecord.Host, proxyRecord.Port)
-		if proxyRecord.Username != "" {
-			proxyURL = fmt.Sprintf("http://%s:%s@%s:%d", proxyRecord.Username, proxyRecord.Password, proxyRecord.Host, proxyRecord.Port)
-		}
-
-		parsedProxyURL, err := url.Parse(proxyURL)
-		if err != nil {
-			result.Error = "invalid proxy URL: " + err.Error()
-			return result, nil
-		}
-
-		httpClient = &http.Client{
-			Transport: &http.Transport{
-				Proxy: http.ProxyURL(parsedProxyURL),
-			},
-			Timeout: 10 * time.Second,
-		}
-
-	default:
-		result.Error = "unsupported proxy type"
-		return result, nil
-	}
-
-	// Test connectivity
-	resp, err := httpClient.Get("https://api.ipify.org?format=json")
-	if err != nil {
-		result.Error = err.Error()
-		return result, nil
-	}
-	defer resp.Body.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	check := proxypool.Check(ctx, &proxyRecord, s.container.Config.ProxyEchoURL)
 
-	result.Latency = int(time.Since(startTime).Milliseconds())
-	result.Success = true
+	// A failing test takes the proxy out of rotation and a passing one puts it back.
+	// Only the scheduled checks deactivate proxies.
+	s.pool.RecordCheck(&proxyRecord, check, 0)
 
-	// Parse IP response
-	var ipResp struct {
-		IP string `json:"ip"`
+	result := &ProxyTestResult{
+		Success:    check.Healthy,
+		Latency:    check.LatencyMs,
+		ExternalIP: check.ExternalIP,
+		Error:      check.Error,
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&ipResp); err == nil {
-		result.ExternalIP = ipResp.IP
+	if check.Healthy {
+		s.checkGeo(ctx, &proxyRecord, result)
 	}
-
-	// Update proxy record with latency
-	s.container.DB.Model(proxyRecord).Updates(map[string]interface{}{
-		"last_check": time.Now(),
-		"latency":    result.Latency,
-	})
-
 	return result, nil
 }
 
@@ -232,8 +205,12 @@ func (s *ProxyService) BulkCreate(userID uuid.UUID, req *BulkCreateProxyRequest)
 			Username: proxyReq.Username,
 			Password: proxyReq.Password,
 			Country:  proxyReq.Country,
+			PoolID:   proxyReq.PoolID,
 			IsActive: true,
 		}
+		if err := s.checkPool(userID, proxyReq.PoolID); err != nil {
+			continue
+		}
 
 		if err := s.container.DB.Create(&proxy).Error; err != nil {
 			continue
@@ -251,31 +228,30 @@ func (s *ProxyService) GetHTTPClient(proxyID uuid.UUID) (*http.Client, error) {
 		return nil, err
 	}
 
-	switch proxyRecord.Type {
-	case "socks5":
-		auth := &proxy.Auth{}
-		if proxyRecord.Username != "" {
-			auth.User = proxyRecord.Username
-			auth.Password = proxyRecord.Password
-		} else {
-			auth = nil
-		}
+	return s.pool.Client(&proxyRecord, 30*time.Second)
+}
 
-		dialer, err := proxy.SOCKS5("tcp", fmt.Sprintf("%s:%d", proxyRecord.Host, proxyRecord.Port), auth, proxy.Direct)
-		if err != nil {
-			return nil, err
-		}
+// Acquire hands out the proxy an account should use: a healthy member of its pool
+// by the pool's rotation policy, or its fixed proxy while that is healthy. Accounts
+// without either get nil. Requests through Client count toward the proxy's
+// success and failure totals.
+func (s *ProxyService) Acquire(accountID uuid.UUID) (*models.Proxy, error) {
+	var account models.PlatformAccount
+	if err := s.container.DB.First(&account, "id = ?", accountID).Error; err != nil {
+		return nil, err
+	}
+	return s.pool.AcquireForAccount(context.Background(), &account)
+}
 
-		return &http.Client{
-			Transport: &http.Transport{
-				Dial: dialer.Dial,
-			},
-			Timeout: 30 * time.Second,
-		}, nil
+// AcquireForProfile is Acquire for a browser profile
+func (s *ProxyService) AcquireForProfile(profile *models.BrowserProfile) (*models.Proxy, error) {
+	return s.pool.AcquireForProfile(context.Background(), profile)
+}
 
-	default:
-		return &http.Client{Timeout: 30 * time.Second}, nil
-	}
+// Client returns an HTTP client through an acquired proxy that records each
+// request's outcome against it
+func (s *ProxyService) Client(picked *models.Proxy, timeout time.Duration) (*http.Client, error) {
+	return s.pool.Client(picked, timeout)
 }
 
 // GetDialer returns a net.Dialer configured with the specified proxy
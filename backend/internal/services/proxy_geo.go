@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/proxypool"
+)
+
+// Proxy test warnings
+const (
+	ProxyWarningCountry  = "country_mismatch"  // Exit IP is not in the country the proxy was declared for
+	ProxyWarningTimezone = "timezone_mismatch" // A profile claims a timezone the exit IP is not in
+	ProxyWarningLanguage = "language_mismatch" // A profile's language region differs from the exit country
+	ProxyWarningIPLeak   = "ip_leak"           // The exit IP is this server's own IP
+	ProxyWarningWebRTC   = "webrtc_leak_risk"  // WebRTC UDP traffic bypasses the proxy
+)
+
+// hostIPTTL is how long this host's own exit IP is remembered
+const hostIPTTL = time.Hour
+
+// ProxyWarning flags something that could give away the real location or
+// identity behind a proxy
+type ProxyWarning struct {
+	Code      string     `json:"code"`
+	Message   string     `json:"message"`
+	ProfileID *uuid.UUID `json:"profile_id,omitempty"`
+}
+
+// checkGeo looks up the exit IP's geo data, stores it on the proxy and compares
+// it to the browser profiles that use the proxy
+func (s *ProxyService) checkGeo(ctx context.Context, proxyRecord *models.Proxy, result *ProxyTestResult) {
+	geoURL := s.container.Config.ProxyGeoURL
+	if geoURL == "" {
+		return
+	}
+
+	geo, err := proxypool.LookupGeo(ctx, proxyRecord, geoURL)
+	if err != nil {
+		result.GeoError = err.Error()
+		return
+	}
+	result.Country = geo.Country
+	result.City = geo.City
+	result.Timezone = geo.Timezone
+	result.ASN = geo.ASN
+	result.Org = geo.Org
+
+	s.container.DB.Model(proxyRecord).Updates(map[string]interface{}{
+		"geo_country":  geo.Country,
+		"geo_city":     geo.City,
+		"geo_timezone": geo.Timezone,
+		"geo_asn":      geo.ASN,
+		"geo_org":      geo.Org,
+	})
+
+	exitIP := geo.IP
+	if exitIP == "" {
+		exitIP = result.ExternalIP
+	}
+	if hostIP := s.hostExitIP(ctx); hostIP != "" && hostIP == exitIP {
+		result.Warnings = append(result.Warnings, ProxyWarning{
+			Code:    ProxyWarningIPLeak,
+			Message: "Requests through the proxy arrive from this server's own IP " + exitIP,
+		})
+	}
+	if proxyRecord.Country != "" && geo.Country != "" && !strings.EqualFold(proxyRecord.Country, geo.Country) {
+		result.Warnings = append(result.Warnings, ProxyWarning{
+			Code:    ProxyWarningCountry,
+			Message: fmt.Sprintf("Proxy is declared as %s but exits in %s", strings.ToUpper(proxyRecord.Country), geo.Country),
+		})
+	}
+
+	profiles := s.profilesUsing(proxyRecord)
+	for i := range profiles {
+		result.Warnings = append(result.Warnings, profileGeoWarnings(&profiles[i], geo)...)
+	}
+	if len(profiles) > 0 {
+		result.Warnings = append(result.Warnings, ProxyWarning{
+			Code: ProxyWarningWebRTC,
+			Message: fmt.Sprintf("%s proxies do not carry WebRTC's UDP traffic; %d profile(s) using this proxy can expose their real IP unless WebRTC is disabled or relay-only",
+				proxyRecord.Type, len(profiles)),
+		})
+	}
+}
+
+// profilesUsing returns the owner's browser profiles assigned the proxy directly
+// or through its pool
+func (s *ProxyService) profilesUsing(proxyRecord *models.Proxy) []models.BrowserProfile {
+	query := s.container.DB.Where("user_id = ?", proxyRecord.UserID)
+	if proxyRecord.PoolID != nil {
+		query = query.Where("proxy_id = ? OR proxy_pool_id = ?", proxyRecord.ID, *proxyRecord.PoolID)
+	} else {
+		query = query.Where("proxy_id = ?", proxyRecord.ID)
+	}
+
+	var profiles []models.BrowserProfile
+	query.Find(&profiles)
+	return profiles
+}
+
+// profileGeoWarnings compares a profile's claimed timezone and language region
+// with the exit IP's location
+func profileGeoWarnings(profile *models.BrowserProfile, geo *proxypool.GeoInfo) []ProxyWarning {
+	var warnings []ProxyWarning
+	id := profile.ID
+
+	if profile.Timezone != "" && geo.Timezone != "" && !sameUTCOffset(profile.Timezone, geo.Timezone) {
+		warnings = append(warnings, ProxyWarning{
+			Code:      ProxyWarningTimezone,
+			Message:   fmt.Sprintf("Profile %s claims timezone %s but the exit IP is in %s", profile.Name, profile.Timezone, geo.Timezone),
+			ProfileID: &id,
+		})
+	}
+
+	if region := languageRegion(profile.Language); region != "" && geo.Country != "" && region != geo.Country {
+		warnings = append(warnings, ProxyWarning{
+			Code:      ProxyWarningLanguage,
+			Message:   fmt.Sprintf("Profile %s uses language %s but the exit IP is in %s", profile.Name, profile.Language, geo.Country),
+			ProfileID: &id,
+		})
+	}
+	return warnings
+}
+
+// sameUTCOffset reports whether two IANA timezones are at the same UTC offset
+// right now. Names that fail to load are compared as strings.
+func sameUTCOffset(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	locA, errA := time.LoadLocation(a)
+	locB, errB := time.LoadLocation(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	now := time.Now()
+	_, offsetA := now.In(locA).Zone()
+	_, offsetB := now.In(locB).Zone()
+	return offsetA == offsetB
+}
+
+// languageRegion returns the upper-case region of a language tag such as en-US
+// or pt_BR, or "" if it has none
+func languageRegion(tag string) string {
+	parts := strings.FieldsFunc(tag, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) < 2 || len(parts[1]) != 2 {
+		return ""
+	}
+	return strings.ToUpper(parts[1])
+}
+
+// hostExitIP returns this server's own exit IP, looked up at most every hostIPTTL
+func (s *ProxyService) hostExitIP(ctx context.Context) string {
+	s.hostIPMu.Lock()
+	defer s.hostIPMu.Unlock()
+
+	if s.hostIP != "" && time.Since(s.hostIPAt) < hostIPTTL {
+		return s.hostIP
+	}
+	geo, err := proxypool.LookupGeo(ctx, nil, s.container.Config.ProxyGeoURL)
+	if err != nil {
+		return s.hostIP
+	}
+	s.hostIP, s.hostIPAt = geo.IP, time.Now()
+	return s.hostIP
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/proxypool"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+const (
+	maxProxyImportLines = 2000
+	// proxyImportProgressEvery is how many rows pass between proxy:import_progress events
+	proxyImportProgressEvery = 25
+)
+
+var (
+	ErrTooManyProxyLines   = fmt.Errorf("at most %d proxies can be imported at once", maxProxyImportLines)
+	ErrInvalidProxyType    = errors.New("proxy type must be http, socks5 or residential")
+	ErrNoProxyLines        = errors.New("no proxy lines to import")
+	errProxyImportCanceled = errors.New("import was canceled before this proxy was processed")
+)
+
+// Proxy import row statuses
+const (
+	ProxyImportCreated     = "created"
+	ProxyImportDuplicate   = "duplicate"   // Already imported, or repeated in the list
+	ProxyImportInvalid     = "invalid"     // Not host:port[:user:pass]
+	ProxyImportUnreachable = "unreachable" // Failed the connectivity test
+	ProxyImportFailed      = "failed"      // Could not be saved, or the import was canceled first
+)
+
+// hostnamePattern matches DNS names such as proxy-1.example.com
+var hostnamePattern = regexp.MustCompile(`(?i)^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+
+// ProxyImportOptions apply to every proxy of an import
+type ProxyImportOptions struct {
+	Type    string     `json:"type"` // http (default), socks5 or residential
+	Country string     `json:"country"`
+	PoolID  *uuid.UUID `json:"pool_id"`
+	Test    bool       `json:"test"` // Only create proxies that pass a connectivity test
+}
+
+// ProxyImportRequest is a pasted proxy list, one host:port[:user:pass] per line.
+// Blank lines and lines starting with # are ignored.
+type ProxyImportRequest struct {
+	Lines []string `json:"lines" binding:"required"`
+	ProxyImportOptions
+}
+
+// ProxyImportRow is the outcome for one line of an import
+type ProxyImportRow struct {
+	Line      int        `json:"line"`  // 1-based, counting ignored lines
+	Input     string     `json:"input"` // The line with its password masked
+	Status    string     `json:"status"`
+	Error     string     `json:"error,omitempty"`
+	ProxyID   *uuid.UUID `json:"proxy_id,omitempty"`
+	LatencyMs int        `json:"latency_ms,omitempty"`
+}
+
+// ProxyImportResult lists every row's outcome with totals per status
+type ProxyImportResult struct {
+	ImportID    uuid.UUID        `json:"import_id"`
+	Rows        []ProxyImportRow `json:"rows"`
+	Created     int              `json:"created"`
+	Duplicates  int              `json:"duplicates"`
+	Invalid     int              `json:"invalid"`
+	Unreachable int              `json:"unreachable"`
+	Failed      int              `json:"failed"`
+}
+
+// ProxyImportProgress is sent as proxy:import_progress while an import runs
+type ProxyImportProgress struct {
+	ImportID  uuid.UUID `json:"import_id"`
+	Total     int       `json:"total"`
+	Processed int       `json:"processed"`
+	Created   int       `json:"created"`
+	Done      bool      `json:"done"`
+}
+
+// proxyCandidate is a parsed line waiting to be tested and saved
+type proxyCandidate struct {
+	row   int // Index into the result rows
+	proxy models.Proxy
+}
+
+// BulkImport parses a proxy list, skips lines that are malformed or name a proxy
+// the user already has, optionally tests the rest concurrently, and saves the
+// ones that pass. Importing the same list again only adds what is missing, so
+// an import that was cut short can simply be re-run.
+func (s *ProxyService) BulkImport(ctx context.Context, userID uuid.UUID, lines []string, opts ProxyImportOptions) (*ProxyImportResult, error) {
+	if len(lines) > maxProxyImportLines {
+		return nil, ErrTooManyProxyLines
+	}
+	if opts.Type == "" {
+		opts.Type = "http"
+	}
+	switch opts.Type {
+	case "http", "socks5", "residential":
+	default:
+		return nil, ErrInvalidProxyType
+	}
+	if err := s.checkPool(userID, opts.PoolID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.proxyKeys(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ProxyImportResult{ImportID: uuid.New(), Rows: []ProxyImportRow{}}
+	seen := make(map[string]int) // Key to the line that first named it
+	var candidates []proxyCandidate
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		row := ProxyImportRow{Line: i + 1, Input: maskProxyLine(line)}
+		parsed, err := parseProxyLine(line)
+		if err != nil {
+			row.Status, row.Error = ProxyImportInvalid, err.Error()
+			result.Rows = append(result.Rows, row)
+			continue
+		}
+
+		key := proxyKey(parsed.Host, parsed.Port, parsed.Username)
+		switch first := seen[key]; {
+		case existing[key]:
+			row.Status, row.Error = ProxyImportDuplicate, "proxy already exists"
+		case first > 0:
+			row.Status, row.Error = ProxyImportDuplicate, fmt.Sprintf("same proxy as line %d", first)
+		default:
+			seen[key] = row.Line
+			parsed.ID = uuid.New()
+			parsed.UserID = userID
+			parsed.Name = fmt.Sprintf("%s:%d", parsed.Host, parsed.Port)
+			parsed.Type = opts.Type
+			parsed.Country = opts.Country
+			parsed.PoolID = opts.PoolID
+			parsed.IsActive = true
+			parsed.IsHealthy = true
+			candidates = append(candidates, proxyCandidate{row: len(result.Rows), proxy: *parsed})
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	if len(result.Rows) == 0 {
+		return nil, ErrNoProxyLines
+	}
+
+	progress := ProxyImportProgress{ImportID: result.ImportID, Total: len(result.Rows), Processed: len(result.Rows) - len(candidates)}
+	s.container.WSHub.BroadcastToUser(userID.String(), "proxy:import_progress", progress)
+
+	s.importCandidates(ctx, userID, candidates, opts.Test, result, &progress)
+
+	for _, row := range result.Rows {
+		switch row.Status {
+		case ProxyImportCreated:
+			result.Created++
+		case ProxyImportDuplicate:
+			result.Duplicates++
+		case ProxyImportInvalid:
+			result.Invalid++
+		case ProxyImportUnreachable:
+			result.Unreachable++
+		case ProxyImportFailed:
+			result.Failed++
+		}
+	}
+
+	progress.Processed = progress.Total
+	progress.Done = true
+	s.container.WSHub.BroadcastToUser(userID.String(), "proxy:import_progress", progress)
+	level := "success"
+	if result.Created < len(result.Rows) {
+		level = "warn"
+	}
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:  level,
+		Source: "proxy",
+		Message: fmt.Sprintf("Proxy import finished: %d created, %d duplicates, %d invalid, %d unreachable, %d failed",
+			result.Created, result.Duplicates, result.Invalid, result.Unreachable, result.Failed),
+	})
+
+	return result, nil
+}
+
+// importCandidates tests and saves the candidates on a bounded pool of workers,
+// filling in their rows. Candidates not reached before ctx ends are marked failed.
+func (s *ProxyService) importCandidates(ctx context.Context, userID uuid.UUID, candidates []proxyCandidate, test bool, result *ProxyImportResult, progress *ProxyImportProgress) {
+	workers := s.container.Config.ProxyImportConcurrency
+	if workers <= 0 {
+		workers = 1
+	}
+	queue := make(chan proxyCandidate)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for w := 0; w < min(workers, len(candidates)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for candidate := range queue {
+				row := s.importCandidate(ctx, &candidate.proxy, test)
+
+				mu.Lock()
+				row.Line = result.Rows[candidate.row].Line
+				row.Input = result.Rows[candidate.row].Input
+				result.Rows[candidate.row] = row
+				progress.Processed++
+				if row.Status == ProxyImportCreated {
+					progress.Created++
+				}
+				if progress.Processed%proxyImportProgressEvery == 0 {
+					s.container.WSHub.BroadcastToUser(userID.String(), "proxy:import_progress", *progress)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	next := 0
+feed:
+	for ; next < len(candidates); next++ {
+		select {
+		case queue <- candidates[next]:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	for _, candidate := range candidates[next:] {
+		result.Rows[candidate.row].Status = ProxyImportFailed
+		result.Rows[candidate.row].Error = errProxyImportCanceled.Error()
+	}
+}
+
+// importCandidate tests a parsed proxy when asked to, then saves it
+func (s *ProxyService) importCandidate(ctx context.Context, candidate *models.Proxy, test bool) ProxyImportRow {
+	var row ProxyImportRow
+	if test {
+		check := proxypool.Check(ctx, candidate, s.container.Config.ProxyEchoURL)
+		if !check.Healthy {
+			row.Status, row.Error = ProxyImportUnreachable, check.Error
+			return row
+		}
+		candidate.LastCheck = time.Now()
+		candidate.Latency = check.LatencyMs
+		candidate.ExternalIP = check.ExternalIP
+		row.LatencyMs = check.LatencyMs
+	}
+
+	if err := s.container.DB.Create(candidate).Error; err != nil {
+		row.Status, row.Error = ProxyImportFailed, err.Error()
+		return row
+	}
+	row.Status = ProxyImportCreated
+	row.ProxyID = &candidate.ID
+	return row
+}
+
+// proxyKeys returns the de-duplication keys of the user's existing proxies
+func (s *ProxyService) proxyKeys(userID uuid.UUID) (map[string]bool, error) {
+	var existing []models.Proxy
+	if err := s.container.DB.Select("host", "port", "username").Where("user_id = ?", userID).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	keys := make(map[string]bool, len(existing))
+	for _, p := range existing {
+		keys[proxyKey(p.Host, p.Port, p.Username)] = true
+	}
+	return keys, nil
+}
+
+// proxyKey identifies a proxy for de-duplication. Providers often sell many
+// credentials on one gateway, so the username is part of it.
+func proxyKey(host string, port int, username string) string {
+	return strings.ToLower(host) + ":" + strconv.Itoa(port) + ":" + username
+}
+
+// parseProxyLine parses host:port or host:port:user:pass. The password may
+// itself contain colons.
+func parseProxyLine(line string) (*models.Proxy, error) {
+	parts := strings.SplitN(line, ":", 4)
+	if len(parts) != 2 && len(parts) != 4 {
+		return nil, errors.New("expected host:port or host:port:user:pass")
+	}
+
+	host := parts[0]
+	if net.ParseIP(host) == nil && (len(host) > 253 || !hostnamePattern.MatchString(host)) {
+		return nil, fmt.Errorf("invalid host %q", host)
+	}
+	port, err := strconv.Atoi(parts[1])
+	if err != nil || port < 1 || port > 65535 {
+		return nil, fmt.Errorf("invalid port %q", parts[1])
+	}
+
+	parsed := &models.Proxy{Host: host, Port: port}
+	if len(parts) == 4 {
+		if parts[2] == "" {
+			return nil, errors.New("username is empty")
+		}
+		parsed.Username = parts[2]
+		parsed.Password = parts[3]
+	}
+	return parsed, nil
+}
+
+// maskProxyLine hides the password of a host:port:user:pass line
+func maskProxyLine(line string) string {
+	parts := strings.SplitN(line, ":", 4)
+	if len(parts) == 4 && parts[3] != "" {
+		parts[3] = "****"
+	}
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+var (
+	ErrProxyPoolNotFound = errors.New("proxy pool not found")
+	ErrInvalidPoolPolicy = errors.New("policy must be round_robin, least_recently_used or sticky")
+)
+
+type ProxyPoolRequest struct {
+	Name   string `json:"name" binding:"required"`
+	Policy string `json:"policy"` // Defaults to round_robin
+}
+
+func validPoolPolicy(policy string) bool {
+	switch policy {
+	case models.ProxyPolicyRoundRobin, models.ProxyPolicyLRU, models.ProxyPolicySticky:
+		return true
+	}
+	return false
+}
+
+// checkPool verifies that a pool a proxy, account or profile is joining is the user's
+func (s *ProxyService) checkPool(userID uuid.UUID, poolID *uuid.UUID) error {
+	if poolID == nil {
+		return nil
+	}
+	var count int64
+	s.container.DB.Model(&models.ProxyPool{}).Where("id = ? AND user_id = ?", *poolID, userID).Count(&count)
+	if count == 0 {
+		return ErrProxyPoolNotFound
+	}
+	return nil
+}
+
+// ListPools returns the user's pools with their members and each member's stats
+func (s *ProxyService) ListPools(userID uuid.UUID) ([]models.ProxyPool, error) {
+	var pools []models.ProxyPool
+	if err := s.container.DB.Where("user_id = ?", userID).
+		Preload("Proxies").
+		Order("name").
+		Find(&pools).Error; err != nil {
+		return nil, err
+	}
+	return pools, nil
+}
+
+func (s *ProxyService) CreatePool(userID uuid.UUID, req *ProxyPoolRequest) (*models.ProxyPool, error) {
+	if req.Policy == "" {
+		req.Policy = models.ProxyPolicyRoundRobin
+	}
+	if !validPoolPolicy(req.Policy) {
+		return nil, ErrInvalidPoolPolicy
+	}
+
+	pool := &models.ProxyPool{
+		ID:     uuid.New(),
+		UserID: userID,
+		Name:   req.Name,
+		Policy: req.Policy,
+	}
+	if err := s.container.DB.Create(pool).Error; err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+func (s *ProxyService) UpdatePool(userID, poolID uuid.UUID, req *ProxyPoolRequest) (*models.ProxyPool, error) {
+	var pool models.ProxyPool
+	if err := s.container.DB.Where("id = ? AND user_id = ?", poolID, userID).First(&pool).Error; err != nil {
+		return nil, ErrProxyPoolNotFound
+	}
+	if req.Policy != "" && !validPoolPolicy(req.Policy) {
+		return nil, ErrInvalidPoolPolicy
+	}
+
+	updates := map[string]interface{}{"name": req.Name}
+	if req.Policy != "" {
+		updates["policy"] = req.Policy
+	}
+	if err := s.container.DB.Model(&pool).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return &pool, nil
+}
+
+// DeletePool removes a pool. Its proxies stay as standalone proxies, and accounts
+// and profiles keep the last proxy the pool gave them as their fixed proxy.
+func (s *ProxyService) DeletePool(userID, poolID uuid.UUID) error {
+	result := s.container.DB.Where("id = ? AND user_id = ?", poolID, userID).Delete(&models.ProxyPool{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrProxyPoolNotFound
+	}
+
+	s.container.DB.Model(&models.Proxy{}).Where("pool_id = ?", poolID).Update("pool_id", nil)
+	s.container.DB.Model(&models.PlatformAccount{}).Where("proxy_pool_id = ?", poolID).Update("proxy_pool_id", nil)
+	s.container.DB.Model(&models.BrowserProfile{}).Where("proxy_pool_id = ?", poolID).Update("proxy_pool_id", nil)
+	return nil
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -20,26 +24,101 @@ const (
 	LockTypeGlobal   LockType = "global"   // Global concurrency control
 )
 
+// Rate limiting algorithms
+const (
+	// RateLimitSlidingWindow counts actions in the trailing window, so there is no
+	// boundary where a fresh window doubles the allowed rate
+	RateLimitSlidingWindow = "sliding_window"
+	// RateLimitTokenBucket refills tokens evenly over the window, spacing actions out
+	// instead of allowing the whole window's quota back to back
+	RateLimitTokenBucket = "token_bucket"
+)
+
 // Common errors
 var (
-	ErrLockNotAcquired = errors.New("could not acquire lock")
-	ErrLockExpired     = errors.New("lock expired")
-	ErrRateLimited     = errors.New("rate limit exceeded")
+	ErrLockNotAcquired  = errors.New("could not acquire lock")
+	ErrLockExpired      = errors.New("lock expired")
+	ErrRateLimited      = errors.New("rate limit exceeded")
+	ErrInvalidRateLimit = errors.New("invalid rate limit")
 )
 
 // RateLimiter handles rate limiting and distributed locks using Redis
 type RateLimiter struct {
 	redis     *redis.Client
 	keyPrefix string
+	algorithm string
+
+	// Per-platform limits, DefaultRateLimits unless configured
+	limits map[string]RateLimitConfig
+	// accountLimit returns an account's override spec, or "" for the platform limit
+	accountLimit func(ctx context.Context, accountID string) string
 }
 
 func NewRateLimiter(redisClient *redis.Client) *RateLimiter {
+	limits := make(map[string]RateLimitConfig, len(DefaultRateLimits))
+	for platform, config := range DefaultRateLimits {
+		limits[platform] = config
+	}
+
 	return &RateLimiter{
 		redis:     redisClient,
 		keyPrefix: "web3airdropos:",
+		algorithm: RateLimitSlidingWindow,
+		limits:    limits,
 	}
 }
 
+// SetPlatformLimits overrides platform limits with specs in the ParseRateLimit
+// format, keyed by platform or "default". Invalid specs are logged and ignored.
+func (r *RateLimiter) SetPlatformLimits(specs map[string]string) {
+	for platform, spec := range specs {
+		base, ok := r.limits[platform]
+		if !ok {
+			base = r.limits["default"]
+		}
+		config, err := ParseRateLimit(spec, base)
+		if err != nil {
+			log.Printf("⚠️ Ignoring rate limit for %s: %v", platform, err)
+			continue
+		}
+		r.limits[platform] = config
+	}
+}
+
+// SetAlgorithm selects RateLimitSlidingWindow or RateLimitTokenBucket. Unknown
+// names are logged and leave the sliding window in place.
+func (r *RateLimiter) SetAlgorithm(algorithm string) {
+	switch algorithm {
+	case RateLimitSlidingWindow, RateLimitTokenBucket:
+		r.algorithm = algorithm
+	default:
+		log.Printf("⚠️ Unknown rate limit algorithm %q, using %s", algorithm, RateLimitSlidingWindow)
+		r.algorithm = RateLimitSlidingWindow
+	}
+}
+
+// SetAccountLimitLookup sets where per-account overrides come from
+func (r *RateLimiter) SetAccountLimitLookup(lookup func(ctx context.Context, accountID string) string) {
+	r.accountLimit = lookup
+}
+
+// limitFor returns the limit for an account on a platform: its override if it has
+// a valid one, otherwise the platform's
+func (r *RateLimiter) limitFor(ctx context.Context, platform string, accountID string) RateLimitConfig {
+	config, ok := r.limits[platform]
+	if !ok {
+		config = r.limits["default"]
+	}
+	if r.accountLimit != nil {
+		if spec := r.accountLimit(ctx, accountID); spec != "" {
+			if override, err := ParseRateLimit(spec, config); err == nil {
+				return override
+			}
+		}
+	}
+	return config
+}
+
 // Lock represents an acquired lock
 type Lock struct {
 	key       string
@@ -148,29 +227,125 @@ var DefaultRateLimits = map[string]RateLimitConfig{
 	"telegram":  {Window: time.Second, MaxTokens: 25, BurstSize: 5},
 	"twitter":   {Window: 15 * time.Minute, MaxTokens: 15, BurstSize: 0},
 	"discord":   {Window: time.Minute, MaxTokens: 50, BurstSize: 10},
+	"lens":      {Window: time.Minute, MaxTokens: 10, BurstSize: 2},
 	"default":   {Window: time.Minute, MaxTokens: 30, BurstSize: 5},
 }
 
-// CheckRateLimit checks if an action is within rate limits using sliding window
+// ParseRateLimit parses "COUNT[/WINDOW][+BURST]", e.g. "20/1m+5" for 20 actions a
+// minute with a burst of 5. Parts left out are taken from base.
+func ParseRateLimit(spec string, base RateLimitConfig) (RateLimitConfig, error) {
+	config := base
+	spec = strings.TrimSpace(spec)
+
+	if rest, burst, ok := strings.Cut(spec, "+"); ok {
+		n, err := strconv.Atoi(strings.TrimSpace(burst))
+		if err != nil || n < 0 {
+			return config, fmt.Errorf("invalid burst in rate limit %q", spec)
+		}
+		config.BurstSize = n
+		spec = rest
+	}
+	if count, window, ok := strings.Cut(spec, "/"); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(window))
+		if err != nil || d <= 0 {
+			return config, fmt.Errorf("invalid window in rate limit %q", spec)
+		}
+		config.Window = d
+		spec = count
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(spec))
+	if err != nil || n <= 0 {
+		return config, fmt.Errorf("invalid count in rate limit %q", spec)
+	}
+	config.MaxTokens = n
+	return config, nil
+}
+
+// RateLimitUsage is an account's current use of its rate limit
+type RateLimitUsage struct {
+	Platform  string    `json:"platform"`
+	AccountID string    `json:"account_id"`
+	Used      int       `json:"used"`
+	Limit     int       `json:"limit"` // Per window, excluding burst
+	Burst     int       `json:"burst"`
+	Window    string    `json:"window"`
+	Remaining int       `json:"remaining"`
+	ResetsAt  time.Time `json:"resets_at,omitempty"` // When the full allowance is available again
+}
+
+// Usage reports how much of its limit an account has used in the current window
+func (r *RateLimiter) Usage(ctx context.Context, platform string, accountID string) (*RateLimitUsage, error) {
+	config := r.limitFor(ctx, platform, accountID)
+
+	usage := &RateLimitUsage{
+		Platform:  platform,
+		AccountID: accountID,
+		Limit:     config.MaxTokens,
+		Burst:     config.BurstSize,
+		Window:    config.Window.String(),
+	}
+
+	if r.algorithm == RateLimitTokenBucket {
+		tokens, err := r.bucketTokens(ctx, platform, accountID, config, false)
+		if err != nil {
+			return nil, err
+		}
+		capacity := bucketCapacity(config)
+		usage.Remaining = int(math.Max(0, math.Floor(tokens)))
+		usage.Used = capacity - usage.Remaining
+		if missing := float64(capacity) - tokens; missing > 0 {
+			usage.ResetsAt = time.Now().Add(time.Duration(missing / bucketRefillRate(config) * float64(time.Millisecond)))
+		}
+		return usage, nil
+	}
+
+	now := time.Now()
+	windowStart := now.Add(-config.Window).UnixMilli()
+
+	entries, err := r.redis.ZRangeByScoreWithScores(ctx, r.rateLimitKey(platform, accountID), &redis.ZRangeBy{
+		Min: fmt.Sprintf("(%d", windowStart),
+		Max: fmt.Sprintf("%d", now.UnixMilli()),
+	}).Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	usage.Used = len(entries)
+	usage.Remaining = config.MaxTokens + config.BurstSize - len(entries)
+	if usage.Remaining < 0 {
+		usage.Remaining = 0
+	}
+	if len(entries) > 0 {
+		usage.ResetsAt = time.UnixMilli(int64(entries[0].Score)).Add(config.Window)
+	}
+	return usage, nil
+}
+
+// CheckRateLimit checks if an action is within rate limits using the configured algorithm
 func (r *RateLimiter) CheckRateLimit(ctx context.Context, platform string, accountID string) (bool, error) {
-	config, ok := DefaultRateLimits[platform]
-	if !ok {
-		config = DefaultRateLimits["default"]
+	config := r.limitFor(ctx, platform, accountID)
+
+	if r.algorithm == RateLimitTokenBucket {
+		tokens, err := r.bucketTokens(ctx, platform, accountID, config, false)
+		if err != nil {
+			return false, err
+		}
+		return tokens >= 1, nil
 	}
 
-	key := fmt.Sprintf("%sratelimit:%s:%s", r.keyPrefix, platform, accountID)
+	key := r.rateLimitKey(platform, accountID)
 	now := time.Now().UnixMilli()
 	windowStart := now - config.Window.Milliseconds()
 
 	// Use sorted set with timestamps as scores
 	pipe := r.redis.Pipeline()
-	
+
 	// Remove old entries
 	pipe.ZRemRangeByScore(ctx, key, "0", fmt.Sprintf("%d", windowStart))
-	
+
 	// Count current entries
 	countCmd := pipe.ZCard(ctx, key)
-	
+
 	_, err := pipe.Exec(ctx)
 	if err != nil && err != redis.Nil {
 		return false, err
@@ -184,39 +359,46 @@ func (r *RateLimiter) CheckRateLimit(ctx context.Context, platform string, accou
 
 // RecordAction records an action for rate limiting
 func (r *RateLimiter) RecordAction(ctx context.Context, platform string, accountID string) error {
-	config, ok := DefaultRateLimits[platform]
-	if !ok {
-		config = DefaultRateLimits["default"]
+	config := r.limitFor(ctx, platform, accountID)
+
+	if r.algorithm == RateLimitTokenBucket {
+		_, err := r.bucketTokens(ctx, platform, accountID, config, true)
+		return err
 	}
 
-	key := fmt.Sprintf("%sratelimit:%s:%s", r.keyPrefix, platform, accountID)
+	key := r.rateLimitKey(platform, accountID)
 	now := time.Now().UnixMilli()
 
 	pipe := r.redis.Pipeline()
-	
-	// Add current action
-	pipe.ZAdd(ctx, key, &redis.Z{Score: float64(now), Member: fmt.Sprintf("%d", now)})
-	
+
+	// Add current action; the member is unique so actions in the same millisecond all count
+	pipe.ZAdd(ctx, key, &redis.Z{Score: float64(now), Member: fmt.Sprintf("%d:%s", now, uuid.New().String())})
+
 	// Set expiry on key
 	pipe.Expire(ctx, key, config.Window*2)
-	
+
 	_, err := pipe.Exec(ctx)
 	return err
 }
 
 // GetRemainingQuota returns remaining actions allowed
 func (r *RateLimiter) GetRemainingQuota(ctx context.Context, platform string, accountID string) (int, error) {
-	config, ok := DefaultRateLimits[platform]
-	if !ok {
-		config = DefaultRateLimits["default"]
+	config := r.limitFor(ctx, platform, accountID)
+
+	if r.algorithm == RateLimitTokenBucket {
+		tokens, err := r.bucketTokens(ctx, platform, accountID, config, false)
+		if err != nil {
+			return 0, err
+		}
+		return int(math.Max(0, math.Floor(tokens))), nil
 	}
 
-	key := fmt.Sprintf("%sratelimit:%s:%s", r.keyPrefix, platform, accountID)
+	key := r.rateLimitKey(platform, accountID)
 	now := time.Now().UnixMilli()
 	windowStart := now - config.Window.Milliseconds()
 
 	// Count actions in current window
-	count, err := r.redis.ZCount(ctx, key, fmt.Sprintf("%d", windowStart), fmt.Sprintf("%d", now)).Result()
+	count, err := r.redis.ZCount(ctx, key, fmt.Sprintf("(%d", windowStart), fmt.Sprintf("%d", now)).Result()
 	if err != nil && err != redis.Nil {
 		return 0, err
 	}
@@ -229,6 +411,84 @@ func (r *RateLimiter) GetRemainingQuota(ctx context.Context, platform string, ac
 	return remaining, nil
 }
 
+// rateLimitKey is the Redis key holding an account's sliding window or token bucket
+func (r *RateLimiter) rateLimitKey(platform string, accountID string) string {
+	if r.algorithm == RateLimitTokenBucket {
+		return fmt.Sprintf("%sbucket:%s:%s", r.keyPrefix, platform, accountID)
+	}
+	return fmt.Sprintf("%sratelimit:%s:%s", r.keyPrefix, platform, accountID)
+}
+
+// bucketCapacity is how many actions a full bucket allows back to back. It is the
+// burst size, so a full bucket plus a window of refill never exceeds MaxTokens+BurstSize,
+// the same ceiling the sliding window enforces.
+func bucketCapacity(config RateLimitConfig) int {
+	if config.BurstSize < 1 {
+		return 1
+	}
+	return config.BurstSize
+}
+
+// bucketRefillRate is tokens per millisecond, spreading MaxTokens evenly over the window
+func bucketRefillRate(config RateLimitConfig) float64 {
+	return float64(config.MaxTokens) / float64(config.Window.Milliseconds())
+}
+
+// tokenBucketScript refills the bucket for the time since it was last touched and,
+// if ARGV[4] is 1, takes a token. Tokens may go negative when concurrent callers
+// all passed the check, which delays the next action instead of letting them through.
+var tokenBucketScript = redis.NewScript(`
+	local capacity = tonumber(ARGV[1])
+	local rate = tonumber(ARGV[2])
+	local now = tonumber(ARGV[3])
+
+	local bucket = redis.call("hmget", KEYS[1], "tokens", "ts")
+	local tokens = tonumber(bucket[1])
+	local ts = tonumber(bucket[2])
+	if tokens == nil or ts == nil then
+		tokens = capacity
+		ts = now
+	end
+
+	local elapsed = math.max(0, now - ts)
+	tokens = math.min(capacity, tokens + elapsed * rate)
+	if now > ts then
+		ts = now
+	end
+
+	if ARGV[4] == "1" then
+		tokens = tokens - 1
+		redis.call("hset", KEYS[1], "tokens", tostring(tokens), "ts", ts)
+		redis.call("pexpire", KEYS[1], ARGV[5])
+	end
+
+	return tostring(tokens)
+`)
+
+// bucketTokens returns the tokens in an account's bucket after refilling, taking one
+// first if consume is set
+func (r *RateLimiter) bucketTokens(ctx context.Context, platform string, accountID string, config RateLimitConfig, consume bool) (float64, error) {
+	take := "0"
+	if consume {
+		take = "1"
+	}
+	// The bucket is full again after capacity/rate, so it is safe to expire then
+	capacity := bucketCapacity(config)
+	ttl := int64(float64(capacity)/bucketRefillRate(config)) + config.Window.Milliseconds()
+
+	result, err := tokenBucketScript.Run(ctx, r.redis, []string{r.rateLimitKey(platform, accountID)},
+		capacity,
+		strconv.FormatFloat(bucketRefillRate(config), 'g', -1, 64),
+		time.Now().UnixMilli(),
+		take,
+		ttl,
+	).Text()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(result, 64)
+}
+
 // WaitForQuota waits until quota is available
 func (r *RateLimiter) WaitForQuota(ctx context.Context, platform string, accountID string, maxWait time.Duration) error {
 	deadline := time.Now().Add(maxWait)
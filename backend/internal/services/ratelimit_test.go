@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestRateLimitNeverExceededAcrossWindowBoundary(t *testing.T) {
+	const (
+		window  = 300 * time.Millisecond
+		ceiling = 4 + 2 // MaxTokens + BurstSize
+	)
+	for _, algorithm := range []string{RateLimitSlidingWindow, RateLimitTokenBucket} {
+		t.Run(algorithm, func(t *testing.T) {
+			server := miniredis.RunT(t)
+			client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+			defer client.Close()
+
+			limiter := NewRateLimiter(client)
+			limiter.SetAlgorithm(algorithm)
+			limiter.SetPlatformLimits(map[string]string{"test": "4/300ms+2"})
+			ctx := context.Background()
+
+			// Act as fast as the limiter allows for several windows
+			var allowed []time.Time
+			for end := time.Now().Add(4 * window); time.Now().Before(end); time.Sleep(2 * time.Millisecond) {
+				ok, err := limiter.CheckRateLimit(ctx, "test", "account-1")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !ok {
+					continue
+				}
+				allowed = append(allowed, time.Now())
+				if err := limiter.RecordAction(ctx, "test", "account-1"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// No trailing window, wherever it starts, holds more than the ceiling.
+			// A few milliseconds of slack absorb Redis' millisecond scores.
+			for i, at := range allowed {
+				count := 0
+				for _, earlier := range allowed[:i+1] {
+					if at.Sub(earlier) < window-5*time.Millisecond {
+						count++
+					}
+				}
+				if count > ceiling {
+					t.Fatalf("%d actions allowed in the %s before %s, want at most %d",
+						count, window, at.Format("15:04:05.000"), ceiling)
+				}
+			}
+			if len(allowed) <= ceiling {
+				t.Fatalf("only %d actions allowed over %s; the limit never refilled", len(allowed), 4*window)
+			}
+		})
+	}
+}
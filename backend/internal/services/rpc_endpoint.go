@@ -0,0 +1,161 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/rpc"
+)
+
+var (
+	ErrRPCEndpointNotFound = errors.New("RPC endpoint not found")
+	ErrInvalidRPCURL       = errors.New("RPC URL must be an http(s) or ws(s) URL")
+)
+
+// RPCEndpointService manages stored RPC endpoints. A nil owner means the shared
+// endpoints every user falls back to.
+type RPCEndpointService struct {
+	container *Container
+}
+
+func NewRPCEndpointService(c *Container) *RPCEndpointService {
+	return &RPCEndpointService{container: c}
+}
+
+type CreateRPCEndpointRequest struct {
+	ChainID  int64  `json:"chain_id" binding:"required"`
+	Name     string `json:"name"`
+	URL      string `json:"url" binding:"required"`
+	Priority int    `json:"priority"`
+	APIKey   string `json:"api_key"`
+}
+
+type UpdateRPCEndpointRequest struct {
+	Name     string  `json:"name"`
+	URL      string  `json:"url"`
+	Priority *int    `json:"priority"`
+	APIKey   *string `json:"api_key"` // Empty string removes the key
+	IsActive *bool   `json:"is_active"`
+}
+
+func (s *RPCEndpointService) List(owner *uuid.UUID, chainID int64) ([]models.RPCEndpoint, error) {
+	var endpoints []models.RPCEndpoint
+	query := s.scoped(owner)
+	if chainID != 0 {
+		query = query.Where("chain_id = ?", chainID)
+	}
+	if err := query.Order("chain_id, priority, created_at").Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	for i := range endpoints {
+		endpoints[i].HasAPIKey = endpoints[i].APIKey != ""
+	}
+	return endpoints, nil
+}
+
+func (s *RPCEndpointService) Create(owner *uuid.UUID, req *CreateRPCEndpointRequest) (*models.RPCEndpoint, error) {
+	if !validRPCURL(req.URL) {
+		return nil, ErrInvalidRPCURL
+	}
+
+	endpoint := &models.RPCEndpoint{
+		ID:       uuid.New(),
+		UserID:   owner,
+		ChainID:  req.ChainID,
+		Name:     req.Name,
+		URL:      req.URL,
+		Priority: req.Priority,
+		IsActive: true,
+	}
+	if req.APIKey != "" {
+		sealed, err := rpc.SealAPIKey(s.container.Config.EncryptionKey, req.APIKey)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.APIKey = sealed
+		endpoint.HasAPIKey = true
+	}
+
+	if err := s.container.DB.Create(endpoint).Error; err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+func (s *RPCEndpointService) Update(owner *uuid.UUID, endpointID uuid.UUID, req *UpdateRPCEndpointRequest) (*models.RPCEndpoint, error) {
+	var endpoint models.RPCEndpoint
+	if err := s.scoped(owner).Where("id = ?", endpointID).First(&endpoint).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRPCEndpointNotFound
+		}
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != "" {
+		updates["name"] = req.Name
+	}
+	if req.URL != "" {
+		if !validRPCURL(req.URL) {
+			return nil, ErrInvalidRPCURL
+		}
+		updates["url"] = req.URL
+	}
+	if req.Priority != nil {
+		updates["priority"] = *req.Priority
+	}
+	if req.APIKey != nil {
+		sealed := ""
+		if *req.APIKey != "" {
+			var err error
+			if sealed, err = rpc.SealAPIKey(s.container.Config.EncryptionKey, *req.APIKey); err != nil {
+				return nil, err
+			}
+		}
+		updates["api_key"] = sealed
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+
+	if err := s.container.DB.Model(&endpoint).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	endpoint.HasAPIKey = endpoint.APIKey != ""
+	return &endpoint, nil
+}
+
+func (s *RPCEndpointService) Delete(owner *uuid.UUID, endpointID uuid.UUID) error {
+	result := s.scoped(owner).Where("id = ?", endpointID).Delete(&models.RPCEndpoint{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRPCEndpointNotFound
+	}
+	return nil
+}
+
+func (s *RPCEndpointService) scoped(owner *uuid.UUID) *gorm.DB {
+	if owner == nil {
+		return s.container.DB.Where("user_id IS NULL")
+	}
+	return s.container.DB.Where("user_id = ?", *owner)
+}
+
+func validRPCURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	switch u.Scheme {
+	case "http", "https", "ws", "wss":
+		return true
+	}
+	return false
+}
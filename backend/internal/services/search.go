@@ -0,0 +1,169 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Search result types
+const (
+	SearchTypeCampaign = "campaign"
+	SearchTypeTask     = "task"
+	SearchTypeAccount  = "account"
+	SearchTypeWallet   = "wallet"
+)
+
+const (
+	minSearchQuery     = 2  // Shorter queries match too much to be useful
+	defaultSearchLimit = 10 // Results per type
+	maxSearchLimit     = 50
+)
+
+var (
+	ErrSearchQueryTooShort = fmt.Errorf("search query must be at least %d characters", minSearchQuery)
+	ErrUnknownSearchType   = errors.New("unknown search type")
+)
+
+// SearchResult is one match. Title is the matched name; Subtitle adds context
+// such as a task's campaign or an account's platform.
+type SearchResult struct {
+	Type       string     `json:"type"`
+	ID         uuid.UUID  `json:"id"`
+	Title      string     `json:"title"`
+	Subtitle   string     `json:"subtitle,omitempty"`
+	CampaignID *uuid.UUID `json:"campaign_id,omitempty"` // Set for tasks
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// SearchResults groups matches by type
+type SearchResults struct {
+	Query     string         `json:"query"`
+	Campaigns []SearchResult `json:"campaigns"`
+	Tasks     []SearchResult `json:"tasks"`
+	Accounts  []SearchResult `json:"accounts"`
+	Wallets   []SearchResult `json:"wallets"`
+	Total     int            `json:"total"`
+}
+
+// SearchService finds the user's campaigns, tasks, accounts and wallets by name.
+// Matching is a case-insensitive substring match backed by trigram indexes.
+type SearchService struct {
+	container *Container
+}
+
+func NewSearchService(c *Container) *SearchService {
+	return &SearchService{container: c}
+}
+
+// Search returns up to limit matches of each requested type, all types when
+// types is empty. Names starting with the query rank first, then the most
+// recently updated.
+func (s *SearchService) Search(userID uuid.UUID, query string, types []string, limit int) (*SearchResults, error) {
+	query = strings.TrimSpace(query)
+	if len([]rune(query)) < minSearchQuery {
+		return nil, ErrSearchQueryTooShort
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	wanted := map[string]bool{}
+	for _, t := range types {
+		switch t {
+		case SearchTypeCampaign, SearchTypeTask, SearchTypeAccount, SearchTypeWallet:
+			wanted[t] = true
+		case "":
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrUnknownSearchType, t)
+		}
+	}
+	all := len(wanted) == 0
+
+	results := &SearchResults{
+		Query:     query,
+		Campaigns: []SearchResult{},
+		Tasks:     []SearchResult{},
+		Accounts:  []SearchResult{},
+		Wallets:   []SearchResult{},
+	}
+	contains, prefix := likePatterns(query)
+	db := s.container.Reader
+
+	if all || wanted[SearchTypeCampaign] {
+		if err := s.find(db.Table("campaigns").
+			Select("id, name AS title, type AS subtitle, updated_at").
+			Where("user_id = ? AND deleted_at IS NULL", userID).
+			Where("name ILIKE ? OR description ILIKE ?", contains, contains).
+			Order(gorm.Expr("name ILIKE ? DESC", prefix)).
+			Order("updated_at DESC"), limit, &results.Campaigns); err != nil {
+			return nil, err
+		}
+		setType(results.Campaigns, SearchTypeCampaign)
+	}
+
+	if all || wanted[SearchTypeTask] {
+		if err := s.find(db.Table("campaign_tasks").
+			Select("campaign_tasks.id, campaign_tasks.name AS title, campaigns.name AS subtitle, campaign_tasks.campaign_id, campaign_tasks.updated_at").
+			Joins("JOIN campaigns ON campaigns.id = campaign_tasks.campaign_id AND campaigns.deleted_at IS NULL").
+			Where("campaigns.user_id = ?", userID).
+			Where("campaign_tasks.name ILIKE ?", contains).
+			Order(gorm.Expr("campaign_tasks.name ILIKE ? DESC", prefix)).
+			Order("campaign_tasks.updated_at DESC"), limit, &results.Tasks); err != nil {
+			return nil, err
+		}
+		setType(results.Tasks, SearchTypeTask)
+	}
+
+	if all || wanted[SearchTypeAccount] {
+		if err := s.find(db.Table("platform_accounts").
+			Select("id, username AS title, platform AS subtitle, updated_at").
+			Where("user_id = ? AND deleted_at IS NULL", userID).
+			Where("username ILIKE ? OR display_name ILIKE ?", contains, contains).
+			Order(gorm.Expr("username ILIKE ? DESC", prefix)).
+			Order("updated_at DESC"), limit, &results.Accounts); err != nil {
+			return nil, err
+		}
+		setType(results.Accounts, SearchTypeAccount)
+	}
+
+	if all || wanted[SearchTypeWallet] {
+		if err := s.find(db.Table("wallets").
+			Select("id, name AS title, address AS subtitle, updated_at").
+			Where("user_id = ? AND deleted_at IS NULL", userID).
+			Where("name ILIKE ? OR address ILIKE ?", contains, contains).
+			Order(gorm.Expr("name ILIKE ? DESC", prefix)).
+			Order("updated_at DESC"), limit, &results.Wallets); err != nil {
+			return nil, err
+		}
+		setType(results.Wallets, SearchTypeWallet)
+	}
+
+	results.Total = len(results.Campaigns) + len(results.Tasks) + len(results.Accounts) + len(results.Wallets)
+	return results, nil
+}
+
+// find runs one type's query, which orders its own results
+func (s *SearchService) find(query *gorm.DB, limit int, into *[]SearchResult) error {
+	return query.Limit(limit).Scan(into).Error
+}
+
+func setType(results []SearchResult, resultType string) {
+	for i := range results {
+		results[i].Type = resultType
+	}
+}
+
+// likePatterns returns ILIKE patterns matching query anywhere and at the start,
+// with LIKE wildcards in it taken literally
+func likePatterns(query string) (contains, prefix string) {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(query)
+	return "%" + escaped + "%", escaped + "%"
+}
@@ -7,15 +7,21 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/web3airdropos/backend/internal/circuit"
 	"github.com/web3airdropos/backend/internal/models"
 	"github.com/web3airdropos/backend/internal/services/platforms"
+	"github.com/web3airdropos/backend/internal/tasks"
 	"github.com/web3airdropos/backend/internal/websocket"
 )
 
+// ErrDependencyNotCompleted is returned when a task's dependency has no completed execution
+var ErrDependencyNotCompleted = errors.New("dependency task not completed yet")
+
 type TaskService struct {
 	container   *Container
 	adapters    map[string]platforms.PlatformAdapter
@@ -52,8 +58,10 @@ type UpdateTaskRequest struct {
 	RequiredAction string `json:"required_action"`
 	IsAutomatable  *bool  `json:"is_automatable"`
 	RequiresManual *bool  `json:"requires_manual"`
+	VerifyAfter    *bool  `json:"verify_after"`
 	Points         *int   `json:"points"`
 	Order          *int   `json:"order"`
+	Version        *int   `json:"version"` // Version the edit was based on; a stale one fails with ErrConflict
 }
 
 type ExecuteTaskRequest struct {
@@ -104,6 +112,9 @@ func (s *TaskService) Update(userID, taskID uuid.UUID, req *UpdateTaskRequest) (
 	if req.RequiresManual != nil {
 		updates["requires_manual"] = *req.RequiresManual
 	}
+	if req.VerifyAfter != nil {
+		updates["verify_after"] = *req.VerifyAfter
+	}
 	if req.Points != nil {
 		updates["points"] = *req.Points
 	}
@@ -111,15 +122,19 @@ func (s *TaskService) Update(userID, taskID uuid.UUID, req *UpdateTaskRequest) (
 		updates["order"] = *req.Order
 	}
 
-	if err := s.container.DB.Model(task).Updates(updates).Error; err != nil {
+	if err := updateVersioned(s.container.DB.Model(task), req.Version, updates); err != nil {
+		return nil, err
+	}
+	if err := s.container.DB.First(task, "id = ?", task.ID).Error; err != nil {
 		return nil, err
 	}
 
 	return task, nil
 }
 
-func (s *TaskService) Execute(userID, taskID uuid.UUID, req *ExecuteTaskRequest) (*models.TaskExecution, error) {
-	ctx := context.Background()
+func (s *TaskService) Execute(ctx context.Context, userID, taskID uuid.UUID, req *ExecuteTaskRequest) (*models.TaskExecution, error) {
+	// Keep the request ID but not the cancellation; a dropped client shouldn't abort the action
+	ctx = context.WithoutCancel(ctx)
 
 	task, err := s.Get(userID, taskID)
 	if err != nil {
@@ -134,10 +149,11 @@ func (s *TaskService) Execute(userID, taskID uuid.UUID, req *ExecuteTaskRequest)
 	if err := s.container.DB.Where("idempotency_key = ?", idempotencyKey).First(&existingExecution).Error; err == nil {
 		// Already executed
 		s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
-			Level:   "warn",
-			Source:  "task",
-			Message: "⚠️ Task already executed (idempotency check)",
-			TaskID:  taskID.String(),
+			Level:      "warn",
+			Source:     "task",
+			Message:    "⚠️ Task already executed (idempotency check)",
+			TaskID:     taskID.String(),
+			CampaignID: task.CampaignID.String(),
 		})
 		return &existingExecution, nil
 	}
@@ -145,9 +161,16 @@ func (s *TaskService) Execute(userID, taskID uuid.UUID, req *ExecuteTaskRequest)
 	// Check dependencies
 	if task.DependsOn != nil && !req.Force {
 		var depExecution models.TaskExecution
-		err := s.container.DB.Where("task_id = ? AND status = ?", task.DependsOn, "completed").First(&depExecution).Error
+		err := s.container.DB.Where("task_id = ? AND status IN ?", task.DependsOn, models.ExecutionStatusValues(models.ExecutionCompleted)).First(&depExecution).Error
 		if err != nil {
-			return nil, errors.New("dependency task not completed yet")
+			return nil, ErrDependencyNotCompleted
+		}
+	}
+
+	// Campaigns can hold back accounts that haven't finished warming up
+	if req.AccountID != nil {
+		if err := s.container.Campaign.checkWarmup(task.CampaignID, *req.AccountID); err != nil {
+			return nil, err
 		}
 	}
 
@@ -158,7 +181,23 @@ func (s *TaskService) Execute(userID, taskID uuid.UUID, req *ExecuteTaskRequest)
 			return nil, fmt.Errorf("rate limit check failed: %w", err)
 		}
 		if !allowed {
-			return nil, errors.New("rate limit exceeded for this platform")
+			return nil, fmt.Errorf("%w for this platform", ErrRateLimited)
+		}
+	}
+
+	// Fail fast while the platform keeps failing for this account
+	var circuitKey string
+	if req.AccountID != nil && task.TargetPlatform != "" {
+		circuitKey = circuit.Key(task.TargetPlatform, req.AccountID.String())
+		if err := s.container.Circuits.Allow(circuitKey); err != nil {
+			s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+				Level:      "warn",
+				Source:     "task",
+				Message:    "⚠️ Task skipped: " + err.Error(),
+				TaskID:     taskID.String(),
+				CampaignID: task.CampaignID.String(),
+			})
+			return nil, err
 		}
 	}
 
@@ -179,10 +218,11 @@ func (s *TaskService) Execute(userID, taskID uuid.UUID, req *ExecuteTaskRequest)
 
 	// Broadcast terminal message
 	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
-		Level:   "info",
-		Source:  "task",
-		Message: "Starting task: " + task.Name,
-		TaskID:  taskID.String(),
+		Level:      "info",
+		Source:     "task",
+		Message:    "Starting task: " + task.Name,
+		TaskID:     taskID.String(),
+		CampaignID: task.CampaignID.String(),
 		Details: map[string]interface{}{
 			"type":            task.Type,
 			"target_url":      task.TargetURL,
@@ -194,20 +234,23 @@ func (s *TaskService) Execute(userID, taskID uuid.UUID, req *ExecuteTaskRequest)
 	// Check if requires manual intervention
 	if task.RequiresManual {
 		execution.Status = "waiting_manual"
+		execution.ErrorCode = tasks.ErrorCodeManualRequired
 		s.container.DB.Save(execution)
 
 		s.container.WSHub.BroadcastTaskUpdate(userID.String(), websocket.TaskStatusUpdate{
 			TaskID:         taskID.String(),
+			CampaignID:     task.CampaignID.String(),
 			Status:         "waiting_manual",
 			Message:        task.RequiredAction,
 			RequiresManual: true,
 		})
 
 		s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
-			Level:   "warn",
-			Source:  "task",
-			Message: "⚠️ Manual action required: " + task.RequiredAction,
-			TaskID:  taskID.String(),
+			Level:      "warn",
+			Source:     "task",
+			Message:    "⚠️ Manual action required: " + task.RequiredAction,
+			TaskID:     taskID.String(),
+			CampaignID: task.CampaignID.String(),
 		})
 
 		return execution, nil
@@ -220,10 +263,14 @@ func (s *TaskService) Execute(userID, taskID uuid.UUID, req *ExecuteTaskRequest)
 	if err == nil && req.AccountID != nil && task.TargetPlatform != "" {
 		s.rateLimiter.RecordAction(ctx, task.TargetPlatform, req.AccountID.String())
 	}
+	if circuitKey != "" {
+		s.container.Circuits.Record(circuitKey, err)
+	}
 
 	if err != nil {
 		execution.Status = "failed"
 		execution.ErrorMessage = err.Error()
+		execution.ErrorCode = executionErrorCode(err)
 		s.container.DB.Save(execution)
 
 		// Log failure to audit
@@ -232,11 +279,13 @@ func (s *TaskService) Execute(userID, taskID uuid.UUID, req *ExecuteTaskRequest)
 		}
 
 		s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
-			Level:   "error",
-			Source:  "task",
-			Message: "❌ Task failed: " + err.Error(),
-			TaskID:  taskID.String(),
+			Level:      "error",
+			Source:     "task",
+			Message:    "❌ Task failed: " + err.Error(),
+			TaskID:     taskID.String(),
+			CampaignID: task.CampaignID.String(),
 		})
+		s.notifyTask(userID, models.WebhookEventTaskFailed, task, execution)
 
 		return execution, err
 	}
@@ -249,6 +298,52 @@ func (s *TaskService) Execute(userID, taskID uuid.UUID, req *ExecuteTaskRequest)
 		execution.PostURL = proof.PostURL
 	}
 
+	// Confirm the action actually landed before marking it completed
+	// Actions the platform already reported as done have nothing new to verify
+	if task.VerifyAfter && proof != nil && !isAlreadyDone(proof) {
+		verified, verifyErr := s.verifyProof(ctx, userID, task, execution, proof)
+		if !verified {
+			execution.Status = "unverified"
+			execution.ErrorCode = tasks.ErrorCodeVerificationFailed
+			if verifyErr != nil {
+				execution.ErrorMessage = "verification failed: " + verifyErr.Error()
+			} else {
+				execution.ErrorMessage = "action could not be verified on " + task.TargetPlatform
+			}
+			s.container.DB.Save(execution)
+
+			if s.audit != nil {
+				s.audit.LogTaskExecution(ctx, execution, task, models.ResultFailed, proof, errors.New(execution.ErrorMessage))
+			}
+
+			s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+				Level:      "warn",
+				Source:     "task",
+				Message:    "⚠️ Task unverified: " + execution.ErrorMessage,
+				TaskID:     taskID.String(),
+				CampaignID: task.CampaignID.String(),
+			})
+
+			s.container.WSHub.BroadcastTaskUpdate(userID.String(), websocket.TaskStatusUpdate{
+				TaskID:     taskID.String(),
+				CampaignID: task.CampaignID.String(),
+				Status:     "unverified",
+				Message:    execution.ErrorMessage,
+			})
+			s.notifyTask(userID, models.WebhookEventTaskFailed, task, execution)
+
+			return execution, nil
+		}
+	}
+
+	// A sent transaction only counts once mined; PollTransactionReceipts completes it
+	if execution.TransactionHash != "" {
+		if err := s.awaitReceipt(userID, task, execution); err != nil {
+			return nil, err
+		}
+		return execution, nil
+	}
+
 	now := time.Now()
 	execution.Status = "completed"
 	execution.CompletedAt = &now
@@ -258,12 +353,14 @@ func (s *TaskService) Execute(userID, taskID uuid.UUID, req *ExecuteTaskRequest)
 	if s.audit != nil {
 		s.audit.LogTaskExecution(ctx, execution, task, models.ResultSuccess, proof, nil)
 	}
+	s.logActivity(task, execution, proof)
 
 	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
-		Level:   "success",
-		Source:  "task",
-		Message: "✅ Task completed: " + task.Name,
-		TaskID:  taskID.String(),
+		Level:      "success",
+		Source:     "task",
+		Message:    "✅ Task completed: " + task.Name,
+		TaskID:     taskID.String(),
+		CampaignID: task.CampaignID.String(),
 		Details: map[string]interface{}{
 			"proof_type":  execution.ProofType,
 			"proof_value": execution.ProofValue,
@@ -271,14 +368,50 @@ func (s *TaskService) Execute(userID, taskID uuid.UUID, req *ExecuteTaskRequest)
 	})
 
 	s.container.WSHub.BroadcastTaskUpdate(userID.String(), websocket.TaskStatusUpdate{
-		TaskID:  taskID.String(),
-		Status:  "completed",
-		Message: "Task completed successfully",
+		TaskID:     taskID.String(),
+		CampaignID: task.CampaignID.String(),
+		Status:     "completed",
+		Message:    "Task completed successfully",
 	})
+	s.notifyTask(userID, models.WebhookEventTaskCompleted, task, execution)
+	s.container.Campaign.completeIfDone(userID, task.CampaignID)
 
 	return execution, nil
 }
 
+// notifyTask sends a task event to the user's webhooks
+func (s *TaskService) notifyTask(userID uuid.UUID, event string, task *models.CampaignTask, execution *models.TaskExecution) {
+	s.container.WebhookDispatcher.Dispatch(userID, event, map[string]interface{}{
+		"task_id":      task.ID,
+		"task_name":    task.Name,
+		"task_type":    task.Type,
+		"campaign_id":  task.CampaignID,
+		"execution_id": execution.ID,
+		"wallet_id":    execution.WalletID,
+		"account_id":   execution.AccountID,
+		"status":       execution.Status,
+		"error":        execution.ErrorMessage,
+		"proof_type":   execution.ProofType,
+		"proof_value":  execution.ProofValue,
+	})
+}
+
+// executionErrorCode classifies an execution error, including the errors of this
+// package that tasks.ErrorCodeFor cannot see
+func executionErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrRateLimited):
+		return tasks.ErrorCodeRateLimited
+	case errors.Is(err, ErrLockNotAcquired):
+		return tasks.ErrorCodeLocked
+	case errors.Is(err, ErrDependencyNotCompleted):
+		return tasks.ErrorCodeDependencyFailed
+	case errors.Is(err, ErrVerificationFailed):
+		return tasks.ErrorCodeVerificationFailed
+	}
+	return tasks.ErrorCodeFor(err)
+}
+
 // generateIdempotencyKey creates a unique key for a task execution
 func (s *TaskService) generateIdempotencyKey(userID, taskID uuid.UUID, req *ExecuteTaskRequest) string {
 	data := fmt.Sprintf("%s:%s:", userID.String(), taskID.String())
@@ -300,9 +433,11 @@ func (s *TaskService) executeTaskByType(ctx context.Context, userID uuid.UUID, t
 	case models.TaskTypeConnect:
 		return nil, s.executeWalletConnect(userID, task, execution)
 	case models.TaskTypeTransaction:
-		return nil, s.executeTransaction(userID, task, execution)
+		return s.executeTransaction(ctx, userID, task, execution)
 	case models.TaskTypeClaim:
 		return nil, s.executeClaim(userID, task, execution)
+	case models.TaskTypeApprove:
+		return s.executeApprove(ctx, userID, task, execution)
 	case models.TaskTypeFollow:
 		return s.executeFollowWithAdapter(ctx, userID, task, execution)
 	case models.TaskTypeJoin:
@@ -315,8 +450,10 @@ func (s *TaskService) executeTaskByType(ctx context.Context, userID uuid.UUID, t
 		return s.executeLikeWithAdapter(ctx, userID, task, execution)
 	case models.TaskTypeRecast:
 		return s.executeRecastWithAdapter(ctx, userID, task, execution)
+	case models.TaskTypeDirectMessage:
+		return s.executeDirectMessageWithAdapter(ctx, userID, task, execution)
 	case models.TaskTypeVerify:
-		return nil, s.executeVerify(userID, task, execution)
+		return s.executeVerify(ctx, userID, task, execution)
 	default:
 		return nil, errors.New("unsupported task type")
 	}
@@ -325,10 +462,11 @@ func (s *TaskService) executeTaskByType(ctx context.Context, userID uuid.UUID, t
 func (s *TaskService) executeWalletConnect(userID uuid.UUID, task *models.CampaignTask, execution *models.TaskExecution) error {
 	// Wallet connect requires browser automation - signal the browser service
 	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
-		Level:   "info",
-		Source:  "task",
-		Message: "Wallet connect task - initiating browser session...",
-		TaskID:  task.ID.String(),
+		Level:      "info",
+		Source:     "task",
+		Message:    "Wallet connect task - initiating browser session...",
+		TaskID:     task.ID.String(),
+		CampaignID: task.CampaignID.String(),
 	})
 
 	// Update execution to pending - requires user interaction in browser
@@ -347,27 +485,29 @@ func (s *TaskService) executeWalletConnect(userID uuid.UUID, task *models.Campai
 	return nil
 }
 
-func (s *TaskService) executeTransaction(userID uuid.UUID, task *models.CampaignTask, execution *models.TaskExecution) error {
+// executeTransaction sends the transaction from a wallet that allows server
+// signing, and otherwise asks the user's browser to sign it
+func (s *TaskService) executeTransaction(ctx context.Context, userID uuid.UUID, task *models.CampaignTask, execution *models.TaskExecution) (*platforms.ActionProof, error) {
+	if execution.WalletID != nil {
+		proof, err := s.sendTaskTransaction(ctx, userID, task, *execution.WalletID)
+		if err == nil {
+			execution.TransactionHash = proof.TxHash
+			return proof, nil
+		}
+		if !needsBrowserSigning(err) {
+			return nil, err
+		}
+	}
+
 	// Transaction execution requires browser wallet interaction
 	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
-		Level:   "info",
-		Source:  "task",
-		Message: "Preparing transaction for signing...",
-		TaskID:  task.ID.String(),
+		Level:      "info",
+		Source:     "task",
+		Message:    "Preparing transaction for signing...",
+		TaskID:     task.ID.String(),
+		CampaignID: task.CampaignID.String(),
 	})
 
-	// Parse transaction details from task config
-	var txConfig struct {
-		ContractAddress string `json:"contract_address"`
-		ChainID         int    `json:"chain_id"`
-		FunctionName    string `json:"function_name"`
-		Value           string `json:"value"`
-	}
-	if task.Config != "" {
-		// Config contains transaction details
-		_ = txConfig // Config parsing happens on frontend
-	}
-
 	// Update execution to pending - requires signature
 	execution.Status = "pending"
 	execution.ErrorMessage = "Awaiting transaction signature in browser"
@@ -382,7 +522,7 @@ func (s *TaskService) executeTransaction(userID uuid.UUID, task *models.Campaign
 		"tx_config":    task.Config,
 	})
 
-	return nil
+	return nil, nil
 }
 
 func (s *TaskService) executeClaim(userID uuid.UUID, task *models.CampaignTask, execution *models.TaskExecution) error {
@@ -412,20 +552,22 @@ func (s *TaskService) executeFollowWithAdapter(ctx context.Context, userID uuid.
 	if err != nil {
 		// Fallback: log and return nil (manual execution needed)
 		s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
-			Level:   "warn",
-			Source:  "task",
-			Message: fmt.Sprintf("No adapter for %s, requires manual execution", task.TargetPlatform),
-			TaskID:  task.ID.String(),
+			Level:      "warn",
+			Source:     "task",
+			Message:    fmt.Sprintf("No adapter for %s, requires manual execution", task.TargetPlatform),
+			TaskID:     task.ID.String(),
+			CampaignID: task.CampaignID.String(),
 		})
 		return nil, nil
 	}
 
 	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
-		Level:     "info",
-		Source:    "task",
-		Message:   "Following " + task.TargetAccount + " on " + task.TargetPlatform,
-		TaskID:    task.ID.String(),
-		AccountID: account.ID.String(),
+		Level:      "info",
+		Source:     "task",
+		Message:    "Following " + task.TargetAccount + " on " + task.TargetPlatform,
+		TaskID:     task.ID.String(),
+		CampaignID: task.CampaignID.String(),
+		AccountID:  account.ID.String(),
 	})
 
 	// Acquire account lock (one action at a time per account)
@@ -435,8 +577,13 @@ func (s *TaskService) executeFollowWithAdapter(ctx context.Context, userID uuid.
 	}
 	defer lock.Release(ctx)
 
-	// Execute follow via adapter
-	return adapter.Follow(ctx, task.TargetAccount)
+	// Execute follow via adapter. A follow that already exists is the outcome
+	// the task wants, so it completes rather than failing.
+	proof, err := adapter.Follow(ctx, task.TargetAccount)
+	if errors.Is(err, platforms.ErrAlreadyFollowing) {
+		return s.alreadyDone(userID, task, task.TargetAccount, "Already following "+task.TargetAccount), nil
+	}
+	return proof, err
 }
 
 func (s *TaskService) executeJoin(userID uuid.UUID, task *models.CampaignTask, execution *models.TaskExecution) error {
@@ -455,7 +602,39 @@ func (s *TaskService) executePostWithAdapter(ctx context.Context, userID uuid.UU
 		return nil, errors.New("account required for post task")
 	}
 
-	// Get content from task config or content draft
+	content := s.postContent(task)
+	if content == "" {
+		return nil, errors.New("no content specified for post")
+	}
+
+	// Get adapter
+	adapter, err := s.GetAdapter(task.TargetPlatform)
+	if err != nil {
+		return nil, nil // Manual execution needed
+	}
+
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:      "info",
+		Source:     "task",
+		Message:    "Creating post on " + task.TargetPlatform,
+		TaskID:     task.ID.String(),
+		CampaignID: task.CampaignID.String(),
+	})
+
+	// Acquire account lock
+	lock, err := s.rateLimiter.AccountLock(ctx, *execution.AccountID, 30*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("could not acquire account lock: %w", err)
+	}
+	defer lock.Release(ctx)
+
+	// Execute via adapter
+	return adapter.Post(ctx, &platforms.PostContent{Text: content})
+}
+
+// postContent returns a post task's text: the config's content or content
+// draft, falling back to the required action
+func (s *TaskService) postContent(task *models.CampaignTask) string {
 	var content string
 	if task.Config != "" {
 		// Try to parse content from task config
@@ -468,8 +647,9 @@ func (s *TaskService) executePostWithAdapter(ctx context.Context, userID uuid.UU
 				content = cfg.Content
 			} else if cfg.ContentDraftID != "" {
 				// Fetch from content drafts table
+				// Flagged drafts are never auto-posted until approved
 				var draft models.ContentDraft
-				if err := s.container.DB.First(&draft, "id = ?", cfg.ContentDraftID).Error; err == nil {
+				if err := s.container.DB.First(&draft, "id = ? AND status != ?", cfg.ContentDraftID, "flagged").Error; err == nil {
 					content = draft.Content
 				}
 			}
@@ -480,33 +660,7 @@ func (s *TaskService) executePostWithAdapter(ctx context.Context, userID uuid.UU
 	if content == "" {
 		content = task.RequiredAction
 	}
-
-	if content == "" {
-		return nil, errors.New("no content specified for post")
-	}
-
-	// Get adapter
-	adapter, err := s.GetAdapter(task.TargetPlatform)
-	if err != nil {
-		return nil, nil // Manual execution needed
-	}
-
-	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
-		Level:   "info",
-		Source:  "task",
-		Message: "Creating post on " + task.TargetPlatform,
-		TaskID:  task.ID.String(),
-	})
-
-	// Acquire account lock
-	lock, err := s.rateLimiter.AccountLock(ctx, *execution.AccountID, 30*time.Second)
-	if err != nil {
-		return nil, fmt.Errorf("could not acquire account lock: %w", err)
-	}
-	defer lock.Release(ctx)
-
-	// Execute via adapter
-	return adapter.Post(ctx, &platforms.PostContent{Text: content})
+	return content
 }
 
 func (s *TaskService) executeReply(userID uuid.UUID, task *models.CampaignTask, execution *models.TaskExecution) error {
@@ -556,10 +710,11 @@ func (s *TaskService) executeLikeWithAdapter(ctx context.Context, userID uuid.UU
 	}
 
 	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
-		Level:   "info",
-		Source:  "task",
-		Message: "Liking post on " + task.TargetPlatform,
-		TaskID:  task.ID.String(),
+		Level:      "info",
+		Source:     "task",
+		Message:    "Liking post on " + task.TargetPlatform,
+		TaskID:     task.ID.String(),
+		CampaignID: task.CampaignID.String(),
 	})
 
 	// Acquire account lock
@@ -569,7 +724,11 @@ func (s *TaskService) executeLikeWithAdapter(ctx context.Context, userID uuid.UU
 	}
 	defer lock.Release(ctx)
 
-	return adapter.Like(ctx, task.TargetURL)
+	proof, err := adapter.Like(ctx, task.TargetURL)
+	if errors.Is(err, platforms.ErrAlreadyLiked) {
+		return s.alreadyDone(userID, task, task.TargetURL, "Post already liked"), nil
+	}
+	return proof, err
 }
 
 func (s *TaskService) executeRecast(userID uuid.UUID, task *models.CampaignTask, execution *models.TaskExecution) error {
@@ -588,10 +747,11 @@ func (s *TaskService) executeRecastWithAdapter(ctx context.Context, userID uuid.
 	}
 
 	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
-		Level:   "info",
-		Source:  "task",
-		Message: "Recasting post on " + task.TargetPlatform,
-		TaskID:  task.ID.String(),
+		Level:      "info",
+		Source:     "task",
+		Message:    "Recasting post on " + task.TargetPlatform,
+		TaskID:     task.ID.String(),
+		CampaignID: task.CampaignID.String(),
 	})
 
 	// Acquire account lock
@@ -604,15 +764,150 @@ func (s *TaskService) executeRecastWithAdapter(ctx context.Context, userID uuid.
 	return adapter.Repost(ctx, task.TargetURL)
 }
 
-func (s *TaskService) executeVerify(userID uuid.UUID, task *models.CampaignTask, execution *models.TaskExecution) error {
-	// Verify task completion
-	return nil
+// alreadyDone builds the proof for an action the platform reports as already
+// taken. Its proof_type is already_done and its value the target.
+func (s *TaskService) alreadyDone(userID uuid.UUID, task *models.CampaignTask, target, message string) *platforms.ActionProof {
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:      "info",
+		Source:     "task",
+		Message:    message + ", nothing to do",
+		TaskID:     task.ID.String(),
+		CampaignID: task.CampaignID.String(),
+	})
+	return &platforms.ActionProof{
+		Timestamp: time.Now().Unix(),
+		Metadata: map[string]string{
+			"already_done": "true",
+			"target":       target,
+		},
+	}
+}
+
+func isAlreadyDone(proof *platforms.ActionProof) bool {
+	return proof != nil && proof.Metadata["already_done"] == "true"
+}
+
+// executeDirectMessageWithAdapter sends a direct message to the task's target account
+func (s *TaskService) executeDirectMessageWithAdapter(ctx context.Context, userID uuid.UUID, task *models.CampaignTask, execution *models.TaskExecution) (*platforms.ActionProof, error) {
+	if execution.AccountID == nil {
+		return nil, errors.New("account required for direct message task")
+	}
+	if task.TargetAccount == "" {
+		return nil, errors.New("no recipient specified for direct message")
+	}
+
+	content := directMessageContent(task)
+	if content == "" {
+		return nil, errors.New("no content specified for direct message")
+	}
+
+	adapter, err := s.GetAdapter(task.TargetPlatform)
+	if err != nil {
+		return nil, nil
+	}
+
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:      "info",
+		Source:     "task",
+		Message:    "Sending direct message to " + task.TargetAccount + " on " + task.TargetPlatform,
+		TaskID:     task.ID.String(),
+		CampaignID: task.CampaignID.String(),
+	})
+
+	// Acquire account lock
+	lock, err := s.rateLimiter.AccountLock(ctx, *execution.AccountID, 30*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("could not acquire account lock: %w", err)
+	}
+	defer lock.Release(ctx)
+
+	return adapter.SendDirectMessage(ctx, task.TargetAccount, content)
+}
+
+// directMessageContent returns a direct message task's text: the config's
+// content, falling back to the required action
+func directMessageContent(task *models.CampaignTask) string {
+	if task.Config != "" {
+		var cfg struct {
+			Content string `json:"content"`
+		}
+		if err := json.Unmarshal([]byte(task.Config), &cfg); err == nil && cfg.Content != "" {
+			return cfg.Content
+		}
+	}
+	return task.RequiredAction
+}
+
+// logActivity adds a completed social task to its account's activity feed.
+// Tasks left for manual execution and actions the platform reported as
+// already done were not taken by this run, so they are not logged.
+func (s *TaskService) logActivity(task *models.CampaignTask, execution *models.TaskExecution, proof *platforms.ActionProof) {
+	if execution.AccountID == nil || proof == nil || isAlreadyDone(proof) {
+		return
+	}
+
+	var content string
+	target := task.TargetURL
+	switch task.Type {
+	case models.TaskTypePost:
+		content = s.postContent(task)
+	case models.TaskTypeReply:
+		content = task.RequiredAction
+	case models.TaskTypeDirectMessage:
+		content = directMessageContent(task)
+		target = task.TargetAccount
+	case models.TaskTypeFollow:
+		target = task.TargetAccount
+	case models.TaskTypeLike, models.TaskTypeRecast:
+	default:
+		return
+	}
+
+	metadata := map[string]interface{}{
+		"task_id":      task.ID,
+		"execution_id": execution.ID,
+		"target":       target,
+		"proof":        proof,
+	}
+	if err := s.container.Account.LogActivity(*execution.AccountID, string(task.Type), content, metadata, &task.CampaignID, task.ID.String()); err != nil {
+		log.Printf("⚠️ Failed to log activity for task %s: %v", task.ID, err)
+	}
+}
+
+// verifyProof asks the platform adapter to confirm an action proof
+func (s *TaskService) verifyProof(ctx context.Context, userID uuid.UUID, task *models.CampaignTask, execution *models.TaskExecution, proof *platforms.ActionProof) (bool, error) {
+	adapter, err := s.GetAdapter(task.TargetPlatform)
+	if err != nil {
+		return false, err
+	}
+
+	execution.Status = "verifying"
+	s.container.DB.Save(execution)
+
+	s.container.WSHub.BroadcastTaskUpdate(userID.String(), websocket.TaskStatusUpdate{
+		TaskID:     task.ID.String(),
+		CampaignID: task.CampaignID.String(),
+		Status:     "verifying",
+		Message:    "Verifying action on " + task.TargetPlatform,
+	})
+
+	// Proof metadata isn't always enough to verify follows
+	if task.Type == models.TaskTypeFollow && task.TargetAccount != "" {
+		if proof.Metadata == nil {
+			proof.Metadata = map[string]string{}
+		}
+		if proof.Metadata["target_fid"] == "" {
+			proof.Metadata["target_fid"] = task.TargetAccount
+		}
+	}
+
+	return adapter.VerifyAction(ctx, string(task.Type), proof)
 }
 
 // Continue resumes a task that was waiting for manual action
 func (s *TaskService) Continue(userID, taskID, executionID uuid.UUID, result map[string]interface{}) error {
 	// Verify ownership
-	_, err := s.Get(userID, taskID)
+	task, err := s.Get(userID, taskID)
 	if err != nil {
 		return err
 	}
@@ -626,30 +921,47 @@ func (s *TaskService) Continue(userID, taskID, executionID uuid.UUID, result map
 		return errors.New("task is not waiting for manual action")
 	}
 
-	// Update execution with result
-	now := time.Now()
-	execution.Status = "completed"
-	execution.CompletedAt = &now
-
 	if txHash, ok := result["transaction_hash"].(string); ok {
 		execution.TransactionHash = txHash
 	}
 
+	if screenshot, ok := result["screenshot"].(string); ok && screenshot != "" {
+		key, err := s.storeScreenshotProof(context.Background(), &execution, screenshot)
+		if err != nil {
+			return err
+		}
+		execution.ScreenshotPath = key
+		if execution.ProofType == "" {
+			execution.ProofType = "screenshot"
+			execution.ProofValue = key
+		}
+	}
+
+	// A browser-signed transaction only counts once mined
+	if execution.TransactionHash != "" {
+		return s.awaitReceipt(userID, task, &execution)
+	}
+
+	now := time.Now()
+	execution.Status = "completed"
+	execution.CompletedAt = &now
 	if err := s.container.DB.Save(&execution).Error; err != nil {
 		return err
 	}
 
 	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
-		Level:   "success",
-		Source:  "task",
-		Message: "✅ Manual task completed",
-		TaskID:  taskID.String(),
+		Level:      "success",
+		Source:     "task",
+		Message:    "✅ Manual task completed",
+		TaskID:     taskID.String(),
+		CampaignID: task.CampaignID.String(),
 	})
 
 	s.container.WSHub.BroadcastTaskUpdate(userID.String(), websocket.TaskStatusUpdate{
-		TaskID:  taskID.String(),
-		Status:  "completed",
-		Message: "Manual action completed",
+		TaskID:     taskID.String(),
+		CampaignID: task.CampaignID.String(),
+		Status:     "completed",
+		Message:    "Manual action completed",
 	})
 
 	return nil
@@ -665,12 +977,16 @@ func (s *TaskService) GetExecutions(userID, taskID uuid.UUID) ([]models.TaskExec
 	if err := s.container.DB.Where("task_id = ?", taskID).Order("created_at DESC").Find(&executions).Error; err != nil {
 		return nil, err
 	}
+	s.signScreenshotURLs(context.Background(), executions)
 
 	return executions, nil
 }
 
 // Helper functions
 func getProofTypeFromAdapter(proof *platforms.ActionProof) string {
+	if isAlreadyDone(proof) {
+		return "already_done"
+	}
 	if proof.TxHash != "" {
 		return "tx_hash"
 	}
@@ -690,6 +1006,9 @@ func getProofTypeFromAdapter(proof *platforms.ActionProof) string {
 }
 
 func getProofValueFromAdapter(proof *platforms.ActionProof) string {
+	if isAlreadyDone(proof) {
+		return proof.Metadata["target"]
+	}
 	if proof.TxHash != "" {
 		return proof.TxHash
 	}
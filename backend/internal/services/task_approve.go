@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/services/platforms"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+// ERC-20 function selectors
+var (
+	selectorAllowance = []byte{0xdd, 0x62, 0xed, 0x3e} // allowance(address,address)
+	selectorApprove   = []byte{0x09, 0x5e, 0xa7, 0xb3} // approve(address,uint256)
+)
+
+// approveConfig is the task config of an approve task. Amount is in the token's base
+// units, or "max" for an unlimited approval, which also needs AllowInfinite.
+type approveConfig struct {
+	Token         string `json:"token"`
+	Spender       string `json:"spender"`
+	Amount        string `json:"amount"`
+	ChainID       int64  `json:"chain_id"`
+	AllowInfinite bool   `json:"allow_infinite"`
+}
+
+func parseApproveConfig(raw string) (*approveConfig, *big.Int, error) {
+	var cfg approveConfig
+	if raw == "" {
+		return nil, nil, errors.New("approve task has no config")
+	}
+	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+		return nil, nil, fmt.Errorf("invalid approve task config: %w", err)
+	}
+	if !common.IsHexAddress(cfg.Token) || !common.IsHexAddress(cfg.Spender) {
+		return nil, nil, errors.New("approve task needs token and spender addresses")
+	}
+	if cfg.ChainID == 0 {
+		cfg.ChainID = 1
+	}
+
+	if strings.EqualFold(cfg.Amount, "max") {
+		if !cfg.AllowInfinite {
+			return nil, nil, errors.New("unlimited approval needs allow_infinite in the task config")
+		}
+		return &cfg, math.MaxBig256, nil
+	}
+	amount, ok := new(big.Int).SetString(cfg.Amount, 10)
+	if !ok || amount.Sign() <= 0 {
+		return nil, nil, errors.New("approve task amount must be a positive integer or \"max\"")
+	}
+	return &cfg, amount, nil
+}
+
+// executeApprove approves a spender for an ERC-20 token, skipping the transaction
+// when the current allowance already covers the amount. The approval is signed on
+// the server, so the wallet must allow server signing.
+func (s *TaskService) executeApprove(ctx context.Context, userID uuid.UUID, task *models.CampaignTask, execution *models.TaskExecution) (*platforms.ActionProof, error) {
+	if execution.WalletID == nil {
+		return nil, errors.New("wallet ID required for approve task")
+	}
+	cfg, amount, err := parseApproveConfig(task.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	var wallet models.Wallet
+	if err := s.container.DB.Where("id = ? AND user_id = ?", *execution.WalletID, userID).First(&wallet).Error; err != nil {
+		return nil, err
+	}
+	token := common.HexToAddress(cfg.Token)
+	spender := common.HexToAddress(cfg.Spender)
+
+	allowance, err := s.allowance(ctx, userID, cfg.ChainID, token, common.HexToAddress(wallet.Address), spender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allowance: %w", err)
+	}
+	if allowance.Cmp(amount) >= 0 {
+		s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+			Level:      "info",
+			Source:     "task",
+			Message:    "Allowance already sufficient, skipping approval",
+			TaskID:     task.ID.String(),
+			CampaignID: task.CampaignID.String(),
+		})
+		return nil, nil
+	}
+
+	data := approveCalldata(spender, amount)
+
+	audit := &LogEntry{
+		UserID:     userID,
+		WalletID:   &wallet.ID,
+		Action:     models.ActionTokenApproval,
+		Platform:   "evm",
+		TargetType: "token",
+		TargetID:   token.Hex(),
+		TaskID:     &task.ID,
+		RequestData: map[string]interface{}{
+			"spender":   spender.Hex(),
+			"amount":    amount.String(),
+			"allowance": allowance.String(),
+			"chain_id":  cfg.ChainID,
+		},
+	}
+
+	hash, err := s.container.Wallet.SendTransaction(userID, wallet.ID, &PrepareTransactionRequest{
+		ChainID: cfg.ChainID,
+		To:      token.Hex(),
+		Data:    hex.EncodeToString(data),
+	}, &task.ID)
+	if err != nil {
+		audit.Result = models.ResultFailed
+		audit.ErrorMessage = err.Error()
+		s.container.Audit.Log(ctx, audit)
+		return nil, err
+	}
+
+	proof := &platforms.ActionProof{TxHash: hash, Timestamp: time.Now().Unix()}
+	audit.Result = models.ResultSuccess
+	audit.Proof = proof
+	s.container.Audit.Log(ctx, audit)
+
+	execution.TransactionHash = hash
+	return proof, nil
+}
+
+// approveCalldata encodes approve(spender, amount)
+func approveCalldata(spender common.Address, amount *big.Int) []byte {
+	data := append(append([]byte{}, selectorApprove...), common.LeftPadBytes(spender.Bytes(), 32)...)
+	return append(data, common.LeftPadBytes(amount.Bytes(), 32)...)
+}
+
+// allowance reads an ERC-20 allowance with eth_call
+func (s *TaskService) allowance(ctx context.Context, userID uuid.UUID, chainID int64, token, owner, spender common.Address) (*big.Int, error) {
+	client, err := s.container.RPC.EVMClient(ctx, userID, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := append(append([]byte{}, selectorAllowance...), common.LeftPadBytes(owner.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(spender.Bytes(), 32)...)
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, nil)
+	if err != nil {
+		s.container.RPC.Discard(client)
+		return nil, err
+	}
+	if len(out) < 32 {
+		return nil, errors.New("token did not return an allowance")
+	}
+	return new(big.Int).SetBytes(out[:32]), nil
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/tasks"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+// maxBatchTasks caps one batch; it runs synchronously within a single request
+const maxBatchTasks = 50
+
+// ErrInvalidTaskBatch is returned for a batch that cannot be run as given
+var ErrInvalidTaskBatch = errors.New("invalid task batch")
+
+// ExecuteBatchRequest runs several tasks in order for one wallet or account
+type ExecuteBatchRequest struct {
+	TaskIDs         []uuid.UUID `json:"task_ids" binding:"required"`
+	WalletID        *uuid.UUID  `json:"wallet_id"`
+	AccountID       *uuid.UUID  `json:"account_id"`
+	Force           bool        `json:"force"`             // Run even if dependencies outside the batch are not met
+	ContinueOnError bool        `json:"continue_on_error"` // Keep going after a task fails
+}
+
+// BatchTaskResult is the outcome of one task in a batch. Status is the execution
+// status, or "failed" or "skipped" when no execution finished.
+type BatchTaskResult struct {
+	TaskID      uuid.UUID  `json:"task_id"`
+	TaskName    string     `json:"task_name"`
+	Status      string     `json:"status"`
+	ExecutionID *uuid.UUID `json:"execution_id,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	ErrorCode   string     `json:"error_code,omitempty"`
+}
+
+// BatchResult summarizes a batch run
+type BatchResult struct {
+	Results   []BatchTaskResult `json:"results"`
+	Completed int               `json:"completed"`
+	Failed    int               `json:"failed"`
+	Skipped   int               `json:"skipped"`
+	Stopped   bool              `json:"stopped"` // A failure ended the batch early
+}
+
+// ExecuteBatch runs the tasks one after another through Execute, so each step gets
+// the usual idempotency check, rate limits and account lock. Tasks are reordered
+// only where needed to run a dependency in the batch before its dependents; a task
+// whose in-batch dependency did not complete is skipped. The first failure stops
+// the batch unless ContinueOnError is set. Progress streams to the terminal.
+func (s *TaskService) ExecuteBatch(ctx context.Context, userID uuid.UUID, req *ExecuteBatchRequest) (*BatchResult, error) {
+	if len(req.TaskIDs) == 0 {
+		return nil, fmt.Errorf("%w: no task IDs", ErrInvalidTaskBatch)
+	}
+	if len(req.TaskIDs) > maxBatchTasks {
+		return nil, fmt.Errorf("%w: at most %d tasks per batch", ErrInvalidTaskBatch, maxBatchTasks)
+	}
+	// The batch outlives a dropped client just like a single execution does
+	ctx = context.WithoutCancel(ctx)
+
+	batch := make(map[uuid.UUID]*models.CampaignTask, len(req.TaskIDs))
+	for _, id := range req.TaskIDs {
+		if _, dup := batch[id]; dup {
+			return nil, fmt.Errorf("%w: task %s is listed twice", ErrInvalidTaskBatch, id)
+		}
+		task, err := s.Get(userID, id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: task %s not found", ErrInvalidTaskBatch, id)
+		}
+		batch[id] = task
+	}
+	order, err := batchOrder(req.TaskIDs, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:   "info",
+		Source:  "task",
+		Message: fmt.Sprintf("Running batch of %d tasks", len(order)),
+	})
+
+	result := &BatchResult{Results: make([]BatchTaskResult, 0, len(order))}
+	statuses := make(map[uuid.UUID]string, len(order))
+	for i, task := range order {
+		step := BatchTaskResult{TaskID: task.ID, TaskName: task.Name}
+		prefix := fmt.Sprintf("[%d/%d] ", i+1, len(order))
+
+		skip := ""
+		if result.Stopped {
+			skip = "batch stopped after a failure"
+			step.ErrorCode = tasks.ErrorCodeCancelled
+		} else if task.DependsOn != nil {
+			if status, inBatch := statuses[*task.DependsOn]; inBatch && status != "completed" {
+				skip = fmt.Sprintf("dependency %s did not complete", batch[*task.DependsOn].Name)
+				step.ErrorCode = tasks.ErrorCodeDependencyFailed
+			}
+		}
+		if skip != "" {
+			step.Status = "skipped"
+			step.Error = skip
+			statuses[task.ID] = step.Status
+			result.Skipped++
+			result.Results = append(result.Results, step)
+			s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+				Level:      "warn",
+				Source:     "task",
+				Message:    prefix + "⏭️ Skipped " + task.Name + ": " + skip,
+				TaskID:     task.ID.String(),
+				CampaignID: task.CampaignID.String(),
+			})
+			continue
+		}
+
+		s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+			Level:      "info",
+			Source:     "task",
+			Message:    prefix + task.Name,
+			TaskID:     task.ID.String(),
+			CampaignID: task.CampaignID.String(),
+		})
+		execution, err := s.Execute(ctx, userID, task.ID, &ExecuteTaskRequest{
+			WalletID:  req.WalletID,
+			AccountID: req.AccountID,
+			Force:     req.Force,
+		})
+		if execution != nil {
+			step.ExecutionID = &execution.ID
+			step.Status = string(execution.Status)
+		}
+		switch {
+		case err != nil || step.Status == "failed":
+			// A failed status without an error is an earlier run replayed by the idempotency check
+			step.Status = "failed"
+			if err != nil {
+				step.Error = err.Error()
+				step.ErrorCode = executionErrorCode(err)
+			} else {
+				step.Error = execution.ErrorMessage
+				step.ErrorCode = execution.ErrorCode
+			}
+			result.Failed++
+			if !req.ContinueOnError {
+				result.Stopped = true
+			}
+			// Failures before an execution record exists are not broadcast by Execute
+			if execution == nil {
+				s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+					Level:      "error",
+					Source:     "task",
+					Message:    prefix + "❌ " + task.Name + ": " + err.Error(),
+					TaskID:     task.ID.String(),
+					CampaignID: task.CampaignID.String(),
+				})
+			}
+		case step.Status == "completed":
+			result.Completed++
+		default:
+			// Waiting for manual action or unverified: not a failure, but dependents can't run
+			step.Error = execution.ErrorMessage
+			step.ErrorCode = execution.ErrorCode
+		}
+		statuses[task.ID] = step.Status
+		result.Results = append(result.Results, step)
+	}
+
+	level := "success"
+	if result.Failed > 0 {
+		level = "error"
+	}
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:   level,
+		Source:  "task",
+		Message: fmt.Sprintf("Batch finished: %d completed, %d failed, %d skipped", result.Completed, result.Failed, result.Skipped),
+		Details: map[string]interface{}{
+			"completed": result.Completed,
+			"failed":    result.Failed,
+			"skipped":   result.Skipped,
+			"stopped":   result.Stopped,
+		},
+	})
+
+	return result, nil
+}
+
+// batchOrder keeps the requested order but moves each task's in-batch dependency
+// ahead of it
+func batchOrder(ids []uuid.UUID, batch map[uuid.UUID]*models.CampaignTask) ([]*models.CampaignTask, error) {
+	order := make([]*models.CampaignTask, 0, len(ids))
+	state := make(map[uuid.UUID]int, len(ids)) // 1 visiting, 2 placed
+
+	var visit func(id uuid.UUID) error
+	visit = func(id uuid.UUID) error {
+		switch state[id] {
+		case 1:
+			return fmt.Errorf("%w: task %s and its dependencies form a cycle", ErrInvalidTaskBatch, id)
+		case 2:
+			return nil
+		}
+		state[id] = 1
+		task := batch[id]
+		if task.DependsOn != nil {
+			if _, inBatch := batch[*task.DependsOn]; inBatch {
+				if err := visit(*task.DependsOn); err != nil {
+					return err
+				}
+			}
+		}
+		state[id] = 2
+		order = append(order, task)
+		return nil
+	}
+
+	for _, id := range ids {
+		if err := visit(id); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
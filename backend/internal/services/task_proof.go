@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/storage"
+)
+
+// ErrInvalidScreenshot is returned for a manual proof screenshot that is not a base64 image
+var ErrInvalidScreenshot = errors.New("screenshot must be a base64-encoded PNG, JPEG or WebP image")
+
+// maxScreenshotBytes caps a decoded manual proof screenshot
+const maxScreenshotBytes = 10 << 20
+
+var screenshotExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+}
+
+// storeScreenshotProof saves a base64 screenshot submitted with a manual
+// completion and returns its storage key. Data URLs are accepted.
+func (s *TaskService) storeScreenshotProof(ctx context.Context, execution *models.TaskExecution, encoded string) (string, error) {
+	if i := strings.Index(encoded, ","); strings.HasPrefix(encoded, "data:") && i >= 0 {
+		encoded = encoded[i+1:]
+	}
+	if base64.StdEncoding.DecodedLen(len(encoded)) > maxScreenshotBytes {
+		return "", ErrInvalidScreenshot
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(data) == 0 {
+		return "", ErrInvalidScreenshot
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := screenshotExtensions[contentType]
+	if !ok {
+		return "", ErrInvalidScreenshot
+	}
+
+	key := fmt.Sprintf("proofs/%s/manual_%s%s", execution.ID, time.Now().Format("20060102_150405"), ext)
+	if err := s.container.Storage.Put(ctx, key, data, contentType); err != nil {
+		return "", fmt.Errorf("failed to save screenshot: %w", err)
+	}
+	return key, nil
+}
+
+// signScreenshotURLs fills in short-lived URLs for executions' stored
+// screenshots. Paths that are not storage keys, such as external URLs, are left alone.
+func (s *TaskService) signScreenshotURLs(ctx context.Context, executions []models.TaskExecution) {
+	for i := range executions {
+		if executions[i].ScreenshotPath == "" {
+			continue
+		}
+		url, err := s.container.Storage.SignedURL(ctx, storage.Key(executions[i].ScreenshotPath), s.container.Config.StorageURLTTL)
+		if err == nil {
+			executions[i].ScreenshotURL = url
+		}
+	}
+}
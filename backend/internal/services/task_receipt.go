@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/services/platforms"
+	"github.com/web3airdropos/backend/internal/tasks"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+// receiptPollBatch is how many pending transactions one poll checks, oldest first
+const receiptPollBatch = 100
+
+// awaitReceipt leaves an execution whose transaction was just sent verifying
+// until PollTransactionReceipts sees the transaction mined
+func (s *TaskService) awaitReceipt(userID uuid.UUID, task *models.CampaignTask, execution *models.TaskExecution) error {
+	now := time.Now()
+	execution.Status = models.ExecutionVerifying
+	execution.CompletedAt = nil
+	execution.ErrorMessage = ""
+	execution.ErrorCode = ""
+	execution.TxStatus = models.TxPending
+	execution.TxSubmittedAt = &now
+	if err := s.container.DB.Save(execution).Error; err != nil {
+		return err
+	}
+
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:      "info",
+		Source:     "task",
+		Message:    "⏳ Waiting for transaction to be mined: " + execution.TransactionHash,
+		TaskID:     task.ID.String(),
+		CampaignID: task.CampaignID.String(),
+	})
+	s.container.WSHub.BroadcastTaskUpdate(userID.String(), websocket.TaskStatusUpdate{
+		TaskID:     task.ID.String(),
+		CampaignID: task.CampaignID.String(),
+		Status:     string(models.ExecutionVerifying),
+		Message:    "Waiting for transaction confirmation",
+	})
+	return nil
+}
+
+// PollTransactionReceipts checks the receipts of executions whose transaction is
+// still pending. A mined transaction completes its execution, or fails it when
+// it reverted; one not mined within TxReceiptTimeout fails as dropped.
+func (s *TaskService) PollTransactionReceipts(ctx context.Context) error {
+	var pending []models.TaskExecution
+	if err := s.container.DB.WithContext(ctx).
+		Where("tx_status = ?", models.TxPending).
+		Order("tx_submitted_at ASC").
+		Limit(receiptPollBatch).
+		Find(&pending).Error; err != nil {
+		return err
+	}
+
+	for i := range pending {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.checkReceipt(ctx, &pending[i]); err != nil {
+			log.Printf("⚠️ Failed to check transaction %s: %v", pending[i].TransactionHash, err)
+		}
+	}
+	return nil
+}
+
+// checkReceipt looks up the receipt of one pending execution's transaction
+func (s *TaskService) checkReceipt(ctx context.Context, execution *models.TaskExecution) error {
+	var task models.CampaignTask
+	if err := s.container.DB.First(&task, "id = ?", execution.TaskID).Error; err != nil {
+		return err
+	}
+	var campaign models.Campaign
+	if err := s.container.DB.Select("id", "user_id").First(&campaign, "id = ?", task.CampaignID).Error; err != nil {
+		return err
+	}
+	userID := campaign.UserID
+
+	// Server-signed transactions are recorded with their chain; browser-signed
+	// ones are assumed to be on the chain the task names
+	chainID := taskChainID(&task)
+	var sent models.Transaction
+	if err := s.container.DB.Select("chain_id").Where("hash = ?", execution.TransactionHash).First(&sent).Error; err == nil && sent.ChainID != 0 {
+		chainID = int64(sent.ChainID)
+	}
+
+	client, err := s.container.RPC.EVMClient(ctx, userID, chainID)
+	if err != nil {
+		return err
+	}
+	receipt, err := client.TransactionReceipt(ctx, common.HexToHash(execution.TransactionHash))
+	if errors.Is(err, ethereum.NotFound) {
+		submitted := execution.CreatedAt
+		if execution.TxSubmittedAt != nil {
+			submitted = *execution.TxSubmittedAt
+		}
+		if timeout := s.container.Config.TxReceiptTimeout; timeout > 0 && time.Since(submitted) > timeout {
+			return s.settleTransaction(ctx, userID, &task, execution, models.TxDropped, nil)
+		}
+		return nil
+	}
+	if err != nil {
+		s.container.RPC.Discard(client)
+		return err
+	}
+
+	status := models.TxConfirmed
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		status = models.TxReverted
+	}
+	return s.settleTransaction(ctx, userID, &task, execution, status, receipt)
+}
+
+// settleTransaction records a transaction's outcome on its execution and in the
+// wallet's transaction history, then completes or fails the execution. Only the
+// first poller to settle a transaction acts on it.
+func (s *TaskService) settleTransaction(ctx context.Context, userID uuid.UUID, task *models.CampaignTask, execution *models.TaskExecution, status models.TxStatus, receipt *types.Receipt) error {
+	now := time.Now()
+	updates := map[string]interface{}{"tx_status": status}
+	switch status {
+	case models.TxConfirmed:
+		updates["status"] = models.ExecutionCompleted
+		updates["completed_at"] = now
+		updates["error_message"] = ""
+		updates["error_code"] = ""
+	case models.TxReverted:
+		updates["status"] = models.ExecutionFailed
+		updates["error_message"] = fmt.Sprintf("transaction %s reverted", execution.TransactionHash)
+		updates["error_code"] = tasks.ErrorCodeTxReverted
+	case models.TxDropped:
+		updates["status"] = models.ExecutionFailed
+		updates["error_message"] = fmt.Sprintf("transaction %s was not mined within %s; it was dropped or replaced",
+			execution.TransactionHash, s.container.Config.TxReceiptTimeout)
+		updates["error_code"] = tasks.ErrorCodeTxDropped
+	}
+	if receipt != nil {
+		updates["gas_used"] = receipt.GasUsed
+		updates["block_number"] = receipt.BlockNumber.Uint64()
+	}
+
+	result := s.container.DB.Model(&models.TaskExecution{}).
+		Where("id = ? AND tx_status = ?", execution.ID, models.TxPending).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+	if err := s.container.DB.First(execution, "id = ?", execution.ID).Error; err != nil {
+		return err
+	}
+	s.recordTransactionOutcome(execution.TransactionHash, status, receipt)
+
+	proof := &platforms.ActionProof{TxHash: execution.TransactionHash, Timestamp: now.Unix()}
+	if status != models.TxConfirmed {
+		if s.audit != nil {
+			s.audit.LogTaskExecution(ctx, execution, task, models.ResultFailed, proof, errors.New(execution.ErrorMessage))
+		}
+		s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+			Level:      "error",
+			Source:     "task",
+			Message:    "❌ Task failed: " + execution.ErrorMessage,
+			TaskID:     task.ID.String(),
+			CampaignID: task.CampaignID.String(),
+		})
+		s.container.WSHub.BroadcastTaskUpdate(userID.String(), websocket.TaskStatusUpdate{
+			TaskID:     task.ID.String(),
+			CampaignID: task.CampaignID.String(),
+			Status:     string(models.ExecutionFailed),
+			Message:    execution.ErrorMessage,
+		})
+		s.notifyTask(userID, models.WebhookEventTaskFailed, task, execution)
+		return nil
+	}
+
+	if s.audit != nil {
+		s.audit.LogTaskExecution(ctx, execution, task, models.ResultSuccess, proof, nil)
+	}
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:      "success",
+		Source:     "task",
+		Message:    fmt.Sprintf("✅ Task completed: %s (transaction confirmed in block %d)", task.Name, execution.BlockNumber),
+		TaskID:     task.ID.String(),
+		CampaignID: task.CampaignID.String(),
+		Details: map[string]interface{}{
+			"transaction_hash": execution.TransactionHash,
+			"block_number":     execution.BlockNumber,
+			"gas_used":         execution.GasUsed,
+		},
+	})
+	s.container.WSHub.BroadcastTaskUpdate(userID.String(), websocket.TaskStatusUpdate{
+		TaskID:     task.ID.String(),
+		CampaignID: task.CampaignID.String(),
+		Status:     string(models.ExecutionCompleted),
+		Message:    "Transaction confirmed",
+	})
+	s.notifyTask(userID, models.WebhookEventTaskCompleted, task, execution)
+	s.container.Campaign.completeIfDone(userID, task.CampaignID)
+	return nil
+}
+
+// recordTransactionOutcome updates the wallet's transaction history, when the
+// transaction was sent from a stored wallet
+func (s *TaskService) recordTransactionOutcome(hash string, status models.TxStatus, receipt *types.Receipt) {
+	updates := map[string]interface{}{}
+	switch status {
+	case models.TxConfirmed:
+		updates["status"] = "success"
+	case models.TxReverted:
+		updates["status"] = "failed"
+	case models.TxDropped:
+		updates["status"] = "dropped"
+	}
+	if receipt != nil {
+		updates["gas_used"] = fmt.Sprintf("%d", receipt.GasUsed)
+		updates["block_number"] = receipt.BlockNumber.Int64()
+		if receipt.EffectiveGasPrice != nil {
+			updates["gas_price"] = receipt.EffectiveGasPrice.String()
+		}
+	}
+	if err := s.container.DB.Model(&models.Transaction{}).Where("hash = ?", hash).Updates(updates).Error; err != nil {
+		log.Printf("⚠️ Failed to record outcome of transaction %s: %v", hash, err)
+	}
+}
+
+// taskChainID is the chain a transaction or approve task sends on
+func taskChainID(task *models.CampaignTask) int64 {
+	var cfg struct {
+		ChainID int64 `json:"chain_id"`
+	}
+	json.Unmarshal([]byte(task.Config), &cfg)
+	if cfg.ChainID == 0 {
+		return 1
+	}
+	return cfg.ChainID
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+var (
+	ErrTaskNotFound                 = errors.New("task not found")
+	ErrScheduledExecutionNotFound   = errors.New("scheduled execution not found")
+	ErrScheduledExecutionNotPending = errors.New("scheduled execution is no longer pending")
+)
+
+type ScheduleExecutionRequest struct {
+	ExecuteTaskRequest
+	RunAt time.Time `json:"run_at" binding:"required"`
+}
+
+// ScheduleExecution stores an execution of the task for runAt. The scheduler's
+// job checker runs it through Execute once that time passes.
+func (s *TaskService) ScheduleExecution(userID, taskID uuid.UUID, runAt time.Time, req *ExecuteTaskRequest) (*models.ScheduledTaskExecution, error) {
+	if !runAt.After(time.Now()) {
+		return nil, ErrScheduleInPast
+	}
+	task, err := s.Get(userID, taskID)
+	if err != nil {
+		return nil, ErrTaskNotFound
+	}
+
+	scheduled := &models.ScheduledTaskExecution{
+		ID:        uuid.New(),
+		UserID:    userID,
+		TaskID:    task.ID,
+		WalletID:  req.WalletID,
+		AccountID: req.AccountID,
+		Force:     req.Force,
+		RunAt:     runAt,
+		Status:    "pending",
+	}
+	if err := s.container.DB.Create(scheduled).Error; err != nil {
+		return nil, err
+	}
+
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:      "info",
+		Source:     "task",
+		Message:    fmt.Sprintf("Scheduled %s for %s", task.Name, runAt.Format(time.RFC3339)),
+		TaskID:     task.ID.String(),
+		CampaignID: task.CampaignID.String(),
+	})
+	return scheduled, nil
+}
+
+// ListScheduled returns the task's scheduled executions, soonest first
+func (s *TaskService) ListScheduled(userID, taskID uuid.UUID) ([]models.ScheduledTaskExecution, error) {
+	if _, err := s.Get(userID, taskID); err != nil {
+		return nil, ErrTaskNotFound
+	}
+
+	var scheduled []models.ScheduledTaskExecution
+	if err := s.container.DB.Where("task_id = ? AND user_id = ?", taskID, userID).
+		Order("run_at ASC").
+		Find(&scheduled).Error; err != nil {
+		return nil, err
+	}
+	return scheduled, nil
+}
+
+// CancelScheduled cancels a pending scheduled execution. One that has already
+// started, finished or been cancelled returns ErrScheduledExecutionNotPending.
+func (s *TaskService) CancelScheduled(userID, scheduledID uuid.UUID) error {
+	var scheduled models.ScheduledTaskExecution
+	if err := s.container.DB.Where("id = ? AND user_id = ?", scheduledID, userID).First(&scheduled).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrScheduledExecutionNotFound
+		}
+		return err
+	}
+
+	// The status condition guards against the scheduler picking it up meanwhile
+	result := s.container.DB.Model(&models.ScheduledTaskExecution{}).
+		Where("id = ? AND status = ?", scheduled.ID, "pending").
+		Updates(map[string]interface{}{"status": "cancelled", "updated_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: status is %s", ErrScheduledExecutionNotPending, scheduled.Status)
+	}
+
+	s.container.WSHub.BroadcastToUser(userID.String(), "task:schedule_cancelled", map[string]string{
+		"id":      scheduled.ID.String(),
+		"task_id": scheduled.TaskID.String(),
+	})
+	return nil
+}
+
+// RunScheduled executes a scheduled execution the scheduler has claimed, and
+// records the outcome on it
+func (s *TaskService) RunScheduled(ctx context.Context, scheduled *models.ScheduledTaskExecution) error {
+	execution, err := s.Execute(ctx, scheduled.UserID, scheduled.TaskID, &ExecuteTaskRequest{
+		WalletID:  scheduled.WalletID,
+		AccountID: scheduled.AccountID,
+		Force:     scheduled.Force,
+	})
+
+	now := time.Now()
+	updates := map[string]interface{}{"status": "completed", "ran_at": now, "updated_at": now}
+	if execution != nil {
+		updates["execution_id"] = execution.ID
+	}
+	if err != nil {
+		updates["status"] = "failed"
+		updates["error_message"] = err.Error()
+	}
+	if dbErr := s.container.DB.Model(scheduled).Updates(updates).Error; dbErr != nil && err == nil {
+		return dbErr
+	}
+	return err
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/services/platforms"
+	"github.com/web3airdropos/backend/internal/tasks"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+// errTransactionNeedsBrowser is returned for a transaction task whose config
+// names a function without calldata, which only the browser can build
+var errTransactionNeedsBrowser = errors.New("transaction must be built and signed in the browser")
+
+// transactionConfig is the task config of a transaction task. Value is in wei
+// and Data is hex calldata; To may be given as contract_address.
+type transactionConfig struct {
+	ContractAddress string `json:"contract_address"`
+	To              string `json:"to"`
+	ChainID         int64  `json:"chain_id"`
+	Value           string `json:"value"`
+	Data            string `json:"data"`
+	FunctionName    string `json:"function_name"`
+	GasLimit        uint64 `json:"gas_limit"`
+}
+
+func parseTransactionConfig(raw string) (*transactionConfig, error) {
+	var cfg transactionConfig
+	if raw == "" {
+		return nil, errors.New("transaction task has no config")
+	}
+	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+		return nil, fmt.Errorf("invalid transaction task config: %w", err)
+	}
+	if cfg.To == "" {
+		cfg.To = cfg.ContractAddress
+	}
+	if !common.IsHexAddress(cfg.To) {
+		return nil, errors.New("transaction task needs a to or contract_address")
+	}
+	cfg.Data = strings.TrimPrefix(cfg.Data, "0x")
+	if cfg.Data == "" && cfg.FunctionName != "" {
+		return nil, errTransactionNeedsBrowser
+	}
+	if cfg.ChainID == 0 {
+		cfg.ChainID = 1
+	}
+	return &cfg, nil
+}
+
+// needsBrowserSigning reports whether a transaction task has to fall back to the
+// user's browser rather than failing
+func needsBrowserSigning(err error) bool {
+	return errors.Is(err, ErrServerSigningDisabled) ||
+		errors.Is(err, ErrSigningUnsupported) ||
+		errors.Is(err, errTransactionNeedsBrowser)
+}
+
+// sendTaskTransaction signs a transaction task with the wallet's stored key and
+// broadcasts it. The wallet must allow server signing.
+func (s *TaskService) sendTaskTransaction(ctx context.Context, userID uuid.UUID, task *models.CampaignTask, walletID uuid.UUID) (*platforms.ActionProof, error) {
+	var wallet models.Wallet
+	if err := s.container.DB.Where("id = ? AND user_id = ?", walletID, userID).First(&wallet).Error; err != nil {
+		return nil, err
+	}
+	if wallet.Type != models.WalletTypeEVM {
+		return nil, ErrSigningUnsupported
+	}
+	if err := canServerSign(&wallet); err != nil {
+		return nil, err
+	}
+	cfg, err := parseTransactionConfig(task.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	// SendTransaction audits the send against the task
+	hash, err := s.container.Wallet.SendTransaction(userID, wallet.ID, &PrepareTransactionRequest{
+		ChainID:  cfg.ChainID,
+		To:       cfg.To,
+		Value:    cfg.Value,
+		Data:     cfg.Data,
+		GasLimit: cfg.GasLimit,
+	}, &task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &platforms.ActionProof{TxHash: hash, Timestamp: time.Now().Unix()}
+
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:      "success",
+		Source:     "task",
+		Message:    "Transaction sent: " + hash,
+		TaskID:     task.ID.String(),
+		CampaignID: task.CampaignID.String(),
+	})
+	return proof, nil
+}
+
+// TransactionExecutor runs transaction tasks for the TaskManager. Wallets that
+// allow server signing send the transaction directly; the rest are left for the
+// user to sign, as the execution then waits in waiting_manual.
+type TransactionExecutor struct {
+	services *Container
+}
+
+func NewTransactionExecutor(c *Container) *TransactionExecutor {
+	return &TransactionExecutor{services: c}
+}
+
+// CanHandle reports whether taskType is a transaction task
+func (e *TransactionExecutor) CanHandle(taskType string) bool {
+	return taskType == string(models.TaskTypeTransaction)
+}
+
+// Execute sends the task's transaction from the request's wallet
+func (e *TransactionExecutor) Execute(ctx context.Context, req *tasks.ExecutionRequest) (*tasks.ExecutionResult, error) {
+	if req.WalletID == nil {
+		return nil, errors.New("wallet ID required for transaction task")
+	}
+	task, err := e.services.Task.Get(req.UserID, req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := e.services.Task.sendTaskTransaction(ctx, req.UserID, task, *req.WalletID)
+	if err != nil {
+		if needsBrowserSigning(err) {
+			e.services.WSHub.BroadcastTerminal(req.UserID.String(), websocket.TerminalMessage{
+				Level:      "warn",
+				Source:     "task",
+				Message:    "⚠️ Transaction needs your signature: " + err.Error(),
+				TaskID:     task.ID.String(),
+				CampaignID: task.CampaignID.String(),
+			})
+			return nil, fmt.Errorf("%w: %v", tasks.ErrManualRequired, err)
+		}
+		return nil, err
+	}
+
+	return &tasks.ExecutionResult{
+		Execution: &tasks.TaskExecution{TransactionHash: proof.TxHash},
+		Proof: &tasks.TaskProof{
+			Type:      tasks.ProofTypeTxHash,
+			Value:     proof.TxHash,
+			Timestamp: time.Unix(proof.Timestamp, 0),
+		},
+	}, nil
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/netguard"
+	"github.com/web3airdropos/backend/internal/services/platforms"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+// ErrVerificationFailed is returned when a verify task finds the checked action
+// has not happened
+var ErrVerificationFailed = errors.New("verification failed")
+
+// Verification strategies of a verify task
+const (
+	VerifySocial      = "social"      // Ask the platform adapter whether the action exists
+	VerifyTransaction = "transaction" // Check the transaction receipt on-chain
+	VerifyURL         = "url"         // Fetch a page and look for a marker
+)
+
+// verifyPageLimit caps how much of a page a URL verification reads
+const verifyPageLimit = 2 << 20
+
+// verifyClient fetches verification pages; like webhooks, they may not be on
+// the server's own network
+var verifyClient = netguard.Client(0, true)
+
+// verifyConfig is the task config of a verify task. With no strategy it is
+// inferred: a tx hash means transaction, a marker means url, otherwise social.
+// Social and transaction checks fall back to the proof of the completed
+// dependency task when no post or tx hash is given.
+type verifyConfig struct {
+	Strategy string `json:"strategy"`
+
+	// social
+	Action string `json:"action"` // follow, like, recast, post...; defaults to the dependency's type
+	PostID string `json:"post_id"`
+
+	// transaction
+	TxHash        string `json:"tx_hash"`
+	ChainID       int64  `json:"chain_id"`
+	Confirmations uint64 `json:"confirmations"`
+
+	// url
+	URL    string `json:"url"`
+	Marker string `json:"marker"`
+}
+
+// executeVerify checks that the action a verify task describes has happened,
+// returning the proof of it
+func (s *TaskService) executeVerify(ctx context.Context, userID uuid.UUID, task *models.CampaignTask, execution *models.TaskExecution) (*platforms.ActionProof, error) {
+	var cfg verifyConfig
+	if task.Config != "" {
+		if err := json.Unmarshal([]byte(task.Config), &cfg); err != nil {
+			return nil, fmt.Errorf("invalid verify config: %w", err)
+		}
+	}
+
+	dependency := s.dependencyExecution(task)
+	if cfg.Strategy == "" {
+		switch {
+		case cfg.TxHash != "" || (dependency != nil && dependency.TransactionHash != ""):
+			cfg.Strategy = VerifyTransaction
+		case cfg.Marker != "":
+			cfg.Strategy = VerifyURL
+		default:
+			cfg.Strategy = VerifySocial
+		}
+	}
+
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:      "info",
+		Source:     "task",
+		Message:    "Verifying " + task.Name + " (" + cfg.Strategy + ")",
+		TaskID:     task.ID.String(),
+		CampaignID: task.CampaignID.String(),
+	})
+
+	switch cfg.Strategy {
+	case VerifySocial:
+		return s.verifySocial(ctx, task, &cfg, dependency)
+	case VerifyTransaction:
+		return s.verifyTransaction(ctx, userID, task, &cfg, dependency)
+	case VerifyURL:
+		return s.verifyURL(ctx, task, &cfg)
+	default:
+		return nil, fmt.Errorf("unknown verification strategy %q", cfg.Strategy)
+	}
+}
+
+// dependencyExecution returns the latest completed execution of the task this
+// one depends on, if any
+func (s *TaskService) dependencyExecution(task *models.CampaignTask) *models.TaskExecution {
+	if task.DependsOn == nil {
+		return nil
+	}
+	var execution models.TaskExecution
+	err := s.container.DB.Preload("Task").
+		Where("task_id = ? AND status IN ?", task.DependsOn, models.ExecutionStatusValues(models.ExecutionCompleted)).
+		Order("completed_at DESC").
+		First(&execution).Error
+	if err != nil {
+		return nil
+	}
+	return &execution
+}
+
+// verifySocial asks the platform adapter to confirm a follow, reaction or post
+func (s *TaskService) verifySocial(ctx context.Context, task *models.CampaignTask, cfg *verifyConfig, dependency *models.TaskExecution) (*platforms.ActionProof, error) {
+	adapter, err := s.GetAdapter(task.TargetPlatform)
+	if err != nil {
+		return nil, err
+	}
+
+	action := cfg.Action
+	postID := cfg.PostID
+	if dependency != nil {
+		if action == "" && dependency.Task != nil {
+			action = string(dependency.Task.Type)
+		}
+		if postID == "" {
+			postID = dependency.PostID
+		}
+	}
+	if postID == "" {
+		postID = task.TargetURL
+	}
+	if action == "" {
+		return nil, errors.New("verify config needs an action for social verification")
+	}
+	if action == string(models.TaskTypeFollow) {
+		if task.TargetAccount == "" {
+			return nil, errors.New("no target account to verify the follow against")
+		}
+	} else if postID == "" {
+		return nil, errors.New("no post to verify")
+	}
+
+	proof := &platforms.ActionProof{
+		PostID:    postID,
+		CastHash:  postID,
+		Timestamp: time.Now().Unix(),
+		Metadata: map[string]string{
+			"strategy":          VerifySocial,
+			"action":            action,
+			"target_fid":        task.TargetAccount,
+			"target_profile_id": task.TargetAccount,
+		},
+	}
+	verified, err := adapter.VerifyAction(ctx, action, proof)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrVerificationFailed, err)
+	}
+	if !verified {
+		return nil, fmt.Errorf("%w: %s not found on %s", ErrVerificationFailed, action, task.TargetPlatform)
+	}
+	if action == string(models.TaskTypeFollow) {
+		proof.PostID, proof.CastHash = "", ""
+	}
+	return proof, nil
+}
+
+// verifyTransaction checks a transaction was mined and succeeded
+func (s *TaskService) verifyTransaction(ctx context.Context, userID uuid.UUID, task *models.CampaignTask, cfg *verifyConfig, dependency *models.TaskExecution) (*platforms.ActionProof, error) {
+	txHash := cfg.TxHash
+	if txHash == "" && dependency != nil {
+		txHash = dependency.TransactionHash
+	}
+	if txHash == "" {
+		return nil, errors.New("verify config needs a tx_hash for transaction verification")
+	}
+	if len(strings.TrimPrefix(txHash, "0x")) != 64 {
+		return nil, fmt.Errorf("invalid tx_hash %q", txHash)
+	}
+	chainID := cfg.ChainID
+	if chainID == 0 {
+		chainID = 1
+	}
+
+	client, err := s.container.RPC.EVMClient(ctx, userID, chainID)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := client.TransactionReceipt(ctx, common.HexToHash(txHash))
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, fmt.Errorf("%w: transaction %s not mined on chain %d", ErrVerificationFailed, txHash, chainID)
+	}
+	if err != nil {
+		s.container.RPC.Discard(client)
+		return nil, err
+	}
+	if receipt.Status != 1 {
+		return nil, fmt.Errorf("%w: transaction %s reverted", ErrVerificationFailed, txHash)
+	}
+
+	if cfg.Confirmations > 0 {
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			s.container.RPC.Discard(client)
+			return nil, err
+		}
+		if mined := receipt.BlockNumber.Uint64(); head < mined || head-mined+1 < cfg.Confirmations {
+			return nil, fmt.Errorf("%w: transaction %s has fewer than %d confirmations", ErrVerificationFailed, txHash, cfg.Confirmations)
+		}
+	}
+
+	return &platforms.ActionProof{
+		TxHash:    txHash,
+		Timestamp: time.Now().Unix(),
+		Metadata: map[string]string{
+			"strategy":     VerifyTransaction,
+			"chain_id":     fmt.Sprintf("%d", chainID),
+			"block_number": receipt.BlockNumber.String(),
+		},
+	}, nil
+}
+
+// verifyURL fetches a page and checks it contains the expected marker
+func (s *TaskService) verifyURL(ctx context.Context, task *models.CampaignTask, cfg *verifyConfig) (*platforms.ActionProof, error) {
+	pageURL := cfg.URL
+	if pageURL == "" {
+		pageURL = task.TargetURL
+	}
+	if !validWebhookURL(pageURL) {
+		return nil, fmt.Errorf("invalid verification URL %q", pageURL)
+	}
+	if cfg.Marker == "" {
+		return nil, errors.New("verify config needs a marker for URL verification")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := verifyClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s returned status %d", ErrVerificationFailed, pageURL, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, verifyPageLimit))
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(string(body), cfg.Marker) {
+		return nil, fmt.Errorf("%w: marker not found on %s", ErrVerificationFailed, pageURL)
+	}
+
+	return &platforms.ActionProof{
+		PostURL:   pageURL,
+		Timestamp: time.Now().Unix(),
+		Metadata:  map[string]string{"strategy": VerifyURL},
+	}, nil
+}
@@ -12,8 +12,10 @@ import (
 	"fmt"
 	"io"
 	"math/big"
+	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -65,9 +67,16 @@ type PreparedTransaction struct {
 	SignURL       string `json:"sign_url"` // URL to open in browser for signing
 }
 
+// TransactionEstimate is the gas a transaction would use and its cost in wei
+type TransactionEstimate struct {
+	GasLimit uint64 `json:"gas_limit"`
+	GasPrice string `json:"gas_price"`
+	Cost     string `json:"cost"`
+}
+
 func (s *WalletService) List(userID uuid.UUID, walletType string, groupID *uuid.UUID) ([]models.Wallet, error) {
 	var wallets []models.Wallet
-	query := s.container.DB.Where("user_id = ?", userID).Preload("Tags").Preload("Groups")
+	query := s.container.Reader.Where("user_id = ?", userID).Preload("Tags").Preload("Groups")
 	
 	if walletType != "" {
 		query = query.Where("type = ?", walletType)
@@ -111,6 +120,7 @@ func (s *WalletService) Create(userID uuid.UUID, req *CreateWalletRequest) (*mod
 
 	// Broadcast wallet created event
 	s.container.WSHub.BroadcastToUser(userID.String(), "wallet:created", wallet)
+	s.container.Dashboard.InvalidateStats(userID)
 
 	return wallet, nil
 }
@@ -198,9 +208,12 @@ func (s *WalletService) Import(userID uuid.UUID, req *ImportWalletRequest) (*mod
 
 	address := crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
 
-	// Check if wallet already exists
+	// Check if wallet already exists; a deleted one still holds its address until purged
 	var existing models.Wallet
-	if err := s.container.DB.Where("address = ?", address).First(&existing).Error; err == nil {
+	if err := s.container.DB.Unscoped().Where("address = ?", address).First(&existing).Error; err == nil {
+		if existing.DeletedAt.Valid && existing.UserID == userID {
+			return nil, ErrWalletDeleted
+		}
 		return nil, errors.New("wallet already imported")
 	}
 
@@ -229,6 +242,7 @@ func (s *WalletService) Import(userID uuid.UUID, req *ImportWalletRequest) (*mod
 	// Sync balance
 	go s.SyncBalance(wallet.ID)
 
+	s.container.Dashboard.InvalidateStats(userID)
 	return wallet, nil
 }
 
@@ -251,12 +265,19 @@ func (s *WalletService) Update(userID, walletID uuid.UUID, updates map[string]in
 	}
 
 	// Only allow certain fields to be updated
-	allowedFields := map[string]bool{"name": true}
+	allowedFields := map[string]bool{"name": true, "allow_server_signing": true, "chain_id": true, "extra_chain_ids": true}
 	for key := range updates {
 		if !allowedFields[key] {
 			delete(updates, key)
 		}
 	}
+	if raw, ok := updates["extra_chain_ids"]; ok {
+		chains, err := parseChainIDs(raw)
+		if err != nil {
+			return nil, err
+		}
+		updates["extra_chain_ids"] = chains
+	}
 
 	if err := s.container.DB.Model(&wallet).Updates(updates).Error; err != nil {
 		return nil, err
@@ -265,76 +286,163 @@ func (s *WalletService) Update(userID, walletID uuid.UUID, updates map[string]in
 	return &wallet, nil
 }
 
-func (s *WalletService) Delete(userID, walletID uuid.UUID) error {
-	return s.container.DB.Where("id = ? AND user_id = ?", walletID, userID).Delete(&models.Wallet{}).Error
+// parseChainIDs validates a JSON array of chain IDs and returns it re-encoded
+func parseChainIDs(raw interface{}) (string, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return "", err
+	}
+	var chains []int64
+	if err := json.Unmarshal(data, &chains); err != nil {
+		return "", errors.New("extra_chain_ids must be an array of chain IDs")
+	}
+	if chains == nil {
+		chains = []int64{}
+	}
+	for _, chainID := range chains {
+		if chainID <= 0 {
+			return "", fmt.Errorf("invalid chain ID: %d", chainID)
+		}
+	}
+	encoded, _ := json.Marshal(chains)
+	return string(encoded), nil
 }
 
-func (s *WalletService) GetBalance(walletID uuid.UUID) (*models.WalletBalance, error) {
+// Delete soft-deletes a wallet; it can be restored until the purge after the
+// retention period. A wallet holding an imported key is only deleted when
+// confirm repeats its address, as its key exists nowhere else.
+func (s *WalletService) Delete(userID, walletID uuid.UUID, confirm string) error {
 	var wallet models.Wallet
-	if err := s.container.DB.First(&wallet, walletID).Error; err != nil {
-		return nil, err
+	if err := s.container.DB.Where("id = ? AND user_id = ?", walletID, userID).First(&wallet).Error; err != nil {
+		return err
+	}
+	if wallet.IsImported && wallet.EncryptedKey != "" && !strings.EqualFold(confirm, wallet.Address) {
+		return ErrDeleteConfirmationRequired
 	}
 
-	// Try to get from cache first
-	ctx := context.Background()
-	cacheKey := fmt.Sprintf("wallet:balance:%s", wallet.Address)
-	cached, err := s.container.Redis.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var balance models.WalletBalance
-		if json.Unmarshal([]byte(cached), &balance) == nil {
-			return &balance, nil
+	if err := s.container.DB.Delete(&wallet).Error; err != nil {
+		return err
+	}
+
+	s.container.Audit.Log(context.Background(), &LogEntry{
+		UserID:     userID,
+		Action:     models.ActionWalletDelete,
+		Result:     models.ResultSuccess,
+		WalletID:   &wallet.ID,
+		Platform:   string(wallet.Type),
+		TargetType: "wallet",
+		TargetID:   wallet.Address,
+		RequestData: map[string]interface{}{
+			"name":        wallet.Name,
+			"is_imported": wallet.IsImported,
+			"purge_after": time.Now().Add(s.container.Config.SoftDeleteRetention),
+		},
+	})
+	s.container.WSHub.BroadcastToUser(userID.String(), "wallet:deleted", map[string]string{"id": walletID.String()})
+	s.container.Dashboard.InvalidateStats(userID)
+	return nil
+}
+
+// balanceCacheTTL is how long a fetched balance is served from Redis
+const balanceCacheTTL = 30 * time.Second
+
+// BalanceCacheKey is the Redis key of a wallet's cached balance on one chain
+func BalanceCacheKey(address string, chainID int64) string {
+	return fmt.Sprintf("wallet:balance:%s:%d", address, chainID)
+}
+
+// WalletChains lists the chains a wallet is active on, its default chain first.
+// Non-EVM wallets only have their default chain.
+func WalletChains(wallet *models.Wallet) []int64 {
+	chains := []int64{int64(wallet.ChainID)}
+	if wallet.Type != models.WalletTypeEVM || wallet.ExtraChainIDs == "" {
+		return chains
+	}
+
+	var extra []int64
+	json.Unmarshal([]byte(wallet.ExtraChainIDs), &extra)
+	for _, chainID := range extra {
+		if chainID != chains[0] {
+			chains = append(chains, chainID)
 		}
 	}
+	return chains
+}
 
-	// Fetch fresh balance
-	balance, err := s.fetchBalance(&wallet)
-	if err != nil {
+// GetBalance returns the wallet's balance on its default chain
+func (s *WalletService) GetBalance(walletID uuid.UUID) (*models.WalletBalance, error) {
+	var wallet models.Wallet
+	if err := s.container.DB.First(&wallet, walletID).Error; err != nil {
 		return nil, err
 	}
 
-	// Cache for 30 seconds
-	if data, err := json.Marshal(balance); err == nil {
-		s.container.Redis.Set(ctx, cacheKey, data, 30*time.Second)
+	return s.chainBalance(context.Background(), &wallet, int64(wallet.ChainID)), nil
+}
+
+// GetBalances returns the wallet's balance on each of chainIDs, or on every chain
+// it is active on when none are given. A chain that cannot be read carries its
+// error in the result instead of failing the call.
+func (s *WalletService) GetBalances(walletID uuid.UUID, chainIDs []int64) ([]models.WalletBalance, error) {
+	var wallet models.Wallet
+	if err := s.container.DB.First(&wallet, walletID).Error; err != nil {
+		return nil, err
+	}
+	if len(chainIDs) == 0 {
+		chainIDs = WalletChains(&wallet)
 	}
 
-	return balance, nil
+	ctx := context.Background()
+	balances := make([]models.WalletBalance, 0, len(chainIDs))
+	for _, chainID := range chainIDs {
+		balances = append(balances, *s.chainBalance(ctx, &wallet, chainID))
+	}
+	return balances, nil
 }
 
-func (s *WalletService) fetchBalance(wallet *models.Wallet) (*models.WalletBalance, error) {
+// chainBalance returns the wallet's balance on one chain, from cache if fresh.
+// Failed reads are not cached.
+func (s *WalletService) chainBalance(ctx context.Context, wallet *models.Wallet, chainID int64) *models.WalletBalance {
+	cacheKey := BalanceCacheKey(wallet.Address, chainID)
+	if cached, err := s.container.Redis.Get(ctx, cacheKey).Result(); err == nil {
+		var balance models.WalletBalance
+		if json.Unmarshal([]byte(cached), &balance) == nil {
+			return &balance
+		}
+	}
+
 	balance := &models.WalletBalance{
 		Address:   wallet.Address,
+		ChainID:   chainID,
 		UpdatedAt: time.Now(),
 	}
-
-	if wallet.Type == models.WalletTypeEVM {
-		// For demo, using public Ethereum RPC
-		client, err := ethclient.Dial("https://eth.llamarpc.com")
-		if err != nil {
-			return balance, nil // Return empty balance on error
-		}
-		defer client.Close()
-
-		address := common.HexToAddress(wallet.Address)
-		balanceWei, err := client.BalanceAt(context.Background(), address, nil)
-		if err != nil {
-			return balance, nil
-		}
-
-		balance.NativeBalance = balanceWei.String()
+	native, err := s.container.Balances.Fetch(ctx, wallet, chainID)
+	if err != nil {
+		balance.Error = err.Error()
+		return balance
 	}
+	balance.NativeBalance = native
 
-	return balance, nil
+	if data, err := json.Marshal(balance); err == nil {
+		s.container.Redis.Set(ctx, cacheKey, data, balanceCacheTTL)
+	}
+	return balance
 }
 
+// SyncBalance stores the wallet's balance on its default chain
 func (s *WalletService) SyncBalance(walletID uuid.UUID) error {
 	balance, err := s.GetBalance(walletID)
 	if err != nil {
 		return err
 	}
+	if balance.Error != "" {
+		s.container.DB.Model(&models.Wallet{}).Where("id = ?", walletID).Update("last_sync_error", balance.Error)
+		return errors.New(balance.Error)
+	}
 
 	return s.container.DB.Model(&models.Wallet{}).Where("id = ?", walletID).Updates(map[string]interface{}{
 		"balance":           balance.NativeBalance,
 		"last_balance_sync": time.Now(),
+		"last_sync_error":   "",
 	}).Error
 }
 
@@ -352,66 +460,54 @@ func (s *WalletService) GetTransactions(userID, walletID uuid.UUID, limit, offse
 	return transactions, total, nil
 }
 
+// FetchOnchainHistory imports the wallet's transactions from the block explorer and
+// returns how many were fetched
+func (s *WalletService) FetchOnchainHistory(walletID uuid.UUID) (int, error) {
+	var wallet models.Wallet
+	if err := s.container.DB.First(&wallet, "id = ?", walletID).Error; err != nil {
+		return 0, err
+	}
+	return s.container.Explorer.SyncWallet(context.Background(), s.container.DB, &wallet)
+}
+
 func (s *WalletService) PrepareTransaction(userID, walletID uuid.UUID, req *PrepareTransactionRequest) (*PreparedTransaction, error) {
 	var wallet models.Wallet
 	if err := s.container.DB.Where("id = ? AND user_id = ?", walletID, userID).First(&wallet).Error; err != nil {
 		return nil, err
 	}
 
-	// Get RPC URL for chain
-	rpcURL := s.getRPCURL(req.ChainID)
-	client, err := ethclient.Dial(rpcURL)
+	ctx := context.Background()
+
+	// Shared client for the first healthy RPC of the chain
+	client, err := s.container.RPC.EVMClient(ctx, userID, req.ChainID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RPC: %v", err)
 	}
-	defer client.Close()
-
-	ctx := context.Background()
 
-	// Get nonce
 	fromAddress := common.HexToAddress(wallet.Address)
-	nonce, err := client.PendingNonceAt(ctx, fromAddress)
+	toAddress := common.HexToAddress(req.To)
+	value, data := transactionPayload(req)
+
+	gasPrice, gasLimit, err := s.gasFor(ctx, client, fromAddress, req, value, data)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get gas price if not provided
-	var gasPrice *big.Int
-	if req.GasPrice != "" {
-		gasPrice = new(big.Int)
-		gasPrice.SetString(req.GasPrice, 10)
-	} else {
-		gasPrice, err = client.SuggestGasPrice(ctx)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	// Parse value
-	value := new(big.Int)
-	if req.Value != "" {
-		value.SetString(req.Value, 10)
-	}
-
-	// Parse data
-	var data []byte
-	if req.Data != "" {
-		data, _ = hex.DecodeString(req.Data)
-	}
-
-	// Estimate gas if not provided
-	gasLimit := req.GasLimit
-	if gasLimit == 0 {
-		gasLimit = 21000 // Default for simple transfers
+	// Reserve a nonce last, so failures above do not leave gaps. Concurrent
+	// prepares from the same wallet get distinct, sequential nonces.
+	nonce, err := s.container.Nonces.Next(ctx, req.ChainID, fromAddress, client)
+	if err != nil {
+		s.container.RPC.Discard(client)
+		return nil, err
 	}
 
 	// Create unsigned transaction
-	toAddress := common.HexToAddress(req.To)
 	tx := types.NewTransaction(nonce, toAddress, value, gasLimit, gasPrice, data)
 
 	// Serialize transaction
 	txBytes, err := tx.MarshalBinary()
 	if err != nil {
+		s.container.Nonces.Release(ctx, req.ChainID, fromAddress, nonce)
 		return nil, err
 	}
 
@@ -427,23 +523,76 @@ func (s *WalletService) PrepareTransaction(userID, walletID uuid.UUID, req *Prep
 	return prepared, nil
 }
 
-func (s *WalletService) BulkCreate(userID uuid.UUID, count int, walletType models.WalletType, groupID *uuid.UUID) ([]models.Wallet, error) {
-	var wallets []models.Wallet
+// EstimateTransaction returns the gas req would be sent with from the wallet and
+// what it would cost, without reserving a nonce or building the transaction
+func (s *WalletService) EstimateTransaction(ctx context.Context, userID, walletID uuid.UUID, req *PrepareTransactionRequest) (*TransactionEstimate, error) {
+	var wallet models.Wallet
+	if err := s.container.DB.Where("id = ? AND user_id = ?", walletID, userID).First(&wallet).Error; err != nil {
+		return nil, err
+	}
 
-	for i := 0; i < count; i++ {
-		req := &CreateWalletRequest{
-			Name:    fmt.Sprintf("Wallet %d", i+1),
-			Type:    walletType,
-			GroupID: groupID,
-		}
-		wallet, err := s.Create(userID, req)
+	client, err := s.container.RPC.EVMClient(ctx, userID, req.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RPC: %v", err)
+	}
+
+	value, data := transactionPayload(req)
+	gasPrice, gasLimit, err := s.gasFor(ctx, client, common.HexToAddress(wallet.Address), req, value, data)
+	if err != nil {
+		return nil, err
+	}
+
+	cost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasLimit))
+	return &TransactionEstimate{GasLimit: gasLimit, GasPrice: gasPrice.String(), Cost: cost.String()}, nil
+}
+
+// transactionPayload parses the value and calldata of req
+func transactionPayload(req *PrepareTransactionRequest) (*big.Int, []byte) {
+	value := new(big.Int)
+	if req.Value != "" {
+		value.SetString(req.Value, 10)
+	}
+	var data []byte
+	if req.Data != "" {
+		data, _ = hex.DecodeString(req.Data)
+	}
+	return value, data
+}
+
+// gasFor returns the gas price and limit for req, suggesting and estimating
+// whichever it leaves unset
+func (s *WalletService) gasFor(ctx context.Context, client *ethclient.Client, from common.Address, req *PrepareTransactionRequest, value *big.Int, data []byte) (*big.Int, uint64, error) {
+	var gasPrice *big.Int
+	if req.GasPrice != "" {
+		gasPrice = new(big.Int)
+		gasPrice.SetString(req.GasPrice, 10)
+	} else {
+		var err error
+		gasPrice, err = client.SuggestGasPrice(ctx)
 		if err != nil {
-			continue
+			s.container.RPC.Discard(client)
+			return nil, 0, err
 		}
-		wallets = append(wallets, *wallet)
 	}
 
-	return wallets, nil
+	to := common.HexToAddress(req.To)
+	gasLimit := req.GasLimit
+	if gasLimit == 0 && len(data) > 0 {
+		// Contract calls vary, so estimate with some headroom
+		estimate, err := client.EstimateGas(ctx, ethereum.CallMsg{
+			From:  from,
+			To:    &to,
+			Value: value,
+			Data:  data,
+		})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to estimate gas: %w", err)
+		}
+		gasLimit = estimate * 12 / 10
+	} else if gasLimit == 0 {
+		gasLimit = 21000 // Default for simple transfers
+	}
+	return gasPrice, gasLimit, nil
 }
 
 func (s *WalletService) encryptPrivateKey(privateKey string) (string, error) {
@@ -508,8 +657,40 @@ func (s *WalletService) decryptPrivateKey(encrypted string) (string, error) {
 	return string(plaintext), nil
 }
 
-func (s *WalletService) getPrivateKey(wallet *models.Wallet) (*ecdsa.PrivateKey, error) {
+// Subsystems that decrypt wallet keys, recorded on each key access
+const (
+	keyUseSign   = "sign"
+	keyUseSend   = "send"
+	keyUseSweep  = "sweep"
+	keyUseExport = "export"
+)
+
+// openPrivateKey decrypts a wallet's stored key, auditing the access as the
+// vault does its secrets, with the subsystem that needed the key
+func (s *WalletService) openPrivateKey(wallet *models.Wallet, subsystem string) (string, error) {
 	decrypted, err := s.decryptPrivateKey(wallet.EncryptedKey)
+
+	entry := &LogEntry{
+		UserID:      wallet.UserID,
+		WalletID:    &wallet.ID,
+		Action:      models.ActionSecretAccess,
+		Platform:    string(wallet.Type),
+		TargetType:  "wallet_key",
+		TargetID:    wallet.Address,
+		Result:      models.ResultSuccess,
+		RequestData: map[string]interface{}{"subsystem": subsystem},
+	}
+	if err != nil {
+		entry.Result = models.ResultFailed
+		entry.ErrorMessage = err.Error()
+	}
+	s.container.Audit.Log(context.Background(), entry)
+
+	return decrypted, err
+}
+
+func (s *WalletService) getPrivateKey(wallet *models.Wallet, subsystem string) (*ecdsa.PrivateKey, error) {
+	decrypted, err := s.openPrivateKey(wallet, subsystem)
 	if err != nil {
 		return nil, err
 	}
@@ -522,21 +703,6 @@ func (s *WalletService) getPrivateKey(wallet *models.Wallet) (*ecdsa.PrivateKey,
 	return crypto.ToECDSA(privateKeyBytes)
 }
 
-func (s *WalletService) getRPCURL(chainID int64) string {
-	rpcURLs := map[int64]string{
-		1:     "https://eth.llamarpc.com",
-		137:   "https://polygon-rpc.com",
-		42161: "https://arb1.arbitrum.io/rpc",
-		10:    "https://mainnet.optimism.io",
-		8453:  "https://mainnet.base.org",
-	}
-	
-	if url, ok := rpcURLs[chainID]; ok {
-		return url
-	}
-	return "https://eth.llamarpc.com"
-}
-
 // Wallet Group operations
 
 type UpdateWalletRequest struct {
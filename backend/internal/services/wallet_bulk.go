@@ -0,0 +1,185 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+const (
+	// maxBulkWallets caps one bulk creation
+	maxBulkWallets = 1000
+
+	// MaxSyncBulkWallets is the largest count created within the request; larger
+	// counts run as a background job
+	MaxSyncBulkWallets = 50
+)
+
+var (
+	ErrTooManyWallets        = fmt.Errorf("at most %d wallets can be created at once", maxBulkWallets)
+	ErrUnsupportedWalletType = errors.New("unsupported wallet type")
+)
+
+// BulkWalletFailure is a wallet that could not be created. Index counts from 1.
+type BulkWalletFailure struct {
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// BulkCreateWalletsResult is the outcome of a synchronous bulk creation
+type BulkCreateWalletsResult struct {
+	Wallets []models.Wallet     `json:"wallets"`
+	Count   int                 `json:"count"`
+	Failed  []BulkWalletFailure `json:"failed"`
+}
+
+// BulkWalletProgress is sent as wallet:bulk_progress after every wallet of a
+// background bulk creation
+type BulkWalletProgress struct {
+	JobID   uuid.UUID `json:"job_id"`
+	Total   int       `json:"total"`
+	Created int       `json:"created"`
+	Failed  int       `json:"failed"`
+	Done    bool      `json:"done"`
+}
+
+// BulkCreate creates count wallets within the request. Wallets that fail are
+// reported in Failed rather than aborting the rest.
+func (s *WalletService) BulkCreate(userID uuid.UUID, count int, walletType models.WalletType, groupID *uuid.UUID) (*BulkCreateWalletsResult, error) {
+	if count > maxBulkWallets {
+		return nil, ErrTooManyWallets
+	}
+
+	result := &BulkCreateWalletsResult{Wallets: []models.Wallet{}, Failed: []BulkWalletFailure{}}
+	for i := 1; i <= count; i++ {
+		wallet, err := s.createBulkWallet(userID, i, walletType, groupID)
+		if err != nil {
+			result.Failed = append(result.Failed, BulkWalletFailure{Index: i, Name: bulkWalletName(i), Error: err.Error()})
+			continue
+		}
+		result.Wallets = append(result.Wallets, *wallet)
+	}
+	result.Count = len(result.Wallets)
+
+	return result, nil
+}
+
+// BulkCreateAsync starts creating count wallets in the background and returns
+// the job that tracks them. Each wallet sends wallet:created, progress follows as
+// wallet:bulk_progress, and failures are written to the job's logs. The job's
+// success and failed run counts hold the created and failed wallets.
+func (s *WalletService) BulkCreateAsync(userID uuid.UUID, count int, walletType models.WalletType, groupID *uuid.UUID) (*models.AutomationJob, error) {
+	if count > maxBulkWallets {
+		return nil, ErrTooManyWallets
+	}
+	if walletType != models.WalletTypeEVM && walletType != models.WalletTypeSolana {
+		return nil, ErrUnsupportedWalletType
+	}
+
+	configJSON, _ := json.Marshal(map[string]interface{}{
+		"count":    count,
+		"type":     walletType,
+		"group_id": groupID,
+	})
+	now := time.Now()
+	job := &models.AutomationJob{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Type:        models.JobTypeWalletBulkCreate,
+		Name:        fmt.Sprintf("Create %d %s wallets", count, walletType),
+		Description: "Bulk wallet creation",
+		Config:      string(configJSON),
+		// Inactive so the scheduler never picks it up as one of its own jobs
+		IsActive:  false,
+		Status:    "running",
+		LastRunAt: &now,
+	}
+	if err := s.container.DB.Create(job).Error; err != nil {
+		return nil, err
+	}
+
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:   "info",
+		Source:  "wallet",
+		JobID:   job.ID.String(),
+		Message: fmt.Sprintf("Creating %d %s wallets...", count, walletType),
+	})
+	s.container.WSHub.BroadcastToUser(userID.String(), "job:started", job)
+
+	go s.runBulkCreate(job, count, walletType, groupID)
+
+	return job, nil
+}
+
+// runBulkCreate creates the wallets of a bulk job, reporting progress as it goes
+func (s *WalletService) runBulkCreate(job *models.AutomationJob, count int, walletType models.WalletType, groupID *uuid.UUID) {
+	userID := job.UserID
+	progress := BulkWalletProgress{JobID: job.ID, Total: count}
+
+	for i := 1; i <= count; i++ {
+		wallet, err := s.createBulkWallet(userID, i, walletType, groupID)
+		if err != nil {
+			progress.Failed++
+			s.container.Job.AddLog(job.ID, models.JobLogError, fmt.Sprintf("Failed to create %s: %v", bulkWalletName(i), err),
+				BulkWalletFailure{Index: i, Name: bulkWalletName(i), Error: err.Error()})
+		} else {
+			progress.Created++
+			entry := models.NewJobLog(job.ID, models.JobLogInfo, "Created "+wallet.Name+" "+wallet.Address, nil)
+			entry.WalletID = &wallet.ID
+			s.container.DB.Create(entry)
+		}
+
+		s.container.DB.Model(job).Updates(map[string]interface{}{
+			"total_runs":   i,
+			"success_runs": progress.Created,
+			"failed_runs":  progress.Failed,
+		})
+		s.container.WSHub.BroadcastToUser(userID.String(), "wallet:bulk_progress", progress)
+	}
+
+	status := "completed"
+	if progress.Created == 0 {
+		status = "failed"
+	}
+	s.container.DB.Model(job).Update("status", status)
+
+	progress.Done = true
+	s.container.WSHub.BroadcastToUser(userID.String(), "wallet:bulk_progress", progress)
+
+	level := "success"
+	if progress.Failed > 0 {
+		level = "warn"
+	}
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:   level,
+		Source:  "wallet",
+		JobID:   job.ID.String(),
+		Message: fmt.Sprintf("Bulk wallet creation finished: %d created, %d failed", progress.Created, progress.Failed),
+	})
+	if progress.Failed > 0 {
+		log.Printf("⚠️ Bulk wallet job %s: %d of %d wallets failed", job.ID, progress.Failed, count)
+	}
+
+	job.Status = status
+	s.container.WSHub.BroadcastToUser(userID.String(), "job:completed", job)
+}
+
+func (s *WalletService) createBulkWallet(userID uuid.UUID, index int, walletType models.WalletType, groupID *uuid.UUID) (*models.Wallet, error) {
+	return s.Create(userID, &CreateWalletRequest{
+		Name:    bulkWalletName(index),
+		Type:    walletType,
+		GroupID: groupID,
+	})
+}
+
+func bulkWalletName(index int) string {
+	return fmt.Sprintf("Wallet %d", index)
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/argon2"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/websocket"
+)
+
+// Wallet export file format; see docs/WALLET_EXPORT.md for offline decryption
+const (
+	WalletExportFormat  = "web3airdropos-wallet-export"
+	WalletExportVersion = 1
+
+	// Argon2id parameters, the same as the vault's key derivation
+	exportKDFTime    = 3
+	exportKDFMemory  = 64 * 1024 // KiB
+	exportKDFThreads = 4
+	exportKeyLen     = 32
+	exportSaltLen    = 16
+)
+
+// ErrWeakPassphrase is returned for an export passphrase that is too short to protect keys
+var ErrWeakPassphrase = errors.New("export passphrase must be at least 12 characters")
+
+// minExportPassphrase is the shortest passphrase accepted for an export
+const minExportPassphrase = 12
+
+// ExportWalletsRequest asks for an encrypted export of wallets. The account
+// password, and the 2FA code when enabled, must be re-entered.
+type ExportWalletsRequest struct {
+	WalletIDs  []uuid.UUID `json:"wallet_ids"` // Empty exports every wallet
+	Passphrase string      `json:"passphrase" binding:"required,min=12"`
+	Password   string      `json:"password" binding:"required"`
+	Code       string      `json:"code"`
+}
+
+// WalletExport is an export file. Only the ciphertext holds key material.
+type WalletExport struct {
+	Format     string           `json:"format"`
+	Version    int              `json:"version"`
+	CreatedAt  time.Time        `json:"created_at"`
+	KDF        WalletExportKDF  `json:"kdf"`
+	Cipher     WalletExportAEAD `json:"cipher"`
+	Wallets    int              `json:"wallets"`
+	Ciphertext string           `json:"ciphertext"` // Base64 AES-GCM output, tag appended
+}
+
+// WalletExportKDF describes how the passphrase becomes the encryption key
+type WalletExportKDF struct {
+	Name    string `json:"name"`
+	Salt    string `json:"salt"` // Base64
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"` // KiB
+	Threads uint8  `json:"threads"`
+	KeyLen  uint32 `json:"key_len"`
+}
+
+// WalletExportAEAD describes the cipher sealing the wallets
+type WalletExportAEAD struct {
+	Name  string `json:"name"`
+	Nonce string `json:"nonce"` // Base64
+}
+
+// walletExportPayload is the decrypted content of an export
+type walletExportPayload struct {
+	Wallets []exportedWallet `json:"wallets"`
+}
+
+type exportedWallet struct {
+	ID         uuid.UUID         `json:"id"`
+	Name       string            `json:"name"`
+	Type       models.WalletType `json:"type"`
+	Address    string            `json:"address"`
+	PrivateKey string            `json:"private_key,omitempty"` // Hex; absent for wallets without a stored key
+}
+
+// Export decrypts the keys of the given wallets, or of every wallet when
+// walletIDs is empty, and seals them under a key derived from passphrase. The
+// caller's identity is confirmed first, and every attempt is audit-logged.
+// Plaintext keys never leave this function.
+func (s *WalletService) Export(userID uuid.UUID, walletIDs []uuid.UUID, passphrase string, confirm *IdentityConfirmation) (*WalletExport, error) {
+	if err := s.container.Auth.ConfirmIdentity(userID, confirm); err != nil {
+		s.auditExport(userID, nil, confirm, walletIDs, fmt.Errorf("identity confirmation failed: %w", err))
+		log.Printf("⚠️ Wallet export denied for user %s: %v", userID, err)
+		return nil, err
+	}
+	if len(passphrase) < minExportPassphrase {
+		return nil, ErrWeakPassphrase
+	}
+
+	query := s.container.DB.Where("user_id = ?", userID)
+	if len(walletIDs) > 0 {
+		query = query.Where("id IN ?", walletIDs)
+	}
+	var wallets []models.Wallet
+	if err := query.Order("created_at").Find(&wallets).Error; err != nil {
+		return nil, err
+	}
+	if len(wallets) == 0 || (len(walletIDs) > 0 && len(wallets) != len(uniqueIDs(walletIDs))) {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	payload := walletExportPayload{Wallets: make([]exportedWallet, len(wallets))}
+	for i, wallet := range wallets {
+		payload.Wallets[i] = exportedWallet{
+			ID:      wallet.ID,
+			Name:    wallet.Name,
+			Type:    wallet.Type,
+			Address: wallet.Address,
+		}
+		if wallet.EncryptedKey == "" {
+			continue
+		}
+		privateKey, err := s.openPrivateKey(&wallet, keyUseExport)
+		if err != nil {
+			err = fmt.Errorf("failed to decrypt key of wallet %s: %w", wallet.ID, err)
+			s.auditExport(userID, &wallet, confirm, walletIDs, err)
+			return nil, err
+		}
+		payload.Wallets[i].PrivateKey = privateKey
+	}
+
+	export, err := sealWalletExport(&payload, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range wallets {
+		s.auditExport(userID, &wallets[i], confirm, walletIDs, nil)
+	}
+	log.Printf("🔑 User %s exported %d wallet keys", userID, len(wallets))
+	s.container.WSHub.BroadcastTerminal(userID.String(), websocket.TerminalMessage{
+		Level:   "warn",
+		Source:  "wallet",
+		Message: fmt.Sprintf("Exported private keys of %d wallets. Keep the export file and its passphrase safe.", len(wallets)),
+	})
+
+	return export, nil
+}
+
+// auditExport records an export attempt. wallet is nil when the attempt was
+// refused before any wallet was read, and confirm is nil when none was given.
+func (s *WalletService) auditExport(userID uuid.UUID, wallet *models.Wallet, confirm *IdentityConfirmation, walletIDs []uuid.UUID, err error) {
+	entry := &LogEntry{
+		UserID:      userID,
+		Action:      models.ActionWalletExport,
+		TargetType:  "wallet",
+		RequestData: map[string]interface{}{"wallet_ids": walletIDs, "all": len(walletIDs) == 0},
+		Result:      models.ResultSuccess,
+	}
+	if confirm != nil {
+		entry.IPAddress = confirm.IPAddress
+		entry.UserAgent = confirm.UserAgent
+	}
+	if wallet != nil {
+		entry.WalletID = &wallet.ID
+		entry.Platform = string(wallet.Type)
+		entry.TargetID = wallet.Address
+	}
+	if err != nil {
+		entry.Result = models.ResultFailed
+		entry.ErrorMessage = err.Error()
+	}
+	s.container.Audit.Log(context.Background(), entry)
+}
+
+// sealWalletExport encrypts payload with AES-256-GCM under an Argon2id key derived from passphrase
+func sealWalletExport(payload *walletExportPayload, passphrase string) (*WalletExport, error) {
+	plaintext, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, exportSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key := argon2.IDKey([]byte(passphrase), salt, exportKDFTime, exportKDFMemory, exportKDFThreads, exportKeyLen)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	ciphertext := gcm.Seal(nil, nonce, plaintext, nil)
+
+	return &WalletExport{
+		Format:    WalletExportFormat,
+		Version:   WalletExportVersion,
+		CreatedAt: time.Now().UTC(),
+		KDF: WalletExportKDF{
+			Name:    "argon2id",
+			Salt:    base64.StdEncoding.EncodeToString(salt),
+			Time:    exportKDFTime,
+			Memory:  exportKDFMemory,
+			Threads: exportKDFThreads,
+			KeyLen:  exportKeyLen,
+		},
+		Cipher: WalletExportAEAD{
+			Name:  "aes-256-gcm",
+			Nonce: base64.StdEncoding.EncodeToString(nonce),
+		},
+		Wallets:    len(payload.Wallets),
+		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+	}, nil
+}
+
+func uniqueIDs(ids []uuid.UUID) map[uuid.UUID]bool {
+	unique := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+	return unique
+}
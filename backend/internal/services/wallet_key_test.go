@@ -0,0 +1,104 @@
+package services
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/auth"
+	"github.com/web3airdropos/backend/internal/models"
+)
+
+// auditArgs records the values written to an audit_logs row
+type auditArgs struct{ values []driver.Value }
+
+func (a *auditArgs) Match(v driver.Value) bool {
+	a.values = append(a.values, v)
+	return true
+}
+
+func (a *auditArgs) has(want string) bool {
+	for _, v := range a.values {
+		if s, ok := v.(string); ok && strings.Contains(s, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func expectAuditInsert(mock sqlmock.Sqlmock) *auditArgs {
+	args := &auditArgs{}
+	matchers := make([]driver.Value, 28)
+	for i := range matchers {
+		matchers[i] = args
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "audit_logs"`).WithArgs(matchers...).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+	return args
+}
+
+func TestWalletKeyAccessIsAudited(t *testing.T) {
+	c, mock := mockContainer(t)
+	c.Config.EncryptionKey = "test-encryption-key"
+	c.Audit = NewAuditService(c.DB)
+	s := NewWalletService(c)
+
+	const privateKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	encrypted, err := s.encryptPrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet := &models.Wallet{ID: uuid.New(), UserID: uuid.New(), Type: models.WalletTypeEVM, Address: "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23", EncryptedKey: encrypted}
+
+	args := expectAuditInsert(mock)
+	if _, err := s.getPrivateKey(wallet, keyUseSweep); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{string(models.ActionSecretAccess), `"subsystem":"sweep"`, string(models.ResultSuccess), wallet.Address} {
+		if !args.has(want) {
+			t.Errorf("audit row %v is missing %q", args.values, want)
+		}
+	}
+
+	// A key that fails to decrypt is audited too
+	wallet.EncryptedKey = "00"
+	args = expectAuditInsert(mock)
+	if _, err := s.openPrivateKey(wallet, keyUseExport); err == nil {
+		t.Fatal("decrypting a corrupt key succeeded")
+	}
+	for _, want := range []string{`"subsystem":"export"`, string(models.ResultFailed)} {
+		if !args.has(want) {
+			t.Errorf("audit row %v is missing %q", args.values, want)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWalletExportWithoutConfirmationIsRefused(t *testing.T) {
+	c, mock := mockContainer(t)
+	c.Audit = NewAuditService(c.DB)
+	c.Auth = NewAuthService(c)
+	s := NewWalletService(c)
+
+	args := expectAuditInsert(mock)
+	if _, err := s.Export(uuid.New(), nil, "a long enough passphrase", nil); !errors.Is(err, auth.ErrInvalidCredentials) {
+		t.Fatalf("got %v, want %v", err, auth.ErrInvalidCredentials)
+	}
+	for _, want := range []string{string(models.ActionWalletExport), string(models.ResultFailed)} {
+		if !args.has(want) {
+			t.Errorf("audit row %v is missing %q", args.values, want)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
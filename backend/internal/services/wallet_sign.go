@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/nonce"
+	"github.com/web3airdropos/backend/internal/services/platforms"
+)
+
+var (
+	ErrSigningUnsupported = errors.New("message signing is only available for EVM wallets")
+	ErrInvalidTypedData   = errors.New("message is not valid EIP-712 typed data")
+)
+
+// Signature schemes
+const (
+	SignTypePersonal  = "personal_sign"
+	SignTypeTypedData = "eip712"
+)
+
+type SignMessageRequest struct {
+	Message   string `json:"message" binding:"required"`
+	TypedData bool   `json:"typed_data"` // Message is EIP-712 typed data JSON
+}
+
+// SignedMessage is a signature in wallet format, with V as 27 or 28
+type SignedMessage struct {
+	Type             string `json:"type"`
+	Signature        string `json:"signature"`
+	Hash             string `json:"hash"`
+	Address          string `json:"address"`
+	RecoveredAddress string `json:"recovered_address"`
+}
+
+// SignMessage signs a message with a server-signable EVM wallet, either as a
+// personal_sign (EIP-191) message or as EIP-712 typed data. The signer is recovered
+// from the signature so callers can check it matches the wallet.
+func (s *WalletService) SignMessage(userID, walletID uuid.UUID, message string, typedData bool) (*SignedMessage, error) {
+	var wallet models.Wallet
+	if err := s.container.DB.Where("id = ? AND user_id = ?", walletID, userID).First(&wallet).Error; err != nil {
+		return nil, err
+	}
+	if wallet.Type != models.WalletTypeEVM {
+		return nil, ErrSigningUnsupported
+	}
+	if err := canServerSign(&wallet); err != nil {
+		return nil, err
+	}
+
+	signType := SignTypePersonal
+	requestData := map[string]interface{}{"type": SignTypePersonal}
+	var hash []byte
+	if typedData {
+		var data apitypes.TypedData
+		if err := json.Unmarshal([]byte(message), &data); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTypedData, err)
+		}
+		digest, _, err := apitypes.TypedDataAndHash(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTypedData, err)
+		}
+		hash = digest
+		signType = SignTypeTypedData
+		requestData = map[string]interface{}{
+			"type":         SignTypeTypedData,
+			"primary_type": data.PrimaryType,
+			"domain":       data.Domain.Name,
+			"chain_id":     data.Domain.ChainId,
+		}
+	} else {
+		hash = accounts.TextHash([]byte(message))
+		requestData["message"] = message
+	}
+	requestData["hash"] = hexutil.Encode(hash)
+
+	key, err := s.getPrivateKey(&wallet, keyUseSign)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wallet key: %w", err)
+	}
+
+	audit := &LogEntry{
+		UserID:      userID,
+		WalletID:    &wallet.ID,
+		Action:      models.ActionSignature,
+		Platform:    "evm",
+		TargetType:  "message",
+		TargetID:    hexutil.Encode(hash),
+		RequestData: requestData,
+	}
+	ctx := context.Background()
+
+	signature, err := crypto.Sign(hash, key)
+	if err != nil {
+		audit.Result = models.ResultFailed
+		audit.ErrorMessage = err.Error()
+		s.container.Audit.Log(ctx, audit)
+		return nil, err
+	}
+
+	pub, err := crypto.SigToPub(hash, signature)
+	if err != nil {
+		return nil, err
+	}
+	recovered := crypto.PubkeyToAddress(*pub).Hex()
+
+	// Wallets and on-chain verifiers expect V as 27/28 rather than 0/1
+	signature[crypto.RecoveryIDOffset] += 27
+
+	audit.Result = models.ResultSuccess
+	audit.Proof = &platforms.ActionProof{Timestamp: time.Now().Unix()}
+	s.container.Audit.Log(ctx, audit)
+
+	return &SignedMessage{
+		Type:             signType,
+		Signature:        hexutil.Encode(signature),
+		Hash:             hexutil.Encode(hash),
+		Address:          wallet.Address,
+		RecoveredAddress: recovered,
+	}, nil
+}
+
+// SendTransaction prepares a transaction as PrepareTransaction does, signs it with
+// the wallet's stored key and broadcasts it, returning the transaction hash. It
+// requires the wallet's allow_server_signing opt-in. The transaction is recorded as
+// pending, and every use of the key is audited as a transaction, against taskID
+// when the send is for a task.
+func (s *WalletService) SendTransaction(userID, walletID uuid.UUID, req *PrepareTransactionRequest, taskID *uuid.UUID) (string, error) {
+	var wallet models.Wallet
+	if err := s.container.DB.Where("id = ? AND user_id = ?", walletID, userID).First(&wallet).Error; err != nil {
+		return "", err
+	}
+	if wallet.Type != models.WalletTypeEVM {
+		return "", ErrSigningUnsupported
+	}
+	if err := canServerSign(&wallet); err != nil {
+		return "", err
+	}
+
+	prepared, err := s.PrepareTransaction(userID, walletID, req)
+	if err != nil {
+		return "", err
+	}
+	ctx := context.Background()
+	from := common.HexToAddress(wallet.Address)
+
+	audit := &LogEntry{
+		UserID:     userID,
+		WalletID:   &wallet.ID,
+		Action:     models.ActionTransaction,
+		Platform:   "evm",
+		TargetType: "address",
+		TargetID:   common.HexToAddress(req.To).Hex(),
+		TaskID:     taskID,
+		RequestData: map[string]interface{}{
+			"type":      "send",
+			"chain_id":  req.ChainID,
+			"value":     req.Value,
+			"data":      req.Data,
+			"nonce":     prepared.Nonce,
+			"gas_limit": prepared.EstimatedGas,
+			"gas_price": prepared.GasPrice,
+		},
+	}
+	fail := func(err error) (string, error) {
+		audit.Result = models.ResultFailed
+		audit.ErrorMessage = err.Error()
+		s.container.Audit.Log(ctx, audit)
+		return "", err
+	}
+
+	signed, err := s.signPrepared(&wallet, prepared, req.ChainID)
+	if err != nil {
+		s.container.Nonces.Release(ctx, req.ChainID, from, prepared.Nonce)
+		return fail(err)
+	}
+
+	client, err := s.container.RPC.EVMClient(ctx, userID, req.ChainID)
+	if err != nil {
+		s.container.Nonces.Release(ctx, req.ChainID, from, prepared.Nonce)
+		return fail(fmt.Errorf("failed to connect to RPC: %v", err))
+	}
+	if err := client.SendTransaction(ctx, signed); err != nil {
+		s.settleFailedSend(ctx, req.ChainID, from, prepared.Nonce, err)
+		return fail(err)
+	}
+
+	hash := signed.Hash().Hex()
+	audit.Result = models.ResultSuccess
+	audit.Proof = &platforms.ActionProof{TxHash: hash, Timestamp: time.Now().Unix()}
+	s.container.Audit.Log(ctx, audit)
+
+	s.container.DB.Create(&models.Transaction{
+		WalletID:    wallet.ID,
+		Hash:        hash,
+		ChainID:     int(req.ChainID),
+		FromAddress: wallet.Address,
+		ToAddress:   signed.To().Hex(),
+		Value:       signed.Value().String(),
+		GasPrice:    signed.GasPrice().String(),
+		Status:      "pending",
+		Timestamp:   time.Now(),
+		DecodedData: "{}",
+	})
+	return hash, nil
+}
+
+// signPrepared signs a transaction built by PrepareTransaction with the wallet's stored key
+func (s *WalletService) signPrepared(wallet *models.Wallet, prepared *PreparedTransaction, chainID int64) (*types.Transaction, error) {
+	raw, err := hex.DecodeString(prepared.UnsignedTx)
+	if err != nil {
+		return nil, err
+	}
+	var tx types.Transaction
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, err
+	}
+
+	key, err := s.getPrivateKey(wallet, keyUseSend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wallet key: %w", err)
+	}
+	return types.SignTx(&tx, types.LatestSignerForChainID(big.NewInt(chainID)), key)
+}
+
+// settleFailedSend returns the nonce of a transaction the node refused. A refusal
+// over the nonce itself means tracking has drifted, so it restarts from the chain.
+func (s *WalletService) settleFailedSend(ctx context.Context, chainID int64, from common.Address, txNonce uint64, err error) {
+	if nonce.IsNonceError(err) {
+		s.container.Nonces.Reset(ctx, chainID, from)
+		return
+	}
+	s.container.Nonces.Release(ctx, chainID, from, txNonce)
+}
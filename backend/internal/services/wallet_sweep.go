@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/services/platforms"
+)
+
+var (
+	ErrServerSigningDisabled = errors.New("server signing is not enabled for this wallet")
+	ErrInvalidDestination    = errors.New("destination must be an EVM address")
+)
+
+// transferGas is the gas used by a plain native transfer to an account without code
+const transferGas = 21000
+
+// Sweep outcomes per wallet
+const (
+	SweepStatusSwept   = "swept"
+	SweepStatusSkipped = "skipped"
+	SweepStatusFailed  = "failed"
+)
+
+type SweepRequest struct {
+	Destination string `json:"destination" binding:"required"`
+	ChainID     int64  `json:"chain_id" binding:"required"`
+}
+
+// SweepWalletResult is what happened to one wallet in a sweep
+type SweepWalletResult struct {
+	WalletID uuid.UUID `json:"wallet_id"`
+	Address  string    `json:"address"`
+	Status   string    `json:"status"`
+	Reason   string    `json:"reason,omitempty"`
+	TxHash   string    `json:"tx_hash,omitempty"`
+	Amount   string    `json:"amount,omitempty"` // In wei
+}
+
+// SweepSummary totals a sweep. TotalMoved is in wei and excludes gas.
+type SweepSummary struct {
+	Destination string              `json:"destination"`
+	ChainID     int64               `json:"chain_id"`
+	Swept       int                 `json:"swept"`
+	Skipped     int                 `json:"skipped"`
+	Failed      int                 `json:"failed"`
+	TotalMoved  string              `json:"total_moved"`
+	Wallets     []SweepWalletResult `json:"wallets"`
+}
+
+// canServerSign reports why a wallet may not be signed for on the server, if so
+func canServerSign(wallet *models.Wallet) error {
+	if !wallet.AllowServerSigning || wallet.IsWatchOnly || wallet.EncryptedKey == "" {
+		return ErrServerSigningDisabled
+	}
+	return nil
+}
+
+// Sweep moves the native balance of every server-signable EVM wallet in the group to
+// destination, leaving only what the transfer's gas costs. Wallets are swept one at a
+// time, each transfer is recorded and audited, and one wallet failing does not stop
+// the rest.
+func (s *WalletService) Sweep(userID, groupID uuid.UUID, destination string, chainID int64) (*SweepSummary, error) {
+	if !common.IsHexAddress(destination) {
+		return nil, ErrInvalidDestination
+	}
+	to := common.HexToAddress(destination)
+
+	var group models.WalletGroup
+	if err := s.container.DB.Where("id = ? AND user_id = ?", groupID, userID).
+		Preload("Wallets").
+		First(&group).Error; err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	client, err := s.container.RPC.EVMClient(ctx, userID, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RPC: %v", err)
+	}
+
+	// Contracts such as multisigs need more than a plain transfer's gas
+	code, err := client.CodeAt(ctx, to, nil)
+	if err != nil {
+		s.container.RPC.Discard(client)
+		return nil, err
+	}
+	destinationIsContract := len(code) > 0
+
+	summary := &SweepSummary{Destination: to.Hex(), ChainID: chainID}
+	total := new(big.Int)
+	signer := types.LatestSignerForChainID(big.NewInt(chainID))
+
+	for i := range group.Wallets {
+		wallet := &group.Wallets[i]
+		result := SweepWalletResult{WalletID: wallet.ID, Address: wallet.Address}
+
+		switch {
+		case wallet.Type != models.WalletTypeEVM:
+			result.Status, result.Reason = SweepStatusSkipped, "not an EVM wallet"
+		case strings.EqualFold(wallet.Address, to.Hex()):
+			result.Status, result.Reason = SweepStatusSkipped, "wallet is the destination"
+		case canServerSign(wallet) != nil:
+			result.Status, result.Reason = SweepStatusSkipped, ErrServerSigningDisabled.Error()
+		default:
+			if err := s.sweepWallet(ctx, signer, wallet, to, destinationIsContract, chainID, group.ID, &result); err != nil {
+				result.Status, result.Reason = SweepStatusFailed, err.Error()
+			}
+		}
+
+		switch result.Status {
+		case SweepStatusSwept:
+			summary.Swept++
+			amount, _ := new(big.Int).SetString(result.Amount, 10)
+			total.Add(total, amount)
+		case SweepStatusSkipped:
+			summary.Skipped++
+		default:
+			summary.Failed++
+		}
+		summary.Wallets = append(summary.Wallets, result)
+	}
+
+	summary.TotalMoved = total.String()
+	return summary, nil
+}
+
+// sweepGasLimit estimates a transfer to a contract destination with some headroom
+func (s *WalletService) sweepGasLimit(ctx context.Context, client *ethclient.Client, wallet *models.Wallet, to common.Address) (uint64, error) {
+	estimate, err := client.EstimateGas(ctx, ethereum.CallMsg{
+		From:  common.HexToAddress(wallet.Address),
+		To:    &to,
+		Value: big.NewInt(1),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	return estimate * 12 / 10, nil
+}
+
+// sweepWallet sends one wallet's balance minus gas to the destination. A balance that
+// does not cover gas leaves the wallet skipped rather than failed.
+func (s *WalletService) sweepWallet(ctx context.Context, signer types.Signer, wallet *models.Wallet, to common.Address, toContract bool, chainID int64, groupID uuid.UUID, result *SweepWalletResult) error {
+	from := common.HexToAddress(wallet.Address)
+
+	// Fetched per wallet so a client discarded after an error is replaced
+	client, err := s.container.RPC.EVMClient(ctx, wallet.UserID, chainID)
+	if err != nil {
+		return err
+	}
+
+	gasLimit := uint64(transferGas)
+	if toContract {
+		if gasLimit, err = s.sweepGasLimit(ctx, client, wallet, to); err != nil {
+			return err
+		}
+	}
+
+	balance, err := client.BalanceAt(ctx, from, nil)
+	if err != nil {
+		s.container.RPC.Discard(client)
+		return err
+	}
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		s.container.RPC.Discard(client)
+		return err
+	}
+
+	gasCost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasLimit))
+	if balance.Cmp(gasCost) <= 0 {
+		result.Status, result.Reason = SweepStatusSkipped, "balance does not cover gas"
+		return nil
+	}
+	value := new(big.Int).Sub(balance, gasCost)
+
+	key, err := s.getPrivateKey(wallet, keyUseSweep)
+	if err != nil {
+		return fmt.Errorf("failed to load wallet key: %w", err)
+	}
+
+	// Allocated rather than read, so a transfer still in the mempool from an
+	// earlier sweep, or one sent concurrently, is not replaced
+	nonce, err := s.container.Nonces.Next(ctx, chainID, from, client)
+	if err != nil {
+		s.container.RPC.Discard(client)
+		return err
+	}
+	tx, err := types.SignTx(types.NewTransaction(nonce, to, value, gasLimit, gasPrice, nil), signer, key)
+	if err != nil {
+		s.container.Nonces.Release(ctx, chainID, from, nonce)
+		return err
+	}
+
+	audit := &LogEntry{
+		UserID:     wallet.UserID,
+		WalletID:   &wallet.ID,
+		Action:     models.ActionTransaction,
+		Platform:   "evm",
+		TargetType: "address",
+		TargetID:   to.Hex(),
+		RequestData: map[string]interface{}{
+			"type":      "sweep",
+			"group_id":  groupID,
+			"chain_id":  chainID,
+			"value":     value.String(),
+			"nonce":     nonce,
+			"gas_limit": gasLimit,
+			"gas_price": gasPrice.String(),
+		},
+	}
+
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		s.settleFailedSend(ctx, chainID, from, nonce, err)
+		audit.Result = models.ResultFailed
+		audit.ErrorMessage = err.Error()
+		s.container.Audit.Log(ctx, audit)
+		return err
+	}
+
+	hash := tx.Hash().Hex()
+	audit.Result = models.ResultSuccess
+	audit.Proof = &platforms.ActionProof{TxHash: hash, Timestamp: time.Now().Unix()}
+	s.container.Audit.Log(ctx, audit)
+
+	s.container.DB.Create(&models.Transaction{
+		WalletID:    wallet.ID,
+		Hash:        hash,
+		ChainID:     int(chainID),
+		FromAddress: from.Hex(),
+		ToAddress:   to.Hex(),
+		Value:       value.String(),
+		GasPrice:    gasPrice.String(),
+		Status:      "pending",
+		Timestamp:   time.Now(),
+		DecodedData: `{"type":"sweep"}`,
+	})
+
+	result.Status = SweepStatusSwept
+	result.TxHash = hash
+	result.Amount = value.String()
+	return nil
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/netguard"
+)
+
+var (
+	ErrWebhookNotFound     = errors.New("webhook not found")
+	ErrInvalidWebhookURL   = errors.New("webhook URL must be a public http(s) URL")
+	ErrInvalidWebhookEvent = errors.New("unknown webhook event")
+)
+
+type WebhookService struct {
+	container *Container
+}
+
+func NewWebhookService(c *Container) *WebhookService {
+	return &WebhookService{container: c}
+}
+
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Description string   `json:"description"`
+	Events      []string `json:"events" binding:"required,min=1"`
+}
+
+type UpdateWebhookRequest struct {
+	URL          string   `json:"url"`
+	Description  *string  `json:"description"`
+	Events       []string `json:"events"`
+	IsActive     *bool    `json:"is_active"`
+	RotateSecret bool     `json:"rotate_secret"`
+}
+
+func (s *WebhookService) List(userID uuid.UUID) ([]models.Webhook, error) {
+	var hooks []models.Webhook
+	if err := s.container.DB.Where("user_id = ?", userID).Order("created_at").Find(&hooks).Error; err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+func (s *WebhookService) Get(userID, webhookID uuid.UUID) (*models.Webhook, error) {
+	var hook models.Webhook
+	if err := s.container.DB.Where("id = ? AND user_id = ?", webhookID, userID).First(&hook).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return &hook, nil
+}
+
+// Create adds a webhook with a generated signing secret. The secret is only
+// readable here; callers show it once.
+func (s *WebhookService) Create(userID uuid.UUID, req *CreateWebhookRequest) (*models.Webhook, error) {
+	if !validWebhookURL(req.URL) {
+		return nil, ErrInvalidWebhookURL
+	}
+	events, err := encodeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	hook := &models.Webhook{
+		ID:          uuid.New(),
+		UserID:      userID,
+		URL:         req.URL,
+		Description: req.Description,
+		Secret:      secret,
+		Events:      events,
+		IsActive:    true,
+	}
+	if err := s.container.DB.Create(hook).Error; err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// Update changes a webhook, optionally rotating its signing secret
+func (s *WebhookService) Update(userID, webhookID uuid.UUID, req *UpdateWebhookRequest) (*models.Webhook, error) {
+	hook, err := s.Get(userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if req.URL != "" {
+		if !validWebhookURL(req.URL) {
+			return nil, ErrInvalidWebhookURL
+		}
+		updates["url"] = req.URL
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.Events != nil {
+		events, err := encodeWebhookEvents(req.Events)
+		if err != nil {
+			return nil, err
+		}
+		updates["events"] = events
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+	if req.RotateSecret {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		updates["secret"] = secret
+		hook.Secret = secret
+	}
+
+	if err := s.container.DB.Model(hook).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+func (s *WebhookService) Delete(userID, webhookID uuid.UUID) error {
+	hook, err := s.Get(userID, webhookID)
+	if err != nil {
+		return err
+	}
+	return s.container.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", hook.ID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(hook).Error
+	})
+}
+
+// Test sends a sample event to a webhook, inactive or not, and returns the delivery
+func (s *WebhookService) Test(userID, webhookID uuid.UUID) (*models.WebhookDelivery, error) {
+	hook, err := s.Get(userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	return s.container.WebhookDispatcher.Send(hook, models.WebhookEventTest, map[string]interface{}{
+		"webhook_id": hook.ID,
+		"message":    "This is a test event from Web3AirdropOS",
+		"sent_at":    time.Now().UTC(),
+	})
+}
+
+// Deliveries lists a webhook's most recent deliveries, newest first
+func (s *WebhookService) Deliveries(userID, webhookID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	if _, err := s.Get(userID, webhookID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := s.container.DB.Where("webhook_id = ?", webhookID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func encodeWebhookEvents(events []string) (string, error) {
+	known := make(map[string]bool, len(models.WebhookEvents))
+	for _, event := range models.WebhookEvents {
+		known[event] = true
+	}
+	for _, event := range events {
+		if !known[event] {
+			return "", fmt.Errorf("%w: %s", ErrInvalidWebhookEvent, event)
+		}
+	}
+
+	data, err := json.Marshal(events)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+func validWebhookURL(raw string) bool {
+	return netguard.CheckURL(raw) == nil
+}
@@ -0,0 +1,99 @@
+// Package storage keeps proof screenshots and other task artifacts on local disk
+// or in an S3-compatible bucket, so they outlive the process that produced them.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/web3airdropos/backend/internal/config"
+)
+
+// Storage backends
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+var (
+	ErrNotFound         = errors.New("stored object not found")
+	ErrInvalidKey       = errors.New("invalid storage key")
+	ErrInvalidSignature = errors.New("invalid or expired storage URL")
+)
+
+// Storage stores objects under slash-separated keys such as proofs/<id>/<file>.png
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes an object; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL that serves the object to anyone holding it until ttl passes
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// New creates the backend selected by STORAGE_BACKEND
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.StorageBackend {
+	case "", BackendLocal:
+		return NewLocal(cfg.StoragePath, cfg.JWTSecret), nil
+	case BackendS3:
+		return NewS3(S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			SessionToken:    cfg.AWSSessionToken,
+			PathStyle:       cfg.S3PathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.StorageBackend)
+	}
+}
+
+// Key returns the storage key of a stored screenshot path. Paths recorded before
+// the storage backend existed look like /proofs/<file>, which is the same file
+// under the local storage root.
+func Key(path string) string {
+	return strings.TrimPrefix(path, "/")
+}
+
+// validKey rejects keys that are empty or could step outside the storage root
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
+
+// escapeKey percent-encodes every key segment with the strict RFC 3986 rules
+// that S3 signatures require
+func escapeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = uriEncode(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
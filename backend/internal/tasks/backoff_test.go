@@ -0,0 +1,53 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDoublesUpToCap(t *testing.T) {
+	cfg := RetryConfig{BaseBackoff: time.Minute, MaxBackoff: 10 * time.Minute}
+	want := []time.Duration{
+		time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute,
+		10 * time.Minute, 10 * time.Minute,
+	}
+	for retry, w := range want {
+		if got := cfg.Backoff(retry); got != w {
+			t.Errorf("Backoff(%d) = %s, want %s", retry, got, w)
+		}
+	}
+
+	// Shifts that would overflow stay at the cap
+	for _, retry := range []int{31, 32, 63, 64, 1000} {
+		if got := cfg.Backoff(retry); got != cfg.MaxBackoff {
+			t.Errorf("Backoff(%d) = %s, want the cap %s", retry, got, cfg.MaxBackoff)
+		}
+	}
+	if got := cfg.Backoff(-1); got != time.Minute {
+		t.Errorf("Backoff(-1) = %s, want %s", got, time.Minute)
+	}
+}
+
+func TestBackoffJitterStaysInRangeAndUnderCap(t *testing.T) {
+	cfg := RetryConfig{BaseBackoff: time.Minute, MaxBackoff: 30 * time.Minute, Jitter: 0.2}
+	for retry := 0; retry < 40; retry++ {
+		base := RetryConfig{BaseBackoff: cfg.BaseBackoff, MaxBackoff: cfg.MaxBackoff}.Backoff(retry)
+		low := time.Duration(float64(base) * (1 - cfg.Jitter))
+		high := time.Duration(float64(base) * (1 + cfg.Jitter))
+		if high > cfg.MaxBackoff {
+			high = cfg.MaxBackoff
+		}
+
+		seen := make(map[time.Duration]bool)
+		for i := 0; i < 200; i++ {
+			got := cfg.Backoff(retry)
+			if got < low || got > high {
+				t.Fatalf("Backoff(%d) = %s, want within [%s, %s]", retry, got, low, high)
+			}
+			seen[got] = true
+		}
+		if len(seen) < 2 {
+			t.Errorf("Backoff(%d) returned the same delay every time; jitter is not applied", retry)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/web3airdropos/backend/internal/circuit"
+	"github.com/web3airdropos/backend/internal/locks"
+	"github.com/web3airdropos/backend/internal/services/platforms"
+)
+
+// ErrManualRequired is returned by an executor that cannot complete the task
+// itself; the execution waits for the user instead of failing or retrying
+var ErrManualRequired = errors.New("manual action required")
+
+// Error codes stored in TaskExecution.ErrorCode, so clients can tell failures
+// apart without parsing ErrorMessage
+const (
+	ErrorCodeRateLimited        = "RATE_LIMITED"        // Platform or local rate limit; retry after the window
+	ErrorCodeAuthFailed         = "AUTH_FAILED"         // Credentials or signer rejected; the account needs attention
+	ErrorCodeAccountSuspended   = "ACCOUNT_SUSPENDED"   // The platform suspended the account
+	ErrorCodeAlreadyDone        = "ALREADY_DONE"        // The action was already taken on the platform
+	ErrorCodeNotFound           = "NOT_FOUND"           // Target post, user or task does not exist
+	ErrorCodeNotSupported       = "NOT_SUPPORTED"       // No adapter or executor supports the action
+	ErrorCodeDependencyFailed   = "DEPENDENCY_FAILED"   // A task this one depends on has not completed
+	ErrorCodeManualRequired     = "MANUAL_REQUIRED"     // Waiting for the user to act
+	ErrorCodeVerificationFailed = "VERIFICATION_FAILED" // The action could not be confirmed on the platform
+	ErrorCodeCircuitOpen        = "CIRCUIT_OPEN"        // Failing fast after repeated failures for the account
+	ErrorCodeLocked             = "LOCKED"              // Another execution holds the account or wallet
+	ErrorCodeTxReverted         = "TX_REVERTED"         // The transaction was mined but reverted
+	ErrorCodeTxDropped          = "TX_DROPPED"          // The transaction was not mined in time; dropped or replaced
+	ErrorCodeTimeout            = "TIMEOUT"
+	ErrorCodeCancelled          = "CANCELLED"
+	ErrorCodeInterrupted        = "INTERRUPTED" // The process stopped before the execution finished
+	ErrorCodePlatformError      = "PLATFORM_ERROR"
+)
+
+// ErrorCodeFor classifies an execution error. Errors it does not recognize are
+// reported as PLATFORM_ERROR, since most executions fail inside an adapter call.
+func ErrorCodeFor(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrManualRequired):
+		return ErrorCodeManualRequired
+	case errors.Is(err, platforms.ErrRateLimited):
+		return ErrorCodeRateLimited
+	case errors.Is(err, platforms.ErrAuthenticationFailed),
+		errors.Is(err, platforms.ErrSignerRevoked),
+		errors.Is(err, platforms.ErrWarpcastKeyRequired):
+		return ErrorCodeAuthFailed
+	case errors.Is(err, platforms.ErrAccountSuspended):
+		return ErrorCodeAccountSuspended
+	case errors.Is(err, platforms.ErrAlreadyFollowing), errors.Is(err, platforms.ErrAlreadyLiked):
+		return ErrorCodeAlreadyDone
+	case errors.Is(err, platforms.ErrPostNotFound), errors.Is(err, platforms.ErrUserNotFound):
+		return ErrorCodeNotFound
+	case errors.Is(err, platforms.ErrNotImplemented):
+		return ErrorCodeNotSupported
+	case errors.Is(err, circuit.ErrCircuitOpen):
+		return ErrorCodeCircuitOpen
+	case errors.Is(err, locks.ErrLockNotAcquired):
+		return ErrorCodeLocked
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorCodeTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorCodeCancelled
+	}
+	return ErrorCodePlatformError
+}
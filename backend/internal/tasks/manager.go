@@ -7,25 +7,36 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"math/rand"
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 
+	"github.com/web3airdropos/backend/internal/circuit"
 	"github.com/web3airdropos/backend/internal/locks"
+	"github.com/web3airdropos/backend/internal/metrics"
+	"github.com/web3airdropos/backend/internal/models"
 	"github.com/web3airdropos/backend/internal/queue"
+	"github.com/web3airdropos/backend/internal/services/platforms"
+	"github.com/web3airdropos/backend/internal/tracing"
 )
 
-// TaskStatus represents the status of a task execution
-type TaskStatus string
+// TaskStatus represents the status of a task execution. It is the models enum,
+// so executions from the manager and TaskService share one vocabulary.
+type TaskStatus = models.TaskExecutionStatus
 
 const (
-	StatusPending        TaskStatus = "PENDING"
-	StatusRunning        TaskStatus = "RUNNING"
-	StatusManualRequired TaskStatus = "MANUAL_REQUIRED"
-	StatusDone           TaskStatus = "DONE"
-	StatusFailed         TaskStatus = "FAILED"
-	StatusSkipped        TaskStatus = "SKIPPED"
+	StatusPending        = models.ExecutionPending
+	StatusRunning        = models.ExecutionInProgress
+	StatusManualRequired = models.ExecutionWaitingManual
+	StatusDone           = models.ExecutionCompleted
+	StatusFailed         = models.ExecutionFailed
+	StatusSkipped        = models.ExecutionSkipped
 )
 
 // ProofType represents types of proof for task completion
@@ -80,6 +91,10 @@ type TaskExecution struct {
 	ErrorMessage    string `gorm:"type:text" json:"error_message,omitempty"`
 	ErrorCode       string `gorm:"size:50" json:"error_code,omitempty"`
 
+	// Transaction tracking, see models.TaskExecution
+	TxStatus      models.TxStatus `gorm:"size:20" json:"tx_status,omitempty"`
+	TxSubmittedAt *time.Time      `json:"tx_submitted_at,omitempty"`
+
 	// Browser session
 	BrowserSessionID *uuid.UUID `gorm:"type:uuid" json:"browser_session_id,omitempty"`
 
@@ -95,6 +110,12 @@ type TaskExecution struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// AfterFind reports rows stored before status normalization with canonical statuses
+func (e *TaskExecution) AfterFind(tx *gorm.DB) error {
+	e.Status = models.NormalizeExecutionStatus(e.Status)
+	return nil
+}
+
 // ExecutionRequest represents a request to execute a task
 type ExecutionRequest struct {
 	TaskID    uuid.UUID              `json:"task_id"`
@@ -118,14 +139,66 @@ type TaskExecutor interface {
 	CanHandle(taskType string) bool
 }
 
+// RetryConfig configures how failed executions are retried
+type RetryConfig struct {
+	MaxRetries  int           // Maximum retry attempts per execution
+	BaseBackoff time.Duration // Delay before the first retry, doubled on each attempt
+	MaxBackoff  time.Duration // Upper bound for the exponential delay
+	Jitter      float64       // Random spread applied to the delay (0.2 = ±20%)
+}
+
+// DefaultRetryConfig returns default retry configuration
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries:  3,
+		BaseBackoff: time.Minute,
+		MaxBackoff:  30 * time.Minute,
+		Jitter:      0.2,
+	}
+}
+
+// Backoff returns the delay before the given retry attempt.
+// The exponential delay is capped at MaxBackoff before jitter is applied so
+// that failing tasks don't all hit the platform API at the same moment.
+func (c RetryConfig) Backoff(retryCount int) time.Duration {
+	if retryCount < 0 {
+		retryCount = 0
+	}
+
+	backoff := c.MaxBackoff
+	// Guard the shift against overflow for large retry counts
+	if retryCount < 32 {
+		if exp := c.BaseBackoff * time.Duration(1<<uint(retryCount)); exp > 0 && exp < c.MaxBackoff {
+			backoff = exp
+		}
+	}
+
+	if c.Jitter > 0 {
+		// Keep the jittered delay within the cap
+		spread := float64(backoff) * c.Jitter
+		backoff = time.Duration(float64(backoff) - spread + rand.Float64()*2*spread)
+		if backoff > c.MaxBackoff {
+			backoff = c.MaxBackoff
+		}
+	}
+
+	return backoff
+}
+
 // TaskManager manages task execution with idempotency and locking
 type TaskManager struct {
-	db          *gorm.DB
-	lockManager *locks.LockManager
-	taskQueue   *queue.Queue
-	executors   map[string]TaskExecutor
+	db           *gorm.DB
+	lockManager  *locks.LockManager
+	taskQueue    *queue.Queue
+	executors    map[string]TaskExecutor
+	retry        RetryConfig
+	onDeadLetter DeadLetterNotifier
+	circuits     *circuit.Breaker // Nil disables circuit breaking
 }
 
+// DeadLetterNotifier is called after an execution is moved to the dead-letter queue
+type DeadLetterNotifier func(ctx context.Context, entry *models.DeadLetterJob)
+
 // NewTaskManager creates a new task manager
 func NewTaskManager(db *gorm.DB, lockManager *locks.LockManager, taskQueue *queue.Queue) *TaskManager {
 	return &TaskManager{
@@ -133,7 +206,41 @@ func NewTaskManager(db *gorm.DB, lockManager *locks.LockManager, taskQueue *queu
 		lockManager: lockManager,
 		taskQueue:   taskQueue,
 		executors:   make(map[string]TaskExecutor),
+		retry:       DefaultRetryConfig(),
+	}
+}
+
+// SetRetryConfig overrides the retry configuration.
+// Zero values fall back to the defaults.
+func (m *TaskManager) SetRetryConfig(config RetryConfig) {
+	defaults := DefaultRetryConfig()
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = defaults.MaxRetries
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = defaults.BaseBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaults.MaxBackoff
 	}
+	if config.MaxBackoff < config.BaseBackoff {
+		config.MaxBackoff = config.BaseBackoff
+	}
+	if config.Jitter < 0 || config.Jitter >= 1 {
+		config.Jitter = defaults.Jitter
+	}
+	m.retry = config
+}
+
+// SetDeadLetterNotifier sets the callback for dead-lettered executions
+func (m *TaskManager) SetDeadLetterNotifier(notifier DeadLetterNotifier) {
+	m.onDeadLetter = notifier
+}
+
+// SetCircuitBreaker sets the breaker that fast-fails executions for platform
+// accounts that keep failing
+func (m *TaskManager) SetCircuitBreaker(breaker *circuit.Breaker) {
+	m.circuits = breaker
 }
 
 // RegisterExecutor registers a task executor for a task type
@@ -156,14 +263,45 @@ func GenerateIdempotencyKey(taskID uuid.UUID, accountID, walletID *uuid.UUID, da
 	return hex.EncodeToString(hash[:])
 }
 
-// Execute executes a task with idempotency checking and locking
+// Execute executes a task with idempotency checking and locking, in a span covering
+// the lock wait, the executor and the bookkeeping around it
 func (m *TaskManager) Execute(ctx context.Context, req *ExecutionRequest) (*ExecutionResult, error) {
+	attrs := []attribute.KeyValue{
+		attribute.String("task.id", req.TaskID.String()),
+		attribute.String("user.id", req.UserID.String()),
+	}
+	if req.AccountID != nil {
+		attrs = append(attrs, attribute.String("account.id", req.AccountID.String()))
+	}
+	if req.WalletID != nil {
+		attrs = append(attrs, attribute.String("wallet.id", req.WalletID.String()))
+	}
+	ctx, span := tracing.Start(ctx, "task.execute", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	result, err := m.execute(ctx, req)
+	if err == nil && result.Error != nil {
+		err = result.Error
+	}
+	if result != nil && result.Execution != nil {
+		span.SetAttributes(attribute.String("task.status", string(result.Execution.Status)))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return result, err
+}
+
+func (m *TaskManager) execute(ctx context.Context, req *ExecutionRequest) (*ExecutionResult, error) {
+	db := m.db.WithContext(ctx)
+
 	// Generate idempotency key
 	idempotencyKey := GenerateIdempotencyKey(req.TaskID, req.AccountID, req.WalletID, time.Now())
 
 	// Check for existing execution (idempotency)
 	var existingExec TaskExecution
-	err := m.db.Where("idempotency_key = ?", idempotencyKey).First(&existingExec).Error
+	err := db.Where("idempotency_key = ?", idempotencyKey).First(&existingExec).Error
 	if err == nil && !req.Force {
 		// Already executed
 		if existingExec.Status == StatusDone {
@@ -211,7 +349,7 @@ func (m *TaskManager) Execute(ctx context.Context, req *ExecutionRequest) (*Exec
 		Status:         StatusRunning,
 		StartedAt:      &now,
 		IdempotencyKey: idempotencyKey,
-		MaxRetries:     3,
+		MaxRetries:     m.retry.MaxRetries,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
@@ -221,9 +359,9 @@ func (m *TaskManager) Execute(ctx context.Context, req *ExecutionRequest) (*Exec
 		execution.ID = existingExec.ID
 		execution.RetryCount = existingExec.RetryCount + 1
 		execution.CreatedAt = existingExec.CreatedAt
-		m.db.Save(execution)
+		db.Save(execution)
 	} else {
-		if err := m.db.Create(execution).Error; err != nil {
+		if err := db.Create(execution).Error; err != nil {
 			return nil, fmt.Errorf("failed to create execution record: %w", err)
 		}
 	}
@@ -232,14 +370,17 @@ func (m *TaskManager) Execute(ctx context.Context, req *ExecutionRequest) (*Exec
 	var task struct {
 		Type           string
 		RequiresManual bool
+		TargetPlatform string
 	}
-	if err := m.db.Table("campaign_tasks").
-		Select("type, requires_manual").
+	if err := db.Table("campaign_tasks").
+		Select("type, requires_manual, target_platform").
 		Where("id = ?", req.TaskID).
 		First(&task).Error; err != nil {
 		execution.Status = StatusFailed
 		execution.ErrorMessage = "Task not found"
-		m.db.Save(execution)
+		execution.ErrorCode = ErrorCodeNotFound
+		db.Save(execution)
+		metrics.TaskExecutions.WithLabelValues("unknown", metrics.TaskResultFailed).Inc()
 		return &ExecutionResult{
 			Execution: execution,
 			Error:     err,
@@ -249,7 +390,9 @@ func (m *TaskManager) Execute(ctx context.Context, req *ExecutionRequest) (*Exec
 	// Check if requires manual intervention
 	if task.RequiresManual {
 		execution.Status = StatusManualRequired
-		m.db.Save(execution)
+		execution.ErrorCode = ErrorCodeManualRequired
+		db.Save(execution)
+		metrics.TaskExecutions.WithLabelValues(task.Type, metrics.TaskResultManualRequired).Inc()
 		return &ExecutionResult{
 			Execution: execution,
 		}, nil
@@ -260,22 +403,57 @@ func (m *TaskManager) Execute(ctx context.Context, req *ExecutionRequest) (*Exec
 	if !exists {
 		execution.Status = StatusFailed
 		execution.ErrorMessage = fmt.Sprintf("No executor for task type: %s", task.Type)
-		m.db.Save(execution)
+		execution.ErrorCode = ErrorCodeNotSupported
+		db.Save(execution)
+		metrics.TaskExecutions.WithLabelValues(task.Type, metrics.TaskResultFailed).Inc()
 		return &ExecutionResult{
 			Execution: execution,
 			Error:     fmt.Errorf("no executor for task type: %s", task.Type),
 		}, nil
 	}
 
-	// Execute the task
-	result, err := executor.Execute(ctx, req)
+	// Execute the task, failing fast while the platform keeps failing for this account
+	var circuitKey string
+	if req.AccountID != nil && task.TargetPlatform != "" {
+		circuitKey = circuit.Key(task.TargetPlatform, req.AccountID.String())
+	}
+	var result *ExecutionResult
+	err = m.circuits.Allow(circuitKey)
+	if err == nil {
+		result, err = executor.Execute(ctx, req)
+		if circuitKey != "" && !errors.Is(err, ErrManualRequired) {
+			m.circuits.Record(circuitKey, err)
+		}
+	}
+	if errors.Is(err, ErrManualRequired) {
+		execution.Status = StatusManualRequired
+		execution.ErrorMessage = err.Error()
+		execution.ErrorCode = ErrorCodeManualRequired
+		db.Save(execution)
+		metrics.TaskExecutions.WithLabelValues(task.Type, metrics.TaskResultManualRequired).Inc()
+		return &ExecutionResult{
+			Execution: execution,
+		}, nil
+	}
 	if err != nil {
 		execution.Status = StatusFailed
 		execution.ErrorMessage = err.Error()
+		execution.ErrorCode = ErrorCodeFor(err)
 
 		// Schedule retry if not exceeded
 		if execution.RetryCount < execution.MaxRetries {
-			backoff := time.Duration(1<<uint(execution.RetryCount)) * time.Minute
+			backoff := m.retry.Backoff(execution.RetryCount)
+
+			// Respect the platform's own wait time when it told us one
+			var rlErr *platforms.RateLimitError
+			if errors.As(err, &rlErr) && rlErr.RetryAfter > backoff {
+				backoff = rlErr.RetryAfter
+			}
+			// Don't retry into a circuit that is still open
+			var openErr *circuit.OpenError
+			if errors.As(err, &openErr) && openErr.RetryAfter > backoff {
+				backoff = openErr.RetryAfter
+			}
 			nextRetry := time.Now().Add(backoff)
 			execution.NextRetryAt = &nextRetry
 
@@ -286,7 +464,11 @@ func (m *TaskManager) Execute(ctx context.Context, req *ExecutionRequest) (*Exec
 			}, queue.WithDelay(backoff))
 		}
 
-		m.db.Save(execution)
+		db.Save(execution)
+		if execution.RetryCount >= execution.MaxRetries {
+			m.deadLetter(ctx, req, task.Type, execution)
+		}
+		metrics.TaskExecutions.WithLabelValues(task.Type, metrics.TaskResultFailed).Inc()
 		return &ExecutionResult{
 			Execution: execution,
 			Error:     err,
@@ -314,7 +496,16 @@ func (m *TaskManager) Execute(ctx context.Context, req *ExecutionRequest) (*Exec
 		execution.ResultData = result.Execution.ResultData
 	}
 
-	m.db.Save(execution)
+	// A sent transaction only counts once mined; the receipt poller completes it
+	if execution.TransactionHash != "" {
+		execution.Status = models.ExecutionVerifying
+		execution.CompletedAt = nil
+		execution.TxStatus = models.TxPending
+		execution.TxSubmittedAt = &completedAt
+	}
+
+	db.Save(execution)
+	metrics.TaskExecutions.WithLabelValues(task.Type, metrics.TaskResultSuccess).Inc()
 
 	return &ExecutionResult{
 		Execution: execution,
@@ -322,6 +513,48 @@ func (m *TaskManager) Execute(ctx context.Context, req *ExecutionRequest) (*Exec
 	}, nil
 }
 
+// deadLetter records an execution that failed its last retry. The request is kept
+// as context so the execution can be replayed with ReplayRequest.
+func (m *TaskManager) deadLetter(ctx context.Context, req *ExecutionRequest, taskType string, execution *TaskExecution) {
+	replay := *req
+	replay.Force = true
+	reqJSON, _ := json.Marshal(&replay)
+
+	entry := &models.DeadLetterJob{
+		ID:              uuid.New(),
+		UserID:          req.UserID,
+		Source:          models.DeadLetterSourceTask,
+		TaskID:          &req.TaskID,
+		TaskExecutionID: &execution.ID,
+		Type:            taskType,
+		Name:            taskType + " task",
+		Attempts:        execution.RetryCount + 1,
+		LastError:       execution.ErrorMessage,
+		Context:         string(reqJSON),
+		Status:          "dead",
+	}
+	if err := m.db.WithContext(ctx).Create(entry).Error; err != nil {
+		log.Printf("❌ Failed to dead-letter execution %s: %v", execution.ID, err)
+		return
+	}
+	if m.onDeadLetter != nil {
+		m.onDeadLetter(ctx, entry)
+	}
+}
+
+// ReplayRequest rebuilds the execution request stored with a dead-lettered task
+func ReplayRequest(entry *models.DeadLetterJob) (*ExecutionRequest, error) {
+	if entry.Source != models.DeadLetterSourceTask {
+		return nil, fmt.Errorf("dead-letter entry %s is not a task execution", entry.ID)
+	}
+	var req ExecutionRequest
+	if err := json.Unmarshal([]byte(entry.Context), &req); err != nil {
+		return nil, fmt.Errorf("invalid dead-letter context: %w", err)
+	}
+	req.Force = true
+	return &req, nil
+}
+
 // ContinueManualTask continues a task that required manual intervention
 func (m *TaskManager) ContinueManualTask(ctx context.Context, executionID uuid.UUID, proof *TaskProof) (*ExecutionResult, error) {
 	var execution TaskExecution
@@ -336,6 +569,7 @@ func (m *TaskManager) ContinueManualTask(ctx context.Context, executionID uuid.U
 	now := time.Now()
 	execution.Status = StatusDone
 	execution.CompletedAt = &now
+	execution.ErrorCode = ""
 
 	if proof != nil {
 		execution.ProofType = string(proof.Type)
@@ -381,7 +615,7 @@ func (m *TaskManager) ListExecutions(ctx context.Context, taskID uuid.UUID, stat
 
 	query := m.db.Model(&TaskExecution{}).Where("task_id = ?", taskID)
 	if status != nil {
-		query = query.Where("status = ?", *status)
+		query = query.Where("status IN ?", models.ExecutionStatusValues(*status))
 	}
 
 	if err := query.Count(&total).Error; err != nil {
@@ -398,10 +632,11 @@ func (m *TaskManager) ListExecutions(ctx context.Context, taskID uuid.UUID, stat
 // CancelExecution cancels a pending or running execution
 func (m *TaskManager) CancelExecution(ctx context.Context, executionID uuid.UUID) error {
 	result := m.db.Model(&TaskExecution{}).
-		Where("id = ? AND status IN ?", executionID, []TaskStatus{StatusPending, StatusRunning, StatusManualRequired}).
+		Where("id = ? AND status IN ?", executionID, models.ExecutionStatusValues(StatusPending, StatusRunning, StatusManualRequired)).
 		Updates(map[string]interface{}{
 			"status":        StatusSkipped,
 			"error_message": "Cancelled by user",
+			"error_code":    ErrorCodeCancelled,
 			"updated_at":    time.Now(),
 		})
 
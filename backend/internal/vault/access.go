@@ -0,0 +1,81 @@
+package vault
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/web3airdropos/backend/internal/audit"
+)
+
+// AccessContext describes who is touching a secret, recorded with every audit entry
+type AccessContext struct {
+	Subsystem string    // Calling component, e.g. "api", "auth", "scheduler"
+	UserID    uuid.UUID // Acting user for operations spanning every user, e.g. the admin running RotateAll
+	IPAddress string
+	UserAgent string
+}
+
+type accessContextKey struct{}
+
+// WithAccessContext attaches caller details to ctx for vault audit entries
+func WithAccessContext(ctx context.Context, access AccessContext) context.Context {
+	return context.WithValue(ctx, accessContextKey{}, access)
+}
+
+// WithSubsystem is shorthand for WithAccessContext when only the caller name is known
+func WithSubsystem(ctx context.Context, subsystem string) context.Context {
+	return WithAccessContext(ctx, AccessContext{Subsystem: subsystem})
+}
+
+func accessContextFrom(ctx context.Context) AccessContext {
+	access, _ := ctx.Value(accessContextKey{}).(AccessContext)
+	if access.Subsystem == "" {
+		access.Subsystem = "unknown"
+	}
+	return access
+}
+
+// auditLog is the part of *audit.Logger the vault writes to
+type auditLog interface {
+	Log(ctx context.Context, entry *audit.LogEntry) (*audit.AuditLog, error)
+}
+
+// SetAuditLogger enables audit logging of every vault read and write
+func (v *Vault) SetAuditLogger(logger *audit.Logger) {
+	if logger == nil {
+		v.auditLogger = nil
+		return
+	}
+	v.auditLogger = logger
+}
+
+// logAccess records a vault operation with the caller taken from ctx
+func (v *Vault) logAccess(ctx context.Context, userID uuid.UUID, action audit.Action, name string, details map[string]interface{}, err error) {
+	if v.auditLogger == nil {
+		return
+	}
+
+	access := accessContextFrom(ctx)
+	data := map[string]interface{}{"subsystem": access.Subsystem}
+	for k, val := range details {
+		data[k] = val
+	}
+
+	entry := &audit.LogEntry{
+		UserID:      userID,
+		Action:      action,
+		Result:      audit.ResultSuccess,
+		TargetType:  "secret",
+		TargetID:    name,
+		RequestData: data,
+		IPAddress:   access.IPAddress,
+		UserAgent:   access.UserAgent,
+	}
+	if err != nil {
+		entry.Result = audit.ResultFailed
+		entry.ErrorMessage = err.Error()
+	}
+
+	v.auditLogger.Log(ctx, entry)
+}
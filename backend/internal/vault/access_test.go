@@ -0,0 +1,87 @@
+package vault
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/web3airdropos/backend/internal/audit"
+)
+
+// recordedLog keeps audit entries in memory
+type recordedLog struct{ entries []*audit.LogEntry }
+
+func (r *recordedLog) Log(ctx context.Context, entry *audit.LogEntry) (*audit.AuditLog, error) {
+	r.entries = append(r.entries, entry)
+	return &audit.AuditLog{}, nil
+}
+
+func TestRollbackIsAuditedOnce(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := NewVault(db, Config{MasterKey: "0000000000000000000000000000000000000000000000000000000000000001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := &recordedLog{}
+	v.auditLogger = recorded
+
+	ctx := context.Background()
+	current, err := v.sealValue(ctx, []byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous, err := v.sealValue(ctx, []byte("old"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userID, secretID := uuid.New(), uuid.New()
+	secretRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "user_id", "name", "key_type", "encrypted_value", "iv", "wrapped_key", "key_provider", "key_version", "version"}).
+			AddRow(secretID, userID, "api_key", "api_key", current.EncryptedValue, current.IV, current.WrappedKey, current.KeyProvider, current.KeyVersion, 2)
+	}
+
+	mock.ExpectQuery(`SELECT \* FROM "secrets_vault"`).WillReturnRows(secretRow())
+	mock.ExpectQuery(`SELECT \* FROM "secret_versions"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "secret_id", "version", "encrypted_value", "iv", "wrapped_key", "key_provider", "key_version"}).
+			AddRow(uuid.New(), secretID, 1, previous.EncryptedValue, previous.IV, previous.WrappedKey, previous.KeyProvider, previous.KeyVersion))
+	mock.ExpectQuery(`SELECT \* FROM "secrets_vault"`).WillReturnRows(secretRow())
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "secret_versions"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectExec(`UPDATE "secrets_vault"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := v.Rollback(ctx, userID, "api_key", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recorded.entries) != 1 {
+		t.Fatalf("rollback wrote %d audit entries, want 1", len(recorded.entries))
+	}
+	entry := recorded.entries[0]
+	if entry.Action != audit.ActionSecretRollback || entry.Result != audit.ResultSuccess {
+		t.Fatalf("entry = %s/%s, want a successful rollback", entry.Action, entry.Result)
+	}
+	data, _ := entry.RequestData.(map[string]interface{})
+	for key, want := range map[string]int{"restored_version": 1, "from_version": 2, "to_version": 3} {
+		if got := data[key]; got != want {
+			t.Errorf("%s = %v, want %d", key, got, want)
+		}
+	}
+}
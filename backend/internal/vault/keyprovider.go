@@ -0,0 +1,129 @@
+package vault
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Key provider names
+const (
+	KeyProviderLocal        = "local"
+	KeyProviderAWSKMS       = "aws-kms"
+	KeyProviderGCPKMS       = "gcp-kms"
+	KeyProviderVaultTransit = "vault-transit"
+)
+
+// ErrKeyProviderMismatch is returned when a secret was wrapped by a different provider
+var ErrKeyProviderMismatch = errors.New("secret was encrypted with a different key provider")
+
+// KeyProvider wraps and unwraps per-secret data encryption keys (envelope encryption).
+// Secret values are encrypted locally with the data key; only the wrapped key is stored.
+type KeyProvider interface {
+	// Name identifies the provider in stored secrets
+	Name() string
+	// WrapKey encrypts a data key, returning an opaque string safe to store
+	WrapKey(ctx context.Context, dataKey []byte) (string, error)
+	// UnwrapKey decrypts a data key previously returned by WrapKey
+	UnwrapKey(ctx context.Context, wrapped string) ([]byte, error)
+}
+
+// LocalKeyProvider wraps data keys with the process-held master key.
+// Intended for development; use a KMS provider in production.
+type LocalKeyProvider struct {
+	masterKey []byte
+}
+
+// NewLocalKeyProvider creates a provider from a 32-byte master key
+func NewLocalKeyProvider(masterKey []byte) (*LocalKeyProvider, error) {
+	if len(masterKey) != 32 {
+		return nil, ErrInvalidKey
+	}
+	return &LocalKeyProvider{masterKey: masterKey}, nil
+}
+
+// Name returns the provider name
+func (p *LocalKeyProvider) Name() string {
+	return KeyProviderLocal
+}
+
+// WrapKey encrypts the data key with AES-256-GCM under the master key
+func (p *LocalKeyProvider) WrapKey(ctx context.Context, dataKey []byte) (string, error) {
+	nonce := make([]byte, 12)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	gcm, err := p.gcm()
+	if err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nil, nonce, dataKey, nil)
+
+	return base64.StdEncoding.EncodeToString(append(nonce, sealed...)), nil
+}
+
+// UnwrapKey decrypts a data key wrapped by WrapKey
+func (p *LocalKeyProvider) UnwrapKey(ctx context.Context, wrapped string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil || len(raw) < 12 {
+		return nil, ErrDecryptionFailed
+	}
+
+	gcm, err := p.gcm()
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := gcm.Open(nil, raw[:12], raw[12:], nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return dataKey, nil
+}
+
+func (p *LocalKeyProvider) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(p.masterKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// newKeyProvider selects the key provider from config
+func newKeyProvider(config Config, masterKey []byte) (KeyProvider, error) {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+
+	switch config.KeyProvider {
+	case "", KeyProviderLocal:
+		return NewLocalKeyProvider(masterKey)
+	case KeyProviderAWSKMS:
+		return NewAWSKMSProvider(httpClient, AWSKMSConfig{
+			KeyID:           config.KMSKeyID,
+			Region:          config.AWSRegion,
+			AccessKeyID:     config.AWSAccessKeyID,
+			SecretAccessKey: config.AWSSecretAccessKey,
+			SessionToken:    config.AWSSessionToken,
+		})
+	case KeyProviderGCPKMS:
+		return NewGCPKMSProvider(httpClient, GCPKMSConfig{
+			KeyName:     config.KMSKeyID,
+			AccessToken: config.GCPAccessToken,
+		})
+	case KeyProviderVaultTransit:
+		return NewTransitProvider(httpClient, TransitConfig{
+			Address: config.VaultAddress,
+			Token:   config.VaultToken,
+			Mount:   config.VaultTransitMount,
+			KeyName: config.KMSKeyID,
+		})
+	default:
+		return nil, fmt.Errorf("unknown vault key provider: %s", config.KeyProvider)
+	}
+}
@@ -0,0 +1,177 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWSKMSConfig configures the AWS KMS key provider
+type AWSKMSConfig struct {
+	KeyID           string // Key ID, ARN or alias
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Optional, for temporary credentials
+}
+
+// AWSKMSProvider wraps data keys with AWS KMS Encrypt/Decrypt.
+// Requests are signed with SigV4 directly to avoid pulling in the AWS SDK.
+type AWSKMSProvider struct {
+	httpClient *http.Client
+	config     AWSKMSConfig
+	endpoint   string
+}
+
+// NewAWSKMSProvider creates an AWS KMS key provider
+func NewAWSKMSProvider(httpClient *http.Client, config AWSKMSConfig) (*AWSKMSProvider, error) {
+	if config.KeyID == "" || config.Region == "" {
+		return nil, errors.New("aws kms: key id and region are required")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, errors.New("aws kms: access key credentials are required")
+	}
+
+	return &AWSKMSProvider{
+		httpClient: httpClient,
+		config:     config,
+		endpoint:   fmt.Sprintf("https://kms.%s.amazonaws.com/", config.Region),
+	}, nil
+}
+
+// Name returns the provider name
+func (p *AWSKMSProvider) Name() string {
+	return KeyProviderAWSKMS
+}
+
+// WrapKey encrypts the data key with the configured KMS key
+func (p *AWSKMSProvider) WrapKey(ctx context.Context, dataKey []byte) (string, error) {
+	var resp struct {
+		CiphertextBlob string `json:"CiphertextBlob"`
+	}
+	err := p.call(ctx, "TrentService.Encrypt", map[string]string{
+		"KeyId":     p.config.KeyID,
+		"Plaintext": base64.StdEncoding.EncodeToString(dataKey),
+	}, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.CiphertextBlob, nil
+}
+
+// UnwrapKey decrypts a data key with KMS
+func (p *AWSKMSProvider) UnwrapKey(ctx context.Context, wrapped string) ([]byte, error) {
+	var resp struct {
+		Plaintext string `json:"Plaintext"`
+	}
+	err := p.call(ctx, "TrentService.Decrypt", map[string]string{
+		"KeyId":          p.config.KeyID,
+		"CiphertextBlob": wrapped,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := base64.StdEncoding.DecodeString(resp.Plaintext)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return dataKey, nil
+}
+
+func (p *AWSKMSProvider) call(ctx context.Context, target string, payload interface{}, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", target)
+	p.sign(req, body, time.Now().UTC())
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("aws kms request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("aws kms error %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return json.Unmarshal(respBody, result)
+}
+
+// sign adds an AWS Signature Version 4 Authorization header
+func (p *AWSKMSProvider) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if p.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.config.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(req.Header.Get(name))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := dateStamp + "/" + p.config.Region + "/kms/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+p.config.SecretAccessKey), dateStamp)
+	signingKey = hmacSHA256(signingKey, p.config.Region)
+	signingKey = hmacSHA256(signingKey, "kms")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		p.config.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,158 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const gcpMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// GCPKMSConfig configures the Google Cloud KMS key provider
+type GCPKMSConfig struct {
+	// KeyName is the full resource name:
+	// projects/{project}/locations/{location}/keyRings/{ring}/cryptoKeys/{key}
+	KeyName string
+	// AccessToken is an optional static OAuth token; otherwise the GCE metadata server is used
+	AccessToken string
+}
+
+// GCPKMSProvider wraps data keys with Cloud KMS via its REST API
+type GCPKMSProvider struct {
+	httpClient *http.Client
+	config     GCPKMSConfig
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewGCPKMSProvider creates a Cloud KMS key provider
+func NewGCPKMSProvider(httpClient *http.Client, config GCPKMSConfig) (*GCPKMSProvider, error) {
+	if config.KeyName == "" {
+		return nil, errors.New("gcp kms: key name is required")
+	}
+	return &GCPKMSProvider{httpClient: httpClient, config: config}, nil
+}
+
+// Name returns the provider name
+func (p *GCPKMSProvider) Name() string {
+	return KeyProviderGCPKMS
+}
+
+// WrapKey encrypts the data key with the configured crypto key
+func (p *GCPKMSProvider) WrapKey(ctx context.Context, dataKey []byte) (string, error) {
+	var resp struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	err := p.call(ctx, "encrypt", map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString(dataKey),
+	}, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.Ciphertext, nil
+}
+
+// UnwrapKey decrypts a data key with Cloud KMS
+func (p *GCPKMSProvider) UnwrapKey(ctx context.Context, wrapped string) ([]byte, error) {
+	var resp struct {
+		Plaintext string `json:"plaintext"`
+	}
+	err := p.call(ctx, "decrypt", map[string]string{
+		"ciphertext": wrapped,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := base64.StdEncoding.DecodeString(resp.Plaintext)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return dataKey, nil
+}
+
+func (p *GCPKMSProvider) call(ctx context.Context, method string, payload interface{}, result interface{}) error {
+	token, err := p.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://cloudkms.googleapis.com/v1/%s:%s", p.config.KeyName, method)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("gcp kms request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("gcp kms error %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return json.Unmarshal(respBody, result)
+}
+
+// accessToken returns the static token or a cached one from the metadata server
+func (p *GCPKMSProvider) accessToken(ctx context.Context) (string, error) {
+	if p.config.AccessToken != "" {
+		return p.config.AccessToken, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Now().Before(p.tokenExpiry) {
+		return p.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", gcpMetadataTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("gcp metadata token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("gcp metadata token error: %d", resp.StatusCode)
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", err
+	}
+
+	p.token = tokenResp.AccessToken
+	// Refresh a minute early
+	p.tokenExpiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn)*time.Second - time.Minute)
+
+	return p.token, nil
+}
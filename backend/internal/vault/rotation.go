@@ -0,0 +1,315 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/audit"
+)
+
+// Key rotation job statuses
+const (
+	RotationRunning   = "running"
+	RotationCompleted = "completed"
+	RotationFailed    = "failed"
+)
+
+// rotationBatchSize is how many user IDs are read at a time during RotateAll
+const rotationBatchSize = 100
+
+// Key rotation errors
+var (
+	ErrRotationInProgress  = errors.New("a key rotation is already running; resume it with the same keys")
+	ErrRotationKeyMismatch = errors.New("neither key is the vault's current master key")
+	ErrRotationSameKey     = errors.New("new key must differ from the old key")
+	ErrRotationJobNotFound = errors.New("key rotation job not found")
+	errRotationInterrupted = errors.New("key rotation was interrupted")
+)
+
+// KeyRotationJob tracks a RotateAll run. Users are rotated in user ID order, each
+// in one transaction together with the job's progress, so a run that stops
+// part-way resumes after LastUserID.
+type KeyRotationJob struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	FromKeyVersion string     `gorm:"size:16;not null" json:"from_key_version"`
+	ToKeyVersion   string     `gorm:"size:16;not null" json:"to_key_version"`
+	Status         string     `gorm:"size:20;not null;default:'running'" json:"status"`
+	TotalUsers     int        `gorm:"default:0" json:"total_users"`
+	RotatedUsers   int        `gorm:"default:0" json:"rotated_users"`
+	RotatedSecrets int        `gorm:"default:0" json:"rotated_secrets"` // Current values and history re-sealed
+	LastUserID     *uuid.UUID `gorm:"type:uuid" json:"last_user_id,omitempty"`
+	Error          string     `gorm:"type:text" json:"error,omitempty"`
+	StartedBy      *uuid.UUID `gorm:"type:uuid" json:"started_by,omitempty"`
+	StartedAt      time.Time  `json:"started_at"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName overrides the default table name
+func (KeyRotationJob) TableName() string {
+	return "key_rotation_jobs"
+}
+
+// RotateAll re-encrypts every user's secrets, including their history and
+// soft-deleted secrets, from oldKey to newKey. One of the two must be the
+// vault's current master key. An unfinished job for the same keys is resumed
+// rather than restarted.
+//
+// New values are sealed with newKey as soon as the run starts, and values
+// still under oldKey stay readable until it finishes. Once it completes, the
+// configured master key must be changed to newKey before the next restart.
+func (v *Vault) RotateAll(ctx context.Context, oldKey, newKey []byte) (*KeyRotationJob, error) {
+	job, from, to, err := v.beginRotation(ctx, oldKey, newKey)
+	if err != nil {
+		return nil, err
+	}
+	err = v.runRotation(ctx, job, from, to)
+	return job, err
+}
+
+// StartRotateAll is RotateAll in the background. It returns the job as it
+// starts or resumes; poll RotationJob for progress.
+func (v *Vault) StartRotateAll(ctx context.Context, oldKey, newKey []byte) (*KeyRotationJob, error) {
+	job, from, to, err := v.beginRotation(ctx, oldKey, newKey)
+	if err != nil {
+		return nil, err
+	}
+	started := *job
+
+	// The run outlives the request but keeps its caller for the audit log
+	runCtx := WithAccessContext(context.Background(), accessContextFrom(ctx))
+	go v.runRotation(runCtx, job, from, to)
+
+	return &started, nil
+}
+
+// RotationJob returns a key rotation job
+func (v *Vault) RotationJob(ctx context.Context, id uuid.UUID) (*KeyRotationJob, error) {
+	var job KeyRotationJob
+	if err := v.db.WithContext(ctx).First(&job, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRotationJobNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListRotationJobs returns the most recent key rotation jobs, newest first
+func (v *Vault) ListRotationJobs(ctx context.Context, limit int) ([]KeyRotationJob, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	var jobs []KeyRotationJob
+	err := v.db.WithContext(ctx).Order("started_at DESC").Limit(limit).Find(&jobs).Error
+	return jobs, err
+}
+
+// beginRotation checks the keys, creates or resumes the job and switches the
+// vault to the new key. On success the caller owns v.rotating until runRotation.
+func (v *Vault) beginRotation(ctx context.Context, oldKey, newKey []byte) (_ *KeyRotationJob, _, _ *Vault, err error) {
+	if !v.rotating.TryLock() {
+		return nil, nil, nil, ErrRotationInProgress
+	}
+	defer func() {
+		if err != nil {
+			v.rotating.Unlock()
+		}
+	}()
+
+	fromVersion, toVersion := KeyVersion(oldKey), KeyVersion(newKey)
+	if fromVersion == toVersion {
+		return nil, nil, nil, ErrRotationSameKey
+	}
+	// Either key may be current: the new one once a configured key change
+	// preceded a resume
+	if current := v.CurrentKeyVersion(); current != fromVersion && current != toVersion {
+		return nil, nil, nil, ErrRotationKeyMismatch
+	}
+
+	from, err := v.withKey(oldKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	to, err := v.withKey(newKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	phase := "started"
+	var job KeyRotationJob
+	err = v.db.Where("from_key_version = ? AND to_key_version = ? AND status <> ?", fromVersion, toVersion, RotationCompleted).
+		Order("started_at DESC").First(&job).Error
+	switch {
+	case err == nil:
+		// Resume where the last run stopped
+		if err := v.db.Model(&job).Updates(map[string]interface{}{"status": RotationRunning, "error": ""}).Error; err != nil {
+			return nil, nil, nil, err
+		}
+		job.Status = RotationRunning
+		job.Error = ""
+		phase = "resumed"
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		var running int64
+		if err := v.db.Model(&KeyRotationJob{}).Where("status = ?", RotationRunning).Count(&running).Error; err != nil {
+			return nil, nil, nil, err
+		}
+		if running > 0 {
+			return nil, nil, nil, ErrRotationInProgress
+		}
+
+		var total int64
+		if err := v.db.Unscoped().Model(&Secret{}).Distinct("user_id").Count(&total).Error; err != nil {
+			return nil, nil, nil, err
+		}
+
+		job = KeyRotationJob{
+			ID:             uuid.New(),
+			FromKeyVersion: fromVersion,
+			ToKeyVersion:   toVersion,
+			Status:         RotationRunning,
+			TotalUsers:     int(total),
+			StartedAt:      time.Now(),
+		}
+		if actor := accessContextFrom(ctx).UserID; actor != uuid.Nil {
+			job.StartedBy = &actor
+		}
+		if err := v.db.Create(&job).Error; err != nil {
+			return nil, nil, nil, err
+		}
+	default:
+		return nil, nil, nil, err
+	}
+
+	// Seal with the new key from now on; the old one keeps opening what is left
+	v.mu.Lock()
+	v.masterKey = newKey
+	v.provider = to.provider
+	if v.retired == nil {
+		v.retired = make(map[string]*Vault)
+	}
+	v.retired[fromVersion] = from
+	v.mu.Unlock()
+
+	v.logRotation(ctx, &job, phase, nil)
+	return &job, from, to, nil
+}
+
+// runRotation rotates the users after the job's cursor and releases v.rotating
+func (v *Vault) runRotation(ctx context.Context, job *KeyRotationJob, from, to *Vault) error {
+	defer v.rotating.Unlock()
+
+	log.Printf("🔑 Vault key rotation %s: rotating %d users from key %s to %s", job.ID, job.TotalUsers, job.FromKeyVersion, job.ToKeyVersion)
+
+	for {
+		query := v.db.Unscoped().Model(&Secret{}).Distinct("user_id").Order("user_id").Limit(rotationBatchSize)
+		if job.LastUserID != nil {
+			query = query.Where("user_id > ?", *job.LastUserID)
+		}
+		var userIDs []uuid.UUID
+		if err := query.Pluck("user_id", &userIDs).Error; err != nil {
+			return v.failRotation(ctx, job, err)
+		}
+		if len(userIDs) == 0 {
+			break
+		}
+
+		for _, userID := range userIDs {
+			if ctx.Err() != nil {
+				return v.failRotation(ctx, job, fmt.Errorf("%w: %v", errRotationInterrupted, ctx.Err()))
+			}
+
+			rotated := 0
+			err := v.db.Transaction(func(tx *gorm.DB) error {
+				var err error
+				if rotated, err = rotateUser(ctx, tx, userID, from, to); err != nil {
+					return fmt.Errorf("user %s: %w", userID, err)
+				}
+				return tx.Model(job).Updates(map[string]interface{}{
+					"rotated_users":   gorm.Expr("rotated_users + 1"),
+					"rotated_secrets": gorm.Expr("rotated_secrets + ?", rotated),
+					"last_user_id":    userID,
+				}).Error
+			})
+			if err != nil {
+				return v.failRotation(ctx, job, err)
+			}
+
+			id := userID
+			job.LastUserID = &id
+			job.RotatedUsers++
+			job.RotatedSecrets += rotated
+		}
+	}
+
+	now := time.Now()
+	if err := v.db.Model(job).Updates(map[string]interface{}{
+		"status":       RotationCompleted,
+		"completed_at": now,
+	}).Error; err != nil {
+		return v.failRotation(ctx, job, err)
+	}
+	job.Status = RotationCompleted
+	job.CompletedAt = &now
+
+	v.mu.Lock()
+	delete(v.retired, job.FromKeyVersion)
+	v.mu.Unlock()
+
+	log.Printf("✅ Vault key rotation %s: %d secret values re-sealed for %d users", job.ID, job.RotatedSecrets, job.RotatedUsers)
+	v.logRotation(ctx, job, "completed", nil)
+	return nil
+}
+
+// failRotation records why a run stopped. The old key stays registered, so
+// secrets not yet rotated remain readable until the job is resumed.
+func (v *Vault) failRotation(ctx context.Context, job *KeyRotationJob, cause error) error {
+	job.Status = RotationFailed
+	job.Error = cause.Error()
+	v.db.Model(job).Updates(map[string]interface{}{"status": RotationFailed, "error": job.Error})
+
+	log.Printf("❌ Vault key rotation %s stopped after %d of %d users: %v", job.ID, job.RotatedUsers, job.TotalUsers, cause)
+	v.logRotation(ctx, job, "failed", cause)
+	return cause
+}
+
+// logRotation records a rotation milestone against the user who started it
+func (v *Vault) logRotation(ctx context.Context, job *KeyRotationJob, phase string, err error) {
+	if v.auditLogger == nil {
+		return
+	}
+
+	access := accessContextFrom(ctx)
+	entry := &audit.LogEntry{
+		UserID:     access.UserID,
+		Action:     audit.ActionKeyRotation,
+		Result:     audit.ResultSuccess,
+		TargetType: "key_rotation_job",
+		TargetID:   job.ID.String(),
+		RequestData: map[string]interface{}{
+			"subsystem":        access.Subsystem,
+			"phase":            phase,
+			"from_key_version": job.FromKeyVersion,
+			"to_key_version":   job.ToKeyVersion,
+		},
+		ResponseData: map[string]interface{}{
+			"total_users":     job.TotalUsers,
+			"rotated_users":   job.RotatedUsers,
+			"rotated_secrets": job.RotatedSecrets,
+		},
+		IPAddress: access.IPAddress,
+		UserAgent: access.UserAgent,
+	}
+	if err != nil {
+		entry.Result = audit.ResultFailed
+		entry.ErrorMessage = err.Error()
+	}
+
+	v.auditLogger.Log(ctx, entry)
+}
@@ -0,0 +1,105 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/audit"
+)
+
+// metadataTagsKey is where a secret's tags live in its metadata
+const metadataTagsKey = "tags"
+
+// normalizeTags trims, lower-cases, de-duplicates and sorts tags
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// metadataTags reads the tags out of metadata passed to Store, which may hold
+// them as []string or, when decoded from JSON, []interface{}
+func metadataTags(metadata map[string]interface{}) []string {
+	switch raw := metadata[metadataTagsKey].(type) {
+	case []string:
+		return raw
+	case []interface{}:
+		tags := make([]string, 0, len(raw))
+		for _, tag := range raw {
+			if s, ok := tag.(string); ok {
+				tags = append(tags, s)
+			}
+		}
+		return tags
+	}
+	return nil
+}
+
+// SetTags replaces a secret's tags
+func (v *Vault) SetTags(ctx context.Context, userID uuid.UUID, name string, tags []string) (err error) {
+	tags = normalizeTags(tags)
+	defer func() {
+		v.logAccess(ctx, userID, audit.ActionSecretUpdate, name, map[string]interface{}{"tags": tags}, err)
+	}()
+
+	tagsJSON, _ := json.Marshal(tags)
+	result := v.db.Model(&Secret{}).
+		Where("user_id = ? AND name = ?", userID, name).
+		Update("metadata", gorm.Expr("jsonb_set(COALESCE(metadata, '{}'::jsonb), '{tags}', ?::jsonb)", string(tagsJSON)))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSecretNotFound
+	}
+	return nil
+}
+
+// Search lists a user's secrets whose name contains query and that carry every
+// one of tags, optionally limited to one type. Empty filters match everything.
+// Values are never decrypted.
+func (v *Vault) Search(ctx context.Context, userID uuid.UUID, query string, tags []string, keyType SecretType) ([]Secret, error) {
+	db := v.db.Where("user_id = ?", userID)
+	if query = strings.TrimSpace(query); query != "" {
+		db = db.Where("name ILIKE ?", "%"+escapeLike(query)+"%")
+	}
+	if tags = normalizeTags(tags); len(tags) > 0 {
+		tagsJSON, _ := json.Marshal(tags)
+		db = db.Where("metadata -> 'tags' @> ?::jsonb", string(tagsJSON))
+	}
+	if keyType != "" {
+		db = db.Where("key_type = ?", keyType)
+	}
+
+	var secrets []Secret
+	if err := db.Order("name").Find(&secrets).Error; err != nil {
+		return nil, err
+	}
+
+	// Clear sensitive fields
+	for i := range secrets {
+		secrets[i].EncryptedValue = ""
+		secrets[i].IV = ""
+	}
+
+	return secrets, nil
+}
+
+// escapeLike escapes LIKE wildcards so a search matches them literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,110 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// TransitConfig configures the HashiCorp Vault Transit key provider
+type TransitConfig struct {
+	Address string // e.g. https://vault.internal:8200
+	Token   string
+	Mount   string // Transit mount path, defaults to "transit"
+	KeyName string
+}
+
+// TransitProvider wraps data keys with HashiCorp Vault's Transit engine
+type TransitProvider struct {
+	httpClient *http.Client
+	config     TransitConfig
+}
+
+// NewTransitProvider creates a Vault Transit key provider
+func NewTransitProvider(httpClient *http.Client, config TransitConfig) (*TransitProvider, error) {
+	if config.Address == "" || config.Token == "" || config.KeyName == "" {
+		return nil, errors.New("vault transit: address, token and key name are required")
+	}
+	if config.Mount == "" {
+		config.Mount = "transit"
+	}
+	config.Address = strings.TrimRight(config.Address, "/")
+
+	return &TransitProvider{httpClient: httpClient, config: config}, nil
+}
+
+// Name returns the provider name
+func (p *TransitProvider) Name() string {
+	return KeyProviderVaultTransit
+}
+
+// WrapKey encrypts the data key with the transit key
+func (p *TransitProvider) WrapKey(ctx context.Context, dataKey []byte) (string, error) {
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	err := p.call(ctx, "encrypt", map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString(dataKey),
+	}, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.Data.Ciphertext, nil
+}
+
+// UnwrapKey decrypts a data key with the transit key
+func (p *TransitProvider) UnwrapKey(ctx context.Context, wrapped string) ([]byte, error) {
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	err := p.call(ctx, "decrypt", map[string]string{
+		"ciphertext": wrapped,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return dataKey, nil
+}
+
+func (p *TransitProvider) call(ctx context.Context, operation string, payload interface{}, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/%s/%s", p.config.Address, p.config.Mount, operation, p.config.KeyName)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", p.config.Token)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault transit request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vault transit error %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return json.Unmarshal(respBody, result)
+}
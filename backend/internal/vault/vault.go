@@ -5,17 +5,21 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/argon2"
 	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/audit"
 )
 
 // SecretType represents the type of secret
@@ -28,6 +32,7 @@ const (
 	SecretTypePrivateKey  SecretType = "private_key"
 	SecretTypeCertificate SecretType = "certificate"
 	SecretTypeCredentials SecretType = "credentials"
+	SecretTypeTOTP        SecretType = "totp"
 )
 
 // Common errors
@@ -36,6 +41,7 @@ var (
 	ErrInvalidKey       = errors.New("invalid encryption key")
 	ErrDecryptionFailed = errors.New("decryption failed")
 	ErrSecretExists     = errors.New("secret already exists")
+	ErrVersionNotFound  = errors.New("secret version not found")
 )
 
 // Secret represents a stored secret
@@ -46,35 +52,79 @@ type Secret struct {
 	KeyType        SecretType     `gorm:"size:50;not null" json:"key_type"`
 	EncryptedValue string         `gorm:"type:text;not null" json:"-"` // Never expose
 	IV             string         `gorm:"size:32;not null" json:"-"`   // Initialization vector
+	WrappedKey     string         `gorm:"type:text" json:"-"`          // Data key wrapped by the key provider; empty for legacy secrets
+	KeyProvider    string         `gorm:"size:50" json:"key_provider,omitempty"`
+	KeyVersion     string         `gorm:"size:16;index" json:"key_version,omitempty"` // Master key that sealed the value; empty for secrets sealed before key versions
 	Metadata       string         `gorm:"type:jsonb" json:"metadata,omitempty"`
 	ExpiresAt      *time.Time     `json:"expires_at,omitempty"`
 	LastAccessedAt *time.Time     `json:"last_accessed_at,omitempty"`
 	AccessCount    int            `gorm:"default:0" json:"access_count"`
+	Version        int            `gorm:"default:1;not null" json:"version"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+// TableName overrides the default table name
+func (Secret) TableName() string {
+	return "secrets_vault"
+}
+
+// SecretVersion is a previous value of a secret, kept so updates can be rolled back
+type SecretVersion struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SecretID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_secret_versions_secret_version" json:"secret_id"`
+	Version        int       `gorm:"not null;uniqueIndex:idx_secret_versions_secret_version" json:"version"`
+	EncryptedValue string    `gorm:"type:text;not null" json:"-"`
+	IV             string    `gorm:"size:32;not null" json:"-"`
+	WrappedKey     string    `gorm:"type:text" json:"-"`
+	KeyProvider    string    `gorm:"size:50" json:"key_provider,omitempty"`
+	KeyVersion     string    `gorm:"size:16" json:"key_version,omitempty"`
+	CreatedAt      time.Time `json:"created_at"` // When this version was superseded
+}
+
 // Vault manages encrypted secrets
 type Vault struct {
-	db         *gorm.DB
-	masterKey  []byte // 32-byte key for AES-256
-	keyDeriver func(password, salt []byte) []byte
+	db          *gorm.DB
+	mu          sync.RWMutex // Guards masterKey, provider and retired, which a rotation swaps
+	masterKey   []byte       // 32-byte key for AES-256
+	keyDeriver  func(password, salt []byte) []byte
+	provider    KeyProvider       // Wraps per-secret data keys
+	retired     map[string]*Vault // Previous keys by key version, still opening secrets mid-rotation
+	auditLogger auditLog
+	rotating    sync.Mutex // Held while RotateAll runs
 }
 
 // Config for the vault
 type Config struct {
 	MasterKey string // 32-byte hex-encoded master key
+
+	// KeyProvider selects how data keys are wrapped: local (default), aws-kms, gcp-kms, vault-transit
+	KeyProvider string
+	KMSKeyID    string // AWS key ID/ARN, GCP crypto key resource name, or Transit key name
+
+	// AWS KMS
+	AWSRegion          string
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	AWSSessionToken    string
+
+	// GCP KMS (falls back to the metadata server when empty)
+	GCPAccessToken string
+
+	// HashiCorp Vault Transit
+	VaultAddress      string
+	VaultToken        string
+	VaultTransitMount string
 }
 
 // NewVault creates a new secrets vault
 func NewVault(db *gorm.DB, config Config) (*Vault, error) {
-	// Decode or derive master key
-	masterKey, err := hex.DecodeString(config.MasterKey)
-	if err != nil || len(masterKey) != 32 {
-		// If not valid hex, derive from password using Argon2
-		salt := []byte("web3airdropos-vault-salt") // Fixed salt for key derivation
-		masterKey = argon2.IDKey([]byte(config.MasterKey), salt, 3, 64*1024, 4, 32)
+	masterKey := DeriveMasterKey(config.MasterKey)
+
+	provider, err := newKeyProvider(config, masterKey)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Vault{
@@ -83,30 +133,99 @@ func NewVault(db *gorm.DB, config Config) (*Vault, error) {
 		keyDeriver: func(password, salt []byte) []byte {
 			return argon2.IDKey(password, salt, 3, 64*1024, 4, 32)
 		},
+		provider: provider,
 	}, nil
 }
 
+// DeriveMasterKey turns a configured master key into the 32-byte vault key. A
+// 64-character hex string is used as-is; anything else is treated as a password.
+func DeriveMasterKey(key string) []byte {
+	masterKey, err := hex.DecodeString(key)
+	if err != nil || len(masterKey) != 32 {
+		// If not valid hex, derive from password using Argon2
+		salt := []byte("web3airdropos-vault-salt") // Fixed salt for key derivation
+		masterKey = argon2.IDKey([]byte(key), salt, 3, 64*1024, 4, 32)
+	}
+	return masterKey
+}
+
+// KeyVersion identifies a master key without revealing it. Every sealed value
+// records the version of the key that sealed it.
+func KeyVersion(masterKey []byte) string {
+	sum := sha256.Sum256(append([]byte("web3airdropos-vault-key-version:"), masterKey...))
+	return hex.EncodeToString(sum[:8])
+}
+
+// KeyProviderName returns the name of the active key provider
+func (v *Vault) KeyProviderName() string {
+	_, provider := v.keys()
+	return provider.Name()
+}
+
+// CurrentKeyVersion returns the version of the master key sealing new values
+func (v *Vault) CurrentKeyVersion() string {
+	masterKey, _ := v.keys()
+	return KeyVersion(masterKey)
+}
+
+// keys returns the active master key and provider
+func (v *Vault) keys() ([]byte, KeyProvider) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.masterKey, v.provider
+}
+
+// withKey returns a vault sealing and opening under masterKey. The key provider
+// is kept unless it is the local one, which wraps data keys with the master key.
+func (v *Vault) withKey(masterKey []byte) (*Vault, error) {
+	_, provider := v.keys()
+	if _, ok := provider.(*LocalKeyProvider); ok {
+		local, err := NewLocalKeyProvider(masterKey)
+		if err != nil {
+			return nil, err
+		}
+		provider = local
+	}
+	return &Vault{
+		db:         v.db,
+		masterKey:  masterKey,
+		keyDeriver: v.keyDeriver,
+		provider:   provider,
+	}, nil
+}
+
+// retiredKey returns the vault for a previous key version, if still known
+func (v *Vault) retiredKey(version string) *Vault {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.retired[version]
+}
+
+func (v *Vault) retiredKeys() []*Vault {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	retired := make([]*Vault, 0, len(v.retired))
+	for _, key := range v.retired {
+		retired = append(retired, key)
+	}
+	return retired
+}
+
 // Store stores an encrypted secret
-func (v *Vault) Store(ctx context.Context, userID uuid.UUID, name string, value string, keyType SecretType, metadata map[string]interface{}) (*Secret, error) {
+func (v *Vault) Store(ctx context.Context, userID uuid.UUID, name string, value string, keyType SecretType, metadata map[string]interface{}) (_ *Secret, err error) {
+	defer func() {
+		v.logAccess(ctx, userID, audit.ActionSecretCreate, name, map[string]interface{}{"key_type": keyType}, err)
+	}()
+
 	// Check if secret already exists
 	var existing Secret
 	if err := v.db.Where("user_id = ? AND name = ?", userID, name).First(&existing).Error; err == nil {
 		return nil, ErrSecretExists
 	}
 
-	// Generate unique IV for this secret
-	iv := make([]byte, 12) // GCM standard nonce size
-	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
-		return nil, fmt.Errorf("failed to generate IV: %w", err)
-	}
-
-	// Derive a unique key for this secret using user ID as additional salt
-	secretKey := v.deriveSecretKey(userID, name)
-
-	// Encrypt the value
-	encrypted, err := v.encrypt([]byte(value), secretKey, iv)
+	sealed, err := v.sealValue(ctx, []byte(value))
 	if err != nil {
-		return nil, fmt.Errorf("encryption failed: %w", err)
+		return nil, err
 	}
 
 	secret := &Secret{
@@ -114,13 +233,20 @@ func (v *Vault) Store(ctx context.Context, userID uuid.UUID, name string, value
 		UserID:         userID,
 		Name:           name,
 		KeyType:        keyType,
-		EncryptedValue: base64.StdEncoding.EncodeToString(encrypted),
-		IV:             hex.EncodeToString(iv),
+		EncryptedValue: sealed.EncryptedValue,
+		IV:             sealed.IV,
+		WrappedKey:     sealed.WrappedKey,
+		KeyProvider:    sealed.KeyProvider,
+		KeyVersion:     sealed.KeyVersion,
+		Version:        1,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
 
 	if metadata != nil {
+		if _, ok := metadata[metadataTagsKey]; ok {
+			metadata[metadataTagsKey] = normalizeTags(metadataTags(metadata))
+		}
 		// Don't store sensitive data in metadata
 		metadataJSON, _ := json.Marshal(metadata)
 		secret.Metadata = string(metadataJSON)
@@ -134,7 +260,11 @@ func (v *Vault) Store(ctx context.Context, userID uuid.UUID, name string, value
 }
 
 // Retrieve retrieves and decrypts a secret
-func (v *Vault) Retrieve(ctx context.Context, userID uuid.UUID, name string) (string, error) {
+func (v *Vault) Retrieve(ctx context.Context, userID uuid.UUID, name string) (_ string, err error) {
+	defer func() {
+		v.logAccess(ctx, userID, audit.ActionSecretAccess, name, nil, err)
+	}()
+
 	var secret Secret
 	if err := v.db.Where("user_id = ? AND name = ?", userID, name).First(&secret).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -148,25 +278,9 @@ func (v *Vault) Retrieve(ctx context.Context, userID uuid.UUID, name string) (st
 		return "", errors.New("secret has expired")
 	}
 
-	// Decode IV
-	iv, err := hex.DecodeString(secret.IV)
-	if err != nil {
-		return "", ErrDecryptionFailed
-	}
-
-	// Decode encrypted value
-	encrypted, err := base64.StdEncoding.DecodeString(secret.EncryptedValue)
+	decrypted, err := v.openValue(ctx, userID, name, secret.sealed())
 	if err != nil {
-		return "", ErrDecryptionFailed
-	}
-
-	// Derive the secret key
-	secretKey := v.deriveSecretKey(userID, name)
-
-	// Decrypt
-	decrypted, err := v.decrypt(encrypted, secretKey, iv)
-	if err != nil {
-		return "", ErrDecryptionFailed
+		return "", err
 	}
 
 	// Update access tracking
@@ -198,36 +312,151 @@ func (v *Vault) RetrieveByID(ctx context.Context, userID uuid.UUID, secretID uui
 }
 
 // Update updates a secret's value
-func (v *Vault) Update(ctx context.Context, userID uuid.UUID, name string, newValue string) error {
+func (v *Vault) Update(ctx context.Context, userID uuid.UUID, name string, newValue string) (err error) {
+	defer func() {
+		v.logAccess(ctx, userID, audit.ActionSecretUpdate, name, nil, err)
+	}()
+
+	_, err = v.update(ctx, userID, name, newValue)
+	return err
+}
+
+// update replaces a secret's value, keeping the old one as history, without
+// auditing it. It returns the version that was replaced.
+func (v *Vault) update(ctx context.Context, userID uuid.UUID, name string, newValue string) (int, error) {
 	var secret Secret
 	if err := v.db.Where("user_id = ? AND name = ?", userID, name).First(&secret).Error; err != nil {
-		return ErrSecretNotFound
+		return 0, ErrSecretNotFound
 	}
+	replaced := secret.Version
 
-	// Generate new IV
-	iv := make([]byte, 12)
-	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
-		return fmt.Errorf("failed to generate IV: %w", err)
+	sealed, err := v.sealValue(ctx, []byte(newValue))
+	if err != nil {
+		return 0, err
+	}
+
+	return replaced, v.db.Transaction(func(tx *gorm.DB) error {
+		// Keep the current ciphertext as history before overwriting it
+		previous := &SecretVersion{
+			ID:             uuid.New(),
+			SecretID:       secret.ID,
+			Version:        secret.Version,
+			EncryptedValue: secret.EncryptedValue,
+			IV:             secret.IV,
+			WrappedKey:     secret.WrappedKey,
+			KeyProvider:    secret.KeyProvider,
+			KeyVersion:     secret.KeyVersion,
+			CreatedAt:      time.Now(),
+		}
+		if err := tx.Create(previous).Error; err != nil {
+			return err
+		}
+
+		secret.EncryptedValue = sealed.EncryptedValue
+		secret.IV = sealed.IV
+		secret.WrappedKey = sealed.WrappedKey
+		secret.KeyProvider = sealed.KeyProvider
+		secret.KeyVersion = sealed.KeyVersion
+		secret.Version++
+		secret.UpdatedAt = time.Now()
+
+		return tx.Save(&secret).Error
+	})
+}
+
+// GetVersion retrieves and decrypts a specific version of a secret
+func (v *Vault) GetVersion(ctx context.Context, userID uuid.UUID, name string, version int) (_ string, err error) {
+	defer func() {
+		v.logAccess(ctx, userID, audit.ActionSecretAccess, name, map[string]interface{}{"version": version}, err)
+	}()
+
+	return v.getVersion(ctx, userID, name, version)
+}
+
+// getVersion decrypts a specific version of a secret without auditing it
+func (v *Vault) getVersion(ctx context.Context, userID uuid.UUID, name string, version int) (string, error) {
+	var secret Secret
+	if err := v.db.Where("user_id = ? AND name = ?", userID, name).First(&secret).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrSecretNotFound
+		}
+		return "", err
+	}
+
+	if version == secret.Version {
+		decrypted, err := v.openValue(ctx, userID, name, secret.sealed())
+		if err != nil {
+			return "", err
+		}
+		return string(decrypted), nil
 	}
 
-	// Derive secret key
-	secretKey := v.deriveSecretKey(userID, name)
+	var previous SecretVersion
+	if err := v.db.Where("secret_id = ? AND version = ?", secret.ID, version).First(&previous).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrVersionNotFound
+		}
+		return "", err
+	}
 
-	// Encrypt new value
-	encrypted, err := v.encrypt([]byte(newValue), secretKey, iv)
+	decrypted, err := v.openValue(ctx, userID, name, previous.sealed())
 	if err != nil {
-		return fmt.Errorf("encryption failed: %w", err)
+		return "", err
 	}
+	return string(decrypted), nil
+}
 
-	secret.EncryptedValue = base64.StdEncoding.EncodeToString(encrypted)
-	secret.IV = hex.EncodeToString(iv)
-	secret.UpdatedAt = time.Now()
+// ListVersions lists the stored history of a secret (without values), newest first
+func (v *Vault) ListVersions(ctx context.Context, userID uuid.UUID, name string) ([]SecretVersion, error) {
+	var secret Secret
+	if err := v.db.Where("user_id = ? AND name = ?", userID, name).First(&secret).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSecretNotFound
+		}
+		return nil, err
+	}
+
+	var versions []SecretVersion
+	if err := v.db.Where("secret_id = ?", secret.ID).Order("version DESC").Find(&versions).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range versions {
+		versions[i].EncryptedValue = ""
+		versions[i].IV = ""
+	}
+
+	return versions, nil
+}
+
+// Rollback restores a previous version as the current value.
+// The rollback is itself a new version, so the value being replaced is kept.
+// It is audited as one entry naming the version replaced and the one written.
+func (v *Vault) Rollback(ctx context.Context, userID uuid.UUID, name string, version int) (err error) {
+	details := map[string]interface{}{"restored_version": version}
+	defer func() {
+		v.logAccess(ctx, userID, audit.ActionSecretRollback, name, details, err)
+	}()
 
-	return v.db.Save(&secret).Error
+	value, err := v.getVersion(ctx, userID, name, version)
+	if err != nil {
+		return err
+	}
+	replaced, err := v.update(ctx, userID, name, value)
+	if err != nil {
+		return err
+	}
+	details["from_version"] = replaced
+	details["to_version"] = replaced + 1
+	return nil
 }
 
 // Delete soft-deletes a secret
-func (v *Vault) Delete(ctx context.Context, userID uuid.UUID, name string) error {
+func (v *Vault) Delete(ctx context.Context, userID uuid.UUID, name string) (err error) {
+	defer func() {
+		v.logAccess(ctx, userID, audit.ActionSecretDelete, name, nil, err)
+	}()
+
 	result := v.db.Where("user_id = ? AND name = ?", userID, name).Delete(&Secret{})
 	if result.RowsAffected == 0 {
 		return ErrSecretNotFound
@@ -285,63 +514,209 @@ func (v *Vault) SetExpiration(ctx context.Context, userID uuid.UUID, name string
 	return result.Error
 }
 
-// RotateKey re-encrypts all secrets with a new key (for key rotation)
+// RotateKey re-encrypts all secrets, including their history, under a new master key.
+// Every value is re-sealed with a fresh data key. With a KMS provider only the wrapping
+// provider is kept as-is, since rotating the KMS key happens in the KMS itself.
+// To rotate every user at once, use RotateAll.
 func (v *Vault) RotateKey(ctx context.Context, userID uuid.UUID, newMasterKey []byte) error {
-	// Get all secrets for user
-	var secrets []Secret
-	if err := v.db.Where("user_id = ?", userID).Find(&secrets).Error; err != nil {
+	newVault, err := v.withKey(newMasterKey)
+	if err != nil {
+		return err
+	}
+
+	if err := v.db.Transaction(func(tx *gorm.DB) error {
+		_, err := rotateUser(ctx, tx, userID, v, newVault)
+		return err
+	}); err != nil {
 		return err
 	}
 
-	// Create new vault instance with new key for encryption
-	newVault := &Vault{
-		db:        v.db,
-		masterKey: newMasterKey,
+	// Update vault master key
+	v.mu.Lock()
+	v.masterKey = newMasterKey
+	v.provider = newVault.provider
+	v.mu.Unlock()
+	return nil
+}
+
+// rotateUser opens a user's secrets and their history with from and re-seals
+// them with to, skipping values already sealed under to's key. Soft-deleted
+// secrets are included, as they still hold ciphertext. It returns the number of
+// values re-sealed.
+func rotateUser(ctx context.Context, tx *gorm.DB, userID uuid.UUID, from, to *Vault) (int, error) {
+	toVersion := to.CurrentKeyVersion()
+
+	var secrets []Secret
+	if err := tx.Unscoped().Where("user_id = ?", userID).Find(&secrets).Error; err != nil {
+		return 0, err
 	}
 
-	// Re-encrypt each secret
+	rotated := 0
 	for _, secret := range secrets {
-		// Decrypt with old key
-		iv, _ := hex.DecodeString(secret.IV)
-		encrypted, _ := base64.StdEncoding.DecodeString(secret.EncryptedValue)
-		secretKey := v.deriveSecretKey(userID, secret.Name)
-		decrypted, err := v.decrypt(encrypted, secretKey, iv)
-		if err != nil {
-			return fmt.Errorf("failed to decrypt secret %s: %w", secret.Name, err)
+		if secret.KeyVersion != toVersion {
+			plaintext, err := from.openValue(ctx, userID, secret.Name, secret.sealed())
+			if err != nil {
+				return rotated, fmt.Errorf("failed to decrypt secret %s: %w", secret.Name, err)
+			}
+
+			sealed, err := to.sealValue(ctx, plaintext)
+			if err != nil {
+				return rotated, fmt.Errorf("failed to encrypt secret %s: %w", secret.Name, err)
+			}
+
+			if err := tx.Unscoped().Model(&secret).Updates(sealed.columns()).Error; err != nil {
+				return rotated, err
+			}
+			rotated++
 		}
 
-		// Generate new IV
-		newIV := make([]byte, 12)
-		if _, err := io.ReadFull(rand.Reader, newIV); err != nil {
-			return err
+		// Re-encrypt history so old versions stay readable under the new key
+		var versions []SecretVersion
+		if err := tx.Where("secret_id = ? AND key_version IS DISTINCT FROM ?", secret.ID, toVersion).Find(&versions).Error; err != nil {
+			return rotated, err
 		}
-
-		// Encrypt with new key
-		newSecretKey := newVault.deriveSecretKey(userID, secret.Name)
-		newEncrypted, err := newVault.encrypt(decrypted, newSecretKey, newIV)
-		if err != nil {
-			return fmt.Errorf("failed to encrypt secret %s: %w", secret.Name, err)
+		for _, version := range versions {
+			plaintext, err := from.openValue(ctx, userID, secret.Name, version.sealed())
+			if err != nil {
+				return rotated, fmt.Errorf("failed to decrypt secret %s version %d: %w", secret.Name, version.Version, err)
+			}
+
+			sealed, err := to.sealValue(ctx, plaintext)
+			if err != nil {
+				return rotated, fmt.Errorf("failed to encrypt secret %s version %d: %w", secret.Name, version.Version, err)
+			}
+
+			if err := tx.Model(&version).Updates(sealed.columns()).Error; err != nil {
+				return rotated, err
+			}
+			rotated++
 		}
+	}
 
-		// Update
-		secret.EncryptedValue = base64.StdEncoding.EncodeToString(newEncrypted)
-		secret.IV = hex.EncodeToString(newIV)
-		secret.UpdatedAt = time.Now()
+	return rotated, nil
+}
 
-		if err := v.db.Save(&secret).Error; err != nil {
-			return err
+// deriveSecretKey derives a unique key for each secret
+func (v *Vault) deriveSecretKey(masterKey []byte, userID uuid.UUID, name string) []byte {
+	salt := append(userID[:], []byte(name)...)
+	return v.keyDeriver(masterKey, salt)
+}
+
+// sealedValue is the stored (encrypted) form of a secret value
+type sealedValue struct {
+	EncryptedValue string // base64 ciphertext
+	IV             string // hex nonce
+	WrappedKey     string // data key wrapped by the key provider
+	KeyProvider    string
+	KeyVersion     string // master key that sealed it
+}
+
+func (s *Secret) sealed() sealedValue {
+	return sealedValue{EncryptedValue: s.EncryptedValue, IV: s.IV, WrappedKey: s.WrappedKey, KeyProvider: s.KeyProvider, KeyVersion: s.KeyVersion}
+}
+
+func (s *SecretVersion) sealed() sealedValue {
+	return sealedValue{EncryptedValue: s.EncryptedValue, IV: s.IV, WrappedKey: s.WrappedKey, KeyProvider: s.KeyProvider, KeyVersion: s.KeyVersion}
+}
+
+// columns returns the sealed value as column updates
+func (s sealedValue) columns() map[string]interface{} {
+	return map[string]interface{}{
+		"encrypted_value": s.EncryptedValue,
+		"iv":              s.IV,
+		"wrapped_key":     s.WrappedKey,
+		"key_provider":    s.KeyProvider,
+		"key_version":     s.KeyVersion,
+	}
+}
+
+// sealValue encrypts a value with a fresh data key (envelope encryption)
+func (v *Vault) sealValue(ctx context.Context, plaintext []byte) (sealedValue, error) {
+	masterKey, provider := v.keys()
+
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return sealedValue{}, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	iv := make([]byte, 12) // GCM standard nonce size
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return sealedValue{}, fmt.Errorf("failed to generate IV: %w", err)
+	}
+
+	encrypted, err := v.encrypt(plaintext, dataKey, iv)
+	if err != nil {
+		return sealedValue{}, fmt.Errorf("encryption failed: %w", err)
+	}
+
+	wrapped, err := provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return sealedValue{}, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	return sealedValue{
+		EncryptedValue: base64.StdEncoding.EncodeToString(encrypted),
+		IV:             hex.EncodeToString(iv),
+		WrappedKey:     wrapped,
+		KeyProvider:    provider.Name(),
+		KeyVersion:     KeyVersion(masterKey),
+	}, nil
+}
+
+// openValue decrypts a stored value. Secrets written before envelope encryption
+// have no wrapped key and use the per-secret key derived from the master key.
+// Values sealed under a retired key are opened with that key while a rotation runs.
+func (v *Vault) openValue(ctx context.Context, userID uuid.UUID, name string, sealed sealedValue) ([]byte, error) {
+	masterKey, provider := v.keys()
+	if sealed.KeyVersion != "" && sealed.KeyVersion != KeyVersion(masterKey) {
+		if retired := v.retiredKey(sealed.KeyVersion); retired != nil {
+			return retired.openValue(ctx, userID, name, sealed)
 		}
 	}
 
-	// Update vault master key
-	v.masterKey = newMasterKey
-	return nil
+	decrypted, err := v.openWith(ctx, masterKey, provider, userID, name, sealed)
+	if err != nil && sealed.KeyVersion == "" {
+		// Sealed before key versions were recorded, so it may still be under a retired key
+		for _, retired := range v.retiredKeys() {
+			if decrypted, rerr := retired.openValue(ctx, userID, name, sealed); rerr == nil {
+				return decrypted, nil
+			}
+		}
+	}
+	return decrypted, err
 }
 
-// deriveSecretKey derives a unique key for each secret
-func (v *Vault) deriveSecretKey(userID uuid.UUID, name string) []byte {
-	salt := append(userID[:], []byte(name)...)
-	return v.keyDeriver(v.masterKey, salt)
+// openWith decrypts a stored value under the given master key and provider
+func (v *Vault) openWith(ctx context.Context, masterKey []byte, provider KeyProvider, userID uuid.UUID, name string, sealed sealedValue) ([]byte, error) {
+	iv, err := hex.DecodeString(sealed.IV)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	encrypted, err := base64.StdEncoding.DecodeString(sealed.EncryptedValue)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	var key []byte
+	if sealed.WrappedKey == "" {
+		key = v.deriveSecretKey(masterKey, userID, name)
+	} else {
+		if sealed.KeyProvider != provider.Name() {
+			return nil, ErrKeyProviderMismatch
+		}
+		key, err = provider.UnwrapKey(ctx, sealed.WrappedKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	decrypted, err := v.decrypt(encrypted, key, iv)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	return decrypted, nil
 }
 
 // encrypt encrypts data using AES-256-GCM
@@ -0,0 +1,248 @@
+// Package webhooks delivers event notifications to user-configured webhook URLs.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/web3airdropos/backend/internal/models"
+	"github.com/web3airdropos/backend/internal/netguard"
+)
+
+const (
+	maxAttempts = 5
+	baseBackoff = 2 * time.Second
+	maxBackoff  = 5 * time.Minute
+
+	// Delivery statuses
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+
+	// SignatureHeader carries "sha256=" and the hex HMAC of the body
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Payload is the JSON body POSTed to a webhook
+type Payload struct {
+	ID        uuid.UUID   `json:"id"` // Delivery ID, stable across retries
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// Dispatcher sends events to the webhooks subscribed to them. Deliveries run in the
+// background and are retried with exponential backoff; every attempt is recorded
+// on the delivery row.
+type Dispatcher struct {
+	db   *gorm.DB
+	http *http.Client
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewDispatcher(db *gorm.DB) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		db:     db,
+		http:   netguard.Client(10*time.Second, false),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Dispatch queues an event for every active webhook of the user subscribed to it.
+// It returns without waiting for delivery.
+func (d *Dispatcher) Dispatch(userID uuid.UUID, event string, data interface{}) {
+	if d == nil || d.db == nil {
+		return
+	}
+
+	subscribed, _ := json.Marshal([]string{event})
+	var hooks []models.Webhook
+	if err := d.db.Where("user_id = ? AND is_active = ? AND events @> ?", userID, true, string(subscribed)).
+		Find(&hooks).Error; err != nil {
+		log.Printf("⚠️ Failed to load webhooks for %s: %v", event, err)
+		return
+	}
+
+	for i := range hooks {
+		delivery, body, err := d.record(&hooks[i], event, data)
+		if err != nil {
+			log.Printf("⚠️ Failed to record webhook delivery: %v", err)
+			continue
+		}
+
+		d.wg.Add(1)
+		go func(hook models.Webhook) {
+			defer d.wg.Done()
+			d.deliver(&hook, delivery, body)
+		}(hooks[i])
+	}
+}
+
+// Send delivers an event to one webhook with a single attempt and returns the
+// recorded delivery, for checking a webhook works
+func (d *Dispatcher) Send(hook *models.Webhook, event string, data interface{}) (*models.WebhookDelivery, error) {
+	delivery, body, err := d.record(hook, event, data)
+	if err != nil {
+		return nil, err
+	}
+	d.attempt(hook, delivery, body, true)
+	return delivery, nil
+}
+
+// Close stops pending retries and waits for in-flight deliveries
+func (d *Dispatcher) Close() {
+	if d == nil {
+		return
+	}
+	d.cancel()
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) record(hook *models.Webhook, event string, data interface{}) (*models.WebhookDelivery, []byte, error) {
+	delivery := &models.WebhookDelivery{
+		ID:        uuid.New(),
+		WebhookID: hook.ID,
+		Event:     event,
+		Status:    StatusPending,
+	}
+	body, err := json.Marshal(Payload{ID: delivery.ID, Event: event, CreatedAt: time.Now().UTC(), Data: data})
+	if err != nil {
+		return nil, nil, err
+	}
+	delivery.Payload = string(body)
+
+	if err := d.db.Create(delivery).Error; err != nil {
+		return nil, nil, err
+	}
+	return delivery, body, nil
+}
+
+// deliver attempts a delivery until it succeeds, fails permanently or runs out of attempts
+func (d *Dispatcher) deliver(hook *models.Webhook, delivery *models.WebhookDelivery, body []byte) {
+	for {
+		final := delivery.Attempts+1 >= maxAttempts
+		if !d.attempt(hook, delivery, body, final) {
+			return
+		}
+
+		select {
+		case <-d.ctx.Done():
+			// Left pending with its next attempt time on record
+			return
+		case <-time.After(backoffFor(delivery.Attempts)):
+		}
+	}
+}
+
+// attempt POSTs the payload once and records the outcome. It reports whether the
+// delivery should be retried; final marks a failure as permanent.
+func (d *Dispatcher) attempt(hook *models.Webhook, delivery *models.WebhookDelivery, body []byte, final bool) bool {
+	delivery.Attempts++
+	status, err := d.post(hook, delivery, body)
+
+	updates := map[string]interface{}{
+		"attempts":        delivery.Attempts,
+		"response_status": status,
+		"last_error":      "",
+		"next_attempt_at": nil,
+	}
+	retry := false
+	switch {
+	case err == nil:
+		now := time.Now()
+		delivery.Status = StatusDelivered
+		delivery.DeliveredAt = &now
+		updates["delivered_at"] = now
+	case !final && retryable(status) && !netguard.IsBlocked(err):
+		retry = true
+		next := time.Now().Add(backoffFor(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		updates["next_attempt_at"] = next
+		updates["last_error"] = err.Error()
+	default:
+		delivery.Status = StatusFailed
+		updates["last_error"] = err.Error()
+	}
+	updates["status"] = delivery.Status
+	delivery.ResponseStatus = status
+	if err != nil {
+		delivery.LastError = err.Error()
+	}
+
+	d.db.Model(&models.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(updates)
+	return retry
+}
+
+// post sends one attempt. Webhooks may not reach the server's own network, and
+// redirects are not followed; a refused destination reports only that it was
+// refused, so deliveries cannot be used to probe internal addresses.
+func (d *Dispatcher) post(hook *models.Webhook, delivery *models.WebhookDelivery, body []byte) (int, error) {
+	if err := netguard.CheckURL(hook.URL); err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(d.ctx, "POST", hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Web3AirdropOS-Webhooks/1.0")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	req.Header.Set(SignatureHeader, "sha256="+Sign(hook.Secret, body))
+
+	resp, err := d.http.Do(req)
+	if netguard.IsBlocked(err) {
+		return 0, netguard.ErrBlockedDestination
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the hex HMAC-SHA256 of body keyed with secret, as sent in SignatureHeader
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryable reports whether a failed attempt may succeed later. Network errors (no
+// status), rate limiting and server errors are; other client errors are not.
+func retryable(status int) bool {
+	return status == 0 || status == http.StatusTooManyRequests || status >= 500
+}
+
+func backoffFor(attempt int) time.Duration {
+	backoff := baseBackoff << (attempt - 1)
+	if backoff > maxBackoff || backoff <= 0 {
+		return maxBackoff
+	}
+	return backoff
+}
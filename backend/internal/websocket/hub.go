@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -11,14 +13,46 @@ import (
 	"github.com/gorilla/websocket"
 )
 
+const (
+	// Time allowed to write a message to the peer
+	writeWait = 10 * time.Second
+
+	// Time allowed to read the next pong from the peer
+	pongWait = 60 * time.Second
+
+	// Pings are sent at this interval, which must be less than pongWait
+	pingPeriod = (pongWait * 9) / 10
+
+	// Maximum message size allowed from the peer
+	maxMessageSize = 512 * 1024
+
+	// Messages buffered per connection before it is considered too slow and dropped
+	sendBufferSize = 256
+)
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
 	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins in development
+		return true // Allow all origins until SetAllowedOrigins is called
 	},
 }
 
+// SetAllowedOrigins limits upgrades from browsers to same-host pages and the
+// origins allowed accepts, normally the API's CORS origins. Requests without
+// an Origin header come from non-browser clients and are let through. Call it
+// before serving.
+func SetAllowedOrigins(allowed func(origin string) bool) {
+	upgrader.CheckOrigin = func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" || allowed(origin) {
+			return true
+		}
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, r.Host)
+	}
+}
+
 // Message represents a WebSocket message
 type Message struct {
 	Type    string      `json:"type"`
@@ -33,6 +67,14 @@ type Client struct {
 	userID string
 	rooms  map[string]bool
 	mu     sync.RWMutex
+
+	// sendMu guards send against writes after the hub has closed it
+	sendMu sync.Mutex
+	closed bool
+
+	// Topic subscriptions for user-scoped messages, see topics.go
+	topics        map[string]bool
+	defaultTopics bool
 }
 
 // Hub maintains the set of active clients and broadcasts messages
@@ -50,6 +92,7 @@ type BroadcastMessage struct {
 	Target  string // "all", "user:<id>", "room:<name>"
 	Type    string
 	Payload interface{}
+	Topics  []string // For user targets, only clients subscribed to one of these receive it
 }
 
 // NewHub creates a new Hub
@@ -78,30 +121,9 @@ func (h *Hub) Run() {
 			log.Printf("🔌 Client connected: %s", client.userID)
 
 		case client := <-h.unregister:
-			h.mu.Lock()
-			if _, ok := h.clients[client]; ok {
-				delete(h.clients, client)
-				close(client.send)
-
-				// Remove from userMap
-				if clients, ok := h.userMap[client.userID]; ok {
-					for i, c := range clients {
-						if c == client {
-							h.userMap[client.userID] = append(clients[:i], clients[i+1:]...)
-							break
-						}
-					}
-				}
-
-				// Remove from rooms
-				for room := range client.rooms {
-					if roomClients, ok := h.rooms[room]; ok {
-						delete(roomClients, client)
-					}
-				}
+			if h.removeClient(client) {
+				log.Printf("🔌 Client disconnected: %s", client.userID)
 			}
-			h.mu.Unlock()
-			log.Printf("🔌 Client disconnected: %s", client.userID)
 
 		case msg := <-h.broadcast:
 			h.handleBroadcast(msg)
@@ -109,6 +131,73 @@ func (h *Hub) Run() {
 	}
 }
 
+// removeClient drops a client from every index and closes its send channel,
+// which makes writePump send a close frame and shut the connection down.
+// It reports false if the client was already removed.
+func (h *Hub) removeClient(client *Client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.clients[client]; !ok {
+		return false
+	}
+	delete(h.clients, client)
+	client.closeSend()
+
+	// Remove from userMap
+	if clients, ok := h.userMap[client.userID]; ok {
+		for i, c := range clients {
+			if c == client {
+				clients = append(clients[:i], clients[i+1:]...)
+				break
+			}
+		}
+		if len(clients) == 0 {
+			delete(h.userMap, client.userID)
+		} else {
+			h.userMap[client.userID] = clients
+		}
+	}
+
+	// Remove from rooms
+	for room := range client.rooms {
+		if roomClients, ok := h.rooms[room]; ok {
+			delete(roomClients, client)
+			if len(roomClients) == 0 {
+				delete(h.rooms, room)
+			}
+		}
+	}
+	return true
+}
+
+// queue hands data to writePump without blocking. It returns false when the
+// send buffer is full or the client has already been closed.
+func (c *Client) queue(data []byte) bool {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	if c.closed {
+		return false
+	}
+	select {
+	case c.send <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Client) closeSend() {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.send)
+	}
+}
+
 func (h *Hub) handleBroadcast(msg *BroadcastMessage) {
 	data, err := json.Marshal(Message{
 		Type:    msg.Type,
@@ -119,40 +208,37 @@ func (h *Hub) handleBroadcast(msg *BroadcastMessage) {
 		return
 	}
 
+	// Collect recipients under the read lock, then send without holding it
+	// so slow clients can be removed
+	var recipients []*Client
 	h.mu.RLock()
-	defer h.mu.RUnlock()
-
 	switch {
 	case msg.Target == "all":
 		for client := range h.clients {
-			select {
-			case client.send <- data:
-			default:
-				close(client.send)
-				delete(h.clients, client)
-			}
+			recipients = append(recipients, client)
 		}
 	case len(msg.Target) > 5 && msg.Target[:5] == "user:":
 		userID := msg.Target[5:]
-		if clients, ok := h.userMap[userID]; ok {
-			for _, client := range clients {
-				select {
-				case client.send <- data:
-				default:
-					close(client.send)
-				}
+		for _, client := range h.userMap[userID] {
+			if client.wants(msg.Topics) {
+				recipients = append(recipients, client)
 			}
 		}
 	case len(msg.Target) > 5 && msg.Target[:5] == "room:":
 		roomName := msg.Target[5:]
-		if roomClients, ok := h.rooms[roomName]; ok {
-			for client := range roomClients {
-				select {
-				case client.send <- data:
-				default:
-					close(client.send)
-				}
-			}
+		for client := range h.rooms[roomName] {
+			recipients = append(recipients, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, client := range recipients {
+		if client.queue(data) {
+			continue
+		}
+		// The client can't keep up; drop it rather than stall the hub
+		if h.removeClient(client) {
+			log.Printf("🔌 Dropped slow client: %s", client.userID)
 		}
 	}
 }
@@ -166,12 +252,19 @@ func (h *Hub) BroadcastToAll(msgType string, payload interface{}) {
 	}
 }
 
-// BroadcastToUser sends a message to a specific user
+// BroadcastToUser sends a message to a specific user, tagged with the
+// message category (e.g. "job" for "job:started") as its topic
 func (h *Hub) BroadcastToUser(userID, msgType string, payload interface{}) {
+	h.BroadcastToUserTopics(userID, messageTopics(msgType), msgType, payload)
+}
+
+// BroadcastToUserTopics sends a message to the user's sockets subscribed to any of topics
+func (h *Hub) BroadcastToUserTopics(userID string, topics []string, msgType string, payload interface{}) {
 	h.broadcast <- &BroadcastMessage{
 		Target:  "user:" + userID,
 		Type:    msgType,
 		Payload: payload,
+		Topics:  topics,
 	}
 }
 
@@ -219,42 +312,73 @@ func (h *Hub) GetOnlineUsers() []string {
 	return users
 }
 
-// ServeWs handles websocket requests from the peer
-func ServeWs(hub *Hub, w http.ResponseWriter, r *http.Request, jwtSecret string) {
-	// Extract token from query
-	token := r.URL.Query().Get("token")
-	userID := ""
-
-	if token != "" {
-		claims := &jwt.RegisteredClaims{}
-		parsedToken, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		})
-
-		if err == nil && parsedToken.Valid {
-			userID = claims.Subject
+// TokenFromRequest extracts an access token from a WebSocket upgrade request.
+// Browsers can't set an Authorization header on WebSocket connections, so the
+// token is read from the Sec-WebSocket-Protocol header ("bearer, <token>" or
+// just "<token>") and falls back to the ?token= query param. The returned
+// subprotocol must be echoed back in the upgrade response.
+func TokenFromRequest(r *http.Request) (token string, subprotocol string) {
+	if header := r.Header.Get("Sec-WebSocket-Protocol"); header != "" {
+		parts := strings.Split(header, ",")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		if len(parts) >= 2 && strings.EqualFold(parts[0], "bearer") && parts[1] != "" {
+			return parts[1], parts[0]
+		}
+		if len(parts) == 1 && parts[0] != "" {
+			return parts[0], parts[0]
 		}
 	}
 
-	conn, err := upgrader.Upgrade(w, r, nil)
-	if err != nil {
-		log.Printf("WebSocket upgrade error: %v", err)
+	return r.URL.Query().Get("token"), ""
+}
+
+// Upgrade upgrades an authenticated request, echoing the subprotocol that carried
+// the token. Handlers that relay their own traffic use it instead of the hub.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*websocket.Conn, error) {
+	var responseHeader http.Header
+	if _, subprotocol := TokenFromRequest(r); subprotocol != "" {
+		responseHeader = http.Header{"Sec-WebSocket-Protocol": []string{subprotocol}}
+	}
+	return upgrader.Upgrade(w, r, responseHeader)
+}
+
+// rejectUnauthorized writes a 401 response before the upgrade happens
+func rejectUnauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnauthorized)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// ServeWs handles websocket requests from the peer
+func ServeWs(hub *Hub, w http.ResponseWriter, r *http.Request, jwtSecret string) {
+	token, _ := TokenFromRequest(r)
+	if token == "" {
+		rejectUnauthorized(w, "Access token required")
 		return
 	}
 
-	client := &Client{
-		hub:    hub,
-		conn:   conn,
-		send:   make(chan []byte, 256),
-		userID: userID,
-		rooms:  make(map[string]bool),
+	claims := jwt.MapClaims{}
+	parsedToken, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(jwtSecret), nil
+	})
+	if err != nil || !parsedToken.Valid {
+		rejectUnauthorized(w, "Invalid or expired token")
+		return
 	}
 
-	hub.register <- client
+	// Basic auth tokens carry user_id, production tokens also set the subject
+	userID, _ := claims["user_id"].(string)
+	if userID == "" {
+		userID, _ = claims.GetSubject()
+	}
+	if userID == "" {
+		rejectUnauthorized(w, "Invalid or expired token")
+		return
+	}
 
-	// Start goroutines for reading and writing
-	go client.writePump()
-	go client.readPump()
+	hub.HandleWebSocket(w, r, userID)
 }
 
 // readPump pumps messages from the websocket connection to the hub
@@ -264,10 +388,11 @@ func (c *Client) readPump() {
 		c.conn.Close()
 	}()
 
-	c.conn.SetReadLimit(512 * 1024) // 512KB max message size
-	c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+	// A peer that misses the pong window fails the read below and is unregistered
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
 	c.conn.SetPongHandler(func(string) error {
-		c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
 		return nil
 	})
 
@@ -287,7 +412,7 @@ func (c *Client) readPump() {
 
 // writePump pumps messages from the hub to the websocket connection
 func (c *Client) writePump() {
-	ticker := time.NewTicker(30 * time.Second) // Ping interval
+	ticker := time.NewTicker(pingPeriod)
 	defer func() {
 		ticker.Stop()
 		c.conn.Close()
@@ -296,7 +421,7 @@ func (c *Client) writePump() {
 	for {
 		select {
 		case message, ok := <-c.send:
-			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if !ok {
 				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
 				return
@@ -320,7 +445,7 @@ func (c *Client) writePump() {
 			}
 
 		case <-ticker.C:
-			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
 			}
@@ -330,14 +455,29 @@ func (c *Client) writePump() {
 
 // handleMessage processes incoming WebSocket messages
 func (c *Client) handleMessage(data []byte) {
-	var msg Message
+	var msg clientMessage
 	if err := json.Unmarshal(data, &msg); err != nil {
 		return
 	}
 
+	// Topic subscriptions: {"subscribe": "campaign:<id>"} / {"unsubscribe": [...]}
+	if len(msg.Subscribe) > 0 || len(msg.Unsubscribe) > 0 {
+		var topics []string
+		if len(msg.Subscribe) > 0 {
+			topics = c.subscribe(parseTopics(msg.Subscribe))
+		}
+		if len(msg.Unsubscribe) > 0 {
+			topics = c.unsubscribe(parseTopics(msg.Unsubscribe))
+		}
+		if reply, err := json.Marshal(Message{Type: "subscriptions", Payload: topics}); err == nil {
+			c.queue(reply)
+		}
+		return
+	}
+
 	switch msg.Type {
 	case "ping":
-		c.send <- []byte(`{"type":"pong"}`)
+		c.queue([]byte(`{"type":"pong"}`))
 
 	case "join_room":
 		if room, ok := msg.Payload.(string); ok {
@@ -363,25 +503,28 @@ func (c *Client) handleMessage(data []byte) {
 
 // TerminalMessage represents a terminal log message
 type TerminalMessage struct {
-	Timestamp time.Time   `json:"timestamp"`
-	Level     string      `json:"level"`  // info, warn, error, success, debug
-	Source    string      `json:"source"` // wallet, account, campaign, task, browser, system
-	Message   string      `json:"message"`
-	Details   interface{} `json:"details,omitempty"`
-	WalletID  string      `json:"wallet_id,omitempty"`
-	AccountID string      `json:"account_id,omitempty"`
-	TaskID    string      `json:"task_id,omitempty"`
+	Timestamp  time.Time   `json:"timestamp"`
+	Level      string      `json:"level"`  // info, warn, error, success, debug
+	Source     string      `json:"source"` // wallet, account, campaign, task, browser, system
+	Message    string      `json:"message"`
+	Details    interface{} `json:"details,omitempty"`
+	WalletID   string      `json:"wallet_id,omitempty"`
+	AccountID  string      `json:"account_id,omitempty"`
+	TaskID     string      `json:"task_id,omitempty"`
+	CampaignID string      `json:"campaign_id,omitempty"`
+	JobID      string      `json:"job_id,omitempty"`
 }
 
 // BroadcastTerminal sends a terminal message to a user
 func (h *Hub) BroadcastTerminal(userID string, msg TerminalMessage) {
 	msg.Timestamp = time.Now()
-	h.BroadcastToUser(userID, "terminal", msg)
+	h.BroadcastToUserTopics(userID, terminalTopics(msg), "terminal", msg)
 }
 
 // TaskStatusUpdate represents a task status change
 type TaskStatusUpdate struct {
 	TaskID         string `json:"task_id"`
+	CampaignID     string `json:"campaign_id,omitempty"`
 	Status         string `json:"status"`
 	Progress       int    `json:"progress"`
 	Message        string `json:"message"`
@@ -389,31 +532,34 @@ type TaskStatusUpdate struct {
 	BrowserURL     string `json:"browser_url,omitempty"`
 }
 
-// BroadcastTaskUpdate sends task status update to a user
+// BroadcastTaskUpdate sends task status update to a user, tagged with the task
+// and, when set, its campaign
 func (h *Hub) BroadcastTaskUpdate(userID string, update TaskStatusUpdate) {
-	h.BroadcastToUser(userID, "task:status", update)
+	topics := []string{"task", EntityTopic("task", update.TaskID)}
+	if update.CampaignID != "" {
+		topics = append(topics, EntityTopic("campaign", update.CampaignID))
+	}
+	h.BroadcastToUserTopics(userID, topics, "task:status", update)
 }
 
-// HandleWebSocket handles WebSocket upgrade and connection
-func (h *Hub) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+// HandleWebSocket upgrades an already-authenticated request and binds the
+// connection to userID so BroadcastToUser only reaches that user's sockets
+func (h *Hub) HandleWebSocket(w http.ResponseWriter, r *http.Request, userID string) {
+	conn, err := Upgrade(w, r)
 	if err != nil {
 		log.Printf("WebSocket upgrade failed: %v", err)
 		return
 	}
 
-	// Extract user ID from query param or JWT
-	userID := r.URL.Query().Get("user_id")
-	if userID == "" {
-		userID = "anonymous"
-	}
-
 	client := &Client{
 		hub:    h,
 		conn:   conn,
-		send:   make(chan []byte, 256),
+		send:   make(chan []byte, sendBufferSize),
 		userID: userID,
 		rooms:  make(map[string]bool),
+
+		topics:        map[string]bool{TopicAll: true},
+		defaultTopics: true,
 	}
 
 	h.register <- client
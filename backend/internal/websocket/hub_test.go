@@ -0,0 +1,106 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
+)
+
+const testSecret = "test-secret"
+
+func signedToken(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestServeWsRequiresToken(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeWs(hub, w, r, testSecret)
+	}))
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	valid := signedToken(t, testSecret, jwt.MapClaims{"user_id": "user-1", "exp": time.Now().Add(time.Hour).Unix()})
+	rejected := map[string]struct {
+		url    string
+		header http.Header
+	}{
+		"no token":       {url: wsURL},
+		"wrong secret":   {url: wsURL + "?token=" + signedToken(t, "other-secret", jwt.MapClaims{"user_id": "user-1"})},
+		"expired":        {url: wsURL + "?token=" + signedToken(t, testSecret, jwt.MapClaims{"user_id": "user-1", "exp": time.Now().Add(-time.Minute).Unix()})},
+		"no user":        {url: wsURL + "?token=" + signedToken(t, testSecret, jwt.MapClaims{"exp": time.Now().Add(time.Hour).Unix()})},
+		"garbage header": {url: wsURL, header: http.Header{"Sec-WebSocket-Protocol": []string{"bearer, not-a-jwt"}}},
+	}
+	for name, tc := range rejected {
+		conn, resp, err := websocket.DefaultDialer.Dial(tc.url, tc.header)
+		if err == nil {
+			conn.Close()
+			t.Errorf("%s: connection was accepted", name)
+			continue
+		}
+		if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s: got %v, want 401", name, resp)
+		}
+	}
+
+	// The token is accepted from the query string and from the subprotocol header
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL+"?token="+valid, nil)
+	if err != nil {
+		t.Fatalf("query token: %v", err)
+	}
+	conn.Close()
+
+	conn, resp, err := websocket.DefaultDialer.Dial(wsURL, http.Header{"Sec-WebSocket-Protocol": []string{"bearer, " + valid}})
+	if err != nil {
+		t.Fatalf("subprotocol token: %v", err)
+	}
+	defer conn.Close()
+	if got := resp.Header.Get("Sec-WebSocket-Protocol"); got != "bearer" {
+		t.Errorf("echoed subprotocol = %q, want bearer", got)
+	}
+
+	// The connection is bound to the token's user
+	deadline := time.Now().Add(time.Second)
+	for len(hub.GetOnlineUsers()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	hub.BroadcastToUser("user-1", "job:started", map[string]string{"job_id": "j1"})
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	var msg Message
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "job:started" {
+		t.Fatalf("read %+v, %v; want the user's job:started message", msg, err)
+	}
+}
+
+func TestSetAllowedOriginsChecksBrowserOrigins(t *testing.T) {
+	previous := upgrader.CheckOrigin
+	t.Cleanup(func() { upgrader.CheckOrigin = previous })
+	SetAllowedOrigins(func(origin string) bool { return origin == "https://app.example.com" })
+
+	for origin, want := range map[string]bool{
+		"":                          true, // not a browser
+		"https://app.example.com":   true,
+		"http://api.example.com":    true, // same host as the request
+		"https://evil.example":      false,
+		"https://app.example.com.x": false,
+	} {
+		r := httptest.NewRequest(http.MethodGet, "http://api.example.com/ws", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		if got := upgrader.CheckOrigin(r); got != want {
+			t.Errorf("origin %q: allowed = %v, want %v", origin, got, want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package websocket
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// TopicAll is the default subscription; clients holding it receive every message for their user
+const TopicAll = "all"
+
+const (
+	maxTopicsPerClient = 100
+	maxTopicLength     = 200
+)
+
+// clientMessage is an incoming frame. Besides the typed messages handled in
+// handleMessage, clients can send {"subscribe": "campaign:<id>"} or
+// {"unsubscribe": [...]} with a single topic or a list.
+type clientMessage struct {
+	Type        string          `json:"type"`
+	Payload     interface{}     `json:"payload"`
+	Subscribe   json.RawMessage `json:"subscribe,omitempty"`
+	Unsubscribe json.RawMessage `json:"unsubscribe,omitempty"`
+}
+
+// parseTopics accepts a JSON string or array of strings
+func parseTopics(raw json.RawMessage) []string {
+	var list []string
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		list = []string{single}
+	} else if err := json.Unmarshal(raw, &list); err != nil {
+		return nil
+	}
+
+	topics := make([]string, 0, len(list))
+	for _, topic := range list {
+		topic = strings.ToLower(strings.TrimSpace(topic))
+		if topic != "" && len(topic) <= maxTopicLength {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+// subscribe adds topics. The first explicit subscription replaces the default
+// "all" so the client only receives what it asked for.
+func (c *Client) subscribe(topics []string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.defaultTopics {
+		delete(c.topics, TopicAll)
+		c.defaultTopics = false
+	}
+	for _, topic := range topics {
+		if len(c.topics) >= maxTopicsPerClient {
+			break
+		}
+		c.topics[topic] = true
+	}
+	return c.topicList()
+}
+
+// unsubscribe removes topics, falling back to "all" once nothing is left
+func (c *Client) unsubscribe(topics []string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, topic := range topics {
+		delete(c.topics, topic)
+	}
+	if len(c.topics) == 0 {
+		c.topics[TopicAll] = true
+		c.defaultTopics = true
+	}
+	return c.topicList()
+}
+
+// wants reports whether a message tagged with topics should reach this client
+func (c *Client) wants(topics []string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.topics[TopicAll] {
+		return true
+	}
+	for _, topic := range topics {
+		if c.topics[topic] {
+			return true
+		}
+	}
+	return false
+}
+
+// topicList must be called with c.mu held
+func (c *Client) topicList() []string {
+	list := make([]string, 0, len(c.topics))
+	for topic := range c.topics {
+		list = append(list, topic)
+	}
+	return list
+}
+
+// messageTopics derives the topic of a message from its type ("job:started" -> "job")
+func messageTopics(msgType string) []string {
+	category := msgType
+	if i := strings.Index(msgType, ":"); i > 0 {
+		category = msgType[:i]
+	}
+	return []string{category}
+}
+
+// terminalTopics tags a terminal line with its source and the entities it concerns,
+// so clients can follow e.g. only "job" output or a single "campaign:<id>"
+func terminalTopics(msg TerminalMessage) []string {
+	topics := []string{"terminal"}
+	if msg.Source != "" {
+		topics = append(topics, msg.Source)
+	}
+	scoped := map[string]string{
+		"campaign": msg.CampaignID,
+		"task":     msg.TaskID,
+		"job":      msg.JobID,
+		"wallet":   msg.WalletID,
+		"account":  msg.AccountID,
+	}
+	for kind, id := range scoped {
+		if id != "" {
+			topics = append(topics, EntityTopic(kind, id))
+		}
+	}
+	return topics
+}
+
+// EntityTopic is the topic following a single entity, e.g. "campaign:<id>"
+func EntityTopic(kind, id string) string {
+	return kind + ":" + strings.ToLower(id)
+}
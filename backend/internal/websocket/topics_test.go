@@ -0,0 +1,114 @@
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// testClient registers a client for userID directly with the hub, without a connection
+func testClient(h *Hub, userID string) *Client {
+	client := &Client{
+		hub:           h,
+		send:          make(chan []byte, sendBufferSize),
+		userID:        userID,
+		rooms:         make(map[string]bool),
+		topics:        map[string]bool{TopicAll: true},
+		defaultTopics: true,
+	}
+	h.register <- client
+	return client
+}
+
+// receive waits for the next message queued to client, or fails after timeout
+func receive(t *testing.T, client *Client, timeout time.Duration) (Message, bool) {
+	t.Helper()
+	select {
+	case data := <-client.send:
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("invalid message %s: %v", data, err)
+		}
+		return msg, true
+	case <-time.After(timeout):
+		return Message{}, false
+	}
+}
+
+func TestCampaignSubscribersReceiveExecutionEvents(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	const campaignID = "3f0c6c1e-1d7a-4d5e-9a55-8a9f0f7f2c11"
+	client := testClient(hub, "user-1")
+	client.subscribe([]string{"campaign:" + campaignID})
+
+	// An execution event of another campaign is filtered out
+	hub.BroadcastTerminal("user-1", TerminalMessage{
+		Level:      "info",
+		Source:     "task",
+		Message:    "Executing task: other",
+		TaskID:     "task-2",
+		CampaignID: "00000000-0000-0000-0000-000000000000",
+	})
+	hub.BroadcastTerminal("user-1", TerminalMessage{
+		Level:      "success",
+		Source:     "task",
+		Message:    "✅ Task completed: follow",
+		TaskID:     "task-1",
+		CampaignID: campaignID,
+	})
+
+	msg, ok := receive(t, client, time.Second)
+	if !ok {
+		t.Fatal("campaign subscriber received no execution event")
+	}
+	payload, _ := msg.Payload.(map[string]interface{})
+	if msg.Type != "terminal" || payload["campaign_id"] != campaignID || payload["task_id"] != "task-1" {
+		t.Fatalf("received %+v, want the task-1 terminal event of campaign %s", msg, campaignID)
+	}
+	if msg, ok := receive(t, client, 100*time.Millisecond); ok {
+		t.Fatalf("received unexpected message %+v", msg)
+	}
+}
+
+func TestTerminalTopics(t *testing.T) {
+	topics := terminalTopics(TerminalMessage{Source: "task", TaskID: "T1", CampaignID: "C1"})
+	want := map[string]bool{"terminal": true, "task": true, "task:t1": true, "campaign:c1": true}
+	if len(topics) != len(want) {
+		t.Fatalf("topics = %v, want %v", topics, want)
+	}
+	for _, topic := range topics {
+		if !want[topic] {
+			t.Errorf("unexpected topic %q in %v", topic, topics)
+		}
+	}
+}
+
+func TestCampaignSubscribersReceiveStatusEvents(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	const campaignID = "3f0c6c1e-1d7a-4d5e-9a55-8a9f0f7f2c11"
+	client := testClient(hub, "user-1")
+	client.subscribe([]string{EntityTopic("campaign", campaignID)})
+
+	hub.BroadcastTaskUpdate("user-1", TaskStatusUpdate{TaskID: "task-2", CampaignID: "00000000-0000-0000-0000-000000000000", Status: "completed"})
+	hub.BroadcastTaskUpdate("user-1", TaskStatusUpdate{TaskID: "task-1", CampaignID: campaignID, Status: "completed"})
+	hub.BroadcastToUserTopics("user-1", []string{"campaign", EntityTopic("campaign", campaignID)}, "campaign:updated",
+		map[string]string{"id": campaignID})
+
+	for _, want := range []string{"task:status", "campaign:updated"} {
+		msg, ok := receive(t, client, time.Second)
+		if !ok {
+			t.Fatalf("campaign subscriber received no %s event", want)
+		}
+		payload, _ := msg.Payload.(map[string]interface{})
+		if msg.Type != want || (want == "task:status" && payload["task_id"] != "task-1") {
+			t.Fatalf("received %+v, want the %s event of campaign %s", msg, want, campaignID)
+		}
+	}
+	if msg, ok := receive(t, client, 100*time.Millisecond); ok {
+		t.Fatalf("received unexpected message %+v", msg)
+	}
+}
@@ -0,0 +1,8 @@
+-- Rollback Migration: 003_task_verification
+-- Description: Rollback Opt-in post-execution proof verification for campaign tasks
+-- Created: 2026-10-14
+
+ALTER TABLE campaign_tasks DROP COLUMN IF EXISTS verify_after;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '003';
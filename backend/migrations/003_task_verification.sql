@@ -0,0 +1,10 @@
+-- Migration: 003_task_verification
+-- Description: Opt-in post-execution proof verification for campaign tasks
+-- Created: 2026-10-14
+
+ALTER TABLE campaign_tasks ADD COLUMN IF NOT EXISTS verify_after BOOLEAN DEFAULT false;
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('003', 'task_verification', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,9 @@
+-- Rollback Migration: 004_two_factor_auth
+-- Description: Rollback TOTP two-factor authentication and recovery codes
+-- Created: 2026-10-14
+
+DROP TABLE IF EXISTS two_factor_recovery_codes;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_enabled;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '004';
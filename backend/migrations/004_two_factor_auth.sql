@@ -0,0 +1,20 @@
+-- Migration: 004_two_factor_auth
+-- Description: TOTP two-factor authentication and recovery codes
+-- Created: 2026-10-14
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled BOOLEAN DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS two_factor_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_two_factor_recovery_codes_user ON two_factor_recovery_codes(user_id);
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('004', 'two_factor_auth', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
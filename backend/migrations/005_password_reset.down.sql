@@ -0,0 +1,8 @@
+-- Rollback Migration: 005_password_reset
+-- Description: Rollback Single-use password reset tokens
+-- Created: 2026-10-14
+
+DROP TABLE IF EXISTS password_reset_tokens;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '005';
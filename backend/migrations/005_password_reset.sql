@@ -0,0 +1,20 @@
+-- Migration: 005_password_reset
+-- Description: Single-use password reset tokens
+-- Created: 2026-10-14
+
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    ip_address VARCHAR(50)
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user ON password_reset_tokens(user_id);
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('005', 'password_reset', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,9 @@
+-- Rollback Migration: 006_secret_versions
+-- Description: Rollback Version history for vault secrets
+-- Created: 2026-10-14
+
+DROP TABLE IF EXISTS secret_versions;
+ALTER TABLE secrets_vault DROP COLUMN IF EXISTS version;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '006';
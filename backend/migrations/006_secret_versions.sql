@@ -0,0 +1,23 @@
+-- Migration: 006_secret_versions
+-- Description: Version history for vault secrets
+-- Created: 2026-10-14
+
+ALTER TABLE secrets_vault ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS secret_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    secret_id UUID NOT NULL REFERENCES secrets_vault(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    encrypted_value TEXT NOT NULL, -- AES-256-GCM encrypted
+    iv VARCHAR(32) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(secret_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_secret_versions_secret_id ON secret_versions(secret_id);
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('006', 'secret_versions', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,12 @@
+-- Rollback Migration: 007_vault_envelope_encryption
+-- Description: Rollback Wrapped data keys for envelope-encrypted vault secrets
+-- Created: 2026-10-14
+
+ALTER TABLE secret_versions DROP COLUMN IF EXISTS key_provider;
+ALTER TABLE secret_versions DROP COLUMN IF EXISTS wrapped_key;
+
+ALTER TABLE secrets_vault DROP COLUMN IF EXISTS key_provider;
+ALTER TABLE secrets_vault DROP COLUMN IF EXISTS wrapped_key;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '007';
@@ -0,0 +1,14 @@
+-- Migration: 007_vault_envelope_encryption
+-- Description: Wrapped data keys for envelope-encrypted vault secrets
+-- Created: 2026-10-14
+
+ALTER TABLE secrets_vault ADD COLUMN IF NOT EXISTS wrapped_key TEXT; -- Data key wrapped by the key provider
+ALTER TABLE secrets_vault ADD COLUMN IF NOT EXISTS key_provider VARCHAR(50); -- local, aws-kms, gcp-kms, vault-transit
+
+ALTER TABLE secret_versions ADD COLUMN IF NOT EXISTS wrapped_key TEXT;
+ALTER TABLE secret_versions ADD COLUMN IF NOT EXISTS key_provider VARCHAR(50);
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('007', 'vault_envelope_encryption', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
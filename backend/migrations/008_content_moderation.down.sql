@@ -0,0 +1,11 @@
+-- Rollback Migration: 008_content_moderation
+-- Description: Rollback Moderation results for generated content drafts
+-- Created: 2026-10-14
+
+DROP INDEX IF EXISTS idx_content_drafts_flagged;
+
+ALTER TABLE content_drafts DROP COLUMN IF EXISTS moderation;
+ALTER TABLE content_drafts DROP COLUMN IF EXISTS moderation_reason;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '008';
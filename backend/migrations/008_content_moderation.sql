@@ -0,0 +1,13 @@
+-- Migration: 008_content_moderation
+-- Description: Moderation results for generated content drafts
+-- Created: 2026-10-14
+
+ALTER TABLE content_drafts ADD COLUMN IF NOT EXISTS moderation_reason TEXT;
+ALTER TABLE content_drafts ADD COLUMN IF NOT EXISTS moderation JSONB; -- Provider, reasons and check time
+
+CREATE INDEX IF NOT EXISTS idx_content_drafts_flagged ON content_drafts(user_id) WHERE status = 'flagged';
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('008', 'content_moderation', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,8 @@
+-- Rollback Migration: 009_thread_parts
+-- Description: Rollback Ordered parts for thread drafts and scheduled threads
+-- Created: 2026-10-14
+
+DROP TABLE IF EXISTS thread_parts;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '009';
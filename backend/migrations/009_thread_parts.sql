@@ -0,0 +1,27 @@
+-- Migration: 009_thread_parts
+-- Description: Ordered parts for thread drafts and scheduled threads
+-- Created: 2026-10-14
+
+CREATE TABLE IF NOT EXISTS thread_parts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    draft_id UUID REFERENCES content_drafts(id) ON DELETE CASCADE,
+    scheduled_post_id UUID REFERENCES scheduled_posts(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL, -- 0-based order in the thread
+    content TEXT NOT NULL,
+    status VARCHAR(30) DEFAULT 'pending', -- pending, posted, failed
+    post_id VARCHAR(200),
+    post_url VARCHAR(500),
+    error_message TEXT,
+    posted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (draft_id IS NOT NULL OR scheduled_post_id IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_thread_parts_draft_id ON thread_parts(draft_id, position);
+CREATE INDEX IF NOT EXISTS idx_thread_parts_scheduled_post_id ON thread_parts(scheduled_post_id, position);
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('009', 'thread_parts', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,8 @@
+-- Rollback Migration: 010_dead_letter_jobs
+-- Description: Rollback Dead-letter queue for jobs and task executions that exhausted their retries
+-- Created: 2026-10-14
+
+DROP TABLE IF EXISTS dead_letter_jobs;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '010';
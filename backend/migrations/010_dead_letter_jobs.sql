@@ -0,0 +1,30 @@
+-- Migration: 010_dead_letter_jobs
+-- Description: Dead-letter queue for jobs and task executions that exhausted their retries
+-- Created: 2026-10-14
+
+CREATE TABLE IF NOT EXISTS dead_letter_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source VARCHAR(20) NOT NULL, -- job, task
+    job_id UUID,
+    task_id UUID,
+    task_execution_id UUID,
+    type VARCHAR(50),
+    name VARCHAR(200),
+    attempts INTEGER DEFAULT 0,
+    last_error TEXT,
+    context JSONB,
+    status VARCHAR(20) DEFAULT 'dead', -- dead, replayed
+    replay_count INTEGER DEFAULT 0,
+    replayed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_dead_letter_jobs_user_id ON dead_letter_jobs(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_dead_letter_jobs_status ON dead_letter_jobs(status);
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('010', 'dead_letter_jobs', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,10 @@
+-- Rollback Migration: 011_request_ids
+-- Description: Rollback Request IDs on audit logs, tying entries to the HTTP request and jobs that produced them
+-- Created: 2026-10-14
+
+DROP INDEX IF EXISTS idx_audit_logs_request_id;
+
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS request_id;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '011';
@@ -0,0 +1,12 @@
+-- Migration: 011_request_ids
+-- Description: Request IDs on audit logs, tying entries to the HTTP request and jobs that produced them
+-- Created: 2026-10-14
+
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS request_id VARCHAR(128);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_request_id ON audit_logs(request_id) WHERE request_id IS NOT NULL;
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('011', 'request_ids', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
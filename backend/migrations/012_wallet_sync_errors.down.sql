@@ -0,0 +1,8 @@
+-- Rollback Migration: 012_wallet_sync_errors
+-- Description: Rollback Last balance sync error per wallet, so failing wallets can be shown
+-- Created: 2026-10-14
+
+ALTER TABLE wallets DROP COLUMN IF EXISTS last_sync_error;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '012';
@@ -0,0 +1,10 @@
+-- Migration: 012_wallet_sync_errors
+-- Description: Last balance sync error per wallet, so failing wallets can be shown
+-- Created: 2026-10-14
+
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS last_sync_error TEXT;
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('012', 'wallet_sync_errors', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,8 @@
+-- Rollback Migration: 013_rpc_endpoints
+-- Description: Rollback Per-chain RPC endpoints, shared (user_id NULL) or owned by a user
+-- Created: 2026-10-14
+
+DROP TABLE IF EXISTS rpc_endpoints;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '013';
@@ -0,0 +1,24 @@
+-- Migration: 013_rpc_endpoints
+-- Description: Per-chain RPC endpoints, shared (user_id NULL) or owned by a user
+-- Created: 2026-10-14
+
+CREATE TABLE IF NOT EXISTS rpc_endpoints (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    chain_id BIGINT NOT NULL,
+    name VARCHAR(100),
+    url VARCHAR(500) NOT NULL,
+    priority INTEGER DEFAULT 0,
+    api_key TEXT, -- AES-256-GCM encrypted
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_rpc_endpoints_chain_id ON rpc_endpoints(chain_id, priority);
+CREATE INDEX IF NOT EXISTS idx_rpc_endpoints_user_id ON rpc_endpoints(user_id);
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('013', 'rpc_endpoints', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
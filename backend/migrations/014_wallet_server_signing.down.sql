@@ -0,0 +1,8 @@
+-- Rollback Migration: 014_wallet_server_signing
+-- Description: Rollback Per-wallet opt-in for signing with the stored key on the server (sweeps, message signing)
+-- Created: 2026-10-14
+
+ALTER TABLE wallets DROP COLUMN IF EXISTS allow_server_signing;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '014';
@@ -0,0 +1,10 @@
+-- Migration: 014_wallet_server_signing
+-- Description: Per-wallet opt-in for signing with the stored key on the server (sweeps, message signing)
+-- Created: 2026-10-14
+
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS allow_server_signing BOOLEAN DEFAULT false;
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('014', 'wallet_server_signing', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
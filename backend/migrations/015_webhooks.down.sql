@@ -0,0 +1,9 @@
+-- Rollback Migration: 015_webhooks
+-- Description: Rollback User webhooks for task, job and campaign events, and their delivery attempts
+-- Created: 2026-10-14
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '015';
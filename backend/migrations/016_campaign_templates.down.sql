@@ -0,0 +1,8 @@
+-- Rollback Migration: 016_campaign_templates
+-- Description: Rollback Reusable campaign templates with {{param}} placeholders
+-- Created: 2026-10-14
+
+DROP TABLE IF EXISTS campaign_templates;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '016';
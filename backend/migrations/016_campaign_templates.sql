@@ -0,0 +1,31 @@
+-- Migration: 016_campaign_templates
+-- Description: Reusable campaign templates with {{param}} placeholders
+-- Created: 2026-10-14
+
+CREATE TABLE IF NOT EXISTS campaign_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    source_campaign_id UUID,
+    name VARCHAR(200) NOT NULL,
+    description TEXT,
+    is_public BOOLEAN DEFAULT false,
+    type VARCHAR(50) NOT NULL,
+    url VARCHAR(500),
+    image_url VARCHAR(500),
+    estimated_reward VARCHAR(100),
+    reward_type VARCHAR(50),
+    metadata JSONB DEFAULT '{}',
+    tasks JSONB DEFAULT '[]',
+    params JSONB DEFAULT '[]',
+    usage_count INTEGER DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_templates_user_id ON campaign_templates(user_id);
+CREATE INDEX IF NOT EXISTS idx_campaign_templates_is_public ON campaign_templates(is_public);
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('016', 'campaign_templates', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,9 @@
+-- Rollback Migration: 017_campaign_reminders
+-- Description: Rollback Track which deadline reminders were sent for each campaign
+-- Created: 2026-10-14
+
+DROP INDEX IF EXISTS idx_campaigns_deadline;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS reminders_sent;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '017';
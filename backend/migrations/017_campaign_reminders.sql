@@ -0,0 +1,12 @@
+-- Migration: 017_campaign_reminders
+-- Description: Track which deadline reminders were sent for each campaign
+-- Created: 2026-10-14
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS reminders_sent JSONB DEFAULT '[]';
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_deadline ON campaigns(deadline) WHERE status = 'active';
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('017', 'campaign_reminders', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
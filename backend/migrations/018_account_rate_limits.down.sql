@@ -0,0 +1,8 @@
+-- Rollback Migration: 018_account_rate_limits
+-- Description: Rollback Per-account rate limit overrides
+-- Created: 2026-10-14
+
+ALTER TABLE platform_accounts DROP COLUMN IF EXISTS rate_limit;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '018';
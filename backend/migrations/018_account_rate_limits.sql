@@ -0,0 +1,10 @@
+-- Migration: 018_account_rate_limits
+-- Description: Per-account rate limit overrides
+-- Created: 2026-10-14
+
+ALTER TABLE platform_accounts ADD COLUMN IF NOT EXISTS rate_limit VARCHAR(50);
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('018', 'account_rate_limits', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
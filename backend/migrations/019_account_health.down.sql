@@ -0,0 +1,10 @@
+-- Rollback Migration: 019_account_health
+-- Description: Rollback Record platform account health from periodic health checks
+-- Created: 2026-10-14
+
+ALTER TABLE platform_accounts DROP COLUMN IF EXISTS health_checked_at;
+ALTER TABLE platform_accounts DROP COLUMN IF EXISTS health_reason;
+ALTER TABLE platform_accounts DROP COLUMN IF EXISTS health;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '019';
@@ -0,0 +1,12 @@
+-- Migration: 019_account_health
+-- Description: Record platform account health from periodic health checks
+-- Created: 2026-10-14
+
+ALTER TABLE platform_accounts ADD COLUMN IF NOT EXISTS health VARCHAR(20) DEFAULT 'active';
+ALTER TABLE platform_accounts ADD COLUMN IF NOT EXISTS health_reason TEXT;
+ALTER TABLE platform_accounts ADD COLUMN IF NOT EXISTS health_checked_at TIMESTAMP;
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('019', 'account_health', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
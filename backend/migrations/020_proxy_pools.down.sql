@@ -0,0 +1,20 @@
+-- Rollback Migration: 020_proxy_pools
+-- Description: Rollback Proxy pools with rotation policies and per-proxy health counters
+-- Created: 2026-10-14
+
+ALTER TABLE browser_profiles DROP COLUMN IF EXISTS proxy_pool_id;
+ALTER TABLE platform_accounts DROP COLUMN IF EXISTS proxy_pool_id;
+
+DROP INDEX IF EXISTS idx_proxies_pool_id;
+ALTER TABLE proxies DROP COLUMN IF EXISTS consecutive_failures;
+ALTER TABLE proxies DROP COLUMN IF EXISTS failure_count;
+ALTER TABLE proxies DROP COLUMN IF EXISTS success_count;
+ALTER TABLE proxies DROP COLUMN IF EXISTS last_error;
+ALTER TABLE proxies DROP COLUMN IF EXISTS last_used_at;
+ALTER TABLE proxies DROP COLUMN IF EXISTS is_healthy;
+ALTER TABLE proxies DROP COLUMN IF EXISTS pool_id;
+
+DROP TABLE IF EXISTS proxy_pools;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '020';
@@ -0,0 +1,34 @@
+-- Migration: 020_proxy_pools
+-- Description: Proxy pools with rotation policies and per-proxy health counters
+-- Created: 2026-10-14
+
+CREATE TABLE IF NOT EXISTS proxy_pools (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    policy VARCHAR(30) DEFAULT 'round_robin',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_proxy_pools_user_id ON proxy_pools(user_id);
+CREATE INDEX IF NOT EXISTS idx_proxy_pools_deleted_at ON proxy_pools(deleted_at);
+
+ALTER TABLE proxies ADD COLUMN IF NOT EXISTS pool_id UUID;
+ALTER TABLE proxies ADD COLUMN IF NOT EXISTS is_healthy BOOLEAN DEFAULT true;
+ALTER TABLE proxies ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMPTZ;
+ALTER TABLE proxies ADD COLUMN IF NOT EXISTS last_error TEXT;
+ALTER TABLE proxies ADD COLUMN IF NOT EXISTS success_count BIGINT DEFAULT 0;
+ALTER TABLE proxies ADD COLUMN IF NOT EXISTS failure_count BIGINT DEFAULT 0;
+ALTER TABLE proxies ADD COLUMN IF NOT EXISTS consecutive_failures INTEGER DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_proxies_pool_id ON proxies(pool_id);
+
+ALTER TABLE platform_accounts ADD COLUMN IF NOT EXISTS proxy_pool_id UUID;
+ALTER TABLE browser_profiles ADD COLUMN IF NOT EXISTS proxy_pool_id UUID;
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('020', 'proxy_pools', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,8 @@
+-- Rollback Migration: 021_scheduled_task_executions
+-- Description: Rollback Task executions scheduled for a later time
+-- Created: 2026-10-14
+
+DROP TABLE IF EXISTS scheduled_task_executions;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '021';
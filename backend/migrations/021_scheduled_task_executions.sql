@@ -0,0 +1,28 @@
+-- Migration: 021_scheduled_task_executions
+-- Description: Task executions scheduled for a later time
+-- Created: 2026-10-14
+
+CREATE TABLE IF NOT EXISTS scheduled_task_executions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id UUID NOT NULL REFERENCES campaign_tasks(id) ON DELETE CASCADE,
+    wallet_id UUID,
+    account_id UUID,
+    force BOOLEAN DEFAULT false,
+    run_at TIMESTAMPTZ NOT NULL,
+    status VARCHAR(30) DEFAULT 'pending',
+    execution_id UUID,
+    error_message TEXT,
+    ran_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_task_executions_user_id ON scheduled_task_executions(user_id);
+CREATE INDEX IF NOT EXISTS idx_scheduled_task_executions_task_id ON scheduled_task_executions(task_id);
+CREATE INDEX IF NOT EXISTS idx_scheduled_task_executions_run_at ON scheduled_task_executions(run_at);
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('021', 'scheduled_task_executions', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
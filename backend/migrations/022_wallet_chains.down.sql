@@ -0,0 +1,8 @@
+-- Rollback Migration: 022_wallet_chains
+-- Description: Rollback Extra chains a wallet is active on besides its default chain
+-- Created: 2026-10-14
+
+ALTER TABLE wallets DROP COLUMN IF EXISTS extra_chain_ids;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '022';
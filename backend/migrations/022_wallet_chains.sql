@@ -0,0 +1,10 @@
+-- Migration: 022_wallet_chains
+-- Description: Extra chains a wallet is active on besides its default chain
+-- Created: 2026-10-14
+
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS extra_chain_ids JSONB DEFAULT '[]';
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('022', 'wallet_chains', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,9 @@
+-- Rollback Migration: 023_proxy_health
+-- Description: Rollback Proxy health check results and auto-disable time
+-- Created: 2026-10-14
+
+ALTER TABLE proxies DROP COLUMN IF EXISTS disabled_at;
+ALTER TABLE proxies DROP COLUMN IF EXISTS external_ip;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '023';
@@ -0,0 +1,11 @@
+-- Migration: 023_proxy_health
+-- Description: Proxy health check results and auto-disable time
+-- Created: 2026-10-14
+
+ALTER TABLE proxies ADD COLUMN IF NOT EXISTS external_ip VARCHAR(45);
+ALTER TABLE proxies ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ;
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('023', 'proxy_health', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
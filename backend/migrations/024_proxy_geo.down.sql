@@ -0,0 +1,12 @@
+-- Rollback Migration: 024_proxy_geo
+-- Description: Rollback Geo data of a proxy's exit IP from the last test
+-- Created: 2026-10-14
+
+ALTER TABLE proxies DROP COLUMN IF EXISTS geo_org;
+ALTER TABLE proxies DROP COLUMN IF EXISTS geo_asn;
+ALTER TABLE proxies DROP COLUMN IF EXISTS geo_timezone;
+ALTER TABLE proxies DROP COLUMN IF EXISTS geo_city;
+ALTER TABLE proxies DROP COLUMN IF EXISTS geo_country;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '024';
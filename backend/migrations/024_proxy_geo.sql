@@ -0,0 +1,14 @@
+-- Migration: 024_proxy_geo
+-- Description: Geo data of a proxy's exit IP from the last test
+-- Created: 2026-10-14
+
+ALTER TABLE proxies ADD COLUMN IF NOT EXISTS geo_country VARCHAR(10);
+ALTER TABLE proxies ADD COLUMN IF NOT EXISTS geo_city VARCHAR(100);
+ALTER TABLE proxies ADD COLUMN IF NOT EXISTS geo_timezone VARCHAR(50);
+ALTER TABLE proxies ADD COLUMN IF NOT EXISTS geo_asn VARCHAR(20);
+ALTER TABLE proxies ADD COLUMN IF NOT EXISTS geo_org VARCHAR(200);
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('024', 'proxy_geo', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,13 @@
+-- Rollback Migration: 025_browser_recordings
+-- Description: Rollback Opt-in screenshot recordings of browser sessions
+-- Created: 2026-10-14
+
+DROP INDEX IF EXISTS idx_browser_sessions_recording;
+
+ALTER TABLE browser_sessions DROP COLUMN IF EXISTS stopped_at;
+ALTER TABLE browser_sessions DROP COLUMN IF EXISTS recording_frames;
+ALTER TABLE browser_sessions DROP COLUMN IF EXISTS recording_path;
+ALTER TABLE browser_sessions DROP COLUMN IF EXISTS recording;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '025';
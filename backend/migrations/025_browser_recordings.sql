@@ -0,0 +1,15 @@
+-- Migration: 025_browser_recordings
+-- Description: Opt-in screenshot recordings of browser sessions
+-- Created: 2026-10-14
+
+ALTER TABLE browser_sessions ADD COLUMN IF NOT EXISTS recording BOOLEAN DEFAULT false;
+ALTER TABLE browser_sessions ADD COLUMN IF NOT EXISTS recording_path VARCHAR(500);
+ALTER TABLE browser_sessions ADD COLUMN IF NOT EXISTS recording_frames INTEGER DEFAULT 0;
+ALTER TABLE browser_sessions ADD COLUMN IF NOT EXISTS stopped_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_browser_sessions_recording ON browser_sessions(stopped_at) WHERE recording_path <> '';
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('025', 'browser_recordings', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
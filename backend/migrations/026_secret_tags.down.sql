@@ -0,0 +1,8 @@
+-- Rollback Migration: 026_secret_tags
+-- Description: Rollback Index vault secret tags, kept in metadata.tags, for tag search
+-- Created: 2026-10-14
+
+DROP INDEX IF EXISTS idx_secrets_vault_tags;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '026';
@@ -0,0 +1,10 @@
+-- Migration: 026_secret_tags
+-- Description: Index vault secret tags, kept in metadata.tags, for tag search
+-- Created: 2026-10-14
+
+CREATE INDEX IF NOT EXISTS idx_secrets_vault_tags ON secrets_vault USING GIN ((metadata -> 'tags'));
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('026', 'secret_tags', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,10 @@
+-- Rollback Migration: 027_job_logs_job_created_index
+-- Description: Rollback Index job logs by job and time for the paginated, time-filtered log viewer
+-- Created: 2026-10-14
+
+CREATE INDEX IF NOT EXISTS idx_job_logs_job_id ON job_logs(job_id);
+
+DROP INDEX IF EXISTS idx_job_logs_job_id_created_at;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '027';
@@ -0,0 +1,13 @@
+-- Migration: 027_job_logs_job_created_index
+-- Description: Index job logs by job and time for the paginated, time-filtered log viewer
+-- Created: 2026-10-14
+
+CREATE INDEX IF NOT EXISTS idx_job_logs_job_id_created_at ON job_logs(job_id, created_at DESC);
+
+-- The composite index covers lookups by job_id alone
+DROP INDEX IF EXISTS idx_job_logs_job_id;
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('027', 'job_logs_job_created_index', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,94 @@
+-- Rollback Migration: 028_task_execution_statuses
+-- Description: Rollback Normalize task execution statuses to the canonical lowercase values
+-- Created: 2026-10-14
+
+-- verifying and unverified have no enum value; they map to RUNNING and DONE
+
+DROP VIEW IF EXISTS campaign_progress_view;
+DROP TRIGGER IF EXISTS validate_task_execution_status ON task_executions;
+
+CREATE TYPE task_status AS ENUM ('PENDING', 'RUNNING', 'MANUAL_REQUIRED', 'DONE', 'FAILED', 'SKIPPED');
+
+ALTER TABLE task_executions ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE task_executions ALTER COLUMN status TYPE task_status USING (
+    CASE status
+        WHEN 'pending' THEN 'PENDING'
+        WHEN 'in_progress' THEN 'RUNNING'
+        WHEN 'verifying' THEN 'RUNNING'
+        WHEN 'waiting_manual' THEN 'MANUAL_REQUIRED'
+        WHEN 'completed' THEN 'DONE'
+        WHEN 'unverified' THEN 'DONE'
+        WHEN 'failed' THEN 'FAILED'
+        WHEN 'skipped' THEN 'SKIPPED'
+        ELSE 'PENDING'
+    END
+)::task_status;
+ALTER TABLE task_executions ALTER COLUMN status SET DEFAULT 'PENDING';
+
+CREATE OR REPLACE FUNCTION validate_task_status_transition()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- Allow any transition from NULL or PENDING
+    IF OLD.status IS NULL OR OLD.status = 'PENDING' THEN
+        RETURN NEW;
+    END IF;
+    
+    -- RUNNING can go to MANUAL_REQUIRED, DONE, FAILED, or stay RUNNING
+    IF OLD.status = 'RUNNING' THEN
+        IF NEW.status NOT IN ('RUNNING', 'MANUAL_REQUIRED', 'DONE', 'FAILED') THEN
+            RAISE EXCEPTION 'Invalid status transition from RUNNING to %', NEW.status;
+        END IF;
+        RETURN NEW;
+    END IF;
+    
+    -- MANUAL_REQUIRED can go to DONE, FAILED, or RUNNING (resumed)
+    IF OLD.status = 'MANUAL_REQUIRED' THEN
+        IF NEW.status NOT IN ('MANUAL_REQUIRED', 'RUNNING', 'DONE', 'FAILED') THEN
+            RAISE EXCEPTION 'Invalid status transition from MANUAL_REQUIRED to %', NEW.status;
+        END IF;
+        RETURN NEW;
+    END IF;
+    
+    -- DONE and FAILED are terminal states (allow reset to PENDING for retry)
+    IF OLD.status IN ('DONE', 'FAILED') THEN
+        IF NEW.status NOT IN ('DONE', 'FAILED', 'PENDING') THEN
+            RAISE EXCEPTION 'Invalid status transition from % to %', OLD.status, NEW.status;
+        END IF;
+        RETURN NEW;
+    END IF;
+    
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER validate_task_execution_status
+BEFORE UPDATE OF status ON task_executions
+FOR EACH ROW
+EXECUTE FUNCTION validate_task_status_transition();
+
+CREATE OR REPLACE VIEW campaign_progress_view AS
+SELECT 
+    c.id AS campaign_id,
+    c.user_id,
+    c.name AS campaign_name,
+    c.status AS campaign_status,
+    COUNT(DISTINCT ct.id) AS total_tasks,
+    COUNT(DISTINCT CASE WHEN te.status = 'DONE' THEN te.id END) AS completed_executions,
+    COUNT(DISTINCT CASE WHEN te.status = 'FAILED' THEN te.id END) AS failed_executions,
+    COUNT(DISTINCT CASE WHEN te.status = 'RUNNING' THEN te.id END) AS running_executions,
+    COUNT(DISTINCT CASE WHEN te.status = 'MANUAL_REQUIRED' THEN te.id END) AS manual_required,
+    ROUND(
+        CASE 
+            WHEN COUNT(DISTINCT ct.id) > 0 
+            THEN (COUNT(DISTINCT CASE WHEN te.status = 'DONE' THEN ct.id END)::DECIMAL / COUNT(DISTINCT ct.id)) * 100 
+            ELSE 0 
+        END, 2
+    ) AS progress_percent
+FROM campaigns c
+LEFT JOIN campaign_tasks ct ON ct.campaign_id = c.id
+LEFT JOIN task_executions te ON te.task_id = ct.id
+WHERE c.deleted_at IS NULL
+GROUP BY c.id, c.user_id, c.name, c.status;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '028';
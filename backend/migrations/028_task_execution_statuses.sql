@@ -0,0 +1,79 @@
+-- Migration: 028_task_execution_statuses
+-- Description: Normalize task execution statuses to the canonical lowercase values
+-- Created: 2026-10-14
+
+-- Canonical statuses (models.TaskExecutionStatus):
+--   pending, in_progress, waiting_manual, verifying, completed, unverified, failed, skipped
+-- The task manager used to store PENDING, RUNNING, MANUAL_REQUIRED, DONE, FAILED
+-- and SKIPPED, which the task_status enum allowed and nothing else.
+
+-- The view and trigger reference the column, so they are rebuilt around the change
+DROP VIEW IF EXISTS campaign_progress_view;
+DROP TRIGGER IF EXISTS validate_task_execution_status ON task_executions;
+
+ALTER TABLE task_executions ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE task_executions ALTER COLUMN status TYPE VARCHAR(30) USING (
+    CASE status::text
+        WHEN 'PENDING' THEN 'pending'
+        WHEN 'RUNNING' THEN 'in_progress'
+        WHEN 'running' THEN 'in_progress'
+        WHEN 'MANUAL_REQUIRED' THEN 'waiting_manual'
+        WHEN 'DONE' THEN 'completed'
+        WHEN 'FAILED' THEN 'failed'
+        WHEN 'SKIPPED' THEN 'skipped'
+        ELSE status::text
+    END
+);
+ALTER TABLE task_executions ALTER COLUMN status SET DEFAULT 'pending';
+
+DROP TYPE IF EXISTS task_status;
+
+-- Completed and failed executions only leave their state to be retried
+CREATE OR REPLACE FUNCTION validate_task_status_transition()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.status = 'completed' AND NEW.status NOT IN ('completed', 'failed', 'pending') THEN
+        RAISE EXCEPTION 'Invalid status transition from completed to %', NEW.status;
+    END IF;
+
+    IF OLD.status = 'failed' AND NEW.status NOT IN ('failed', 'completed', 'pending', 'in_progress') THEN
+        RAISE EXCEPTION 'Invalid status transition from failed to %', NEW.status;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER validate_task_execution_status
+BEFORE UPDATE OF status ON task_executions
+FOR EACH ROW
+EXECUTE FUNCTION validate_task_status_transition();
+
+CREATE OR REPLACE VIEW campaign_progress_view AS
+SELECT 
+    c.id AS campaign_id,
+    c.user_id,
+    c.name AS campaign_name,
+    c.status AS campaign_status,
+    COUNT(DISTINCT ct.id) AS total_tasks,
+    COUNT(DISTINCT CASE WHEN te.status = 'completed' THEN te.id END) AS completed_executions,
+    COUNT(DISTINCT CASE WHEN te.status = 'failed' THEN te.id END) AS failed_executions,
+    COUNT(DISTINCT CASE WHEN te.status = 'in_progress' THEN te.id END) AS running_executions,
+    COUNT(DISTINCT CASE WHEN te.status = 'waiting_manual' THEN te.id END) AS manual_required,
+    ROUND(
+        CASE 
+            WHEN COUNT(DISTINCT ct.id) > 0 
+            THEN (COUNT(DISTINCT CASE WHEN te.status = 'completed' THEN ct.id END)::DECIMAL / COUNT(DISTINCT ct.id)) * 100 
+            ELSE 0 
+        END, 2
+    ) AS progress_percent
+FROM campaigns c
+LEFT JOIN campaign_tasks ct ON ct.campaign_id = c.id
+LEFT JOIN task_executions te ON te.task_id = ct.id
+WHERE c.deleted_at IS NULL
+GROUP BY c.id, c.user_id, c.name, c.status;
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('028', 'task_execution_statuses', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
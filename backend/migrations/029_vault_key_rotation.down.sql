@@ -0,0 +1,12 @@
+-- Rollback Migration: 029_vault_key_rotation
+-- Description: Rollback Key versions on vault secrets and resumable org-wide key rotation jobs
+-- Created: 2026-10-14
+
+DROP TABLE IF EXISTS key_rotation_jobs;
+
+DROP INDEX IF EXISTS idx_secrets_vault_key_version;
+ALTER TABLE secret_versions DROP COLUMN IF EXISTS key_version;
+ALTER TABLE secrets_vault DROP COLUMN IF EXISTS key_version;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '029';
@@ -0,0 +1,32 @@
+-- Migration: 029_vault_key_rotation
+-- Description: Key versions on vault secrets and resumable org-wide key rotation jobs
+-- Created: 2026-10-14
+
+-- Fingerprint of the master key that sealed each value; empty for values sealed before key versions
+ALTER TABLE secrets_vault ADD COLUMN IF NOT EXISTS key_version VARCHAR(16);
+ALTER TABLE secret_versions ADD COLUMN IF NOT EXISTS key_version VARCHAR(16);
+
+CREATE INDEX IF NOT EXISTS idx_secrets_vault_key_version ON secrets_vault(key_version);
+
+CREATE TABLE IF NOT EXISTS key_rotation_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    from_key_version VARCHAR(16) NOT NULL,
+    to_key_version VARCHAR(16) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running', -- running, completed, failed
+    total_users INTEGER DEFAULT 0,
+    rotated_users INTEGER DEFAULT 0,
+    rotated_secrets INTEGER DEFAULT 0,
+    last_user_id UUID, -- Users up to and including this one are rotated
+    error TEXT,
+    started_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    started_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_key_rotation_jobs_keys ON key_rotation_jobs(from_key_version, to_key_version, status);
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('029', 'vault_key_rotation', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
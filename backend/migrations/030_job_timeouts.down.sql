@@ -0,0 +1,9 @@
+-- Rollback Migration: 030_job_timeouts
+-- Description: Rollback Per-job run timeouts and the error code of a job's last failed run
+-- Created: 2026-10-14
+
+ALTER TABLE automation_jobs DROP COLUMN IF EXISTS error_code;
+ALTER TABLE automation_jobs DROP COLUMN IF EXISTS timeout_seconds;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '030';
@@ -0,0 +1,12 @@
+-- Migration: 030_job_timeouts
+-- Description: Per-job run timeouts and the error code of a job's last failed run
+-- Created: 2026-10-14
+
+-- 0 uses JOB_DEFAULT_TIMEOUT
+ALTER TABLE automation_jobs ADD COLUMN IF NOT EXISTS timeout_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE automation_jobs ADD COLUMN IF NOT EXISTS error_code VARCHAR(50);
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('030', 'job_timeouts', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,9 @@
+-- Rollback Migration: 031_edit_versions
+-- Description: Rollback Version columns for optimistic concurrency on campaign and task edits
+-- Created: 2026-10-14
+
+ALTER TABLE campaign_tasks DROP COLUMN IF EXISTS version;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS version;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '031';
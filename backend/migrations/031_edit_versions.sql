@@ -0,0 +1,11 @@
+-- Migration: 031_edit_versions
+-- Description: Version columns for optimistic concurrency on campaign and task edits
+-- Created: 2026-10-14
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE campaign_tasks ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('031', 'edit_versions', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,16 @@
+-- Rollback Migration: 032_search_indexes
+-- Description: Rollback Trigram indexes for substring search over campaigns, tasks, accounts and wallets
+-- Created: 2026-10-14
+
+DROP INDEX IF EXISTS idx_wallets_address_trgm;
+DROP INDEX IF EXISTS idx_wallets_name_trgm;
+DROP INDEX IF EXISTS idx_platform_accounts_display_name_trgm;
+DROP INDEX IF EXISTS idx_platform_accounts_username_trgm;
+DROP INDEX IF EXISTS idx_campaign_tasks_name_trgm;
+DROP INDEX IF EXISTS idx_campaigns_description_trgm;
+DROP INDEX IF EXISTS idx_campaigns_name_trgm;
+
+-- The pg_trgm extension is left installed; other objects may use it
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '032';
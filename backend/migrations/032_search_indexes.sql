@@ -0,0 +1,19 @@
+-- Migration: 032_search_indexes
+-- Description: Trigram indexes for substring search over campaigns, tasks, accounts and wallets
+-- Created: 2026-10-14
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Serve ILIKE '%query%' from GET /search
+CREATE INDEX IF NOT EXISTS idx_campaigns_name_trgm ON campaigns USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_campaigns_description_trgm ON campaigns USING gin (description gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_campaign_tasks_name_trgm ON campaign_tasks USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_platform_accounts_username_trgm ON platform_accounts USING gin (username gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_platform_accounts_display_name_trgm ON platform_accounts USING gin (display_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_wallets_name_trgm ON wallets USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_wallets_address_trgm ON wallets USING gin (address gin_trgm_ops);
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('032', 'search_indexes', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
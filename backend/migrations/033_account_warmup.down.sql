@@ -0,0 +1,17 @@
+-- Rollback Migration: 033_account_warmup
+-- Description: Rollback Account warmup state and a minimum warmup score for campaigns
+-- Created: 2026-10-14
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS min_warmup_score;
+
+DROP INDEX IF EXISTS idx_platform_accounts_warmup_status;
+
+ALTER TABLE platform_accounts DROP COLUMN IF EXISTS warmup_last_action_at;
+ALTER TABLE platform_accounts DROP COLUMN IF EXISTS warmup_started_at;
+ALTER TABLE platform_accounts DROP COLUMN IF EXISTS warmup_days_done;
+ALTER TABLE platform_accounts DROP COLUMN IF EXISTS warmup_config;
+ALTER TABLE platform_accounts DROP COLUMN IF EXISTS warmup_score;
+ALTER TABLE platform_accounts DROP COLUMN IF EXISTS warmup_status;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '033';
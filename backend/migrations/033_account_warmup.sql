@@ -0,0 +1,19 @@
+-- Migration: 033_account_warmup
+-- Description: Account warmup state and a minimum warmup score for campaigns
+-- Created: 2026-10-14
+
+ALTER TABLE platform_accounts ADD COLUMN IF NOT EXISTS warmup_status VARCHAR(20) DEFAULT 'none';
+ALTER TABLE platform_accounts ADD COLUMN IF NOT EXISTS warmup_score INTEGER DEFAULT 0;
+ALTER TABLE platform_accounts ADD COLUMN IF NOT EXISTS warmup_config JSONB;
+ALTER TABLE platform_accounts ADD COLUMN IF NOT EXISTS warmup_days_done INTEGER DEFAULT 0;
+ALTER TABLE platform_accounts ADD COLUMN IF NOT EXISTS warmup_started_at TIMESTAMPTZ;
+ALTER TABLE platform_accounts ADD COLUMN IF NOT EXISTS warmup_last_action_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_platform_accounts_warmup_status ON platform_accounts(warmup_status) WHERE warmup_status = 'warming';
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS min_warmup_score INTEGER DEFAULT 0;
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('033', 'account_warmup', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
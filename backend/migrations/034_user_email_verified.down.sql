@@ -0,0 +1,8 @@
+-- Rollback Migration: 034_user_email_verified
+-- Description: Rollback When a user's email address was verified
+-- Created: 2026-10-14
+
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '034';
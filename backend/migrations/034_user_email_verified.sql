@@ -0,0 +1,10 @@
+-- Migration: 034_user_email_verified
+-- Description: When a user's email address was verified
+-- Created: 2026-10-14
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('034', 'user_email_verified', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
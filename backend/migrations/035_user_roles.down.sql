@@ -0,0 +1,10 @@
+-- Rollback Migration: 035_user_roles
+-- Description: Rollback User roles for admin-only endpoints
+-- Created: 2026-10-14
+
+DROP INDEX IF EXISTS idx_users_role;
+
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '035';
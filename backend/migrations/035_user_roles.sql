@@ -0,0 +1,12 @@
+-- Migration: 035_user_roles
+-- Description: User roles for admin-only endpoints
+-- Created: 2026-10-14
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';
+
+CREATE INDEX IF NOT EXISTS idx_users_role ON users(role) WHERE role <> 'user';
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('035', 'user_roles', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,13 @@
+-- Rollback Migration: 036_task_execution_tx_status
+-- Description: Rollback On-chain status and gas used of task execution transactions
+-- Created: 2026-10-14
+
+DROP INDEX IF EXISTS idx_task_executions_tx_pending;
+
+ALTER TABLE task_executions DROP COLUMN IF EXISTS block_number;
+ALTER TABLE task_executions DROP COLUMN IF EXISTS gas_used;
+ALTER TABLE task_executions DROP COLUMN IF EXISTS tx_submitted_at;
+ALTER TABLE task_executions DROP COLUMN IF EXISTS tx_status;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '036';
@@ -0,0 +1,16 @@
+-- Migration: 036_task_execution_tx_status
+-- Description: On-chain status and gas used of task execution transactions
+-- Created: 2026-10-14
+
+ALTER TABLE task_executions ADD COLUMN IF NOT EXISTS tx_status VARCHAR(20);
+ALTER TABLE task_executions ADD COLUMN IF NOT EXISTS tx_submitted_at TIMESTAMPTZ;
+ALTER TABLE task_executions ADD COLUMN IF NOT EXISTS gas_used BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE task_executions ADD COLUMN IF NOT EXISTS block_number BIGINT NOT NULL DEFAULT 0;
+
+-- The receipt poller only looks at transactions still waiting to be mined
+CREATE INDEX IF NOT EXISTS idx_task_executions_tx_pending ON task_executions(tx_submitted_at) WHERE tx_status = 'pending';
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('036', 'task_execution_tx_status', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
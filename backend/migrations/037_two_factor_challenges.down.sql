@@ -0,0 +1,9 @@
+-- Rollback Migration: 037_two_factor_challenges
+-- Description: Rollback Single-use two-factor login challenges and TOTP replay protection
+-- Created: 2026-10-14
+
+ALTER TABLE users DROP COLUMN IF EXISTS totp_last_step;
+DROP TABLE IF EXISTS two_factor_challenges;
+
+-- Remove migration record
+DELETE FROM schema_migrations WHERE version = '037';
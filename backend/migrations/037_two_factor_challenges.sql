@@ -0,0 +1,22 @@
+-- Migration: 037_two_factor_challenges
+-- Description: Single-use two-factor login challenges and TOTP replay protection
+-- Created: 2026-10-14
+
+CREATE TABLE IF NOT EXISTS two_factor_challenges (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    used_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_two_factor_challenges_user ON two_factor_challenges(user_id);
+
+-- Time step of the last accepted TOTP code; codes at or below it are replays
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT DEFAULT 0;
+
+-- Record this migration
+INSERT INTO schema_migrations (version, name, checksum) 
+VALUES ('037', 'two_factor_challenges', 'auto-generated')
+ON CONFLICT (version) DO NOTHING;
@@ -57,7 +57,10 @@ export function Terminal() {
     let ws: WebSocket | null = null
 
     try {
-      ws = new WebSocket(`${wsUrl}/ws`)
+      // The backend requires an access token to upgrade; browsers can't set
+      // headers on WebSockets so it travels as a subprotocol
+      const token = localStorage.getItem('token')
+      ws = new WebSocket(`${wsUrl}/ws`, token ? ['bearer', token] : undefined)
 
       ws.onopen = () => {
         setLines((prev) => [
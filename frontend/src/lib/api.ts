@@ -47,7 +47,11 @@ export const walletsAPI = {
   get: (id: string) => api.get(`/api/v1/wallets/${id}`),
   create: (data: any) => api.post('/api/v1/wallets', data),
   update: (id: string, data: any) => api.put(`/api/v1/wallets/${id}`, data),
-  delete: (id: string) => api.delete(`/api/v1/wallets/${id}`),
+  // Wallets with an imported key need their address as confirm
+  delete: (id: string, confirm?: string) =>
+    api.delete(`/api/v1/wallets/${id}`, { params: confirm ? { confirm } : undefined }),
+  listDeleted: () => api.get('/api/v1/wallets/deleted'),
+  restore: (id: string) => api.post(`/api/v1/wallets/${id}/restore`),
   import: (data: any) => api.post('/api/v1/wallets/import', data),
   bulkCreate: (data: any) => api.post('/api/v1/wallets/bulk', data),
   getBalance: (id: string) => api.get(`/api/v1/wallets/${id}/balance`),
@@ -73,10 +77,16 @@ export const accountsAPI = {
   create: (data: any) => api.post('/api/v1/accounts', data),
   update: (id: string, data: any) => api.put(`/api/v1/accounts/${id}`, data),
   delete: (id: string) => api.delete(`/api/v1/accounts/${id}`),
+  listDeleted: () => api.get('/api/v1/accounts/deleted'),
+  restore: (id: string) => api.post(`/api/v1/accounts/${id}/restore`),
   getActivities: (id: string) => api.get(`/api/v1/accounts/${id}/activities`),
   linkWallet: (id: string, walletId: string) =>
     api.post(`/api/v1/accounts/${id}/link-wallet`, { wallet_id: walletId }),
   sync: (id: string) => api.post(`/api/v1/accounts/${id}/sync`),
+  getWarmup: (id: string) => api.get(`/api/v1/accounts/${id}/warmup`),
+  startWarmup: (id: string, data: { days?: number; min_daily_actions?: number; max_daily_actions?: number; channels?: string[]; restart?: boolean }) =>
+    api.put(`/api/v1/accounts/${id}/warmup`, data),
+  stopWarmup: (id: string) => api.delete(`/api/v1/accounts/${id}/warmup`),
 }
 
 // Campaigns
@@ -90,6 +100,8 @@ export const campaignsAPI = {
   addTask: (id: string, data: any) => api.post(`/api/v1/campaigns/${id}/tasks`, data),
   execute: (id: string, walletIds: string[]) =>
     api.post(`/api/v1/campaigns/${id}/execute`, { wallet_ids: walletIds }),
+  planExecute: (id: string, data: { wallet_ids?: string[]; account_ids?: string[]; task_ids: string[] }) =>
+    api.post(`/api/v1/campaigns/${id}/execute`, { ...data, dry_run: true }),
   getProgress: (id: string) => api.get(`/api/v1/campaigns/${id}/progress`),
 }
 
@@ -159,6 +171,8 @@ export const proxiesAPI = {
   test: (id: string) => api.post(`/api/v1/proxies/${id}/test`),
   bulkCreate: (proxies: string[]) =>
     api.post('/api/v1/proxies/bulk', { proxies }),
+  import: (lines: string[], options?: { type?: string; country?: string; pool_id?: string; test?: boolean }) =>
+    api.post('/api/v1/proxies/import', { lines, ...options }),
 }
 
 // Dashboard
@@ -169,6 +183,12 @@ export const dashboardAPI = {
   getNotifications: () => api.get('/api/v1/dashboard/notifications'),
 }
 
+// Search
+export const searchAPI = {
+  search: (q: string, types?: string[]) =>
+    api.get('/api/v1/search', { params: { q, types: types?.join(',') } }),
+}
+
 // Notifications
 export const notificationsAPI = {
   list: () => api.get('/api/v1/notifications'),